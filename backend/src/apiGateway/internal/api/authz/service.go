@@ -1,122 +1,46 @@
 package authz
 
 import (
-    "strings"
+	"context"
+	"fmt"
 
-    "gold-api/internal/model" 
-    "go.uber.org/zap"
-)
-
-var rolePermissionsMap = map[string][]string{ 
-    model.RoleAdmin: {
-        model.PermInventoryReadItem, model.PermInventoryCreateItem, model.PermInventoryUpdateItem, model.PermInventoryDeleteItem,
-        model.PermInventoryIncreaseStock, model.PermInventoryDecreaseStock, model.PermInventoryReadCategory, model.PermInventoryManageCategory,
-        model.PermInventoryReadGoldPrice, model.PermInventoryUpdateGoldPrice,
-        model.PermCRMReadCustomer, model.PermCRMCreateCustomer, model.PermCRMUpdateCustomer, model.PermCRMDeleteCustomer,
-        model.PermCRMReadSupplier, model.PermCRMCreateSupplier, model.PermCRMUpdateSupplier, model.PermCRMDeleteSupplier,
-        model.PermCRMViewCustomerBalance, model.PermCRMViewSupplierBalance,
-        model.PermTransactionReadSaleInvoice, model.PermTransactionCreateSaleInvoice, model.PermTransactionUpdateSaleInvoice, model.PermTransactionDeleteSaleInvoice,
-        model.PermTransactionReadPurchaseInvoice, model.PermTransactionCreatePurchaseInvoice, model.PermTransactionUpdatePurchaseInvoice, model.PermTransactionDeletePurchaseInvoice,
-        model.PermTransactionManagePayments, model.PermTransactionManageExpenses, model.PermTransactionManageCheques,
-        model.PermReportViewSalesSummary, model.PermReportViewInventorySummary, model.PermReportViewProfitLoss, model.PermReportViewBalances, model.PermReportExportData,
-        model.PermUserRead, model.PermUserCreate, model.PermUserUpdate, model.PermUserDelete, model.PermUserChangeAnyPassword,
-        model.PermSystemSettingsRead, model.PermSystemSettingsManage,
-    },
-    model.RoleOwner: {
-        // ... (Permissions for Owner)
-        model.PermInventoryReadItem, model.PermInventoryCreateItem, model.PermInventoryUpdateItem, model.PermInventoryDeleteItem,
-        model.PermInventoryIncreaseStock, model.PermInventoryDecreaseStock, model.PermInventoryReadCategory, model.PermInventoryManageCategory,
-        model.PermInventoryReadGoldPrice, model.PermInventoryUpdateGoldPrice,
-
-        model.PermCRMReadCustomer, model.PermCRMCreateCustomer, model.PermCRMUpdateCustomer, model.PermCRMDeleteCustomer,
-        model.PermCRMReadSupplier, model.PermCRMCreateSupplier, model.PermCRMUpdateSupplier, model.PermCRMDeleteSupplier,
-        model.PermCRMViewCustomerBalance, model.PermCRMViewSupplierBalance,
+	"common-gold/authz"
 
-        model.PermTransactionReadSaleInvoice, model.PermTransactionCreateSaleInvoice, model.PermTransactionUpdateSaleInvoice, model.PermTransactionDeleteSaleInvoice,
-        model.PermTransactionReadPurchaseInvoice, model.PermTransactionCreatePurchaseInvoice, model.PermTransactionUpdatePurchaseInvoice, model.PermTransactionDeletePurchaseInvoice,
-        model.PermTransactionManagePayments, model.PermTransactionManageExpenses, model.PermTransactionManageCheques,
-
-        model.PermReportViewSalesSummary, model.PermReportViewInventorySummary, model.PermReportViewProfitLoss, model.PermReportViewBalances, model.PermReportExportData,
+	"go.uber.org/zap"
+)
 
-        model.PermUserRead,
-        model.PermSystemSettingsRead,
-    },
-    model.RoleSalesperson: {
-        model.PermInventoryReadItem,
-        model.PermInventoryReadGoldPrice,
-        model.PermCRMReadCustomer,
-        model.PermCRMCreateCustomer,
-        model.PermCRMUpdateCustomer,
-        model.PermTransactionReadSaleInvoice,
-        model.PermTransactionCreateSaleInvoice,
-        model.PermTransactionManagePayments,
-        model.PermReportViewSalesSummary,
-        model.PermInventoryDecreaseStock,
-    },
-    model.RoleAccountant: {
-        model.PermInventoryReadItem,
-        model.PermInventoryReadGoldPrice,
-        model.PermCRMReadCustomer,
-        model.PermCRMReadSupplier,
-        model.PermCRMViewCustomerBalance,
-        model.PermCRMViewSupplierBalance,
-        model.PermTransactionReadSaleInvoice, model.PermTransactionUpdateSaleInvoice, model.PermTransactionDeleteSaleInvoice,
-        model.PermTransactionReadPurchaseInvoice, model.PermTransactionCreatePurchaseInvoice, model.PermTransactionUpdatePurchaseInvoice, model.PermTransactionDeletePurchaseInvoice,
-        model.PermTransactionManagePayments, model.PermTransactionManageExpenses, model.PermTransactionManageCheques,
-        model.PermReportViewSalesSummary, model.PermReportViewInventorySummary, model.PermReportViewProfitLoss, model.PermReportViewBalances, model.PermReportExportData,
-    },
-}
+// PermissionService checks permissions against the policy owned by Profile Manager, cached in memory
+// and refreshed by polling; see common-gold/authz.
 type PermissionService interface {
-    GetPermissionsForRoles(roles []string) []string
-    HasPermission(roles []string, requiredPermission string) bool
+	// HasPermission returns authz.ErrPolicyUnavailable until the first policy has been fetched.
+	HasPermission(userID string, roles []string, requiredPermission string) (bool, error)
+	// Refresh pulls the latest policy right away, e.g. after an RBAC change made through the gateway.
+	Refresh(ctx context.Context) error
 }
 
 type PermissionServiceImpl struct {
-    logger *zap.Logger
+	logger *zap.Logger
+	cache  *authz.Cache
 }
 
-func NewPermissionService(logger *zap.Logger) PermissionService { // <-- Returns the interface, not the struct pointer
-    if logger == nil {
-        panic("Logger cannot be nil for PermissionService.")
-    }
-    return &PermissionServiceImpl{logger: logger}
+func NewPermissionService(logger *zap.Logger, cache *authz.Cache) (PermissionService, error) {
+	if logger == nil {
+		return nil, fmt.Errorf("logger cannot be nil for PermissionService")
+	}
+	if cache == nil {
+		return nil, fmt.Errorf("policy cache cannot be nil for PermissionService")
+	}
+	return &PermissionServiceImpl{logger: logger, cache: cache}, nil
 }
 
-func (s *PermissionServiceImpl) GetPermissionsForRoles(roles []string) []string {
-    uniquePerms := make(map[string]struct{})
-    for _, role := range roles {
-        if perms, ok := rolePermissionsMap[role]; ok {
-            for _, perm := range perms {
-                uniquePerms[perm] = struct{}{}
-            }
-        }
-    }
-
-    var permsList []string
-    for perm := range uniquePerms {
-        permsList = append(permsList, perm)
-    }
-    return permsList
+func (s *PermissionServiceImpl) HasPermission(userID string, roles []string, requiredPermission string) (bool, error) {
+	return s.cache.Allows(userID, roles, requiredPermission)
 }
 
-func (s *PermissionServiceImpl) HasPermission(roles []string, requiredPermission string) bool {
-    for _, role := range roles {
-        if role == model.RoleAdmin {
-            return true
-        }
-    }
-
-    userPermissions := s.GetPermissionsForRoles(roles)
-    for _, perm := range userPermissions {
-        if perm == requiredPermission {
-            return true
-        }
-        if strings.HasSuffix(perm, ":*") {
-            resourcePrefix := strings.TrimSuffix(perm, ":*")
-            if strings.HasPrefix(requiredPermission, resourcePrefix+":") {
-                return true
-            }
-        }
-    }
-    return false
-}
\ No newline at end of file
+func (s *PermissionServiceImpl) Refresh(ctx context.Context) error {
+	if err := s.cache.Refresh(ctx); err != nil {
+		s.logger.Warn("Failed to refresh authorization policy", zap.Error(err))
+		return err
+	}
+	return nil
+}
@@ -1,10 +1,14 @@
 package handler
 
 import (
+	"errors"
+	"gold-api/internal/model"
+	service "gold-api/internal/service/common"
 	profilemanager "gold-api/internal/service/profilemanger"
 	"gold-api/internal/utils"
 
 	"github.com/gofiber/fiber/v2"
+	"go.uber.org/zap"
 )
 
 type AccountHandlerAG struct {
@@ -20,9 +24,22 @@ func NewAccountHandlerAG(client profilemanager.ProfileManagerClient) *AccountHan
 		profileManagerClient: client,
 	}
 }
+// HandleChangePassword keeps the current session signed in; every other session of the user is revoked.
 func (h *AccountHandlerAG) HandleChangePassword(c *fiber.Ctx) error {
-	// Implementation for changing password
-	return nil
+	var req model.ChangePasswordRequest
+	if err := c.BodyParser(&req); err != nil || req.OldPassword == "" || req.NewPassword == "" {
+		return c.Status(fiber.StatusBadRequest).JSON(model.ErrorResponse{Message: "Old and new passwords are required.", Code: "400"})
+	}
+
+	userID, _ := c.Locals("userID").(string)
+	sessionID, _ := c.Locals("sessionID").(string)
+	if err := h.profileManagerClient.ChangePassword(userID, sessionID, req); err != nil {
+		if errors.Is(err, service.ErrWeakPassword) {
+			return c.Status(fiber.StatusBadRequest).JSON(model.ErrorResponse{Message: "Password does not meet the password policy.", Code: "400", Details: err.Error()})
+		}
+		return writeTwoFAError(c, err)
+	}
+	return c.Status(fiber.StatusOK).JSON(model.AuthResponse{Message: "Password changed successfully. Other sessions were signed out."})
 }
 func (h *AccountHandlerAG) HandleChangeUsername(c *fiber.Ctx) error {
 	// Implementation for changing username
@@ -33,14 +50,165 @@ func (h *AccountHandlerAG) HandleProfilePictureUpload(c *fiber.Ctx) error {
 	return nil
 }
 func (h *AccountHandlerAG) HandleGenerateTwoFASetup(c *fiber.Ctx) error {
-	// Implementation for generating 2FA setup
-	return nil
+	userID, _ := c.Locals("userID").(string)
+	setup, err := h.profileManagerClient.GenerateTwoFASetup(userID)
+	if err != nil {
+		return writeTwoFAError(c, err)
+	}
+	return c.Status(fiber.StatusOK).JSON(setup)
 }
+
 func (h *AccountHandlerAG) HandleVerifyAndEnableTwoFA(c *fiber.Ctx) error {
-	// Implementation for verifying and enabling 2FA
-	return nil
+	var req model.EnableTwoFARequest
+	if err := c.BodyParser(&req); err != nil || req.Code == "" {
+		return c.Status(fiber.StatusBadRequest).JSON(model.ErrorResponse{Message: "2FA code is required.", Code: "400"})
+	}
+
+	userID, _ := c.Locals("userID").(string)
+	codes, err := h.profileManagerClient.VerifyAndEnableTwoFA(userID, req)
+	if err != nil {
+		return writeTwoFAError(c, err)
+	}
+	return c.Status(fiber.StatusOK).JSON(model.RecoveryCodesResponse{
+		Message:       "Two-factor authentication enabled. Store the recovery codes in a safe place.",
+		RecoveryCodes: codes,
+		Remaining:     int64(len(codes)),
+	})
 }
+
 func (h *AccountHandlerAG) HandleDisableTwoFA(c *fiber.Ctx) error {
-	// Implementation for disabling 2FA
-	return nil
+	var req model.DisableTwoFARequest
+	if err := c.BodyParser(&req); err != nil || req.Password == "" || req.Code == "" {
+		return c.Status(fiber.StatusBadRequest).JSON(model.ErrorResponse{Message: "Current password and 2FA code are required.", Code: "400"})
+	}
+
+	userID, _ := c.Locals("userID").(string)
+	if err := h.profileManagerClient.DisableTwoFA(userID, req, clientInfo(c, "")); err != nil {
+		return writeTwoFAError(c, err)
+	}
+	return c.Status(fiber.StatusOK).JSON(model.AuthResponse{Message: "Two-factor authentication disabled."})
+}
+
+func (h *AccountHandlerAG) HandleRegenerateRecoveryCodes(c *fiber.Ctx) error {
+	var req model.EnableTwoFARequest
+	if err := c.BodyParser(&req); err != nil || req.Code == "" {
+		return c.Status(fiber.StatusBadRequest).JSON(model.ErrorResponse{Message: "2FA code is required.", Code: "400"})
+	}
+
+	userID, _ := c.Locals("userID").(string)
+	codes, err := h.profileManagerClient.RegenerateRecoveryCodes(userID, req, clientInfo(c, ""))
+	if err != nil {
+		return writeTwoFAError(c, err)
+	}
+	return c.Status(fiber.StatusOK).JSON(model.RecoveryCodesResponse{
+		Message:       "New recovery codes issued; the previous ones no longer work.",
+		RecoveryCodes: codes,
+		Remaining:     int64(len(codes)),
+	})
+}
+
+func (h *AccountHandlerAG) HandleGetRecoveryCodesStatus(c *fiber.Ctx) error {
+	userID, _ := c.Locals("userID").(string)
+	remaining, err := h.profileManagerClient.GetRecoveryCodesRemaining(userID)
+	if err != nil {
+		return writeTwoFAError(c, err)
+	}
+	return c.Status(fiber.StatusOK).JSON(model.RecoveryCodesResponse{Remaining: remaining})
+}
+
+func writeTwoFAError(c *fiber.Ctx, err error) error {
+	if locked, werr := lockoutResponse(c, err); locked {
+		return werr
+	}
+	switch {
+	case errors.Is(err, service.ErrUserNotFound):
+		return c.Status(fiber.StatusNotFound).JSON(model.ErrorResponse{Message: "User not found.", Code: "404"})
+	case errors.Is(err, service.ErrTwoFAAlreadyEnabled):
+		return c.Status(fiber.StatusConflict).JSON(model.ErrorResponse{Message: "Two-factor authentication is already enabled.", Code: "409"})
+	case errors.Is(err, service.ErrTwoFANotEnabled):
+		return c.Status(fiber.StatusBadRequest).JSON(model.ErrorResponse{Message: "Two-factor authentication is not enabled.", Code: "400"})
+	case errors.Is(err, service.ErrTwoFASetupNotStarted):
+		return c.Status(fiber.StatusBadRequest).JSON(model.ErrorResponse{Message: "Generate a new 2FA secret first.", Code: "400"})
+	case errors.Is(err, service.ErrInvalidTwoFACode):
+		return c.Status(fiber.StatusBadRequest).JSON(model.ErrorResponse{Message: "Invalid 2FA code.", Code: "400"})
+	case errors.Is(err, service.ErrInvalidCredentials):
+		return c.Status(fiber.StatusForbidden).JSON(model.ErrorResponse{Message: "Current password is incorrect.", Code: "403"})
+	case errors.Is(err, service.ErrProfileManagerDown):
+		return c.Status(fiber.StatusServiceUnavailable).JSON(model.ErrorResponse{Message: "Service temporarily unavailable.", Code: "503"})
+	default:
+		utils.Log.Error("2FA account operation failed", zap.Error(err))
+		return c.Status(fiber.StatusInternalServerError).JSON(model.ErrorResponse{Message: "Internal server error.", Code: "500"})
+	}
+}
+
+// HandleRequestMobileVerification texts a code to the new number; it is saved on the account once confirmed.
+func (h *AccountHandlerAG) HandleRequestMobileVerification(c *fiber.Ctx) error {
+	var req model.OTPRequest
+	if err := c.BodyParser(&req); err != nil || req.Mobile == "" {
+		return c.Status(fiber.StatusBadRequest).JSON(model.ErrorResponse{Message: "Mobile number is required.", Code: "400"})
+	}
+
+	userID, _ := c.Locals("userID").(string)
+	if err := h.profileManagerClient.RequestMobileVerification(userID, req.Mobile, clientInfo(c, "")); err != nil {
+		return writeOTPError(c, err)
+	}
+	return c.Status(fiber.StatusOK).JSON(model.MobileResponse{Message: "Verification code sent."})
+}
+
+func (h *AccountHandlerAG) HandleVerifyMobile(c *fiber.Ctx) error {
+	var req model.VerifyMobileRequest
+	if err := c.BodyParser(&req); err != nil || req.Code == "" {
+		return c.Status(fiber.StatusBadRequest).JSON(model.ErrorResponse{Message: "Code is required.", Code: "400"})
+	}
+
+	userID, _ := c.Locals("userID").(string)
+	resp, err := h.profileManagerClient.VerifyMobile(userID, req.Code, clientInfo(c, ""))
+	if err != nil {
+		return writeOTPError(c, err)
+	}
+	return c.Status(fiber.StatusOK).JSON(resp)
+}
+
+func (h *AccountHandlerAG) HandleListSessions(c *fiber.Ctx) error {
+	userID, _ := c.Locals("userID").(string)
+	sessionID, _ := c.Locals("sessionID").(string)
+	sessions, err := h.profileManagerClient.ListSessions(userID, sessionID)
+	if err != nil {
+		return writeSessionError(c, err)
+	}
+	return c.Status(fiber.StatusOK).JSON(model.SessionsResponse{Sessions: sessions})
+}
+
+func (h *AccountHandlerAG) HandleRevokeSession(c *fiber.Ctx) error {
+	userID, _ := c.Locals("userID").(string)
+	if err := h.profileManagerClient.RevokeSession(userID, c.Params("sessionID")); err != nil {
+		return writeSessionError(c, err)
+	}
+	return c.Status(fiber.StatusOK).JSON(model.RevokeSessionsResponse{Message: "Session signed out.", Revoked: 1})
+}
+
+// HandleRevokeOtherSessions signs out every device except the one making the request.
+func (h *AccountHandlerAG) HandleRevokeOtherSessions(c *fiber.Ctx) error {
+	userID, _ := c.Locals("userID").(string)
+	sessionID, _ := c.Locals("sessionID").(string)
+	if sessionID == "" {
+		return c.Status(fiber.StatusBadRequest).JSON(model.ErrorResponse{Message: "This token is not bound to a session; log in again.", Code: "400"})
+	}
+	revoked, err := h.profileManagerClient.RevokeOtherSessions(userID, sessionID)
+	if err != nil {
+		return writeSessionError(c, err)
+	}
+	return c.Status(fiber.StatusOK).JSON(model.RevokeSessionsResponse{Message: "All other sessions signed out.", Revoked: revoked})
+}
+
+func writeSessionError(c *fiber.Ctx, err error) error {
+	switch {
+	case errors.Is(err, service.ErrSessionNotFound):
+		return c.Status(fiber.StatusNotFound).JSON(model.ErrorResponse{Message: "Session not found.", Code: "404"})
+	case errors.Is(err, service.ErrProfileManagerDown):
+		return c.Status(fiber.StatusServiceUnavailable).JSON(model.ErrorResponse{Message: "Service temporarily unavailable.", Code: "503"})
+	default:
+		utils.Log.Error("Session operation failed", zap.Error(err))
+		return c.Status(fiber.StatusInternalServerError).JSON(model.ErrorResponse{Message: "Internal server error.", Code: "500"})
+	}
 }
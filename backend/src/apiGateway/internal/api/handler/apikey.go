@@ -0,0 +1,111 @@
+package handler
+
+import (
+	"errors"
+	"fmt"
+
+	"gold-api/internal/model"
+	service "gold-api/internal/service/common"
+	profilemanager "gold-api/internal/service/profilemanger"
+	"gold-api/internal/utils"
+
+	"common-gold/audit"
+
+	"github.com/gofiber/fiber/v2"
+	"go.uber.org/zap"
+)
+
+// APIKeyHandler manages API keys in Profile Manager. A key is always created for the caller, so it can never
+// carry more than the caller holds. Creating and revoking keys is recorded in the audit log.
+type APIKeyHandler struct {
+	profileManagerClient profilemanager.ProfileManagerClient
+	audit                auditTrail
+}
+
+func NewAPIKeyHandler(client profilemanager.ProfileManagerClient, auditSink audit.Sink) (*APIKeyHandler, error) {
+	if client == nil {
+		return nil, fmt.Errorf("ProfileManagerClient cannot be nil for APIKeyHandler")
+	}
+	if auditSink == nil {
+		return nil, fmt.Errorf("audit sink cannot be nil for APIKeyHandler")
+	}
+	return &APIKeyHandler{profileManagerClient: client, audit: auditTrail{sink: auditSink}}, nil
+}
+
+// HandleListAPIKeys lists the caller's own keys.
+func (h *APIKeyHandler) HandleListAPIKeys(c *fiber.Ctx) error {
+	userID, ok := c.Locals("userID").(string)
+	if !ok || userID == "" {
+		return c.Status(fiber.StatusUnauthorized).JSON(model.ErrorResponse{Message: "User ID not found in token.", Code: "401"})
+	}
+	keys, err := h.profileManagerClient.ListAPIKeys(userID)
+	if err != nil {
+		return writeAPIKeyError(c, err)
+	}
+	return c.Status(fiber.StatusOK).JSON(model.APIKeysResponse{APIKeys: keys})
+}
+
+func (h *APIKeyHandler) HandleCreateAPIKey(c *fiber.Ctx) error {
+	var req model.CreateAPIKeyRequest
+	if err := c.BodyParser(&req); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(model.ErrorResponse{Message: "Invalid request body.", Code: "400"})
+	}
+	userID, ok := c.Locals("userID").(string)
+	if !ok || userID == "" {
+		return c.Status(fiber.StatusUnauthorized).JSON(model.ErrorResponse{Message: "User ID not found in token.", Code: "401"})
+	}
+	req.UserID = userID
+
+	resp, err := h.profileManagerClient.CreateAPIKey(req)
+	if err != nil {
+		return writeAPIKeyError(c, err)
+	}
+	h.audit.record(c, audit.Event{
+		Action:      audit.ActionAPIKeyCreate,
+		Category:    audit.CategoryAuth,
+		TargetType:  "api_key",
+		TargetID:    resp.APIKey.ID,
+		Description: fmt.Sprintf("ایجاد کلید API %s", resp.APIKey.Name),
+		After:       audit.Snapshot(resp.APIKey),
+	})
+	return c.Status(fiber.StatusCreated).JSON(resp)
+}
+
+// HandleRevokeAPIKey revokes one of the caller's own keys; another user's key is reported as not found.
+func (h *APIKeyHandler) HandleRevokeAPIKey(c *fiber.Ctx) error {
+	userID, ok := c.Locals("userID").(string)
+	if !ok || userID == "" {
+		return c.Status(fiber.StatusUnauthorized).JSON(model.ErrorResponse{Message: "User ID not found in token.", Code: "401"})
+	}
+	key, err := h.profileManagerClient.RevokeAPIKey(userID, c.Params("keyID"))
+	if err != nil {
+		return writeAPIKeyError(c, err)
+	}
+	h.audit.record(c, audit.Event{
+		Action:      audit.ActionAPIKeyRevoke,
+		Category:    audit.CategoryAuth,
+		TargetType:  "api_key",
+		TargetID:    key.ID,
+		Description: fmt.Sprintf("ابطال کلید API %s", key.Name),
+		After:       audit.Snapshot(key),
+	})
+	return c.Status(fiber.StatusOK).JSON(key)
+}
+
+func writeAPIKeyError(c *fiber.Ctx, err error) error {
+	switch {
+	case errors.Is(err, service.ErrAPIKeyNotFound):
+		return c.Status(fiber.StatusNotFound).JSON(model.ErrorResponse{Message: "API key not found.", Code: "404"})
+	case errors.Is(err, service.ErrUserNotFound):
+		return c.Status(fiber.StatusNotFound).JSON(model.ErrorResponse{Message: "User not found.", Code: "404"})
+	case errors.Is(err, service.ErrInvalidAPIKeyRequest), errors.Is(err, service.ErrUnknownPermission):
+		return c.Status(fiber.StatusBadRequest).JSON(model.ErrorResponse{Message: err.Error(), Code: "400"})
+	case errors.Is(err, service.ErrPermissionNotHeld):
+		return c.Status(fiber.StatusForbidden).JSON(model.ErrorResponse{Message: err.Error(), Code: "403"})
+	case errors.Is(err, service.ErrProfileManagerDown):
+		return c.Status(fiber.StatusServiceUnavailable).JSON(model.ErrorResponse{Message: "Service temporarily unavailable.", Code: "503"})
+	default:
+		utils.Log.Error("API key operation failed", zap.Error(err))
+		return c.Status(fiber.StatusInternalServerError).JSON(model.ErrorResponse{Message: "Internal server error.", Code: "500"})
+	}
+}
@@ -0,0 +1,93 @@
+package handler
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"gold-api/internal/model"
+	service "gold-api/internal/service/common"
+	crmmanager "gold-api/internal/service/crmManager"
+	"gold-api/internal/utils"
+	"time"
+
+	"common-gold/audit"
+
+	"github.com/gofiber/fiber/v2"
+	"go.uber.org/zap"
+)
+
+// AuditHandler exposes CRM Manager's audit log search and hash-chain check.
+type AuditHandler struct {
+	crmManagerClient crmmanager.CrmManagerClient
+}
+
+func NewAuditHandler(client crmmanager.CrmManagerClient) (*AuditHandler, error) {
+	if client == nil {
+		return nil, fmt.Errorf("CrmManagerClient cannot be nil for AuditHandler")
+	}
+	return &AuditHandler{crmManagerClient: client}, nil
+}
+
+// HandleListEvents passes the query string (bidId, actorId, action, category, targetType, targetId,
+// correlationId, q, from, to, page, pageSize) through to CRM Manager unchanged.
+func (h *AuditHandler) HandleListEvents(c *fiber.Ctx) error {
+	page, err := h.crmManagerClient.ListAuditEvents(c.Context(), string(c.Request().URI().QueryString()))
+	if err != nil {
+		return writeCrmError(c, err, "Failed to list audit events.")
+	}
+	return c.Status(fiber.StatusOK).JSON(page)
+}
+
+func (h *AuditHandler) HandleVerifyChain(c *fiber.Ctx) error {
+	report, err := h.crmManagerClient.VerifyAuditChain(c.Context())
+	if err != nil {
+		return writeCrmError(c, err, "Failed to verify the audit log.")
+	}
+	return c.Status(fiber.StatusOK).JSON(report)
+}
+
+func writeCrmError(c *fiber.Ctx, err error, message string) error {
+	var upstream *service.UpstreamError
+	switch {
+	case errors.As(err, &upstream):
+		return c.Status(upstream.StatusCode).JSON(model.ErrorResponse{Message: upstream.Message, Code: fmt.Sprint(upstream.StatusCode)})
+	case errors.Is(err, service.ErrCrmManagerDown):
+		return c.Status(fiber.StatusServiceUnavailable).JSON(model.ErrorResponse{Message: "Service temporarily unavailable.", Code: "503"})
+	default:
+		utils.Log.Error(message, zap.Error(err))
+		return c.Status(fiber.StatusInternalServerError).JSON(model.ErrorResponse{Message: message, Code: "500"})
+	}
+}
+
+const auditSendTimeout = 5 * time.Second
+
+// auditTrail records gateway events (logins, RBAC changes) in CRM Manager's audit log. Sending happens in the
+// background so a slow or unavailable audit store never delays or fails the user's request; failures are logged.
+type auditTrail struct {
+	sink audit.Sink
+}
+
+func (t auditTrail) record(c *fiber.Ctx, event audit.Event) {
+	if event.ActorID == "" {
+		event.ActorID, _ = c.Locals("userID").(string)
+	}
+	if event.ActorName == "" {
+		event.ActorName, _ = c.Locals("username").(string)
+		// Requests made with an API key act as its owner; the key is named so they can be told apart.
+		if keyName, ok := c.Locals("apiKeyName").(string); ok {
+			event.ActorName = fmt.Sprintf("%s (کلید API: %s)", event.ActorName, keyName)
+		}
+	}
+	if meta, ok := c.Locals(audit.MetaKey).(audit.Meta); ok {
+		meta.Apply(&event)
+	}
+	event.OccurredAt = time.Now()
+
+	go func() {
+		ctx, cancel := context.WithTimeout(context.Background(), auditSendTimeout)
+		defer cancel()
+		if err := t.sink.Record(ctx, event); err != nil {
+			utils.Log.Error("Failed to record audit event", zap.String("action", event.Action), zap.String("correlation_id", event.CorrelationID), zap.Error(err))
+		}
+	}()
+}
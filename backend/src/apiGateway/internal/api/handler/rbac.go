@@ -0,0 +1,191 @@
+package handler
+
+import (
+	"errors"
+	"fmt"
+
+	"gold-api/internal/api/authz"
+	"gold-api/internal/model"
+	service "gold-api/internal/service/common"
+	profilemanager "gold-api/internal/service/profilemanger"
+	"gold-api/internal/utils"
+
+	"common-gold/audit"
+
+	"github.com/gofiber/fiber/v2"
+	"go.uber.org/zap"
+)
+
+// RBACHandler manages roles and per-user permissions in Profile Manager. After a change it refreshes the
+// gateway's own policy cache so the change applies here immediately rather than on the next poll.
+// Every change is recorded in the audit log with the role or overrides before and after it.
+type RBACHandler struct {
+	profileManagerClient profilemanager.ProfileManagerClient
+	permissionService    authz.PermissionService
+	audit                auditTrail
+}
+
+func NewRBACHandler(client profilemanager.ProfileManagerClient, permService authz.PermissionService, auditSink audit.Sink) (*RBACHandler, error) {
+	if client == nil {
+		return nil, fmt.Errorf("ProfileManagerClient cannot be nil for RBACHandler")
+	}
+	if permService == nil {
+		return nil, fmt.Errorf("PermissionService cannot be nil for RBACHandler")
+	}
+	if auditSink == nil {
+		return nil, fmt.Errorf("audit sink cannot be nil for RBACHandler")
+	}
+	return &RBACHandler{profileManagerClient: client, permissionService: permService, audit: auditTrail{sink: auditSink}}, nil
+}
+
+func (h *RBACHandler) HandleListRoles(c *fiber.Ctx) error {
+	roles, err := h.profileManagerClient.ListRoles()
+	if err != nil {
+		return writeRBACError(c, err)
+	}
+	return c.Status(fiber.StatusOK).JSON(model.RolesResponse{Roles: roles})
+}
+
+func (h *RBACHandler) HandleCreateRole(c *fiber.Ctx) error {
+	var req model.RoleRequest
+	if err := c.BodyParser(&req); err != nil || req.Name == "" {
+		return c.Status(fiber.StatusBadRequest).JSON(model.ErrorResponse{Message: "Role name is required.", Code: "400"})
+	}
+	role, err := h.profileManagerClient.CreateRole(req)
+	if err != nil {
+		return writeRBACError(c, err)
+	}
+	h.refreshPolicy(c)
+	h.audit.record(c, audit.Event{
+		Action:      audit.ActionRoleCreate,
+		Category:    audit.CategoryRBAC,
+		TargetType:  "role",
+		TargetID:    role.Name,
+		Description: fmt.Sprintf("ایجاد نقش %s", role.Name),
+		After:       audit.Snapshot(role),
+	})
+	return c.Status(fiber.StatusCreated).JSON(role)
+}
+
+func (h *RBACHandler) HandleUpdateRole(c *fiber.Ctx) error {
+	var req model.RoleRequest
+	if err := c.BodyParser(&req); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(model.ErrorResponse{Message: "Invalid request body.", Code: "400"})
+	}
+	before := h.findRole(c.Params("name"))
+	role, err := h.profileManagerClient.UpdateRole(c.Params("name"), req)
+	if err != nil {
+		return writeRBACError(c, err)
+	}
+	h.refreshPolicy(c)
+	h.audit.record(c, audit.Event{
+		Action:      audit.ActionRoleUpdate,
+		Category:    audit.CategoryRBAC,
+		TargetType:  "role",
+		TargetID:    c.Params("name"),
+		Description: fmt.Sprintf("ویرایش نقش %s", c.Params("name")),
+		Before:      audit.Snapshot(before),
+		After:       audit.Snapshot(role),
+	})
+	return c.Status(fiber.StatusOK).JSON(role)
+}
+
+func (h *RBACHandler) HandleDeleteRole(c *fiber.Ctx) error {
+	before := h.findRole(c.Params("name"))
+	if err := h.profileManagerClient.DeleteRole(c.Params("name")); err != nil {
+		return writeRBACError(c, err)
+	}
+	h.refreshPolicy(c)
+	h.audit.record(c, audit.Event{
+		Action:      audit.ActionRoleDelete,
+		Category:    audit.CategoryRBAC,
+		TargetType:  "role",
+		TargetID:    c.Params("name"),
+		Description: fmt.Sprintf("حذف نقش %s", c.Params("name")),
+		Before:      audit.Snapshot(before),
+	})
+	return c.SendStatus(fiber.StatusNoContent)
+}
+
+func (h *RBACHandler) HandleListPermissions(c *fiber.Ctx) error {
+	perms, err := h.profileManagerClient.ListPermissions()
+	if err != nil {
+		return writeRBACError(c, err)
+	}
+	return c.Status(fiber.StatusOK).JSON(model.PermissionsResponse{Permissions: perms})
+}
+
+func (h *RBACHandler) HandleGetUserPermissions(c *fiber.Ctx) error {
+	resp, err := h.profileManagerClient.GetUserPermissions(c.Params("userID"))
+	if err != nil {
+		return writeRBACError(c, err)
+	}
+	return c.Status(fiber.StatusOK).JSON(resp)
+}
+
+func (h *RBACHandler) HandleSetUserPermissions(c *fiber.Ctx) error {
+	var req model.UserPermissionsRequest
+	if err := c.BodyParser(&req); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(model.ErrorResponse{Message: "Invalid request body.", Code: "400"})
+	}
+	before, err := h.profileManagerClient.GetUserPermissions(c.Params("userID"))
+	if err != nil {
+		return writeRBACError(c, err)
+	}
+	resp, err := h.profileManagerClient.SetUserPermissions(c.Params("userID"), req)
+	if err != nil {
+		return writeRBACError(c, err)
+	}
+	h.refreshPolicy(c)
+	h.audit.record(c, audit.Event{
+		Action:      audit.ActionUserPermissions,
+		Category:    audit.CategoryRBAC,
+		TargetType:  "user",
+		TargetID:    c.Params("userID"),
+		Description: "تغییر مجوزهای اختصاصی کاربر",
+		Before:      audit.Snapshot(before),
+		After:       audit.Snapshot(resp),
+	})
+	return c.Status(fiber.StatusOK).JSON(resp)
+}
+
+// findRole returns the role as it is before a change, or nil if it cannot be read; the change itself then reports the error.
+func (h *RBACHandler) findRole(name string) *model.Role {
+	roles, err := h.profileManagerClient.ListRoles()
+	if err != nil {
+		return nil
+	}
+	for i := range roles {
+		if roles[i].Name == name {
+			return &roles[i]
+		}
+	}
+	return nil
+}
+
+// refreshPolicy is best effort: the change is already stored and the regular poll will pick it up anyway.
+func (h *RBACHandler) refreshPolicy(c *fiber.Ctx) {
+	_ = h.permissionService.Refresh(c.Context())
+}
+
+func writeRBACError(c *fiber.Ctx, err error) error {
+	switch {
+	case errors.Is(err, service.ErrRoleNotFound):
+		return c.Status(fiber.StatusNotFound).JSON(model.ErrorResponse{Message: "Role not found.", Code: "404"})
+	case errors.Is(err, service.ErrUserNotFound):
+		return c.Status(fiber.StatusNotFound).JSON(model.ErrorResponse{Message: "User not found.", Code: "404"})
+	case errors.Is(err, service.ErrRoleAlreadyExists):
+		return c.Status(fiber.StatusConflict).JSON(model.ErrorResponse{Message: "A role with this name already exists.", Code: "409"})
+	case errors.Is(err, service.ErrRoleInUse):
+		return c.Status(fiber.StatusConflict).JSON(model.ErrorResponse{Message: "Role is still assigned to users.", Code: "409"})
+	case errors.Is(err, service.ErrSystemRole):
+		return c.Status(fiber.StatusConflict).JSON(model.ErrorResponse{Message: "Built-in roles cannot be deleted.", Code: "409"})
+	case errors.Is(err, service.ErrUnknownPermission), errors.Is(err, service.ErrInvalidRoleName), errors.Is(err, service.ErrConflictingOverride):
+		return c.Status(fiber.StatusBadRequest).JSON(model.ErrorResponse{Message: err.Error(), Code: "400"})
+	case errors.Is(err, service.ErrProfileManagerDown):
+		return c.Status(fiber.StatusServiceUnavailable).JSON(model.ErrorResponse{Message: "Service temporarily unavailable.", Code: "503"})
+	default:
+		utils.Log.Error("RBAC operation failed", zap.Error(err))
+		return c.Status(fiber.StatusInternalServerError).JSON(model.ErrorResponse{Message: "Internal server error.", Code: "500"})
+	}
+}
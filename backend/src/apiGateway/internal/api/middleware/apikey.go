@@ -0,0 +1,191 @@
+package middleware
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"common-gold/authz"
+
+	"github.com/gofiber/fiber/v2"
+	"go.uber.org/zap"
+
+	"gold-api/internal/model"
+	service "gold-api/internal/service/common"
+)
+
+// apiKeyCacheTTL is how long a verified key is trusted without asking Profile Manager again. It bounds how stale
+// a key's last-used time can be; revocation does not wait for it, the revocation marker is checked on every request.
+const apiKeyCacheTTL = time.Minute
+
+// APIKeyHeader carries an API key for clients that cannot set the Authorization header.
+const APIKeyHeader = "X-API-Key"
+
+// APIKeyVerifier exchanges an API key for its principal; the Profile Manager client implements it.
+type APIKeyVerifier interface {
+	VerifyAPIKey(key string, client model.ClientInfo) (*model.APIKeyPrincipal, error)
+}
+
+type cachedAPIKey struct {
+	principal *model.APIKeyPrincipal
+	until     time.Time
+}
+
+// apiKeyCache keeps verified principals keyed by the SHA-256 of the key, so plain keys are not held in memory.
+type apiKeyCache struct {
+	mu      sync.Mutex
+	entries map[string]cachedAPIKey
+}
+
+func newAPIKeyCache() *apiKeyCache {
+	return &apiKeyCache{entries: make(map[string]cachedAPIKey)}
+}
+
+func (c *apiKeyCache) get(hash string, now time.Time) *model.APIKeyPrincipal {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	entry, ok := c.entries[hash]
+	if !ok || !now.Before(entry.until) {
+		return nil
+	}
+	return entry.principal
+}
+
+// put never keeps a principal past the key's expiry or past its owner token's expiry.
+func (c *apiKeyCache) put(hash string, principal *model.APIKeyPrincipal, now time.Time) {
+	until := now.Add(apiKeyCacheTTL)
+	if principal.ExpiresAt != nil && principal.ExpiresAt.Before(until) {
+		until = *principal.ExpiresAt
+	}
+	// Leave a margin so a forwarded token does not expire on its way downstream.
+	if tokenExp := time.Unix(principal.TokenExp, 0).Add(-apiKeyCacheTTL); tokenExp.Before(until) {
+		until = tokenExp
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	for h, entry := range c.entries {
+		if !now.Before(entry.until) {
+			delete(c.entries, h)
+		}
+	}
+	c.entries[hash] = cachedAPIKey{principal: principal, until: until}
+}
+
+func (c *apiKeyCache) drop(hash string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	delete(c.entries, hash)
+}
+
+// apiKeyFrom returns the API key of the request, from X-API-Key or from an Authorization bearer that is a key
+// rather than a JWT, or "" when the request does not use one.
+func apiKeyFrom(c *fiber.Ctx) string {
+	if key := c.Get(APIKeyHeader); key != "" {
+		return key
+	}
+	if bearer := strings.TrimPrefix(c.Get("Authorization"), "Bearer "); strings.HasPrefix(bearer, model.APIKeyPrefix) {
+		return bearer
+	}
+	return ""
+}
+
+// authorizeAPIKey authenticates a request made with an API key. The request acts as the key's owner, is allowed
+// only what both the key and the owner are allowed, and stays within the key's business if it has one.
+func (m *AuthMiddleware) authorizeAPIKey(c *fiber.Ctx, key, requiredPermission string) error {
+	sum := sha256.Sum256([]byte(key))
+	hash := hex.EncodeToString(sum[:])
+	now := time.Now()
+
+	principal := m.apiKeys.get(hash, now)
+	if principal == nil {
+		var err error
+		principal, err = m.apiKeyVerifier.VerifyAPIKey(key, model.ClientInfo{IPAddress: c.IP(), UserAgent: c.Get(fiber.HeaderUserAgent)})
+		if errors.Is(err, service.ErrInvalidAPIKey) {
+			m.logger.Warn("Invalid API key used for protected route", zap.String("path", c.OriginalURL()), zap.String("ip", c.IP()))
+			return c.Status(fiber.StatusUnauthorized).JSON(model.ErrorResponse{Message: "Invalid, expired or revoked API key.", Details: "invalid_api_key"})
+		}
+		if err != nil {
+			m.logger.Error("Failed to verify API key", zap.Error(err))
+			return c.Status(fiber.StatusServiceUnavailable).JSON(model.ErrorResponse{Message: "Authentication is temporarily unavailable."})
+		}
+		m.apiKeys.put(hash, principal, now)
+	}
+
+	revoked, err := m.revocationRepo.IsAPIKeyRevoked(principal.KeyID)
+	if err != nil {
+		m.logger.Error("Failed to check API key revocation", zap.Error(err), zap.String("apiKeyID", principal.KeyID))
+		return c.Status(fiber.StatusServiceUnavailable).JSON(model.ErrorResponse{Message: "Authentication is temporarily unavailable."})
+	}
+	if revoked {
+		m.apiKeys.drop(hash)
+		m.logger.Warn("Revoked API key used for protected route", zap.String("apiKeyID", principal.KeyID), zap.String("path", c.OriginalURL()))
+		return c.Status(fiber.StatusUnauthorized).JSON(model.ErrorResponse{Message: "Invalid, expired or revoked API key.", Details: "api_key_revoked"})
+	}
+
+	c.Locals("userToken", principal.Token)
+	c.Locals("userID", principal.UserID)
+	c.Locals("username", principal.Username)
+	c.Locals("userRoles", principal.Roles)
+	c.Locals("apiKeyID", principal.KeyID)
+	c.Locals("apiKeyName", principal.Name)
+
+	if !authz.Covers(principal.Permissions, requiredPermission) {
+		m.logger.Warn("Access denied: API key does not grant required permission",
+			zap.String("apiKeyID", principal.KeyID), zap.String("required_permission", requiredPermission), zap.String("path", c.OriginalURL()))
+		return c.Status(fiber.StatusForbidden).JSON(model.ErrorResponse{Message: "Access denied: The API key does not grant this permission."})
+	}
+	// The owner may have lost the permission since the key was created.
+	allowed, err := m.permissionService.HasPermission(principal.UserID, principal.Roles, requiredPermission)
+	if err != nil {
+		m.logger.Error("Failed to evaluate permission", zap.Error(err), zap.String("userID", principal.UserID))
+		return c.Status(fiber.StatusServiceUnavailable).JSON(model.ErrorResponse{Message: "Authorization is temporarily unavailable."})
+	}
+	if !allowed {
+		m.logger.Warn("Access denied: API key owner does not have required permission",
+			zap.String("apiKeyID", principal.KeyID), zap.String("userID", principal.UserID), zap.String("required_permission", requiredPermission))
+		return c.Status(fiber.StatusForbidden).JSON(model.ErrorResponse{Message: "Access denied: Insufficient permissions."})
+	}
+
+	if principal.BusinessID != nil {
+		if !scopeToBusiness(c, *principal.BusinessID) {
+			m.logger.Warn("Access denied: API key used outside its business",
+				zap.String("apiKeyID", principal.KeyID), zap.String("bidId", c.Query("bidId")), zap.String("path", c.OriginalURL()))
+			return c.Status(fiber.StatusForbidden).JSON(model.ErrorResponse{Message: "Access denied: The API key is limited to another business."})
+		}
+		c.Locals("businessID", *principal.BusinessID)
+	}
+
+	return c.Next()
+}
+
+// scopeToBusiness refuses early a request whose bidId query parameter names another business and fills in a
+// missing one. It is only a first check: the forwarded token carries the business too, and CRM Manager enforces
+// it on request bodies and on records looked up by ID.
+func scopeToBusiness(c *fiber.Ctx, businessID uint) bool {
+	want := strconv.FormatUint(uint64(businessID), 10)
+	got := c.Query("bidId")
+	if got == "" {
+		uri := c.Request().URI()
+		uri.QueryArgs().Set("bidId", want)
+		// Handlers that forward the raw query string read it from the URI, not from the parsed args.
+		uri.SetQueryStringBytes(uri.QueryArgs().QueryString())
+		return true
+	}
+	return got == want
+}
+
+// RejectAPIKeys keeps API keys away from routes that manage the owner's own account and credentials,
+// so a leaked key cannot be used to mint further keys or take over the account.
+func (m *AuthMiddleware) RejectAPIKeys() fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		if apiKeyFrom(c) != "" {
+			m.logger.Warn("API key used for an interactive-only route", zap.String("path", c.OriginalURL()))
+			return c.Status(fiber.StatusForbidden).JSON(model.ErrorResponse{Message: "This endpoint cannot be used with an API key.", Details: "api_key_not_allowed"})
+		}
+		return c.Next()
+	}
+}
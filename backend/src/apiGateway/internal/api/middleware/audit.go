@@ -0,0 +1,23 @@
+package middleware
+
+import (
+	"common-gold/audit"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// AuditMeta gives every request a correlation ID and records the caller's IP and user agent, so audit
+// events written here or in downstream services can be tied back to the same request. The gateway is the
+// edge, so the client's own X-Client-* headers are never trusted.
+func AuditMeta() fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		meta := audit.Meta{
+			CorrelationID: audit.CorrelationIDOr(c.Get(audit.CorrelationHeader)),
+			IPAddress:     c.IP(),
+			UserAgent:     c.Get(fiber.HeaderUserAgent),
+		}
+		c.Locals(audit.MetaKey, meta)
+		c.Set(audit.CorrelationHeader, meta.CorrelationID)
+		return c.Next()
+	}
+}
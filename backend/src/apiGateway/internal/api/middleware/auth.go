@@ -10,16 +10,20 @@ import (
 
 	"gold-api/internal/api/authz"
 	"gold-api/internal/model"
+	redisdb "gold-api/internal/repo/db/redisDb"
 	"gold-api/internal/utils"
 )
 
 type AuthMiddleware struct {
 	permissionService authz.PermissionService
 	jwtValidator      utils.JWTValidator
+	revocationRepo    redisdb.RevocationRepository
+	apiKeyVerifier    APIKeyVerifier
+	apiKeys           *apiKeyCache
 	logger            *zap.Logger
 }
 
-func NewAuthMiddleware(permService authz.PermissionService, logger *zap.Logger, jwtValidator utils.JWTValidator) (*AuthMiddleware, error) {
+func NewAuthMiddleware(permService authz.PermissionService, logger *zap.Logger, jwtValidator utils.JWTValidator, revocationRepo redisdb.RevocationRepository, apiKeyVerifier APIKeyVerifier) (*AuthMiddleware, error) {
 	if permService == nil {
 		return nil, fmt.Errorf("permissionService cannot be nil for AuthMiddleware")
 	}
@@ -29,16 +33,29 @@ func NewAuthMiddleware(permService authz.PermissionService, logger *zap.Logger,
 	if jwtValidator == nil {
 		return nil, fmt.Errorf("JWTValidator cannot be nil for AuthMiddleware")
 	}
+	if revocationRepo == nil {
+		return nil, fmt.Errorf("RevocationRepository cannot be nil for AuthMiddleware")
+	}
+	if apiKeyVerifier == nil {
+		return nil, fmt.Errorf("APIKeyVerifier cannot be nil for AuthMiddleware")
+	}
 
 	return &AuthMiddleware{
 		permissionService: permService,
 		jwtValidator:      jwtValidator,
+		revocationRepo:    revocationRepo,
+		apiKeyVerifier:    apiKeyVerifier,
+		apiKeys:           newAPIKeyCache(),
 		logger:            logger,
 	}, nil
 }
 
 func (m *AuthMiddleware) AuthorizeMiddleware(requiredPermission string) fiber.Handler {
 	return func(c *fiber.Ctx) error {
+		if key := apiKeyFrom(c); key != "" {
+			return m.authorizeAPIKey(c, key, requiredPermission)
+		}
+
 		authHeader := c.Get("Authorization")
 		m.logger.Debug("Received Authorization header", zap.String("header", authHeader))
 		if authHeader == "" {
@@ -62,9 +79,19 @@ func (m *AuthMiddleware) AuthorizeMiddleware(requiredPermission string) fiber.Ha
 			return c.Status(fiber.StatusUnauthorized).JSON(model.ErrorResponse{Message: "Invalid or expired token", Details: err.Error()})
 		}
 
+		if revoked, err := m.isRevoked(tokenString, claims); err != nil {
+			m.logger.Error("Failed to check token revocation", zap.Error(err), zap.String("userID", claims.UserID))
+			return c.Status(fiber.StatusServiceUnavailable).JSON(model.ErrorResponse{Message: "Authentication is temporarily unavailable."})
+		} else if revoked {
+			m.logger.Warn("Revoked token used for protected route",
+				zap.String("userID", claims.UserID), zap.String("sessionID", claims.SessionID), zap.String("path", c.OriginalURL()))
+			return c.Status(fiber.StatusUnauthorized).JSON(model.ErrorResponse{Message: "Invalid or expired token", Details: "token_revoked"})
+		}
+
 		c.Locals("userToken", tokenString)
 		c.Locals("userID", claims.UserID)
 		c.Locals("username", claims.Username)
+		c.Locals("sessionID", claims.SessionID)
 
 		var userRoles []string
 		if err := json.Unmarshal(claims.Roles, &userRoles); err != nil {
@@ -74,7 +101,12 @@ func (m *AuthMiddleware) AuthorizeMiddleware(requiredPermission string) fiber.Ha
 		}
 		c.Locals("userRoles", userRoles)
 
-		if !m.permissionService.HasPermission(userRoles, requiredPermission) {
+		allowed, err := m.permissionService.HasPermission(claims.UserID, userRoles, requiredPermission)
+		if err != nil {
+			m.logger.Error("Failed to evaluate permission", zap.Error(err), zap.String("userID", claims.UserID))
+			return c.Status(fiber.StatusServiceUnavailable).JSON(model.ErrorResponse{Message: "Authorization is temporarily unavailable."})
+		}
+		if !allowed {
 			m.logger.Warn("Access denied: User does not have required permission",
 				zap.String("userID", claims.UserID),
 				zap.Strings("user_roles", userRoles),
@@ -87,6 +119,25 @@ func (m *AuthMiddleware) AuthorizeMiddleware(requiredPermission string) fiber.Ha
 	}
 }
 
+// isRevoked reports whether the token was logged out, belongs to a signed-out session or was issued
+// before all of the user's sessions were revoked (e.g. by a password reset).
+func (m *AuthMiddleware) isRevoked(tokenString string, claims *model.CustomClaims) (bool, error) {
+	blacklisted, err := m.revocationRepo.IsTokenBlacklisted(tokenString)
+	if err != nil || blacklisted {
+		return blacklisted, err
+	}
+	if claims.SessionID != "" {
+		revoked, err := m.revocationRepo.IsSessionRevoked(claims.SessionID)
+		if err != nil || revoked {
+			return revoked, err
+		}
+	}
+	if claims.IssuedAt == nil {
+		return false, nil
+	}
+	return m.revocationRepo.IsUserTokenRevoked(claims.UserID, claims.IssuedAt.Time)
+}
+
 /*func (m *AuthMiddleware) VerifyServiceToken() fiber.Handler {
 	return func(c *fiber.Ctx) error {
 		internalJWTString := c.Get("X-Internal-JWT")
@@ -18,7 +18,8 @@ func SetUpAccountRoutes(apiGroup fiber.Router, accountHandlerAG *handler.Account
 		return fmt.Errorf("AuthMiddleware is nil in SetUpAccountRoutes")
 	}
 
-	accountGroup := apiGroup.Group("/account")
+	// A key acts for its owner but must not change the owner's credentials, 2FA or sessions.
+	accountGroup := apiGroup.Group("/account", authMiddleware.RejectAPIKeys())
 	utils.Log.Info("Configuring /api/v1/account protected routes.")
 
 	accountGroup.Post("/change-username", authMiddleware.AuthorizeMiddleware(model.PermUserUpdate), accountHandlerAG.HandleChangeUsername)
@@ -29,7 +30,18 @@ func SetUpAccountRoutes(apiGroup fiber.Router, accountHandlerAG *handler.Account
 	twoFASetupGroup.Post("/generate-secret", authMiddleware.AuthorizeMiddleware(model.PermUserUpdate), accountHandlerAG.HandleGenerateTwoFASetup)
 	twoFASetupGroup.Post("/enable", authMiddleware.AuthorizeMiddleware(model.PermUserUpdate), accountHandlerAG.HandleVerifyAndEnableTwoFA)
 	twoFASetupGroup.Post("/disable", authMiddleware.AuthorizeMiddleware(model.PermUserUpdate), accountHandlerAG.HandleDisableTwoFA)
+	twoFASetupGroup.Get("/recovery-codes", authMiddleware.AuthorizeMiddleware(model.PermUserUpdate), accountHandlerAG.HandleGetRecoveryCodesStatus)
+	twoFASetupGroup.Post("/recovery-codes/regenerate", authMiddleware.AuthorizeMiddleware(model.PermUserUpdate), accountHandlerAG.HandleRegenerateRecoveryCodes)
 
-	utils.Log.Info("/account routes and /account/2fa routes configured with RBAC.")
+	mobileGroup := accountGroup.Group("/mobile")
+	mobileGroup.Post("/", authMiddleware.AuthorizeMiddleware(model.PermUserUpdate), accountHandlerAG.HandleRequestMobileVerification)
+	mobileGroup.Post("/verify", authMiddleware.AuthorizeMiddleware(model.PermUserUpdate), accountHandlerAG.HandleVerifyMobile)
+
+	sessionGroup := accountGroup.Group("/sessions")
+	sessionGroup.Get("/", authMiddleware.AuthorizeMiddleware(model.PermUserUpdate), accountHandlerAG.HandleListSessions)
+	sessionGroup.Post("/revoke-others", authMiddleware.AuthorizeMiddleware(model.PermUserUpdate), accountHandlerAG.HandleRevokeOtherSessions)
+	sessionGroup.Delete("/:sessionID", authMiddleware.AuthorizeMiddleware(model.PermUserUpdate), accountHandlerAG.HandleRevokeSession)
+
+	utils.Log.Info("/account, /account/2fa, /account/mobile and /account/sessions routes configured with RBAC.")
 	return nil
 }
@@ -0,0 +1,31 @@
+package server
+
+import (
+	"fmt"
+	"gold-api/internal/api/handler"
+	"gold-api/internal/api/middleware"
+	"gold-api/internal/model"
+	"gold-api/internal/utils"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+func SetUpAPIKeyRoutes(apiGroup fiber.Router, apiKeyHandler *handler.APIKeyHandler, authMiddleware *middleware.AuthMiddleware) error {
+	if apiKeyHandler == nil {
+		return fmt.Errorf("APIKeyHandler is nil in SetUpAPIKeyRoutes")
+	}
+	if authMiddleware == nil {
+		return fmt.Errorf("AuthMiddleware is nil in SetUpAPIKeyRoutes")
+	}
+
+	// Keys are managed by signed-in users only; a key cannot create or revoke keys.
+	apiKeyGroup := apiGroup.Group("/api-keys", authMiddleware.RejectAPIKeys())
+	utils.Log.Info("Configuring /api/v1/api-keys protected routes.")
+
+	apiKeyGroup.Get("/", authMiddleware.AuthorizeMiddleware(model.PermAPIKeyManage), apiKeyHandler.HandleListAPIKeys)
+	apiKeyGroup.Post("/", authMiddleware.AuthorizeMiddleware(model.PermAPIKeyManage), apiKeyHandler.HandleCreateAPIKey)
+	apiKeyGroup.Delete("/:keyID", authMiddleware.AuthorizeMiddleware(model.PermAPIKeyManage), apiKeyHandler.HandleRevokeAPIKey)
+
+	utils.Log.Info("/api-keys routes configured with RBAC.")
+	return nil
+}
@@ -0,0 +1,29 @@
+package server
+
+import (
+	"fmt"
+	"gold-api/internal/api/handler"
+	"gold-api/internal/api/middleware"
+	"gold-api/internal/model"
+	"gold-api/internal/utils"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+func SetUpAuditRoutes(apiGroup fiber.Router, auditHandler *handler.AuditHandler, authMiddleware *middleware.AuthMiddleware) error {
+	if auditHandler == nil {
+		return fmt.Errorf("AuditHandler is nil in SetUpAuditRoutes")
+	}
+	if authMiddleware == nil {
+		return fmt.Errorf("AuthMiddleware is nil in SetUpAuditRoutes")
+	}
+
+	auditGroup := apiGroup.Group("/audit")
+	utils.Log.Info("Configuring /api/v1/audit protected routes.")
+
+	auditGroup.Get("/events", authMiddleware.AuthorizeMiddleware(model.PermAuditRead), auditHandler.HandleListEvents)
+	auditGroup.Get("/verify", authMiddleware.AuthorizeMiddleware(model.PermAuditRead), auditHandler.HandleVerifyChain)
+
+	utils.Log.Info("/audit routes configured with RBAC.")
+	return nil
+}
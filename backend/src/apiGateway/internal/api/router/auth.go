@@ -4,6 +4,7 @@ import (
 	"fmt"
 	"gold-api/internal/api/handler"
 	"gold-api/internal/api/middleware"
+	"gold-api/internal/model"
 	"gold-api/internal/utils"
 
 	"github.com/gofiber/fiber/v2"
@@ -25,6 +26,10 @@ func SetUpAuthRoutes(apiGroup fiber.Router, authHandler *handler.AuthHandler, au
 	authGroup.Post("/password/request-reset", authHandler.HandleRequestPasswordReset)
 	authGroup.Post("/password/reset", authHandler.HandleResetPassword)
 	authGroup.Post("/2fa/verify", authHandler.HandleLoginTwoFA) 
+	authGroup.Post("/otp/request", authHandler.HandleRequestLoginOTP)
+	authGroup.Post("/otp/verify", authHandler.HandleVerifyLoginOTP)
+	authGroup.Post("/refresh", authHandler.HandleRefresh)
+	authGroup.Post("/users/:userID/unlock", authMiddleware.AuthorizeMiddleware(model.PermUserChangeAnyPassword), authHandler.HandleUnlockUser)
 
 	authGroup.Post("/logout", authHandler.LogoutUser)
 
@@ -0,0 +1,34 @@
+package server
+
+import (
+	"fmt"
+	"gold-api/internal/api/handler"
+	"gold-api/internal/api/middleware"
+	"gold-api/internal/model"
+	"gold-api/internal/utils"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+func SetUpRBACRoutes(apiGroup fiber.Router, rbacHandler *handler.RBACHandler, authMiddleware *middleware.AuthMiddleware) error {
+	if rbacHandler == nil {
+		return fmt.Errorf("RBACHandler is nil in SetUpRBACRoutes")
+	}
+	if authMiddleware == nil {
+		return fmt.Errorf("AuthMiddleware is nil in SetUpRBACRoutes")
+	}
+
+	rbacGroup := apiGroup.Group("/rbac")
+	utils.Log.Info("Configuring /api/v1/rbac protected routes.")
+
+	rbacGroup.Get("/roles", authMiddleware.AuthorizeMiddleware(model.PermSystemSettingsRead), rbacHandler.HandleListRoles)
+	rbacGroup.Post("/roles", authMiddleware.AuthorizeMiddleware(model.PermSystemSettingsManage), rbacHandler.HandleCreateRole)
+	rbacGroup.Put("/roles/:name", authMiddleware.AuthorizeMiddleware(model.PermSystemSettingsManage), rbacHandler.HandleUpdateRole)
+	rbacGroup.Delete("/roles/:name", authMiddleware.AuthorizeMiddleware(model.PermSystemSettingsManage), rbacHandler.HandleDeleteRole)
+	rbacGroup.Get("/permissions", authMiddleware.AuthorizeMiddleware(model.PermSystemSettingsRead), rbacHandler.HandleListPermissions)
+	rbacGroup.Get("/users/:userID/permissions", authMiddleware.AuthorizeMiddleware(model.PermUserRead), rbacHandler.HandleGetUserPermissions)
+	rbacGroup.Put("/users/:userID/permissions", authMiddleware.AuthorizeMiddleware(model.PermSystemSettingsManage), rbacHandler.HandleSetUserPermissions)
+
+	utils.Log.Info("/rbac routes configured with RBAC.")
+	return nil
+}
@@ -7,6 +7,7 @@ import (
 	"gold-api/internal/api/middleware"
 	"gold-api/internal/api/proxy"
 	"gold-api/internal/model"
+	redisdb "gold-api/internal/repo/db/redisDb"
 	"gold-api/internal/utils"
 	"os"
 
@@ -23,6 +24,11 @@ func SetupAllRoutes(
 	profileHandlerAG *handler.ProfileHandler,
 	proxyHandler *proxy.ProxyHandler,
 	sliderHandler *handler.SliderHandler,
+	rbacHandler *handler.RBACHandler,
+	auditHandler *handler.AuditHandler,
+	revocationRepo redisdb.RevocationRepository,
+	apiKeyHandler *handler.APIKeyHandler,
+	apiKeyVerifier middleware.APIKeyVerifier,
 ) error {
 	if app == nil {
 		return fmt.Errorf("fiber app instance is nil in SetupAllRoutes")
@@ -48,13 +54,28 @@ func SetupAllRoutes(
 	if sliderHandler == nil {
 		return fmt.Errorf("SliderHandler is nil in SetupAllRoutes")
 	}
+	if rbacHandler == nil {
+		return fmt.Errorf("RBACHandler is nil in SetupAllRoutes")
+	}
+	if auditHandler == nil {
+		return fmt.Errorf("AuditHandler is nil in SetupAllRoutes")
+	}
+	if revocationRepo == nil {
+		return fmt.Errorf("RevocationRepository is nil in SetupAllRoutes")
+	}
+	if apiKeyHandler == nil {
+		return fmt.Errorf("APIKeyHandler is nil in SetupAllRoutes")
+	}
+	if apiKeyVerifier == nil {
+		return fmt.Errorf("APIKeyVerifier is nil in SetupAllRoutes")
+	}
 
 	apiV1 := app.Group("/api/v1")
 	utils.Log.Info("Base API group /api/v1 created.")
 
 	jwtValidator := utils.NewJWTValidatorImpl("JWT_SECRET_KEY", utils.Log)
 	
-	authMiddleware, err := middleware.NewAuthMiddleware(permissionService, utils.Log, jwtValidator)
+	authMiddleware, err := middleware.NewAuthMiddleware(permissionService, utils.Log, jwtValidator, revocationRepo, apiKeyVerifier)
 	if err != nil {
 		utils.Log.Error("Failed to initialize AuthMiddleware", zap.Error(err))
 		return fmt.Errorf("failed to initialize auth middleware: %w", err)
@@ -77,6 +98,18 @@ func SetupAllRoutes(
 		return fmt.Errorf("failed to set up slider routes: %w", err)
 	}
 
+	if err := SetUpRBACRoutes(apiV1, rbacHandler, authMiddleware); err != nil {
+		return fmt.Errorf("failed to set up RBAC routes: %w", err)
+	}
+
+	if err := SetUpAuditRoutes(apiV1, auditHandler, authMiddleware); err != nil {
+		return fmt.Errorf("failed to set up audit routes: %w", err)
+	}
+
+	if err := SetUpAPIKeyRoutes(apiV1, apiKeyHandler, authMiddleware); err != nil {
+		return fmt.Errorf("failed to set up API key routes: %w", err)
+	}
+
 	// Proxy routes
 	profileManagerServiceURL := os.Getenv("PROFILE_MANAGER_BASE_URL")
 	if profileManagerServiceURL != "" {
@@ -1,11 +1,15 @@
 package server
 
 import (
+	"common-gold/audit"
+	commonauthz "common-gold/authz"
+	"context"
 	"fmt"
 	"gold-api/internal/api/authz"
 	"gold-api/internal/api/handler"
 	"gold-api/internal/api/middleware"
 	"gold-api/internal/api/proxy"
+	redisdb "gold-api/internal/repo/db/redisDb"
 	"gold-api/internal/service/auth"
 	"gold-api/internal/service/crm"
 	crmmanager "gold-api/internal/service/crmManager"
@@ -13,6 +17,8 @@ import (
 	"gold-api/internal/utils"
 	"log"
 	"os"
+	"strconv"
+	"time"
 
 	"github.com/gofiber/fiber/v2"
 	"go.uber.org/zap"
@@ -26,6 +32,7 @@ func StartServer(port string) {
 
 	app.Use(middleware.CorsMiddleware())
 	utils.Log.Info("CORS middleware applied.")
+	app.Use(middleware.AuditMeta())
 
 	profileManagerBaseURL := os.Getenv("PROFILE_MANAGER_BASE_URL")
 	if profileManagerBaseURL == "" {
@@ -45,19 +52,47 @@ func StartServer(port string) {
 		utils.Log.Fatal("Failed to initialize ProfileManagerClient.", zap.Error(err))
 	}
 
-	permissionService := authz.NewPermissionService(utils.Log)
-	if permissionService == nil {
-		utils.Log.Fatal("ERROR: Failed to initialize PermissionService. Exiting application.")
+	redisClient, err := redisdb.InitRedisClient()
+	if err != nil {
+		utils.Log.Fatal("Failed to initialize Redis client. Exiting application.", zap.Error(err))
+	}
+	revocationRepo, err := redisdb.NewRedisRevocationRepository(redisClient)
+	if err != nil {
+		utils.Log.Fatal("Failed to initialize RevocationRepository. Exiting application.", zap.Error(err))
+	}
+
+	policySource, err := commonauthz.NewHTTPSource(profileManagerBaseURL, os.Getenv("PROFILE_MANAGER_SERVICE_SECRET"))
+	if err != nil {
+		utils.Log.Fatal("Failed to initialize authorization policy source. Exiting application.", zap.Error(err))
+	}
+	policyCache, err := commonauthz.NewCache(policySource)
+	if err != nil {
+		utils.Log.Fatal("Failed to initialize authorization policy cache. Exiting application.", zap.Error(err))
+	}
+	// Profile Manager may start after the gateway; until the first fetch succeeds protected routes answer 503.
+	go policyCache.Run(context.Background(), policyRefreshInterval(), func(err error) {
+		utils.Log.Warn("Failed to refresh authorization policy", zap.Error(err))
+	})
+
+	permissionService, err := authz.NewPermissionService(utils.Log, policyCache)
+	if err != nil {
+		utils.Log.Fatal("Failed to initialize PermissionService. Exiting application.", zap.Error(err))
 	}
 	utils.Log.Info("PermissionService initialized successfully.")
 
+	// Gateway events (logins, RBAC changes) are stored in CRM Manager's audit log.
+	auditSink, err := audit.NewHTTPSink(crmManagerBaseURL, os.Getenv("CRM_MANAGER_SERVICE_SECRET"))
+	if err != nil {
+		utils.Log.Fatal("Failed to initialize audit sink. Exiting application.", zap.Error(err))
+	}
+
 	authSvc, err := auth.NewAuthService(profileManagerClient)
 	if err != nil {
 		utils.Log.Fatal("Failed to initialize AuthService. Exiting application.", zap.Error(err))
 	}
 	utils.Log.Info("AuthService initialized successfully.")
 
-	authHandler, err := handler.NewAuthHandler(authSvc)
+	authHandler, err := handler.NewAuthHandler(authSvc, auditSink)
 	if err != nil {
 		utils.Log.Fatal("Failed to initialize AuthHandler. Exiting application.", zap.Error(err))
 	}
@@ -96,6 +131,24 @@ func StartServer(port string) {
 	}
 	utils.Log.Info("SliderHandler initialized successfully.")
 
+	rbacHandler, err := handler.NewRBACHandler(profileManagerClient, permissionService, auditSink)
+	if err != nil {
+		utils.Log.Fatal("Failed to initialize RBACHandler. Exiting application.", zap.Error(err))
+	}
+	utils.Log.Info("RBACHandler initialized successfully.")
+
+	auditHandler, err := handler.NewAuditHandler(crmManagerClient)
+	if err != nil {
+		utils.Log.Fatal("Failed to initialize AuditHandler. Exiting application.", zap.Error(err))
+	}
+	utils.Log.Info("AuditHandler initialized successfully.")
+
+	apiKeyHandler, err := handler.NewAPIKeyHandler(profileManagerClient, auditSink)
+	if err != nil {
+		utils.Log.Fatal("Failed to initialize APIKeyHandler. Exiting application.", zap.Error(err))
+	}
+	utils.Log.Info("APIKeyHandler initialized successfully.")
+
 	utils.Log.Info("All core dependencies initialized successfully.")
 	utils.Log.Info("Setting up API routes for API Gateway...")
 
@@ -108,6 +161,11 @@ func StartServer(port string) {
 		profileHandlerAG,
 		proxyHandler,
 		sliderHandler,
+		rbacHandler,
+		auditHandler,
+		revocationRepo,
+		apiKeyHandler,
+		profileManagerClient,
 	); err != nil {
 		utils.Log.Fatal("ERROR: Failed to set up API routes: %v. Exiting application.", zap.Error(err))
 	}
@@ -116,4 +174,16 @@ func StartServer(port string) {
 	fullAddr := fmt.Sprintf("0.0.0.0%s", port)
 	utils.Log.Info("API Gateway is attempting to listen", zap.String("address", fullAddr))
 	log.Fatal(app.Listen(port))
-}
\ No newline at end of file
+}
+
+// policyRefreshInterval how often the cached authorization policy is checked for a newer version (AUTHZ_POLICY_REFRESH_SECONDS).
+func policyRefreshInterval() time.Duration {
+	if v := os.Getenv("AUTHZ_POLICY_REFRESH_SECONDS"); v != "" {
+		seconds, err := strconv.Atoi(v)
+		if err != nil || seconds <= 0 {
+			utils.Log.Fatal("AUTHZ_POLICY_REFRESH_SECONDS must be a positive integer", zap.String("value", v))
+		}
+		return time.Duration(seconds) * time.Second
+	}
+	return 30 * time.Second
+}
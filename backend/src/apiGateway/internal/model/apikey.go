@@ -0,0 +1,58 @@
+package model
+
+import "time"
+
+// APIKeyPrefix starts every API key, so the gateway can tell a key from a JWT in the Authorization header.
+const APIKeyPrefix = "gk_"
+
+type APIKey struct {
+	ID          string     `json:"id"`
+	Name        string     `json:"name"`
+	Prefix      string     `json:"prefix"`
+	UserID      string     `json:"user_id"`
+	Permissions []string   `json:"permissions"`
+	BusinessID  *uint      `json:"business_id,omitempty"`
+	ExpiresAt   *time.Time `json:"expires_at,omitempty"`
+	LastUsedAt  *time.Time `json:"last_used_at,omitempty"`
+	LastUsedIP  string     `json:"last_used_ip,omitempty"`
+	CreatedAt   time.Time  `json:"created_at"`
+	RevokedAt   *time.Time `json:"revoked_at,omitempty"`
+}
+
+// CreateAPIKeyRequest is the body of POST /api-keys. UserID is always set by the gateway to the caller.
+type CreateAPIKeyRequest struct {
+	UserID      string     `json:"user_id"`
+	Name        string     `json:"name"`
+	Permissions []string   `json:"permissions"`
+	BusinessID  *uint      `json:"business_id,omitempty"`
+	ExpiresAt   *time.Time `json:"expires_at,omitempty"`
+}
+
+type CreateAPIKeyResponse struct {
+	Message string `json:"message"`
+	Key     string `json:"key"`
+	APIKey  APIKey `json:"api_key"`
+}
+
+type APIKeysResponse struct {
+	APIKeys []APIKey `json:"api_keys"`
+}
+
+type VerifyAPIKeyRequest struct {
+	Key string `json:"key"`
+}
+
+// APIKeyPrincipal is Profile Manager's answer for a valid key. Token is a short-lived access token of the key's
+// owner, forwarded to downstream services in place of the key.
+type APIKeyPrincipal struct {
+	KeyID       string     `json:"key_id"`
+	Name        string     `json:"name"`
+	UserID      string     `json:"user_id"`
+	Username    string     `json:"username"`
+	Roles       []string   `json:"roles"`
+	Permissions []string   `json:"permissions"`
+	BusinessID  *uint      `json:"business_id,omitempty"`
+	ExpiresAt   *time.Time `json:"expires_at,omitempty"`
+	Token       string     `json:"token"`
+	TokenExp    int64      `json:"token_exp"`
+}
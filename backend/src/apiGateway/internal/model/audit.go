@@ -0,0 +1,42 @@
+package model
+
+import (
+	"encoding/json"
+	"time"
+)
+
+type AuditLog struct {
+	ID            uint            `json:"id"`
+	Seq           uint64          `json:"seq"`
+	BIDID         *uint           `json:"bidId,omitempty"`
+	Action        string          `json:"action"`
+	Category      string          `json:"category"`
+	ActorID       string          `json:"actorId"`
+	ActorName     string          `json:"actorName"`
+	IPAddress     string          `json:"ipAddress"`
+	UserAgent     string          `json:"userAgent"`
+	CorrelationID string          `json:"correlationId"`
+	TargetType    string          `json:"targetType"`
+	TargetID      string          `json:"targetId"`
+	Description   string          `json:"description"`
+	Before        json.RawMessage `json:"before,omitempty"`
+	After         json.RawMessage `json:"after,omitempty"`
+	OccurredAt    time.Time       `json:"occurredAt"`
+	PrevHash      string          `json:"prevHash"`
+	Hash          string          `json:"hash"`
+}
+
+type AuditLogPage struct {
+	Items    []AuditLog `json:"items"`
+	Total    int64      `json:"total"`
+	Page     int        `json:"page"`
+	PageSize int        `json:"pageSize"`
+}
+
+type AuditChainReport struct {
+	Valid       bool    `json:"valid"`
+	Checked     int64   `json:"checked"`
+	LastSeq     uint64  `json:"lastSeq"`
+	BrokenAtSeq *uint64 `json:"brokenAtSeq,omitempty"`
+	Reason      string  `json:"reason,omitempty"`
+}
@@ -7,8 +7,9 @@ import (
 )
 
 type LoginRequest struct {
-	Username string `json:"username"`
-	Password string `json:"password"`
+	Username   string `json:"username"`
+	Password   string `json:"password"`
+	DeviceName string `json:"device_name,omitempty"`
 }
 
 type RegisterRequest struct {
@@ -25,13 +26,95 @@ type User struct {
 	Roles        datatypes.JSON `json:"roles" gorm:"type:jsonb"`
 	CreatedAt    time.Time      `json:"created_at"`
 	UpdatedAt    time.Time      `json:"updated_at"`
+	// Mobile is only present once the user has verified it; it can then be used for SMS login.
+	Mobile           *string    `json:"mobile,omitempty" gorm:"-"`
+	MobileVerifiedAt *time.Time `json:"mobile_verified_at,omitempty" gorm:"-"`
 }
 
 type AuthResponse struct {
+	Message        string `json:"message"`
+	Token          string `json:"token,omitempty"`
+	User           *User  `json:"user,omitempty"`
+	Exp            int64  `json:"exp,omitempty"`
+	TwoFARequired  bool   `json:"two_fa_required,omitempty"`
+	ChallengeToken string `json:"challenge_token,omitempty"`
+	RefreshToken   string `json:"refresh_token,omitempty"`
+	RefreshExp     int64  `json:"refresh_exp,omitempty"`
+	SessionID      string `json:"session_id,omitempty"`
+}
+
+// TwoFAChallenge is returned by login instead of a session when the user has 2FA enabled.
+type TwoFAChallenge struct {
+	Token     string
+	ExpiresAt int64
+}
+
+// LoginResult holds either a session (Token, RefreshToken and Claims) or a pending 2FA Challenge.
+type LoginResult struct {
+	User             *User
+	Token            string
+	Claims           *CustomClaims
+	RefreshToken     string
+	RefreshExpiresAt int64
+	Challenge        *TwoFAChallenge
+}
+
+type TwoFALoginRequest struct {
+	ChallengeToken string `json:"challenge_token"`
+	Code           string `json:"code"`
+	DeviceName     string `json:"device_name,omitempty"`
+}
+
+// OTPRequest asks for an SMS code, either to log in or to verify a new mobile number.
+type OTPRequest struct {
+	Mobile string `json:"mobile"`
+}
+
+type OTPLoginRequest struct {
+	Mobile     string `json:"mobile"`
+	Code       string `json:"code"`
+	DeviceName string `json:"device_name,omitempty"`
+}
+
+type VerifyMobileRequest struct {
+	Code string `json:"code"`
+}
+
+type MobileResponse struct {
+	Message          string     `json:"message"`
+	Mobile           string     `json:"mobile,omitempty"`
+	MobileVerifiedAt *time.Time `json:"mobile_verified_at,omitempty"`
+}
+
+type RefreshTokenRequest struct {
+	RefreshToken string `json:"refresh_token"`
+}
+
+// ClientInfo is the end user's address and browser, forwarded to Profile Manager for the session list.
+type ClientInfo struct {
+	IPAddress  string
+	UserAgent  string
+	DeviceName string
+}
+
+type Session struct {
+	ID         string    `json:"id"`
+	DeviceName string    `json:"device_name"`
+	IPAddress  string    `json:"ip_address"`
+	UserAgent  string    `json:"user_agent"`
+	CreatedAt  time.Time `json:"created_at"`
+	LastUsedAt time.Time `json:"last_used_at"`
+	ExpiresAt  time.Time `json:"expires_at"`
+	Current    bool      `json:"current"`
+}
+
+type SessionsResponse struct {
+	Sessions []Session `json:"sessions"`
+}
+
+type RevokeSessionsResponse struct {
 	Message string `json:"message"`
-	Token   string `json:"token,omitempty"`
-	User    *User  `json:"user,omitempty"`
-	Exp     int64  `json:"exp,omitempty"`
+	Revoked int    `json:"revoked"`
 }
 type VerifyTwoFARequest struct {
 	Username string `json:"username"`
@@ -40,8 +123,24 @@ type VerifyTwoFARequest struct {
 }
 
 type DisableTwoFARequest struct {
-	Password string `json:"password"` // User's password to confirm disabling 2FA
-	Code     string `json:"code"`     // Current 2FA code to confirm
+	Password string `json:"current_password"` // User's password to confirm disabling 2FA
+	Code     string `json:"totp_code"`        // Current 2FA code (or a recovery code) to confirm
+}
+
+type EnableTwoFARequest struct {
+	Code string `json:"totp_code"`
+}
+
+type TwoFASetupResponse struct {
+	Secret     string `json:"secret"`
+	OTPAuthURL string `json:"otpauth_url"`
+	QRCodePNG  string `json:"qr_code_png"` // data:image/png;base64,...
+}
+
+type RecoveryCodesResponse struct {
+	Message       string   `json:"message,omitempty"`
+	RecoveryCodes []string `json:"recovery_codes,omitempty"`
+	Remaining     int64    `json:"remaining"`
 }
 
 type ChangeUsernameRequest struct {
@@ -57,6 +156,6 @@ type RequestPasswordReset struct {
 	Email string `json:"email"`
 }
 type ResetPasswordRequest struct {
-	Token string `json:"reset_token"` // Token sent to the user's email
+	Token string `json:"token"` // Token sent to the user's email
 	NewPassword string `json:"new_password"` // New password to set
 }
@@ -3,6 +3,8 @@ package model
 import (
 	"time"
 
+	"common-gold/money"
+
 	"gorm.io/gorm"
 )
 
@@ -42,12 +44,14 @@ type Customer struct {
 	LastActivityDate *time.Time `json:"lastActivityDate,omitempty"`
 	InternalNotes    *string    `json:"internalNotes,omitempty" gorm:"type:text"`
 
-	InitialBalanceToman float64 `json:"initialBalanceToman" gorm:"default:0"`
-	InitialBalanceGold  float64 `json:"initialBalanceGold" gorm:"default:0"`
+	// crmManager omits these fields for users without crm:view_customer_balance; pointers keep them
+	// absent here too instead of being re-encoded as a zero balance.
+	InitialBalanceToman *money.Amount `json:"initialBalanceToman,omitempty" gorm:"not null;default:0"`
+	InitialBalanceGold  *money.Weight `json:"initialBalanceGold,omitempty" gorm:"not null;default:0"`
 
-	GoldRateType        *string  `json:"goldRateType,omitempty" gorm:"size:50"`
-	DefaultGoldUnit     *string  `json:"defaultGoldUnit,omitempty" gorm:"size:50"`
-	DefaultGoldUnitRate *float64 `json:"defaultGoldUnitRate,omitempty"`
+	GoldRateType        *string       `json:"goldRateType,omitempty" gorm:"size:50"`
+	DefaultGoldUnit     *string       `json:"defaultGoldUnit,omitempty" gorm:"size:50"`
+	DefaultGoldUnitRate *money.Amount `json:"defaultGoldUnitRate,omitempty"`
 
 	BankAccounts      []CusCard `gorm:"foreignKey:PersonID"`
 	CustomerTypes     []CusType `gorm:"many2many:customer_customer_types;"`
@@ -130,11 +134,11 @@ type CreateCustomerRequest struct {
 	Sabt          string `json:"sabt"`
 	TaxID         string `json:"taxID"`
 
-	InitialBalanceToman float64 `json:"initialBalanceToman"`
-	InitialBalanceGold  float64 `json:"initialBalanceGold"`
+	InitialBalanceToman money.Amount `json:"initialBalanceToman"`
+	InitialBalanceGold  money.Weight `json:"initialBalanceGold"`
 
-	GoldRateType        string  `json:"goldRateType"`
-	DefaultGoldUnit     string  `json:"defaultGoldUnit"`
-	DefaultGoldUnitRate float64 `json:"defaultGoldUnitRate"`
-	CustomerCategory    string  `json:"customerCategory"`
+	GoldRateType        string       `json:"goldRateType"`
+	DefaultGoldUnit     string       `json:"defaultGoldUnit"`
+	DefaultGoldUnitRate money.Amount `json:"defaultGoldUnitRate"`
+	CustomerCategory    string       `json:"customerCategory"`
 }
@@ -12,9 +12,10 @@ type ErrorResponse struct {
 }
 
 type CustomClaims struct {
-	UserID   string         `json:"user_id"`
-	Username string         `json:"username"`
-	Roles    datatypes.JSON `json:"roles"`
+	UserID    string         `json:"user_id"`
+	Username  string         `json:"username"`
+	Roles     datatypes.JSON `json:"roles"`
+	SessionID string         `json:"sid,omitempty"`
 	jwt.RegisteredClaims
 }
 
@@ -20,6 +20,7 @@ const (
 	PermInventoryUpdateGoldPrice = "inventory:update_gold_price"
 
 	PermCRMReadCustomer        = "crm:read_customer"
+	PermCRMReadAllCustomers    = "crm:read_all_customers"
 	PermCRMCreateCustomer      = "crm:create_customer"
 	PermCRMUpdateCustomer      = "crm:update_customer"
 	PermCRMDeleteCustomer      = "crm:delete_customer"
@@ -43,6 +44,7 @@ const (
 	PermTransactionManageCheques         = "transaction:manage_cheques"
 
 	PermReportViewSalesSummary     = "report:view_sales_summary"
+	PermReportViewAllSales         = "report:view_all_sales"
 	PermReportViewInventorySummary = "report:view_inventory_summary"
 	PermReportViewProfitLoss       = "report:view_profit_loss"
 	PermReportViewBalances         = "report:view_balances"
@@ -56,4 +58,6 @@ const (
 	PermUserChangeAnyPassword = "user:change_any_password"
 	PermSystemSettingsRead    = "system:settings_read"
 	PermSystemSettingsManage  = "system:settings_manage"
+	PermAuditRead             = "audit:read"
+	PermAPIKeyManage          = "apikey:manage"
 )
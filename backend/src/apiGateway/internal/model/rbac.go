@@ -0,0 +1,46 @@
+package model
+
+import "time"
+
+type Role struct {
+	ID          string    `json:"id"`
+	Name        string    `json:"name"`
+	Description string    `json:"description"`
+	IsSystem    bool      `json:"is_system"`
+	Permissions []string  `json:"permissions"`
+	CreatedAt   time.Time `json:"created_at"`
+	UpdatedAt   time.Time `json:"updated_at"`
+}
+
+type Permission struct {
+	ID          string `json:"id"`
+	Name        string `json:"name"`
+	Description string `json:"description"`
+}
+
+type RoleRequest struct {
+	Name        string   `json:"name"`
+	Description string   `json:"description"`
+	Permissions []string `json:"permissions"`
+}
+
+type RolesResponse struct {
+	Roles []Role `json:"roles"`
+}
+
+type PermissionsResponse struct {
+	Permissions []Permission `json:"permissions"`
+}
+
+type UserPermissionsRequest struct {
+	Grant []string `json:"grant"`
+	Deny  []string `json:"deny"`
+}
+
+type UserPermissionsResponse struct {
+	UserID    string   `json:"user_id"`
+	Roles     []string `json:"roles"`
+	Grant     []string `json:"grant"`
+	Deny      []string `json:"deny"`
+	Effective []string `json:"effective"`
+}
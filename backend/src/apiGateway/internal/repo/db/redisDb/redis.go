@@ -0,0 +1,48 @@
+package redisdb
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"time"
+
+	"gold-api/internal/utils"
+
+	"github.com/go-redis/redis/v8"
+	"go.uber.org/zap"
+)
+
+// InitRedisClient connects to the Redis instance Profile Manager writes token and session revocations to.
+func InitRedisClient() (*redis.Client, error) {
+	redisHost := os.Getenv("REDIS_HOST")
+	redisPort := os.Getenv("REDIS_PORT")
+	redisDbStr := os.Getenv("REDIS_DB")
+	if redisHost == "" || redisPort == "" {
+		return nil, fmt.Errorf("REDIS_HOST or REDIS_PORT environment variables are not set")
+	}
+
+	dbIndex := 0
+	if redisDbStr != "" {
+		if _, err := fmt.Sscanf(redisDbStr, "%d", &dbIndex); err != nil {
+			utils.Log.Warn("Failed to parse REDIS_DB, defaulting to 0", zap.String("redis_db_env", redisDbStr), zap.Error(err))
+		}
+	}
+
+	redisAddr := fmt.Sprintf("%s:%s", redisHost, redisPort)
+	client := redis.NewClient(&redis.Options{
+		Addr:        redisAddr,
+		Password:    os.Getenv("REDIS_PASSWORD"),
+		DB:          dbIndex,
+		PoolSize:    10,
+		PoolTimeout: 30 * time.Second,
+		IdleTimeout: 5 * time.Minute,
+	})
+
+	ctx, cancel := context.WithTimeout(context.Background(), 15*time.Second)
+	defer cancel()
+	if err := client.Ping(ctx).Err(); err != nil {
+		return nil, fmt.Errorf("failed to connect to redis at %s: %w", redisAddr, err)
+	}
+	utils.Log.Info("Redis client connected successfully.", zap.String("address", redisAddr), zap.Int("db_index", dbIndex))
+	return client, nil
+}
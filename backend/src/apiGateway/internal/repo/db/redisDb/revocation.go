@@ -0,0 +1,73 @@
+package redisdb
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"gold-api/internal/utils"
+
+	"github.com/go-redis/redis/v8"
+)
+
+// RevocationRepository reads the revocation markers Profile Manager sets on logout, session sign-out and
+// password reset. The key formats must stay in sync with Profile Manager's TokenRepository.
+type RevocationRepository interface {
+	IsTokenBlacklisted(token string) (bool, error)
+	IsSessionRevoked(sessionID string) (bool, error)
+	// IsUserTokenRevoked reports whether tokens issued at issuedAt were revoked for the whole user. The marker holds
+	// the revocation time in Unix milliseconds; tokens issued strictly before it are revoked.
+	IsUserTokenRevoked(userID string, issuedAt time.Time) (bool, error)
+	// IsAPIKeyRevoked lets a revoked key stop working before the gateway's cached verification expires.
+	IsAPIKeyRevoked(keyID string) (bool, error)
+}
+
+type redisRevocationRepository struct {
+	client *redis.Client
+}
+
+func NewRedisRevocationRepository(client *redis.Client) (RevocationRepository, error) {
+	if client == nil {
+		return nil, fmt.Errorf("redis client cannot be nil for RevocationRepository")
+	}
+	utils.Log.Info("RevocationRepository initialized successfully.")
+	return &redisRevocationRepository{client: client}, nil
+}
+
+func (r *redisRevocationRepository) IsTokenBlacklisted(token string) (bool, error) {
+	return r.exists(fmt.Sprintf("jwt_blacklist:%s", token))
+}
+
+func (r *redisRevocationRepository) IsSessionRevoked(sessionID string) (bool, error) {
+	return r.exists(fmt.Sprintf("session_revoked:%s", sessionID))
+}
+
+func (r *redisRevocationRepository) IsAPIKeyRevoked(keyID string) (bool, error) {
+	return r.exists(fmt.Sprintf("api_key_revoked:%s", keyID))
+}
+
+func (r *redisRevocationRepository) IsUserTokenRevoked(userID string, issuedAt time.Time) (bool, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	before, err := r.client.Get(ctx, fmt.Sprintf("jwt_revoked_before:%s", userID)).Int64()
+	if errors.Is(err, redis.Nil) {
+		return false, nil
+	}
+	if err != nil {
+		return false, fmt.Errorf("failed to check user token revocation: %w", err)
+	}
+	return issuedAt.UnixMilli() < before, nil
+}
+
+func (r *redisRevocationRepository) exists(key string) (bool, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	n, err := r.client.Exists(ctx, key).Result()
+	if err != nil {
+		return false, fmt.Errorf("failed to check revocation key: %w", err)
+	}
+	return n == 1, nil
+}
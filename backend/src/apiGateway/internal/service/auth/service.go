@@ -13,12 +13,16 @@ import (
 )
 
 type AuthService interface {
-	LoginUser(username, password string) (*model.User, string, *model.CustomClaims, error)
+	LoginUser(username, password string, client model.ClientInfo) (*model.LoginResult, error)
 	RegisterUser(req model.RegisterRequest) error
 	LogoutUser(token string) error
-	RequestPasswordReset(email string) error
+	RequestPasswordReset(email string, client model.ClientInfo) error
 	ResetPassword(token, newPassword string) error
-	VerifyTwoFACode(username, code string) (*model.User, string, *model.CustomClaims, error)
+	VerifyTwoFACode(challengeToken, code string, client model.ClientInfo) (*model.LoginResult, error)
+	RefreshSession(refreshToken string, client model.ClientInfo) (*model.LoginResult, error)
+	UnlockUser(userID string) error
+	RequestLoginOTP(mobile string, client model.ClientInfo) error
+	VerifyLoginOTP(mobile, code string, client model.ClientInfo) (*model.LoginResult, error)
 }
 type AuthServiceImpl struct {
 	profileMgrClient profilemanager.ProfileManagerClient
@@ -34,26 +38,34 @@ func NewAuthService(client profilemanager.ProfileManagerClient) (AuthService, er
 	return &AuthServiceImpl{profileMgrClient: client}, nil
 }
 
-func (s *AuthServiceImpl) LoginUser(username, password string) (*model.User, string, *model.CustomClaims, error) {
+func (s *AuthServiceImpl) LoginUser(username, password string, client model.ClientInfo) (*model.LoginResult, error) {
 
-	user, token, claims, err := s.profileMgrClient.AuthenticateUser(username, password)
+	result, err := s.profileMgrClient.AuthenticateUser(username, password, client)
 
 	if err != nil {
 		utils.Log.Error("Authentication failed in ProfileManagerClient", zap.String("username", username), zap.Error(err))
 		if errors.Is(err, service.ErrInvalidCredentials) {
-			return nil, "", nil, service.ErrInvalidCredentials
+			return nil, service.ErrInvalidCredentials
+		}
+		if errors.Is(err, service.ErrAccountLocked) {
+			return nil, err
 		}
 		if errors.Is(err, service.ErrProfileManagerDown) {
-			return nil, "", nil, service.ErrProfileManagerDown
+			return nil, service.ErrProfileManagerDown
 		}
-		return nil, "", nil, fmt.Errorf("%w: failed to authenticate user with profile manager", service.ErrInternalService)
+		return nil, fmt.Errorf("%w: failed to authenticate user with profile manager", service.ErrInternalService)
+	}
+
+	if result.Challenge != nil {
+		utils.Log.Info("Profile Manager requires 2FA to complete login", zap.String("username", username))
+		return result, nil
 	}
 
 	utils.Log.Info("User authenticated successfully by Profile Manager",
-		zap.String("username", user.Username),
-		zap.Any("roles", user.Roles),
+		zap.String("username", result.User.Username),
+		zap.Any("roles", result.User.Roles),
 	)
-	return user, token, claims, nil
+	return result, nil
 }
 
 func (s *AuthServiceImpl) RegisterUser(req model.RegisterRequest) error {
@@ -63,6 +75,9 @@ func (s *AuthServiceImpl) RegisterUser(req model.RegisterRequest) error {
 		if errors.Is(err, service.ErrUserAlreadyExists) {
 			return service.ErrUserAlreadyExists
 		}
+		if errors.Is(err, service.ErrWeakPassword) {
+			return err
+		}
 		if errors.Is(err, service.ErrProfileManagerDown) {
 			return service.ErrProfileManagerDown
 		}
@@ -72,11 +87,11 @@ func (s *AuthServiceImpl) RegisterUser(req model.RegisterRequest) error {
 	return nil
 }
 
-func (s *AuthServiceImpl) RequestPasswordReset(email string) error {
-	err := s.profileMgrClient.RequestPasswordReset(email)
+func (s *AuthServiceImpl) RequestPasswordReset(email string, client model.ClientInfo) error {
+	err := s.profileMgrClient.RequestPasswordReset(email, client)
 	if err != nil {
-		if errors.Is(err, service.ErrUserNotFound) {
-			return service.ErrUserNotFound
+		if errors.Is(err, service.ErrAccountLocked) {
+			return err
 		}
 		if errors.Is(err, service.ErrProfileManagerDown) {
 			return service.ErrProfileManagerDown
@@ -92,6 +107,9 @@ func (s *AuthServiceImpl) ResetPassword(token, newPassword string) error {
 		if errors.Is(err, service.ErrInvalidToken) {
 			return service.ErrInvalidToken
 		}
+		if errors.Is(err, service.ErrWeakPassword) {
+			return err
+		}
 		if errors.Is(err, service.ErrProfileManagerDown) {
 			return service.ErrProfileManagerDown
 		}
@@ -100,18 +118,23 @@ func (s *AuthServiceImpl) ResetPassword(token, newPassword string) error {
 	return nil
 }
 
-func (s *AuthServiceImpl) LoginTwoFA(username, code string) (*model.User, string, *model.CustomClaims, error) {
-	user, token, claims, err := s.profileMgrClient.VerifyTwoFACode(username, code)
+func (s *AuthServiceImpl) RefreshSession(refreshToken string, client model.ClientInfo) (*model.LoginResult, error) {
+	result, err := s.profileMgrClient.RefreshSession(refreshToken, client)
 	if err != nil {
-		if errors.Is(err, service.ErrInvalidTwoFACode) {
-			return nil, "", nil, service.ErrInvalidTwoFACode
+		if errors.Is(err, service.ErrRefreshTokenReused) {
+			utils.Log.Warn("Refresh token reuse reported by Profile Manager; session revoked", zap.Error(err))
+			return nil, service.ErrRefreshTokenReused
+		}
+		if errors.Is(err, service.ErrInvalidToken) {
+			return nil, service.ErrInvalidToken
 		}
 		if errors.Is(err, service.ErrProfileManagerDown) {
-			return nil, "", nil, service.ErrProfileManagerDown
+			return nil, service.ErrProfileManagerDown
 		}
-		return nil, "", nil, fmt.Errorf("%w: failed to perform 2FA login via profile manager", service.ErrInternalService)
+		utils.Log.Error("Token refresh failed in ProfileManagerClient", zap.Error(err))
+		return nil, fmt.Errorf("%w: failed to refresh session via profile manager", service.ErrInternalService)
 	}
-	return user, token, claims, nil
+	return result, nil
 }
 
 func (s *AuthServiceImpl) LogoutUser(token string) error {
@@ -133,32 +156,74 @@ func (s *AuthServiceImpl) LogoutUser(token string) error {
 	return nil
 }
 
-func (s *AuthServiceImpl) VerifyTwoFACode(username, code string) (*model.User, string, *model.CustomClaims, error) {
-
-	req := model.VerifyTwoFARequest{
-		Username: username,
-		Code:     code,
-	}
-
-	user, token, claims, err := s.profileMgrClient.VerifyTwoFACode(req.Username, req.Code)
-
+func (s *AuthServiceImpl) VerifyTwoFACode(challengeToken, code string, client model.ClientInfo) (*model.LoginResult, error) {
+	result, err := s.profileMgrClient.VerifyTwoFACode(challengeToken, code, client)
 	if err != nil {
-		utils.Log.Error("2FA verification failed in ProfileManagerClient",
-			zap.String("username", username),
-			zap.Error(err),
-		)
+		utils.Log.Error("2FA verification failed in ProfileManagerClient", zap.Error(err))
 
 		if errors.Is(err, service.ErrInvalidTwoFACode) {
-			return nil, "", nil, service.ErrInvalidTwoFACode
+			return nil, service.ErrInvalidTwoFACode
+		}
+		if errors.Is(err, service.ErrInvalidToken) {
+			return nil, service.ErrInvalidToken
+		}
+		if errors.Is(err, service.ErrTooManyAttempts) {
+			return nil, service.ErrTooManyAttempts
+		}
+		if errors.Is(err, service.ErrAccountLocked) {
+			return nil, err
 		}
 		if errors.Is(err, service.ErrProfileManagerDown) {
-			return nil, "", nil, service.ErrProfileManagerDown
+			return nil, service.ErrProfileManagerDown
 		}
-		return nil, "", nil, fmt.Errorf("%w: failed to verify 2FA code with profile manager", service.ErrInternalService)
+		return nil, fmt.Errorf("%w: failed to verify 2FA code with profile manager", service.ErrInternalService)
 	}
 
 	utils.Log.Info("User 2FA verified successfully by Profile Manager",
-		zap.String("username", user.Username),
+		zap.String("username", result.User.Username),
 	)
-	return user, token, claims, nil
+	return result, nil
+}
+
+func (s *AuthServiceImpl) UnlockUser(userID string) error {
+	err := s.profileMgrClient.UnlockUser(userID)
+	if err != nil {
+		if errors.Is(err, service.ErrUserNotFound) {
+			return service.ErrUserNotFound
+		}
+		if errors.Is(err, service.ErrProfileManagerDown) {
+			return service.ErrProfileManagerDown
+		}
+		utils.Log.Error("Unlock user failed in ProfileManagerClient", zap.String("user_id", userID), zap.Error(err))
+		return fmt.Errorf("%w: failed to unlock user via profile manager", service.ErrInternalService)
+	}
+	utils.Log.Info("User unlocked by Profile Manager", zap.String("user_id", userID))
+	return nil
+}
+
+// RequestLoginOTP passes Profile Manager's reasons on unchanged; they are the same for known and unknown numbers.
+func (s *AuthServiceImpl) RequestLoginOTP(mobile string, client model.ClientInfo) error {
+	err := s.profileMgrClient.RequestLoginOTP(mobile, client)
+	if err != nil {
+		if errors.Is(err, service.ErrAccountLocked) || errors.Is(err, service.ErrInvalidMobile) || errors.Is(err, service.ErrProfileManagerDown) {
+			return err
+		}
+		utils.Log.Error("SMS login code request failed in ProfileManagerClient", zap.Error(err))
+		return fmt.Errorf("%w: failed to request SMS login code from profile manager", service.ErrInternalService)
+	}
+	return nil
+}
+
+func (s *AuthServiceImpl) VerifyLoginOTP(mobile, code string, client model.ClientInfo) (*model.LoginResult, error) {
+	result, err := s.profileMgrClient.VerifyLoginOTP(mobile, code, client)
+	if err != nil {
+		switch {
+		case errors.Is(err, service.ErrInvalidOTP), errors.Is(err, service.ErrTooManyAttempts), errors.Is(err, service.ErrAccountLocked),
+			errors.Is(err, service.ErrInvalidMobile), errors.Is(err, service.ErrProfileManagerDown):
+			return nil, err
+		}
+		utils.Log.Error("SMS login failed in ProfileManagerClient", zap.Error(err))
+		return nil, fmt.Errorf("%w: failed to verify SMS login code with profile manager", service.ErrInternalService)
+	}
+	return result, nil
 }
@@ -1,6 +1,10 @@
 package service
 
-import "errors"
+import (
+	"errors"
+	"fmt"
+	"time"
+)
 
 var (
 	ErrInvalidCredentials = errors.New("invalid username or password")
@@ -11,4 +15,51 @@ var (
 	ErrCrmManagerDown    = errors.New("CRM manager service is unavailable")
 	ErrInvalidToken       = errors.New("invalid or expired token")
 	ErrInvalidTwoFACode   = errors.New("invalid two-factor authentication code")
+	ErrWeakPassword       = errors.New("password does not meet the password policy")
+	ErrTooManyAttempts    = errors.New("too many failed attempts")
+	ErrTwoFAAlreadyEnabled  = errors.New("two-factor authentication is already enabled")
+	ErrTwoFANotEnabled      = errors.New("two-factor authentication is not enabled")
+	ErrTwoFASetupNotStarted = errors.New("two-factor authentication setup has not been started")
+	ErrSessionNotFound      = errors.New("session not found")
+	ErrRefreshTokenReused   = errors.New("refresh token was already used")
+	ErrRoleNotFound         = errors.New("role not found")
+	ErrRoleAlreadyExists    = errors.New("role with this name already exists")
+	ErrSystemRole           = errors.New("system roles cannot be renamed or deleted")
+	ErrRoleInUse            = errors.New("role is still assigned to users")
+	ErrUnknownPermission    = errors.New("unknown permission")
+	ErrInvalidRoleName      = errors.New("invalid role name")
+	ErrConflictingOverride  = errors.New("permission cannot be both granted and denied")
+	ErrAccountLocked        = errors.New("temporarily locked after too many failed attempts")
+	ErrInvalidMobile        = errors.New("invalid mobile number")
+	ErrMobileInUse          = errors.New("mobile number is already used by another user")
+	ErrInvalidOTP           = errors.New("invalid or expired one-time code")
+	ErrSMSDeliveryFailed    = errors.New("failed to deliver SMS")
+	ErrAPIKeyNotFound       = errors.New("API key not found")
+	ErrInvalidAPIKey        = errors.New("invalid, expired or revoked API key")
+	ErrInvalidAPIKeyRequest = errors.New("invalid API key request")
+	ErrPermissionNotHeld    = errors.New("permission is not held by the key owner")
 )
+
+// LockoutError carries Profile Manager's Retry-After for a locked login, 2FA, password-reset or SMS code request.
+// It matches ErrAccountLocked.
+type LockoutError struct {
+	RetryAfter time.Duration
+}
+
+func (e *LockoutError) Error() string {
+	return fmt.Sprintf("%s, retry after %s", ErrAccountLocked, e.RetryAfter)
+}
+
+func (e *LockoutError) Is(target error) bool {
+	return target == ErrAccountLocked
+}
+
+// UpstreamError is a client error (4xx) returned by a backend service; the gateway passes it on unchanged.
+type UpstreamError struct {
+	StatusCode int
+	Message    string
+}
+
+func (e *UpstreamError) Error() string {
+	return fmt.Sprintf("upstream service returned %d: %s", e.StatusCode, e.Message)
+}
@@ -0,0 +1,90 @@
+package crmmanager
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"gold-api/internal/model"
+	service "gold-api/internal/service/common"
+	"net/http"
+	"os"
+	"syscall"
+
+	"common-gold/audit"
+)
+
+func (c *crmManagerHTTPClient) ListAuditEvents(ctx context.Context, rawQuery string) (*model.AuditLogPage, error) {
+	path := "/crm/audit/events"
+	if rawQuery != "" {
+		path += "?" + rawQuery
+	}
+	var page model.AuditLogPage
+	if err := c.getJSON(ctx, path, &page); err != nil {
+		return nil, err
+	}
+	return &page, nil
+}
+
+func (c *crmManagerHTTPClient) VerifyAuditChain(ctx context.Context) (*model.AuditChainReport, error) {
+	var report model.AuditChainReport
+	if err := c.getJSON(ctx, "/crm/audit/verify", &report); err != nil {
+		return nil, err
+	}
+	return &report, nil
+}
+
+// getJSON sends an authenticated GET to CRM Manager on behalf of the current user and decodes the response into out.
+// 4xx answers are returned as *service.UpstreamError so the handler can pass them through.
+func (c *crmManagerHTTPClient) getJSON(ctx context.Context, path string, out any) error {
+	token, ok := ctx.Value("userToken").(string)
+	if !ok || token == "" {
+		return fmt.Errorf("user token not found in context")
+	}
+	internalServiceSecret := os.Getenv("CRM_MANAGER_SERVICE_SECRET")
+	if internalServiceSecret == "" {
+		return fmt.Errorf("CRM_MANAGER_SERVICE_SECRET environment variable is not set")
+	}
+
+	httpReq, err := http.NewRequestWithContext(ctx, http.MethodGet, c.baseURL+path, nil)
+	if err != nil {
+		return fmt.Errorf("failed to create CRM manager request: %w", err)
+	}
+	httpReq.Header.Set("Authorization", "Bearer "+token)
+	httpReq.Header.Set("X-Service-Secret", internalServiceSecret)
+	setAuditHeaders(ctx, httpReq)
+
+	resp, err := c.client.Do(httpReq)
+	if err != nil {
+		if errors.Is(err, context.DeadlineExceeded) || errors.Is(err, os.ErrDeadlineExceeded) || errors.Is(err, syscall.ECONNREFUSED) {
+			return fmt.Errorf("%w: %v", service.ErrCrmManagerDown, err)
+		}
+		return fmt.Errorf("failed to send request to CRM manager: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		var errorResp model.ErrorResponse
+		_ = json.NewDecoder(resp.Body).Decode(&errorResp)
+		if resp.StatusCode >= 400 && resp.StatusCode < 500 {
+			return &service.UpstreamError{StatusCode: resp.StatusCode, Message: errorResp.Message}
+		}
+		return fmt.Errorf("CRM manager returned unexpected status code %d: %s", resp.StatusCode, errorResp.Message)
+	}
+	if err := json.NewDecoder(resp.Body).Decode(out); err != nil {
+		return fmt.Errorf("failed to decode CRM manager response: %w", err)
+	}
+	return nil
+}
+
+// setAuditHeaders forwards the request's correlation ID and the end user's IP and user agent, which CRM Manager
+// stores with the audit events the request produces.
+func setAuditHeaders(ctx context.Context, httpReq *http.Request) {
+	meta, ok := audit.MetaFrom(ctx)
+	if !ok {
+		return
+	}
+	httpReq.Header.Set(audit.CorrelationHeader, meta.CorrelationID)
+	httpReq.Header.Set(audit.ClientIPHeader, meta.IPAddress)
+	httpReq.Header.Set(audit.ClientUserAgentHeader, meta.UserAgent)
+}
@@ -63,6 +63,7 @@ func (c *crmManagerHTTPClient) GetAllCustomers(ctx context.Context) ([]model.Cus
         return nil, fmt.Errorf("CRM_MANAGER_SERVICE_SECRET environment variable is not set")
     }
     httpReq.Header.Set("X-Service-Secret", internalServiceSecret)
+    setAuditHeaders(ctx, httpReq)
 
     resp, err := c.client.Do(httpReq.WithContext(ctx))
     if err != nil {
@@ -126,6 +127,7 @@ func (c *crmManagerHTTPClient) CreateCustomer(ctx context.Context, req *model.Cr
 		return nil, fmt.Errorf("CRM_MANAGER_SERVICE_SECRET environment variable is not set")
 	}
 	httpReq.Header.Set("X-Service-Secret", internalServiceSecret)
+	setAuditHeaders(ctx, httpReq)
 
 	resp, err := c.client.Do(httpReq.WithContext(ctx))
 	if err != nil {
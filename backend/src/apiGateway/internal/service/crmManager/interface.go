@@ -8,6 +8,8 @@ import (
 type CrmManagerClient interface {
 	GetAllCustomers(ctx context.Context) ([]model.Customer, error)
 	CreateCustomer(ctx context.Context, customer *model.CreateCustomerRequest) (*model.Customer, error)
+	ListAuditEvents(ctx context.Context, rawQuery string) (*model.AuditLogPage, error)
+	VerifyAuditChain(ctx context.Context) (*model.AuditChainReport, error)
 	/*	UpdateCustomer(id string, req model.UpdateCustomerRequest) (*model.Customer, error)
 		DeleteCustomer(id string) error
 		GetCustomerTypes() ([]model.CustomerType, error)
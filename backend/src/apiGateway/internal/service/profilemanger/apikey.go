@@ -0,0 +1,130 @@
+package profilemanager
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"strings"
+	"syscall"
+
+	"gold-api/internal/model"
+	service "gold-api/internal/service/common"
+	"gold-api/internal/utils"
+
+	"go.uber.org/zap"
+)
+
+// apiKeyErrors maps the stable Details reasons from Profile Manager back to gateway errors.
+var apiKeyErrors = map[string]error{
+	"invalid_api_key":         service.ErrInvalidAPIKey,
+	"api_key_not_found":       service.ErrAPIKeyNotFound,
+	"user_not_found":          service.ErrUserNotFound,
+	"invalid_api_key_request": service.ErrInvalidAPIKeyRequest,
+	"unknown_permission":      service.ErrUnknownPermission,
+	"permission_not_held":     service.ErrPermissionNotHeld,
+}
+
+// ListAPIKeys returns the keys of userID only.
+func (c *profileManagerHTTPClient) ListAPIKeys(userID string) ([]model.APIKey, error) {
+	var resp model.APIKeysResponse
+	if err := c.doAPIKeyRequest(http.MethodGet, ownAPIKeysPath(userID), nil, nil, &resp); err != nil {
+		return nil, err
+	}
+	return resp.APIKeys, nil
+}
+
+// CreateAPIKey returns the full key, which Profile Manager does not keep, together with the stored key.
+func (c *profileManagerHTTPClient) CreateAPIKey(req model.CreateAPIKeyRequest) (*model.CreateAPIKeyResponse, error) {
+	var resp model.CreateAPIKeyResponse
+	if err := c.doAPIKeyRequest(http.MethodPost, "/api-keys", req, nil, &resp); err != nil {
+		return nil, err
+	}
+	return &resp, nil
+}
+
+// RevokeAPIKey reports a key of another user as service.ErrAPIKeyNotFound.
+func (c *profileManagerHTTPClient) RevokeAPIKey(userID, keyID string) (*model.APIKey, error) {
+	var key model.APIKey
+	if err := c.doAPIKeyRequest(http.MethodDelete, ownAPIKeysPath(userID)+"/"+url.PathEscape(keyID), nil, nil, &key); err != nil {
+		return nil, err
+	}
+	return &key, nil
+}
+
+// VerifyAPIKey exchanges a key for its principal. The client's IP is recorded as the key's last use.
+func (c *profileManagerHTTPClient) VerifyAPIKey(key string, client model.ClientInfo) (*model.APIKeyPrincipal, error) {
+	var principal model.APIKeyPrincipal
+	if err := c.doAPIKeyRequest(http.MethodPost, "/api-keys/verify", model.VerifyAPIKeyRequest{Key: key}, &client, &principal); err != nil {
+		return nil, err
+	}
+	return &principal, nil
+}
+
+func ownAPIKeysPath(userID string) string {
+	return "/account/" + url.PathEscape(userID) + "/api-keys"
+}
+
+// doAPIKeyRequest calls path on Profile Manager with the service secret.
+func (c *profileManagerHTTPClient) doAPIKeyRequest(method, path string, body interface{}, client *model.ClientInfo, out interface{}) error {
+	var reqBody io.Reader
+	if body != nil {
+		payload, err := json.Marshal(body)
+		if err != nil {
+			return fmt.Errorf("failed to marshal API key request: %w", err)
+		}
+		reqBody = bytes.NewReader(payload)
+	}
+	httpReq, err := http.NewRequest(method, c.baseURL+path, reqBody)
+	if err != nil {
+		return fmt.Errorf("failed to create API key request: %w", err)
+	}
+	if body != nil {
+		httpReq.Header.Set("Content-Type", "application/json")
+	}
+	if client != nil {
+		setClientHeaders(httpReq, *client)
+	}
+	internalServiceSecret := os.Getenv("PROFILE_MANAGER_SERVICE_SECRET")
+	if internalServiceSecret == "" {
+		return fmt.Errorf("PROFILE_MANAGER_SERVICE_SECRET environment variable is not set for internal communication")
+	}
+	httpReq.Header.Set("X-Service-Secret", internalServiceSecret)
+
+	resp, err := c.client.Do(httpReq)
+	if err != nil {
+		if errors.Is(err, context.DeadlineExceeded) || errors.Is(err, os.ErrDeadlineExceeded) || errors.Is(err, syscall.ECONNREFUSED) {
+			return fmt.Errorf("%w: cannot connect to profile manager service at %s", service.ErrProfileManagerDown, c.baseURL)
+		}
+		return fmt.Errorf("failed to send API key request to profile manager: %w", err)
+	}
+	defer resp.Body.Close()
+
+	respBody, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return fmt.Errorf("failed to read API key response body: %w", err)
+	}
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		var errorResp model.ErrorResponse
+		_ = json.Unmarshal(respBody, &errorResp)
+		if known, ok := apiKeyErrors[errorResp.Details]; ok {
+			// Profile Manager's message already starts with the same error text.
+			return fmt.Errorf("%w: %s", known, strings.TrimPrefix(errorResp.Message, known.Error()+": "))
+		}
+		utils.Log.Error("Profile Manager returned error for API key request", zap.Int("status", resp.StatusCode), zap.String("message", errorResp.Message))
+		return fmt.Errorf("profile manager API key request failed with status %d", resp.StatusCode)
+	}
+
+	if out != nil {
+		if err := json.Unmarshal(respBody, out); err != nil {
+			return fmt.Errorf("failed to unmarshal API key response: %w", err)
+		}
+	}
+	return nil
+}
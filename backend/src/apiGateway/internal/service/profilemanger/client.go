@@ -11,7 +11,9 @@ import (
 	"gold-api/internal/utils"
 	"io"
 	"net/http"
+	"net/url"
 	"os"
+	"strconv"
 	"syscall"
 	"time"
 
@@ -37,34 +39,35 @@ func NewClient(baseURL string) (ProfileManagerClient, error) {
 	return concreteClient, nil
 }
 
-func (c *profileManagerHTTPClient) AuthenticateUser(username, password string) (*model.User, string, *model.CustomClaims, error) {
-	req := model.LoginRequest{Username: username, Password: password}
+func (c *profileManagerHTTPClient) AuthenticateUser(username, password string, client model.ClientInfo) (*model.LoginResult, error) {
+	req := model.LoginRequest{Username: username, Password: password, DeviceName: client.DeviceName}
 	body, err := json.Marshal(req)
 	if err != nil {
-		return nil, "", nil, fmt.Errorf("failed to marshal login request: %w", err)
+		return nil, fmt.Errorf("failed to marshal login request: %w", err)
 	}
 
 	httpReq, err := http.NewRequest(http.MethodPost, c.baseURL+"/auth/login", bytes.NewBuffer(body))
 	if err != nil {
-		return nil, "", nil, fmt.Errorf("failed to create HTTP request: %w", err)
+		return nil, fmt.Errorf("failed to create HTTP request: %w", err)
 	}
 	httpReq.Header.Set("Content-Type", "application/json")
+	setClientHeaders(httpReq, client)
 
 	resp, err := c.client.Do(httpReq)
 	if err != nil {
 		if errors.Is(err, context.DeadlineExceeded) || errors.Is(err, os.ErrDeadlineExceeded) { // Changed to os.ErrDeadlineExceeded
-			return nil, "", nil, fmt.Errorf("%w: timeout connecting to profile manager service at %s", service.ErrProfileManagerDown, c.baseURL)
+			return nil, fmt.Errorf("%w: timeout connecting to profile manager service at %s", service.ErrProfileManagerDown, c.baseURL)
 		}
 		if errors.Is(err, syscall.ECONNREFUSED) {
-			return nil, "", nil, fmt.Errorf("%w: connection refused to profile manager service at %s", service.ErrProfileManagerDown, c.baseURL)
+			return nil, fmt.Errorf("%w: connection refused to profile manager service at %s", service.ErrProfileManagerDown, c.baseURL)
 		}
-		return nil, "", nil, fmt.Errorf("failed to send request to profile manager: %w", err)
+		return nil, fmt.Errorf("failed to send request to profile manager: %w", err)
 	}
 	defer resp.Body.Close()
 
 	respBody, err := io.ReadAll(resp.Body) // Use io.ReadAll instead of ioutil.ReadAll
 	if err != nil {
-		return nil, "", nil, fmt.Errorf("failed to read profile manager login response body: %w", err)
+		return nil, fmt.Errorf("failed to read profile manager login response body: %w", err)
 	}
 
 	if resp.StatusCode != http.StatusOK {
@@ -74,38 +77,132 @@ func (c *profileManagerHTTPClient) AuthenticateUser(username, password string) (
 		if unmarshalErr := json.Unmarshal(respBody, &errorResp); unmarshalErr == nil && errorResp.Message != "" {
 
 			utils.Log.Error("Profile Manager returned error response", zap.Int("status", resp.StatusCode), zap.String("message", errorResp.Message), zap.String("details", errorResp.Details))
+			if lockout := lockoutError(resp, errorResp); lockout != nil {
+				return nil, lockout
+			}
 			if resp.StatusCode == http.StatusUnauthorized {
-				return nil, "", nil, fmt.Errorf("%w: %s", service.ErrInvalidCredentials, errorResp.Message)
+				return nil, fmt.Errorf("%w: %s", service.ErrInvalidCredentials, errorResp.Message)
 			}
-			return nil, "", nil, fmt.Errorf("profile manager login failed: %s (%d)", errorResp.Message, resp.StatusCode)
+			return nil, fmt.Errorf("profile manager login failed: %s (%d)", errorResp.Message, resp.StatusCode)
 		} else {
 
 			utils.Log.Error("Profile Manager returned unexpected error status", zap.Int("status", resp.StatusCode), zap.ByteString("raw_body", respBody))
 			if resp.StatusCode == http.StatusUnauthorized {
-				return nil, "", nil, fmt.Errorf("%w: invalid credentials", service.ErrInvalidCredentials)
+				return nil, fmt.Errorf("%w: invalid credentials", service.ErrInvalidCredentials)
 			}
-			return nil, "", nil, fmt.Errorf("profile manager login failed with status %d: %s", resp.StatusCode, string(respBody))
+			return nil, fmt.Errorf("profile manager login failed with status %d: %s", resp.StatusCode, string(respBody))
 		}
 	}
 
 	var authResp model.AuthResponse
 	if err := json.Unmarshal(respBody, &authResp); err != nil {
-		return nil, "", nil, fmt.Errorf("failed to unmarshal profile manager login response: %w, raw: %s", err, string(respBody))
+		return nil, fmt.Errorf("failed to unmarshal profile manager login response: %w, raw: %s", err, string(respBody))
+	}
+
+	if authResp.TwoFARequired {
+		if authResp.ChallengeToken == "" {
+			return nil, errors.New("profile manager requested 2FA without a challenge token")
+		}
+		return &model.LoginResult{Challenge: &model.TwoFAChallenge{Token: authResp.ChallengeToken, ExpiresAt: authResp.Exp}}, nil
 	}
 
 	if authResp.User == nil || authResp.User.ID == "" {
-		return nil, "", nil, errors.New("profile manager did not return complete user details")
+		return nil, errors.New("profile manager did not return complete user details")
 	}
+	return loginResultFromAuthResponse(&authResp), nil
+}
 
+// RefreshSession exchanges a refresh token for a new access and refresh token pair.
+func (c *profileManagerHTTPClient) RefreshSession(refreshToken string, client model.ClientInfo) (*model.LoginResult, error) {
+	body, err := json.Marshal(model.RefreshTokenRequest{RefreshToken: refreshToken})
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal refresh request: %w", err)
+	}
+
+	httpReq, err := http.NewRequest(http.MethodPost, c.baseURL+"/auth/refresh", bytes.NewBuffer(body))
+	if err != nil {
+		return nil, fmt.Errorf("failed to create refresh request: %w", err)
+	}
+	httpReq.Header.Set("Content-Type", "application/json")
+	setClientHeaders(httpReq, client)
+
+	resp, err := c.client.Do(httpReq)
+	if err != nil {
+		if errors.Is(err, context.DeadlineExceeded) || errors.Is(err, os.ErrDeadlineExceeded) || errors.Is(err, syscall.ECONNREFUSED) {
+			return nil, fmt.Errorf("%w: cannot connect to profile manager service at %s", service.ErrProfileManagerDown, c.baseURL)
+		}
+		return nil, fmt.Errorf("failed to send refresh request to profile manager: %w", err)
+	}
+	defer resp.Body.Close()
+
+	respBody, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read refresh response body: %w", err)
+	}
+
+	if resp.StatusCode != http.StatusOK {
+		var errorResp model.ErrorResponse
+		_ = json.Unmarshal(respBody, &errorResp)
+		utils.Log.Warn("Profile Manager rejected token refresh", zap.Int("status", resp.StatusCode), zap.String("message", errorResp.Message), zap.String("details", errorResp.Details))
+		if resp.StatusCode == http.StatusUnauthorized && errorResp.Details == "refresh_token_reused" {
+			return nil, fmt.Errorf("%w: %s", service.ErrRefreshTokenReused, errorResp.Message)
+		}
+		if resp.StatusCode == http.StatusUnauthorized || resp.StatusCode == http.StatusBadRequest {
+			return nil, fmt.Errorf("%w: %s", service.ErrInvalidToken, errorResp.Message)
+		}
+		return nil, fmt.Errorf("profile manager token refresh failed with status %d", resp.StatusCode)
+	}
+
+	var authResp model.AuthResponse
+	if err := json.Unmarshal(respBody, &authResp); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal refresh response: %w", err)
+	}
+	if authResp.User == nil || authResp.User.ID == "" {
+		return nil, errors.New("profile manager did not return complete user details after token refresh")
+	}
+	return loginResultFromAuthResponse(&authResp), nil
+}
+
+func loginResultFromAuthResponse(authResp *model.AuthResponse) *model.LoginResult {
 	claims := &model.CustomClaims{
-		UserID:   authResp.User.ID,
-		Username: authResp.User.Username,
-		Roles:    authResp.User.Roles,
+		UserID:    authResp.User.ID,
+		Username:  authResp.User.Username,
+		Roles:     authResp.User.Roles,
+		SessionID: authResp.SessionID,
 		RegisteredClaims: jwt.RegisteredClaims{
 			ExpiresAt: jwt.NewNumericDate(time.Unix(authResp.Exp, 0)),
 		},
 	}
-	return authResp.User, authResp.Token, claims, nil
+	return &model.LoginResult{
+		User:             authResp.User,
+		Token:            authResp.Token,
+		Claims:           claims,
+		RefreshToken:     authResp.RefreshToken,
+		RefreshExpiresAt: authResp.RefreshExp,
+	}
+}
+
+// setClientHeaders forwards the end user's address and browser, which Profile Manager records on the session.
+// Profile Manager trusts them only together with the service secret, so it is sent on public routes too.
+func setClientHeaders(httpReq *http.Request, client model.ClientInfo) {
+	if internalServiceSecret := os.Getenv("PROFILE_MANAGER_SERVICE_SECRET"); internalServiceSecret != "" {
+		httpReq.Header.Set("X-Service-Secret", internalServiceSecret)
+	}
+	if client.IPAddress != "" {
+		httpReq.Header.Set("X-Client-IP", client.IPAddress)
+	}
+	if client.UserAgent != "" {
+		httpReq.Header.Set("X-Client-User-Agent", client.UserAgent)
+	}
+}
+
+// lockoutError turns Profile Manager's 429 "account_locked" reply into a *service.LockoutError, keeping its Retry-After.
+func lockoutError(resp *http.Response, errorResp model.ErrorResponse) error {
+	if resp.StatusCode != http.StatusTooManyRequests || errorResp.Details != "account_locked" {
+		return nil
+	}
+	seconds, _ := strconv.Atoi(resp.Header.Get("Retry-After"))
+	return &service.LockoutError{RetryAfter: time.Duration(seconds) * time.Second}
 }
 
 func (c *profileManagerHTTPClient) BaseURL() string {
@@ -148,6 +245,9 @@ func (c *profileManagerHTTPClient) RegisterUser(req model.RegisterRequest) error
 			if resp.StatusCode == http.StatusConflict {
 				return fmt.Errorf("%w: %s", service.ErrUserAlreadyExists, errorResp.Message)
 			}
+			if resp.StatusCode == http.StatusBadRequest {
+				return fmt.Errorf("%w: %s", service.ErrWeakPassword, errorResp.Details)
+			}
 			return fmt.Errorf("profile manager registration failed: %s (%d)", errorResp.Message, resp.StatusCode)
 		} else {
 			utils.Log.Error("Profile Manager returned unexpected error status for registration", zap.Int("status", resp.StatusCode), zap.ByteString("raw_body", respBody))
@@ -220,7 +320,7 @@ func (c *profileManagerHTTPClient) LogoutUser(token string) error {
 	return nil
 }
 
-func (c *profileManagerHTTPClient) RequestPasswordReset(email string) error {
+func (c *profileManagerHTTPClient) RequestPasswordReset(email string, client model.ClientInfo) error {
 
 	reqBody := map[string]string{"email": email}
 	body, err := json.Marshal(reqBody)
@@ -233,6 +333,7 @@ func (c *profileManagerHTTPClient) RequestPasswordReset(email string) error {
 		return fmt.Errorf("failed to create password reset request: %w", err)
 	}
 	httpReq.Header.Set("Content-Type", "application/json")
+	setClientHeaders(httpReq, client)
 
 	resp, err := c.client.Do(httpReq)
 	if err != nil {
@@ -252,6 +353,9 @@ func (c *profileManagerHTTPClient) RequestPasswordReset(email string) error {
 		var errorResp model.ErrorResponse
 		if unmarshalErr := json.Unmarshal(respBody, &errorResp); unmarshalErr == nil && errorResp.Message != "" {
 			utils.Log.Error("Profile Manager returned error response for password reset", zap.Int("status", resp.StatusCode), zap.String("message", errorResp.Message), zap.String("details", errorResp.Details))
+			if lockout := lockoutError(resp, errorResp); lockout != nil {
+				return lockout
+			}
 			if resp.StatusCode == http.StatusNotFound {
 				return fmt.Errorf("%w: %s", service.ErrUserNotFound, errorResp.Message)
 			}
@@ -300,6 +404,9 @@ func (c *profileManagerHTTPClient) ResetPassword(token, newPassword string) erro
 			if resp.StatusCode == http.StatusUnauthorized { // Assuming 401 for invalid/expired token
 				return fmt.Errorf("%w: %s", service.ErrInvalidToken, errorResp.Message)
 			}
+			if resp.StatusCode == http.StatusBadRequest {
+				return fmt.Errorf("%w: %s", service.ErrWeakPassword, errorResp.Details)
+			}
 			return fmt.Errorf("profile manager reset password failed: %s (%d)", errorResp.Message, resp.StatusCode)
 		} else {
 			utils.Log.Error("Profile Manager returned unexpected error status for reset password", zap.Int("status", resp.StatusCode), zap.ByteString("raw_body", respBody))
@@ -309,70 +416,106 @@ func (c *profileManagerHTTPClient) ResetPassword(token, newPassword string) erro
 	return nil
 }
 
-func (c *profileManagerHTTPClient) VerifyTwoFACode(username, code string) (*model.User, string, *model.CustomClaims, error) {
-	// This is typically part of the login flow, often requiring a temporary session token or similar
-	// along with the username for context. Assuming for now, just username and code are passed.
-	reqBody := model.VerifyTwoFARequest{Code: code}
-	// If Profile Manager needs the username in the body, include it:
-	// reqBody := map[string]string{"username": username, "code": code}
+func (c *profileManagerHTTPClient) VerifyTwoFACode(challengeToken, code string, client model.ClientInfo) (*model.LoginResult, error) {
+	reqBody := model.TwoFALoginRequest{ChallengeToken: challengeToken, Code: code, DeviceName: client.DeviceName}
 
 	body, err := json.Marshal(reqBody)
 	if err != nil {
-		return nil, "", nil, fmt.Errorf("failed to marshal 2FA verification request: %w", err)
+		return nil, fmt.Errorf("failed to marshal 2FA verification request: %w", err)
 	}
 
 	httpReq, err := http.NewRequest(http.MethodPost, c.baseURL+"/auth/2fa/verify", bytes.NewBuffer(body))
 	if err != nil {
-		return nil, "", nil, fmt.Errorf("failed to create 2FA verification request: %w", err)
+		return nil, fmt.Errorf("failed to create 2FA verification request: %w", err)
 	}
 	httpReq.Header.Set("Content-Type", "application/json")
-	// Potentially add a temporary session token header here if Profile Manager's 2FA flow requires it.
-	// E.g., httpReq.Header.Set("X-Temp-Auth-Token", tempToken)
+	setClientHeaders(httpReq, client)
 
 	resp, err := c.client.Do(httpReq)
 	if err != nil {
 		if errors.Is(err, context.DeadlineExceeded) || errors.Is(err, os.ErrDeadlineExceeded) || errors.Is(err, syscall.ECONNREFUSED) {
-			return nil, "", nil, fmt.Errorf("%w: cannot connect to profile manager service at %s", service.ErrProfileManagerDown, c.baseURL)
+			return nil, fmt.Errorf("%w: cannot connect to profile manager service at %s", service.ErrProfileManagerDown, c.baseURL)
 		}
-		return nil, "", nil, fmt.Errorf("failed to send 2FA verification request to profile manager: %w", err)
+		return nil, fmt.Errorf("failed to send 2FA verification request to profile manager: %w", err)
 	}
 	defer resp.Body.Close()
 
 	respBody, err := io.ReadAll(resp.Body)
 	if err != nil {
-		return nil, "", nil, fmt.Errorf("failed to read 2FA verification response body: %w", err)
+		return nil, fmt.Errorf("failed to read 2FA verification response body: %w", err)
 	}
 
 	if resp.StatusCode != http.StatusOK {
 		var errorResp model.ErrorResponse
 		if unmarshalErr := json.Unmarshal(respBody, &errorResp); unmarshalErr == nil && errorResp.Message != "" {
 			utils.Log.Error("Profile Manager returned error response for 2FA verification", zap.Int("status", resp.StatusCode), zap.String("message", errorResp.Message), zap.String("details", errorResp.Details))
-			if resp.StatusCode == http.StatusUnauthorized { // Assuming 401 for invalid 2FA code
-				return nil, "", nil, fmt.Errorf("%w: %s", service.ErrInvalidTwoFACode, errorResp.Message)
+			if lockout := lockoutError(resp, errorResp); lockout != nil {
+				return nil, lockout
+			}
+			if resp.StatusCode == http.StatusUnauthorized && errorResp.Details == "challenge_expired" {
+				return nil, fmt.Errorf("%w: %s", service.ErrInvalidToken, errorResp.Message)
+			}
+			if resp.StatusCode == http.StatusUnauthorized {
+				return nil, fmt.Errorf("%w: %s", service.ErrInvalidTwoFACode, errorResp.Message)
+			}
+			if resp.StatusCode == http.StatusTooManyRequests {
+				return nil, fmt.Errorf("%w: %s", service.ErrTooManyAttempts, errorResp.Message)
 			}
-			return nil, "", nil, fmt.Errorf("profile manager 2FA verification failed: %s (%d)", errorResp.Message, resp.StatusCode)
+			return nil, fmt.Errorf("profile manager 2FA verification failed: %s (%d)", errorResp.Message, resp.StatusCode)
 		} else {
 			utils.Log.Error("Profile Manager returned unexpected error status for 2FA verification", zap.Int("status", resp.StatusCode), zap.ByteString("raw_body", respBody))
-			return nil, "", nil, fmt.Errorf("profile manager 2FA verification failed with status %d: %s", resp.StatusCode, string(respBody))
+			return nil, fmt.Errorf("profile manager 2FA verification failed with status %d: %s", resp.StatusCode, string(respBody))
 		}
 	}
 
 	var authResp model.AuthResponse
 	if err := json.Unmarshal(respBody, &authResp); err != nil {
-		return nil, "", nil, fmt.Errorf("failed to unmarshal 2FA verification response: %w, raw: %s", err, string(respBody))
+		return nil, fmt.Errorf("failed to unmarshal 2FA verification response: %w, raw: %s", err, string(respBody))
 	}
 
 	if authResp.User == nil || authResp.User.ID == "" {
-		return nil, "", nil, errors.New("profile manager did not return complete user details after 2FA verification")
+		return nil, errors.New("profile manager did not return complete user details after 2FA verification")
 	}
 
-	claims := &model.CustomClaims{
-		UserID:   authResp.User.ID,
-		Username: authResp.User.Username,
-		Roles:    authResp.User.Roles,
+	return loginResultFromAuthResponse(&authResp), nil
+}
+
+// UnlockUser lifts the login, 2FA and password-reset lockouts of a user on Profile Manager.
+func (c *profileManagerHTTPClient) UnlockUser(userID string) error {
+	httpReq, err := http.NewRequest(http.MethodPost, fmt.Sprintf("%s/auth/users/%s/unlock", c.baseURL, userID), nil)
+	if err != nil {
+		return fmt.Errorf("failed to create unlock user request: %w", err)
+	}
+	internalServiceSecret := os.Getenv("PROFILE_MANAGER_SERVICE_SECRET")
+	if internalServiceSecret == "" {
+		return fmt.Errorf("PROFILE_MANAGER_SERVICE_SECRET environment variable is not set for internal communication")
 	}
+	httpReq.Header.Set("X-Service-Secret", internalServiceSecret)
 
-	return authResp.User, authResp.Token, claims, nil
+	resp, err := c.client.Do(httpReq)
+	if err != nil {
+		if errors.Is(err, context.DeadlineExceeded) || errors.Is(err, os.ErrDeadlineExceeded) || errors.Is(err, syscall.ECONNREFUSED) {
+			return fmt.Errorf("%w: cannot connect to profile manager service at %s", service.ErrProfileManagerDown, c.baseURL)
+		}
+		return fmt.Errorf("failed to send unlock user request to profile manager: %w", err)
+	}
+	defer resp.Body.Close()
+
+	respBody, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return fmt.Errorf("failed to read unlock user response body: %w", err)
+	}
+
+	if resp.StatusCode != http.StatusOK {
+		var errorResp model.ErrorResponse
+		_ = json.Unmarshal(respBody, &errorResp)
+		utils.Log.Error("Profile Manager returned error for unlock user", zap.Int("status", resp.StatusCode), zap.String("message", errorResp.Message))
+		if resp.StatusCode == http.StatusNotFound {
+			return fmt.Errorf("%w: %s", service.ErrUserNotFound, errorResp.Message)
+		}
+		return fmt.Errorf("profile manager unlock user failed with status %d", resp.StatusCode)
+	}
+	return nil
 }
 
 // --- Account Management Implementations ---
@@ -412,19 +555,27 @@ func (c *profileManagerHTTPClient) ChangeUsername(userID string, req model.Chang
 	return nil
 }
 
-func (c *profileManagerHTTPClient) ChangePassword(userID string, req model.ChangePasswordRequest) error {
+// ChangePassword passes the caller's own session as ?current= so Profile Manager keeps it and signs out the others.
+func (c *profileManagerHTTPClient) ChangePassword(userID, currentSessionID string, req model.ChangePasswordRequest) error {
 	body, err := json.Marshal(req)
 	if err != nil {
 		return fmt.Errorf("failed to marshal change password request: %w", err)
 	}
 
-	httpReq, err := http.NewRequest(http.MethodPost, fmt.Sprintf("%s/account/%s/change-password", c.baseURL, userID), bytes.NewBuffer(body))
+	endpoint := fmt.Sprintf("%s/account/%s/change-password", c.baseURL, userID)
+	if currentSessionID != "" {
+		endpoint += "?current=" + url.QueryEscape(currentSessionID)
+	}
+	httpReq, err := http.NewRequest(http.MethodPost, endpoint, bytes.NewBuffer(body))
 	if err != nil {
 		return fmt.Errorf("failed to create change password request: %w", err)
 	}
 	httpReq.Header.Set("Content-Type", "application/json")
-	// IMPORTANT: Add Authorization header
-	// httpReq.Header.Set("Authorization", "Bearer " + getInternalTokenForProfileManager())
+	internalServiceSecret := os.Getenv("PROFILE_MANAGER_SERVICE_SECRET")
+	if internalServiceSecret == "" {
+		return fmt.Errorf("PROFILE_MANAGER_SERVICE_SECRET environment variable is not set for internal communication")
+	}
+	httpReq.Header.Set("X-Service-Secret", internalServiceSecret)
 
 	resp, err := c.client.Do(httpReq)
 	if err != nil {
@@ -440,6 +591,14 @@ func (c *profileManagerHTTPClient) ChangePassword(userID string, req model.Chang
 	if resp.StatusCode != http.StatusOK {
 		var errorResp model.ErrorResponse
 		if unmarshalErr := json.Unmarshal(respBody, &errorResp); unmarshalErr == nil && errorResp.Message != "" {
+			switch resp.StatusCode {
+			case http.StatusBadRequest:
+				return fmt.Errorf("%w: %s", service.ErrWeakPassword, errorResp.Details)
+			case http.StatusForbidden:
+				return fmt.Errorf("%w: %s", service.ErrInvalidCredentials, errorResp.Message)
+			case http.StatusNotFound:
+				return fmt.Errorf("%w: %s", service.ErrUserNotFound, errorResp.Message)
+			}
 			return fmt.Errorf("profile manager change password failed: %s (%d)", errorResp.Message, resp.StatusCode)
 		}
 		return fmt.Errorf("profile manager change password failed with status %d: %s", resp.StatusCode, string(respBody))
@@ -485,112 +644,6 @@ func (c *profileManagerHTTPClient) UploadProfilePicture(userID string, filename
 	return nil
 }
 
-func (c *profileManagerHTTPClient) GenerateTwoFASetup(userID string) (secret string, qrCode string, err error) {
-	httpReq, err := http.NewRequest(http.MethodPost, fmt.Sprintf("%s/account/%s/2fa/generate-secret", c.baseURL, userID), nil)
-	if err != nil {
-		return "", "", fmt.Errorf("failed to create generate 2FA secret request: %w", err)
-	}
-	// IMPORTANT: Add Authorization header
-	// httpReq.Header.Set("Authorization", "Bearer " + getInternalTokenForProfileManager())
-
-	resp, err := c.client.Do(httpReq)
-	if err != nil {
-		return "", "", fmt.Errorf("%w: failed to send generate 2FA secret request to profile manager: %w", service.ErrProfileManagerDown, err)
-	}
-	defer resp.Body.Close()
-
-	respBody, err := io.ReadAll(resp.Body)
-	if err != nil {
-		return "", "", fmt.Errorf("failed to read generate 2FA secret response body: %w", err)
-	}
-
-	if resp.StatusCode != http.StatusOK {
-		var errorResp model.ErrorResponse
-		if unmarshalErr := json.Unmarshal(respBody, &errorResp); unmarshalErr == nil && errorResp.Message != "" {
-			return "", "", fmt.Errorf("profile manager generate 2FA secret failed: %s (%d)", errorResp.Message, resp.StatusCode)
-		}
-		return "", "", fmt.Errorf("profile manager generate 2FA secret failed with status %d: %s", resp.StatusCode, string(respBody))
-	}
-
-	var response struct { // Assuming Profile Manager returns this structure
-		Secret string `json:"secret"`
-		QRCode string `json:"qr_code"`
-	}
-	if err := json.Unmarshal(respBody, &response); err != nil {
-		return "", "", fmt.Errorf("failed to unmarshal generate 2FA secret response: %w, raw: %s", err, string(respBody))
-	}
-
-	return response.Secret, response.QRCode, nil
-}
-
-func (c *profileManagerHTTPClient) VerifyAndEnableTwoFA(userID string, req model.VerifyTwoFARequest) error {
-	body, err := json.Marshal(req)
-	if err != nil {
-		return fmt.Errorf("failed to marshal verify and enable 2FA request: %w", err)
-	}
-
-	httpReq, err := http.NewRequest(http.MethodPost, fmt.Sprintf("%s/account/%s/2fa/enable", c.baseURL, userID), bytes.NewBuffer(body))
-	if err != nil {
-		return fmt.Errorf("failed to create verify and enable 2FA request: %w", err)
-	}
-	httpReq.Header.Set("Content-Type", "application/json")
-	// IMPORTANT: Add Authorization header
-
-	resp, err := c.client.Do(httpReq)
-	if err != nil {
-		return fmt.Errorf("%w: failed to send verify and enable 2FA request to profile manager: %w", service.ErrProfileManagerDown, err)
-	}
-	defer resp.Body.Close()
-
-	respBody, err := io.ReadAll(resp.Body)
-	if err != nil {
-		return fmt.Errorf("failed to read verify and enable 2FA response body: %w", err)
-	}
-
-	if resp.StatusCode != http.StatusOK {
-		var errorResp model.ErrorResponse
-		if unmarshalErr := json.Unmarshal(respBody, &errorResp); unmarshalErr == nil && errorResp.Message != "" {
-			return fmt.Errorf("profile manager verify and enable 2FA failed: %s (%d)", errorResp.Message, resp.StatusCode)
-		}
-		return fmt.Errorf("profile manager verify and enable 2FA failed with status %d: %s", resp.StatusCode, string(respBody))
-	}
-	return nil
-}
-
-func (c *profileManagerHTTPClient) DisableTwoFA(userID string, req model.DisableTwoFARequest) error {
-	body, err := json.Marshal(req)
-	if err != nil {
-		return fmt.Errorf("failed to marshal disable 2FA request: %w", err)
-	}
-
-	httpReq, err := http.NewRequest(http.MethodPost, fmt.Sprintf("%s/account/%s/2fa/disable", c.baseURL, userID), bytes.NewBuffer(body))
-	if err != nil {
-		return fmt.Errorf("failed to create disable 2FA request: %w", err)
-	}
-	httpReq.Header.Set("Content-Type", "application/json")
-	// IMPORTANT: Add Authorization header
-
-	resp, err := c.client.Do(httpReq)
-	if err != nil {
-		return fmt.Errorf("%w: failed to send disable 2FA request to profile manager: %w", service.ErrProfileManagerDown, err)
-	}
-	defer resp.Body.Close()
-
-	respBody, err := io.ReadAll(resp.Body)
-	if err != nil {
-		return fmt.Errorf("failed to read disable 2FA response body: %w", err)
-	}
-
-	if resp.StatusCode != http.StatusOK {
-		var errorResp model.ErrorResponse
-		if unmarshalErr := json.Unmarshal(respBody, &errorResp); unmarshalErr == nil && errorResp.Message != "" {
-			return fmt.Errorf("profile manager disable 2FA failed: %s (%d)", errorResp.Message, resp.StatusCode)
-		}
-		return fmt.Errorf("profile manager disable 2FA failed with status %d: %s", resp.StatusCode, string(respBody))
-	}
-	return nil
-}
-
 // --- User Management Implementations ---
 
 func (c *profileManagerHTTPClient) GetUsers() ([]model.User, error) {
@@ -5,18 +5,42 @@ import "gold-api/internal/model"
 type ProfileManagerClient interface {
 
 	RegisterUser(req model.RegisterRequest) error
-	AuthenticateUser(username, password string) (*model.User, string, *model.CustomClaims, error)
+	AuthenticateUser(username, password string, client model.ClientInfo) (*model.LoginResult, error)
 	LogoutUser(token string) error
-	RequestPasswordReset(email string) error                                               
+	RequestPasswordReset(email string, client model.ClientInfo) error
 	ResetPassword(token, newPassword string) error                                          
-	VerifyTwoFACode(username, code string) (*model.User, string, *model.CustomClaims, error) 
+	VerifyTwoFACode(challengeToken, code string, client model.ClientInfo) (*model.LoginResult, error)
+	RefreshSession(refreshToken string, client model.ClientInfo) (*model.LoginResult, error)
+	UnlockUser(userID string) error
+	RequestLoginOTP(mobile string, client model.ClientInfo) error
+	VerifyLoginOTP(mobile, code string, client model.ClientInfo) (*model.LoginResult, error)
 
 	ChangeUsername(userID string, req model.ChangeUsernameRequest) error
-	ChangePassword(userID string, req model.ChangePasswordRequest) error
+	ChangePassword(userID, currentSessionID string, req model.ChangePasswordRequest) error
 	UploadProfilePicture(userID string, filename string, contentType string, fileContent []byte) error 
-	GenerateTwoFASetup(userID string) (secret string, qrCode string, err error)                       
-	VerifyAndEnableTwoFA(userID string, req model.VerifyTwoFARequest) error
-	DisableTwoFA(userID string, req model.DisableTwoFARequest) error
+	GenerateTwoFASetup(userID string) (*model.TwoFASetupResponse, error)
+	VerifyAndEnableTwoFA(userID string, req model.EnableTwoFARequest) ([]string, error)
+	DisableTwoFA(userID string, req model.DisableTwoFARequest, client model.ClientInfo) error
+	RegenerateRecoveryCodes(userID string, req model.EnableTwoFARequest, client model.ClientInfo) ([]string, error)
+	GetRecoveryCodesRemaining(userID string) (int64, error)
+	ListSessions(userID, currentSessionID string) ([]model.Session, error)
+	RevokeSession(userID, sessionID string) error
+	RevokeOtherSessions(userID, currentSessionID string) (int, error)
+	RequestMobileVerification(userID, mobile string, client model.ClientInfo) error
+	VerifyMobile(userID, code string, client model.ClientInfo) (*model.MobileResponse, error)
+
+	ListRoles() ([]model.Role, error)
+	CreateRole(req model.RoleRequest) (*model.Role, error)
+	UpdateRole(name string, req model.RoleRequest) (*model.Role, error)
+	DeleteRole(name string) error
+	ListPermissions() ([]model.Permission, error)
+	GetUserPermissions(userID string) (*model.UserPermissionsResponse, error)
+	SetUserPermissions(userID string, req model.UserPermissionsRequest) (*model.UserPermissionsResponse, error)
+
+	ListAPIKeys(userID string) ([]model.APIKey, error)
+	CreateAPIKey(req model.CreateAPIKeyRequest) (*model.CreateAPIKeyResponse, error)
+	RevokeAPIKey(userID, keyID string) (*model.APIKey, error)
+	VerifyAPIKey(key string, client model.ClientInfo) (*model.APIKeyPrincipal, error)
 
 	GetUsers() ([]model.User, error)
 	GetUserByID(userID string) (*model.User, error)
@@ -0,0 +1,124 @@
+package profilemanager
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"syscall"
+
+	"gold-api/internal/model"
+	service "gold-api/internal/service/common"
+	"gold-api/internal/utils"
+
+	"go.uber.org/zap"
+)
+
+// RequestLoginOTP asks Profile Manager to text a login code. It succeeds for unknown numbers too.
+func (c *profileManagerHTTPClient) RequestLoginOTP(mobile string, client model.ClientInfo) error {
+	return c.doOTPRequest("/auth/otp/request", false, model.OTPRequest{Mobile: mobile}, client, nil)
+}
+
+func (c *profileManagerHTTPClient) VerifyLoginOTP(mobile, code string, client model.ClientInfo) (*model.LoginResult, error) {
+	var authResp model.AuthResponse
+	req := model.OTPLoginRequest{Mobile: mobile, Code: code, DeviceName: client.DeviceName}
+	if err := c.doOTPRequest("/auth/otp/verify", false, req, client, &authResp); err != nil {
+		return nil, err
+	}
+	if authResp.TwoFARequired {
+		if authResp.ChallengeToken == "" {
+			return nil, errors.New("profile manager requested 2FA without a challenge token")
+		}
+		return &model.LoginResult{Challenge: &model.TwoFAChallenge{Token: authResp.ChallengeToken, ExpiresAt: authResp.Exp}}, nil
+	}
+	if authResp.User == nil || authResp.User.ID == "" {
+		return nil, errors.New("profile manager did not return complete user details after SMS login")
+	}
+	return loginResultFromAuthResponse(&authResp), nil
+}
+
+func (c *profileManagerHTTPClient) RequestMobileVerification(userID, mobile string, client model.ClientInfo) error {
+	return c.doOTPRequest(fmt.Sprintf("/account/%s/mobile", userID), true, model.OTPRequest{Mobile: mobile}, client, nil)
+}
+
+func (c *profileManagerHTTPClient) VerifyMobile(userID, code string, client model.ClientInfo) (*model.MobileResponse, error) {
+	var resp model.MobileResponse
+	if err := c.doOTPRequest(fmt.Sprintf("/account/%s/mobile/verify", userID), true, model.VerifyMobileRequest{Code: code}, client, &resp); err != nil {
+		return nil, err
+	}
+	return &resp, nil
+}
+
+// doOTPRequest posts to an SMS code endpoint of Profile Manager and maps its error reasons back to service errors.
+// Account endpoints additionally need the service secret.
+func (c *profileManagerHTTPClient) doOTPRequest(path string, withSecret bool, reqBody interface{}, client model.ClientInfo, out interface{}) error {
+	data, err := json.Marshal(reqBody)
+	if err != nil {
+		return fmt.Errorf("failed to marshal SMS code request: %w", err)
+	}
+	httpReq, err := http.NewRequest(http.MethodPost, c.baseURL+path, bytes.NewBuffer(data))
+	if err != nil {
+		return fmt.Errorf("failed to create SMS code request: %w", err)
+	}
+	httpReq.Header.Set("Content-Type", "application/json")
+	setClientHeaders(httpReq, client)
+	if withSecret {
+		internalServiceSecret := os.Getenv("PROFILE_MANAGER_SERVICE_SECRET")
+		if internalServiceSecret == "" {
+			return fmt.Errorf("PROFILE_MANAGER_SERVICE_SECRET environment variable is not set for internal communication")
+		}
+		httpReq.Header.Set("X-Service-Secret", internalServiceSecret)
+	}
+
+	resp, err := c.client.Do(httpReq)
+	if err != nil {
+		if errors.Is(err, context.DeadlineExceeded) || errors.Is(err, os.ErrDeadlineExceeded) || errors.Is(err, syscall.ECONNREFUSED) {
+			return fmt.Errorf("%w: cannot connect to profile manager service at %s", service.ErrProfileManagerDown, c.baseURL)
+		}
+		return fmt.Errorf("failed to send SMS code request to profile manager: %w", err)
+	}
+	defer resp.Body.Close()
+
+	respBody, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return fmt.Errorf("failed to read SMS code response body: %w", err)
+	}
+
+	if resp.StatusCode != http.StatusOK {
+		var errorResp model.ErrorResponse
+		if unmarshalErr := json.Unmarshal(respBody, &errorResp); unmarshalErr != nil || errorResp.Message == "" {
+			utils.Log.Error("Profile Manager returned unexpected error status for SMS code request", zap.Int("status", resp.StatusCode), zap.ByteString("raw_body", respBody))
+			return fmt.Errorf("profile manager SMS code request failed with status %d", resp.StatusCode)
+		}
+		utils.Log.Warn("Profile Manager rejected SMS code request", zap.Int("status", resp.StatusCode), zap.String("message", errorResp.Message), zap.String("details", errorResp.Details))
+		if lockout := lockoutError(resp, errorResp); lockout != nil {
+			return lockout
+		}
+		switch {
+		case resp.StatusCode == http.StatusNotFound:
+			return fmt.Errorf("%w: %s", service.ErrUserNotFound, errorResp.Message)
+		case errorResp.Details == "invalid_mobile":
+			return fmt.Errorf("%w: %s", service.ErrInvalidMobile, errorResp.Message)
+		case errorResp.Details == "invalid_code":
+			return fmt.Errorf("%w: %s", service.ErrInvalidOTP, errorResp.Message)
+		case errorResp.Details == "too_many_attempts":
+			return fmt.Errorf("%w: %s", service.ErrTooManyAttempts, errorResp.Message)
+		case errorResp.Details == "mobile_in_use":
+			return fmt.Errorf("%w: %s", service.ErrMobileInUse, errorResp.Message)
+		case errorResp.Details == "sms_failed":
+			return fmt.Errorf("%w: %s", service.ErrSMSDeliveryFailed, errorResp.Message)
+		}
+		return fmt.Errorf("profile manager SMS code request failed: %s (%d)", errorResp.Message, resp.StatusCode)
+	}
+
+	if out != nil {
+		if err := json.Unmarshal(respBody, out); err != nil {
+			return fmt.Errorf("failed to unmarshal SMS code response: %w", err)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,139 @@
+package profilemanager
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"syscall"
+
+	"gold-api/internal/model"
+	service "gold-api/internal/service/common"
+	"gold-api/internal/utils"
+
+	"go.uber.org/zap"
+)
+
+// rbacErrors maps the stable Details reasons from Profile Manager back to gateway errors.
+var rbacErrors = map[string]error{
+	"role_not_found":       service.ErrRoleNotFound,
+	"user_not_found":       service.ErrUserNotFound,
+	"role_exists":          service.ErrRoleAlreadyExists,
+	"role_in_use":          service.ErrRoleInUse,
+	"system_role":          service.ErrSystemRole,
+	"unknown_permission":   service.ErrUnknownPermission,
+	"invalid_role_name":    service.ErrInvalidRoleName,
+	"conflicting_override": service.ErrConflictingOverride,
+}
+
+func (c *profileManagerHTTPClient) ListRoles() ([]model.Role, error) {
+	var resp model.RolesResponse
+	if err := c.doRBACRequest(http.MethodGet, "/roles", nil, &resp); err != nil {
+		return nil, err
+	}
+	return resp.Roles, nil
+}
+
+func (c *profileManagerHTTPClient) CreateRole(req model.RoleRequest) (*model.Role, error) {
+	var role model.Role
+	if err := c.doRBACRequest(http.MethodPost, "/roles", req, &role); err != nil {
+		return nil, err
+	}
+	return &role, nil
+}
+
+func (c *profileManagerHTTPClient) UpdateRole(name string, req model.RoleRequest) (*model.Role, error) {
+	var role model.Role
+	if err := c.doRBACRequest(http.MethodPut, "/roles/"+url.PathEscape(name), req, &role); err != nil {
+		return nil, err
+	}
+	return &role, nil
+}
+
+func (c *profileManagerHTTPClient) DeleteRole(name string) error {
+	return c.doRBACRequest(http.MethodDelete, "/roles/"+url.PathEscape(name), nil, nil)
+}
+
+func (c *profileManagerHTTPClient) ListPermissions() ([]model.Permission, error) {
+	var resp model.PermissionsResponse
+	if err := c.doRBACRequest(http.MethodGet, "/permissions", nil, &resp); err != nil {
+		return nil, err
+	}
+	return resp.Permissions, nil
+}
+
+func (c *profileManagerHTTPClient) GetUserPermissions(userID string) (*model.UserPermissionsResponse, error) {
+	var resp model.UserPermissionsResponse
+	if err := c.doRBACRequest(http.MethodGet, "/users/"+url.PathEscape(userID)+"/permissions", nil, &resp); err != nil {
+		return nil, err
+	}
+	return &resp, nil
+}
+
+func (c *profileManagerHTTPClient) SetUserPermissions(userID string, req model.UserPermissionsRequest) (*model.UserPermissionsResponse, error) {
+	var resp model.UserPermissionsResponse
+	if err := c.doRBACRequest(http.MethodPut, "/users/"+url.PathEscape(userID)+"/permissions", req, &resp); err != nil {
+		return nil, err
+	}
+	return &resp, nil
+}
+
+// doRBACRequest calls /rbac{path} on Profile Manager with the service secret.
+func (c *profileManagerHTTPClient) doRBACRequest(method, path string, body, out interface{}) error {
+	var reqBody io.Reader
+	if body != nil {
+		payload, err := json.Marshal(body)
+		if err != nil {
+			return fmt.Errorf("failed to marshal rbac request: %w", err)
+		}
+		reqBody = bytes.NewReader(payload)
+	}
+	httpReq, err := http.NewRequest(method, c.baseURL+"/rbac"+path, reqBody)
+	if err != nil {
+		return fmt.Errorf("failed to create rbac request: %w", err)
+	}
+	if body != nil {
+		httpReq.Header.Set("Content-Type", "application/json")
+	}
+	internalServiceSecret := os.Getenv("PROFILE_MANAGER_SERVICE_SECRET")
+	if internalServiceSecret == "" {
+		return fmt.Errorf("PROFILE_MANAGER_SERVICE_SECRET environment variable is not set for internal communication")
+	}
+	httpReq.Header.Set("X-Service-Secret", internalServiceSecret)
+
+	resp, err := c.client.Do(httpReq)
+	if err != nil {
+		if errors.Is(err, context.DeadlineExceeded) || errors.Is(err, os.ErrDeadlineExceeded) || errors.Is(err, syscall.ECONNREFUSED) {
+			return fmt.Errorf("%w: cannot connect to profile manager service at %s", service.ErrProfileManagerDown, c.baseURL)
+		}
+		return fmt.Errorf("failed to send rbac request to profile manager: %w", err)
+	}
+	defer resp.Body.Close()
+
+	respBody, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return fmt.Errorf("failed to read rbac response body: %w", err)
+	}
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		var errorResp model.ErrorResponse
+		_ = json.Unmarshal(respBody, &errorResp)
+		if known, ok := rbacErrors[errorResp.Details]; ok {
+			return fmt.Errorf("%w: %s", known, errorResp.Message)
+		}
+		utils.Log.Error("Profile Manager returned error for rbac request", zap.Int("status", resp.StatusCode), zap.String("message", errorResp.Message))
+		return fmt.Errorf("profile manager rbac request failed with status %d", resp.StatusCode)
+	}
+
+	if out != nil {
+		if err := json.Unmarshal(respBody, out); err != nil {
+			return fmt.Errorf("failed to unmarshal rbac response: %w", err)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,88 @@
+package profilemanager
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"syscall"
+
+	"gold-api/internal/model"
+	service "gold-api/internal/service/common"
+	"gold-api/internal/utils"
+
+	"go.uber.org/zap"
+)
+
+func (c *profileManagerHTTPClient) ListSessions(userID, currentSessionID string) ([]model.Session, error) {
+	var resp model.SessionsResponse
+	if err := c.doSessionRequest(http.MethodGet, userID, "", currentSessionID, &resp); err != nil {
+		return nil, err
+	}
+	return resp.Sessions, nil
+}
+
+func (c *profileManagerHTTPClient) RevokeSession(userID, sessionID string) error {
+	return c.doSessionRequest(http.MethodDelete, userID, "/"+url.PathEscape(sessionID), "", nil)
+}
+
+func (c *profileManagerHTTPClient) RevokeOtherSessions(userID, currentSessionID string) (int, error) {
+	var resp model.RevokeSessionsResponse
+	if err := c.doSessionRequest(http.MethodPost, userID, "/revoke-others", currentSessionID, &resp); err != nil {
+		return 0, err
+	}
+	return resp.Revoked, nil
+}
+
+// doSessionRequest calls /account/{userID}/sessions{path} on Profile Manager with the service secret,
+// passing the caller's own session as ?current= when given.
+func (c *profileManagerHTTPClient) doSessionRequest(method, userID, path, currentSessionID string, out interface{}) error {
+	endpoint := fmt.Sprintf("%s/account/%s/sessions%s", c.baseURL, userID, path)
+	if currentSessionID != "" {
+		endpoint += "?current=" + url.QueryEscape(currentSessionID)
+	}
+	httpReq, err := http.NewRequest(method, endpoint, nil)
+	if err != nil {
+		return fmt.Errorf("failed to create session request: %w", err)
+	}
+	internalServiceSecret := os.Getenv("PROFILE_MANAGER_SERVICE_SECRET")
+	if internalServiceSecret == "" {
+		return fmt.Errorf("PROFILE_MANAGER_SERVICE_SECRET environment variable is not set for internal communication")
+	}
+	httpReq.Header.Set("X-Service-Secret", internalServiceSecret)
+
+	resp, err := c.client.Do(httpReq)
+	if err != nil {
+		if errors.Is(err, context.DeadlineExceeded) || errors.Is(err, os.ErrDeadlineExceeded) || errors.Is(err, syscall.ECONNREFUSED) {
+			return fmt.Errorf("%w: cannot connect to profile manager service at %s", service.ErrProfileManagerDown, c.baseURL)
+		}
+		return fmt.Errorf("failed to send session request to profile manager: %w", err)
+	}
+	defer resp.Body.Close()
+
+	respBody, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return fmt.Errorf("failed to read session response body: %w", err)
+	}
+
+	if resp.StatusCode != http.StatusOK {
+		var errorResp model.ErrorResponse
+		_ = json.Unmarshal(respBody, &errorResp)
+		utils.Log.Error("Profile Manager returned error for session request", zap.Int("status", resp.StatusCode), zap.String("message", errorResp.Message))
+		if resp.StatusCode == http.StatusNotFound {
+			return fmt.Errorf("%w: %s", service.ErrSessionNotFound, errorResp.Message)
+		}
+		return fmt.Errorf("profile manager session request failed with status %d", resp.StatusCode)
+	}
+
+	if out != nil {
+		if err := json.Unmarshal(respBody, out); err != nil {
+			return fmt.Errorf("failed to unmarshal session response: %w", err)
+		}
+	}
+	return nil
+}
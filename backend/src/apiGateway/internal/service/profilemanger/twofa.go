@@ -0,0 +1,131 @@
+package profilemanager
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"syscall"
+
+	"gold-api/internal/model"
+	service "gold-api/internal/service/common"
+	"gold-api/internal/utils"
+
+	"go.uber.org/zap"
+)
+
+func (c *profileManagerHTTPClient) GenerateTwoFASetup(userID string) (*model.TwoFASetupResponse, error) {
+	var setup model.TwoFASetupResponse
+	if err := c.doTwoFARequest(http.MethodPost, userID, "/generate-secret", nil, &setup, model.ClientInfo{}); err != nil {
+		return nil, err
+	}
+	return &setup, nil
+}
+
+func (c *profileManagerHTTPClient) VerifyAndEnableTwoFA(userID string, req model.EnableTwoFARequest) ([]string, error) {
+	var resp model.RecoveryCodesResponse
+	if err := c.doTwoFARequest(http.MethodPost, userID, "/enable", req, &resp, model.ClientInfo{}); err != nil {
+		return nil, err
+	}
+	return resp.RecoveryCodes, nil
+}
+
+func (c *profileManagerHTTPClient) DisableTwoFA(userID string, req model.DisableTwoFARequest, client model.ClientInfo) error {
+	return c.doTwoFARequest(http.MethodPost, userID, "/disable", req, nil, client)
+}
+
+func (c *profileManagerHTTPClient) RegenerateRecoveryCodes(userID string, req model.EnableTwoFARequest, client model.ClientInfo) ([]string, error) {
+	var resp model.RecoveryCodesResponse
+	if err := c.doTwoFARequest(http.MethodPost, userID, "/recovery-codes/regenerate", req, &resp, client); err != nil {
+		return nil, err
+	}
+	return resp.RecoveryCodes, nil
+}
+
+func (c *profileManagerHTTPClient) GetRecoveryCodesRemaining(userID string) (int64, error) {
+	var resp model.RecoveryCodesResponse
+	if err := c.doTwoFARequest(http.MethodGet, userID, "/recovery-codes", nil, &resp, model.ClientInfo{}); err != nil {
+		return 0, err
+	}
+	return resp.Remaining, nil
+}
+
+// doTwoFARequest calls /account/{userID}/2fa{path} on Profile Manager with the service secret
+// and maps its error reasons back to service errors. Endpoints that check a code get the end user's client,
+// since Profile Manager counts wrong codes per IP as well as per user.
+func (c *profileManagerHTTPClient) doTwoFARequest(method, userID, path string, reqBody interface{}, out interface{}, client model.ClientInfo) error {
+	var body io.Reader
+	if reqBody != nil {
+		data, err := json.Marshal(reqBody)
+		if err != nil {
+			return fmt.Errorf("failed to marshal 2FA request: %w", err)
+		}
+		body = bytes.NewBuffer(data)
+	}
+
+	url := fmt.Sprintf("%s/account/%s/2fa%s", c.baseURL, userID, path)
+	httpReq, err := http.NewRequest(method, url, body)
+	if err != nil {
+		return fmt.Errorf("failed to create 2FA request: %w", err)
+	}
+	if reqBody != nil {
+		httpReq.Header.Set("Content-Type", "application/json")
+	}
+	internalServiceSecret := os.Getenv("PROFILE_MANAGER_SERVICE_SECRET")
+	if internalServiceSecret == "" {
+		return fmt.Errorf("PROFILE_MANAGER_SERVICE_SECRET environment variable is not set for internal communication")
+	}
+	httpReq.Header.Set("X-Service-Secret", internalServiceSecret)
+	setClientHeaders(httpReq, client)
+
+	resp, err := c.client.Do(httpReq)
+	if err != nil {
+		if errors.Is(err, context.DeadlineExceeded) || errors.Is(err, os.ErrDeadlineExceeded) || errors.Is(err, syscall.ECONNREFUSED) {
+			return fmt.Errorf("%w: cannot connect to profile manager service at %s", service.ErrProfileManagerDown, c.baseURL)
+		}
+		return fmt.Errorf("failed to send 2FA request to profile manager: %w", err)
+	}
+	defer resp.Body.Close()
+
+	respBody, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return fmt.Errorf("failed to read 2FA response body: %w", err)
+	}
+
+	if resp.StatusCode != http.StatusOK {
+		var errorResp model.ErrorResponse
+		if unmarshalErr := json.Unmarshal(respBody, &errorResp); unmarshalErr != nil || errorResp.Message == "" {
+			utils.Log.Error("Profile Manager returned unexpected error status for 2FA request", zap.Int("status", resp.StatusCode), zap.ByteString("raw_body", respBody))
+			return fmt.Errorf("profile manager 2FA request failed with status %d", resp.StatusCode)
+		}
+		if lockout := lockoutError(resp, errorResp); lockout != nil {
+			return lockout
+		}
+		switch {
+		case resp.StatusCode == http.StatusNotFound:
+			return fmt.Errorf("%w: %s", service.ErrUserNotFound, errorResp.Message)
+		case errorResp.Details == "already_enabled":
+			return fmt.Errorf("%w: %s", service.ErrTwoFAAlreadyEnabled, errorResp.Message)
+		case errorResp.Details == "not_enabled":
+			return fmt.Errorf("%w: %s", service.ErrTwoFANotEnabled, errorResp.Message)
+		case errorResp.Details == "setup_not_started":
+			return fmt.Errorf("%w: %s", service.ErrTwoFASetupNotStarted, errorResp.Message)
+		case errorResp.Details == "invalid_code":
+			return fmt.Errorf("%w: %s", service.ErrInvalidTwoFACode, errorResp.Message)
+		case errorResp.Details == "invalid_password":
+			return fmt.Errorf("%w: %s", service.ErrInvalidCredentials, errorResp.Message)
+		}
+		return fmt.Errorf("profile manager 2FA request failed: %s (%d)", errorResp.Message, resp.StatusCode)
+	}
+
+	if out != nil {
+		if err := json.Unmarshal(respBody, out); err != nil {
+			return fmt.Errorf("failed to unmarshal 2FA response: %w", err)
+		}
+	}
+	return nil
+}
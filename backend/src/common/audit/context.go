@@ -0,0 +1,70 @@
+package audit
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+)
+
+// هدرهایی که apiGateway برای سرویس‌های پشتی می‌فرستد؛ IP و مرورگر کاربر نهایی با هدرهای X-Client-* می‌رسند.
+const (
+	CorrelationHeader     = "X-Correlation-ID"
+	ClientIPHeader        = "X-Client-IP"
+	ClientUserAgentHeader = "X-Client-User-Agent"
+)
+
+// Meta اطلاعات درخواستی که رویداد در آن رخ داده است.
+type Meta struct {
+	CorrelationID string
+	IPAddress     string
+	UserAgent     string
+}
+
+type metaKey struct{}
+
+// MetaKey کلید Meta در context؛ مانند authz.AccessKey در fiber با c.Locals(MetaKey, meta) ثبت می‌شود.
+var MetaKey = metaKey{}
+
+func WithMeta(ctx context.Context, meta Meta) context.Context {
+	return context.WithValue(ctx, MetaKey, meta)
+}
+
+func MetaFrom(ctx context.Context) (Meta, bool) {
+	meta, ok := ctx.Value(MetaKey).(Meta)
+	return meta, ok
+}
+
+// NewCorrelationID شناسه تصادفی ۱۲۸ بیتی برای درخواستی که بدون X-Correlation-ID رسیده است.
+func NewCorrelationID() string {
+	b := make([]byte, 16)
+	if _, err := rand.Read(b); err != nil {
+		return ""
+	}
+	return hex.EncodeToString(b)
+}
+
+// CorrelationIDOr شناسه رسیده از هدر را اگر معتبر باشد (حداکثر ۶۴ نویسه چاپی ASCII) برمی‌گرداند و در غیر این صورت شناسه تازه می‌سازد.
+func CorrelationIDOr(id string) string {
+	if id == "" || len(id) > 64 {
+		return NewCorrelationID()
+	}
+	for i := 0; i < len(id); i++ {
+		if id[i] < 0x21 || id[i] > 0x7e {
+			return NewCorrelationID()
+		}
+	}
+	return id
+}
+
+// Apply فیلدهای خالی رویداد را از Meta پر می‌کند.
+func (m Meta) Apply(e *Event) {
+	if e.CorrelationID == "" {
+		e.CorrelationID = m.CorrelationID
+	}
+	if e.IPAddress == "" {
+		e.IPAddress = m.IPAddress
+	}
+	if e.UserAgent == "" {
+		e.UserAgent = m.UserAgent
+	}
+}
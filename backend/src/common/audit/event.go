@@ -0,0 +1,116 @@
+// Package audit رویدادهای امنیتی و تجاری که در دفتر رویداد crmManager با زنجیره هش ثبت می‌شوند.
+// سرویس‌های دیگر رویدادهای خود را با Sink به crmManager می‌فرستند.
+package audit
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"strconv"
+	"time"
+)
+
+// بخش‌های دفتر رویداد؛ ستون «بخش» در نمایشگر رویدادها
+const (
+	CategoryAuth     = "auth"
+	CategoryRBAC     = "rbac"
+	CategoryCustomer = "customer"
+	CategoryPrice    = "price"
+	CategoryInvoice  = "invoice"
+)
+
+const (
+	ActionLogin           = "auth.login"
+	ActionLoginFailed     = "auth.login_failed"
+	ActionLogout          = "auth.logout"
+	ActionUnlockUser      = "auth.unlock_user"
+	ActionAPIKeyCreate    = "auth.api_key_create"
+	ActionAPIKeyRevoke    = "auth.api_key_revoke"
+	ActionRoleCreate      = "rbac.role_create"
+	ActionRoleUpdate      = "rbac.role_update"
+	ActionRoleDelete      = "rbac.role_delete"
+	ActionUserPermissions = "rbac.user_permissions"
+	ActionCustomerCreate  = "customer.create"
+	ActionCustomerUpdate  = "customer.update"
+	ActionCustomerDelete  = "customer.delete"
+	// ActionPriceOverride تغییر دستی نرخ طلای کسب‌وکار که مبنای قیمت فروش و ارزش‌گذاری موجودی است
+	ActionPriceOverride = "price.override"
+	ActionInvoiceVoid   = "invoice.void"
+)
+
+// Event یک رویداد؛ Before و After تصویر JSON موضوع رویداد پیش و پس از تغییر هستند.
+type Event struct {
+	BusinessID    *uint           `json:"businessId,omitempty"`
+	Action        string          `json:"action"`
+	Category      string          `json:"category"`
+	ActorID       string          `json:"actorId"`
+	ActorName     string          `json:"actorName"`
+	IPAddress     string          `json:"ipAddress"`
+	UserAgent     string          `json:"userAgent"`
+	CorrelationID string          `json:"correlationId"`
+	TargetType    string          `json:"targetType"`
+	TargetID      string          `json:"targetId"`
+	Description   string          `json:"description"`
+	Before        json.RawMessage `json:"before,omitempty"`
+	After         json.RawMessage `json:"after,omitempty"`
+	OccurredAt    time.Time       `json:"occurredAt"`
+}
+
+// Snapshot تصویر JSON یک مقدار برای Before و After؛ مقدار nil تصویری ندارد.
+func Snapshot(v interface{}) json.RawMessage {
+	if v == nil {
+		return nil
+	}
+	data, err := json.Marshal(v)
+	if err != nil || string(data) == "null" {
+		return nil
+	}
+	return data
+}
+
+// Hash هش رویداد شماره seq که به هش رویداد قبلی (prevHash) زنجیر می‌شود؛ تغییر، حذف یا جابه‌جایی هر رویداد
+// هش همه رویدادهای بعدی را باطل می‌کند. زمان با دقت میکروثانیه (دقت timestamp در Postgres) در هش می‌آید.
+func Hash(prevHash string, seq uint64, e Event) string {
+	var business string
+	if e.BusinessID != nil {
+		business = strconv.FormatUint(uint64(*e.BusinessID), 10)
+	}
+	fields := []string{
+		prevHash,
+		strconv.FormatUint(seq, 10),
+		business,
+		e.Action,
+		e.Category,
+		e.ActorID,
+		e.ActorName,
+		e.IPAddress,
+		e.UserAgent,
+		e.CorrelationID,
+		e.TargetType,
+		e.TargetID,
+		e.Description,
+		compact(e.Before),
+		compact(e.After),
+		e.OccurredAt.UTC().Truncate(time.Microsecond).Format(time.RFC3339Nano),
+	}
+	// هر فیلد با طولش نوشته می‌شود تا مرز فیلدها مبهم نباشد.
+	h := sha256.New()
+	for _, field := range fields {
+		h.Write([]byte(strconv.Itoa(len(field))))
+		h.Write([]byte{':'})
+		h.Write([]byte(field))
+	}
+	return hex.EncodeToString(h.Sum(nil))
+}
+
+func compact(raw json.RawMessage) string {
+	if len(raw) == 0 {
+		return ""
+	}
+	var buf bytes.Buffer
+	if err := json.Compact(&buf, raw); err != nil {
+		return string(raw)
+	}
+	return buf.String()
+}
@@ -0,0 +1,88 @@
+package audit
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+)
+
+func sampleEvent() Event {
+	bid := uint(7)
+	return Event{
+		BusinessID:  &bid,
+		Action:      ActionCustomerUpdate,
+		Category:    CategoryCustomer,
+		ActorID:     "3f1c2d4e-0000-4000-8000-000000000001",
+		ActorName:   "admin",
+		IPAddress:   "10.0.0.1",
+		UserAgent:   "test",
+		TargetType:  "customer",
+		TargetID:    "42",
+		Description: "phone changed",
+		Before:      json.RawMessage(`{"phone":"0912"}`),
+		After:       json.RawMessage(`{"phone":"0935"}`),
+		OccurredAt:  time.Date(2024, 3, 20, 10, 30, 0, 123456000, time.UTC),
+	}
+}
+
+func TestHashIsDeterministic(t *testing.T) {
+	e := sampleEvent()
+	if Hash("prev", 1, e) != Hash("prev", 1, e) {
+		t.Fatal("hash of the same event differs between calls")
+	}
+}
+
+func TestHashIgnoresRepresentation(t *testing.T) {
+	base := Hash("prev", 1, sampleEvent())
+	tests := []struct {
+		name   string
+		modify func(*Event)
+	}{
+		{"json whitespace", func(e *Event) { e.Before = json.RawMessage("{ \"phone\" : \"0912\" }") }},
+		{"time zone", func(e *Event) { e.OccurredAt = e.OccurredAt.In(time.FixedZone("IRST", 12600)) }},
+		{"sub-microsecond precision", func(e *Event) { e.OccurredAt = e.OccurredAt.Add(999 * time.Nanosecond) }},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			e := sampleEvent()
+			tt.modify(&e)
+			if got := Hash("prev", 1, e); got != base {
+				t.Errorf("hash changed to %s, want %s", got, base)
+			}
+		})
+	}
+}
+
+func TestHashDetectsChanges(t *testing.T) {
+	base := Hash("prev", 1, sampleEvent())
+	otherBID := uint(8)
+	tests := []struct {
+		name     string
+		prevHash string
+		seq      uint64
+		modify   func(*Event)
+	}{
+		{"previous hash", "other", 1, func(e *Event) {}},
+		{"sequence", "prev", 2, func(e *Event) {}},
+		{"business", "prev", 1, func(e *Event) { e.BusinessID = &otherBID }},
+		{"no business", "prev", 1, func(e *Event) { e.BusinessID = nil }},
+		{"action", "prev", 1, func(e *Event) { e.Action = ActionCustomerDelete }},
+		{"actor", "prev", 1, func(e *Event) { e.ActorID = "someone-else" }},
+		{"target", "prev", 1, func(e *Event) { e.TargetID = "43" }},
+		{"description", "prev", 1, func(e *Event) { e.Description = "phone removed" }},
+		{"before", "prev", 1, func(e *Event) { e.Before = json.RawMessage(`{"phone":"0913"}`) }},
+		{"after", "prev", 1, func(e *Event) { e.After = nil }},
+		{"time", "prev", 1, func(e *Event) { e.OccurredAt = e.OccurredAt.Add(time.Microsecond) }},
+		// مرز فیلدها نباید با جابه‌جا کردن متن بین دو فیلد مجاور مبهم شود.
+		{"field boundary", "prev", 1, func(e *Event) { e.ActorName, e.IPAddress = "admin10.0.0.1", "" }},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			e := sampleEvent()
+			tt.modify(&e)
+			if got := Hash(tt.prevHash, tt.seq, e); got == base {
+				t.Errorf("hash did not change")
+			}
+		})
+	}
+}
@@ -0,0 +1,68 @@
+package audit
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+)
+
+// IngestPath مسیر داخلی crmManager برای ثبت رویداد سرویس‌های دیگر.
+const IngestPath = "/internal/audit/events"
+
+// Sink رویداد را در دفتر رویداد ثبت می‌کند.
+type Sink interface {
+	Record(ctx context.Context, event Event) error
+}
+
+type httpSink struct {
+	baseURL string
+	secret  string
+	client  *http.Client
+}
+
+// NewHTTPSink رویدادها را با هدر X-Service-Secret به crmManager می‌فرستد.
+func NewHTTPSink(baseURL, serviceSecret string) (Sink, error) {
+	if baseURL == "" {
+		return nil, fmt.Errorf("crm manager base URL cannot be empty for audit sink")
+	}
+	if serviceSecret == "" {
+		return nil, fmt.Errorf("service secret cannot be empty for audit sink")
+	}
+	return &httpSink{
+		baseURL: strings.TrimRight(baseURL, "/"),
+		secret:  serviceSecret,
+		client:  &http.Client{Timeout: 5 * time.Second},
+	}, nil
+}
+
+func (s *httpSink) Record(ctx context.Context, event Event) error {
+	body, err := json.Marshal(event)
+	if err != nil {
+		return fmt.Errorf("failed to marshal audit event: %w", err)
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, s.baseURL+IngestPath, bytes.NewReader(body))
+	if err != nil {
+		return fmt.Errorf("failed to create audit request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("X-Service-Secret", s.secret)
+	if event.CorrelationID != "" {
+		req.Header.Set(CorrelationHeader, event.CorrelationID)
+	}
+
+	resp, err := s.client.Do(req)
+	if err != nil {
+		return fmt.Errorf("failed to send audit event: %w", err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusCreated {
+		respBody, _ := io.ReadAll(resp.Body)
+		return fmt.Errorf("audit event rejected with status %d: %s", resp.StatusCode, string(respBody))
+	}
+	return nil
+}
@@ -0,0 +1,149 @@
+package authz
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// ErrPolicyUnavailable هنوز هیچ نسخه‌ای از سیاست دریافت نشده است؛ درخواست‌ها باید رد شوند نه اینکه مجاز فرض شوند.
+var ErrPolicyUnavailable = errors.New("authorization policy is not loaded yet")
+
+// PolicyPath مسیر داخلی profileManager برای دریافت سیاست.
+const PolicyPath = "/authz/policy"
+
+// Source اگر knownVersion هنوز جدیدترین نسخه باشد changed برابر false و policy برابر nil است.
+type Source interface {
+	FetchPolicy(ctx context.Context, knownVersion int64) (policy *Policy, changed bool, err error)
+}
+
+type httpSource struct {
+	baseURL string
+	secret  string
+	client  *http.Client
+}
+
+// NewHTTPSource سیاست را از profileManager با هدر X-Service-Secret می‌گیرد و نسخه فعلی را در If-None-Match می‌فرستد.
+func NewHTTPSource(baseURL, serviceSecret string) (Source, error) {
+	if baseURL == "" {
+		return nil, fmt.Errorf("profile manager base URL cannot be empty for policy source")
+	}
+	if serviceSecret == "" {
+		return nil, fmt.Errorf("service secret cannot be empty for policy source")
+	}
+	return &httpSource{
+		baseURL: strings.TrimRight(baseURL, "/"),
+		secret:  serviceSecret,
+		client:  &http.Client{Timeout: 5 * time.Second},
+	}, nil
+}
+
+func (s *httpSource) FetchPolicy(ctx context.Context, knownVersion int64) (*Policy, bool, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, s.baseURL+PolicyPath, nil)
+	if err != nil {
+		return nil, false, fmt.Errorf("failed to create policy request: %w", err)
+	}
+	req.Header.Set("X-Service-Secret", s.secret)
+	if knownVersion > 0 {
+		req.Header.Set("If-None-Match", ETag(knownVersion))
+	}
+
+	resp, err := s.client.Do(req)
+	if err != nil {
+		return nil, false, fmt.Errorf("failed to fetch policy: %w", err)
+	}
+	defer resp.Body.Close()
+
+	switch resp.StatusCode {
+	case http.StatusNotModified:
+		return nil, false, nil
+	case http.StatusOK:
+		var policy Policy
+		if err := json.NewDecoder(resp.Body).Decode(&policy); err != nil {
+			return nil, false, fmt.Errorf("failed to decode policy: %w", err)
+		}
+		return &policy, true, nil
+	default:
+		return nil, false, fmt.Errorf("policy request failed with status %d", resp.StatusCode)
+	}
+}
+
+// ETag شناسه نسخه در هدرهای ETag و If-None-Match.
+func ETag(version int64) string {
+	return `"` + strconv.FormatInt(version, 10) + `"`
+}
+
+// Cache آخرین نسخه سیاست را در حافظه نگه می‌دارد تا بررسی مجوز در هر درخواست به شبکه نیاز نداشته باشد.
+type Cache struct {
+	source Source
+	mu     sync.RWMutex
+	policy *Policy
+}
+
+func NewCache(source Source) (*Cache, error) {
+	if source == nil {
+		return nil, fmt.Errorf("policy source cannot be nil for Cache")
+	}
+	return &Cache{source: source}, nil
+}
+
+// Policy نسخه فعلی؛ تا اولین دریافت موفق ErrPolicyUnavailable برمی‌گرداند.
+func (c *Cache) Policy() (*Policy, error) {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	if c.policy == nil {
+		return nil, ErrPolicyUnavailable
+	}
+	return c.policy, nil
+}
+
+func (c *Cache) Allows(userID string, roles []string, permission string) (bool, error) {
+	policy, err := c.Policy()
+	if err != nil {
+		return false, err
+	}
+	return policy.Allows(userID, roles, permission), nil
+}
+
+// Refresh فقط وقتی نسخه تغییر کرده باشد سیاست جدید جایگزین می‌شود.
+func (c *Cache) Refresh(ctx context.Context) error {
+	var known int64
+	c.mu.RLock()
+	if c.policy != nil {
+		known = c.policy.Version
+	}
+	c.mu.RUnlock()
+
+	policy, changed, err := c.source.FetchPolicy(ctx, known)
+	if err != nil || !changed {
+		return err
+	}
+	c.mu.Lock()
+	if c.policy == nil || policy.Version >= c.policy.Version {
+		c.policy = policy
+	}
+	c.mu.Unlock()
+	return nil
+}
+
+// Run تا لغو ctx هر interval یک بار Refresh را صدا می‌زند؛ خطاها به onError داده می‌شوند و نسخه قبلی حفظ می‌شود.
+func (c *Cache) Run(ctx context.Context, interval time.Duration, onError func(error)) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		if err := c.Refresh(ctx); err != nil && onError != nil {
+			onError(err)
+		}
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
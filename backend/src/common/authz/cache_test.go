@@ -0,0 +1,166 @@
+package authz
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+)
+
+// policyServer serves the policy like profileManager: 304 when If-None-Match names the current version.
+type policyServer struct {
+	mu          sync.Mutex
+	policy      Policy
+	status      int
+	lastIfMatch string
+	secret      string
+}
+
+func (s *policyServer) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.secret = r.Header.Get("X-Service-Secret")
+	s.lastIfMatch = r.Header.Get("If-None-Match")
+	if r.URL.Path != PolicyPath {
+		http.NotFound(w, r)
+		return
+	}
+	if s.status != 0 {
+		w.WriteHeader(s.status)
+		return
+	}
+	if s.lastIfMatch == ETag(s.policy.Version) {
+		w.WriteHeader(http.StatusNotModified)
+		return
+	}
+	w.Header().Set("ETag", ETag(s.policy.Version))
+	json.NewEncoder(w).Encode(s.policy)
+}
+
+func (s *policyServer) set(policy Policy, status int) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.policy, s.status = policy, status
+}
+
+func newTestCache(t *testing.T, server *policyServer) *Cache {
+	t.Helper()
+	ts := httptest.NewServer(server)
+	t.Cleanup(ts.Close)
+	source, err := NewHTTPSource(ts.URL+"/", "secret")
+	if err != nil {
+		t.Fatalf("NewHTTPSource: %v", err)
+	}
+	cache, err := NewCache(source)
+	if err != nil {
+		t.Fatalf("NewCache: %v", err)
+	}
+	return cache
+}
+
+func TestETag(t *testing.T) {
+	if got := ETag(42); got != `"42"` {
+		t.Errorf("ETag(42) = %s", got)
+	}
+}
+
+func TestCacheUnavailableUntilFirstFetch(t *testing.T) {
+	server := &policyServer{status: http.StatusServiceUnavailable}
+	cache := newTestCache(t, server)
+
+	if err := cache.Refresh(context.Background()); err == nil {
+		t.Fatal("Refresh succeeded on a failing source")
+	}
+	if _, err := cache.Allows("u1", []string{SuperRole}, "crm:cheque:view"); !errors.Is(err, ErrPolicyUnavailable) {
+		t.Fatalf("Allows before the first fetch: error = %v, want ErrPolicyUnavailable", err)
+	}
+	if _, err := cache.Resolve(Rules{}, "u1", nil, "crm:cheque:view"); !errors.Is(err, ErrPolicyUnavailable) {
+		t.Fatalf("Resolve before the first fetch: error = %v, want ErrPolicyUnavailable", err)
+	}
+}
+
+func TestCacheRefreshUsesETag(t *testing.T) {
+	server := &policyServer{policy: Policy{Version: 1, Roles: map[string][]string{"seller": {"crm:sale:create"}}}}
+	cache := newTestCache(t, server)
+	ctx := context.Background()
+
+	if err := cache.Refresh(ctx); err != nil {
+		t.Fatalf("first Refresh: %v", err)
+	}
+	if server.lastIfMatch != "" {
+		t.Errorf("first Refresh sent If-None-Match %s", server.lastIfMatch)
+	}
+	if server.secret != "secret" {
+		t.Errorf("X-Service-Secret = %q", server.secret)
+	}
+	if ok, err := cache.Allows("u1", []string{"seller"}, "crm:sale:create"); err != nil || !ok {
+		t.Fatalf("Allows after first Refresh = %v, %v", ok, err)
+	}
+
+	// The same version answers 304 and the cached policy stays in place.
+	if err := cache.Refresh(ctx); err != nil {
+		t.Fatalf("unchanged Refresh: %v", err)
+	}
+	if server.lastIfMatch != ETag(1) {
+		t.Errorf("If-None-Match = %s, want %s", server.lastIfMatch, ETag(1))
+	}
+	if p, _ := cache.Policy(); p.Version != 1 {
+		t.Fatalf("Version after 304 = %d, want 1", p.Version)
+	}
+
+	server.set(Policy{Version: 2, Roles: map[string][]string{"seller": {}}}, 0)
+	if err := cache.Refresh(ctx); err != nil {
+		t.Fatalf("Refresh to version 2: %v", err)
+	}
+	if ok, _ := cache.Allows("u1", []string{"seller"}, "crm:sale:create"); ok {
+		t.Fatal("permission removed in version 2 is still allowed")
+	}
+
+	// A failed fetch keeps the last good version.
+	server.set(Policy{}, http.StatusInternalServerError)
+	if err := cache.Refresh(ctx); err == nil {
+		t.Fatal("Refresh succeeded on a failing source")
+	}
+	if p, err := cache.Policy(); err != nil || p.Version != 2 {
+		t.Fatalf("Policy after a failed Refresh = %+v, %v", p, err)
+	}
+}
+
+// staticSource returns a fixed policy, so a stale response can be simulated.
+type staticSource struct{ policy *Policy }
+
+func (s staticSource) FetchPolicy(ctx context.Context, knownVersion int64) (*Policy, bool, error) {
+	return s.policy, true, nil
+}
+
+func TestCacheKeepsNewerVersion(t *testing.T) {
+	cache, err := NewCache(staticSource{policy: &Policy{Version: 5}})
+	if err != nil {
+		t.Fatalf("NewCache: %v", err)
+	}
+	if err := cache.Refresh(context.Background()); err != nil {
+		t.Fatalf("Refresh: %v", err)
+	}
+	cache.source = staticSource{policy: &Policy{Version: 4}}
+	if err := cache.Refresh(context.Background()); err != nil {
+		t.Fatalf("stale Refresh: %v", err)
+	}
+	if p, _ := cache.Policy(); p.Version != 5 {
+		t.Errorf("Version after a stale response = %d, want 5", p.Version)
+	}
+}
+
+func TestNewHTTPSourceValidates(t *testing.T) {
+	if _, err := NewHTTPSource("", "secret"); err == nil {
+		t.Error("NewHTTPSource accepted an empty base URL")
+	}
+	if _, err := NewHTTPSource("http://profile", ""); err == nil {
+		t.Error("NewHTTPSource accepted an empty secret")
+	}
+	if _, err := NewCache(nil); err == nil {
+		t.Error("NewCache accepted a nil source")
+	}
+}
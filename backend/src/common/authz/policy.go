@@ -0,0 +1,93 @@
+// Package authz سیاست دسترسی (نقش‌ها، مجوزها و استثناهای هر کاربر) که مالک آن profileManager است.
+// سرویس‌های دیگر نسخه‌ای کش‌شده از آن را نگه می‌دارند و با تغییر Version آن را دوباره می‌گیرند.
+package authz
+
+import "strings"
+
+// SuperRole نقشی که بدون نگاه به جدول مجوزها به همه چیز دسترسی دارد.
+const SuperRole = "admin"
+
+// Overrides مجوزهایی که جدا از نقش‌ها به یک کاربر داده یا از او گرفته شده‌اند.
+type Overrides struct {
+	Grant []string `json:"grant"`
+	Deny  []string `json:"deny"`
+}
+
+// Policy تصویر کامل RBAC در یک نسخه مشخص.
+type Policy struct {
+	Version       int64                `json:"version"`
+	Roles         map[string][]string  `json:"roles"`
+	UserOverrides map[string]Overrides `json:"user_overrides"`
+}
+
+func (p *Policy) IsRole(role string) bool {
+	_, ok := p.Roles[role]
+	return ok
+}
+
+// Allows مجوز Deny کاربر بر مجوز نقش‌ها مقدم است؛ Grant کاربر به مجوز نقش‌ها اضافه می‌شود.
+func (p *Policy) Allows(userID string, roles []string, permission string) bool {
+	for _, role := range roles {
+		if role == SuperRole {
+			return true
+		}
+	}
+	overrides := p.UserOverrides[userID]
+	if matchesAny(overrides.Deny, permission) {
+		return false
+	}
+	if matchesAny(overrides.Grant, permission) {
+		return true
+	}
+	for _, role := range roles {
+		if matchesAny(p.Roles[role], permission) {
+			return true
+		}
+	}
+	return false
+}
+
+// Effective فهرست مجوزهای نهایی کاربر برای نمایش در تنظیمات.
+func (p *Policy) Effective(userID string, roles []string) []string {
+	seen := make(map[string]bool)
+	var perms []string
+	add := func(list []string) {
+		for _, perm := range list {
+			if !seen[perm] {
+				seen[perm] = true
+				perms = append(perms, perm)
+			}
+		}
+	}
+	for _, role := range roles {
+		add(p.Roles[role])
+	}
+	add(p.UserOverrides[userID].Grant)
+
+	denied := p.UserOverrides[userID].Deny
+	effective := perms[:0]
+	for _, perm := range perms {
+		if !matchesAny(denied, perm) {
+			effective = append(effective, perm)
+		}
+	}
+	return effective
+}
+
+// Covers آیا فهرست مجوزهای داده‌شده (مثلاً مجوزهای یک کلید API) permission را پوشش می‌دهد.
+func Covers(granted []string, permission string) bool {
+	return matchesAny(granted, permission)
+}
+
+// matchesAny مجوزی مثل "crm:*" همه مجوزهای منبع crm را پوشش می‌دهد.
+func matchesAny(granted []string, permission string) bool {
+	for _, perm := range granted {
+		if perm == permission {
+			return true
+		}
+		if strings.HasSuffix(perm, ":*") && strings.HasPrefix(permission, strings.TrimSuffix(perm, "*")) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,79 @@
+package authz
+
+import (
+	"reflect"
+	"testing"
+)
+
+func samplePolicy() *Policy {
+	return &Policy{
+		Version: 3,
+		Roles: map[string][]string{
+			"accountant": {"crm:cheque:view", "crm:cheque:create", "crm:report:*"},
+			"seller":     {"crm:sale:create"},
+		},
+		UserOverrides: map[string]Overrides{
+			"u-granted": {Grant: []string{"crm:sale:view_all"}},
+			"u-denied":  {Deny: []string{"crm:cheque:create", "crm:report:*"}},
+		},
+	}
+}
+
+func TestPolicyAllows(t *testing.T) {
+	p := samplePolicy()
+	tests := []struct {
+		name       string
+		userID     string
+		roles      []string
+		permission string
+		want       bool
+	}{
+		{"role permission", "u1", []string{"accountant"}, "crm:cheque:view", true},
+		{"wildcard role permission", "u1", []string{"accountant"}, "crm:report:trial_balance", true},
+		{"wildcard does not cover other resources", "u1", []string{"accountant"}, "crm:reports", false},
+		{"permission of another role", "u1", []string{"seller"}, "crm:cheque:view", false},
+		{"any of several roles", "u1", []string{"seller", "accountant"}, "crm:cheque:create", true},
+		{"unknown role", "u1", []string{"ghost"}, "crm:cheque:view", false},
+		{"no roles", "u1", nil, "crm:cheque:view", false},
+		{"super role", "u1", []string{SuperRole}, "anything:at:all", true},
+		{"super role ignores deny", "u-denied", []string{SuperRole}, "crm:cheque:create", true},
+		{"grant override", "u-granted", []string{"seller"}, "crm:sale:view_all", true},
+		{"grant is per user", "u1", []string{"seller"}, "crm:sale:view_all", false},
+		{"deny beats role", "u-denied", []string{"accountant"}, "crm:cheque:create", false},
+		{"wildcard deny beats role", "u-denied", []string{"accountant"}, "crm:report:balance_sheet", false},
+		{"deny leaves other permissions", "u-denied", []string{"accountant"}, "crm:cheque:view", true},
+	}
+	for _, tt := range tests {
+		if got := p.Allows(tt.userID, tt.roles, tt.permission); got != tt.want {
+			t.Errorf("%s: Allows(%q, %v, %q) = %v, want %v", tt.name, tt.userID, tt.roles, tt.permission, got, tt.want)
+		}
+	}
+}
+
+func TestPolicyEffective(t *testing.T) {
+	p := samplePolicy()
+	got := p.Effective("u-denied", []string{"accountant", "seller"})
+	want := []string{"crm:cheque:view", "crm:sale:create"}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("Effective = %v, want %v", got, want)
+	}
+	got = p.Effective("u-granted", []string{"seller", "seller"})
+	want = []string{"crm:sale:create", "crm:sale:view_all"}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("Effective with grant = %v, want %v", got, want)
+	}
+}
+
+func TestCovers(t *testing.T) {
+	granted := []string{"crm:customer:view", "crm:inventory:*"}
+	for permission, want := range map[string]bool{
+		"crm:customer:view":       true,
+		"crm:customer:edit":       false,
+		"crm:inventory:valuation": true,
+		"crm:inventoryx":          false,
+	} {
+		if got := Covers(granted, permission); got != want {
+			t.Errorf("Covers(%q) = %v, want %v", permission, got, want)
+		}
+	}
+}
@@ -0,0 +1,110 @@
+package authz
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+)
+
+// OwnerScope ردیف‌های نتیجه یک مجوز را به ردیف‌هایی محدود می‌کند که ستون Column آن‌ها به کاربر تعلق دارد؛
+// نگاشت شناسه کاربر به مقدار ستون با خود سرویس است و دارنده مجوز Bypass همه ردیف‌ها را می‌بیند.
+type OwnerScope struct {
+	Column string
+	Bypass string
+}
+
+// Rules سیاست‌های ردیف و فیلد هر سرویس که به ازای مجوز تعریف می‌شوند.
+// Scopes بر اساس مجوز مسیر و Fields بر اساس مجوزی است که دیدن آن فیلدهای JSON را مجاز می‌کند.
+type Rules struct {
+	Scopes map[string]OwnerScope
+	Fields map[string][]string
+}
+
+// Access نتیجه اعمال Rules برای یک کاربر؛ OwnerColumn خالی یعنی محدودیت ردیفی وجود ندارد.
+// BusinessID را Rules تعیین نمی‌کند: سرویس آن را از توکنی که به یک کسب‌وکار محدود است (مانند کلید API) پر می‌کند.
+type Access struct {
+	OwnerColumn string
+	OwnerID     string
+	BusinessID  uint
+	Hidden      []string
+}
+
+func (a Access) Restricted() bool {
+	return a.OwnerColumn != "" || a.BusinessID != 0 || len(a.Hidden) > 0
+}
+
+// Resolve محدودیت‌های کاربر برای درخواستی که با مجوز permission پذیرفته شده است.
+func (r Rules) Resolve(p *Policy, userID string, roles []string, permission string) Access {
+	var access Access
+	if scope, ok := r.Scopes[permission]; ok && (scope.Bypass == "" || !p.Allows(userID, roles, scope.Bypass)) {
+		access.OwnerColumn = scope.Column
+		access.OwnerID = userID
+	}
+	for perm, fields := range r.Fields {
+		if !p.Allows(userID, roles, perm) {
+			access.Hidden = append(access.Hidden, fields...)
+		}
+	}
+	return access
+}
+
+// Resolve مانند Rules.Resolve روی نسخه کش‌شده؛ تا اولین دریافت سیاست ErrPolicyUnavailable برمی‌گرداند.
+func (c *Cache) Resolve(rules Rules, userID string, roles []string, permission string) (Access, error) {
+	policy, err := c.Policy()
+	if err != nil {
+		return Access{}, err
+	}
+	return rules.Resolve(policy, userID, roles, permission), nil
+}
+
+type accessKey struct{}
+
+// AccessKey کلید Access در context؛ در fiber کافی است با c.Locals(AccessKey, access) ثبت شود
+// چون RequestCtx مقادیر Locals را از Value برمی‌گرداند و handlerها c.Context() را به پایین می‌فرستند.
+var AccessKey = accessKey{}
+
+func WithAccess(ctx context.Context, access Access) context.Context {
+	return context.WithValue(ctx, AccessKey, access)
+}
+
+// AccessFrom اگر درخواست از مسیر دارای سیاست نیامده باشد (مثلاً کارهای داخلی) ok برابر false است.
+func AccessFrom(ctx context.Context) (Access, bool) {
+	access, ok := ctx.Value(AccessKey).(Access)
+	return access, ok
+}
+
+// Redact کلیدهای hidden را در هر عمق از بدنه JSON حذف می‌کند؛ اعداد بدون تغییر دقت بازنویسی می‌شوند.
+func Redact(body []byte, hidden []string) ([]byte, error) {
+	if len(hidden) == 0 || len(bytes.TrimSpace(body)) == 0 {
+		return body, nil
+	}
+	decoder := json.NewDecoder(bytes.NewReader(body))
+	decoder.UseNumber()
+	var doc interface{}
+	if err := decoder.Decode(&doc); err != nil {
+		return nil, err
+	}
+	drop := make(map[string]bool, len(hidden))
+	for _, field := range hidden {
+		drop[field] = true
+	}
+	return json.Marshal(redactValue(doc, drop))
+}
+
+func redactValue(v interface{}, drop map[string]bool) interface{} {
+	switch node := v.(type) {
+	case map[string]interface{}:
+		for key, child := range node {
+			if drop[key] {
+				delete(node, key)
+				continue
+			}
+			node[key] = redactValue(child, drop)
+		}
+	case []interface{}:
+		for i, child := range node {
+			node[i] = redactValue(child, drop)
+		}
+	}
+	return v
+}
@@ -0,0 +1,96 @@
+package authz
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"reflect"
+	"sort"
+	"testing"
+)
+
+var sampleRules = Rules{
+	Scopes: map[string]OwnerScope{
+		"crm:sale:view": {Column: "salesperson_id", Bypass: "crm:sale:view_all"},
+		"crm:task:view": {Column: "assignee_id"},
+	},
+	Fields: map[string][]string{
+		"crm:sale:view_cost": {"cost", "margin"},
+	},
+}
+
+func TestRulesResolve(t *testing.T) {
+	p := &Policy{
+		Roles: map[string][]string{
+			"seller":  {"crm:sale:view", "crm:task:view"},
+			"manager": {"crm:sale:view", "crm:sale:view_all", "crm:sale:view_cost"},
+		},
+	}
+	tests := []struct {
+		name       string
+		roles      []string
+		permission string
+		want       Access
+	}{
+		{"scoped without bypass", []string{"seller"}, "crm:sale:view", Access{OwnerColumn: "salesperson_id", OwnerID: "u1", Hidden: []string{"cost", "margin"}}},
+		{"bypass lifts the scope", []string{"manager"}, "crm:sale:view", Access{}},
+		{"scope without bypass permission applies to everyone", []string{"manager", "seller"}, "crm:task:view", Access{OwnerColumn: "assignee_id", OwnerID: "u1"}},
+		{"unscoped permission", []string{"seller"}, "crm:customer:view", Access{Hidden: []string{"cost", "margin"}}},
+		{"super role sees everything", []string{SuperRole}, "crm:sale:view", Access{}},
+	}
+	for _, tt := range tests {
+		got := sampleRules.Resolve(p, "u1", tt.roles, tt.permission)
+		sort.Strings(got.Hidden)
+		if !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("%s: Resolve = %+v, want %+v", tt.name, got, tt.want)
+		}
+		if got.Restricted() != (tt.want.OwnerColumn != "" || len(tt.want.Hidden) > 0) {
+			t.Errorf("%s: Restricted = %v", tt.name, got.Restricted())
+		}
+	}
+}
+
+func TestAccessRestrictedByBusiness(t *testing.T) {
+	if !(Access{BusinessID: 4}).Restricted() {
+		t.Error("Access limited to a business is not Restricted")
+	}
+}
+
+func TestAccessContext(t *testing.T) {
+	if _, ok := AccessFrom(context.Background()); ok {
+		t.Fatal("AccessFrom on a bare context reported ok")
+	}
+	want := Access{OwnerColumn: "salesperson_id", OwnerID: "u1"}
+	got, ok := AccessFrom(WithAccess(context.Background(), want))
+	if !ok || !reflect.DeepEqual(got, want) {
+		t.Fatalf("AccessFrom = %+v, %v; want %+v, true", got, ok, want)
+	}
+}
+
+func TestRedact(t *testing.T) {
+	body := []byte(`{"data":[{"id":1,"cost":"12.50","items":[{"margin":3,"weight":1.234567890123456789}]}],"cost":7}`)
+	got, err := Redact(body, []string{"cost", "margin"})
+	if err != nil {
+		t.Fatalf("Redact: %v", err)
+	}
+	var doc, want interface{}
+	if err := json.Unmarshal(got, &doc); err != nil {
+		t.Fatalf("redacted body is not JSON: %v", err)
+	}
+	if err := json.Unmarshal([]byte(`{"data":[{"id":1,"items":[{"weight":1.234567890123456789}]}]}`), &want); err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(doc, want) {
+		t.Errorf("Redact = %s", got)
+	}
+	if !bytes.Contains(got, []byte("1.234567890123456789")) {
+		t.Errorf("Redact changed number precision: %s", got)
+	}
+
+	if out, err := Redact(body, nil); err != nil || string(out) != string(body) {
+		t.Errorf("Redact without hidden fields = %s, %v", out, err)
+	}
+	if _, err := Redact([]byte(`{"cost":`), []string{"cost"}); err == nil {
+		t.Error("Redact accepted a truncated body")
+	}
+}
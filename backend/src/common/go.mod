@@ -0,0 +1,5 @@
+module common-gold
+
+go 1.24.3
+
+require github.com/shopspring/decimal v1.4.0
@@ -0,0 +1,59 @@
+package money
+
+import (
+	"bytes"
+	"fmt"
+	"strings"
+
+	"github.com/shopspring/decimal"
+)
+
+// marshalFixed عدد JSON با همه ارقام؛ متن عدد دقیق است و تبدیل آن به float بر عهده مصرف‌کننده است.
+func marshalFixed(d decimal.Decimal) []byte {
+	return []byte(d.String())
+}
+
+// unmarshalFixed عدد یا رشته عددی را می‌پذیرد؛ null و رشته خالی صفر هستند.
+func unmarshalFixed(data []byte) (decimal.Decimal, error) {
+	data = bytes.TrimSpace(data)
+	if bytes.Equal(data, []byte("null")) {
+		return decimal.Zero, nil
+	}
+	s := string(data)
+	if len(data) >= 2 && data[0] == '"' && data[len(data)-1] == '"' {
+		s = s[1 : len(s)-1]
+	}
+	return parseFixed(s)
+}
+
+func parseFixed(s string) (decimal.Decimal, error) {
+	s = strings.TrimSpace(s)
+	if s == "" {
+		return decimal.Zero, nil
+	}
+	d, err := decimal.NewFromString(s)
+	if err != nil {
+		return decimal.Zero, fmt.Errorf("invalid decimal value %q", s)
+	}
+	return d, nil
+}
+
+// scanFixed ستون NUMERIC به صورت متن خوانده می‌شود؛ float64 و int64 برای ستون‌هایی است که هنوز مهاجرت نکرده‌اند.
+func scanFixed(src interface{}) (decimal.Decimal, error) {
+	switch v := src.(type) {
+	case nil:
+		return decimal.Zero, nil
+	case []byte:
+		return parseFixed(string(v))
+	case string:
+		return parseFixed(v)
+	case float64:
+		return decimal.NewFromFloat(v), nil
+	case float32:
+		return decimal.NewFromFloat32(v), nil
+	case int64:
+		return decimal.NewFromInt(v), nil
+	default:
+		return decimal.Zero, fmt.Errorf("cannot scan %T into a decimal value", src)
+	}
+}
@@ -0,0 +1,132 @@
+package money
+
+import (
+	"database/sql/driver"
+
+	"github.com/shopspring/decimal"
+)
+
+// ForeignScale تعداد رقم اعشار مبالغ ارزی و نرخ تبدیل ارز
+const ForeignScale = 6
+
+// Foreign مبلغ به یک ارز خارجی؛ ارز آن را فیلد کنار آن (مثلا CurrencyID) مشخص می‌کند.
+type Foreign struct {
+	d decimal.Decimal
+}
+
+func NewForeign(f float64) Foreign {
+	return Foreign{d: decimal.NewFromFloat(f).Round(ForeignScale)}
+}
+
+func ForeignFromDecimal(d decimal.Decimal) Foreign {
+	return Foreign{d: d.Round(ForeignScale)}
+}
+
+func ParseForeign(s string) (Foreign, error) {
+	d, err := parseFixed(s)
+	if err != nil {
+		return Foreign{}, err
+	}
+	return ForeignFromDecimal(d), nil
+}
+
+func (f Foreign) Add(o Foreign) Foreign        { return Foreign{d: f.d.Add(o.d)} }
+func (f Foreign) Sub(o Foreign) Foreign        { return Foreign{d: f.d.Sub(o.d)} }
+func (f Foreign) Neg() Foreign                 { return Foreign{d: f.d.Neg()} }
+func (f Foreign) Abs() Foreign                 { return Foreign{d: f.d.Abs()} }
+func (f Foreign) Cmp(o Foreign) int            { return f.d.Cmp(o.d) }
+func (f Foreign) Sign() int                    { return f.d.Sign() }
+func (f Foreign) IsZero() bool                 { return f.d.IsZero() }
+func (f Foreign) IsPositive() bool             { return f.d.IsPositive() }
+func (f Foreign) IsNegative() bool             { return f.d.IsNegative() }
+func (f Foreign) Decimal() decimal.Decimal     { return f.d }
+func (f Foreign) Float64() float64             { return f.d.InexactFloat64() }
+func (f Foreign) String() string               { return f.d.String() }
+func (f Foreign) MarshalJSON() ([]byte, error) { return marshalFixed(f.d), nil }
+
+// ToToman ارزش تومانی مبلغ ارزی با نرخ rate، گرد شده به دقت تومانی
+func (f Foreign) ToToman(rate Rate) Amount {
+	return AmountFromDecimal(f.d.Mul(rate.d))
+}
+
+func (f *Foreign) UnmarshalJSON(data []byte) error {
+	d, err := unmarshalFixed(data)
+	if err != nil {
+		return err
+	}
+	*f = ForeignFromDecimal(d)
+	return nil
+}
+
+func (f Foreign) Value() (driver.Value, error) { return f.d.String(), nil }
+
+func (f *Foreign) Scan(src interface{}) error {
+	d, err := scanFixed(src)
+	if err != nil {
+		return err
+	}
+	*f = ForeignFromDecimal(d)
+	return nil
+}
+
+func (Foreign) GormDataType() string { return "numeric(24,6)" }
+
+// Rate نرخ تبدیل یک واحد ارز خارجی به تومان
+type Rate struct {
+	d decimal.Decimal
+}
+
+func NewRate(f float64) Rate {
+	return Rate{d: decimal.NewFromFloat(f).Round(ForeignScale)}
+}
+
+func RateFromDecimal(d decimal.Decimal) Rate {
+	return Rate{d: d.Round(ForeignScale)}
+}
+
+func ParseRate(s string) (Rate, error) {
+	d, err := parseFixed(s)
+	if err != nil {
+		return Rate{}, err
+	}
+	return RateFromDecimal(d), nil
+}
+
+func (r Rate) Cmp(o Rate) int               { return r.d.Cmp(o.d) }
+func (r Rate) Sign() int                    { return r.d.Sign() }
+func (r Rate) IsZero() bool                 { return r.d.IsZero() }
+func (r Rate) IsPositive() bool             { return r.d.IsPositive() }
+func (r Rate) Decimal() decimal.Decimal     { return r.d }
+func (r Rate) Float64() float64             { return r.d.InexactFloat64() }
+func (r Rate) String() string               { return r.d.String() }
+func (r Rate) MarshalJSON() ([]byte, error) { return marshalFixed(r.d), nil }
+
+func (r *Rate) UnmarshalJSON(data []byte) error {
+	d, err := unmarshalFixed(data)
+	if err != nil {
+		return err
+	}
+	*r = RateFromDecimal(d)
+	return nil
+}
+
+func (r Rate) Value() (driver.Value, error) { return r.d.String(), nil }
+
+func (r *Rate) Scan(src interface{}) error {
+	d, err := scanFixed(src)
+	if err != nil {
+		return err
+	}
+	*r = RateFromDecimal(d)
+	return nil
+}
+
+func (Rate) GormDataType() string { return "numeric(24,6)" }
+
+// ToForeign معادل ارزی مبلغ تومانی با نرخ rate؛ نرخ صفر معادلی ندارد و صفر برمی‌گرداند.
+func (a Amount) ToForeign(rate Rate) Foreign {
+	if rate.d.IsZero() {
+		return Foreign{}
+	}
+	return ForeignFromDecimal(a.d.Div(rate.d))
+}
@@ -0,0 +1,141 @@
+// Package money مقادیر پولی و وزنی با دقت ثابت؛ برخلاف float64 جمع آن‌ها خطای گرد کردن انباشته نمی‌کند.
+// هر دو نوع در JSON به صورت عدد (و در ورودی به صورت عدد یا رشته) و در Postgres به صورت NUMERIC ذخیره می‌شوند.
+package money
+
+import (
+	"database/sql/driver"
+
+	"github.com/shopspring/decimal"
+)
+
+const (
+	// AmountScale تعداد رقم اعشار مبالغ تومانی
+	AmountScale = 2
+	// WeightScale تعداد رقم اعشار وزن به گرم (میلی‌گرم)
+	WeightScale = 3
+)
+
+// Amount مبلغ به تومان
+type Amount struct {
+	d decimal.Decimal
+}
+
+func NewAmount(f float64) Amount {
+	return Amount{d: decimal.NewFromFloat(f).Round(AmountScale)}
+}
+
+func AmountFromDecimal(d decimal.Decimal) Amount {
+	return Amount{d: d.Round(AmountScale)}
+}
+
+func ParseAmount(s string) (Amount, error) {
+	d, err := parseFixed(s)
+	if err != nil {
+		return Amount{}, err
+	}
+	return AmountFromDecimal(d), nil
+}
+
+func (a Amount) Add(b Amount) Amount      { return Amount{d: a.d.Add(b.d)} }
+func (a Amount) Sub(b Amount) Amount      { return Amount{d: a.d.Sub(b.d)} }
+func (a Amount) Neg() Amount              { return Amount{d: a.d.Neg()} }
+func (a Amount) Abs() Amount              { return Amount{d: a.d.Abs()} }
+func (a Amount) Cmp(b Amount) int         { return a.d.Cmp(b.d) }
+func (a Amount) Sign() int                { return a.d.Sign() }
+func (a Amount) IsZero() bool             { return a.d.IsZero() }
+func (a Amount) IsPositive() bool         { return a.d.IsPositive() }
+func (a Amount) IsNegative() bool         { return a.d.IsNegative() }
+func (a Amount) Decimal() decimal.Decimal { return a.d }
+
+// Float64 برای مرز با کدهایی که هنوز float64 می‌گیرند؛ در جمع و مقایسه از خود Amount استفاده شود.
+func (a Amount) Float64() float64 { return a.d.InexactFloat64() }
+func (a Amount) String() string   { return a.d.String() }
+
+func (a Amount) MarshalJSON() ([]byte, error) { return marshalFixed(a.d), nil }
+
+func (a *Amount) UnmarshalJSON(data []byte) error {
+	d, err := unmarshalFixed(data)
+	if err != nil {
+		return err
+	}
+	*a = AmountFromDecimal(d)
+	return nil
+}
+
+func (a Amount) Value() (driver.Value, error) { return a.d.String(), nil }
+
+func (a *Amount) Scan(src interface{}) error {
+	d, err := scanFixed(src)
+	if err != nil {
+		return err
+	}
+	*a = AmountFromDecimal(d)
+	return nil
+}
+
+// GormDataType نوع ستون در مهاجرت خودکار gorm
+func (Amount) GormDataType() string { return "numeric(24,2)" }
+
+// Weight وزن به گرم
+type Weight struct {
+	d decimal.Decimal
+}
+
+func NewWeight(f float64) Weight {
+	return Weight{d: decimal.NewFromFloat(f).Round(WeightScale)}
+}
+
+func WeightFromDecimal(d decimal.Decimal) Weight {
+	return Weight{d: d.Round(WeightScale)}
+}
+
+func ParseWeight(s string) (Weight, error) {
+	d, err := parseFixed(s)
+	if err != nil {
+		return Weight{}, err
+	}
+	return WeightFromDecimal(d), nil
+}
+
+func (w Weight) Add(o Weight) Weight      { return Weight{d: w.d.Add(o.d)} }
+func (w Weight) Sub(o Weight) Weight      { return Weight{d: w.d.Sub(o.d)} }
+func (w Weight) Neg() Weight              { return Weight{d: w.d.Neg()} }
+func (w Weight) Abs() Weight              { return Weight{d: w.d.Abs()} }
+func (w Weight) Cmp(o Weight) int         { return w.d.Cmp(o.d) }
+func (w Weight) Sign() int                { return w.d.Sign() }
+func (w Weight) IsZero() bool             { return w.d.IsZero() }
+func (w Weight) IsPositive() bool         { return w.d.IsPositive() }
+func (w Weight) IsNegative() bool         { return w.d.IsNegative() }
+func (w Weight) Decimal() decimal.Decimal { return w.d }
+
+// MulRate ارزش وزن با نرخ هر گرم، گرد شده به دقت تومانی
+func (w Weight) MulRate(rate Amount) Amount {
+	return AmountFromDecimal(w.d.Mul(rate.d))
+}
+
+func (w Weight) Float64() float64 { return w.d.InexactFloat64() }
+func (w Weight) String() string   { return w.d.String() }
+
+func (w Weight) MarshalJSON() ([]byte, error) { return marshalFixed(w.d), nil }
+
+func (w *Weight) UnmarshalJSON(data []byte) error {
+	d, err := unmarshalFixed(data)
+	if err != nil {
+		return err
+	}
+	*w = WeightFromDecimal(d)
+	return nil
+}
+
+func (w Weight) Value() (driver.Value, error) { return w.d.String(), nil }
+
+func (w *Weight) Scan(src interface{}) error {
+	d, err := scanFixed(src)
+	if err != nil {
+		return err
+	}
+	*w = WeightFromDecimal(d)
+	return nil
+}
+
+func (Weight) GormDataType() string { return "numeric(24,3)" }
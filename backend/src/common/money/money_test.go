@@ -0,0 +1,141 @@
+package money
+
+import (
+	"encoding/json"
+	"testing"
+)
+
+func TestAmountUnmarshalJSON(t *testing.T) {
+	tests := []struct {
+		name, in, want string
+	}{
+		{"number", `1250.5`, "1250.5"},
+		{"string", `"1250.50"`, "1250.5"},
+		{"rounds to scale", `10.005`, "10.01"},
+		{"null", `null`, "0"},
+		{"empty string", `""`, "0"},
+		{"negative", `-3.333`, "-3.33"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var a Amount
+			if err := json.Unmarshal([]byte(tt.in), &a); err != nil {
+				t.Fatalf("unmarshal %s: %v", tt.in, err)
+			}
+			if a.String() != tt.want {
+				t.Errorf("got %s, want %s", a, tt.want)
+			}
+		})
+	}
+}
+
+func TestUnmarshalJSONRejectsGarbage(t *testing.T) {
+	var a Amount
+	if err := json.Unmarshal([]byte(`"12abc"`), &a); err == nil {
+		t.Error("amount accepted a non-numeric string")
+	}
+	var w Weight
+	if err := json.Unmarshal([]byte(`true`), &w); err == nil {
+		t.Error("weight accepted a boolean")
+	}
+}
+
+func TestMarshalJSONIsNumber(t *testing.T) {
+	v := struct {
+		Amount Amount `json:"amount"`
+		Weight Weight `json:"weight"`
+	}{mustAmount(t, "1500000.25"), NewWeight(4.1234)}
+	got, err := json.Marshal(v)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := `{"amount":1500000.25,"weight":4.123}`; string(got) != want {
+		t.Errorf("got %s, want %s", got, want)
+	}
+}
+
+func TestScan(t *testing.T) {
+	tests := []struct {
+		name string
+		src  interface{}
+		want string
+	}{
+		{"bytes", []byte("12.34"), "12.34"},
+		{"string", "0.10", "0.1"},
+		{"float64 rounds to scale", 0.1 + 0.2, "0.3"},
+		{"int64", int64(7), "7"},
+		{"nil", nil, "0"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var a Amount
+			if err := a.Scan(tt.src); err != nil {
+				t.Fatalf("scan %v: %v", tt.src, err)
+			}
+			if a.String() != tt.want {
+				t.Errorf("got %s, want %s", a, tt.want)
+			}
+		})
+	}
+	var a Amount
+	if err := a.Scan(true); err == nil {
+		t.Error("scan accepted a boolean")
+	}
+}
+
+func TestValueRoundTrip(t *testing.T) {
+	w := NewWeight(12.3456)
+	v, err := w.Value()
+	if err != nil {
+		t.Fatal(err)
+	}
+	var got Weight
+	if err := got.Scan(v); err != nil {
+		t.Fatal(err)
+	}
+	if got.Cmp(w) != 0 || got.String() != "12.346" {
+		t.Errorf("round trip gave %s, want 12.346", got)
+	}
+}
+
+func TestSumIsExact(t *testing.T) {
+	var sum Amount
+	for i := 0; i < 10; i++ {
+		sum = sum.Add(NewAmount(0.1))
+	}
+	if sum.Cmp(NewAmount(1)) != 0 {
+		t.Errorf("ten times 0.1 is %s, want 1", sum)
+	}
+}
+
+func TestWeightMulRate(t *testing.T) {
+	got := NewWeight(1.234).MulRate(NewAmount(3333333.33))
+	if want := "4113333.33"; got.String() != want {
+		t.Errorf("got %s, want %s", got, want)
+	}
+}
+
+func TestForeignConversions(t *testing.T) {
+	rate, err := ParseRate("58500.5")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := NewForeign(10).ToToman(rate); got.String() != "585005" {
+		t.Errorf("ToToman got %s, want 585005", got)
+	}
+	if got := NewAmount(100000).ToForeign(rate); got.String() != "1.709387" {
+		t.Errorf("ToForeign got %s, want 1.709387", got)
+	}
+	if got := NewAmount(100000).ToForeign(Rate{}); !got.IsZero() {
+		t.Errorf("ToForeign with zero rate got %s, want 0", got)
+	}
+}
+
+func mustAmount(t *testing.T, s string) Amount {
+	t.Helper()
+	a, err := ParseAmount(s)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return a
+}
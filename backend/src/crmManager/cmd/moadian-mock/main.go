@@ -0,0 +1,54 @@
+package main
+
+import (
+	"crm-gold/internal/moadian"
+	"crypto/rsa"
+	"fmt"
+	"net/http"
+	"os"
+	"strconv"
+
+	"go.uber.org/zap"
+)
+
+// شبیه‌ساز محلی سامانه مودیان؛ CRM با MOADIAN_BASE_URL=http://localhost:8095 به آن وصل می‌شود.
+func main() {
+	logger, err := zap.NewDevelopment()
+	if err != nil {
+		panic(fmt.Errorf("failed to initialize logger: %w", err))
+	}
+	defer logger.Sync()
+
+	addr := os.Getenv("MOADIAN_MOCK_ADDR")
+	if addr == "" {
+		addr = ":8095"
+	}
+	opts := moadian.MockOptions{PublicKeys: map[string]*rsa.PublicKey{}}
+	if v := os.Getenv("MOADIAN_MOCK_PENDING_POLLS"); v != "" {
+		if opts.PendingPolls, err = strconv.Atoi(v); err != nil {
+			logger.Fatal("Invalid MOADIAN_MOCK_PENDING_POLLS", zap.Error(err))
+		}
+	}
+	if v := os.Getenv("MOADIAN_MOCK_FAIL_FIRST"); v != "" {
+		if opts.FailFirst, err = strconv.Atoi(v); err != nil {
+			logger.Fatal("Invalid MOADIAN_MOCK_FAIL_FIRST", zap.Error(err))
+		}
+	}
+	// MOADIAN_MOCK_CLIENT_ID و MOADIAN_MOCK_PUBLIC_KEY (مسیر فایل PEM) بررسی امضا را برای آن شناسه حافظه فعال می‌کنند.
+	if clientID, keyPath := os.Getenv("MOADIAN_MOCK_CLIENT_ID"), os.Getenv("MOADIAN_MOCK_PUBLIC_KEY"); clientID != "" && keyPath != "" {
+		pem, err := os.ReadFile(keyPath)
+		if err != nil {
+			logger.Fatal("Failed to read mock public key", zap.Error(err))
+		}
+		pub, err := moadian.ParsePublicKey(string(pem))
+		if err != nil {
+			logger.Fatal("Failed to parse mock public key", zap.Error(err))
+		}
+		opts.PublicKeys[clientID] = pub
+	}
+
+	logger.Info("Moadian mock listening", zap.String("addr", addr), zap.Int("pending_polls", opts.PendingPolls), zap.Int("fail_first", opts.FailFirst))
+	if err := http.ListenAndServe(addr, moadian.NewMockServer(opts)); err != nil {
+		logger.Fatal("Moadian mock stopped", zap.Error(err))
+	}
+}
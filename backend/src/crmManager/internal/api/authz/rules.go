@@ -0,0 +1,19 @@
+package authz
+
+import (
+	"common-gold/authz"
+
+	"crm-gold/internal/model"
+)
+
+// Rules سیاست‌های ردیف و فیلد crmManager. هر مسیری که با AuthZMiddleware محافظت شود این قواعد را
+// بر اساس مجوز همان مسیر اعمال می‌کند؛ handlerها و سرویس‌ها نباید دوباره آن‌ها را بررسی کنند.
+var Rules = authz.Rules{
+	Scopes: map[string]authz.OwnerScope{
+		// فروشنده فقط مشتریانی را می‌بیند که به خودش تخصیص داده شده‌اند.
+		model.PermCRMReadCustomer: {Column: "assigned_employee_id", Bypass: model.PermCRMReadAllCustomers},
+	},
+	Fields: map[string][]string{
+		model.PermCRMViewCustomerBalance: {"initialBalanceToman", "initialBalanceGold"},
+	},
+}
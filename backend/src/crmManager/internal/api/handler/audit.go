@@ -0,0 +1,70 @@
+package handler
+
+import (
+	"crm-gold/internal/model"
+	"crm-gold/internal/service"
+	"crm-gold/internal/utils"
+
+	"common-gold/audit"
+
+	"github.com/gofiber/fiber/v2"
+	"go.uber.org/zap"
+)
+
+type AuditHandler struct {
+	auditSvc service.AuditService
+}
+
+func NewAuditHandler(auditSvc service.AuditService) *AuditHandler {
+	if auditSvc == nil {
+		utils.Log.Fatal("auditSvc cannot be nil for AuditHandler in CrmManager.")
+	}
+	return &AuditHandler{auditSvc: auditSvc}
+}
+
+func (h *AuditHandler) HandleListEvents(c *fiber.Ctx) error {
+	from, to, err := parseOptionalRange(c)
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(model.ErrorResponse{Message: "Invalid date range", Details: err.Error()})
+	}
+	page, err := h.auditSvc.ListEvents(c.Context(), model.AuditFilter{
+		BIDID:         optionalUintQuery(c, "bidId"),
+		ActorID:       c.Query("actorId"),
+		Action:        c.Query("action"),
+		Category:      c.Query("category"),
+		TargetType:    c.Query("targetType"),
+		TargetID:      c.Query("targetId"),
+		CorrelationID: c.Query("correlationId"),
+		Query:         c.Query("q"),
+		From:          from,
+		To:            to,
+		Page:          c.QueryInt("page", 1),
+		PageSize:      c.QueryInt("pageSize"),
+	})
+	if err != nil {
+		return writeServiceError(c, err, "Failed to list audit events due to an internal error.")
+	}
+	return c.Status(fiber.StatusOK).JSON(page)
+}
+
+func (h *AuditHandler) HandleVerifyChain(c *fiber.Ctx) error {
+	report, err := h.auditSvc.VerifyChain(c.Context())
+	if err != nil {
+		return writeServiceError(c, err, "Failed to verify audit chain due to an internal error.")
+	}
+	return c.Status(fiber.StatusOK).JSON(report)
+}
+
+// HandleIngestEvent رویدادهای سرویس‌های دیگر را ثبت می‌کند؛ کاربر، IP و مرورگر را خود سرویس فرستنده پر کرده است
+// و به همین دلیل از UserContext (بدون Locals این درخواست داخلی) استفاده می‌شود.
+func (h *AuditHandler) HandleIngestEvent(c *fiber.Ctx) error {
+	var event audit.Event
+	if err := c.BodyParser(&event); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(model.ErrorResponse{Message: "Invalid request body", Details: err.Error()})
+	}
+	if err := h.auditSvc.Record(c.UserContext(), event); err != nil {
+		utils.Log.Error("Failed to ingest audit event", zap.String("action", event.Action), zap.Error(err))
+		return writeServiceError(c, err, "Failed to record audit event due to an internal error.")
+	}
+	return c.SendStatus(fiber.StatusCreated)
+}
@@ -0,0 +1,259 @@
+package handler
+
+import (
+	"crm-gold/internal/model"
+	"crm-gold/internal/service"
+	"crm-gold/internal/utils"
+
+	"github.com/gofiber/fiber/v2"
+	"go.uber.org/zap"
+)
+
+type BankHandler struct {
+	bankSvc service.BankService
+}
+
+func NewBankHandler(bankSvc service.BankService) *BankHandler {
+	if bankSvc == nil {
+		utils.Log.Fatal("bankSvc cannot be nil for BankHandler in CrmManager.")
+	}
+	return &BankHandler{bankSvc: bankSvc}
+}
+
+func (h *BankHandler) HandleCreateBankAccount(c *fiber.Ctx) error {
+	var req model.CreateBankAccountRequest
+	if err := c.BodyParser(&req); err != nil {
+		utils.Log.Error("Failed to parse request body for bank account creation", zap.Error(err))
+		return c.Status(fiber.StatusBadRequest).JSON(model.ErrorResponse{Message: "Invalid request body", Details: err.Error()})
+	}
+	account, err := h.bankSvc.CreateBankAccount(c.Context(), &req, actorFromCtx(c))
+	if err != nil {
+		utils.Log.Error("Failed to create bank account via service layer", zap.Error(err))
+		return writeServiceError(c, err, "Failed to create bank account due to an internal error.")
+	}
+	return c.Status(fiber.StatusCreated).JSON(account)
+}
+
+func (h *BankHandler) HandleListBankAccounts(c *fiber.Ctx) error {
+	accounts, err := h.bankSvc.ListBankAccounts(c.Context(), uint(c.QueryInt("bidId")))
+	if err != nil {
+		return writeServiceError(c, err, "Failed to list bank accounts due to an internal error.")
+	}
+	return c.Status(fiber.StatusOK).JSON(accounts)
+}
+
+func (h *BankHandler) HandleGetBankAccount(c *fiber.Ctx) error {
+	id, err := parseIDParam(c, "id")
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(model.ErrorResponse{Message: "Invalid bank account id", Details: err.Error()})
+	}
+	account, err := h.bankSvc.GetBankAccount(c.Context(), id)
+	if err != nil {
+		return writeServiceError(c, err, "Failed to get bank account due to an internal error.")
+	}
+	return c.Status(fiber.StatusOK).JSON(account)
+}
+
+func (h *BankHandler) HandleAddPOSTerminal(c *fiber.Ctx) error {
+	id, err := parseIDParam(c, "id")
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(model.ErrorResponse{Message: "Invalid bank account id", Details: err.Error()})
+	}
+	var req model.POSTerminalRequest
+	if err := c.BodyParser(&req); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(model.ErrorResponse{Message: "Invalid request body", Details: err.Error()})
+	}
+	terminal, err := h.bankSvc.AddPOSTerminal(c.Context(), id, &req)
+	if err != nil {
+		return writeServiceError(c, err, "Failed to add pos terminal due to an internal error.")
+	}
+	return c.Status(fiber.StatusCreated).JSON(terminal)
+}
+
+func (h *BankHandler) HandleCreateTransaction(c *fiber.Ctx) error {
+	id, err := parseIDParam(c, "id")
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(model.ErrorResponse{Message: "Invalid bank account id", Details: err.Error()})
+	}
+	var req model.BankTransactionRequest
+	if err := c.BodyParser(&req); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(model.ErrorResponse{Message: "Invalid request body", Details: err.Error()})
+	}
+	txn, err := h.bankSvc.CreateTransaction(c.Context(), id, &req, actorFromCtx(c))
+	if err != nil {
+		utils.Log.Error("Failed to create bank transaction", zap.Uint("bank_account_id", id), zap.Error(err))
+		return writeServiceError(c, err, "Failed to create bank transaction due to an internal error.")
+	}
+	return c.Status(fiber.StatusCreated).JSON(txn)
+}
+
+func (h *BankHandler) HandleListTransactions(c *fiber.Ctx) error {
+	id, err := parseIDParam(c, "id")
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(model.ErrorResponse{Message: "Invalid bank account id", Details: err.Error()})
+	}
+	from, to, err := parseOptionalRange(c)
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(model.ErrorResponse{Message: "Invalid date range", Details: err.Error()})
+	}
+	txns, err := h.bankSvc.ListTransactions(c.Context(), id, from, to)
+	if err != nil {
+		return writeServiceError(c, err, "Failed to list bank transactions due to an internal error.")
+	}
+	return c.Status(fiber.StatusOK).JSON(txns)
+}
+
+func (h *BankHandler) HandleDeleteTransaction(c *fiber.Ctx) error {
+	id, err := parseIDParam(c, "txnId")
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(model.ErrorResponse{Message: "Invalid transaction id", Details: err.Error()})
+	}
+	if err := h.bankSvc.DeleteTransaction(c.Context(), id, actorFromCtx(c)); err != nil {
+		utils.Log.Error("Failed to delete bank transaction", zap.Uint("txn_id", id), zap.Error(err))
+		return writeServiceError(c, err, "Failed to delete bank transaction due to an internal error.")
+	}
+	return c.SendStatus(fiber.StatusNoContent)
+}
+
+func (h *BankHandler) HandleGetStatement(c *fiber.Ctx) error {
+	id, err := parseIDParam(c, "id")
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(model.ErrorResponse{Message: "Invalid bank account id", Details: err.Error()})
+	}
+	from, to, err := parseRequiredRange(c)
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(model.ErrorResponse{Message: "Invalid date range", Details: err.Error()})
+	}
+	statement, err := h.bankSvc.GetStatement(c.Context(), id, from, to)
+	if err != nil {
+		return writeServiceError(c, err, "Failed to build bank statement due to an internal error.")
+	}
+	return c.Status(fiber.StatusOK).JSON(statement)
+}
+
+// HandleImportStatement فایل CSV صورتحساب را در فیلد file دریافت می‌کند؛ unit=toman برای فایل‌هایی که مبالغ آن به تومان است.
+func (h *BankHandler) HandleImportStatement(c *fiber.Ctx) error {
+	id, err := parseIDParam(c, "id")
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(model.ErrorResponse{Message: "Invalid bank account id", Details: err.Error()})
+	}
+	fileHeader, err := c.FormFile("file")
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(model.ErrorResponse{Message: "Statement file is required", Details: err.Error()})
+	}
+	file, err := fileHeader.Open()
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(model.ErrorResponse{Message: "Failed to read statement file", Details: err.Error()})
+	}
+	defer file.Close()
+
+	unit := c.Query("unit", c.FormValue("unit"))
+	result, err := h.bankSvc.ImportStatement(c.Context(), id, fileHeader.Filename, file, unit, actorFromCtx(c))
+	if err != nil {
+		utils.Log.Error("Failed to import bank statement", zap.Uint("bank_account_id", id), zap.Error(err))
+		return writeServiceError(c, err, "Failed to import bank statement due to an internal error.")
+	}
+	return c.Status(fiber.StatusCreated).JSON(result)
+}
+
+func (h *BankHandler) HandleListStatementLines(c *fiber.Ctx) error {
+	id, err := parseIDParam(c, "id")
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(model.ErrorResponse{Message: "Invalid bank account id", Details: err.Error()})
+	}
+	lines, err := h.bankSvc.ListStatementLines(c.Context(), id, c.Query("status"))
+	if err != nil {
+		return writeServiceError(c, err, "Failed to list statement lines due to an internal error.")
+	}
+	return c.Status(fiber.StatusOK).JSON(lines)
+}
+
+func (h *BankHandler) HandleListUnmatchedLines(c *fiber.Ctx) error {
+	id, err := parseIDParam(c, "id")
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(model.ErrorResponse{Message: "Invalid bank account id", Details: err.Error()})
+	}
+	lines, err := h.bankSvc.ListStatementLines(c.Context(), id, model.StatementLineUnmatched)
+	if err != nil {
+		return writeServiceError(c, err, "Failed to list unmatched lines due to an internal error.")
+	}
+	return c.Status(fiber.StatusOK).JSON(lines)
+}
+
+func (h *BankHandler) HandleListCandidates(c *fiber.Ctx) error {
+	id, err := parseIDParam(c, "id")
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(model.ErrorResponse{Message: "Invalid bank account id", Details: err.Error()})
+	}
+	from, to, err := parseRequiredRange(c)
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(model.ErrorResponse{Message: "Invalid date range", Details: err.Error()})
+	}
+	candidates, err := h.bankSvc.ListCandidates(c.Context(), id, from, to)
+	if err != nil {
+		return writeServiceError(c, err, "Failed to list reconciliation candidates due to an internal error.")
+	}
+	return c.Status(fiber.StatusOK).JSON(candidates)
+}
+
+func (h *BankHandler) HandleMatchLine(c *fiber.Ctx) error {
+	id, err := parseIDParam(c, "lineId")
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(model.ErrorResponse{Message: "Invalid statement line id", Details: err.Error()})
+	}
+	var req model.MatchStatementLineRequest
+	if err := c.BodyParser(&req); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(model.ErrorResponse{Message: "Invalid request body", Details: err.Error()})
+	}
+	line, err := h.bankSvc.MatchStatementLine(c.Context(), id, &req, actorFromCtx(c))
+	if err != nil {
+		return writeServiceError(c, err, "Failed to match statement line due to an internal error.")
+	}
+	return c.Status(fiber.StatusOK).JSON(line)
+}
+
+func (h *BankHandler) HandleRecordLine(c *fiber.Ctx) error {
+	id, err := parseIDParam(c, "lineId")
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(model.ErrorResponse{Message: "Invalid statement line id", Details: err.Error()})
+	}
+	var req model.RecordStatementLineRequest
+	if err := c.BodyParser(&req); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(model.ErrorResponse{Message: "Invalid request body", Details: err.Error()})
+	}
+	line, err := h.bankSvc.RecordStatementLine(c.Context(), id, &req, actorFromCtx(c))
+	if err != nil {
+		return writeServiceError(c, err, "Failed to record statement line due to an internal error.")
+	}
+	return c.Status(fiber.StatusOK).JSON(line)
+}
+
+func (h *BankHandler) HandleIgnoreLine(c *fiber.Ctx) error {
+	id, err := parseIDParam(c, "lineId")
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(model.ErrorResponse{Message: "Invalid statement line id", Details: err.Error()})
+	}
+	var req model.IgnoreStatementLineRequest
+	if len(c.Body()) > 0 {
+		if err := c.BodyParser(&req); err != nil {
+			return c.Status(fiber.StatusBadRequest).JSON(model.ErrorResponse{Message: "Invalid request body", Details: err.Error()})
+		}
+	}
+	line, err := h.bankSvc.IgnoreStatementLine(c.Context(), id, &req, actorFromCtx(c))
+	if err != nil {
+		return writeServiceError(c, err, "Failed to ignore statement line due to an internal error.")
+	}
+	return c.Status(fiber.StatusOK).JSON(line)
+}
+
+func (h *BankHandler) HandleUnmatchLine(c *fiber.Ctx) error {
+	id, err := parseIDParam(c, "lineId")
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(model.ErrorResponse{Message: "Invalid statement line id", Details: err.Error()})
+	}
+	line, err := h.bankSvc.UnmatchStatementLine(c.Context(), id, actorFromCtx(c))
+	if err != nil {
+		return writeServiceError(c, err, "Failed to unmatch statement line due to an internal error.")
+	}
+	return c.Status(fiber.StatusOK).JSON(line)
+}
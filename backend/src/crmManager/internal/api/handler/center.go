@@ -0,0 +1,203 @@
+package handler
+
+import (
+	"time"
+
+	"crm-gold/internal/model"
+	"crm-gold/internal/service"
+	"crm-gold/internal/utils"
+
+	"github.com/gofiber/fiber/v2"
+	"go.uber.org/zap"
+)
+
+type CenterHandler struct {
+	centerSvc    service.CenterService
+	recurringSvc service.RecurringExpenseService
+}
+
+func NewCenterHandler(centerSvc service.CenterService, recurringSvc service.RecurringExpenseService) *CenterHandler {
+	if centerSvc == nil {
+		utils.Log.Fatal("centerSvc cannot be nil for CenterHandler in CrmManager.")
+	}
+	if recurringSvc == nil {
+		utils.Log.Fatal("recurringSvc cannot be nil for CenterHandler in CrmManager.")
+	}
+	return &CenterHandler{centerSvc: centerSvc, recurringSvc: recurringSvc}
+}
+
+func (h *CenterHandler) HandleCreateCenter(c *fiber.Ctx) error {
+	var req model.CreateCenterRequest
+	if err := c.BodyParser(&req); err != nil {
+		utils.Log.Error("Failed to parse request body for center creation", zap.Error(err))
+		return c.Status(fiber.StatusBadRequest).JSON(model.ErrorResponse{Message: "Invalid request body", Details: err.Error()})
+	}
+	center, err := h.centerSvc.CreateCenter(c.Context(), &req)
+	if err != nil {
+		utils.Log.Error("Failed to create center via service layer", zap.Error(err))
+		return writeServiceError(c, err, "Failed to create center due to an internal error.")
+	}
+	return c.Status(fiber.StatusCreated).JSON(center)
+}
+
+// HandleListCenters با tree=true مراکز را به صورت درختی برمی‌گرداند.
+func (h *CenterHandler) HandleListCenters(c *fiber.Ctx) error {
+	bidID := uint(c.QueryInt("bidId"))
+	centerType := c.Query("type")
+	var (
+		centers []model.FinanceCenter
+		err     error
+	)
+	if c.QueryBool("tree") {
+		centers, err = h.centerSvc.GetCenterTree(c.Context(), bidID, centerType)
+	} else {
+		centers, err = h.centerSvc.ListCenters(c.Context(), bidID, centerType)
+	}
+	if err != nil {
+		return writeServiceError(c, err, "Failed to list centers due to an internal error.")
+	}
+	return c.Status(fiber.StatusOK).JSON(centers)
+}
+
+func (h *CenterHandler) HandleUpdateCenter(c *fiber.Ctx) error {
+	id, err := parseIDParam(c, "id")
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(model.ErrorResponse{Message: "Invalid center id", Details: err.Error()})
+	}
+	var req model.UpdateCenterRequest
+	if err := c.BodyParser(&req); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(model.ErrorResponse{Message: "Invalid request body", Details: err.Error()})
+	}
+	center, err := h.centerSvc.UpdateCenter(c.Context(), id, &req)
+	if err != nil {
+		utils.Log.Error("Failed to update center", zap.Uint("center_id", id), zap.Error(err))
+		return writeServiceError(c, err, "Failed to update center due to an internal error.")
+	}
+	return c.Status(fiber.StatusOK).JSON(center)
+}
+
+// HandleTopCenters داده نمودارهای TopCostCentersChart و TopIncomeCentersChart؛ بازه from تا to شامل هر دو روز است.
+func (h *CenterHandler) HandleTopCenters(c *fiber.Ctx) error {
+	from, to, err := parseRequiredRange(c)
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(model.ErrorResponse{Message: "Invalid date range", Details: err.Error()})
+	}
+	from = utils.StartOfDay(from)
+	to = utils.StartOfDay(to).AddDate(0, 0, 1)
+	resp, err := h.centerSvc.TopCenters(c.Context(), uint(c.QueryInt("bidId")), c.Query("type"), from, to, c.QueryInt("limit"))
+	if err != nil {
+		return writeServiceError(c, err, "Failed to compute top centers due to an internal error.")
+	}
+	return c.Status(fiber.StatusOK).JSON(resp)
+}
+
+func (h *CenterHandler) HandleCreateVoucher(c *fiber.Ctx) error {
+	var req model.CreateCenterVoucherRequest
+	if err := c.BodyParser(&req); err != nil {
+		utils.Log.Error("Failed to parse request body for center voucher creation", zap.Error(err))
+		return c.Status(fiber.StatusBadRequest).JSON(model.ErrorResponse{Message: "Invalid request body", Details: err.Error()})
+	}
+	voucher, err := h.centerSvc.CreateVoucher(c.Context(), &req, actorFromCtx(c))
+	if err != nil {
+		utils.Log.Error("Failed to create center voucher via service layer", zap.Error(err))
+		return writeServiceError(c, err, "Failed to create voucher due to an internal error.")
+	}
+	return c.Status(fiber.StatusCreated).JSON(voucher)
+}
+
+func (h *CenterHandler) HandleListVouchers(c *fiber.Ctx) error {
+	from, to, err := parseOptionalRange(c)
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(model.ErrorResponse{Message: "Invalid date range", Details: err.Error()})
+	}
+	filter := model.CenterVoucherFilter{
+		BIDID:      uint(c.QueryInt("bidId")),
+		CenterID:   uint(c.QueryInt("centerId")),
+		CenterType: c.Query("type"),
+		Status:     c.Query("status"),
+		From:       from,
+		To:         to,
+	}
+	vouchers, err := h.centerSvc.ListVouchers(c.Context(), filter)
+	if err != nil {
+		return writeServiceError(c, err, "Failed to list vouchers due to an internal error.")
+	}
+	return c.Status(fiber.StatusOK).JSON(vouchers)
+}
+
+func (h *CenterHandler) HandleGetVoucher(c *fiber.Ctx) error {
+	id, err := parseIDParam(c, "id")
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(model.ErrorResponse{Message: "Invalid voucher id", Details: err.Error()})
+	}
+	voucher, err := h.centerSvc.GetVoucher(c.Context(), id)
+	if err != nil {
+		return writeServiceError(c, err, "Failed to get voucher due to an internal error.")
+	}
+	return c.Status(fiber.StatusOK).JSON(voucher)
+}
+
+func (h *CenterHandler) HandleReverseVoucher(c *fiber.Ctx) error {
+	id, err := parseIDParam(c, "id")
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(model.ErrorResponse{Message: "Invalid voucher id", Details: err.Error()})
+	}
+	var req model.ReverseCenterVoucherRequest
+	if err := c.BodyParser(&req); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(model.ErrorResponse{Message: "Invalid request body", Details: err.Error()})
+	}
+	voucher, err := h.centerSvc.ReverseVoucher(c.Context(), id, &req, actorFromCtx(c))
+	if err != nil {
+		utils.Log.Error("Failed to reverse center voucher", zap.Uint("voucher_id", id), zap.Error(err))
+		return writeServiceError(c, err, "Failed to reverse voucher due to an internal error.")
+	}
+	return c.Status(fiber.StatusOK).JSON(voucher)
+}
+
+func (h *CenterHandler) HandleCreateRecurringExpense(c *fiber.Ctx) error {
+	var req model.CreateRecurringExpenseRequest
+	if err := c.BodyParser(&req); err != nil {
+		utils.Log.Error("Failed to parse request body for recurring expense creation", zap.Error(err))
+		return c.Status(fiber.StatusBadRequest).JSON(model.ErrorResponse{Message: "Invalid request body", Details: err.Error()})
+	}
+	rec, err := h.recurringSvc.CreateRecurringExpense(c.Context(), &req, actorFromCtx(c))
+	if err != nil {
+		utils.Log.Error("Failed to create recurring expense via service layer", zap.Error(err))
+		return writeServiceError(c, err, "Failed to create recurring expense due to an internal error.")
+	}
+	return c.Status(fiber.StatusCreated).JSON(rec)
+}
+
+func (h *CenterHandler) HandleListRecurringExpenses(c *fiber.Ctx) error {
+	recs, err := h.recurringSvc.ListRecurringExpenses(c.Context(), uint(c.QueryInt("bidId")))
+	if err != nil {
+		return writeServiceError(c, err, "Failed to list recurring expenses due to an internal error.")
+	}
+	return c.Status(fiber.StatusOK).JSON(recs)
+}
+
+func (h *CenterHandler) HandleUpdateRecurringExpense(c *fiber.Ctx) error {
+	id, err := parseIDParam(c, "id")
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(model.ErrorResponse{Message: "Invalid recurring expense id", Details: err.Error()})
+	}
+	var req model.UpdateRecurringExpenseRequest
+	if err := c.BodyParser(&req); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(model.ErrorResponse{Message: "Invalid request body", Details: err.Error()})
+	}
+	rec, err := h.recurringSvc.UpdateRecurringExpense(c.Context(), id, &req)
+	if err != nil {
+		utils.Log.Error("Failed to update recurring expense", zap.Uint("recurring_expense_id", id), zap.Error(err))
+		return writeServiceError(c, err, "Failed to update recurring expense due to an internal error.")
+	}
+	return c.Status(fiber.StatusOK).JSON(rec)
+}
+
+// HandleRunRecurringExpenses اجرای دستی همان کاری است که زمان‌بند روزانه انجام می‌دهد.
+func (h *CenterHandler) HandleRunRecurringExpenses(c *fiber.Ctx) error {
+	created, err := h.recurringSvc.RunDue(c.Context(), time.Now())
+	if err != nil {
+		return writeServiceError(c, err, "Failed to run recurring expenses due to an internal error.")
+	}
+	return c.Status(fiber.StatusOK).JSON(fiber.Map{"created": created})
+}
@@ -0,0 +1,181 @@
+package handler
+
+import (
+	"errors"
+	"strconv"
+	"time"
+
+	"crm-gold/internal/model"
+	"crm-gold/internal/service"
+	"crm-gold/internal/utils"
+
+	"github.com/gofiber/fiber/v2"
+	"go.uber.org/zap"
+)
+
+type ChequeHandler struct {
+	chequeSvc service.ChequeService
+	alertSvc  service.ChequeAlertService
+}
+
+func NewChequeHandler(chequeSvc service.ChequeService, alertSvc service.ChequeAlertService) *ChequeHandler {
+	if chequeSvc == nil {
+		utils.Log.Fatal("chequeSvc cannot be nil for ChequeHandler in CrmManager.")
+	}
+	if alertSvc == nil {
+		utils.Log.Fatal("alertSvc cannot be nil for ChequeHandler in CrmManager.")
+	}
+	return &ChequeHandler{chequeSvc: chequeSvc, alertSvc: alertSvc}
+}
+
+func (h *ChequeHandler) HandleCreateReceivedCheque(c *fiber.Ctx) error {
+	return h.createCheque(c, model.ChequeDirectionReceived)
+}
+
+func (h *ChequeHandler) HandleCreateIssuedCheque(c *fiber.Ctx) error {
+	return h.createCheque(c, model.ChequeDirectionIssued)
+}
+
+func (h *ChequeHandler) createCheque(c *fiber.Ctx, direction string) error {
+	var req model.CreateChequeRequest
+	if err := c.BodyParser(&req); err != nil {
+		utils.Log.Error("Failed to parse request body for cheque creation", zap.Error(err))
+		return c.Status(fiber.StatusBadRequest).JSON(model.ErrorResponse{
+			Message: "Invalid request body",
+			Details: err.Error(),
+		})
+	}
+
+	cheque, err := h.chequeSvc.CreateCheque(c.Context(), direction, &req, actorFromCtx(c))
+	if err != nil {
+		utils.Log.Error("Failed to create cheque via service layer", zap.String("direction", direction), zap.Error(err))
+		return writeServiceError(c, err, "Failed to create cheque due to an internal error.")
+	}
+	return c.Status(fiber.StatusCreated).JSON(cheque)
+}
+
+func (h *ChequeHandler) HandleListCheques(c *fiber.Ctx) error {
+	filter := model.ChequeFilter{
+		BIDID:     uint(c.QueryInt("bidId")),
+		Direction: c.Query("direction"),
+		Status:    c.Query("status"),
+		PersonID:  uint(c.QueryInt("personId")),
+	}
+	if v := c.Query("dueFrom"); v != "" {
+		t, err := utils.ParseDateParam(v)
+		if err != nil {
+			return c.Status(fiber.StatusBadRequest).JSON(model.ErrorResponse{Message: "Invalid dueFrom date", Details: err.Error()})
+		}
+		filter.DueFrom = &t
+	}
+	if v := c.Query("dueTo"); v != "" {
+		t, err := utils.ParseDateParam(v)
+		if err != nil {
+			return c.Status(fiber.StatusBadRequest).JSON(model.ErrorResponse{Message: "Invalid dueTo date", Details: err.Error()})
+		}
+		// بازه شامل روز پایانی هم می‌شود
+		t = t.AddDate(0, 0, 1)
+		filter.DueTo = &t
+	}
+
+	cheques, err := h.chequeSvc.ListCheques(c.Context(), filter)
+	if err != nil {
+		utils.Log.Error("Failed to list cheques via service layer", zap.Error(err))
+		return writeServiceError(c, err, "Failed to list cheques due to an internal error.")
+	}
+	return c.Status(fiber.StatusOK).JSON(cheques)
+}
+
+func (h *ChequeHandler) HandleGetCheque(c *fiber.Ctx) error {
+	id, err := parseIDParam(c, "id")
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(model.ErrorResponse{Message: "Invalid cheque id", Details: err.Error()})
+	}
+	cheque, err := h.chequeSvc.GetCheque(c.Context(), uint(c.QueryInt("bidId")), id)
+	if err != nil {
+		return writeServiceError(c, err, "Failed to get cheque due to an internal error.")
+	}
+	return c.Status(fiber.StatusOK).JSON(cheque)
+}
+
+func (h *ChequeHandler) HandleChangeChequeStatus(c *fiber.Ctx) error {
+	id, err := parseIDParam(c, "id")
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(model.ErrorResponse{Message: "Invalid cheque id", Details: err.Error()})
+	}
+	var req model.ChequeTransitionRequest
+	if err := c.BodyParser(&req); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(model.ErrorResponse{Message: "Invalid request body", Details: err.Error()})
+	}
+
+	cheque, err := h.chequeSvc.ChangeStatus(c.Context(), uint(c.QueryInt("bidId")), id, &req, actorFromCtx(c))
+	if err != nil {
+		utils.Log.Error("Failed to change cheque status", zap.Uint("cheque_id", id), zap.String("to", req.Status), zap.Error(err))
+		return writeServiceError(c, err, "Failed to change cheque status due to an internal error.")
+	}
+	return c.Status(fiber.StatusOK).JSON(cheque)
+}
+
+func (h *ChequeHandler) HandleGetChequeEntries(c *fiber.Ctx) error {
+	id, err := parseIDParam(c, "id")
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(model.ErrorResponse{Message: "Invalid cheque id", Details: err.Error()})
+	}
+	entries, err := h.chequeSvc.GetChequeEntries(c.Context(), uint(c.QueryInt("bidId")), id)
+	if err != nil {
+		return writeServiceError(c, err, "Failed to get cheque entries due to an internal error.")
+	}
+	return c.Status(fiber.StatusOK).JSON(entries)
+}
+
+func (h *ChequeHandler) HandleGetDueSummary(c *fiber.Ctx) error {
+	summary, err := h.alertSvc.GetDueSummary(c.Context(), uint(c.QueryInt("bidId")), time.Now())
+	if err != nil {
+		utils.Log.Error("Failed to build cheque due summary", zap.Error(err))
+		return writeServiceError(c, err, "Failed to build cheque due summary due to an internal error.")
+	}
+	return c.Status(fiber.StatusOK).JSON(summary)
+}
+
+func (h *ChequeHandler) HandleRunDueAlerts(c *fiber.Ctx) error {
+	sent, err := h.alertSvc.RunDueAlerts(c.Context(), time.Now())
+	if err != nil {
+		utils.Log.Error("Failed to run cheque due alerts", zap.Error(err))
+		return writeServiceError(c, err, "Failed to run cheque due alerts due to an internal error.")
+	}
+	return c.Status(fiber.StatusOK).JSON(fiber.Map{"sent": sent})
+}
+
+func parseIDParam(c *fiber.Ctx, name string) (uint, error) {
+	id, err := strconv.ParseUint(c.Params(name), 10, 64)
+	if err != nil || id == 0 {
+		return 0, errors.New("id must be a positive integer")
+	}
+	return uint(id), nil
+}
+
+func actorFromCtx(c *fiber.Ctx) string {
+	if userID, ok := c.Locals("userID").(string); ok {
+		return userID
+	}
+	return ""
+}
+
+// writeServiceError خطاهای شناخته‌شده لایه سرویس را به کد وضعیت HTTP مناسب تبدیل می‌کند.
+func writeServiceError(c *fiber.Ctx, err error, fallback string) error {
+	switch {
+	case errors.Is(err, service.ErrNotFound):
+		return c.Status(fiber.StatusNotFound).JSON(model.ErrorResponse{Message: err.Error()})
+	case errors.Is(err, service.ErrValidation):
+		return c.Status(fiber.StatusBadRequest).JSON(model.ErrorResponse{Message: err.Error()})
+	case errors.Is(err, service.ErrForbidden):
+		return c.Status(fiber.StatusForbidden).JSON(model.ErrorResponse{Message: err.Error()})
+	case errors.Is(err, service.ErrInvalidTransition), errors.Is(err, service.ErrConflict),
+		errors.Is(err, model.ErrFiscalYearClosed), errors.Is(err, model.ErrLedgerImmutable):
+		return c.Status(fiber.StatusConflict).JSON(model.ErrorResponse{Message: err.Error()})
+	}
+	return c.Status(fiber.StatusInternalServerError).JSON(model.ErrorResponse{
+		Message: fallback,
+		Details: err.Error(),
+	})
+}
@@ -0,0 +1,114 @@
+package handler
+
+import (
+	"time"
+
+	"crm-gold/internal/model"
+	"crm-gold/internal/service"
+	"crm-gold/internal/utils"
+
+	"github.com/gofiber/fiber/v2"
+	"go.uber.org/zap"
+)
+
+type CurrencyHandler struct {
+	currencySvc service.CurrencyService
+}
+
+func NewCurrencyHandler(currencySvc service.CurrencyService) *CurrencyHandler {
+	if currencySvc == nil {
+		utils.Log.Fatal("currencySvc cannot be nil for CurrencyHandler in CrmManager.")
+	}
+	return &CurrencyHandler{currencySvc: currencySvc}
+}
+
+// parseDateQuery تاریخ اختیاری؛ در نبود آن امروز در نظر گرفته می‌شود.
+func parseDateQuery(c *fiber.Ctx, name string) (time.Time, error) {
+	v := c.Query(name)
+	if v == "" {
+		return utils.StartOfDay(time.Now()), nil
+	}
+	t, err := utils.ParseDateParam(v)
+	if err != nil {
+		return time.Time{}, err
+	}
+	return utils.StartOfDay(t), nil
+}
+
+func (h *CurrencyHandler) HandleListCurrencies(c *fiber.Ctx) error {
+	currencies, err := h.currencySvc.ListCurrencies(c.Context())
+	if err != nil {
+		return writeServiceError(c, err, "Failed to list currencies due to an internal error.")
+	}
+	return c.Status(fiber.StatusOK).JSON(currencies)
+}
+
+// HandleSetRate نرخ روز یک ارز را ثبت می‌کند؛ ثبت دوباره برای همان روز نرخ قبلی را جایگزین می‌کند.
+func (h *CurrencyHandler) HandleSetRate(c *fiber.Ctx) error {
+	var req model.SetExchangeRateRequest
+	if err := c.BodyParser(&req); err != nil {
+		utils.Log.Error("Failed to parse request body for exchange rate", zap.Error(err))
+		return c.Status(fiber.StatusBadRequest).JSON(model.ErrorResponse{Message: "Invalid request body", Details: err.Error()})
+	}
+	rate, err := h.currencySvc.SetRate(c.Context(), &req, actorFromCtx(c))
+	if err != nil {
+		utils.Log.Error("Failed to save exchange rate via service layer", zap.Error(err))
+		return writeServiceError(c, err, "Failed to save exchange rate due to an internal error.")
+	}
+	return c.Status(fiber.StatusOK).JSON(rate)
+}
+
+func (h *CurrencyHandler) HandleListRates(c *fiber.Ctx) error {
+	from, to, err := parseOptionalRange(c)
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(model.ErrorResponse{Message: "Invalid date range", Details: err.Error()})
+	}
+	filter := model.ExchangeRateFilter{
+		BIDID:      uint(c.QueryInt("bidId")),
+		CurrencyID: uint(c.QueryInt("currencyId")),
+		From:       from,
+		To:         to,
+	}
+	rates, err := h.currencySvc.ListRates(c.Context(), filter)
+	if err != nil {
+		return writeServiceError(c, err, "Failed to list exchange rates due to an internal error.")
+	}
+	return c.Status(fiber.StatusOK).JSON(rates)
+}
+
+// HandleRateAt آخرین نرخ ثبت‌شده تا تاریخ date (پیش‌فرض امروز)
+func (h *CurrencyHandler) HandleRateAt(c *fiber.Ctx) error {
+	at, err := parseDateQuery(c, "date")
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(model.ErrorResponse{Message: "Invalid date", Details: err.Error()})
+	}
+	rate, err := h.currencySvc.RateAt(c.Context(), uint(c.QueryInt("bidId")), uint(c.QueryInt("currencyId")), at)
+	if err != nil {
+		return writeServiceError(c, err, "Failed to get exchange rate due to an internal error.")
+	}
+	return c.Status(fiber.StatusOK).JSON(rate)
+}
+
+func (h *CurrencyHandler) HandleRevaluation(c *fiber.Ctx) error {
+	asOf, err := parseDateQuery(c, "asOf")
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(model.ErrorResponse{Message: "Invalid date", Details: err.Error()})
+	}
+	report, err := h.currencySvc.Revaluation(c.Context(), uint(c.QueryInt("bidId")), asOf)
+	if err != nil {
+		return writeServiceError(c, err, "Failed to compute currency revaluation due to an internal error.")
+	}
+	return c.Status(fiber.StatusOK).JSON(report)
+}
+
+func (h *CurrencyHandler) HandleExportRevaluation(c *fiber.Ctx) error {
+	asOf, err := parseDateQuery(c, "asOf")
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(model.ErrorResponse{Message: "Invalid date", Details: err.Error()})
+	}
+	report, err := h.currencySvc.Revaluation(c.Context(), uint(c.QueryInt("bidId")), asOf)
+	if err != nil {
+		return writeServiceError(c, err, "Failed to compute currency revaluation due to an internal error.")
+	}
+	return writeExport(c, service.CurrencyRevaluationTable(report), "currency-revaluation")
+}
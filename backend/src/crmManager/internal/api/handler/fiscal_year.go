@@ -0,0 +1,97 @@
+package handler
+
+import (
+	"crm-gold/internal/model"
+	"crm-gold/internal/service"
+	"crm-gold/internal/utils"
+
+	"github.com/gofiber/fiber/v2"
+	"go.uber.org/zap"
+)
+
+type FiscalYearHandler struct {
+	fiscalYearSvc service.FiscalYearService
+}
+
+func NewFiscalYearHandler(fiscalYearSvc service.FiscalYearService) *FiscalYearHandler {
+	if fiscalYearSvc == nil {
+		utils.Log.Fatal("fiscalYearSvc cannot be nil for FiscalYearHandler in CrmManager.")
+	}
+	return &FiscalYearHandler{fiscalYearSvc: fiscalYearSvc}
+}
+
+func (h *FiscalYearHandler) HandleCreateFiscalYear(c *fiber.Ctx) error {
+	var req model.CreateFiscalYearRequest
+	if err := c.BodyParser(&req); err != nil {
+		utils.Log.Error("Failed to parse request body for fiscal year creation", zap.Error(err))
+		return c.Status(fiber.StatusBadRequest).JSON(model.ErrorResponse{Message: "Invalid request body", Details: err.Error()})
+	}
+	fy, err := h.fiscalYearSvc.CreateFiscalYear(c.Context(), &req, actorFromCtx(c))
+	if err != nil {
+		utils.Log.Error("Failed to create fiscal year via service layer", zap.Error(err))
+		return writeServiceError(c, err, "Failed to create fiscal year due to an internal error.")
+	}
+	return c.Status(fiber.StatusCreated).JSON(fy)
+}
+
+func (h *FiscalYearHandler) HandleListFiscalYears(c *fiber.Ctx) error {
+	years, err := h.fiscalYearSvc.ListFiscalYears(c.Context(), uint(c.QueryInt("bidId")))
+	if err != nil {
+		return writeServiceError(c, err, "Failed to list fiscal years due to an internal error.")
+	}
+	return c.Status(fiber.StatusOK).JSON(years)
+}
+
+func (h *FiscalYearHandler) HandleGetFiscalYear(c *fiber.Ctx) error {
+	id, err := parseIDParam(c, "id")
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(model.ErrorResponse{Message: "Invalid fiscal year id", Details: err.Error()})
+	}
+	fy, err := h.fiscalYearSvc.GetFiscalYear(c.Context(), id)
+	if err != nil {
+		return writeServiceError(c, err, "Failed to get fiscal year due to an internal error.")
+	}
+	return c.Status(fiber.StatusOK).JSON(fy)
+}
+
+func (h *FiscalYearHandler) HandleListEvents(c *fiber.Ctx) error {
+	id, err := parseIDParam(c, "id")
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(model.ErrorResponse{Message: "Invalid fiscal year id", Details: err.Error()})
+	}
+	events, err := h.fiscalYearSvc.ListEvents(c.Context(), id)
+	if err != nil {
+		return writeServiceError(c, err, "Failed to list fiscal year events due to an internal error.")
+	}
+	return c.Status(fiber.StatusOK).JSON(events)
+}
+
+func (h *FiscalYearHandler) HandleCloseFiscalYear(c *fiber.Ctx) error {
+	id, err := parseIDParam(c, "id")
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(model.ErrorResponse{Message: "Invalid fiscal year id", Details: err.Error()})
+	}
+	fy, err := h.fiscalYearSvc.CloseFiscalYear(c.Context(), id, actorFromCtx(c))
+	if err != nil {
+		utils.Log.Error("Failed to close fiscal year", zap.Uint("fiscal_year_id", id), zap.Error(err))
+		return writeServiceError(c, err, "Failed to close fiscal year due to an internal error.")
+	}
+	return c.Status(fiber.StatusOK).JSON(fy)
+}
+
+func (h *FiscalYearHandler) HandleReopenFiscalYear(c *fiber.Ctx) error {
+	id, err := parseIDParam(c, "id")
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(model.ErrorResponse{Message: "Invalid fiscal year id", Details: err.Error()})
+	}
+	var req model.ReopenFiscalYearRequest
+	if err := c.BodyParser(&req); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(model.ErrorResponse{Message: "Invalid request body", Details: err.Error()})
+	}
+	fy, err := h.fiscalYearSvc.ReopenFiscalYear(c.Context(), id, &req, actorFromCtx(c))
+	if err != nil {
+		utils.Log.Error("Failed to reopen fiscal year", zap.Uint("fiscal_year_id", id), zap.Error(err))
+		return writeServiceError(c, err, "Failed to reopen fiscal year due to an internal error.")
+	}
+	return c.Status(fiber.StatusOK).JSON(fy)
+}
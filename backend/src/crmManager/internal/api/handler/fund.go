@@ -0,0 +1,195 @@
+package handler
+
+import (
+	"time"
+
+	"crm-gold/internal/model"
+	"crm-gold/internal/service"
+	"crm-gold/internal/utils"
+
+	"github.com/gofiber/fiber/v2"
+	"go.uber.org/zap"
+)
+
+type FundHandler struct {
+	fundSvc service.FundService
+}
+
+func NewFundHandler(fundSvc service.FundService) *FundHandler {
+	if fundSvc == nil {
+		utils.Log.Fatal("fundSvc cannot be nil for FundHandler in CrmManager.")
+	}
+	return &FundHandler{fundSvc: fundSvc}
+}
+
+func (h *FundHandler) HandleCreateFund(c *fiber.Ctx) error {
+	var req model.CreateFundRequest
+	if err := c.BodyParser(&req); err != nil {
+		utils.Log.Error("Failed to parse request body for fund creation", zap.Error(err))
+		return c.Status(fiber.StatusBadRequest).JSON(model.ErrorResponse{Message: "Invalid request body", Details: err.Error()})
+	}
+	fund, err := h.fundSvc.CreateFund(c.Context(), &req, actorFromCtx(c))
+	if err != nil {
+		utils.Log.Error("Failed to create fund via service layer", zap.Error(err))
+		return writeServiceError(c, err, "Failed to create fund due to an internal error.")
+	}
+	return c.Status(fiber.StatusCreated).JSON(fund)
+}
+
+func (h *FundHandler) HandleListFunds(c *fiber.Ctx) error {
+	funds, err := h.fundSvc.ListFunds(c.Context(), uint(c.QueryInt("bidId")))
+	if err != nil {
+		return writeServiceError(c, err, "Failed to list funds due to an internal error.")
+	}
+	return c.Status(fiber.StatusOK).JSON(funds)
+}
+
+func (h *FundHandler) HandleGetFund(c *fiber.Ctx) error {
+	id, err := parseIDParam(c, "id")
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(model.ErrorResponse{Message: "Invalid fund id", Details: err.Error()})
+	}
+	fund, err := h.fundSvc.GetFund(c.Context(), id)
+	if err != nil {
+		return writeServiceError(c, err, "Failed to get fund due to an internal error.")
+	}
+	return c.Status(fiber.StatusOK).JSON(fund)
+}
+
+func (h *FundHandler) HandleCreateVoucher(c *fiber.Ctx) error {
+	id, err := parseIDParam(c, "id")
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(model.ErrorResponse{Message: "Invalid fund id", Details: err.Error()})
+	}
+	var req model.CashVoucherRequest
+	if err := c.BodyParser(&req); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(model.ErrorResponse{Message: "Invalid request body", Details: err.Error()})
+	}
+	voucher, err := h.fundSvc.CreateVoucher(c.Context(), id, &req, actorFromCtx(c))
+	if err != nil {
+		utils.Log.Error("Failed to create cash voucher", zap.Uint("fund_id", id), zap.Error(err))
+		return writeServiceError(c, err, "Failed to create cash voucher due to an internal error.")
+	}
+	return c.Status(fiber.StatusCreated).JSON(voucher)
+}
+
+func (h *FundHandler) HandleListVouchers(c *fiber.Ctx) error {
+	id, err := parseIDParam(c, "id")
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(model.ErrorResponse{Message: "Invalid fund id", Details: err.Error()})
+	}
+	from, to, err := parseOptionalRange(c)
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(model.ErrorResponse{Message: "Invalid date range", Details: err.Error()})
+	}
+	vouchers, err := h.fundSvc.ListVouchers(c.Context(), id, from, to)
+	if err != nil {
+		return writeServiceError(c, err, "Failed to list cash vouchers due to an internal error.")
+	}
+	return c.Status(fiber.StatusOK).JSON(vouchers)
+}
+
+func (h *FundHandler) HandleUpdateVoucher(c *fiber.Ctx) error {
+	id, err := parseIDParam(c, "voucherId")
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(model.ErrorResponse{Message: "Invalid voucher id", Details: err.Error()})
+	}
+	var req model.CashVoucherRequest
+	if err := c.BodyParser(&req); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(model.ErrorResponse{Message: "Invalid request body", Details: err.Error()})
+	}
+	voucher, err := h.fundSvc.UpdateVoucher(c.Context(), id, &req, actorFromCtx(c))
+	if err != nil {
+		utils.Log.Error("Failed to update cash voucher", zap.Uint("voucher_id", id), zap.Error(err))
+		return writeServiceError(c, err, "Failed to update cash voucher due to an internal error.")
+	}
+	return c.Status(fiber.StatusOK).JSON(voucher)
+}
+
+func (h *FundHandler) HandleDeleteVoucher(c *fiber.Ctx) error {
+	id, err := parseIDParam(c, "voucherId")
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(model.ErrorResponse{Message: "Invalid voucher id", Details: err.Error()})
+	}
+	if err := h.fundSvc.DeleteVoucher(c.Context(), id, actorFromCtx(c)); err != nil {
+		utils.Log.Error("Failed to delete cash voucher", zap.Uint("voucher_id", id), zap.Error(err))
+		return writeServiceError(c, err, "Failed to delete cash voucher due to an internal error.")
+	}
+	return c.SendStatus(fiber.StatusNoContent)
+}
+
+func (h *FundHandler) HandleGetStatement(c *fiber.Ctx) error {
+	id, err := parseIDParam(c, "id")
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(model.ErrorResponse{Message: "Invalid fund id", Details: err.Error()})
+	}
+	from, to, err := parseRequiredRange(c)
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(model.ErrorResponse{Message: "Invalid date range", Details: err.Error()})
+	}
+	statement, err := h.fundSvc.GetStatement(c.Context(), id, optionalUintQuery(c, "currencyId"), from, to)
+	if err != nil {
+		return writeServiceError(c, err, "Failed to build fund statement due to an internal error.")
+	}
+	return c.Status(fiber.StatusOK).JSON(statement)
+}
+
+func (h *FundHandler) HandleCloseDay(c *fiber.Ctx) error {
+	id, err := parseIDParam(c, "id")
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(model.ErrorResponse{Message: "Invalid fund id", Details: err.Error()})
+	}
+	var req model.CloseFundDayRequest
+	if err := c.BodyParser(&req); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(model.ErrorResponse{Message: "Invalid request body", Details: err.Error()})
+	}
+	dayClose, err := h.fundSvc.CloseDay(c.Context(), id, &req, actorFromCtx(c))
+	if err != nil {
+		utils.Log.Error("Failed to close fund day", zap.Uint("fund_id", id), zap.Error(err))
+		return writeServiceError(c, err, "Failed to close fund day due to an internal error.")
+	}
+	return c.Status(fiber.StatusOK).JSON(dayClose)
+}
+
+// parseRequiredRange بازه from/to را (شمسی یا میلادی) از کوئری می‌خواند.
+func parseRequiredRange(c *fiber.Ctx) (time.Time, time.Time, error) {
+	from, err := utils.ParseDateParam(c.Query("from"))
+	if err != nil {
+		return time.Time{}, time.Time{}, err
+	}
+	to, err := utils.ParseDateParam(c.Query("to"))
+	if err != nil {
+		return time.Time{}, time.Time{}, err
+	}
+	return from, to, nil
+}
+
+// parseOptionalRange مانند parseRequiredRange است ولی پایان بازه را منحصر (روز بعد) برمی‌گرداند.
+func parseOptionalRange(c *fiber.Ctx) (*time.Time, *time.Time, error) {
+	var from, to *time.Time
+	if v := c.Query("from"); v != "" {
+		t, err := utils.ParseDateParam(v)
+		if err != nil {
+			return nil, nil, err
+		}
+		from = &t
+	}
+	if v := c.Query("to"); v != "" {
+		t, err := utils.ParseDateParam(v)
+		if err != nil {
+			return nil, nil, err
+		}
+		t = utils.StartOfDay(t).AddDate(0, 0, 1)
+		to = &t
+	}
+	return from, to, nil
+}
+
+func optionalUintQuery(c *fiber.Ctx, name string) *uint {
+	v := c.QueryInt(name)
+	if v <= 0 {
+		return nil
+	}
+	u := uint(v)
+	return &u
+}
@@ -0,0 +1,114 @@
+package handler
+
+import (
+	"crm-gold/internal/model"
+	"crm-gold/internal/service"
+	"crm-gold/internal/utils"
+
+	"common-gold/money"
+
+	"github.com/gofiber/fiber/v2"
+	"go.uber.org/zap"
+)
+
+type InventoryHandler struct {
+	inventorySvc service.InventoryService
+}
+
+func NewInventoryHandler(inventorySvc service.InventoryService) *InventoryHandler {
+	if inventorySvc == nil {
+		utils.Log.Fatal("inventorySvc cannot be nil for InventoryHandler in CrmManager.")
+	}
+	return &InventoryHandler{inventorySvc: inventorySvc}
+}
+
+func (h *InventoryHandler) HandleCreateItem(c *fiber.Ctx) error {
+	var req model.CreateInventoryItemRequest
+	if err := c.BodyParser(&req); err != nil {
+		utils.Log.Error("Failed to parse request body for inventory item creation", zap.Error(err))
+		return c.Status(fiber.StatusBadRequest).JSON(model.ErrorResponse{Message: "Invalid request body", Details: err.Error()})
+	}
+	item, err := h.inventorySvc.CreateItem(c.Context(), &req, actorFromCtx(c))
+	if err != nil {
+		utils.Log.Error("Failed to create inventory item via service layer", zap.Error(err))
+		return writeServiceError(c, err, "Failed to create inventory item due to an internal error.")
+	}
+	return c.Status(fiber.StatusCreated).JSON(item)
+}
+
+func (h *InventoryHandler) HandleListItems(c *fiber.Ctx) error {
+	filter := model.InventoryItemFilter{
+		BIDID:     uint(c.QueryInt("bidId")),
+		Category:  c.Query("category"),
+		Status:    c.Query("status"),
+		Ownership: c.Query("ownership"),
+	}
+	items, err := h.inventorySvc.ListItems(c.Context(), filter)
+	if err != nil {
+		return writeServiceError(c, err, "Failed to list inventory items due to an internal error.")
+	}
+	return c.Status(fiber.StatusOK).JSON(items)
+}
+
+func (h *InventoryHandler) HandleGetItem(c *fiber.Ctx) error {
+	id, err := parseIDParam(c, "id")
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(model.ErrorResponse{Message: "Invalid item id", Details: err.Error()})
+	}
+	item, err := h.inventorySvc.GetItem(c.Context(), id)
+	if err != nil {
+		return writeServiceError(c, err, "Failed to get inventory item due to an internal error.")
+	}
+	return c.Status(fiber.StatusOK).JSON(item)
+}
+
+func (h *InventoryHandler) HandleUpdateItem(c *fiber.Ctx) error {
+	id, err := parseIDParam(c, "id")
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(model.ErrorResponse{Message: "Invalid item id", Details: err.Error()})
+	}
+	var req model.UpdateInventoryItemRequest
+	if err := c.BodyParser(&req); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(model.ErrorResponse{Message: "Invalid request body", Details: err.Error()})
+	}
+	item, err := h.inventorySvc.UpdateItem(c.Context(), id, &req)
+	if err != nil {
+		utils.Log.Error("Failed to update inventory item", zap.Uint("item_id", id), zap.Error(err))
+		return writeServiceError(c, err, "Failed to update inventory item due to an internal error.")
+	}
+	return c.Status(fiber.StatusOK).JSON(item)
+}
+
+// HandleValuation ارزش موجودی؛ goldRate اختیاری است و در نبود آن نرخ روز تنظیمات کسب‌وکار استفاده می‌شود.
+func (h *InventoryHandler) HandleValuation(c *fiber.Ctx) error {
+	valuation, err := h.inventorySvc.Valuation(c.Context(), uint(c.QueryInt("bidId")), money.NewAmount(c.QueryFloat("goldRate")))
+	if err != nil {
+		return writeServiceError(c, err, "Failed to compute inventory valuation due to an internal error.")
+	}
+	return c.Status(fiber.StatusOK).JSON(valuation)
+}
+
+func (h *InventoryHandler) HandleExportValuation(c *fiber.Ctx) error {
+	valuation, err := h.inventorySvc.Valuation(c.Context(), uint(c.QueryInt("bidId")), money.NewAmount(c.QueryFloat("goldRate")))
+	if err != nil {
+		return writeServiceError(c, err, "Failed to compute inventory valuation due to an internal error.")
+	}
+	return writeExport(c, service.InventoryValuationTable(valuation), "inventory-valuation")
+}
+
+// HandleSlowMovers کالاهایی که days روز (پیش‌فرض ۹۰) فروش نرفته‌اند.
+func (h *InventoryHandler) HandleSlowMovers(c *fiber.Ctx) error {
+	result, err := h.inventorySvc.SlowMovers(c.Context(), uint(c.QueryInt("bidId")), c.QueryInt("days"))
+	if err != nil {
+		return writeServiceError(c, err, "Failed to list slow-moving items due to an internal error.")
+	}
+	return c.Status(fiber.StatusOK).JSON(result)
+}
+
+func (h *InventoryHandler) HandleExportSlowMovers(c *fiber.Ctx) error {
+	result, err := h.inventorySvc.SlowMovers(c.Context(), uint(c.QueryInt("bidId")), c.QueryInt("days"))
+	if err != nil {
+		return writeServiceError(c, err, "Failed to list slow-moving items due to an internal error.")
+	}
+	return writeExport(c, service.SlowMoversTable(result), "slow-movers")
+}
@@ -0,0 +1,137 @@
+package handler
+
+import (
+	"crm-gold/internal/model"
+	"crm-gold/internal/service"
+	"crm-gold/internal/utils"
+
+	"github.com/gofiber/fiber/v2"
+	"go.uber.org/zap"
+)
+
+type LedgerHandler struct {
+	ledgerSvc service.LedgerService
+}
+
+func NewLedgerHandler(ledgerSvc service.LedgerService) *LedgerHandler {
+	if ledgerSvc == nil {
+		utils.Log.Fatal("ledgerSvc cannot be nil for LedgerHandler in CrmManager.")
+	}
+	return &LedgerHandler{ledgerSvc: ledgerSvc}
+}
+
+func (h *LedgerHandler) HandleListAccounts(c *fiber.Ctx) error {
+	accounts, err := h.ledgerSvc.ListAccounts(c.Context(), uint(c.QueryInt("bidId")))
+	if err != nil {
+		return writeServiceError(c, err, "Failed to list accounts due to an internal error.")
+	}
+	return c.Status(fiber.StatusOK).JSON(accounts)
+}
+
+func (h *LedgerHandler) HandleCreateAccount(c *fiber.Ctx) error {
+	var req model.CreateAccountRequest
+	if err := c.BodyParser(&req); err != nil {
+		utils.Log.Error("Failed to parse request body for account creation", zap.Error(err))
+		return c.Status(fiber.StatusBadRequest).JSON(model.ErrorResponse{Message: "Invalid request body", Details: err.Error()})
+	}
+	account, err := h.ledgerSvc.CreateAccount(c.Context(), &req)
+	if err != nil {
+		utils.Log.Error("Failed to create account via service layer", zap.Error(err))
+		return writeServiceError(c, err, "Failed to create account due to an internal error.")
+	}
+	return c.Status(fiber.StatusCreated).JSON(account)
+}
+
+func (h *LedgerHandler) HandleUpdateAccount(c *fiber.Ctx) error {
+	id, err := parseIDParam(c, "id")
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(model.ErrorResponse{Message: "Invalid account id", Details: err.Error()})
+	}
+	var req model.UpdateAccountRequest
+	if err := c.BodyParser(&req); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(model.ErrorResponse{Message: "Invalid request body", Details: err.Error()})
+	}
+	account, err := h.ledgerSvc.UpdateAccount(c.Context(), id, &req)
+	if err != nil {
+		utils.Log.Error("Failed to update account", zap.Uint("account_id", id), zap.Error(err))
+		return writeServiceError(c, err, "Failed to update account due to an internal error.")
+	}
+	return c.Status(fiber.StatusOK).JSON(account)
+}
+
+// HandleGetAccountLedger دفتر حساب؛ detailId برای محدود کردن به یک شخص، صندوق، بانک یا مرکز است.
+func (h *LedgerHandler) HandleGetAccountLedger(c *fiber.Ctx) error {
+	from, to, err := parseRequiredRange(c)
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(model.ErrorResponse{Message: "Invalid date range", Details: err.Error()})
+	}
+	from = utils.StartOfDay(from)
+	to = utils.StartOfDay(to).AddDate(0, 0, 1)
+	ledger, err := h.ledgerSvc.GetAccountLedger(c.Context(), uint(c.QueryInt("bidId")), c.Params("code"), optionalUintQuery(c, "detailId"), from, to)
+	if err != nil {
+		return writeServiceError(c, err, "Failed to get account ledger due to an internal error.")
+	}
+	return c.Status(fiber.StatusOK).JSON(ledger)
+}
+
+func (h *LedgerHandler) HandleCreateJournalEntry(c *fiber.Ctx) error {
+	var req model.CreateJournalEntryRequest
+	if err := c.BodyParser(&req); err != nil {
+		utils.Log.Error("Failed to parse request body for journal entry", zap.Error(err))
+		return c.Status(fiber.StatusBadRequest).JSON(model.ErrorResponse{Message: "Invalid request body", Details: err.Error()})
+	}
+	entry, err := h.ledgerSvc.CreateJournalEntry(c.Context(), &req, actorFromCtx(c))
+	if err != nil {
+		utils.Log.Error("Failed to post journal entry via service layer", zap.Error(err))
+		return writeServiceError(c, err, "Failed to post journal entry due to an internal error.")
+	}
+	return c.Status(fiber.StatusCreated).JSON(entry)
+}
+
+func (h *LedgerHandler) HandleListEntries(c *fiber.Ctx) error {
+	from, to, err := parseOptionalRange(c)
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(model.ErrorResponse{Message: "Invalid date range", Details: err.Error()})
+	}
+	filter := model.JournalFilter{
+		BIDID:       uint(c.QueryInt("bidId")),
+		DocType:     c.Query("docType"),
+		AccountCode: c.Query("accountCode"),
+		From:        from,
+		To:          to,
+	}
+	entries, err := h.ledgerSvc.ListEntries(c.Context(), filter)
+	if err != nil {
+		return writeServiceError(c, err, "Failed to list journal entries due to an internal error.")
+	}
+	return c.Status(fiber.StatusOK).JSON(entries)
+}
+
+func (h *LedgerHandler) HandleGetEntry(c *fiber.Ctx) error {
+	id, err := parseIDParam(c, "id")
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(model.ErrorResponse{Message: "Invalid entry id", Details: err.Error()})
+	}
+	entry, err := h.ledgerSvc.GetEntry(c.Context(), id)
+	if err != nil {
+		return writeServiceError(c, err, "Failed to get journal entry due to an internal error.")
+	}
+	return c.Status(fiber.StatusOK).JSON(entry)
+}
+
+func (h *LedgerHandler) HandleReverseEntry(c *fiber.Ctx) error {
+	id, err := parseIDParam(c, "id")
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(model.ErrorResponse{Message: "Invalid entry id", Details: err.Error()})
+	}
+	var req model.ReverseJournalEntryRequest
+	if err := c.BodyParser(&req); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(model.ErrorResponse{Message: "Invalid request body", Details: err.Error()})
+	}
+	reversal, err := h.ledgerSvc.ReverseEntry(c.Context(), id, &req, actorFromCtx(c))
+	if err != nil {
+		utils.Log.Error("Failed to reverse journal entry", zap.Uint("entry_id", id), zap.Error(err))
+		return writeServiceError(c, err, "Failed to reverse journal entry due to an internal error.")
+	}
+	return c.Status(fiber.StatusCreated).JSON(reversal)
+}
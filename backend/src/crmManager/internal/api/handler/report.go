@@ -0,0 +1,137 @@
+package handler
+
+import (
+	"bytes"
+	"fmt"
+
+	"crm-gold/internal/export"
+	"crm-gold/internal/model"
+	"crm-gold/internal/service"
+	"crm-gold/internal/utils"
+
+	"common-gold/money"
+
+	"github.com/gofiber/fiber/v2"
+	"go.uber.org/zap"
+)
+
+type ReportHandler struct {
+	reportSvc service.ReportService
+}
+
+func NewReportHandler(reportSvc service.ReportService) *ReportHandler {
+	if reportSvc == nil {
+		utils.Log.Fatal("reportSvc cannot be nil for ReportHandler in CrmManager.")
+	}
+	return &ReportHandler{reportSvc: reportSvc}
+}
+
+// parseReportFilter بازه from تا to شامل هر دو روز است؛ goldRate اختیاری و به تومان برای هر گرم است.
+func parseReportFilter(c *fiber.Ctx) (model.ReportFilter, error) {
+	from, to, err := parseRequiredRange(c)
+	if err != nil {
+		return model.ReportFilter{}, err
+	}
+	return model.ReportFilter{
+		BIDID:    uint(c.QueryInt("bidId")),
+		From:     utils.StartOfDay(from),
+		To:       utils.StartOfDay(to).AddDate(0, 0, 1),
+		GoldRate: money.NewAmount(c.QueryFloat("goldRate")),
+		Columns:  c.QueryInt("columns"),
+		Level:    c.Query("level"),
+	}, nil
+}
+
+// writeExport جدول گزارش را با فرمت format (xlsx یا pdf) به عنوان فایل پیوست برمی‌گرداند.
+func writeExport(c *fiber.Ctx, table *export.Table, name string) error {
+	format := c.Query("format", export.FormatXLSX)
+	if !export.ValidFormat(format) {
+		return c.Status(fiber.StatusBadRequest).JSON(model.ErrorResponse{Message: "Invalid export format", Details: "format must be xlsx or pdf"})
+	}
+	var buf bytes.Buffer
+	var err error
+	if format == export.FormatPDF {
+		err = export.WritePDF(&buf, table)
+	} else {
+		err = export.WriteXLSX(&buf, table)
+	}
+	if err != nil {
+		utils.Log.Error("Failed to render report export", zap.String("report", name), zap.String("format", format), zap.Error(err))
+		return c.Status(fiber.StatusInternalServerError).JSON(model.ErrorResponse{Message: "Failed to export report due to an internal error."})
+	}
+	c.Set(fiber.HeaderContentType, export.ContentType(format))
+	c.Set(fiber.HeaderContentDisposition, fmt.Sprintf(`attachment; filename="%s.%s"`, name, format))
+	return c.Status(fiber.StatusOK).Send(buf.Bytes())
+}
+
+func (h *ReportHandler) trialBalance(c *fiber.Ctx) (*model.TrialBalance, error) {
+	filter, err := parseReportFilter(c)
+	if err != nil {
+		return nil, fmt.Errorf("%w: invalid date range: %v", service.ErrValidation, err)
+	}
+	return h.reportSvc.TrialBalance(c.Context(), filter)
+}
+
+func (h *ReportHandler) profitLoss(c *fiber.Ctx) (*model.ProfitLoss, error) {
+	filter, err := parseReportFilter(c)
+	if err != nil {
+		return nil, fmt.Errorf("%w: invalid date range: %v", service.ErrValidation, err)
+	}
+	return h.reportSvc.ProfitLoss(c.Context(), filter)
+}
+
+func (h *ReportHandler) balanceSheet(c *fiber.Ctx) (*model.BalanceSheet, error) {
+	filter, err := parseReportFilter(c)
+	if err != nil {
+		return nil, fmt.Errorf("%w: invalid date range: %v", service.ErrValidation, err)
+	}
+	return h.reportSvc.BalanceSheet(c.Context(), filter)
+}
+
+func (h *ReportHandler) HandleTrialBalance(c *fiber.Ctx) error {
+	tb, err := h.trialBalance(c)
+	if err != nil {
+		return writeServiceError(c, err, "Failed to compute trial balance due to an internal error.")
+	}
+	return c.Status(fiber.StatusOK).JSON(tb)
+}
+
+func (h *ReportHandler) HandleExportTrialBalance(c *fiber.Ctx) error {
+	tb, err := h.trialBalance(c)
+	if err != nil {
+		return writeServiceError(c, err, "Failed to compute trial balance due to an internal error.")
+	}
+	return writeExport(c, service.TrialBalanceTable(tb), "trial-balance")
+}
+
+func (h *ReportHandler) HandleProfitLoss(c *fiber.Ctx) error {
+	pl, err := h.profitLoss(c)
+	if err != nil {
+		return writeServiceError(c, err, "Failed to compute profit and loss due to an internal error.")
+	}
+	return c.Status(fiber.StatusOK).JSON(pl)
+}
+
+func (h *ReportHandler) HandleExportProfitLoss(c *fiber.Ctx) error {
+	pl, err := h.profitLoss(c)
+	if err != nil {
+		return writeServiceError(c, err, "Failed to compute profit and loss due to an internal error.")
+	}
+	return writeExport(c, service.ProfitLossTable(pl), "profit-loss")
+}
+
+func (h *ReportHandler) HandleBalanceSheet(c *fiber.Ctx) error {
+	bs, err := h.balanceSheet(c)
+	if err != nil {
+		return writeServiceError(c, err, "Failed to compute balance sheet due to an internal error.")
+	}
+	return c.Status(fiber.StatusOK).JSON(bs)
+}
+
+func (h *ReportHandler) HandleExportBalanceSheet(c *fiber.Ctx) error {
+	bs, err := h.balanceSheet(c)
+	if err != nil {
+		return writeServiceError(c, err, "Failed to compute balance sheet due to an internal error.")
+	}
+	return writeExport(c, service.BalanceSheetTable(bs), "balance-sheet")
+}
@@ -0,0 +1,153 @@
+package handler
+
+import (
+	"errors"
+	"fmt"
+
+	"crm-gold/internal/api/authz"
+	"crm-gold/internal/model"
+	"crm-gold/internal/service"
+	"crm-gold/internal/utils"
+
+	"github.com/gofiber/fiber/v2"
+	"go.uber.org/zap"
+)
+
+// salesAllPermission مجوزی که دیدن ارقام فروش همه فروشندگان را مجاز می‌کند؛ بقیه فقط ارقام خود را می‌بینند.
+const salesAllPermission = model.PermReportViewAllSales
+
+type SaleHandler struct {
+	saleSvc     service.SaleService
+	permService authz.PermissionService
+}
+
+func NewSaleHandler(saleSvc service.SaleService, permService authz.PermissionService) *SaleHandler {
+	if saleSvc == nil {
+		utils.Log.Fatal("saleSvc cannot be nil for SaleHandler in CrmManager.")
+	}
+	if permService == nil {
+		utils.Log.Fatal("permService cannot be nil for SaleHandler in CrmManager.")
+	}
+	return &SaleHandler{saleSvc: saleSvc, permService: permService}
+}
+
+func (h *SaleHandler) HandleCreateInvoice(c *fiber.Ctx) error {
+	var req model.CreateSaleInvoiceRequest
+	if err := c.BodyParser(&req); err != nil {
+		utils.Log.Error("Failed to parse request body for sale invoice creation", zap.Error(err))
+		return c.Status(fiber.StatusBadRequest).JSON(model.ErrorResponse{Message: "Invalid request body", Details: err.Error()})
+	}
+	invoice, err := h.saleSvc.CreateInvoice(c.Context(), &req, actorFromCtx(c))
+	if err != nil {
+		utils.Log.Error("Failed to create sale invoice via service layer", zap.Error(err))
+		return writeServiceError(c, err, "Failed to create sale invoice due to an internal error.")
+	}
+	return c.Status(fiber.StatusCreated).JSON(invoice)
+}
+
+func (h *SaleHandler) HandleListInvoices(c *fiber.Ctx) error {
+	from, to, err := parseOptionalRange(c)
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(model.ErrorResponse{Message: "Invalid date range", Details: err.Error()})
+	}
+	filter := model.SaleInvoiceFilter{
+		BIDID:         uint(c.QueryInt("bidId")),
+		CustomerID:    uint(c.QueryInt("customerId")),
+		SalespersonID: optionalUintQuery(c, "salespersonId"),
+		Status:        c.Query("status"),
+		From:          from,
+		To:            to,
+	}
+	invoices, err := h.saleSvc.ListInvoices(c.Context(), filter)
+	if err != nil {
+		return writeServiceError(c, err, "Failed to list sale invoices due to an internal error.")
+	}
+	return c.Status(fiber.StatusOK).JSON(invoices)
+}
+
+func (h *SaleHandler) HandleGetInvoice(c *fiber.Ctx) error {
+	id, err := parseIDParam(c, "id")
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(model.ErrorResponse{Message: "Invalid invoice id", Details: err.Error()})
+	}
+	invoice, err := h.saleSvc.GetInvoice(c.Context(), id)
+	if err != nil {
+		return writeServiceError(c, err, "Failed to get sale invoice due to an internal error.")
+	}
+	return c.Status(fiber.StatusOK).JSON(invoice)
+}
+
+func (h *SaleHandler) HandleVoidInvoice(c *fiber.Ctx) error {
+	id, err := parseIDParam(c, "id")
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(model.ErrorResponse{Message: "Invalid invoice id", Details: err.Error()})
+	}
+	var req model.VoidSaleInvoiceRequest
+	if err := c.BodyParser(&req); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(model.ErrorResponse{Message: "Invalid request body", Details: err.Error()})
+	}
+	invoice, err := h.saleSvc.VoidInvoice(c.Context(), id, &req, actorFromCtx(c))
+	if err != nil {
+		utils.Log.Error("Failed to void sale invoice", zap.Uint("invoice_id", id), zap.Error(err))
+		return writeServiceError(c, err, "Failed to void sale invoice due to an internal error.")
+	}
+	return c.Status(fiber.StatusOK).JSON(invoice)
+}
+
+// salespersonScope کاربر بدون مجوز salesAllPermission به فروشنده خودش محدود می‌شود و درخواست ارقام فروشنده دیگر رد می‌شود.
+func (h *SaleHandler) salespersonScope(c *fiber.Ctx, requested *uint) (*uint, error) {
+	roles, _ := c.Locals("userRoles").([]string)
+	allowed, err := h.permService.HasPermission(actorFromCtx(c), roles, salesAllPermission)
+	if err != nil {
+		return nil, fmt.Errorf("failed to check sales permission: %w", err)
+	}
+	if allowed {
+		return requested, nil
+	}
+	self, err := h.saleSvc.SalespersonOf(c.Context(), actorFromCtx(c))
+	if err != nil {
+		if errors.Is(err, service.ErrNotFound) {
+			return nil, fmt.Errorf("%w: user is not linked to a salesperson", service.ErrForbidden)
+		}
+		return nil, err
+	}
+	if requested != nil && *requested != self {
+		return nil, fmt.Errorf("%w: salespeople can only view their own sales figures", service.ErrForbidden)
+	}
+	return &self, nil
+}
+
+func (h *SaleHandler) salesReport(c *fiber.Ctx) (*model.SalesReport, error) {
+	from, to, err := parseRequiredRange(c)
+	if err != nil {
+		return nil, fmt.Errorf("%w: invalid date range: %v", service.ErrValidation, err)
+	}
+	salespersonID, err := h.salespersonScope(c, optionalUintQuery(c, "salespersonId"))
+	if err != nil {
+		return nil, err
+	}
+	return h.saleSvc.SalesReport(c.Context(), model.SalesReportFilter{
+		BIDID:         uint(c.QueryInt("bidId")),
+		From:          utils.StartOfDay(from),
+		To:            utils.StartOfDay(to).AddDate(0, 0, 1),
+		GroupBy:       c.Query("groupBy"),
+		SalespersonID: salespersonID,
+	})
+}
+
+// HandleSalesReport گزارش فروش؛ groupBy یکی از day، week، month، category، salesperson یا customer و بازه from تا to شامل هر دو روز است.
+func (h *SaleHandler) HandleSalesReport(c *fiber.Ctx) error {
+	report, err := h.salesReport(c)
+	if err != nil {
+		return writeServiceError(c, err, "Failed to compute sales report due to an internal error.")
+	}
+	return c.Status(fiber.StatusOK).JSON(report)
+}
+
+func (h *SaleHandler) HandleExportSalesReport(c *fiber.Ctx) error {
+	report, err := h.salesReport(c)
+	if err != nil {
+		return writeServiceError(c, err, "Failed to compute sales report due to an internal error.")
+	}
+	return writeExport(c, service.SalesReportTable(report), "sales-"+report.GroupBy)
+}
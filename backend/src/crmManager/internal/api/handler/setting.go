@@ -0,0 +1,55 @@
+package handler
+
+import (
+	"crm-gold/internal/model"
+	"crm-gold/internal/service"
+	"crm-gold/internal/utils"
+
+	"github.com/gofiber/fiber/v2"
+	"go.uber.org/zap"
+)
+
+type SettingHandler struct {
+	settingSvc service.SettingService
+}
+
+func NewSettingHandler(settingSvc service.SettingService) *SettingHandler {
+	if settingSvc == nil {
+		utils.Log.Fatal("settingSvc cannot be nil for SettingHandler in CrmManager.")
+	}
+	return &SettingHandler{settingSvc: settingSvc}
+}
+
+func (h *SettingHandler) HandleGetBusinessSetting(c *fiber.Ctx) error {
+	setting, err := h.settingSvc.GetBusinessSetting(c.Context(), uint(c.QueryInt("bidId")))
+	if err != nil {
+		return writeServiceError(c, err, "Failed to get business settings due to an internal error.")
+	}
+	return c.Status(fiber.StatusOK).JSON(setting)
+}
+
+func (h *SettingHandler) HandleUpdateBusinessSetting(c *fiber.Ctx) error {
+	var req model.UpdateBusinessSettingRequest
+	if err := c.BodyParser(&req); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(model.ErrorResponse{Message: "Invalid request body", Details: err.Error()})
+	}
+	setting, err := h.settingSvc.UpdateBusinessSetting(c.Context(), &req, actorFromCtx(c))
+	if err != nil {
+		utils.Log.Error("Failed to update business settings", zap.Uint("bid_id", req.BIDID), zap.Error(err))
+		return writeServiceError(c, err, "Failed to update business settings due to an internal error.")
+	}
+	return c.Status(fiber.StatusOK).JSON(setting)
+}
+
+func (h *SettingHandler) HandleUpdateGoldRate(c *fiber.Ctx) error {
+	var req model.UpdateGoldRateRequest
+	if err := c.BodyParser(&req); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(model.ErrorResponse{Message: "Invalid request body", Details: err.Error()})
+	}
+	setting, err := h.settingSvc.UpdateGoldRate(c.Context(), &req, actorFromCtx(c))
+	if err != nil {
+		utils.Log.Error("Failed to update gold rate", zap.Uint("bid_id", req.BIDID), zap.Error(err))
+		return writeServiceError(c, err, "Failed to update gold rate due to an internal error.")
+	}
+	return c.Status(fiber.StatusOK).JSON(setting)
+}
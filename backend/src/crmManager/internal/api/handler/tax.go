@@ -0,0 +1,163 @@
+package handler
+
+import (
+	"crm-gold/internal/model"
+	"crm-gold/internal/service"
+	"crm-gold/internal/utils"
+
+	"github.com/gofiber/fiber/v2"
+	"go.uber.org/zap"
+)
+
+type TaxHandler struct {
+	taxSvc     service.TaxService
+	taxRuleSvc service.TaxRuleService
+}
+
+func NewTaxHandler(taxSvc service.TaxService, taxRuleSvc service.TaxRuleService) *TaxHandler {
+	if taxSvc == nil {
+		utils.Log.Fatal("taxSvc cannot be nil for TaxHandler in CrmManager.")
+	}
+	if taxRuleSvc == nil {
+		utils.Log.Fatal("taxRuleSvc cannot be nil for TaxHandler in CrmManager.")
+	}
+	return &TaxHandler{taxSvc: taxSvc, taxRuleSvc: taxRuleSvc}
+}
+
+func (h *TaxHandler) HandleGetSettings(c *fiber.Ctx) error {
+	setting, err := h.taxSvc.GetSettings(c.Context(), uint(c.QueryInt("bidId")))
+	if err != nil {
+		return writeServiceError(c, err, "Failed to get tax settings due to an internal error.")
+	}
+	return c.Status(fiber.StatusOK).JSON(setting)
+}
+
+func (h *TaxHandler) HandleUpdateSettings(c *fiber.Ctx) error {
+	var req model.UpdateTaxSettingRequest
+	if err := c.BodyParser(&req); err != nil {
+		utils.Log.Error("Failed to parse request body for tax settings update", zap.Error(err))
+		return c.Status(fiber.StatusBadRequest).JSON(model.ErrorResponse{Message: "Invalid request body", Details: err.Error()})
+	}
+	setting, err := h.taxSvc.UpdateSettings(c.Context(), &req, actorFromCtx(c))
+	if err != nil {
+		utils.Log.Error("Failed to update tax settings via service layer", zap.Error(err))
+		return writeServiceError(c, err, "Failed to update tax settings due to an internal error.")
+	}
+	return c.Status(fiber.StatusOK).JSON(setting)
+}
+
+// HandleSubmitInvoice فاکتور فروش را به سامانه مودیان می‌فرستد؛ فاکتور ابطال‌شده به‌صورت صورتحساب ابطالی ارسال می‌شود.
+func (h *TaxHandler) HandleSubmitInvoice(c *fiber.Ctx) error {
+	id, err := parseIDParam(c, "id")
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(model.ErrorResponse{Message: "Invalid invoice id", Details: err.Error()})
+	}
+	submission, err := h.taxSvc.SubmitInvoice(c.Context(), uint(c.QueryInt("bidId")), id, actorFromCtx(c))
+	if err != nil {
+		utils.Log.Error("Failed to submit sale invoice to tax system", zap.Uint("invoice_id", id), zap.Error(err))
+		return writeServiceError(c, err, "Failed to submit invoice to tax system due to an internal error.")
+	}
+	return c.Status(fiber.StatusAccepted).JSON(submission)
+}
+
+func (h *TaxHandler) HandleListSubmissions(c *fiber.Ctx) error {
+	filter := model.TaxSubmissionFilter{
+		BIDID:         uint(c.QueryInt("bidId")),
+		SaleInvoiceID: uint(c.QueryInt("saleInvoiceId")),
+		Status:        c.Query("status"),
+	}
+	submissions, err := h.taxSvc.ListSubmissions(c.Context(), filter)
+	if err != nil {
+		return writeServiceError(c, err, "Failed to list tax submissions due to an internal error.")
+	}
+	return c.Status(fiber.StatusOK).JSON(submissions)
+}
+
+func (h *TaxHandler) HandleGetSubmission(c *fiber.Ctx) error {
+	id, err := parseIDParam(c, "id")
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(model.ErrorResponse{Message: "Invalid submission id", Details: err.Error()})
+	}
+	submission, err := h.taxSvc.GetSubmission(c.Context(), uint(c.QueryInt("bidId")), id)
+	if err != nil {
+		return writeServiceError(c, err, "Failed to get tax submission due to an internal error.")
+	}
+	return c.Status(fiber.StatusOK).JSON(submission)
+}
+
+func (h *TaxHandler) HandleRefreshSubmission(c *fiber.Ctx) error {
+	id, err := parseIDParam(c, "id")
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(model.ErrorResponse{Message: "Invalid submission id", Details: err.Error()})
+	}
+	submission, err := h.taxSvc.RefreshSubmission(c.Context(), uint(c.QueryInt("bidId")), id)
+	if err != nil {
+		utils.Log.Error("Failed to refresh tax submission", zap.Uint("submission_id", id), zap.Error(err))
+		return writeServiceError(c, err, "Failed to refresh tax submission due to an internal error.")
+	}
+	return c.Status(fiber.StatusOK).JSON(submission)
+}
+
+func (h *TaxHandler) HandleListRules(c *fiber.Ctx) error {
+	rules, err := h.taxRuleSvc.ListRules(c.Context(), uint(c.QueryInt("bidId")))
+	if err != nil {
+		return writeServiceError(c, err, "Failed to list tax rules due to an internal error.")
+	}
+	return c.Status(fiber.StatusOK).JSON(rules)
+}
+
+func (h *TaxHandler) HandleGetRule(c *fiber.Ctx) error {
+	id, err := parseIDParam(c, "id")
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(model.ErrorResponse{Message: "Invalid rule id", Details: err.Error()})
+	}
+	rule, err := h.taxRuleSvc.GetRule(c.Context(), id)
+	if err != nil {
+		return writeServiceError(c, err, "Failed to get tax rule due to an internal error.")
+	}
+	return c.Status(fiber.StatusOK).JSON(rule)
+}
+
+func (h *TaxHandler) HandleCreateRule(c *fiber.Ctx) error {
+	var req model.TaxRuleRequest
+	if err := c.BodyParser(&req); err != nil {
+		utils.Log.Error("Failed to parse request body for tax rule creation", zap.Error(err))
+		return c.Status(fiber.StatusBadRequest).JSON(model.ErrorResponse{Message: "Invalid request body", Details: err.Error()})
+	}
+	rule, err := h.taxRuleSvc.CreateRule(c.Context(), &req, actorFromCtx(c))
+	if err != nil {
+		utils.Log.Error("Failed to create tax rule via service layer", zap.Error(err))
+		return writeServiceError(c, err, "Failed to create tax rule due to an internal error.")
+	}
+	return c.Status(fiber.StatusCreated).JSON(rule)
+}
+
+func (h *TaxHandler) HandleUpdateRule(c *fiber.Ctx) error {
+	id, err := parseIDParam(c, "id")
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(model.ErrorResponse{Message: "Invalid rule id", Details: err.Error()})
+	}
+	var req model.TaxRuleRequest
+	if err := c.BodyParser(&req); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(model.ErrorResponse{Message: "Invalid request body", Details: err.Error()})
+	}
+	rule, err := h.taxRuleSvc.UpdateRule(c.Context(), id, &req, actorFromCtx(c))
+	if err != nil {
+		utils.Log.Error("Failed to update tax rule", zap.Uint("rule_id", id), zap.Error(err))
+		return writeServiceError(c, err, "Failed to update tax rule due to an internal error.")
+	}
+	return c.Status(fiber.StatusOK).JSON(rule)
+}
+
+// HandlePreviewTax مالیات اجزای یک مبلغ را با قاعده معتبر در تاریخ داده‌شده محاسبه می‌کند.
+func (h *TaxHandler) HandlePreviewTax(c *fiber.Ctx) error {
+	var req model.TaxPreviewRequest
+	if err := c.BodyParser(&req); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(model.ErrorResponse{Message: "Invalid request body", Details: err.Error()})
+	}
+	assessment, err := h.taxRuleSvc.Preview(c.Context(), &req)
+	if err != nil {
+		return writeServiceError(c, err, "Failed to compute tax due to an internal error.")
+	}
+	return c.Status(fiber.StatusOK).JSON(assessment)
+}
@@ -0,0 +1,96 @@
+package handler
+
+import (
+	"crm-gold/internal/model"
+	"crm-gold/internal/service"
+	"crm-gold/internal/utils"
+
+	"github.com/gofiber/fiber/v2"
+	"go.uber.org/zap"
+)
+
+type TransferHandler struct {
+	transferSvc service.TransferService
+}
+
+func NewTransferHandler(transferSvc service.TransferService) *TransferHandler {
+	if transferSvc == nil {
+		utils.Log.Fatal("transferSvc cannot be nil for TransferHandler in CrmManager.")
+	}
+	return &TransferHandler{transferSvc: transferSvc}
+}
+
+func (h *TransferHandler) HandleCreateTransfer(c *fiber.Ctx) error {
+	var req model.CreateTransferRequest
+	if err := c.BodyParser(&req); err != nil {
+		utils.Log.Error("Failed to parse request body for transfer creation", zap.Error(err))
+		return c.Status(fiber.StatusBadRequest).JSON(model.ErrorResponse{Message: "Invalid request body", Details: err.Error()})
+	}
+	transfer, err := h.transferSvc.CreateTransfer(c.Context(), &req, actorFromCtx(c))
+	if err != nil {
+		utils.Log.Error("Failed to create transfer via service layer", zap.Error(err))
+		return writeServiceError(c, err, "Failed to create transfer due to an internal error.")
+	}
+	return c.Status(fiber.StatusCreated).JSON(transfer)
+}
+
+func (h *TransferHandler) HandleListTransfers(c *fiber.Ctx) error {
+	from, to, err := parseOptionalRange(c)
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(model.ErrorResponse{Message: "Invalid date range", Details: err.Error()})
+	}
+	filter := model.TransferFilter{
+		BIDID:  uint(c.QueryInt("bidId")),
+		Kind:   c.Query("accountKind"),
+		ID:     uint(c.QueryInt("accountId")),
+		Status: c.Query("status"),
+		From:   from,
+		To:     to,
+	}
+	transfers, err := h.transferSvc.ListTransfers(c.Context(), filter)
+	if err != nil {
+		return writeServiceError(c, err, "Failed to list transfers due to an internal error.")
+	}
+	return c.Status(fiber.StatusOK).JSON(transfers)
+}
+
+func (h *TransferHandler) HandleGetTransfer(c *fiber.Ctx) error {
+	id, err := parseIDParam(c, "id")
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(model.ErrorResponse{Message: "Invalid transfer id", Details: err.Error()})
+	}
+	transfer, err := h.transferSvc.GetTransfer(c.Context(), id)
+	if err != nil {
+		return writeServiceError(c, err, "Failed to get transfer due to an internal error.")
+	}
+	return c.Status(fiber.StatusOK).JSON(transfer)
+}
+
+func (h *TransferHandler) HandleReverseTransfer(c *fiber.Ctx) error {
+	id, err := parseIDParam(c, "id")
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(model.ErrorResponse{Message: "Invalid transfer id", Details: err.Error()})
+	}
+	var req model.ReverseTransferRequest
+	if err := c.BodyParser(&req); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(model.ErrorResponse{Message: "Invalid request body", Details: err.Error()})
+	}
+	transfer, err := h.transferSvc.ReverseTransfer(c.Context(), id, &req, actorFromCtx(c))
+	if err != nil {
+		utils.Log.Error("Failed to reverse transfer", zap.Uint("transfer_id", id), zap.Error(err))
+		return writeServiceError(c, err, "Failed to reverse transfer due to an internal error.")
+	}
+	return c.Status(fiber.StatusOK).JSON(transfer)
+}
+
+func (h *TransferHandler) HandleGetTransferEntries(c *fiber.Ctx) error {
+	id, err := parseIDParam(c, "id")
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(model.ErrorResponse{Message: "Invalid transfer id", Details: err.Error()})
+	}
+	entries, err := h.transferSvc.GetTransferEntries(c.Context(), id)
+	if err != nil {
+		return writeServiceError(c, err, "Failed to get transfer entries due to an internal error.")
+	}
+	return c.Status(fiber.StatusOK).JSON(entries)
+}
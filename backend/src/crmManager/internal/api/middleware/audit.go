@@ -0,0 +1,28 @@
+package middleware
+
+import (
+	"common-gold/audit"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// AuditMeta شناسه همبستگی، IP و مرورگر کاربر را برای ثبت در دفتر رویداد در Locals می‌گذارد.
+// درخواست‌هایی که از apiGateway می‌رسند این مقادیر را در هدرهای X-Client-* و X-Correlation-ID دارند.
+func AuditMeta() fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		meta := audit.Meta{
+			CorrelationID: audit.CorrelationIDOr(c.Get(audit.CorrelationHeader)),
+			IPAddress:     c.Get(audit.ClientIPHeader),
+			UserAgent:     c.Get(audit.ClientUserAgentHeader),
+		}
+		if meta.IPAddress == "" {
+			meta.IPAddress = c.IP()
+		}
+		if meta.UserAgent == "" {
+			meta.UserAgent = c.Get(fiber.HeaderUserAgent)
+		}
+		c.Locals(audit.MetaKey, meta)
+		c.Set(audit.CorrelationHeader, meta.CorrelationID)
+		return c.Next()
+	}
+}
@@ -1,10 +1,15 @@
 package middleware
 
 import (
+	"crypto/subtle"
 	"encoding/json"
 	"fmt"
+	"os"
+	"strconv"
 	"strings"
 
+	policy "common-gold/authz"
+
 	"github.com/gofiber/fiber/v2"
 	"go.uber.org/zap"
 
@@ -56,6 +61,9 @@ func (m *AuthZMiddleware) VerifyUserJWT(requiredPermission string) fiber.Handler
 			m.logger.Error("Invalid or expired user token for protected route", zap.Error(err), zap.String("path", c.OriginalURL()))
 			return c.Status(fiber.StatusUnauthorized).JSON(model.ErrorResponse{Message: "Invalid or expired token", Details: err.Error()})
 		}
+		if !m.restrictToBusiness(c, claims) {
+			return c.Status(fiber.StatusForbidden).JSON(model.ErrorResponse{Message: "Access denied: This token is limited to another business.", Code: "403"})
+		}
 
 		c.Locals("userID", claims.UserID)
 		c.Locals("username", claims.Username)
@@ -66,14 +74,19 @@ func (m *AuthZMiddleware) VerifyUserJWT(requiredPermission string) fiber.Handler
 		}
 		c.Locals("userRoles", userRoles)
 
-		if !m.permissionService.HashPermission(userRoles, requiredPermission) {
-			m.logger.Warn("Access denied in API Gateway: User does not have required permission",
+		allowed, err := m.permissionService.HasPermission(claims.UserID, userRoles, requiredPermission)
+		if err != nil {
+			m.logger.Error("Failed to evaluate permission", zap.Error(err), zap.String("userID", claims.UserID))
+			return c.Status(fiber.StatusServiceUnavailable).JSON(model.ErrorResponse{Message: "Authorization is temporarily unavailable."})
+		}
+		if !allowed {
+			m.logger.Warn("CRM Manager: Access denied: User does not have required permission",
 				zap.String("userID", claims.UserID), zap.Strings("user_roles", userRoles),
 				zap.String("required_permission", requiredPermission), zap.String("path", c.OriginalURL()))
 			return c.Status(fiber.StatusForbidden).JSON(model.ErrorResponse{Message: "Access denied: Insufficient permissions."})
 		}
 
-		return c.Next()
+		return m.enforceRules(c, claims.UserID, userRoles, requiredPermission)
 	}
 }
 
@@ -93,6 +106,9 @@ func (m *AuthZMiddleware) VerifyServiceToken() fiber.Handler {
 			m.logger.Error("Invalid or expired internal service token", zap.Error(err), zap.String("path", c.OriginalURL()))
 			return c.Status(fiber.StatusUnauthorized).JSON(model.ErrorResponse{Message: "Invalid or expired internal token", Details: err.Error()})
 		}
+		if !m.restrictToBusiness(c, claims) {
+			return c.Status(fiber.StatusForbidden).JSON(model.ErrorResponse{Message: "Access denied: This token is limited to another business.", Code: "403"})
+		}
 
 		c.Locals("userID", claims.UserID)
 		c.Locals("username", claims.Username)
@@ -110,6 +126,73 @@ func (m *AuthZMiddleware) VerifyServiceToken() fiber.Handler {
 	}
 }
 
+// restrictToBusiness درخواست توکنی را که به یک کسب‌وکار محدود است (کلید API با کسب‌وکار مشخص) روی همان کسب‌وکار
+// نگه می‌دارد. bidId کوئری و bidId بدنه JSON اگر کسب‌وکار دیگری باشند درخواست رد می‌شود و bidId کوئری اگر نباشد پر
+// می‌شود؛ رکوردهایی که با شناسه مسیر خوانده می‌شوند را مخزن با Access.BusinessID محدود می‌کند.
+// بدنه بدون bidId عمدا رد نمی‌شود: بسیاری از درخواست‌ها (مثل تغییر وضعیت چک) کسب‌وکار را فقط از کوئری می‌گیرند، و
+// سرویس‌هایی که رکورد تازه با bidId بدنه می‌سازند bidId صفر را با ErrValidation رد می‌کنند؛ پس چنین درخواستی به
+// کسب‌وکار دیگری نمی‌رسد.
+func (m *AuthZMiddleware) restrictToBusiness(c *fiber.Ctx, claims *model.CustomClaims) bool {
+	if claims.BusinessID == nil {
+		return true
+	}
+	if !sameBusiness(c, *claims.BusinessID) {
+		m.logger.Warn("CRM Manager: Access denied: Token is limited to another business",
+			zap.String("userID", claims.UserID), zap.Uint("businessID", *claims.BusinessID), zap.String("path", c.OriginalURL()))
+		return false
+	}
+	c.Locals("businessID", *claims.BusinessID)
+	return true
+}
+
+func sameBusiness(c *fiber.Ctx, businessID uint) bool {
+	want := strconv.FormatUint(uint64(businessID), 10)
+	if got := c.Query("bidId"); got == "" {
+		uri := c.Request().URI()
+		uri.QueryArgs().Set("bidId", want)
+		uri.SetQueryStringBytes(uri.QueryArgs().QueryString())
+	} else if got != want {
+		return false
+	}
+
+	if !strings.HasPrefix(string(c.Request().Header.ContentType()), fiber.MIMEApplicationJSON) || len(c.Body()) == 0 {
+		return true
+	}
+	var body struct {
+		BIDID *uint `json:"bidId"`
+	}
+	// بدنه‌ای که خوانده نشود را handler هم نمی‌پذیرد.
+	if err := json.Unmarshal(c.Body(), &body); err != nil || body.BIDID == nil {
+		return true
+	}
+	return *body.BIDID == businessID
+}
+
+// VerifyServiceSecret مسیرهای داخلی که سرویس‌های دیگر (نه کاربر) صدا می‌زنند را با هدر X-Service-Secret محافظت می‌کند.
+func (m *AuthZMiddleware) VerifyServiceSecret() fiber.Handler {
+	expectedSecret := os.Getenv("CRM_MANAGER_SERVICE_SECRET")
+	if expectedSecret == "" {
+		m.logger.Fatal("CRM_MANAGER_SERVICE_SECRET not set for VerifyServiceSecret middleware.")
+	}
+	return func(c *fiber.Ctx) error {
+		serviceSecret := c.Get("X-Service-Secret")
+		if serviceSecret == "" {
+			m.logger.Warn("CRM Manager: X-Service-Secret header missing for internal route", zap.String("path", c.OriginalURL()))
+			return c.Status(fiber.StatusUnauthorized).JSON(model.ErrorResponse{
+				Message: "Unauthorized: Service secret header missing.",
+				Code:    "401",
+			})
+		}
+		if subtle.ConstantTimeCompare([]byte(serviceSecret), []byte(expectedSecret)) != 1 {
+			m.logger.Warn("CRM Manager: Invalid service secret received", zap.String("path", c.OriginalURL()))
+			return c.Status(fiber.StatusForbidden).JSON(model.ErrorResponse{
+				Message: "Forbidden: Invalid service secret.",
+				Code:    "403",
+			})
+		}
+		return c.Next()
+	}
+}
 
 func (m *AuthZMiddleware) AuthorizePermission(requiredPermission string) fiber.Handler {
 	return func(c *fiber.Ctx) error {
@@ -119,9 +202,18 @@ func (m *AuthZMiddleware) AuthorizePermission(requiredPermission string) fiber.H
 			return c.Status(fiber.StatusInternalServerError).JSON(model.ErrorResponse{Message: "Internal server error: User roles not available for permission check."})
 		}
 
-		if !m.permissionService.HashPermission(userRoles, requiredPermission) {
+		userID, _ := c.Locals("userID").(string)
+		allowed, err := m.permissionService.HasPermission(userID, userRoles, requiredPermission)
+		if err != nil {
+			m.logger.Error("CRM Manager: Failed to evaluate permission", zap.Error(err), zap.String("userID", userID))
+			return c.Status(fiber.StatusServiceUnavailable).JSON(model.ErrorResponse{
+				Message: "Authorization is temporarily unavailable.",
+				Code:    "503",
+			})
+		}
+		if !allowed {
 			m.logger.Warn("CRM Manager: Access denied: User does not have required permission for internal operation",
-				zap.String("userID", c.Locals("userID").(string)),
+				zap.String("userID", userID),
 				zap.Strings("user_roles", userRoles),
 				zap.String("required_permission", requiredPermission),
 				zap.String("path", c.OriginalURL()))
@@ -130,6 +222,39 @@ func (m *AuthZMiddleware) AuthorizePermission(requiredPermission string) fiber.H
 				Code:    "403",
 			})
 		}
+		return m.enforceRules(c, userID, userRoles, requiredPermission)
+	}
+}
+
+// enforceRules محدودیت ردیفی را در Locals می‌گذارد تا مخزن از طریق c.Context() به آن برسد و پس از اجرای
+// handler فیلدهایی را که کاربر مجوز دیدنشان را ندارد از پاسخ JSON حذف می‌کند.
+func (m *AuthZMiddleware) enforceRules(c *fiber.Ctx, userID string, userRoles []string, permission string) error {
+	access, err := m.permissionService.Resolve(userID, userRoles, permission)
+	if err != nil {
+		m.logger.Error("CRM Manager: Failed to resolve authorization rules", zap.Error(err), zap.String("userID", userID))
+		return c.Status(fiber.StatusServiceUnavailable).JSON(model.ErrorResponse{Message: "Authorization is temporarily unavailable.", Code: "503"})
+	}
+	if businessID, ok := c.Locals("businessID").(uint); ok {
+		access.BusinessID = businessID
+	}
+	if !access.Restricted() {
 		return c.Next()
 	}
+	c.Locals(policy.AccessKey, access)
+
+	if err := c.Next(); err != nil {
+		return err
+	}
+	if len(access.Hidden) == 0 || !strings.HasPrefix(string(c.Response().Header.ContentType()), fiber.MIMEApplicationJSON) {
+		return nil
+	}
+	body, err := policy.Redact(c.Response().Body(), access.Hidden)
+	if err != nil {
+		// پاسخی که نتوان فیلدهای محافظت‌شده‌اش را حذف کرد نباید ارسال شود.
+		m.logger.Error("CRM Manager: Failed to redact protected fields from response", zap.Error(err), zap.String("path", c.OriginalURL()))
+		c.Response().ResetBody()
+		return c.Status(fiber.StatusInternalServerError).JSON(model.ErrorResponse{Message: "Internal server error.", Code: "500"})
+	}
+	c.Response().SetBodyRaw(body)
+	return nil
 }
\ No newline at end of file
@@ -0,0 +1,36 @@
+package router
+
+import (
+	"crm-gold/internal/api/handler"
+	"crm-gold/internal/api/middleware"
+	"crm-gold/internal/model"
+	"crm-gold/internal/utils"
+	"fmt"
+
+	"common-gold/audit"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+func SetUpAuditRoutes(app *fiber.App, auditHandler *handler.AuditHandler, AuthZMiddleware *middleware.AuthZMiddleware) error {
+	if app == nil {
+		return fmt.Errorf("fiber app instance cannot be nil in CrmManager.")
+	}
+	if auditHandler == nil {
+		return fmt.Errorf("auditHandler is nil in CrmManager's SetUpAuditRoutes.")
+	}
+	if AuthZMiddleware == nil {
+		return fmt.Errorf("authz middleware is nil in CrmManager's SetUpAuditRoutes.")
+	}
+
+	auditGroup := app.Group("/crm/audit")
+	utils.Log.Info("Setting up audit routes in CrmManager...")
+
+	auditGroup.Get("/events", AuthZMiddleware.VerifyUserJWT(model.PermAuditRead), auditHandler.HandleListEvents)
+	auditGroup.Get("/verify", AuthZMiddleware.VerifyUserJWT(model.PermAuditRead), auditHandler.HandleVerifyChain)
+	// مسیر داخلی برای رویدادهای apiGateway و سرویس‌های دیگر
+	app.Post(audit.IngestPath, AuthZMiddleware.VerifyServiceSecret(), auditHandler.HandleIngestEvent)
+
+	utils.Log.Info("Audit routes set up successfully in CrmManager.")
+	return nil
+}
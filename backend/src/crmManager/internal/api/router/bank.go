@@ -0,0 +1,46 @@
+package router
+
+import (
+	"crm-gold/internal/api/handler"
+	"crm-gold/internal/api/middleware"
+	"crm-gold/internal/model"
+	"crm-gold/internal/utils"
+	"fmt"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+func SetUpBankRoutes(app *fiber.App, bankHandler *handler.BankHandler, AuthZMiddleware *middleware.AuthZMiddleware) error {
+	if app == nil {
+		return fmt.Errorf("fiber app instance cannot be nil in CrmManager.")
+	}
+	if bankHandler == nil {
+		return fmt.Errorf("bankHandler is nil in CrmManager's SetUpBankRoutes.")
+	}
+	if AuthZMiddleware == nil {
+		return fmt.Errorf("authz middleware is nil in CrmManager's SetUpBankRoutes.")
+	}
+
+	bankGroup := app.Group("/crm/bank-accounts", AuthZMiddleware.VerifyUserJWT(model.PermTransactionManagePayments))
+	utils.Log.Info("Setting up bank account routes in CrmManager...")
+
+	bankGroup.Get("/", bankHandler.HandleListBankAccounts)
+	bankGroup.Post("/", bankHandler.HandleCreateBankAccount)
+	bankGroup.Delete("/transactions/:txnId", bankHandler.HandleDeleteTransaction)
+	bankGroup.Post("/statement-lines/:lineId/match", bankHandler.HandleMatchLine)
+	bankGroup.Post("/statement-lines/:lineId/record", bankHandler.HandleRecordLine)
+	bankGroup.Post("/statement-lines/:lineId/ignore", bankHandler.HandleIgnoreLine)
+	bankGroup.Post("/statement-lines/:lineId/unmatch", bankHandler.HandleUnmatchLine)
+	bankGroup.Get("/:id", bankHandler.HandleGetBankAccount)
+	bankGroup.Post("/:id/pos-terminals", bankHandler.HandleAddPOSTerminal)
+	bankGroup.Get("/:id/transactions", bankHandler.HandleListTransactions)
+	bankGroup.Post("/:id/transactions", bankHandler.HandleCreateTransaction)
+	bankGroup.Get("/:id/statement", bankHandler.HandleGetStatement)
+	bankGroup.Post("/:id/reconciliation/import", bankHandler.HandleImportStatement)
+	bankGroup.Get("/:id/reconciliation/lines", bankHandler.HandleListStatementLines)
+	bankGroup.Get("/:id/reconciliation/unmatched", bankHandler.HandleListUnmatchedLines)
+	bankGroup.Get("/:id/reconciliation/candidates", bankHandler.HandleListCandidates)
+
+	utils.Log.Info("Bank account routes set up successfully in CrmManager.")
+	return nil
+}
@@ -0,0 +1,46 @@
+package router
+
+import (
+	"crm-gold/internal/api/handler"
+	"crm-gold/internal/api/middleware"
+	"crm-gold/internal/model"
+	"crm-gold/internal/utils"
+	"fmt"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+func SetUpCenterRoutes(app *fiber.App, centerHandler *handler.CenterHandler, AuthZMiddleware *middleware.AuthZMiddleware) error {
+	if app == nil {
+		return fmt.Errorf("fiber app instance cannot be nil in CrmManager.")
+	}
+	if centerHandler == nil {
+		return fmt.Errorf("centerHandler is nil in CrmManager's SetUpCenterRoutes.")
+	}
+	if AuthZMiddleware == nil {
+		return fmt.Errorf("authz middleware is nil in CrmManager's SetUpCenterRoutes.")
+	}
+
+	utils.Log.Info("Setting up cost and income center routes in CrmManager...")
+
+	centerGroup := app.Group("/crm/centers", AuthZMiddleware.VerifyUserJWT(model.PermTransactionManageExpenses))
+	centerGroup.Get("/", centerHandler.HandleListCenters)
+	centerGroup.Post("/", centerHandler.HandleCreateCenter)
+	centerGroup.Get("/top", centerHandler.HandleTopCenters)
+	centerGroup.Put("/:id", centerHandler.HandleUpdateCenter)
+
+	voucherGroup := app.Group("/crm/center-vouchers", AuthZMiddleware.VerifyUserJWT(model.PermTransactionManageExpenses))
+	voucherGroup.Get("/", centerHandler.HandleListVouchers)
+	voucherGroup.Post("/", centerHandler.HandleCreateVoucher)
+	voucherGroup.Get("/:id", centerHandler.HandleGetVoucher)
+	voucherGroup.Post("/:id/reverse", centerHandler.HandleReverseVoucher)
+
+	recurringGroup := app.Group("/crm/recurring-expenses", AuthZMiddleware.VerifyUserJWT(model.PermTransactionManageExpenses))
+	recurringGroup.Get("/", centerHandler.HandleListRecurringExpenses)
+	recurringGroup.Post("/", centerHandler.HandleCreateRecurringExpense)
+	recurringGroup.Post("/run", centerHandler.HandleRunRecurringExpenses)
+	recurringGroup.Put("/:id", centerHandler.HandleUpdateRecurringExpense)
+
+	utils.Log.Info("Cost and income center routes set up successfully in CrmManager.")
+	return nil
+}
@@ -0,0 +1,38 @@
+package router
+
+import (
+	"crm-gold/internal/api/handler"
+	"crm-gold/internal/api/middleware"
+	"crm-gold/internal/model"
+	"crm-gold/internal/utils"
+	"fmt"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+func SetUpChequeRoutes(app *fiber.App, chequeHandler *handler.ChequeHandler, AuthZMiddleware *middleware.AuthZMiddleware) error {
+	if app == nil {
+		return fmt.Errorf("fiber app instance cannot be nil in CrmManager.")
+	}
+	if chequeHandler == nil {
+		return fmt.Errorf("chequeHandler is nil in CrmManager's SetUpChequeRoutes.")
+	}
+	if AuthZMiddleware == nil {
+		return fmt.Errorf("authz middleware is nil in CrmManager's SetUpChequeRoutes.")
+	}
+
+	chequeGroup := app.Group("/crm/cheques", AuthZMiddleware.VerifyUserJWT(model.PermTransactionManageCheques))
+	utils.Log.Info("Setting up cheque routes in CrmManager...")
+
+	chequeGroup.Get("/", chequeHandler.HandleListCheques)
+	chequeGroup.Post("/received", chequeHandler.HandleCreateReceivedCheque)
+	chequeGroup.Post("/issued", chequeHandler.HandleCreateIssuedCheque)
+	chequeGroup.Get("/alerts/summary", chequeHandler.HandleGetDueSummary)
+	chequeGroup.Post("/alerts/run", chequeHandler.HandleRunDueAlerts)
+	chequeGroup.Get("/:id", chequeHandler.HandleGetCheque)
+	chequeGroup.Post("/:id/status", chequeHandler.HandleChangeChequeStatus)
+	chequeGroup.Get("/:id/entries", chequeHandler.HandleGetChequeEntries)
+
+	utils.Log.Info("Cheque routes set up successfully in CrmManager.")
+	return nil
+}
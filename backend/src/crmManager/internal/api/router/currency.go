@@ -0,0 +1,38 @@
+package router
+
+import (
+	"crm-gold/internal/api/handler"
+	"crm-gold/internal/api/middleware"
+	"crm-gold/internal/model"
+	"crm-gold/internal/utils"
+	"fmt"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+func SetUpCurrencyRoutes(app *fiber.App, currencyHandler *handler.CurrencyHandler, AuthZMiddleware *middleware.AuthZMiddleware) error {
+	if app == nil {
+		return fmt.Errorf("fiber app instance cannot be nil in CrmManager.")
+	}
+	if currencyHandler == nil {
+		return fmt.Errorf("currencyHandler is nil in CrmManager's SetUpCurrencyRoutes.")
+	}
+	if AuthZMiddleware == nil {
+		return fmt.Errorf("authz middleware is nil in CrmManager's SetUpCurrencyRoutes.")
+	}
+
+	utils.Log.Info("Setting up currency routes in CrmManager...")
+
+	app.Get("/crm/currencies", AuthZMiddleware.VerifyUserJWT(model.PermSystemSettingsRead), currencyHandler.HandleListCurrencies)
+
+	rateGroup := app.Group("/crm/exchange-rates")
+	rateGroup.Get("/", AuthZMiddleware.VerifyUserJWT(model.PermSystemSettingsRead), currencyHandler.HandleListRates)
+	rateGroup.Put("/", AuthZMiddleware.VerifyUserJWT(model.PermSystemSettingsManage), currencyHandler.HandleSetRate)
+	rateGroup.Get("/at", AuthZMiddleware.VerifyUserJWT(model.PermSystemSettingsRead), currencyHandler.HandleRateAt)
+
+	app.Get("/crm/reports/currency-revaluation", AuthZMiddleware.VerifyUserJWT(model.PermReportViewBalances), currencyHandler.HandleRevaluation)
+	app.Get("/crm/reports/currency-revaluation/export", AuthZMiddleware.VerifyUserJWT(model.PermReportExportData), currencyHandler.HandleExportRevaluation)
+
+	utils.Log.Info("Currency routes set up successfully in CrmManager.")
+	return nil
+}
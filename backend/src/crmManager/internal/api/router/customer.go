@@ -3,6 +3,7 @@ package router
 import (
 	"crm-gold/internal/api/handler"
 	"crm-gold/internal/api/middleware"
+	"crm-gold/internal/model"
 	"crm-gold/internal/utils"
 	"fmt"
 
@@ -16,12 +17,16 @@ func SetUpCustomerRoutes(app *fiber.App, crmHandler *handler.CrmHandler, AuthZMi
 	if crmHandler == nil {
 		return fmt.Errorf("crmHandler is nil in CrmManager's SetUpCustomerRoutes.")
 	}
+	if AuthZMiddleware == nil {
+		return fmt.Errorf("authz middleware is nil in CrmManager's SetUpCustomerRoutes.")
+	}
 
 	crmGroup := app.Group("/crm")
 	utils.Log.Info("Setting up customer routes in CrmManager...")
 
-	crmGroup.Get("/customers", crmHandler.HandleGetAllCustomers)
-	crmGroup.Post("/customers", crmHandler.HandleCreateCustomer)
+	// محدود کردن مشتریان به فروشنده و حذف فیلدهای مانده اول دوره در AuthZMiddleware و بر اساس authz.Rules انجام می‌شود.
+	crmGroup.Get("/customers", AuthZMiddleware.VerifyUserJWT(model.PermCRMReadCustomer), crmHandler.HandleGetAllCustomers)
+	crmGroup.Post("/customers", AuthZMiddleware.VerifyUserJWT(model.PermCRMCreateCustomer), crmHandler.HandleCreateCustomer)
 	/*crmGroup.Put("/customers/:id", crmHandler.HandleUpdateCustomer)
 	crmGroup.Delete("/customers/:id", crmHandler.HandleDeleteCustomer)
 
@@ -0,0 +1,36 @@
+package router
+
+import (
+	"crm-gold/internal/api/handler"
+	"crm-gold/internal/api/middleware"
+	"crm-gold/internal/model"
+	"crm-gold/internal/utils"
+	"fmt"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+func SetUpFiscalYearRoutes(app *fiber.App, fiscalYearHandler *handler.FiscalYearHandler, AuthZMiddleware *middleware.AuthZMiddleware) error {
+	if app == nil {
+		return fmt.Errorf("fiber app instance cannot be nil in CrmManager.")
+	}
+	if fiscalYearHandler == nil {
+		return fmt.Errorf("fiscalYearHandler is nil in CrmManager's SetUpFiscalYearRoutes.")
+	}
+	if AuthZMiddleware == nil {
+		return fmt.Errorf("authz middleware is nil in CrmManager's SetUpFiscalYearRoutes.")
+	}
+
+	fiscalYearGroup := app.Group("/crm/fiscal-years")
+	utils.Log.Info("Setting up fiscal year routes in CrmManager...")
+
+	fiscalYearGroup.Get("/", AuthZMiddleware.VerifyUserJWT(model.PermSystemSettingsRead), fiscalYearHandler.HandleListFiscalYears)
+	fiscalYearGroup.Post("/", AuthZMiddleware.VerifyUserJWT(model.PermSystemSettingsManage), fiscalYearHandler.HandleCreateFiscalYear)
+	fiscalYearGroup.Get("/:id", AuthZMiddleware.VerifyUserJWT(model.PermSystemSettingsRead), fiscalYearHandler.HandleGetFiscalYear)
+	fiscalYearGroup.Get("/:id/events", AuthZMiddleware.VerifyUserJWT(model.PermSystemSettingsRead), fiscalYearHandler.HandleListEvents)
+	fiscalYearGroup.Post("/:id/close", AuthZMiddleware.VerifyUserJWT(model.PermSystemSettingsManage), fiscalYearHandler.HandleCloseFiscalYear)
+	fiscalYearGroup.Post("/:id/reopen", AuthZMiddleware.VerifyUserJWT(model.PermSystemSettingsManage), fiscalYearHandler.HandleReopenFiscalYear)
+
+	utils.Log.Info("Fiscal year routes set up successfully in CrmManager.")
+	return nil
+}
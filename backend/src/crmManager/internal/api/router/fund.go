@@ -0,0 +1,39 @@
+package router
+
+import (
+	"crm-gold/internal/api/handler"
+	"crm-gold/internal/api/middleware"
+	"crm-gold/internal/model"
+	"crm-gold/internal/utils"
+	"fmt"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+func SetUpFundRoutes(app *fiber.App, fundHandler *handler.FundHandler, AuthZMiddleware *middleware.AuthZMiddleware) error {
+	if app == nil {
+		return fmt.Errorf("fiber app instance cannot be nil in CrmManager.")
+	}
+	if fundHandler == nil {
+		return fmt.Errorf("fundHandler is nil in CrmManager's SetUpFundRoutes.")
+	}
+	if AuthZMiddleware == nil {
+		return fmt.Errorf("authz middleware is nil in CrmManager's SetUpFundRoutes.")
+	}
+
+	fundGroup := app.Group("/crm/funds", AuthZMiddleware.VerifyUserJWT(model.PermTransactionManagePayments))
+	utils.Log.Info("Setting up fund routes in CrmManager...")
+
+	fundGroup.Get("/", fundHandler.HandleListFunds)
+	fundGroup.Post("/", fundHandler.HandleCreateFund)
+	fundGroup.Put("/vouchers/:voucherId", fundHandler.HandleUpdateVoucher)
+	fundGroup.Delete("/vouchers/:voucherId", fundHandler.HandleDeleteVoucher)
+	fundGroup.Get("/:id", fundHandler.HandleGetFund)
+	fundGroup.Get("/:id/statement", fundHandler.HandleGetStatement)
+	fundGroup.Get("/:id/vouchers", fundHandler.HandleListVouchers)
+	fundGroup.Post("/:id/vouchers", fundHandler.HandleCreateVoucher)
+	fundGroup.Post("/:id/close-day", fundHandler.HandleCloseDay)
+
+	utils.Log.Info("Fund routes set up successfully in CrmManager.")
+	return nil
+}
@@ -0,0 +1,40 @@
+package router
+
+import (
+	"crm-gold/internal/api/handler"
+	"crm-gold/internal/api/middleware"
+	"crm-gold/internal/model"
+	"crm-gold/internal/utils"
+	"fmt"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+func SetUpInventoryRoutes(app *fiber.App, inventoryHandler *handler.InventoryHandler, AuthZMiddleware *middleware.AuthZMiddleware) error {
+	if app == nil {
+		return fmt.Errorf("fiber app instance cannot be nil in CrmManager.")
+	}
+	if inventoryHandler == nil {
+		return fmt.Errorf("inventoryHandler is nil in CrmManager's SetUpInventoryRoutes.")
+	}
+	if AuthZMiddleware == nil {
+		return fmt.Errorf("authz middleware is nil in CrmManager's SetUpInventoryRoutes.")
+	}
+
+	utils.Log.Info("Setting up inventory routes in CrmManager...")
+
+	itemGroup := app.Group("/crm/inventory/items")
+	itemGroup.Get("/", AuthZMiddleware.VerifyUserJWT(model.PermInventoryReadItem), inventoryHandler.HandleListItems)
+	itemGroup.Post("/", AuthZMiddleware.VerifyUserJWT(model.PermInventoryCreateItem), inventoryHandler.HandleCreateItem)
+	itemGroup.Get("/:id", AuthZMiddleware.VerifyUserJWT(model.PermInventoryReadItem), inventoryHandler.HandleGetItem)
+	itemGroup.Put("/:id", AuthZMiddleware.VerifyUserJWT(model.PermInventoryUpdateItem), inventoryHandler.HandleUpdateItem)
+
+	reportGroup := app.Group("/crm/reports")
+	reportGroup.Get("/inventory-valuation", AuthZMiddleware.VerifyUserJWT(model.PermReportViewInventorySummary), inventoryHandler.HandleValuation)
+	reportGroup.Get("/inventory-valuation/export", AuthZMiddleware.VerifyUserJWT(model.PermReportExportData), inventoryHandler.HandleExportValuation)
+	reportGroup.Get("/slow-movers", AuthZMiddleware.VerifyUserJWT(model.PermReportViewInventorySummary), inventoryHandler.HandleSlowMovers)
+	reportGroup.Get("/slow-movers/export", AuthZMiddleware.VerifyUserJWT(model.PermReportExportData), inventoryHandler.HandleExportSlowMovers)
+
+	utils.Log.Info("Inventory routes set up successfully in CrmManager.")
+	return nil
+}
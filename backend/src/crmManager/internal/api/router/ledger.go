@@ -0,0 +1,38 @@
+package router
+
+import (
+	"crm-gold/internal/api/handler"
+	"crm-gold/internal/api/middleware"
+	"crm-gold/internal/model"
+	"crm-gold/internal/utils"
+	"fmt"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+func SetUpLedgerRoutes(app *fiber.App, ledgerHandler *handler.LedgerHandler, AuthZMiddleware *middleware.AuthZMiddleware) error {
+	if app == nil {
+		return fmt.Errorf("fiber app instance cannot be nil in CrmManager.")
+	}
+	if ledgerHandler == nil {
+		return fmt.Errorf("ledgerHandler is nil in CrmManager's SetUpLedgerRoutes.")
+	}
+	if AuthZMiddleware == nil {
+		return fmt.Errorf("authz middleware is nil in CrmManager's SetUpLedgerRoutes.")
+	}
+
+	ledgerGroup := app.Group("/crm/ledger")
+	utils.Log.Info("Setting up general ledger routes in CrmManager...")
+
+	ledgerGroup.Get("/accounts", AuthZMiddleware.VerifyUserJWT(model.PermReportViewBalances), ledgerHandler.HandleListAccounts)
+	ledgerGroup.Post("/accounts", AuthZMiddleware.VerifyUserJWT(model.PermSystemSettingsManage), ledgerHandler.HandleCreateAccount)
+	ledgerGroup.Put("/accounts/:id", AuthZMiddleware.VerifyUserJWT(model.PermSystemSettingsManage), ledgerHandler.HandleUpdateAccount)
+	ledgerGroup.Get("/accounts/:code/ledger", AuthZMiddleware.VerifyUserJWT(model.PermReportViewBalances), ledgerHandler.HandleGetAccountLedger)
+	ledgerGroup.Get("/journal", AuthZMiddleware.VerifyUserJWT(model.PermReportViewBalances), ledgerHandler.HandleListEntries)
+	ledgerGroup.Post("/journal", AuthZMiddleware.VerifyUserJWT(model.PermTransactionManagePayments), ledgerHandler.HandleCreateJournalEntry)
+	ledgerGroup.Get("/journal/:id", AuthZMiddleware.VerifyUserJWT(model.PermReportViewBalances), ledgerHandler.HandleGetEntry)
+	ledgerGroup.Post("/journal/:id/reverse", AuthZMiddleware.VerifyUserJWT(model.PermTransactionManagePayments), ledgerHandler.HandleReverseEntry)
+
+	utils.Log.Info("General ledger routes set up successfully in CrmManager.")
+	return nil
+}
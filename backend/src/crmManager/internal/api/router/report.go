@@ -0,0 +1,36 @@
+package router
+
+import (
+	"crm-gold/internal/api/handler"
+	"crm-gold/internal/api/middleware"
+	"crm-gold/internal/model"
+	"crm-gold/internal/utils"
+	"fmt"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+func SetUpReportRoutes(app *fiber.App, reportHandler *handler.ReportHandler, AuthZMiddleware *middleware.AuthZMiddleware) error {
+	if app == nil {
+		return fmt.Errorf("fiber app instance cannot be nil in CrmManager.")
+	}
+	if reportHandler == nil {
+		return fmt.Errorf("reportHandler is nil in CrmManager's SetUpReportRoutes.")
+	}
+	if AuthZMiddleware == nil {
+		return fmt.Errorf("authz middleware is nil in CrmManager's SetUpReportRoutes.")
+	}
+
+	reportGroup := app.Group("/crm/reports")
+	utils.Log.Info("Setting up financial report routes in CrmManager...")
+
+	reportGroup.Get("/trial-balance", AuthZMiddleware.VerifyUserJWT(model.PermReportViewBalances), reportHandler.HandleTrialBalance)
+	reportGroup.Get("/trial-balance/export", AuthZMiddleware.VerifyUserJWT(model.PermReportExportData), reportHandler.HandleExportTrialBalance)
+	reportGroup.Get("/profit-loss", AuthZMiddleware.VerifyUserJWT(model.PermReportViewProfitLoss), reportHandler.HandleProfitLoss)
+	reportGroup.Get("/profit-loss/export", AuthZMiddleware.VerifyUserJWT(model.PermReportExportData), reportHandler.HandleExportProfitLoss)
+	reportGroup.Get("/balance-sheet", AuthZMiddleware.VerifyUserJWT(model.PermReportViewBalances), reportHandler.HandleBalanceSheet)
+	reportGroup.Get("/balance-sheet/export", AuthZMiddleware.VerifyUserJWT(model.PermReportExportData), reportHandler.HandleExportBalanceSheet)
+
+	utils.Log.Info("Financial report routes set up successfully in CrmManager.")
+	return nil
+}
@@ -0,0 +1,37 @@
+package router
+
+import (
+	"crm-gold/internal/api/handler"
+	"crm-gold/internal/api/middleware"
+	"crm-gold/internal/model"
+	"crm-gold/internal/utils"
+	"fmt"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+func SetUpSaleRoutes(app *fiber.App, saleHandler *handler.SaleHandler, AuthZMiddleware *middleware.AuthZMiddleware) error {
+	if app == nil {
+		return fmt.Errorf("fiber app instance cannot be nil in CrmManager.")
+	}
+	if saleHandler == nil {
+		return fmt.Errorf("saleHandler is nil in CrmManager's SetUpSaleRoutes.")
+	}
+	if AuthZMiddleware == nil {
+		return fmt.Errorf("authz middleware is nil in CrmManager's SetUpSaleRoutes.")
+	}
+
+	utils.Log.Info("Setting up sale invoice and sales report routes in CrmManager...")
+
+	invoiceGroup := app.Group("/crm/sale-invoices")
+	invoiceGroup.Get("/", AuthZMiddleware.VerifyUserJWT(model.PermTransactionReadSaleInvoice), saleHandler.HandleListInvoices)
+	invoiceGroup.Post("/", AuthZMiddleware.VerifyUserJWT(model.PermTransactionCreateSaleInvoice), saleHandler.HandleCreateInvoice)
+	invoiceGroup.Get("/:id", AuthZMiddleware.VerifyUserJWT(model.PermTransactionReadSaleInvoice), saleHandler.HandleGetInvoice)
+	invoiceGroup.Post("/:id/void", AuthZMiddleware.VerifyUserJWT(model.PermTransactionDeleteSaleInvoice), saleHandler.HandleVoidInvoice)
+
+	app.Get("/crm/reports/sales", AuthZMiddleware.VerifyUserJWT(model.PermReportViewSalesSummary), saleHandler.HandleSalesReport)
+	app.Get("/crm/reports/sales/export", AuthZMiddleware.VerifyUserJWT(model.PermReportExportData), saleHandler.HandleExportSalesReport)
+
+	utils.Log.Info("Sale invoice and sales report routes set up successfully in CrmManager.")
+	return nil
+}
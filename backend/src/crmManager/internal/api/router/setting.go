@@ -0,0 +1,34 @@
+package router
+
+import (
+	"crm-gold/internal/api/handler"
+	"crm-gold/internal/api/middleware"
+	"crm-gold/internal/model"
+	"crm-gold/internal/utils"
+	"fmt"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+func SetUpSettingRoutes(app *fiber.App, settingHandler *handler.SettingHandler, AuthZMiddleware *middleware.AuthZMiddleware) error {
+	if app == nil {
+		return fmt.Errorf("fiber app instance cannot be nil in CrmManager.")
+	}
+	if settingHandler == nil {
+		return fmt.Errorf("settingHandler is nil in CrmManager's SetUpSettingRoutes.")
+	}
+	if AuthZMiddleware == nil {
+		return fmt.Errorf("authz middleware is nil in CrmManager's SetUpSettingRoutes.")
+	}
+
+	settingGroup := app.Group("/crm/settings")
+	utils.Log.Info("Setting up business setting routes in CrmManager...")
+
+	settingGroup.Get("/", AuthZMiddleware.VerifyUserJWT(model.PermSystemSettingsRead), settingHandler.HandleGetBusinessSetting)
+	settingGroup.Put("/", AuthZMiddleware.VerifyUserJWT(model.PermSystemSettingsManage), settingHandler.HandleUpdateBusinessSetting)
+	settingGroup.Get("/gold-rate", AuthZMiddleware.VerifyUserJWT(model.PermInventoryReadGoldPrice), settingHandler.HandleGetBusinessSetting)
+	settingGroup.Put("/gold-rate", AuthZMiddleware.VerifyUserJWT(model.PermInventoryUpdateGoldPrice), settingHandler.HandleUpdateGoldRate)
+
+	utils.Log.Info("Business setting routes set up successfully in CrmManager.")
+	return nil
+}
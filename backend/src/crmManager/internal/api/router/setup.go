@@ -9,13 +9,55 @@ import (
 	"github.com/gofiber/fiber/v2"
 )
 
-func SetUpAllRoutes(app *fiber.App, crmHandler *handler.CrmHandler, AuthZMiddleware *middleware.AuthZMiddleware) error {
+func SetUpAllRoutes(app *fiber.App, crmHandler *handler.CrmHandler, chequeHandler *handler.ChequeHandler, fundHandler *handler.FundHandler, bankHandler *handler.BankHandler, transferHandler *handler.TransferHandler, settingHandler *handler.SettingHandler, centerHandler *handler.CenterHandler, ledgerHandler *handler.LedgerHandler, fiscalYearHandler *handler.FiscalYearHandler, reportHandler *handler.ReportHandler, saleHandler *handler.SaleHandler, inventoryHandler *handler.InventoryHandler, taxHandler *handler.TaxHandler, currencyHandler *handler.CurrencyHandler, auditHandler *handler.AuditHandler, AuthZMiddleware *middleware.AuthZMiddleware) error {
 	if app == nil {
 		return fmt.Errorf("fiber app instance cannot be nil in SetUpAllRoutes.")
 	}
 	if crmHandler == nil {
 		return fmt.Errorf("crmHandler is nil in SetUpAllRoutes.")
 	}
+	if chequeHandler == nil {
+		return fmt.Errorf("chequeHandler is nil in SetUpAllRoutes.")
+	}
+	if fundHandler == nil {
+		return fmt.Errorf("fundHandler is nil in SetUpAllRoutes.")
+	}
+	if bankHandler == nil {
+		return fmt.Errorf("bankHandler is nil in SetUpAllRoutes.")
+	}
+	if transferHandler == nil {
+		return fmt.Errorf("transferHandler is nil in SetUpAllRoutes.")
+	}
+	if settingHandler == nil {
+		return fmt.Errorf("settingHandler is nil in SetUpAllRoutes.")
+	}
+	if centerHandler == nil {
+		return fmt.Errorf("centerHandler is nil in SetUpAllRoutes.")
+	}
+	if ledgerHandler == nil {
+		return fmt.Errorf("ledgerHandler is nil in SetUpAllRoutes.")
+	}
+	if fiscalYearHandler == nil {
+		return fmt.Errorf("fiscalYearHandler is nil in SetUpAllRoutes.")
+	}
+	if reportHandler == nil {
+		return fmt.Errorf("reportHandler is nil in SetUpAllRoutes.")
+	}
+	if saleHandler == nil {
+		return fmt.Errorf("saleHandler is nil in SetUpAllRoutes.")
+	}
+	if inventoryHandler == nil {
+		return fmt.Errorf("inventoryHandler is nil in SetUpAllRoutes.")
+	}
+	if taxHandler == nil {
+		return fmt.Errorf("taxHandler is nil in SetUpAllRoutes.")
+	}
+	if currencyHandler == nil {
+		return fmt.Errorf("currencyHandler is nil in SetUpAllRoutes.")
+	}
+	if auditHandler == nil {
+		return fmt.Errorf("auditHandler is nil in SetUpAllRoutes.")
+	}
 	if AuthZMiddleware == nil {
 		return fmt.Errorf("authz middleware cannot be nil in SetUpAllRoutes.")
 	}
@@ -25,6 +67,48 @@ func SetUpAllRoutes(app *fiber.App, crmHandler *handler.CrmHandler, AuthZMiddlew
 	if err := SetUpCustomerRoutes(app, crmHandler, AuthZMiddleware); err != nil {
 		return fmt.Errorf("failed to set up customer routes: %w", err)
 	}
+	if err := SetUpChequeRoutes(app, chequeHandler, AuthZMiddleware); err != nil {
+		return fmt.Errorf("failed to set up cheque routes: %w", err)
+	}
+	if err := SetUpFundRoutes(app, fundHandler, AuthZMiddleware); err != nil {
+		return fmt.Errorf("failed to set up fund routes: %w", err)
+	}
+	if err := SetUpBankRoutes(app, bankHandler, AuthZMiddleware); err != nil {
+		return fmt.Errorf("failed to set up bank routes: %w", err)
+	}
+	if err := SetUpTransferRoutes(app, transferHandler, AuthZMiddleware); err != nil {
+		return fmt.Errorf("failed to set up transfer routes: %w", err)
+	}
+	if err := SetUpSettingRoutes(app, settingHandler, AuthZMiddleware); err != nil {
+		return fmt.Errorf("failed to set up setting routes: %w", err)
+	}
+	if err := SetUpCenterRoutes(app, centerHandler, AuthZMiddleware); err != nil {
+		return fmt.Errorf("failed to set up center routes: %w", err)
+	}
+	if err := SetUpLedgerRoutes(app, ledgerHandler, AuthZMiddleware); err != nil {
+		return fmt.Errorf("failed to set up ledger routes: %w", err)
+	}
+	if err := SetUpFiscalYearRoutes(app, fiscalYearHandler, AuthZMiddleware); err != nil {
+		return fmt.Errorf("failed to set up fiscal year routes: %w", err)
+	}
+	if err := SetUpReportRoutes(app, reportHandler, AuthZMiddleware); err != nil {
+		return fmt.Errorf("failed to set up report routes: %w", err)
+	}
+	if err := SetUpSaleRoutes(app, saleHandler, AuthZMiddleware); err != nil {
+		return fmt.Errorf("failed to set up sale routes: %w", err)
+	}
+	if err := SetUpInventoryRoutes(app, inventoryHandler, AuthZMiddleware); err != nil {
+		return fmt.Errorf("failed to set up inventory routes: %w", err)
+	}
+	if err := SetUpTaxRoutes(app, taxHandler, AuthZMiddleware); err != nil {
+		return fmt.Errorf("failed to set up tax routes: %w", err)
+	}
+	if err := SetUpCurrencyRoutes(app, currencyHandler, AuthZMiddleware); err != nil {
+		return fmt.Errorf("failed to set up currency routes: %w", err)
+	}
+	if err := SetUpAuditRoutes(app, auditHandler, AuthZMiddleware); err != nil {
+		return fmt.Errorf("failed to set up audit routes: %w", err)
+	}
 	utils.Log.Info("All routes set up successfully in SetUpAllRoutes.")
 	
 	return nil
@@ -1,13 +1,21 @@
 package router
 
 import (
+	commonauthz "common-gold/authz"
+	"context"
 	"crm-gold/internal/api/authz"
 	"crm-gold/internal/api/handler"
 	"crm-gold/internal/api/middleware"
+	"crm-gold/internal/jobs"
+	"crm-gold/internal/moadian"
 	"crm-gold/internal/repository/db/postgresDb"
 	"crm-gold/internal/service"
+	"crm-gold/internal/service/notification"
 	"crm-gold/internal/utils"
 	"fmt"
+	"os"
+	"strconv"
+	"time"
 
 	"github.com/gofiber/fiber/v2"
 	"go.uber.org/zap"
@@ -20,13 +28,30 @@ func StartServer(port string) error {
 	
     app.Use(middleware.CorsMiddleware())
     utils.Log.Info("CORS middleware applied to the Fiber app.")
+    app.Use(middleware.AuditMeta())
     if app == nil {
         return fmt.Errorf("fiber app instance cannot be nil in CrmManager.")
     }
     utils.Log.Info("Setting up routes for CrmManager...")
 
     // This section remains the same, initializing dependencies
-    permissionService, err := authz.NewPermissionService(utils.Log)
+    profileManagerBaseURL := os.Getenv("PROFILE_MANAGER_BASE_URL")
+    if profileManagerBaseURL == "" {
+        profileManagerBaseURL = "http://localhost:8081"
+    }
+    policySource, err := commonauthz.NewHTTPSource(profileManagerBaseURL, os.Getenv("PROFILE_MANAGER_SERVICE_SECRET"))
+    if err != nil {
+        utils.Log.Fatal("Failed to initialize authorization policy source", zap.Error(err))
+    }
+    policyCache, err := commonauthz.NewCache(policySource)
+    if err != nil {
+        utils.Log.Fatal("Failed to initialize authorization policy cache", zap.Error(err))
+    }
+    // تا اولین دریافت موفق سیاست از profileManager مسیرهای محافظت‌شده 503 برمی‌گردانند.
+    go policyCache.Run(context.Background(), policyRefreshInterval(), func(err error) {
+        utils.Log.Warn("Failed to refresh authorization policy", zap.Error(err))
+    })
+    permissionService, err := authz.NewPermissionService(utils.Log, policyCache)
     if err != nil {
         utils.Log.Fatal("Failed to initialize PermissionService. Exiting application.", zap.Error(err))
     }
@@ -35,10 +60,180 @@ func StartServer(port string) error {
     if err != nil {
         utils.Log.Fatal("Failed to initialize customer repository", zap.Error(err))
     }
-    customerService, err := service.NewCustomerService(customerRepo, utils.Log)
+    auditRepo, err := postgresDb.NewAuditRepository(postgresDb.DB, utils.Log)
+    if err != nil {
+        utils.Log.Fatal("Failed to initialize audit repository", zap.Error(err))
+    }
+    auditService, err := service.NewAuditService(auditRepo, utils.Log)
+    if err != nil {
+        utils.Log.Fatal("Failed to initialize audit service", zap.Error(err))
+    }
+    auditHandler := handler.NewAuditHandler(auditService)
+    customerService, err := service.NewCustomerService(customerRepo, auditService, utils.Log)
     if err != nil {
         utils.Log.Fatal("Failed to initialize customer service", zap.Error(err))
     }
+    ledgerRepo, err := postgresDb.NewLedgerRepository(postgresDb.DB, utils.Log)
+    if err != nil {
+        utils.Log.Fatal("Failed to initialize ledger repository", zap.Error(err))
+    }
+    bankRepo, err := postgresDb.NewBankRepository(postgresDb.DB, utils.Log)
+    if err != nil {
+        utils.Log.Fatal("Failed to initialize bank repository", zap.Error(err))
+    }
+    chequeRepo, err := postgresDb.NewChequeRepository(postgresDb.DB, utils.Log)
+    if err != nil {
+        utils.Log.Fatal("Failed to initialize cheque repository", zap.Error(err))
+    }
+    chequeService, err := service.NewChequeService(chequeRepo, customerRepo, bankRepo, ledgerRepo, utils.Log)
+    if err != nil {
+        utils.Log.Fatal("Failed to initialize cheque service", zap.Error(err))
+    }
+    notificationBaseURL := os.Getenv("NOTIFICATION_MANAGER_BASE_URL")
+    if notificationBaseURL == "" {
+        notificationBaseURL = "http://localhost:8084"
+    }
+    notificationClient, err := notification.NewClient(notificationBaseURL)
+    if err != nil {
+        utils.Log.Fatal("Failed to initialize notification client", zap.Error(err))
+    }
+    alertWindowDays := 3
+    if v := os.Getenv("CHEQUE_ALERT_WINDOW_DAYS"); v != "" {
+        days, err := strconv.Atoi(v)
+        if err != nil {
+            utils.Log.Fatal("CHEQUE_ALERT_WINDOW_DAYS must be an integer", zap.String("value", v))
+        }
+        alertWindowDays = days
+    }
+    chequeAlertService, err := service.NewChequeAlertService(chequeRepo, notificationClient, alertWindowDays, utils.Log)
+    if err != nil {
+        utils.Log.Fatal("Failed to initialize cheque alert service", zap.Error(err))
+    }
+    if _, err := jobs.StartChequeAlertJob(chequeAlertService, os.Getenv("CHEQUE_ALERT_CRON"), utils.Log); err != nil {
+        utils.Log.Fatal("Failed to schedule cheque alert job", zap.Error(err))
+    }
+    chequeHandler := handler.NewChequeHandler(chequeService, chequeAlertService)
+    fundRepo, err := postgresDb.NewFundRepository(postgresDb.DB, utils.Log)
+    if err != nil {
+        utils.Log.Fatal("Failed to initialize fund repository", zap.Error(err))
+    }
+    currencyRepo, err := postgresDb.NewCurrencyRepository(postgresDb.DB, utils.Log)
+    if err != nil {
+        utils.Log.Fatal("Failed to initialize currency repository", zap.Error(err))
+    }
+    currencyService, err := service.NewCurrencyService(currencyRepo, utils.Log)
+    if err != nil {
+        utils.Log.Fatal("Failed to initialize currency service", zap.Error(err))
+    }
+    currencyHandler := handler.NewCurrencyHandler(currencyService)
+    fundService, err := service.NewFundService(fundRepo, customerRepo, bankRepo, ledgerRepo, currencyService, utils.Log)
+    if err != nil {
+        utils.Log.Fatal("Failed to initialize fund service", zap.Error(err))
+    }
+    fundHandler := handler.NewFundHandler(fundService)
+    bankService, err := service.NewBankService(bankRepo, fundRepo, customerRepo, ledgerRepo, utils.Log)
+    if err != nil {
+        utils.Log.Fatal("Failed to initialize bank service", zap.Error(err))
+    }
+    bankHandler := handler.NewBankHandler(bankService)
+    settingRepo, err := postgresDb.NewSettingRepository(postgresDb.DB, utils.Log)
+    if err != nil {
+        utils.Log.Fatal("Failed to initialize setting repository", zap.Error(err))
+    }
+    settingService, err := service.NewSettingService(settingRepo, auditService, utils.Log)
+    if err != nil {
+        utils.Log.Fatal("Failed to initialize setting service", zap.Error(err))
+    }
+    settingHandler := handler.NewSettingHandler(settingService)
+    transferRepo, err := postgresDb.NewTransferRepository(postgresDb.DB, utils.Log)
+    if err != nil {
+        utils.Log.Fatal("Failed to initialize transfer repository", zap.Error(err))
+    }
+    transferService, err := service.NewTransferService(transferRepo, fundRepo, bankRepo, customerRepo, settingRepo, ledgerRepo, utils.Log)
+    if err != nil {
+        utils.Log.Fatal("Failed to initialize transfer service", zap.Error(err))
+    }
+    transferHandler := handler.NewTransferHandler(transferService)
+    centerRepo, err := postgresDb.NewCenterRepository(postgresDb.DB, utils.Log)
+    if err != nil {
+        utils.Log.Fatal("Failed to initialize center repository", zap.Error(err))
+    }
+    centerService, err := service.NewCenterService(centerRepo, fundRepo, bankRepo, customerRepo, ledgerRepo, utils.Log)
+    if err != nil {
+        utils.Log.Fatal("Failed to initialize center service", zap.Error(err))
+    }
+    recurringExpenseService, err := service.NewRecurringExpenseService(centerRepo, fundRepo, bankRepo, utils.Log)
+    if err != nil {
+        utils.Log.Fatal("Failed to initialize recurring expense service", zap.Error(err))
+    }
+    if _, err := jobs.StartRecurringExpenseJob(recurringExpenseService, os.Getenv("RECURRING_EXPENSE_CRON"), utils.Log); err != nil {
+        utils.Log.Fatal("Failed to schedule recurring expense job", zap.Error(err))
+    }
+    centerHandler := handler.NewCenterHandler(centerService, recurringExpenseService)
+    accountRepo, err := postgresDb.NewAccountRepository(postgresDb.DB, utils.Log)
+    if err != nil {
+        utils.Log.Fatal("Failed to initialize account repository", zap.Error(err))
+    }
+    ledgerService, err := service.NewLedgerService(accountRepo, ledgerRepo, customerRepo, fundRepo, bankRepo, centerRepo, utils.Log)
+    if err != nil {
+        utils.Log.Fatal("Failed to initialize ledger service", zap.Error(err))
+    }
+    ledgerHandler := handler.NewLedgerHandler(ledgerService)
+    fiscalYearRepo, err := postgresDb.NewFiscalYearRepository(postgresDb.DB, utils.Log)
+    if err != nil {
+        utils.Log.Fatal("Failed to initialize fiscal year repository", zap.Error(err))
+    }
+    fiscalYearService, err := service.NewFiscalYearService(fiscalYearRepo, accountRepo, ledgerRepo, customerRepo, utils.Log)
+    if err != nil {
+        utils.Log.Fatal("Failed to initialize fiscal year service", zap.Error(err))
+    }
+    fiscalYearHandler := handler.NewFiscalYearHandler(fiscalYearService)
+    reportService, err := service.NewReportService(accountRepo, ledgerRepo, fiscalYearRepo, utils.Log)
+    if err != nil {
+        utils.Log.Fatal("Failed to initialize report service", zap.Error(err))
+    }
+    reportHandler := handler.NewReportHandler(reportService)
+    saleRepo, err := postgresDb.NewSaleRepository(postgresDb.DB, utils.Log)
+    if err != nil {
+        utils.Log.Fatal("Failed to initialize sale repository", zap.Error(err))
+    }
+    inventoryRepo, err := postgresDb.NewInventoryRepository(postgresDb.DB, utils.Log)
+    if err != nil {
+        utils.Log.Fatal("Failed to initialize inventory repository", zap.Error(err))
+    }
+    inventoryService, err := service.NewInventoryService(inventoryRepo, customerRepo, settingRepo, utils.Log)
+    if err != nil {
+        utils.Log.Fatal("Failed to initialize inventory service", zap.Error(err))
+    }
+    inventoryHandler := handler.NewInventoryHandler(inventoryService)
+    taxRepo, err := postgresDb.NewTaxRepository(postgresDb.DB, utils.Log)
+    if err != nil {
+        utils.Log.Fatal("Failed to initialize tax repository", zap.Error(err))
+    }
+    taxRuleService, err := service.NewTaxRuleService(taxRepo, customerRepo, utils.Log)
+    if err != nil {
+        utils.Log.Fatal("Failed to initialize tax rule service", zap.Error(err))
+    }
+    saleService, err := service.NewSaleService(saleRepo, inventoryRepo, customerRepo, ledgerRepo, taxRuleService, currencyService, auditService, utils.Log)
+    if err != nil {
+        utils.Log.Fatal("Failed to initialize sale service", zap.Error(err))
+    }
+    saleHandler := handler.NewSaleHandler(saleService, permissionService)
+    // MOADIAN_BASE_URL می‌تواند به شبیه‌ساز محلی (cmd/moadian-mock) اشاره کند
+    moadianClients := moadian.NewHTTPClientFactory(os.Getenv("MOADIAN_BASE_URL"), 3, 2*time.Second)
+    // TAX_KEY_ENCRYPTION_KEY کلید ۳۲ بایتی base64 برای رمز کردن کلید خصوصی حافظه‌های مالیاتی در پایگاه داده
+    taxKeyBox, err := utils.NewSecretBox(os.Getenv("TAX_KEY_ENCRYPTION_KEY"))
+    if err != nil {
+        utils.Log.Fatal("TAX_KEY_ENCRYPTION_KEY is missing or invalid", zap.Error(err))
+    }
+    taxService, err := service.NewTaxService(taxRepo, saleRepo, customerRepo, moadianClients, taxKeyBox, utils.Log)
+    if err != nil {
+        utils.Log.Fatal("Failed to initialize tax service", zap.Error(err))
+    }
+    if _, err := jobs.StartTaxStatusJob(taxService, os.Getenv("TAX_STATUS_CRON"), utils.Log); err != nil {
+        utils.Log.Fatal("Failed to schedule tax status job", zap.Error(err))
+    }
+    taxHandler := handler.NewTaxHandler(taxService, taxRuleService)
     crmHandler := handler.NewCrmHandler(customerService)
     if crmHandler == nil {
         utils.Log.Fatal("Failed to initialize CrmHandler", zap.Error(fmt.Errorf("crmHandler cannot be nil")))
@@ -49,7 +244,7 @@ func StartServer(port string) error {
     }
 
     // ⭐ STEP 1: All valid routes are set up here.
-    if err := SetUpAllRoutes(app, crmHandler, authZMiddlewareForCRM); err != nil {
+    if err := SetUpAllRoutes(app, crmHandler, chequeHandler, fundHandler, bankHandler, transferHandler, settingHandler, centerHandler, ledgerHandler, fiscalYearHandler, reportHandler, saleHandler, inventoryHandler, taxHandler, currencyHandler, auditHandler, authZMiddlewareForCRM); err != nil {
         utils.Log.Fatal("CRM Manager Service failed to start Fiber server", zap.Error(err))
     }
 
@@ -62,4 +257,16 @@ func StartServer(port string) error {
     fullAddr := fmt.Sprintf("0.0.0.0%s", port)
     utils.Log.Info("Crm Manager is attempting to listen", zap.String("address", fullAddr))
     return app.Listen(port)
-}
\ No newline at end of file
+}
+
+// policyRefreshInterval فاصله بررسی نسخه جدید سیاست دسترسی (AUTHZ_POLICY_REFRESH_SECONDS، پیش‌فرض ۳۰ ثانیه)
+func policyRefreshInterval() time.Duration {
+	if v := os.Getenv("AUTHZ_POLICY_REFRESH_SECONDS"); v != "" {
+		seconds, err := strconv.Atoi(v)
+		if err != nil || seconds <= 0 {
+			utils.Log.Fatal("AUTHZ_POLICY_REFRESH_SECONDS must be a positive integer", zap.String("value", v))
+		}
+		return time.Duration(seconds) * time.Second
+	}
+	return 30 * time.Second
+}
@@ -0,0 +1,45 @@
+package router
+
+import (
+	"crm-gold/internal/api/handler"
+	"crm-gold/internal/api/middleware"
+	"crm-gold/internal/model"
+	"crm-gold/internal/utils"
+	"fmt"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+func SetUpTaxRoutes(app *fiber.App, taxHandler *handler.TaxHandler, AuthZMiddleware *middleware.AuthZMiddleware) error {
+	if app == nil {
+		return fmt.Errorf("fiber app instance cannot be nil in CrmManager.")
+	}
+	if taxHandler == nil {
+		return fmt.Errorf("taxHandler is nil in CrmManager's SetUpTaxRoutes.")
+	}
+	if AuthZMiddleware == nil {
+		return fmt.Errorf("authz middleware is nil in CrmManager's SetUpTaxRoutes.")
+	}
+
+	utils.Log.Info("Setting up tax routes in CrmManager...")
+
+	app.Get("/crm/settings/tax", AuthZMiddleware.VerifyUserJWT(model.PermSystemSettingsRead), taxHandler.HandleGetSettings)
+	app.Put("/crm/settings/tax", AuthZMiddleware.VerifyUserJWT(model.PermSystemSettingsManage), taxHandler.HandleUpdateSettings)
+
+	ruleGroup := app.Group("/crm/settings/tax/rules")
+	ruleGroup.Get("/", AuthZMiddleware.VerifyUserJWT(model.PermSystemSettingsRead), taxHandler.HandleListRules)
+	ruleGroup.Post("/", AuthZMiddleware.VerifyUserJWT(model.PermSystemSettingsManage), taxHandler.HandleCreateRule)
+	ruleGroup.Post("/preview", AuthZMiddleware.VerifyUserJWT(model.PermSystemSettingsRead), taxHandler.HandlePreviewTax)
+	ruleGroup.Get("/:id", AuthZMiddleware.VerifyUserJWT(model.PermSystemSettingsRead), taxHandler.HandleGetRule)
+	ruleGroup.Put("/:id", AuthZMiddleware.VerifyUserJWT(model.PermSystemSettingsManage), taxHandler.HandleUpdateRule)
+
+	app.Post("/crm/sale-invoices/:id/tax-submit", AuthZMiddleware.VerifyUserJWT(model.PermTransactionUpdateSaleInvoice), taxHandler.HandleSubmitInvoice)
+
+	submissionGroup := app.Group("/crm/tax/submissions")
+	submissionGroup.Get("/", AuthZMiddleware.VerifyUserJWT(model.PermTransactionReadSaleInvoice), taxHandler.HandleListSubmissions)
+	submissionGroup.Get("/:id", AuthZMiddleware.VerifyUserJWT(model.PermTransactionReadSaleInvoice), taxHandler.HandleGetSubmission)
+	submissionGroup.Post("/:id/refresh", AuthZMiddleware.VerifyUserJWT(model.PermTransactionUpdateSaleInvoice), taxHandler.HandleRefreshSubmission)
+
+	utils.Log.Info("Tax routes set up successfully in CrmManager.")
+	return nil
+}
@@ -0,0 +1,35 @@
+package router
+
+import (
+	"crm-gold/internal/api/handler"
+	"crm-gold/internal/api/middleware"
+	"crm-gold/internal/model"
+	"crm-gold/internal/utils"
+	"fmt"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+func SetUpTransferRoutes(app *fiber.App, transferHandler *handler.TransferHandler, AuthZMiddleware *middleware.AuthZMiddleware) error {
+	if app == nil {
+		return fmt.Errorf("fiber app instance cannot be nil in CrmManager.")
+	}
+	if transferHandler == nil {
+		return fmt.Errorf("transferHandler is nil in CrmManager's SetUpTransferRoutes.")
+	}
+	if AuthZMiddleware == nil {
+		return fmt.Errorf("authz middleware is nil in CrmManager's SetUpTransferRoutes.")
+	}
+
+	transferGroup := app.Group("/crm/transfers", AuthZMiddleware.VerifyUserJWT(model.PermTransactionManagePayments))
+	utils.Log.Info("Setting up transfer routes in CrmManager...")
+
+	transferGroup.Get("/", transferHandler.HandleListTransfers)
+	transferGroup.Post("/", transferHandler.HandleCreateTransfer)
+	transferGroup.Get("/:id", transferHandler.HandleGetTransfer)
+	transferGroup.Post("/:id/reverse", transferHandler.HandleReverseTransfer)
+	transferGroup.Get("/:id/entries", transferHandler.HandleGetTransferEntries)
+
+	utils.Log.Info("Transfer routes set up successfully in CrmManager.")
+	return nil
+}
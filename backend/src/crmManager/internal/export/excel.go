@@ -0,0 +1,157 @@
+package export
+
+import (
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/xuri/excelize/v2"
+)
+
+const excelSheet = "گزارش"
+
+type excelStyles struct {
+	title, subtitle, header, text, textBold int
+	numbers, numbersBold                    map[int]int
+}
+
+func numberFormat(decimals int) string {
+	if decimals <= 0 {
+		return "#,##0;(#,##0)"
+	}
+	f := "#,##0." + strings.Repeat("0", decimals)
+	return f + ";(" + f + ")"
+}
+
+func newExcelStyles(f *excelize.File, columns []Column) (*excelStyles, error) {
+	border := []excelize.Border{
+		{Type: "left", Color: "BFBFBF", Style: 1},
+		{Type: "right", Color: "BFBFBF", Style: 1},
+		{Type: "top", Color: "BFBFBF", Style: 1},
+		{Type: "bottom", Color: "BFBFBF", Style: 1},
+	}
+	rtl := &excelize.Alignment{Horizontal: "right", Vertical: "center", ReadingOrder: 2}
+	emphasisFill := excelize.Fill{Type: "pattern", Color: []string{"F2F2F2"}, Pattern: 1}
+
+	s := &excelStyles{numbers: map[int]int{}, numbersBold: map[int]int{}}
+	var err error
+	if s.title, err = f.NewStyle(&excelize.Style{Font: &excelize.Font{Bold: true, Size: 14}, Alignment: &excelize.Alignment{Horizontal: "center", ReadingOrder: 2}}); err != nil {
+		return nil, err
+	}
+	if s.subtitle, err = f.NewStyle(&excelize.Style{Font: &excelize.Font{Color: "595959"}, Alignment: &excelize.Alignment{Horizontal: "center", ReadingOrder: 2}}); err != nil {
+		return nil, err
+	}
+	if s.header, err = f.NewStyle(&excelize.Style{Border: border, Font: &excelize.Font{Bold: true}, Fill: excelize.Fill{Type: "pattern", Color: []string{"DDEBF7"}, Pattern: 1}, Alignment: &excelize.Alignment{Horizontal: "center", Vertical: "center", ReadingOrder: 2, WrapText: true}}); err != nil {
+		return nil, err
+	}
+	if s.text, err = f.NewStyle(&excelize.Style{Border: border, Alignment: rtl}); err != nil {
+		return nil, err
+	}
+	if s.textBold, err = f.NewStyle(&excelize.Style{Border: border, Font: &excelize.Font{Bold: true}, Fill: emphasisFill, Alignment: rtl}); err != nil {
+		return nil, err
+	}
+	for _, col := range columns {
+		if !col.Numeric {
+			continue
+		}
+		if _, ok := s.numbers[col.Decimals]; ok {
+			continue
+		}
+		format := numberFormat(col.Decimals)
+		if s.numbers[col.Decimals], err = f.NewStyle(&excelize.Style{Border: border, CustomNumFmt: &format}); err != nil {
+			return nil, err
+		}
+		if s.numbersBold[col.Decimals], err = f.NewStyle(&excelize.Style{Border: border, Font: &excelize.Font{Bold: true}, Fill: emphasisFill, CustomNumFmt: &format}); err != nil {
+			return nil, err
+		}
+	}
+	return s, nil
+}
+
+// WriteXLSX جدول را در یک برگه راست‌به‌چپ Excel می‌نویسد؛ مبالغ به صورت عددی ذخیره می‌شوند تا در Excel قابل محاسبه باشند.
+func WriteXLSX(w io.Writer, t *Table) error {
+	f := excelize.NewFile()
+	defer f.Close()
+
+	if err := f.SetSheetName(f.GetSheetName(0), excelSheet); err != nil {
+		return err
+	}
+	rtl := true
+	if err := f.SetSheetView(excelSheet, -1, &excelize.ViewOptions{RightToLeft: &rtl}); err != nil {
+		return err
+	}
+	styles, err := newExcelStyles(f, t.Columns)
+	if err != nil {
+		return fmt.Errorf("failed to create excel styles: %w", err)
+	}
+
+	lastCol, _ := excelize.ColumnNumberToName(len(t.Columns))
+	row := 1
+	setRow := func(text string, style int) error {
+		if err := f.SetCellValue(excelSheet, fmt.Sprintf("A%d", row), text); err != nil {
+			return err
+		}
+		if err := f.MergeCell(excelSheet, fmt.Sprintf("A%d", row), fmt.Sprintf("%s%d", lastCol, row)); err != nil {
+			return err
+		}
+		err := f.SetCellStyle(excelSheet, fmt.Sprintf("A%d", row), fmt.Sprintf("%s%d", lastCol, row), style)
+		row++
+		return err
+	}
+	if err := setRow(t.Title, styles.title); err != nil {
+		return err
+	}
+	for _, sub := range t.Subtitle {
+		if err := setRow(sub, styles.subtitle); err != nil {
+			return err
+		}
+	}
+	row++
+
+	for i, col := range t.Columns {
+		cell, _ := excelize.CoordinatesToCellName(i+1, row)
+		if err := f.SetCellValue(excelSheet, cell, col.Header); err != nil {
+			return err
+		}
+		if err := f.SetCellStyle(excelSheet, cell, cell, styles.header); err != nil {
+			return err
+		}
+		name, _ := excelize.ColumnNumberToName(i + 1)
+		if err := f.SetColWidth(excelSheet, name, name, col.Width*4); err != nil {
+			return err
+		}
+	}
+	row++
+
+	for _, r := range t.Rows {
+		for i, col := range t.Columns {
+			cell, _ := excelize.CoordinatesToCellName(i+1, row)
+			var value interface{}
+			if i < len(r.Cells) {
+				value = r.Cells[i]
+			}
+			if d, ok := value.(decimalValue); ok {
+				value = d.Float64()
+			}
+			style := styles.text
+			switch {
+			case col.Numeric && r.Emphasis:
+				style = styles.numbersBold[col.Decimals]
+			case col.Numeric:
+				style = styles.numbers[col.Decimals]
+			case r.Emphasis:
+				style = styles.textBold
+			}
+			if value != nil {
+				if err := f.SetCellValue(excelSheet, cell, value); err != nil {
+					return err
+				}
+			}
+			if err := f.SetCellStyle(excelSheet, cell, cell, style); err != nil {
+				return err
+			}
+		}
+		row++
+	}
+	return f.Write(w)
+}
@@ -0,0 +1,130 @@
+package export
+
+import (
+	_ "embed"
+	"io"
+	"time"
+
+	"crm-gold/internal/utils"
+
+	"github.com/go-pdf/fpdf"
+)
+
+// فونت DejaVu Sans Condensed شکل‌های نمایشی حروف فارسی را دارد و بدون وابستگی به فونت‌های سیستم در PDF جاسازی می‌شود.
+//
+//go:embed fonts/DejaVuSansCondensed.ttf
+var pdfFont []byte
+
+const (
+	pdfFontFamily = "dejavu"
+	pdfMargin     = 10.0
+	pdfRowHeight  = 7.0
+)
+
+// fitText متن را در صورت نیاز کوتاه می‌کند تا در عرض خانه جا شود.
+func fitText(pdf *fpdf.Fpdf, text string, width float64) string {
+	visual := visualRTL(text)
+	if pdf.GetStringWidth(visual) <= width {
+		return visual
+	}
+	runes := []rune(text)
+	for len(runes) > 0 {
+		runes = runes[:len(runes)-1]
+		visual = visualRTL(string(runes) + "…")
+		if pdf.GetStringWidth(visual) <= width {
+			return visual
+		}
+	}
+	return ""
+}
+
+// WritePDF جدول را به صورت PDF راست‌به‌چپ فارسی می‌نویسد؛ ستون اول در سمت راست صفحه قرار می‌گیرد و سرستون‌ها در هر صفحه تکرار می‌شوند.
+func WritePDF(w io.Writer, t *Table) error {
+	orientation := "P"
+	if len(t.Columns) > 5 {
+		orientation = "L"
+	}
+	pdf := fpdf.New(orientation, "mm", "A4", "")
+	pdf.AddUTF8FontFromBytes(pdfFontFamily, "", pdfFont)
+	pdf.SetAutoPageBreak(false, pdfMargin)
+	pdf.SetMargins(pdfMargin, pdfMargin, pdfMargin)
+	pageW, pageH := pdf.GetPageSize()
+	printedAt := ToPersianDigits(utils.FormatJalali(time.Now()))
+	pdf.SetFooterFunc(func() {
+		pdf.SetFont(pdfFontFamily, "", 8)
+		pdf.SetTextColor(110, 110, 110)
+		pdf.SetXY(pdfMargin, pageH-pdfMargin+2)
+		pdf.CellFormat(pageW-2*pdfMargin, 5, visualRTL("صفحه "+ToPersianDigits(itoa(pdf.PageNo()))+" - تاریخ چاپ "+printedAt), "", 0, "C", false, 0, "")
+	})
+
+	var totalWidth float64
+	for _, col := range t.Columns {
+		totalWidth += col.Width
+	}
+	widths := make([]float64, len(t.Columns))
+	for i, col := range t.Columns {
+		widths[i] = col.Width / totalWidth * (pageW - 2*pdfMargin)
+	}
+
+	drawRow := func(cells []string, aligns []string, fill bool) {
+		y := pdf.GetY()
+		x := pageW - pdfMargin
+		for i, text := range cells {
+			x -= widths[i]
+			pdf.SetXY(x, y)
+			pdf.CellFormat(widths[i], pdfRowHeight, fitText(pdf, text, widths[i]-2), "1", 0, aligns[i], fill, 0, "")
+		}
+		pdf.SetXY(pdfMargin, y+pdfRowHeight)
+	}
+	headers := make([]string, len(t.Columns))
+	headerAligns := make([]string, len(t.Columns))
+	aligns := make([]string, len(t.Columns))
+	for i, col := range t.Columns {
+		headers[i] = col.Header
+		headerAligns[i] = "CM"
+		aligns[i] = "RM"
+		if col.Numeric {
+			aligns[i] = "LM"
+		}
+	}
+	drawHeader := func() {
+		pdf.SetFont(pdfFontFamily, "", 9)
+		pdf.SetFillColor(221, 235, 247)
+		pdf.SetTextColor(0, 0, 0)
+		pdf.SetDrawColor(170, 170, 170)
+		drawRow(headers, headerAligns, true)
+	}
+
+	pdf.AddPage()
+	pdf.SetFont(pdfFontFamily, "", 14)
+	pdf.CellFormat(pageW-2*pdfMargin, 9, visualRTL(t.Title), "", 1, "C", false, 0, "")
+	pdf.SetFont(pdfFontFamily, "", 9)
+	pdf.SetTextColor(90, 90, 90)
+	for _, sub := range t.Subtitle {
+		pdf.CellFormat(pageW-2*pdfMargin, 6, visualRTL(ToPersianDigits(sub)), "", 1, "C", false, 0, "")
+	}
+	pdf.Ln(3)
+	drawHeader()
+
+	pdf.SetFont(pdfFontFamily, "", 8.5)
+	for _, r := range t.Rows {
+		if pdf.GetY()+pdfRowHeight > pageH-pdfMargin-2 {
+			pdf.AddPage()
+			drawHeader()
+			pdf.SetFont(pdfFontFamily, "", 8.5)
+		}
+		cells := make([]string, len(t.Columns))
+		for i, col := range t.Columns {
+			if i < len(r.Cells) {
+				cells[i] = ToPersianDigits(cellText(r.Cells[i], col))
+			}
+		}
+		pdf.SetFillColor(242, 242, 242)
+		drawRow(cells, aligns, r.Emphasis)
+	}
+	return pdf.Output(w)
+}
+
+func itoa(n int) string {
+	return formatAmount(float64(n), 0)
+}
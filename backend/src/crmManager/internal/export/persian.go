@@ -0,0 +1,197 @@
+package export
+
+import "unicode"
+
+// شکل‌های نمایشی هر حرف به ترتیب: تنها، پایانی، آغازی، میانی؛ صفر یعنی حرف آن شکل را ندارد (فقط به حرف قبل می‌چسبد).
+var persianForms = map[rune][4]rune{
+	'ء': {0xFE80, 0, 0, 0},
+	'آ': {0xFE81, 0xFE82, 0, 0},
+	'أ': {0xFE83, 0xFE84, 0, 0},
+	'ؤ': {0xFE85, 0xFE86, 0, 0},
+	'إ': {0xFE87, 0xFE88, 0, 0},
+	'ئ': {0xFE89, 0xFE8A, 0xFE8B, 0xFE8C},
+	'ا': {0xFE8D, 0xFE8E, 0, 0},
+	'ب': {0xFE8F, 0xFE90, 0xFE91, 0xFE92},
+	'ة': {0xFE93, 0xFE94, 0, 0},
+	'ت': {0xFE95, 0xFE96, 0xFE97, 0xFE98},
+	'ث': {0xFE99, 0xFE9A, 0xFE9B, 0xFE9C},
+	'ج': {0xFE9D, 0xFE9E, 0xFE9F, 0xFEA0},
+	'ح': {0xFEA1, 0xFEA2, 0xFEA3, 0xFEA4},
+	'خ': {0xFEA5, 0xFEA6, 0xFEA7, 0xFEA8},
+	'د': {0xFEA9, 0xFEAA, 0, 0},
+	'ذ': {0xFEAB, 0xFEAC, 0, 0},
+	'ر': {0xFEAD, 0xFEAE, 0, 0},
+	'ز': {0xFEAF, 0xFEB0, 0, 0},
+	'س': {0xFEB1, 0xFEB2, 0xFEB3, 0xFEB4},
+	'ش': {0xFEB5, 0xFEB6, 0xFEB7, 0xFEB8},
+	'ص': {0xFEB9, 0xFEBA, 0xFEBB, 0xFEBC},
+	'ض': {0xFEBD, 0xFEBE, 0xFEBF, 0xFEC0},
+	'ط': {0xFEC1, 0xFEC2, 0xFEC3, 0xFEC4},
+	'ظ': {0xFEC5, 0xFEC6, 0xFEC7, 0xFEC8},
+	'ع': {0xFEC9, 0xFECA, 0xFECB, 0xFECC},
+	'غ': {0xFECD, 0xFECE, 0xFECF, 0xFED0},
+	'ـ': {0x0640, 0x0640, 0x0640, 0x0640},
+	'ف': {0xFED1, 0xFED2, 0xFED3, 0xFED4},
+	'ق': {0xFED5, 0xFED6, 0xFED7, 0xFED8},
+	'ك': {0xFED9, 0xFEDA, 0xFEDB, 0xFEDC},
+	'ل': {0xFEDD, 0xFEDE, 0xFEDF, 0xFEE0},
+	'م': {0xFEE1, 0xFEE2, 0xFEE3, 0xFEE4},
+	'ن': {0xFEE5, 0xFEE6, 0xFEE7, 0xFEE8},
+	'ه': {0xFEE9, 0xFEEA, 0xFEEB, 0xFEEC},
+	'و': {0xFEED, 0xFEEE, 0, 0},
+	'ى': {0xFEEF, 0xFEF0, 0, 0},
+	'ي': {0xFEF1, 0xFEF2, 0xFEF3, 0xFEF4},
+	'پ': {0xFB56, 0xFB57, 0xFB58, 0xFB59},
+	'چ': {0xFB7A, 0xFB7B, 0xFB7C, 0xFB7D},
+	'ژ': {0xFB8A, 0xFB8B, 0, 0},
+	'ک': {0xFB8E, 0xFB8F, 0xFB90, 0xFB91},
+	'گ': {0xFB92, 0xFB93, 0xFB94, 0xFB95},
+	'ی': {0xFBFC, 0xFBFD, 0xFBFE, 0xFBFF},
+}
+
+// لام‌الف: شکل تنها و پایانی ترکیب لام با انواع الف
+var lamAlefForms = map[rune][2]rune{
+	'آ': {0xFEF5, 0xFEF6},
+	'أ': {0xFEF7, 0xFEF8},
+	'إ': {0xFEF9, 0xFEFA},
+	'ا': {0xFEFB, 0xFEFC},
+}
+
+const zwnj = '‌'
+
+// اعراب در اتصال حروف نقشی ندارند.
+func isTransparent(r rune) bool {
+	return r >= 'ً' && r <= 'ْ'
+}
+
+func joinsForward(r rune) bool {
+	f, ok := persianForms[r]
+	return ok && f[2] != 0
+}
+
+func joinsBackward(r rune) bool {
+	f, ok := persianForms[r]
+	return ok && f[1] != 0
+}
+
+// shapePersian حروف فارسی را با توجه به حروف مجاور به شکل نمایشی (Presentation Forms) تبدیل می‌کند.
+func shapePersian(s string) []rune {
+	in := []rune(s)
+	out := make([]rune, 0, len(in))
+	neighbour := func(i, step int) rune {
+		for j := i + step; j >= 0 && j < len(in); j += step {
+			if !isTransparent(in[j]) {
+				return in[j]
+			}
+		}
+		return 0
+	}
+	for i := 0; i < len(in); i++ {
+		r := in[i]
+		if r == zwnj {
+			continue
+		}
+		forms, ok := persianForms[r]
+		if !ok {
+			out = append(out, r)
+			continue
+		}
+		prevJoins := joinsForward(neighbour(i, -1))
+		if r == 'ل' {
+			if next := neighbour(i, 1); next != 0 {
+				if la, ok := lamAlefForms[next]; ok {
+					if prevJoins {
+						out = append(out, la[1])
+					} else {
+						out = append(out, la[0])
+					}
+					for i++; in[i] != next; i++ {
+					}
+					continue
+				}
+			}
+		}
+		nextJoins := joinsForward(r) && joinsBackward(neighbour(i, 1))
+		prevJoins = prevJoins && joinsBackward(r)
+		switch {
+		case prevJoins && nextJoins:
+			out = append(out, forms[3])
+		case nextJoins:
+			out = append(out, forms[2])
+		case prevJoins:
+			out = append(out, forms[1])
+		default:
+			out = append(out, forms[0])
+		}
+	}
+	return out
+}
+
+func isLTRRune(r rune) bool {
+	return r < 0x80 && (unicode.IsLetter(r) || unicode.IsDigit(r)) || (r >= '۰' && r <= '۹') || (r >= '٠' && r <= '٩')
+}
+
+func isRTLRune(r rune) bool {
+	return !isLTRRune(r) && (r >= 0x0600 && r <= 0x06FF || r >= 0xFB50 && r <= 0xFEFF)
+}
+
+var mirrored = map[rune]rune{'(': ')', ')': '(', '[': ']', ']': '[', '<': '>', '>': '<', '«': '»', '»': '«'}
+
+// visualRTL متن منطقی را به ترتیب دیداری راست به چپ برای چاپ چپ به راست در PDF تبدیل می‌کند؛
+// اعداد و کلمات لاتین ترتیب خود را حفظ می‌کنند و علائم بین دو بخش چپ‌به‌راست جزو همان بخش می‌مانند.
+func visualRTL(s string) string {
+	runes := shapePersian(s)
+	ltr := make([]bool, len(runes))
+	for i, r := range runes {
+		ltr[i] = isLTRRune(r)
+	}
+	// علائم خنثی بین دو نویسه چپ‌به‌راست (مانند جداکننده هزارگان یا / در تاریخ) جزو همان بخش هستند.
+	for i := 0; i < len(runes); {
+		if ltr[i] || isRTLRune(runes[i]) {
+			i++
+			continue
+		}
+		end := i
+		for end < len(runes) && !ltr[end] && !isRTLRune(runes[end]) {
+			end++
+		}
+		if i > 0 && ltr[i-1] && end < len(runes) && ltr[end] {
+			for j := i; j < end; j++ {
+				ltr[j] = true
+			}
+		}
+		i = end
+	}
+
+	out := make([]rune, 0, len(runes))
+	for end := len(runes); end > 0; {
+		start := end - 1
+		for start > 0 && ltr[start-1] == ltr[end-1] {
+			start--
+		}
+		if ltr[end-1] {
+			out = append(out, runes[start:end]...)
+		} else {
+			for j := end - 1; j >= start; j-- {
+				r := runes[j]
+				if m, ok := mirrored[r]; ok {
+					r = m
+				}
+				out = append(out, r)
+			}
+		}
+		end = start
+	}
+	return string(out)
+}
+
+// ToPersianDigits ارقام لاتین را به ارقام فارسی تبدیل می‌کند.
+func ToPersianDigits(s string) string {
+	out := []rune(s)
+	for i, r := range out {
+		if r >= '0' && r <= '9' {
+			out[i] = '۰' + (r - '0')
+		}
+	}
+	return string(out)
+}
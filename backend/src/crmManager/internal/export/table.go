@@ -0,0 +1,95 @@
+package export
+
+import (
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+)
+
+const (
+	FormatXLSX = "xlsx"
+	FormatPDF  = "pdf"
+)
+
+// Column ستون جدول گزارش؛ Width نسبی است و Decimals فقط برای ستون‌های عددی به کار می‌رود.
+type Column struct {
+	Header   string
+	Width    float64
+	Numeric  bool
+	Decimals int
+}
+
+// Row سطر جدول؛ هر خانه string یا float64 است و Emphasis برای سرفصل بخش‌ها و جمع‌ها پررنگ چاپ می‌شود.
+type Row struct {
+	Cells    []interface{}
+	Emphasis bool
+}
+
+// Table ساختار مشترک خروجی Excel و PDF گزارش‌ها
+type Table struct {
+	Title    string
+	Subtitle []string
+	Columns  []Column
+	Rows     []Row
+}
+
+// ContentType نوع MIME فایل خروجی
+func ContentType(format string) string {
+	if format == FormatPDF {
+		return "application/pdf"
+	}
+	return "application/vnd.openxmlformats-officedocument.spreadsheetml.sheet"
+}
+
+// ValidFormat فرمت‌های پشتیبانی‌شده برای خروجی گزارش
+func ValidFormat(format string) bool {
+	return format == FormatXLSX || format == FormatPDF
+}
+
+// formatAmount مبلغ را با جداکننده هزارگان نمایش می‌دهد؛ مقدار منفی داخل پرانتز (سبک حسابداری) قرار می‌گیرد.
+func formatAmount(v float64, decimals int) string {
+	if math.Abs(v) < math.Pow(10, -float64(decimals))/2 {
+		return "0"
+	}
+	s := strconv.FormatFloat(math.Abs(v), 'f', decimals, 64)
+	intPart, frac := s, ""
+	if i := strings.IndexByte(s, '.'); i >= 0 {
+		intPart, frac = s[:i], strings.TrimRight(s[i:], "0")
+		if frac == "." {
+			frac = ""
+		}
+	}
+	var b strings.Builder
+	for i, r := range intPart {
+		if i > 0 && (len(intPart)-i)%3 == 0 {
+			b.WriteByte(',')
+		}
+		b.WriteRune(r)
+	}
+	b.WriteString(frac)
+	if v < 0 {
+		return "(" + b.String() + ")"
+	}
+	return b.String()
+}
+
+// decimalValue مقدارهای دقیق (مبلغ و وزن) که برای نمایش به float64 تبدیل می‌شوند.
+type decimalValue interface {
+	Float64() float64
+}
+
+func cellText(v interface{}, col Column) string {
+	switch t := v.(type) {
+	case nil:
+		return ""
+	case string:
+		return t
+	case float64:
+		return formatAmount(t, col.Decimals)
+	case decimalValue:
+		return formatAmount(t.Float64(), col.Decimals)
+	default:
+		return fmt.Sprint(t)
+	}
+}
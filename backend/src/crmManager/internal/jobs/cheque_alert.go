@@ -0,0 +1,44 @@
+package jobs
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"crm-gold/internal/service"
+	"crm-gold/internal/utils"
+
+	"github.com/robfig/cron/v3"
+	"go.uber.org/zap"
+)
+
+const DefaultChequeAlertSpec = "0 8 * * *"
+
+// StartChequeAlertJob اجرای روزانه هشدار سررسید چک‌ها را به وقت تهران زمان‌بندی می‌کند.
+func StartChequeAlertJob(alertSvc service.ChequeAlertService, spec string, logger *zap.Logger) (*cron.Cron, error) {
+	if alertSvc == nil {
+		return nil, errors.New("cheque alert service cannot be nil for ChequeAlertJob")
+	}
+	if logger == nil {
+		return nil, errors.New("logger cannot be nil for ChequeAlertJob")
+	}
+	if spec == "" {
+		spec = DefaultChequeAlertSpec
+	}
+
+	c := cron.New(cron.WithLocation(utils.TehranLocation))
+	_, err := c.AddFunc(spec, func() {
+		ctx, cancel := context.WithTimeout(context.Background(), 5*time.Minute)
+		defer cancel()
+		if _, err := alertSvc.RunDueAlerts(ctx, time.Now()); err != nil {
+			logger.Error("Cheque due alert job failed", zap.Error(err))
+		}
+	})
+	if err != nil {
+		return nil, fmt.Errorf("invalid cheque alert schedule %q: %w", spec, err)
+	}
+	c.Start()
+	logger.Info("Cheque due alert job scheduled.", zap.String("spec", spec))
+	return c, nil
+}
@@ -0,0 +1,44 @@
+package jobs
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"crm-gold/internal/service"
+	"crm-gold/internal/utils"
+
+	"github.com/robfig/cron/v3"
+	"go.uber.org/zap"
+)
+
+const DefaultRecurringExpenseSpec = "0 1 * * *"
+
+// StartRecurringExpenseJob ثبت روزانه اسناد هزینه‌های تکراری (اجاره، حقوق و ...) را به وقت تهران زمان‌بندی می‌کند.
+func StartRecurringExpenseJob(recurringSvc service.RecurringExpenseService, spec string, logger *zap.Logger) (*cron.Cron, error) {
+	if recurringSvc == nil {
+		return nil, errors.New("recurring expense service cannot be nil for RecurringExpenseJob")
+	}
+	if logger == nil {
+		return nil, errors.New("logger cannot be nil for RecurringExpenseJob")
+	}
+	if spec == "" {
+		spec = DefaultRecurringExpenseSpec
+	}
+
+	c := cron.New(cron.WithLocation(utils.TehranLocation))
+	_, err := c.AddFunc(spec, func() {
+		ctx, cancel := context.WithTimeout(context.Background(), 5*time.Minute)
+		defer cancel()
+		if _, err := recurringSvc.RunDue(ctx, time.Now()); err != nil {
+			logger.Error("Recurring expense job failed", zap.Error(err))
+		}
+	})
+	if err != nil {
+		return nil, fmt.Errorf("invalid recurring expense schedule %q: %w", spec, err)
+	}
+	c.Start()
+	logger.Info("Recurring expense job scheduled.", zap.String("spec", spec))
+	return c, nil
+}
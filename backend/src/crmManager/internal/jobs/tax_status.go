@@ -0,0 +1,44 @@
+package jobs
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"crm-gold/internal/service"
+	"crm-gold/internal/utils"
+
+	"github.com/robfig/cron/v3"
+	"go.uber.org/zap"
+)
+
+const DefaultTaxStatusSpec = "*/10 * * * *"
+
+// StartTaxStatusJob وضعیت صورتحساب‌های ارسالی به سامانه مودیان را دوره‌ای استعلام و ارسال‌های ناموفق را تکرار می‌کند.
+func StartTaxStatusJob(taxSvc service.TaxService, spec string, logger *zap.Logger) (*cron.Cron, error) {
+	if taxSvc == nil {
+		return nil, errors.New("tax service cannot be nil for TaxStatusJob")
+	}
+	if logger == nil {
+		return nil, errors.New("logger cannot be nil for TaxStatusJob")
+	}
+	if spec == "" {
+		spec = DefaultTaxStatusSpec
+	}
+
+	c := cron.New(cron.WithLocation(utils.TehranLocation))
+	_, err := c.AddFunc(spec, func() {
+		ctx, cancel := context.WithTimeout(context.Background(), 5*time.Minute)
+		defer cancel()
+		if _, err := taxSvc.ProcessPending(ctx); err != nil {
+			logger.Error("Tax status job failed", zap.Error(err))
+		}
+	})
+	if err != nil {
+		return nil, fmt.Errorf("invalid tax status schedule %q: %w", spec, err)
+	}
+	c.Start()
+	logger.Info("Tax status job scheduled.", zap.String("spec", spec))
+	return c, nil
+}
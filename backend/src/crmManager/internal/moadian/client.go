@@ -0,0 +1,250 @@
+package moadian
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+)
+
+// وضعیت پردازش صورتحساب در سامانه
+const (
+	StatusPending    = "PENDING"
+	StatusInProgress = "IN_PROGRESS"
+	StatusSuccess    = "SUCCESS"
+	StatusFailed     = "FAILED"
+	StatusNotFound   = "NOT_FOUND"
+)
+
+// هدرهای احراز هویت: شناسه حافظه، nonce دریافتی از سامانه و امضای nonce#clientId
+const (
+	HeaderClientID  = "X-Client-Id"
+	HeaderNonce     = "X-Nonce"
+	HeaderSignature = "X-Signature"
+)
+
+type Packet struct {
+	UID           string  `json:"uid"`
+	PacketType    string  `json:"packetType"`
+	Retry         bool    `json:"retry"`
+	FiscalID      string  `json:"fiscalId"`
+	Data          Invoice `json:"data"`
+	DataSignature string  `json:"dataSignature"`
+}
+
+type SubmitResult struct {
+	UID             string `json:"uid"`
+	ReferenceNumber string `json:"referenceNumber"`
+	ErrorCode       string `json:"errorCode,omitempty"`
+	ErrorDetail     string `json:"errorDetail,omitempty"`
+}
+
+type Message struct {
+	Code    string `json:"code"`
+	Message string `json:"message"`
+}
+
+type InquiryResult struct {
+	ReferenceNumber string    `json:"referenceNumber"`
+	UID             string    `json:"uid"`
+	Status          string    `json:"status"`
+	Errors          []Message `json:"errors,omitempty"`
+	Warnings        []Message `json:"warnings,omitempty"`
+}
+
+// Final وضعیت نهایی است و نیازی به پیگیری دوباره ندارد.
+func (r InquiryResult) Final() bool {
+	return r.Status == StatusSuccess || r.Status == StatusFailed
+}
+
+// Client ارسال صورتحساب و استعلام وضعیت؛ پیاده‌سازی HTTP یا هر پیاده‌سازی دیگری (مثلا برای آزمون) قابل استفاده است.
+type Client interface {
+	SendInvoices(ctx context.Context, packets []Packet) ([]SubmitResult, error)
+	InquiryByReference(ctx context.Context, referenceNumbers []string) ([]InquiryResult, error)
+}
+
+// APIError پاسخ خطای غیرقابل تکرار سامانه (4xx)
+type APIError struct {
+	StatusCode int
+	Body       string
+}
+
+func (e *APIError) Error() string {
+	return fmt.Sprintf("moadian api returned %d: %s", e.StatusCode, e.Body)
+}
+
+type HTTPConfig struct {
+	BaseURL    string
+	ClientID   string
+	Signer     *Signer
+	Timeout    time.Duration
+	MaxRetries int
+	RetryDelay time.Duration
+}
+
+type HTTPClient struct {
+	baseURL    string
+	clientID   string
+	signer     *Signer
+	http       *http.Client
+	maxRetries int
+	retryDelay time.Duration
+}
+
+func NewHTTPClient(cfg HTTPConfig) (*HTTPClient, error) {
+	if cfg.BaseURL == "" {
+		return nil, errors.New("moadian base url cannot be empty")
+	}
+	if cfg.ClientID == "" {
+		return nil, errors.New("moadian client id cannot be empty")
+	}
+	if cfg.Signer == nil {
+		return nil, errors.New("signer cannot be nil for moadian client")
+	}
+	if cfg.Timeout <= 0 {
+		cfg.Timeout = 30 * time.Second
+	}
+	if cfg.MaxRetries < 0 {
+		cfg.MaxRetries = 0
+	}
+	if cfg.RetryDelay <= 0 {
+		cfg.RetryDelay = time.Second
+	}
+	return &HTTPClient{
+		baseURL:    strings.TrimRight(cfg.BaseURL, "/"),
+		clientID:   cfg.ClientID,
+		signer:     cfg.Signer,
+		http:       &http.Client{Timeout: cfg.Timeout},
+		maxRetries: cfg.MaxRetries,
+		retryDelay: cfg.RetryDelay,
+	}, nil
+}
+
+func (c *HTTPClient) SendInvoices(ctx context.Context, packets []Packet) ([]SubmitResult, error) {
+	var resp struct {
+		Result []SubmitResult `json:"result"`
+	}
+	if err := c.call(ctx, "/invoice", map[string]interface{}{"packets": packets}, &resp); err != nil {
+		return nil, err
+	}
+	return resp.Result, nil
+}
+
+func (c *HTTPClient) InquiryByReference(ctx context.Context, referenceNumbers []string) ([]InquiryResult, error) {
+	var resp struct {
+		Result []InquiryResult `json:"result"`
+	}
+	if err := c.call(ctx, "/inquiry-by-reference-number", map[string]interface{}{"referenceNumbers": referenceNumbers}, &resp); err != nil {
+		return nil, err
+	}
+	return resp.Result, nil
+}
+
+// call خطاهای شبکه، 429 و 5xx را با تاخیر دوبرابرشونده تا maxRetries بار تکرار می‌کند؛ هر تلاش nonce تازه می‌گیرد.
+func (c *HTTPClient) call(ctx context.Context, path string, body, out interface{}) error {
+	payload, err := json.Marshal(body)
+	if err != nil {
+		return fmt.Errorf("failed to marshal moadian request: %w", err)
+	}
+	delay := c.retryDelay
+	var lastErr error
+	for attempt := 0; attempt <= c.maxRetries; attempt++ {
+		if attempt > 0 {
+			select {
+			case <-ctx.Done():
+				return ctx.Err()
+			case <-time.After(delay):
+			}
+			delay *= 2
+		}
+		lastErr = c.do(ctx, path, payload, out)
+		if lastErr == nil {
+			return nil
+		}
+		var apiErr *APIError
+		if errors.As(lastErr, &apiErr) && apiErr.StatusCode != http.StatusTooManyRequests && apiErr.StatusCode < 500 {
+			return lastErr
+		}
+		if ctx.Err() != nil {
+			return ctx.Err()
+		}
+	}
+	return fmt.Errorf("moadian request failed after %d attempts: %w", c.maxRetries+1, lastErr)
+}
+
+func (c *HTTPClient) do(ctx context.Context, path string, payload []byte, out interface{}) error {
+	nonce, err := c.nonce(ctx)
+	if err != nil {
+		return err
+	}
+	signature, err := c.signer.Sign(nonce + "#" + c.clientID)
+	if err != nil {
+		return err
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, c.baseURL+path, bytes.NewReader(payload))
+	if err != nil {
+		return fmt.Errorf("failed to build moadian request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set(HeaderClientID, c.clientID)
+	req.Header.Set(HeaderNonce, nonce)
+	req.Header.Set(HeaderSignature, signature)
+	return c.send(req, out)
+}
+
+func (c *HTTPClient) nonce(ctx context.Context) (string, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, c.baseURL+"/nonce", nil)
+	if err != nil {
+		return "", fmt.Errorf("failed to build nonce request: %w", err)
+	}
+	var resp struct {
+		Nonce string `json:"nonce"`
+	}
+	if err := c.send(req, &resp); err != nil {
+		return "", err
+	}
+	if resp.Nonce == "" {
+		return "", errors.New("moadian returned an empty nonce")
+	}
+	return resp.Nonce, nil
+}
+
+func (c *HTTPClient) send(req *http.Request, out interface{}) error {
+	res, err := c.http.Do(req)
+	if err != nil {
+		return fmt.Errorf("moadian request to %s failed: %w", req.URL.Path, err)
+	}
+	defer res.Body.Close()
+	body, err := io.ReadAll(io.LimitReader(res.Body, 1<<20))
+	if err != nil {
+		return fmt.Errorf("failed to read moadian response: %w", err)
+	}
+	if res.StatusCode >= 300 {
+		return &APIError{StatusCode: res.StatusCode, Body: strings.TrimSpace(string(body))}
+	}
+	if err := json.Unmarshal(body, out); err != nil {
+		return fmt.Errorf("failed to decode moadian response: %w", err)
+	}
+	return nil
+}
+
+// ClientFactory برای هر کسب‌وکار با شناسه حافظه و کلید خودش یک Client می‌سازد؛ در آزمون می‌توان پیاده‌سازی دیگری داد.
+type ClientFactory func(memoryID string, signer *Signer) (Client, error)
+
+// NewHTTPClientFactory کارخانه پیش‌فرض که به baseURL (سامانه واقعی یا شبیه‌ساز محلی) وصل می‌شود.
+func NewHTTPClientFactory(baseURL string, maxRetries int, retryDelay time.Duration) ClientFactory {
+	return func(memoryID string, signer *Signer) (Client, error) {
+		return NewHTTPClient(HTTPConfig{
+			BaseURL:    baseURL,
+			ClientID:   memoryID,
+			Signer:     signer,
+			MaxRetries: maxRetries,
+			RetryDelay: retryDelay,
+		})
+	}
+}
@@ -0,0 +1,123 @@
+package moadian
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/rsa"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+const testMemoryID = "A1B2C3"
+
+func newTestClient(t *testing.T, opts MockOptions, signer *Signer) *HTTPClient {
+	t.Helper()
+	srv := httptest.NewServer(NewMockServer(opts))
+	t.Cleanup(srv.Close)
+	client, err := NewHTTPClient(HTTPConfig{BaseURL: srv.URL, ClientID: testMemoryID, Signer: signer, MaxRetries: 2, RetryDelay: time.Millisecond})
+	if err != nil {
+		t.Fatal(err)
+	}
+	return client
+}
+
+func testSigner(t *testing.T) *Signer {
+	t.Helper()
+	signer, err := NewSigner(pkcs1PEM(testKey))
+	if err != nil {
+		t.Fatal(err)
+	}
+	return signer
+}
+
+// testPacket صورتحساب الگوی طلا با یک سطر و جمع‌های درست
+func testPacket(t *testing.T, signer *Signer, serial uint64) Packet {
+	t.Helper()
+	taxID, err := GenerateTaxID(testMemoryID, time.Now(), serial)
+	if err != nil {
+		t.Fatal(err)
+	}
+	inv := Invoice{
+		Header: Header{TaxID: taxID, Type: InvoiceTypeWithBuyer, Pattern: PatternGold, Subject: SubjectOriginal, SellerTIN: "14001234567", Settlement: SettlementCash},
+		Body:   []Item{GoldItem("2710000138624", "164", "ring", 2.5, 40_000_000, 5_000_000, 2_000_000, 0, 10, 700_000)},
+	}
+	inv.Totalize()
+	signature, err := signer.SignData(inv)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return Packet{UID: taxID, PacketType: PacketTypeInvoice, FiscalID: testMemoryID, Data: inv, DataSignature: signature}
+}
+
+func sendAndInquire(t *testing.T, client *HTTPClient, packet Packet) InquiryResult {
+	t.Helper()
+	ctx := context.Background()
+	results, err := client.SendInvoices(ctx, []Packet{packet})
+	if err != nil {
+		t.Fatalf("SendInvoices: %v", err)
+	}
+	if len(results) != 1 || results[0].ReferenceNumber == "" || results[0].ErrorCode != "" {
+		t.Fatalf("send results = %+v, want one reference number", results)
+	}
+	inquiry, err := client.InquiryByReference(ctx, []string{results[0].ReferenceNumber})
+	if err != nil {
+		t.Fatalf("InquiryByReference: %v", err)
+	}
+	if len(inquiry) != 1 {
+		t.Fatalf("inquiry results = %+v, want one", inquiry)
+	}
+	return inquiry[0]
+}
+
+func TestHTTPClientSendAndInquire(t *testing.T) {
+	signer := testSigner(t)
+	opts := MockOptions{PublicKeys: map[string]*rsa.PublicKey{testMemoryID: signer.PublicKey()}, RequireKnownClient: true}
+	res := sendAndInquire(t, newTestClient(t, opts, signer), testPacket(t, signer, 1))
+	if res.Status != StatusSuccess || len(res.Errors) != 0 {
+		t.Errorf("inquiry = %+v, want SUCCESS without errors", res)
+	}
+}
+
+func TestHTTPClientReportsInvalidPacket(t *testing.T) {
+	signer := testSigner(t)
+	opts := MockOptions{PublicKeys: map[string]*rsa.PublicKey{testMemoryID: signer.PublicKey()}}
+	packet := testPacket(t, signer, 2)
+	packet.Data.Header.TotalBill++
+	res := sendAndInquire(t, newTestClient(t, opts, signer), packet)
+	if res.Status != StatusFailed {
+		t.Fatalf("inquiry status = %s, want FAILED", res.Status)
+	}
+	codes := map[string]bool{}
+	for _, m := range res.Errors {
+		codes[m.Code] = true
+	}
+	// امضا روی جمع اصلی گرفته شده است، پس تغییر جمع هر دو خطا را می‌دهد.
+	if !codes["TOTAL_MISMATCH"] || !codes["INVALID_SIGNATURE"] {
+		t.Errorf("errors = %+v, want TOTAL_MISMATCH and INVALID_SIGNATURE", res.Errors)
+	}
+}
+
+func TestHTTPClientRetriesUnavailable(t *testing.T) {
+	signer := testSigner(t)
+	res := sendAndInquire(t, newTestClient(t, MockOptions{FailFirst: 2}, signer), testPacket(t, signer, 3))
+	if res.Status != StatusSuccess {
+		t.Errorf("inquiry status = %s, want SUCCESS after retries", res.Status)
+	}
+}
+
+func TestHTTPClientRejectedSignature(t *testing.T) {
+	signer := testSigner(t)
+	other, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatal(err)
+	}
+	opts := MockOptions{PublicKeys: map[string]*rsa.PublicKey{testMemoryID: &other.PublicKey}}
+	_, err = newTestClient(t, opts, signer).SendInvoices(context.Background(), []Packet{testPacket(t, signer, 4)})
+	var apiErr *APIError
+	if !errors.As(err, &apiErr) || apiErr.StatusCode != http.StatusUnauthorized {
+		t.Errorf("err = %v, want 401 APIError without retries", err)
+	}
+}
@@ -0,0 +1,131 @@
+package moadian
+
+// الگو و موضوع صورتحساب در سامانه مودیان
+const (
+	PatternGold = 3
+
+	SubjectOriginal   = 1
+	SubjectCorrection = 2
+	SubjectCancel     = 3
+	SubjectReturn     = 4
+
+	// نوع اول: صورتحساب با مشخصات خریدار
+	InvoiceTypeWithBuyer = 1
+
+	BuyerNatural = 1
+	BuyerLegal   = 2
+
+	SettlementCash   = 1
+	SettlementCredit = 2
+)
+
+const PacketTypeInvoice = "INVOICE.V01"
+
+// Invoice صورتحساب الکترونیکی با نام فیلدهای سامانه مودیان؛ همه مبالغ به ریال هستند.
+type Invoice struct {
+	Header   Header    `json:"header"`
+	Body     []Item    `json:"body"`
+	Payments []Payment `json:"payments"`
+}
+
+type Header struct {
+	TaxID        string `json:"taxid"`
+	IssuedAt     int64  `json:"indatim"`
+	CreatedAt    int64  `json:"indati2m"`
+	Type         int    `json:"inty"`
+	Serial       string `json:"inno"`
+	ReferenceTax string `json:"irtaxid,omitempty"`
+	Pattern      int    `json:"inp"`
+	Subject      int    `json:"ins"`
+	SellerTIN    string `json:"tins"`
+	BuyerType    int    `json:"tob,omitempty"`
+	BuyerID      string `json:"bid,omitempty"`
+	BuyerTIN     string `json:"tinb,omitempty"`
+	BuyerPostal  string `json:"bpc,omitempty"`
+	TotalPre     int64  `json:"tprdis"`
+	TotalDisc    int64  `json:"tdis"`
+	TotalAfter   int64  `json:"tadis"`
+	TotalVAT     int64  `json:"tvam"`
+	TotalOther   int64  `json:"todam"`
+	TotalBill    int64  `json:"tbill"`
+	Settlement   int    `json:"setm"`
+	CashPaid     int64  `json:"cap"`
+	CreditAmount int64  `json:"insp"`
+}
+
+// Item سطر صورتحساب الگوی طلا؛ اجرت، سود فروشنده و حق‌العمل جدا از ارزش طلا گزارش می‌شوند.
+type Item struct {
+	ProductID   string  `json:"sstid"`
+	Description string  `json:"sstt"`
+	Quantity    float64 `json:"am"`
+	Unit        string  `json:"mu"`
+	UnitPrice   int64   `json:"fee"`
+	Currency    string  `json:"cut,omitempty"`
+	PreDiscount int64   `json:"prdis"`
+	Discount    int64   `json:"dis"`
+	AfterDisc   int64   `json:"adis"`
+	VATRate     float64 `json:"vra"`
+	VATAmount   int64   `json:"vam"`
+	LaborFee    int64   `json:"consfee"`
+	Profit      int64   `json:"spro"`
+	Commission  int64   `json:"bros"`
+	ValueAdded  int64   `json:"tcpbs"`
+	Total       int64   `json:"tsstam"`
+}
+
+type Payment struct {
+	Method int   `json:"pmt"`
+	Amount int64 `json:"pv"`
+	PaidAt int64 `json:"pdt"`
+}
+
+// GoldItem سطر الگوی طلا را از مبالغ ریالی می‌سازد: مبلغ قبل از تخفیف = وزن × نرخ + اجرت + سود + حق‌العمل.
+// مالیات (vat) از بیرون و طبق قاعده مالیاتی کسب‌وکار داده می‌شود.
+func GoldItem(productID, unit, description string, weight float64, rate, laborFee, profit, commission int64, vatRate float64, vat int64) Item {
+	gold := roundRial(weight * float64(rate))
+	valueAdded := laborFee + profit + commission
+	pre := gold + valueAdded
+	return Item{
+		ProductID:   productID,
+		Description: description,
+		Quantity:    weight,
+		Unit:        unit,
+		UnitPrice:   rate,
+		Currency:    "IRR",
+		PreDiscount: pre,
+		AfterDisc:   pre,
+		VATRate:     vatRate,
+		VATAmount:   vat,
+		LaborFee:    laborFee,
+		Profit:      profit,
+		Commission:  commission,
+		ValueAdded:  valueAdded,
+		Total:       pre + vat,
+	}
+}
+
+// Totalize جمع‌های سربرگ را از سطرها محاسبه می‌کند.
+func (inv *Invoice) Totalize() {
+	h := &inv.Header
+	h.TotalPre, h.TotalDisc, h.TotalAfter, h.TotalVAT = 0, 0, 0, 0
+	for _, it := range inv.Body {
+		h.TotalPre += it.PreDiscount
+		h.TotalDisc += it.Discount
+		h.TotalAfter += it.AfterDisc
+		h.TotalVAT += it.VATAmount
+	}
+	h.TotalBill = h.TotalAfter + h.TotalVAT + h.TotalOther
+	h.CashPaid, h.CreditAmount = 0, 0
+	if h.Settlement == SettlementCredit {
+		h.CreditAmount = h.TotalBill
+	} else {
+		h.CashPaid = h.TotalBill
+	}
+}
+
+func roundRial(v float64) int64 {
+	if v < 0 {
+		return -int64(-v + 0.5)
+	}
+	return int64(v + 0.5)
+}
@@ -0,0 +1,238 @@
+package moadian
+
+import (
+	"crypto/rand"
+	"crypto/rsa"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+)
+
+// MockOptions رفتار شبیه‌ساز سامانه مودیان
+type MockOptions struct {
+	// PublicKeys کلید عمومی هر شناسه حافظه؛ در صورت وجود، امضای درخواست و بسته با آن بررسی می‌شود.
+	PublicKeys map[string]*rsa.PublicKey
+	// RequireKnownClient درخواست شناسه‌هایی که کلید عمومی ندارند را رد می‌کند.
+	RequireKnownClient bool
+	// PendingPolls تعداد استعلام‌هایی که صورتحساب پیش از نهایی شدن در وضعیت PENDING می‌ماند.
+	PendingPolls int
+	// FailFirst تعداد درخواست‌های اولیه که با 503 پاسخ داده می‌شوند (برای آزمون تکرار).
+	FailFirst int
+}
+
+type mockRecord struct {
+	uid      string
+	taxID    string
+	polls    int
+	errors   []Message
+	warnings []Message
+}
+
+// MockServer شبیه‌ساز درون‌حافظه‌ای API مودیان برای اجرای محلی و آزمون؛ شماره مالیاتی، امضا و جمع‌ها را اعتبارسنجی می‌کند.
+type MockServer struct {
+	opts    MockOptions
+	mu      sync.Mutex
+	nonces  map[string]time.Time
+	records map[string]*mockRecord
+	taxIDs  map[string]string
+	failed  int
+}
+
+func NewMockServer(opts MockOptions) *MockServer {
+	if opts.PublicKeys == nil {
+		opts.PublicKeys = map[string]*rsa.PublicKey{}
+	}
+	return &MockServer{
+		opts:    opts,
+		nonces:  map[string]time.Time{},
+		records: map[string]*mockRecord{},
+		taxIDs:  map[string]string{},
+	}
+}
+
+func (m *MockServer) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if m.shouldFail() {
+		writeMockJSON(w, http.StatusServiceUnavailable, map[string]string{"error": "service temporarily unavailable"})
+		return
+	}
+	switch {
+	case r.Method == http.MethodGet && r.URL.Path == "/nonce":
+		m.handleNonce(w)
+	case r.Method == http.MethodPost && r.URL.Path == "/invoice":
+		m.handleInvoice(w, r)
+	case r.Method == http.MethodPost && r.URL.Path == "/inquiry-by-reference-number":
+		m.handleInquiry(w, r)
+	default:
+		writeMockJSON(w, http.StatusNotFound, map[string]string{"error": "not found"})
+	}
+}
+
+func (m *MockServer) shouldFail() bool {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if m.failed < m.opts.FailFirst {
+		m.failed++
+		return true
+	}
+	return false
+}
+
+func (m *MockServer) handleNonce(w http.ResponseWriter) {
+	nonce := randomHex(16)
+	m.mu.Lock()
+	now := time.Now()
+	for n, exp := range m.nonces {
+		if now.After(exp) {
+			delete(m.nonces, n)
+		}
+	}
+	m.nonces[nonce] = now.Add(5 * time.Minute)
+	m.mu.Unlock()
+	writeMockJSON(w, http.StatusOK, map[string]string{"nonce": nonce})
+}
+
+// authenticate nonce یک‌بارمصرف است و امضای nonce#clientId در صورت وجود کلید عمومی بررسی می‌شود.
+func (m *MockServer) authenticate(r *http.Request) (string, *rsa.PublicKey, error) {
+	clientID := r.Header.Get(HeaderClientID)
+	nonce := r.Header.Get(HeaderNonce)
+	if clientID == "" || nonce == "" {
+		return "", nil, fmt.Errorf("missing %s or %s header", HeaderClientID, HeaderNonce)
+	}
+	m.mu.Lock()
+	exp, ok := m.nonces[nonce]
+	delete(m.nonces, nonce)
+	pub := m.opts.PublicKeys[clientID]
+	m.mu.Unlock()
+	if !ok || time.Now().After(exp) {
+		return "", nil, fmt.Errorf("nonce is unknown or expired")
+	}
+	if pub == nil {
+		if m.opts.RequireKnownClient {
+			return "", nil, fmt.Errorf("client %s is not registered", clientID)
+		}
+		return clientID, nil, nil
+	}
+	if err := Verify(pub, nonce+"#"+clientID, r.Header.Get(HeaderSignature)); err != nil {
+		return "", nil, fmt.Errorf("invalid request signature: %v", err)
+	}
+	return clientID, pub, nil
+}
+
+func (m *MockServer) handleInvoice(w http.ResponseWriter, r *http.Request) {
+	clientID, pub, err := m.authenticate(r)
+	if err != nil {
+		writeMockJSON(w, http.StatusUnauthorized, map[string]string{"error": err.Error()})
+		return
+	}
+	var req struct {
+		Packets []Packet `json:"packets"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeMockJSON(w, http.StatusBadRequest, map[string]string{"error": "invalid request body: " + err.Error()})
+		return
+	}
+	results := make([]SubmitResult, 0, len(req.Packets))
+	for _, p := range req.Packets {
+		if p.UID == "" || p.PacketType != PacketTypeInvoice {
+			results = append(results, SubmitResult{UID: p.UID, ErrorCode: "INVALID_PACKET", ErrorDetail: "uid and packetType INVOICE.V01 are required"})
+			continue
+		}
+		rec := &mockRecord{uid: p.UID, taxID: p.Data.Header.TaxID, errors: validateMockPacket(clientID, pub, p)}
+		ref := randomHex(16)
+		m.mu.Lock()
+		if prev, ok := m.taxIDs[rec.taxID]; ok && !p.Retry && len(rec.errors) == 0 {
+			if prevRec := m.records[prev]; prevRec != nil && len(prevRec.errors) == 0 {
+				rec.errors = append(rec.errors, Message{Code: "DUPLICATE_TAXID", Message: "tax id was already submitted"})
+			}
+		}
+		m.records[ref] = rec
+		if len(rec.errors) == 0 {
+			m.taxIDs[rec.taxID] = ref
+		}
+		m.mu.Unlock()
+		results = append(results, SubmitResult{UID: p.UID, ReferenceNumber: ref})
+	}
+	writeMockJSON(w, http.StatusOK, map[string]interface{}{"result": results})
+}
+
+func validateMockPacket(clientID string, pub *rsa.PublicKey, p Packet) []Message {
+	var errs []Message
+	h := p.Data.Header
+	if !ValidTaxID(h.TaxID) {
+		errs = append(errs, Message{Code: "INVALID_TAXID", Message: "tax id format or check digit is invalid"})
+	} else if !strings.EqualFold(h.TaxID[:memoryIDLength], clientID) || !strings.EqualFold(p.FiscalID, clientID) {
+		errs = append(errs, Message{Code: "MEMORY_ID_MISMATCH", Message: "tax id does not belong to the requesting memory id"})
+	}
+	if h.Subject != SubjectOriginal && h.ReferenceTax == "" {
+		errs = append(errs, Message{Code: "MISSING_REFERENCE", Message: "irtaxid is required for correction, cancel and return invoices"})
+	}
+	if h.SellerTIN == "" {
+		errs = append(errs, Message{Code: "MISSING_SELLER", Message: "seller economic code (tins) is required"})
+	}
+	if len(p.Data.Body) == 0 {
+		errs = append(errs, Message{Code: "EMPTY_BODY", Message: "invoice has no items"})
+	}
+	check := Invoice{Header: h, Body: p.Data.Body}
+	check.Totalize()
+	if check.Header.TotalBill != h.TotalBill || check.Header.TotalVAT != h.TotalVAT {
+		errs = append(errs, Message{Code: "TOTAL_MISMATCH", Message: "header totals do not match item totals"})
+	}
+	if pub != nil {
+		normalized, err := Normalize(p.Data)
+		if err != nil || Verify(pub, normalized, p.DataSignature) != nil {
+			errs = append(errs, Message{Code: "INVALID_SIGNATURE", Message: "packet data signature is invalid"})
+		}
+	}
+	return errs
+}
+
+func (m *MockServer) handleInquiry(w http.ResponseWriter, r *http.Request) {
+	if _, _, err := m.authenticate(r); err != nil {
+		writeMockJSON(w, http.StatusUnauthorized, map[string]string{"error": err.Error()})
+		return
+	}
+	var req struct {
+		ReferenceNumbers []string `json:"referenceNumbers"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeMockJSON(w, http.StatusBadRequest, map[string]string{"error": "invalid request body: " + err.Error()})
+		return
+	}
+	results := make([]InquiryResult, 0, len(req.ReferenceNumbers))
+	m.mu.Lock()
+	for _, ref := range req.ReferenceNumbers {
+		rec, ok := m.records[ref]
+		if !ok {
+			results = append(results, InquiryResult{ReferenceNumber: ref, Status: StatusNotFound})
+			continue
+		}
+		res := InquiryResult{ReferenceNumber: ref, UID: rec.uid, Status: StatusPending}
+		if rec.polls >= m.opts.PendingPolls {
+			if len(rec.errors) > 0 {
+				res.Status, res.Errors = StatusFailed, rec.errors
+			} else {
+				res.Status, res.Warnings = StatusSuccess, rec.warnings
+			}
+		}
+		rec.polls++
+		results = append(results, res)
+	}
+	m.mu.Unlock()
+	writeMockJSON(w, http.StatusOK, map[string]interface{}{"result": results})
+}
+
+func writeMockJSON(w http.ResponseWriter, status int, body interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	_ = json.NewEncoder(w).Encode(body)
+}
+
+func randomHex(n int) string {
+	b := make([]byte, n)
+	_, _ = rand.Read(b)
+	return hex.EncodeToString(b)
+}
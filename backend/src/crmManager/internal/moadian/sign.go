@@ -0,0 +1,162 @@
+package moadian
+
+import (
+	"crypto"
+	"crypto/rsa"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/json"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// Signer بسته‌ها و درخواست‌ها را با کلید خصوصی کسب‌وکار (RSA، SHA-256) امضا می‌کند.
+type Signer struct {
+	key *rsa.PrivateKey
+}
+
+// NewSigner کلید خصوصی PEM با قالب PKCS#1 یا PKCS#8 را می‌پذیرد.
+func NewSigner(privateKeyPEM string) (*Signer, error) {
+	key, err := ParsePrivateKey(privateKeyPEM)
+	if err != nil {
+		return nil, err
+	}
+	return &Signer{key: key}, nil
+}
+
+func ParsePrivateKey(privateKeyPEM string) (*rsa.PrivateKey, error) {
+	block, _ := pem.Decode([]byte(strings.TrimSpace(privateKeyPEM)))
+	if block == nil {
+		return nil, errors.New("private key is not valid PEM")
+	}
+	if key, err := x509.ParsePKCS1PrivateKey(block.Bytes); err == nil {
+		return key, nil
+	}
+	parsed, err := x509.ParsePKCS8PrivateKey(block.Bytes)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse private key: %w", err)
+	}
+	key, ok := parsed.(*rsa.PrivateKey)
+	if !ok {
+		return nil, errors.New("private key must be an RSA key")
+	}
+	return key, nil
+}
+
+func ParsePublicKey(publicKeyPEM string) (*rsa.PublicKey, error) {
+	block, _ := pem.Decode([]byte(strings.TrimSpace(publicKeyPEM)))
+	if block == nil {
+		return nil, errors.New("public key is not valid PEM")
+	}
+	if key, err := x509.ParsePKCS1PublicKey(block.Bytes); err == nil {
+		return key, nil
+	}
+	parsed, err := x509.ParsePKIXPublicKey(block.Bytes)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse public key: %w", err)
+	}
+	key, ok := parsed.(*rsa.PublicKey)
+	if !ok {
+		return nil, errors.New("public key must be an RSA key")
+	}
+	return key, nil
+}
+
+// Sign امضای base64 متن داده‌شده
+func (s *Signer) Sign(text string) (string, error) {
+	digest := sha256.Sum256([]byte(text))
+	sig, err := rsa.SignPKCS1v15(nil, s.key, crypto.SHA256, digest[:])
+	if err != nil {
+		return "", fmt.Errorf("failed to sign: %w", err)
+	}
+	return base64.StdEncoding.EncodeToString(sig), nil
+}
+
+// SignData صورت نرمال‌شده داده را امضا می‌کند.
+func (s *Signer) SignData(data interface{}) (string, error) {
+	normalized, err := Normalize(data)
+	if err != nil {
+		return "", err
+	}
+	return s.Sign(normalized)
+}
+
+func (s *Signer) PublicKey() *rsa.PublicKey {
+	return &s.key.PublicKey
+}
+
+func Verify(pub *rsa.PublicKey, text, signature string) error {
+	sig, err := base64.StdEncoding.DecodeString(signature)
+	if err != nil {
+		return fmt.Errorf("signature is not valid base64: %w", err)
+	}
+	digest := sha256.Sum256([]byte(text))
+	return rsa.VerifyPKCS1v15(pub, crypto.SHA256, digest[:], sig)
+}
+
+// Normalize داده را مانند SDK سامانه مودیان به یک رشته یکتا تبدیل می‌کند: ساختار JSON تخت می‌شود (کلیدهای تو در تو با نقطه
+// و اندیس آرایه با E)، کلیدها مرتب و مقدارها با # به هم وصل می‌شوند؛ مقدار خالی # و کاراکتر # درون مقدار ## می‌شود.
+func Normalize(data interface{}) (string, error) {
+	raw, err := json.Marshal(data)
+	if err != nil {
+		return "", fmt.Errorf("failed to marshal data for normalization: %w", err)
+	}
+	var tree interface{}
+	dec := json.NewDecoder(strings.NewReader(string(raw)))
+	dec.UseNumber()
+	if err := dec.Decode(&tree); err != nil {
+		return "", fmt.Errorf("failed to decode data for normalization: %w", err)
+	}
+	flat := map[string]string{}
+	flatten("", tree, flat)
+	keys := make([]string, 0, len(flat))
+	for k := range flat {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	values := make([]string, len(keys))
+	for i, k := range keys {
+		v := flat[k]
+		if v == "" {
+			v = "#"
+		} else {
+			v = strings.ReplaceAll(v, "#", "##")
+		}
+		values[i] = v
+	}
+	return strings.Join(values, "#"), nil
+}
+
+func flatten(prefix string, node interface{}, out map[string]string) {
+	join := func(key string) string {
+		if prefix == "" {
+			return key
+		}
+		return prefix + "." + key
+	}
+	switch v := node.(type) {
+	case map[string]interface{}:
+		for k, child := range v {
+			flatten(join(k), child, out)
+		}
+	case []interface{}:
+		for i, child := range v {
+			flatten(join("E"+strconv.Itoa(i)), child, out)
+		}
+	case nil:
+		out[prefix] = ""
+	case string:
+		out[prefix] = v
+	case json.Number:
+		out[prefix] = v.String()
+	case bool:
+		out[prefix] = strconv.FormatBool(v)
+	default:
+		out[prefix] = fmt.Sprint(v)
+	}
+}
@@ -0,0 +1,113 @@
+package moadian
+
+import (
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/pem"
+	"testing"
+)
+
+// testKey کلید RSA آزمون؛ ساخت کلید کند است و یک بار برای همه آزمون‌های بسته انجام می‌شود.
+var testKey = func() *rsa.PrivateKey {
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		panic(err)
+	}
+	return key
+}()
+
+func pkcs1PEM(key *rsa.PrivateKey) string {
+	return string(pem.EncodeToMemory(&pem.Block{Type: "RSA PRIVATE KEY", Bytes: x509.MarshalPKCS1PrivateKey(key)}))
+}
+
+func pkcs8PEM(t *testing.T, key *rsa.PrivateKey) string {
+	t.Helper()
+	der, err := x509.MarshalPKCS8PrivateKey(key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return string(pem.EncodeToMemory(&pem.Block{Type: "PRIVATE KEY", Bytes: der}))
+}
+
+func TestNormalize(t *testing.T) {
+	tests := []struct {
+		name string
+		data interface{}
+		want string
+	}{
+		{
+			name: "keys sorted and nested keys joined with dots",
+			data: map[string]interface{}{"b": map[string]interface{}{"y": 2, "x": 1}, "a": "first"},
+			want: "first#1#2",
+		},
+		{
+			name: "array items indexed with E",
+			data: map[string]interface{}{"items": []interface{}{"p", "q"}, "itemsZ": "r"},
+			want: "p#q#r",
+		},
+		{
+			name: "empty and null values become #",
+			data: map[string]interface{}{"a": "", "b": nil, "c": true},
+			want: "####true",
+		},
+		{
+			name: "# inside a value is doubled",
+			data: map[string]interface{}{"a": "x#y", "b": 1.5},
+			want: "x##y#1.5",
+		},
+		{
+			name: "large numbers keep their digits",
+			data: map[string]interface{}{"tbill": int64(123456789012345)},
+			want: "123456789012345",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := Normalize(tt.data)
+			if err != nil {
+				t.Fatalf("Normalize: %v", err)
+			}
+			if got != tt.want {
+				t.Errorf("Normalize = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestSignerSignAndVerify(t *testing.T) {
+	for name, keyPEM := range map[string]string{"pkcs1": pkcs1PEM(testKey), "pkcs8": pkcs8PEM(t, testKey)} {
+		t.Run(name, func(t *testing.T) {
+			signer, err := NewSigner(keyPEM)
+			if err != nil {
+				t.Fatalf("NewSigner: %v", err)
+			}
+			data := map[string]interface{}{"taxid": "A1B2C3", "tbill": 1000}
+			signature, err := signer.SignData(data)
+			if err != nil {
+				t.Fatalf("SignData: %v", err)
+			}
+			normalized, _ := Normalize(data)
+			if err := Verify(signer.PublicKey(), normalized, signature); err != nil {
+				t.Errorf("Verify: %v", err)
+			}
+			if err := Verify(signer.PublicKey(), normalized+"#", signature); err == nil {
+				t.Error("signature verified for different data")
+			}
+		})
+	}
+}
+
+func TestNewSignerRejects(t *testing.T) {
+	for name, keyPEM := range map[string]string{
+		"empty":       "",
+		"not pem":     "not a key",
+		"garbage der": string(pem.EncodeToMemory(&pem.Block{Type: "PRIVATE KEY", Bytes: []byte("garbage")})),
+	} {
+		t.Run(name, func(t *testing.T) {
+			if _, err := NewSigner(keyPEM); err == nil {
+				t.Error("NewSigner accepted an invalid key")
+			}
+		})
+	}
+}
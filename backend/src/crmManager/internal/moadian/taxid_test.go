@@ -0,0 +1,92 @@
+package moadian
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestVerhoeffCheckDigit(t *testing.T) {
+	tests := []struct {
+		digits string
+		want   int
+	}{
+		{"236", 3},
+		{"12345", 1},
+		{"142857", 0},
+	}
+	for _, tt := range tests {
+		got, err := VerhoeffCheckDigit(tt.digits)
+		if err != nil {
+			t.Fatalf("VerhoeffCheckDigit(%q): %v", tt.digits, err)
+		}
+		if got != tt.want {
+			t.Errorf("VerhoeffCheckDigit(%q) = %d, want %d", tt.digits, got, tt.want)
+		}
+	}
+	if _, err := VerhoeffCheckDigit("12a"); err == nil {
+		t.Error("non-digit input was accepted")
+	}
+}
+
+func TestGenerateTaxID(t *testing.T) {
+	// روز 0x4D5A از ۱۹۷۰ و سریال 0xFF
+	date := time.Unix(0x4D5A*86400+3600, 0)
+	taxID, err := GenerateTaxID(" a1b2c3 ", date, 0xFF)
+	if err != nil {
+		t.Fatalf("GenerateTaxID: %v", err)
+	}
+	if len(taxID) != TaxIDLength {
+		t.Fatalf("tax id %q has length %d, want %d", taxID, len(taxID), TaxIDLength)
+	}
+	if want := "A1B2C304D5A00000000FF"; !strings.HasPrefix(taxID, want) {
+		t.Errorf("tax id = %s, want prefix %s", taxID, want)
+	}
+	// رقم کنترلی روی صورت دهدهی: A=65، B=66، C=67، سپس روزها با ۶ رقم و سریال با ۱۲ رقم
+	check, err := VerhoeffCheckDigit("651662673" + "019802" + "000000000255")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := taxID[21:]; got != string(rune('0'+check)) {
+		t.Errorf("check digit = %s, want %d", got, check)
+	}
+	if !ValidTaxID(taxID) {
+		t.Errorf("ValidTaxID(%s) = false", taxID)
+	}
+}
+
+func TestGenerateTaxIDRejects(t *testing.T) {
+	date := time.Date(2024, 3, 20, 0, 0, 0, 0, time.UTC)
+	tests := []struct {
+		name     string
+		memoryID string
+		date     time.Time
+		serial   uint64
+	}{
+		{"short memory id", "A1B2C", date, 1},
+		{"invalid character", "A1B2C-", date, 1},
+		{"zero serial", "A1B2C3", date, 0},
+		{"serial overflow", "A1B2C3", date, maxSerial + 1},
+		{"date before 1970", "A1B2C3", time.Date(1969, 1, 1, 0, 0, 0, 0, time.UTC), 1},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if id, err := GenerateTaxID(tt.memoryID, tt.date, tt.serial); err == nil {
+				t.Errorf("GenerateTaxID = %s, want error", id)
+			}
+		})
+	}
+}
+
+func TestValidTaxIDRejects(t *testing.T) {
+	taxID, err := GenerateTaxID("A1B2C3", time.Date(2024, 3, 20, 0, 0, 0, 0, time.UTC), 42)
+	if err != nil {
+		t.Fatal(err)
+	}
+	wrongCheck := taxID[:21] + string(rune('0'+(taxID[21]-'0'+1)%10))
+	for _, id := range []string{wrongCheck, taxID[:21], taxID[:11] + "0000000000" + taxID[21:], taxID[:6] + "XXXXX" + taxID[11:]} {
+		if ValidTaxID(id) {
+			t.Errorf("ValidTaxID(%s) = true, want false", id)
+		}
+	}
+}
@@ -0,0 +1,164 @@
+package model
+
+import (
+	"time"
+
+	"common-gold/money"
+
+	"gorm.io/gorm"
+)
+
+// سطوح کدینگ حساب‌ها: گروه (۱ رقم)، کل (۲ رقم) و معین (۴ رقم)؛ حساب‌های تفصیلی همان اشخاص، صندوق‌ها، بانک‌ها و مراکز هستند.
+const (
+	AccountLevelGroup      = "group"
+	AccountLevelGeneral    = "general"
+	AccountLevelSubsidiary = "subsidiary"
+)
+
+const (
+	AccountCategoryAsset     = "asset"
+	AccountCategoryLiability = "liability"
+	AccountCategoryEquity    = "equity"
+	AccountCategoryRevenue   = "revenue"
+	AccountCategoryExpense   = "expense"
+)
+
+const (
+	AccountNatureDebit  = "debit"
+	AccountNatureCredit = "credit"
+)
+
+// Account یک حساب در سرفصل حساب‌ها (کدینگ استاندارد)؛ Kind حساب معین را به نوع سطرهایی که اسناد سیستمی ثبت می‌کنند وصل می‌کند.
+type Account struct {
+	gorm.Model
+
+	BIDID    uint   `json:"bidId" gorm:"column:bid_id;not null;index;uniqueIndex:idx_account_code;uniqueIndex:idx_account_kind,where:kind <> ''"`
+	Code     string `json:"code" gorm:"not null;size:20;uniqueIndex:idx_account_code"`
+	Name     string `json:"name" gorm:"not null;size:255"`
+	Level    string `json:"level" gorm:"not null;size:20"`
+	Category string `json:"category" gorm:"not null;size:20;index"`
+	Nature   string `json:"nature" gorm:"not null;size:10"`
+	ParentID *uint  `json:"parentId,omitempty" gorm:"index"`
+	Kind     string `json:"kind,omitempty" gorm:"size:50;uniqueIndex:idx_account_kind"`
+	// نوع حساب تفصیلی لازم برای سطرهای این حساب (person، fund، bank، center)؛ خالی یعنی بدون تفصیلی
+	DetailType string `json:"detailType,omitempty" gorm:"size:20"`
+	IsSystem   bool   `json:"isSystem" gorm:"default:false"`
+	IsActive   bool   `json:"isActive" gorm:"default:true"`
+}
+
+const (
+	DetailTypePerson = "person"
+	DetailTypeFund   = "fund"
+	DetailTypeBank   = "bank"
+	DetailTypeCenter = "center"
+)
+
+// DefaultChartOfAccounts سرفصل حساب‌های پیش‌فرض هر کسب‌وکار؛ والد هر حساب با پیشوند کد آن مشخص می‌شود.
+var DefaultChartOfAccounts = []Account{
+	{Code: "1", Name: "دارایی‌ها", Level: AccountLevelGroup, Category: AccountCategoryAsset, Nature: AccountNatureDebit},
+	{Code: "11", Name: "دارایی‌های جاری", Level: AccountLevelGeneral, Category: AccountCategoryAsset, Nature: AccountNatureDebit},
+	{Code: "1101", Name: "موجودی صندوق", Level: AccountLevelSubsidiary, Category: AccountCategoryAsset, Nature: AccountNatureDebit, Kind: LedgerAccountFund, DetailType: DetailTypeFund},
+	{Code: "1102", Name: "موجودی نزد بانک‌ها", Level: AccountLevelSubsidiary, Category: AccountCategoryAsset, Nature: AccountNatureDebit, Kind: LedgerAccountBank, DetailType: DetailTypeBank},
+	{Code: "1103", Name: "حساب‌های دریافتنی و پرداختنی اشخاص", Level: AccountLevelSubsidiary, Category: AccountCategoryAsset, Nature: AccountNatureDebit, Kind: LedgerAccountPerson, DetailType: DetailTypePerson},
+	{Code: "1104", Name: "اسناد دریافتنی", Level: AccountLevelSubsidiary, Category: AccountCategoryAsset, Nature: AccountNatureDebit, Kind: LedgerAccountChequesReceivable},
+	{Code: "1105", Name: "اسناد در جریان وصول", Level: AccountLevelSubsidiary, Category: AccountCategoryAsset, Nature: AccountNatureDebit, Kind: LedgerAccountChequesCollection},
+	{Code: "1106", Name: "اسناد برگشتی", Level: AccountLevelSubsidiary, Category: AccountCategoryAsset, Nature: AccountNatureDebit, Kind: LedgerAccountChequesBounced},
+	{Code: "1107", Name: "موجودی کالا و طلا", Level: AccountLevelSubsidiary, Category: AccountCategoryAsset, Nature: AccountNatureDebit},
+	{Code: "2", Name: "بدهی‌ها", Level: AccountLevelGroup, Category: AccountCategoryLiability, Nature: AccountNatureCredit},
+	{Code: "21", Name: "بدهی‌های جاری", Level: AccountLevelGeneral, Category: AccountCategoryLiability, Nature: AccountNatureCredit},
+	{Code: "2101", Name: "اسناد پرداختنی", Level: AccountLevelSubsidiary, Category: AccountCategoryLiability, Nature: AccountNatureCredit, Kind: LedgerAccountChequesPayable},
+	{Code: "2102", Name: "مالیات بر ارزش افزوده پرداختنی", Level: AccountLevelSubsidiary, Category: AccountCategoryLiability, Nature: AccountNatureCredit},
+	{Code: "3", Name: "حقوق صاحبان سرمایه", Level: AccountLevelGroup, Category: AccountCategoryEquity, Nature: AccountNatureCredit},
+	{Code: "31", Name: "سرمایه", Level: AccountLevelGeneral, Category: AccountCategoryEquity, Nature: AccountNatureCredit},
+	{Code: "3101", Name: "سرمایه", Level: AccountLevelSubsidiary, Category: AccountCategoryEquity, Nature: AccountNatureCredit},
+	{Code: "3102", Name: "تراز افتتاحیه", Level: AccountLevelSubsidiary, Category: AccountCategoryEquity, Nature: AccountNatureCredit, Kind: LedgerAccountOpeningBalance},
+	{Code: "3103", Name: "سود (زیان) انباشته", Level: AccountLevelSubsidiary, Category: AccountCategoryEquity, Nature: AccountNatureCredit},
+	{Code: "4", Name: "درآمدها", Level: AccountLevelGroup, Category: AccountCategoryRevenue, Nature: AccountNatureCredit},
+	{Code: "41", Name: "درآمدهای عملیاتی", Level: AccountLevelGeneral, Category: AccountCategoryRevenue, Nature: AccountNatureCredit},
+	{Code: "4101", Name: "فروش", Level: AccountLevelSubsidiary, Category: AccountCategoryRevenue, Nature: AccountNatureCredit},
+	{Code: "4102", Name: "سایر درآمدها", Level: AccountLevelSubsidiary, Category: AccountCategoryRevenue, Nature: AccountNatureCredit, Kind: LedgerAccountIncome, DetailType: DetailTypeCenter},
+	{Code: "4103", Name: "درآمد اجرت ساخت", Level: AccountLevelSubsidiary, Category: AccountCategoryRevenue, Nature: AccountNatureCredit},
+	{Code: "5", Name: "هزینه‌ها", Level: AccountLevelGroup, Category: AccountCategoryExpense, Nature: AccountNatureDebit},
+	{Code: "51", Name: "هزینه‌های عملیاتی", Level: AccountLevelGeneral, Category: AccountCategoryExpense, Nature: AccountNatureDebit},
+	{Code: "5101", Name: "بهای تمام‌شده کالای فروش‌رفته", Level: AccountLevelSubsidiary, Category: AccountCategoryExpense, Nature: AccountNatureDebit},
+	{Code: "5102", Name: "هزینه‌های عمومی و اداری", Level: AccountLevelSubsidiary, Category: AccountCategoryExpense, Nature: AccountNatureDebit, Kind: LedgerAccountExpense, DetailType: DetailTypeCenter},
+	{Code: "5103", Name: "کارمزد بانکی", Level: AccountLevelSubsidiary, Category: AccountCategoryExpense, Nature: AccountNatureDebit, Kind: LedgerAccountBankFees},
+}
+
+type CreateAccountRequest struct {
+	BIDID      uint   `json:"bidId" validate:"required"`
+	ParentCode string `json:"parentCode" validate:"required"`
+	Code       string `json:"code" validate:"required"`
+	Name       string `json:"name" validate:"required"`
+	DetailType string `json:"detailType"`
+}
+
+type UpdateAccountRequest struct {
+	Name     *string `json:"name"`
+	IsActive *bool   `json:"isActive"`
+}
+
+// JournalLineRequest سطر سند دستی؛ DetailID برای حساب‌هایی که تفصیلی دارند الزامی است.
+type JournalLineRequest struct {
+	AccountCode string       `json:"accountCode" validate:"required"`
+	DetailID    *uint        `json:"detailId"`
+	Debit       money.Amount `json:"debit"`
+	Credit      money.Amount `json:"credit"`
+	GoldDebit   money.Weight `json:"goldDebit"`
+	GoldCredit  money.Weight `json:"goldCredit"`
+	Description string       `json:"description"`
+}
+
+type CreateJournalEntryRequest struct {
+	BIDID       uint                 `json:"bidId" validate:"required"`
+	Date        string               `json:"date" validate:"required"`
+	Description string               `json:"description" validate:"required"`
+	Lines       []JournalLineRequest `json:"lines" validate:"required"`
+}
+
+type ReverseJournalEntryRequest struct {
+	Reason string `json:"reason" validate:"required"`
+	Date   string `json:"date"`
+}
+
+type JournalFilter struct {
+	BIDID       uint
+	DocType     string
+	AccountCode string
+	From        *time.Time
+	To          *time.Time
+}
+
+// AccountLedgerLine سطر دفتر معین با گردش و مانده ریالی و وزنی (گرم طلا)
+type AccountLedgerLine struct {
+	EntryID     uint         `json:"entryId"`
+	Date        time.Time    `json:"date"`
+	DateJalali  string       `json:"dateJalali"`
+	DocType     string       `json:"docType"`
+	DocID       uint         `json:"docId"`
+	Reference   string       `json:"reference"`
+	Description string       `json:"description"`
+	DetailID    *uint        `json:"detailId,omitempty"`
+	Debit       money.Amount `json:"debit"`
+	Credit      money.Amount `json:"credit"`
+	GoldDebit   money.Weight `json:"goldDebit"`
+	GoldCredit  money.Weight `json:"goldCredit"`
+	Balance     money.Amount `json:"balance"`
+	GoldBalance money.Weight `json:"goldBalance"`
+}
+
+type AccountLedger struct {
+	Account         Account             `json:"account"`
+	DetailID        *uint               `json:"detailId,omitempty"`
+	From            string              `json:"from"`
+	To              string              `json:"to"`
+	OpeningBalance  money.Amount        `json:"openingBalance"`
+	OpeningGold     money.Weight        `json:"openingGold"`
+	TotalDebit      money.Amount        `json:"totalDebit"`
+	TotalCredit     money.Amount        `json:"totalCredit"`
+	TotalGoldDebit  money.Weight        `json:"totalGoldDebit"`
+	TotalGoldCredit money.Weight        `json:"totalGoldCredit"`
+	ClosingBalance  money.Amount        `json:"closingBalance"`
+	ClosingGold     money.Weight        `json:"closingGold"`
+	Lines           []AccountLedgerLine `json:"lines"`
+}
@@ -0,0 +1,127 @@
+package model
+
+import (
+	"encoding/json"
+	"time"
+
+	"common-gold/audit"
+)
+
+// AuditLog رویداد ثبت‌شده در دفتر رویداد؛ فقط درج می‌شود و هرگز ویرایش یا حذف نمی‌شود.
+// Hash هر رویداد از PrevHash (هش رویداد Seq-1) و فیلدهای خود رویداد ساخته می‌شود.
+// Before و After به صورت متن نگه داشته می‌شوند نه jsonb، چون jsonb ترتیب کلیدها را عوض می‌کند و هش دیگر قابل بازسازی نیست.
+type AuditLog struct {
+	ID            uint      `json:"id" gorm:"primaryKey"`
+	Seq           uint64    `json:"seq" gorm:"not null;uniqueIndex"`
+	BIDID         *uint     `json:"bidId,omitempty" gorm:"column:bid_id;index"`
+	Action        string    `json:"action" gorm:"not null;size:64;index"`
+	Category      string    `json:"category" gorm:"not null;size:32;index"`
+	ActorID       string    `json:"actorId" gorm:"size:64;index"`
+	ActorName     string    `json:"actorName" gorm:"size:100"`
+	IPAddress     string    `json:"ipAddress" gorm:"size:64"`
+	UserAgent     string    `json:"userAgent" gorm:"size:255"`
+	CorrelationID string    `json:"correlationId" gorm:"size:64;index"`
+	TargetType    string    `json:"targetType" gorm:"size:50;index:idx_audit_target"`
+	TargetID      string    `json:"targetId" gorm:"size:64;index:idx_audit_target"`
+	Description   string    `json:"description" gorm:"type:text"`
+	Before        string    `json:"-" gorm:"type:text"`
+	After         string    `json:"-" gorm:"type:text"`
+	OccurredAt    time.Time `json:"occurredAt" gorm:"not null;index"`
+	PrevHash      string    `json:"prevHash" gorm:"size:64"`
+	Hash          string    `json:"hash" gorm:"not null;size:64"`
+}
+
+// NewAuditLog رویداد را برای درج آماده می‌کند؛ Seq و هش‌ها را مخزن هنگام درج پر می‌کند.
+func NewAuditLog(e audit.Event) *AuditLog {
+	return &AuditLog{
+		BIDID:         e.BusinessID,
+		Action:        e.Action,
+		Category:      e.Category,
+		ActorID:       e.ActorID,
+		ActorName:     e.ActorName,
+		IPAddress:     e.IPAddress,
+		UserAgent:     truncateRunes(e.UserAgent, 255),
+		CorrelationID: e.CorrelationID,
+		TargetType:    e.TargetType,
+		TargetID:      e.TargetID,
+		Description:   e.Description,
+		Before:        string(e.Before),
+		After:         string(e.After),
+		OccurredAt:    e.OccurredAt.UTC().Truncate(time.Microsecond),
+	}
+}
+
+func (l *AuditLog) Event() audit.Event {
+	return audit.Event{
+		BusinessID:    l.BIDID,
+		Action:        l.Action,
+		Category:      l.Category,
+		ActorID:       l.ActorID,
+		ActorName:     l.ActorName,
+		IPAddress:     l.IPAddress,
+		UserAgent:     l.UserAgent,
+		CorrelationID: l.CorrelationID,
+		TargetType:    l.TargetType,
+		TargetID:      l.TargetID,
+		Description:   l.Description,
+		Before:        json.RawMessage(l.Before),
+		After:         json.RawMessage(l.After),
+		OccurredAt:    l.OccurredAt,
+	}
+}
+
+// MarshalJSON تصویرهای Before و After را به صورت JSON خام برمی‌گرداند نه رشته.
+func (l AuditLog) MarshalJSON() ([]byte, error) {
+	type plain AuditLog
+	return json.Marshal(struct {
+		plain
+		Before json.RawMessage `json:"before,omitempty"`
+		After  json.RawMessage `json:"after,omitempty"`
+	}{plain(l), rawOrNil(l.Before), rawOrNil(l.After)})
+}
+
+func truncateRunes(s string, n int) string {
+	if r := []rune(s); len(r) > n {
+		return string(r[:n])
+	}
+	return s
+}
+
+func rawOrNil(s string) json.RawMessage {
+	if s == "" {
+		return nil
+	}
+	return json.RawMessage(s)
+}
+
+// AuditFilter همه شرط‌ها با هم (AND) اعمال می‌شوند؛ Query در شرح، نام کاربر، IP و شناسه موضوع جستجو می‌کند.
+type AuditFilter struct {
+	BIDID         *uint
+	ActorID       string
+	Action        string
+	Category      string
+	TargetType    string
+	TargetID      string
+	CorrelationID string
+	Query         string
+	From          *time.Time
+	To            *time.Time
+	Page          int
+	PageSize      int
+}
+
+type AuditLogPage struct {
+	Items    []AuditLog `json:"items"`
+	Total    int64      `json:"total"`
+	Page     int        `json:"page"`
+	PageSize int        `json:"pageSize"`
+}
+
+// AuditChainReport نتیجه بررسی زنجیره؛ در صورت خرابی BrokenAtSeq اولین رویدادی است که هشش نمی‌خواند.
+type AuditChainReport struct {
+	Valid       bool    `json:"valid"`
+	Checked     int64   `json:"checked"`
+	LastSeq     uint64  `json:"lastSeq"`
+	BrokenAtSeq *uint64 `json:"brokenAtSeq,omitempty"`
+	Reason      string  `json:"reason,omitempty"`
+}
@@ -0,0 +1,184 @@
+package model
+
+import (
+	"time"
+
+	"common-gold/money"
+
+	"gorm.io/gorm"
+)
+
+// BankAccount حساب بانکی خود کسب‌وکار (حساب‌های بانکی اشخاص در CusCard نگهداری می‌شوند)
+type BankAccount struct {
+	gorm.Model
+
+	BIDID       uint    `json:"bidId" gorm:"column:bid_id;not null;index;uniqueIndex:idx_bank_account_num"`
+	Code        string  `json:"code" gorm:"not null;size:50"`
+	Name        string  `json:"name" gorm:"not null;size:255"`
+	BankName    string  `json:"bankName" gorm:"not null;size:100"`
+	Branch      *string `json:"branch,omitempty" gorm:"size:100"`
+	AccountNum  string  `json:"accountNum" gorm:"not null;size:50;uniqueIndex:idx_bank_account_num"`
+	ShabaNum    *string `json:"shabaNum,omitempty" gorm:"size:26"`
+	CardNum     *string `json:"cardNum,omitempty" gorm:"size:16"`
+	HolderName  *string `json:"holderName,omitempty" gorm:"size:255"`
+	Description *string `json:"description,omitempty" gorm:"type:text"`
+	IsActive    bool    `json:"isActive" gorm:"default:true"`
+
+	OpeningDate    time.Time    `json:"openingDate" gorm:"not null"`
+	OpeningBalance money.Amount `json:"openingBalance" gorm:"not null;default:0"`
+	LedgerEntryID  *uint        `json:"ledgerEntryId,omitempty"`
+
+	POSTerminals []POSTerminal `json:"posTerminals,omitempty" gorm:"foreignKey:BankAccountID"`
+}
+
+// POSTerminal کارتخوانی که وجوه آن به این حساب واریز می‌شود
+type POSTerminal struct {
+	gorm.Model
+
+	BankAccountID uint    `json:"bankAccountId" gorm:"not null;index"`
+	TerminalID    string  `json:"terminalId" gorm:"not null;size:50;uniqueIndex"`
+	MerchantID    *string `json:"merchantId,omitempty" gorm:"size:50"`
+	Provider      *string `json:"provider,omitempty" gorm:"size:100"`
+	Description   *string `json:"description,omitempty" gorm:"type:text"`
+	IsActive      bool    `json:"isActive" gorm:"default:true"`
+}
+
+const (
+	BankTransactionDeposit    = "deposit"
+	BankTransactionWithdrawal = "withdrawal"
+)
+
+// BankTransaction سند واریز یا برداشت از حساب بانکی
+type BankTransaction struct {
+	gorm.Model
+
+	BIDID         uint      `json:"bidId" gorm:"column:bid_id;not null;index"`
+	BankAccountID uint      `json:"bankAccountId" gorm:"not null;index"`
+	Type          string    `json:"type" gorm:"not null;size:20"`
+	Number        string    `json:"number" gorm:"not null;size:50;index"`
+	Date          time.Time `json:"date" gorm:"not null;index"`
+	DateJalali    string    `json:"dateJalali" gorm:"size:10"`
+
+	CounterpartyKind string `json:"counterpartyKind" gorm:"not null;size:50"`
+	CounterpartyID   *uint  `json:"counterpartyId,omitempty"`
+
+	Amount        money.Amount `json:"amount" gorm:"not null"`
+	POSTerminalID *uint        `json:"posTerminalId,omitempty" gorm:"index"`
+	TrackingCode  *string      `json:"trackingCode,omitempty" gorm:"size:100;index"`
+	Description   *string      `json:"description,omitempty" gorm:"type:text"`
+	LedgerEntryID *uint        `json:"ledgerEntryId,omitempty"`
+	CreatedBy     string       `json:"createdBy" gorm:"size:100"`
+}
+
+// BankStatementImport یک فایل صورتحساب بانکی وارد شده
+type BankStatementImport struct {
+	gorm.Model
+
+	BankAccountID uint   `json:"bankAccountId" gorm:"not null;index"`
+	FileName      string `json:"fileName" gorm:"size:255"`
+	Format        string `json:"format" gorm:"size:50"`
+	LineCount     int    `json:"lineCount"`
+	MatchedCount  int    `json:"matchedCount"`
+	ImportedBy    string `json:"importedBy" gorm:"size:100"`
+
+	Lines []BankStatementLine `json:"lines,omitempty" gorm:"foreignKey:ImportID"`
+}
+
+const (
+	StatementLineUnmatched = "unmatched"
+	StatementLineMatched   = "matched"
+	StatementLineIgnored   = "ignored"
+)
+
+// BankStatementLine یک ردیف صورتحساب بانک؛ مبالغ به تومان ذخیره می‌شوند.
+// Deposit یعنی ستون بستانکار صورتحساب بانک و Withdrawal ستون بدهکار آن.
+type BankStatementLine struct {
+	gorm.Model
+
+	ImportID      uint          `json:"importId" gorm:"not null;index"`
+	BankAccountID uint          `json:"bankAccountId" gorm:"not null;index"`
+	RowNumber     int           `json:"rowNumber"`
+	Date          time.Time     `json:"date" gorm:"not null;index"`
+	DateJalali    string        `json:"dateJalali" gorm:"size:10"`
+	Description   string        `json:"description" gorm:"size:500"`
+	Deposit       money.Amount  `json:"deposit" gorm:"not null;default:0"`
+	Withdrawal    money.Amount  `json:"withdrawal" gorm:"not null;default:0"`
+	Balance       *money.Amount `json:"balance,omitempty"`
+	TrackingCode  *string       `json:"trackingCode,omitempty" gorm:"size:100;index"`
+
+	Status string `json:"status" gorm:"not null;size:20;index;default:unmatched"`
+	// هر سطر دفتر حداکثر با یک ردیف صورتحساب تطبیق داده می‌شود
+	MatchedLedgerLineID *uint      `json:"matchedLedgerLineId,omitempty" gorm:"uniqueIndex"`
+	MatchedAt           *time.Time `json:"matchedAt,omitempty"`
+	MatchedBy           string     `json:"matchedBy,omitempty" gorm:"size:100"`
+	Note                *string    `json:"note,omitempty" gorm:"type:text"`
+}
+
+// ReconciliationCandidate سطر دفتر حساب بانک که هنوز با صورتحساب تطبیق نخورده است
+type ReconciliationCandidate struct {
+	LedgerLineID uint         `json:"ledgerLineId"`
+	EntryID      uint         `json:"entryId"`
+	PostedAt     time.Time    `json:"postedAt"`
+	DocType      string       `json:"docType"`
+	DocID        uint         `json:"docId"`
+	Reference    string       `json:"reference"`
+	Description  string       `json:"description"`
+	Debit        money.Amount `json:"debit"`
+	Credit       money.Amount `json:"credit"`
+}
+
+type ReconciliationResult struct {
+	Import    BankStatementImport `json:"import"`
+	Matched   int                 `json:"matched"`
+	Unmatched []BankStatementLine `json:"unmatched"`
+}
+
+type POSTerminalRequest struct {
+	TerminalID  string `json:"terminalId" validate:"required"`
+	MerchantID  string `json:"merchantId"`
+	Provider    string `json:"provider"`
+	Description string `json:"description"`
+}
+
+type CreateBankAccountRequest struct {
+	BIDID          uint                 `json:"bidId" validate:"required"`
+	Code           string               `json:"code" validate:"required"`
+	Name           string               `json:"name" validate:"required"`
+	BankName       string               `json:"bankName" validate:"required"`
+	Branch         string               `json:"branch"`
+	AccountNum     string               `json:"accountNum" validate:"required"`
+	ShabaNum       string               `json:"shabaNum"`
+	CardNum        string               `json:"cardNum"`
+	HolderName     string               `json:"holderName"`
+	Description    string               `json:"description"`
+	OpeningDate    string               `json:"openingDate"`
+	OpeningBalance money.Amount         `json:"openingBalance"`
+	POSTerminals   []POSTerminalRequest `json:"posTerminals"`
+}
+
+type BankTransactionRequest struct {
+	Type             string       `json:"type" validate:"required"`
+	Date             string       `json:"date" validate:"required"`
+	CounterpartyKind string       `json:"counterpartyKind" validate:"required"`
+	CounterpartyID   *uint        `json:"counterpartyId"`
+	Amount           money.Amount `json:"amount" validate:"required"`
+	POSTerminalID    *uint        `json:"posTerminalId"`
+	TrackingCode     string       `json:"trackingCode"`
+	Description      string       `json:"description"`
+}
+
+type MatchStatementLineRequest struct {
+	LedgerLineID uint   `json:"ledgerLineId" validate:"required"`
+	Note         string `json:"note"`
+}
+
+type IgnoreStatementLineRequest struct {
+	Note string `json:"note"`
+}
+
+// RecordStatementLineRequest برای ردیف‌هایی که سندی در سیستم ندارند (مثل کارمزد بانک) سند بانکی می‌سازد و همان‌جا تطبیق می‌دهد.
+type RecordStatementLineRequest struct {
+	CounterpartyKind string `json:"counterpartyKind" validate:"required"`
+	CounterpartyID   *uint  `json:"counterpartyId"`
+	Description      string `json:"description"`
+}
@@ -0,0 +1,175 @@
+package model
+
+import (
+	"time"
+
+	"common-gold/money"
+
+	"gorm.io/gorm"
+)
+
+const (
+	CenterTypeCost   = "cost"
+	CenterTypeIncome = "income"
+)
+
+// FinanceCenter مرکز هزینه یا درآمد؛ Path شناسه‌های اجداد را به صورت /1/4/9/ نگه می‌دارد تا جمع زیرشاخه‌ها ساده باشد.
+type FinanceCenter struct {
+	gorm.Model
+
+	BIDID       uint            `json:"bidId" gorm:"column:bid_id;not null;index;uniqueIndex:idx_center_code"`
+	Type        string          `json:"type" gorm:"not null;size:10;index"`
+	Code        string          `json:"code" gorm:"not null;size:50;uniqueIndex:idx_center_code"`
+	Name        string          `json:"name" gorm:"not null;size:255"`
+	ParentID    *uint           `json:"parentId,omitempty" gorm:"index"`
+	Path        string          `json:"path" gorm:"not null;size:500;index"`
+	Description *string         `json:"description,omitempty" gorm:"type:text"`
+	IsActive    bool            `json:"isActive" gorm:"default:true"`
+	Children    []FinanceCenter `json:"children,omitempty" gorm:"-"`
+}
+
+const (
+	CenterVoucherPosted   = "posted"
+	CenterVoucherReversed = "reversed"
+)
+
+// CenterVoucher سند هزینه (پرداخت از صندوق یا بانک) یا درآمد (دریافت به صندوق یا بانک) برای یک مرکز
+type CenterVoucher struct {
+	gorm.Model
+
+	BIDID      uint         `json:"bidId" gorm:"column:bid_id;not null;index"`
+	CenterID   uint         `json:"centerId" gorm:"not null;index"`
+	CenterType string       `json:"centerType" gorm:"not null;size:10"`
+	Number     string       `json:"number" gorm:"not null;size:50;uniqueIndex"`
+	Date       time.Time    `json:"date" gorm:"not null;index;uniqueIndex:idx_recurring_run"`
+	DateJalali string       `json:"dateJalali" gorm:"size:10"`
+	Amount     money.Amount `json:"amount" gorm:"not null"`
+
+	// صندوق یا بانکی که وجه از آن پرداخت یا به آن واریز شده است
+	AccountKind string `json:"accountKind" gorm:"not null;size:20"`
+	AccountID   uint   `json:"accountId" gorm:"not null"`
+	PersonID    *uint  `json:"personId,omitempty" gorm:"index"`
+
+	Description        *string `json:"description,omitempty" gorm:"type:text"`
+	RecurringExpenseID *uint   `json:"recurringExpenseId,omitempty" gorm:"uniqueIndex:idx_recurring_run"`
+	LedgerEntryID      *uint   `json:"ledgerEntryId,omitempty"`
+
+	Status         string     `json:"status" gorm:"not null;size:20;index"`
+	ReversedAt     *time.Time `json:"reversedAt,omitempty"`
+	ReversedBy     string     `json:"reversedBy,omitempty" gorm:"size:100"`
+	ReversalReason *string    `json:"reversalReason,omitempty" gorm:"type:text"`
+	CreatedBy      string     `json:"createdBy" gorm:"size:100"`
+}
+
+const (
+	RecurrenceWeekly  = "weekly"
+	RecurrenceMonthly = "monthly"
+	RecurrenceYearly  = "yearly"
+)
+
+// RecurringExpense هزینه تکراری مثل اجاره و حقوق؛ ماهانه و سالانه بر اساس تقویم شمسی محاسبه می‌شوند.
+type RecurringExpense struct {
+	gorm.Model
+
+	BIDID       uint         `json:"bidId" gorm:"column:bid_id;not null;index"`
+	CenterID    uint         `json:"centerId" gorm:"not null;index"`
+	Title       string       `json:"title" gorm:"not null;size:255"`
+	Amount      money.Amount `json:"amount" gorm:"not null"`
+	AccountKind string       `json:"accountKind" gorm:"not null;size:20"`
+	AccountID   uint         `json:"accountId" gorm:"not null"`
+	PersonID    *uint        `json:"personId,omitempty"`
+
+	Frequency string `json:"frequency" gorm:"not null;size:20"`
+	// روز ماه شمسی برای تکرار ماهانه و سالانه؛ در ماه‌های کوتاه‌تر آخرین روز ماه استفاده می‌شود
+	DayOfMonth  int        `json:"dayOfMonth"`
+	StartDate   time.Time  `json:"startDate" gorm:"not null"`
+	EndDate     *time.Time `json:"endDate,omitempty"`
+	NextRunDate time.Time  `json:"nextRunDate" gorm:"not null;index"`
+	LastRunAt   *time.Time `json:"lastRunAt,omitempty"`
+	IsActive    bool       `json:"isActive" gorm:"default:true;index"`
+	CreatedBy   string     `json:"createdBy" gorm:"size:100"`
+}
+
+type CenterVoucherFilter struct {
+	BIDID      uint
+	CenterID   uint
+	CenterType string
+	Status     string
+	From       *time.Time
+	To         *time.Time
+}
+
+// CenterTotal جمع خالص یک مرکز در بازه گزارش
+type CenterTotal struct {
+	CenterID uint         `json:"centerId"`
+	Code     string       `json:"code"`
+	Name     string       `json:"name"`
+	Amount   money.Amount `json:"amount"`
+	Percent  float64      `json:"percent"`
+}
+
+// CenterChartData داده آماده نمودارهای TopCostCentersChart و TopIncomeCentersChart
+type CenterChartData struct {
+	Type   string         `json:"type"`
+	From   string         `json:"from"`
+	To     string         `json:"to"`
+	Total  money.Amount   `json:"total"`
+	Others money.Amount   `json:"others"`
+	Labels []string       `json:"labels"`
+	Values []money.Amount `json:"values"`
+	Items  []CenterTotal  `json:"items"`
+}
+
+type TopCentersResponse struct {
+	CostCenters   *CenterChartData `json:"costCenters,omitempty"`
+	IncomeCenters *CenterChartData `json:"incomeCenters,omitempty"`
+}
+
+type CreateCenterRequest struct {
+	BIDID       uint   `json:"bidId" validate:"required"`
+	Type        string `json:"type" validate:"required"`
+	Code        string `json:"code" validate:"required"`
+	Name        string `json:"name" validate:"required"`
+	ParentID    *uint  `json:"parentId"`
+	Description string `json:"description"`
+}
+
+type UpdateCenterRequest struct {
+	Name        *string `json:"name"`
+	ParentID    *uint   `json:"parentId"`
+	Description *string `json:"description"`
+	IsActive    *bool   `json:"isActive"`
+}
+
+type CreateCenterVoucherRequest struct {
+	CenterID    uint         `json:"centerId" validate:"required"`
+	Date        string       `json:"date" validate:"required"`
+	Amount      money.Amount `json:"amount" validate:"required"`
+	AccountKind string       `json:"accountKind" validate:"required"`
+	AccountID   uint         `json:"accountId" validate:"required"`
+	PersonID    *uint        `json:"personId"`
+	Description string       `json:"description"`
+}
+
+type CreateRecurringExpenseRequest struct {
+	CenterID    uint         `json:"centerId" validate:"required"`
+	Title       string       `json:"title" validate:"required"`
+	Amount      money.Amount `json:"amount" validate:"required"`
+	AccountKind string       `json:"accountKind" validate:"required"`
+	AccountID   uint         `json:"accountId" validate:"required"`
+	PersonID    *uint        `json:"personId"`
+	Frequency   string       `json:"frequency" validate:"required"`
+	StartDate   string       `json:"startDate" validate:"required"`
+	EndDate     string       `json:"endDate"`
+}
+
+type UpdateRecurringExpenseRequest struct {
+	Amount   *money.Amount `json:"amount"`
+	EndDate  *string       `json:"endDate"`
+	IsActive *bool         `json:"isActive"`
+}
+
+type ReverseCenterVoucherRequest struct {
+	Reason string `json:"reason" validate:"required"`
+	Date   string `json:"date"`
+}
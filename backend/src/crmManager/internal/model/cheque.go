@@ -0,0 +1,133 @@
+package model
+
+import (
+	"time"
+
+	"common-gold/money"
+
+	"gorm.io/gorm"
+)
+
+const (
+	ChequeDirectionReceived = "received"
+	ChequeDirectionIssued   = "issued"
+)
+
+const (
+	ChequeStatusInHand    = "in_hand"
+	ChequeStatusIssued    = "issued"
+	ChequeStatusDeposited = "deposited"
+	ChequeStatusCleared   = "cleared"
+	ChequeStatusBounced   = "bounced"
+	ChequeStatusReturned  = "returned"
+	ChequeStatusEndorsed  = "endorsed"
+)
+
+type Cheque struct {
+	gorm.Model
+
+	BIDID     uint   `json:"bidId" gorm:"column:bid_id;not null;index;uniqueIndex:idx_cheque_sayad"`
+	Direction string `json:"direction" gorm:"not null;size:20;index"`
+	Status    string `json:"status" gorm:"not null;size:20;index"`
+
+	Serial   string       `json:"serial" gorm:"not null;size:50"`
+	SayadID  string       `json:"sayadId" gorm:"not null;size:16;uniqueIndex:idx_cheque_sayad"`
+	BankName string       `json:"bankName" gorm:"not null;size:100"`
+	Branch   *string      `json:"branch,omitempty" gorm:"size:100"`
+	Amount   money.Amount `json:"amount" gorm:"not null"`
+
+	IssueDate     *time.Time `json:"issueDate,omitempty"`
+	DueDate       time.Time  `json:"dueDate" gorm:"not null;index"`
+	DueDateJalali string     `json:"dueDateJalali" gorm:"size:10;index"`
+
+	// طرف حساب: صادرکننده در چک دریافتی، گیرنده در چک پرداختی
+	PersonID uint      `json:"personId" gorm:"not null;index"`
+	Person   *Customer `json:"person,omitempty" gorm:"foreignKey:PersonID;references:ID"`
+
+	EndorsedToPersonID *uint      `json:"endorsedToPersonId,omitempty" gorm:"index"`
+	EndorsedToPerson   *Customer  `json:"endorsedToPerson,omitempty" gorm:"foreignKey:EndorsedToPersonID;references:ID"`
+	DepositBankID      *uint      `json:"depositBankId,omitempty" gorm:"index"`
+	ResponsibleUserRef *string    `json:"responsibleUserRef,omitempty" gorm:"type:uuid;index"`
+	StatusChangedAt    *time.Time `json:"statusChangedAt,omitempty"`
+
+	Description *string               `json:"description,omitempty" gorm:"type:text"`
+	History     []ChequeStatusHistory `json:"history,omitempty" gorm:"foreignKey:ChequeID"`
+}
+
+type ChequeStatusHistory struct {
+	gorm.Model
+
+	ChequeID      uint      `json:"chequeId" gorm:"not null;index"`
+	FromStatus    string    `json:"fromStatus" gorm:"size:20"`
+	ToStatus      string    `json:"toStatus" gorm:"not null;size:20"`
+	ChangedAt     time.Time `json:"changedAt" gorm:"not null"`
+	ChangedBy     string    `json:"changedBy" gorm:"size:100"`
+	Note          *string   `json:"note,omitempty" gorm:"type:text"`
+	LedgerEntryID *uint     `json:"ledgerEntryId,omitempty" gorm:"index"`
+}
+
+type CreateChequeRequest struct {
+	BIDID              uint         `json:"bidId" validate:"required"`
+	Serial             string       `json:"serial" validate:"required"`
+	SayadID            string       `json:"sayadId" validate:"required"`
+	BankName           string       `json:"bankName" validate:"required"`
+	Branch             string       `json:"branch"`
+	Amount             money.Amount `json:"amount" validate:"required"`
+	IssueDate          string       `json:"issueDate"`
+	DueDate            string       `json:"dueDate" validate:"required"`
+	PersonID           uint         `json:"personId" validate:"required"`
+	ResponsibleUserRef *string      `json:"responsibleUserRef"`
+	Description        string       `json:"description"`
+}
+
+type ChequeTransitionRequest struct {
+	Status             string `json:"status" validate:"required"`
+	EndorsedToPersonID *uint  `json:"endorsedToPersonId"`
+	BankID             *uint  `json:"bankId"`
+	Note               string `json:"note"`
+}
+
+type ChequeFilter struct {
+	BIDID     uint
+	Direction string
+	Status    string
+	Statuses  []string
+	PersonID  uint
+	DueFrom   *time.Time
+	DueTo     *time.Time
+}
+
+const (
+	ChequeAlertUpcoming = "upcoming"
+	ChequeAlertDueToday = "due_today"
+	ChequeAlertOverdue  = "overdue"
+)
+
+// ChequeAlert هر هشدار سررسید فقط یک بار برای هر چک و نوع هشدار ثبت می‌شود تا اجرای مجدد کران تکراری نفرستد.
+type ChequeAlert struct {
+	gorm.Model
+
+	ChequeID     uint       `json:"chequeId" gorm:"not null;uniqueIndex:idx_cheque_alert"`
+	Kind         string     `json:"kind" gorm:"not null;size:20;uniqueIndex:idx_cheque_alert"`
+	DueDate      time.Time  `json:"dueDate" gorm:"not null;uniqueIndex:idx_cheque_alert"`
+	RecipientRef *string    `json:"recipientRef,omitempty" gorm:"type:uuid"`
+	SentAt       *time.Time `json:"sentAt,omitempty"`
+	LastError    *string    `json:"lastError,omitempty" gorm:"type:text"`
+}
+
+type ChequeDueBucket struct {
+	Count  int          `json:"count"`
+	Amount money.Amount `json:"amount"`
+}
+
+type ChequeDueTotals struct {
+	Overdue     ChequeDueBucket `json:"overdue"`
+	DueToday    ChequeDueBucket `json:"dueToday"`
+	DueThisWeek ChequeDueBucket `json:"dueThisWeek"`
+}
+
+type ChequeDueSummary struct {
+	Date     string          `json:"date"`
+	Received ChequeDueTotals `json:"received"`
+	Issued   ChequeDueTotals `json:"issued"`
+}
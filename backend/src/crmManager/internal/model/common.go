@@ -15,5 +15,7 @@ type CustomClaims struct {
 	UserID   string         `json:"user_id"`
 	Username string         `json:"username"`
 	Roles    datatypes.JSON `json:"roles"`
+	// BusinessID در توکنی که برای کلید API محدود به یک کسب‌وکار صادر شده است.
+	BusinessID *uint `json:"bid,omitempty"`
 	jwt.RegisteredClaims
 }
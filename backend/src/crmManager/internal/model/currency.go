@@ -0,0 +1,72 @@
+package model
+
+import (
+	"time"
+
+	"common-gold/money"
+
+	"gorm.io/gorm"
+)
+
+// CurrencyDecimals دقت نگهداری مبالغ ارزی
+const CurrencyDecimals = money.ForeignScale
+
+// ExchangeRate نرخ تبدیل یک واحد ارز به تومان در یک روز؛ برای هر تاریخ آخرین نرخِ ثبت‌شده تا آن روز معتبر است.
+type ExchangeRate struct {
+	gorm.Model
+
+	BIDID      uint       `json:"bidId" gorm:"column:bid_id;not null;uniqueIndex:idx_exchange_rate_day"`
+	CurrencyID uint       `json:"currencyId" gorm:"not null;uniqueIndex:idx_exchange_rate_day"`
+	Currency   *Currency  `json:"currency,omitempty" gorm:"foreignKey:CurrencyID;references:ID"`
+	Date       time.Time  `json:"date" gorm:"not null;uniqueIndex:idx_exchange_rate_day"`
+	DateJalali string     `json:"dateJalali" gorm:"size:10"`
+	Rate       money.Rate `json:"rate" gorm:"not null"`
+	CreatedBy  string     `json:"createdBy" gorm:"size:100"`
+}
+
+type SetExchangeRateRequest struct {
+	BIDID      uint       `json:"bidId" validate:"required"`
+	CurrencyID uint       `json:"currencyId" validate:"required"`
+	Date       string     `json:"date" validate:"required"`
+	Rate       money.Rate `json:"rate" validate:"required"`
+}
+
+type ExchangeRateFilter struct {
+	BIDID      uint
+	CurrencyID uint
+	From       *time.Time
+	To         *time.Time
+}
+
+// CurrencyBalanceRow مانده ارزی و تومانیِ دفتری یک حساب تفصیلی در یک ارز
+type CurrencyBalanceRow struct {
+	AccountKind    string
+	AccountID      uint
+	CurrencyID     uint
+	ForeignBalance money.Foreign
+	BookBalance    money.Amount
+}
+
+// RevaluationRow تسعیر: ارزش روز = مانده ارزی × نرخ روز؛ تفاوت مثبت سود و منفی زیان تسعیر است.
+type RevaluationRow struct {
+	AccountKind    string        `json:"accountKind"`
+	AccountID      uint          `json:"accountId"`
+	CurrencyID     uint          `json:"currencyId"`
+	CurrencyName   string        `json:"currencyName"`
+	ForeignBalance money.Foreign `json:"foreignBalance"`
+	BookBalance    money.Amount  `json:"bookBalance"`
+	Rate           money.Rate    `json:"rate"`
+	RateDate       string        `json:"rateDate"`
+	RevaluedValue  money.Amount  `json:"revaluedValue"`
+	Difference     money.Amount  `json:"difference"`
+}
+
+type RevaluationReport struct {
+	AsOf        string           `json:"asOf"`
+	Rows        []RevaluationRow `json:"rows"`
+	TotalGain   money.Amount     `json:"totalGain"`
+	TotalLoss   money.Amount     `json:"totalLoss"`
+	NetGainLoss money.Amount     `json:"netGainLoss"`
+	// ارزهایی که تا تاریخ گزارش نرخی برایشان ثبت نشده و در جمع‌ها لحاظ نشده‌اند
+	MissingRates []uint `json:"missingRates,omitempty"`
+}
@@ -0,0 +1,79 @@
+package model
+
+import (
+	"errors"
+	"time"
+
+	"common-gold/money"
+
+	"gorm.io/gorm"
+)
+
+const (
+	FiscalYearOpen   = "open"
+	FiscalYearClosed = "closed"
+)
+
+const (
+	FiscalYearEventCreate = "create"
+	FiscalYearEventClose  = "close"
+	FiscalYearEventReopen = "reopen"
+)
+
+// کد حساب سود (زیان) انباشته در سرفصل پیش‌فرض؛ مانده درآمدها و هزینه‌ها در پایان سال به این حساب بسته می‌شود.
+const AccountCodeRetainedEarnings = "3103"
+
+// ErrFiscalYearClosed ثبت، ویرایش یا برگشت هر سندی با تاریخ داخل سال مالی بسته‌شده ممنوع است.
+var ErrFiscalYearClosed = errors.New("fiscal year is closed")
+
+// FiscalYear سال مالی شمسی هر کسب‌وکار؛ EndDate آخرین روز سال (نیمه‌شب به وقت تهران) است.
+type FiscalYear struct {
+	gorm.Model
+
+	BIDID      uint      `json:"bidId" gorm:"column:bid_id;not null;index;uniqueIndex:idx_fiscal_year"`
+	JalaliYear int       `json:"jalaliYear" gorm:"not null;uniqueIndex:idx_fiscal_year"`
+	Title      string    `json:"title" gorm:"not null;size:100"`
+	StartDate  time.Time `json:"startDate" gorm:"not null;index"`
+	EndDate    time.Time `json:"endDate" gorm:"not null;index"`
+	Status     string    `json:"status" gorm:"not null;size:20;index"`
+
+	// سند افتتاحیه همین سال (انتقال مانده از سال قبل یا مانده اولیه اشخاص در سال اول)
+	OpeningEntryID *uint `json:"openingEntryId,omitempty"`
+	// اسناد بستن حساب‌های موقت و اختتامیه که هنگام بستن سال ثبت می‌شوند
+	ProfitClosingEntryID *uint      `json:"profitClosingEntryId,omitempty"`
+	ClosingEntryID       *uint      `json:"closingEntryId,omitempty"`
+	ClosedAt             *time.Time `json:"closedAt,omitempty"`
+	ClosedBy             string     `json:"closedBy,omitempty" gorm:"size:100"`
+	CreatedBy            string     `json:"createdBy" gorm:"size:100"`
+}
+
+// FiscalYearEvent سابقه ایجاد، بستن و بازگشایی سال مالی
+type FiscalYearEvent struct {
+	gorm.Model
+
+	FiscalYearID uint    `json:"fiscalYearId" gorm:"not null;index"`
+	BIDID        uint    `json:"bidId" gorm:"column:bid_id;not null;index"`
+	Action       string  `json:"action" gorm:"not null;size:20"`
+	Actor        string  `json:"actor" gorm:"size:100"`
+	Reason       *string `json:"reason,omitempty" gorm:"type:text"`
+}
+
+// AccountBalanceRow مانده تجمعی یک حساب معین/تفصیلی/ارز برای محاسبه اسناد پایان سال
+type AccountBalanceRow struct {
+	AccountCode     string
+	AccountKind     string
+	AccountID       *uint
+	CurrencyID      *uint
+	Balance         money.Amount
+	GoldBalance     money.Weight
+	CurrencyBalance money.Foreign
+}
+
+type CreateFiscalYearRequest struct {
+	BIDID      uint `json:"bidId" validate:"required"`
+	JalaliYear int  `json:"jalaliYear" validate:"required"`
+}
+
+type ReopenFiscalYearRequest struct {
+	Reason string `json:"reason" validate:"required"`
+}
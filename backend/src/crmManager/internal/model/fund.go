@@ -0,0 +1,109 @@
+package model
+
+import (
+	"time"
+
+	"common-gold/money"
+
+	"gorm.io/gorm"
+)
+
+// Fund صندوق نقدی یک کسب‌وکار یا شعبه
+type Fund struct {
+	gorm.Model
+
+	BIDID       uint    `json:"bidId" gorm:"column:bid_id;not null;index;uniqueIndex:idx_fund_code"`
+	BranchID    *uint   `json:"branchId,omitempty" gorm:"index"`
+	Code        string  `json:"code" gorm:"not null;size:50;uniqueIndex:idx_fund_code"`
+	Name        string  `json:"name" gorm:"not null;size:255"`
+	Description *string `json:"description,omitempty" gorm:"type:text"`
+	IsActive    bool    `json:"isActive" gorm:"default:true"`
+
+	OpeningDate     time.Time            `json:"openingDate" gorm:"not null"`
+	OpeningBalances []FundOpeningBalance `json:"openingBalances,omitempty" gorm:"foreignKey:FundID"`
+
+	// همه اسناد تا پایان این روز بسته شده‌اند و قابل ویرایش نیستند
+	ClosedThrough *time.Time `json:"closedThrough,omitempty"`
+}
+
+// FundOpeningBalance موجودی اول دوره؛ CurrencyID خالی یعنی تومان. Amount به واحد همان ارز (برای تومان خود مبلغ تومانی) است.
+type FundOpeningBalance struct {
+	gorm.Model
+
+	FundID        uint          `json:"fundId" gorm:"not null;index"`
+	CurrencyID    *uint         `json:"currencyId,omitempty"`
+	Currency      *Currency     `json:"currency,omitempty" gorm:"foreignKey:CurrencyID;references:ID"`
+	Amount        money.Foreign `json:"amount" gorm:"not null"`
+	AmountInToman money.Amount  `json:"amountInToman" gorm:"not null"`
+	LedgerEntryID *uint         `json:"ledgerEntryId,omitempty"`
+}
+
+const (
+	CashVoucherReceipt = "receipt"
+	CashVoucherPayment = "payment"
+)
+
+// CashVoucher سند دریافت یا پرداخت نقدی از صندوق
+type CashVoucher struct {
+	gorm.Model
+
+	BIDID      uint      `json:"bidId" gorm:"column:bid_id;not null;index"`
+	FundID     uint      `json:"fundId" gorm:"not null;index"`
+	Type       string    `json:"type" gorm:"not null;size:20"`
+	Number     string    `json:"number" gorm:"not null;size:50;index"`
+	Date       time.Time `json:"date" gorm:"not null;index"`
+	DateJalali string    `json:"dateJalali" gorm:"size:10"`
+
+	CounterpartyKind string `json:"counterpartyKind" gorm:"not null;size:20"`
+	CounterpartyID   uint   `json:"counterpartyId" gorm:"not null"`
+
+	Amount         money.Amount  `json:"amount" gorm:"not null"`
+	CurrencyID     *uint         `json:"currencyId,omitempty"`
+	CurrencyAmount money.Foreign `json:"currencyAmount,omitzero" gorm:"default:0"`
+
+	Description   *string `json:"description,omitempty" gorm:"type:text"`
+	LedgerEntryID *uint   `json:"ledgerEntryId,omitempty"`
+	CreatedBy     string  `json:"createdBy" gorm:"size:100"`
+}
+
+type FundDayClose struct {
+	gorm.Model
+
+	FundID     uint         `json:"fundId" gorm:"not null;uniqueIndex:idx_fund_day"`
+	Date       time.Time    `json:"date" gorm:"not null;uniqueIndex:idx_fund_day"`
+	DateJalali string       `json:"dateJalali" gorm:"size:10"`
+	Balance    money.Amount `json:"balance"`
+	ClosedBy   string       `json:"closedBy" gorm:"size:100"`
+}
+
+type FundOpeningBalanceRequest struct {
+	CurrencyID    *uint         `json:"currencyId"`
+	Amount        money.Foreign `json:"amount"`
+	AmountInToman money.Amount  `json:"amountInToman"`
+}
+
+type CreateFundRequest struct {
+	BIDID           uint                        `json:"bidId" validate:"required"`
+	BranchID        *uint                       `json:"branchId"`
+	Code            string                      `json:"code" validate:"required"`
+	Name            string                      `json:"name" validate:"required"`
+	Description     string                      `json:"description"`
+	OpeningDate     string                      `json:"openingDate"`
+	OpeningBalances []FundOpeningBalanceRequest `json:"openingBalances"`
+}
+
+type CashVoucherRequest struct {
+	Type             string `json:"type" validate:"required"`
+	Date             string `json:"date" validate:"required"`
+	CounterpartyKind string `json:"counterpartyKind" validate:"required"`
+	CounterpartyID   uint   `json:"counterpartyId" validate:"required"`
+	// مبلغ تومانی؛ برای سند ارزی اگر خالی باشد از نرخ ارز روز سند محاسبه می‌شود
+	Amount         money.Amount  `json:"amount"`
+	CurrencyID     *uint         `json:"currencyId"`
+	CurrencyAmount money.Foreign `json:"currencyAmount"`
+	Description    string        `json:"description"`
+}
+
+type CloseFundDayRequest struct {
+	Date string `json:"date" validate:"required"`
+}
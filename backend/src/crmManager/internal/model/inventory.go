@@ -0,0 +1,124 @@
+package model
+
+import (
+	"time"
+
+	"common-gold/money"
+
+	"gorm.io/gorm"
+)
+
+// مالکیت کالا: کالای امانی متعلق به امانت‌گذار است و در ریسک طلای فروشگاه حساب نمی‌شود.
+const (
+	InventoryOwned     = "owned"
+	InventoryConsigned = "consigned"
+)
+
+const (
+	InventoryItemInStock = "in_stock"
+	InventoryItemSold    = "sold"
+)
+
+// عیار مرجع نرخ روز (۱۸ عیار) و طلای خالص، به هزارم
+const (
+	GoldRatePurity = 750
+	FineGoldPurity = 1000
+)
+
+// InventoryItem یک قطعه کالای طلا در موجودی؛ Purity به هزارم است (۷۵۰ برای ۱۸ عیار).
+// CostPrice بهای تمام‌شده قطعه و برای کالای امانی مبلغ قابل پرداخت به امانت‌گذار است.
+type InventoryItem struct {
+	gorm.Model
+
+	BIDID       uint         `json:"bidId" gorm:"column:bid_id;not null;index;uniqueIndex:idx_inventory_item_code"`
+	Code        string       `json:"code" gorm:"not null;size:50;uniqueIndex:idx_inventory_item_code"`
+	Name        string       `json:"name" gorm:"not null;size:255"`
+	Category    string       `json:"category" gorm:"size:100;index"`
+	Purity      int          `json:"purity" gorm:"not null"`
+	Weight      money.Weight `json:"weight" gorm:"not null"`
+	CostPrice   money.Amount `json:"costPrice" gorm:"not null;default:0"`
+	Ownership   string       `json:"ownership" gorm:"not null;size:20;index"`
+	ConsignorID *uint        `json:"consignorId,omitempty" gorm:"index"`
+	ReceivedAt  time.Time    `json:"receivedAt" gorm:"not null;index"`
+	Status      string       `json:"status" gorm:"not null;size:20;index"`
+	SoldAt      *time.Time   `json:"soldAt,omitempty"`
+	// فاکتور فروشی که کالا با آن فروخته شده است
+	SaleInvoiceID *uint   `json:"saleInvoiceId,omitempty" gorm:"index"`
+	Description   *string `json:"description,omitempty" gorm:"type:text"`
+	CreatedBy     string  `json:"createdBy" gorm:"size:100"`
+}
+
+type CreateInventoryItemRequest struct {
+	BIDID       uint         `json:"bidId" validate:"required"`
+	Code        string       `json:"code" validate:"required"`
+	Name        string       `json:"name" validate:"required"`
+	Category    string       `json:"category"`
+	Purity      int          `json:"purity" validate:"required"`
+	Weight      money.Weight `json:"weight" validate:"required"`
+	CostPrice   money.Amount `json:"costPrice"`
+	Ownership   string       `json:"ownership"`
+	ConsignorID *uint        `json:"consignorId"`
+	ReceivedAt  string       `json:"receivedAt"`
+	Description string       `json:"description"`
+}
+
+type UpdateInventoryItemRequest struct {
+	Name        *string       `json:"name"`
+	Category    *string       `json:"category"`
+	CostPrice   *money.Amount `json:"costPrice"`
+	Description *string       `json:"description"`
+}
+
+type InventoryItemFilter struct {
+	BIDID     uint
+	Category  string
+	Status    string
+	Ownership string
+}
+
+// InventoryStockRow جمع کالاهای موجود به تفکیک دسته‌بندی، عیار و مالکیت
+type InventoryStockRow struct {
+	Category  string
+	Purity    int
+	Ownership string
+	ItemCount int
+	Weight    money.Weight
+	Cost      money.Amount
+}
+
+// InventoryValuationRow وزن خالص معادل طلای ۲۴ عیار است و ارزش روز با نرخ هر گرم ۱۸ عیار به نسبت عیار محاسبه می‌شود.
+type InventoryValuationRow struct {
+	Category    string       `json:"category"`
+	Purity      int          `json:"purity"`
+	ItemCount   int          `json:"itemCount"`
+	Weight      money.Weight `json:"weight"`
+	FineWeight  money.Weight `json:"fineWeight"`
+	CostValue   money.Amount `json:"costValue"`
+	MarketValue money.Amount `json:"marketValue"`
+}
+
+// InventoryValuation ارزش موجودی فعلی؛ Exposure همان موجودی ملکی است چون کالای امانی ریسک طلای فروشگاه نیست.
+type InventoryValuation struct {
+	AsOf              string                  `json:"asOf"`
+	GoldRate          money.Amount            `json:"goldRate"`
+	GoldRateUpdatedAt *time.Time              `json:"goldRateUpdatedAt,omitempty"`
+	Rows              []InventoryValuationRow `json:"rows"`
+	Owned             InventoryValuationRow   `json:"owned"`
+	Consigned         InventoryValuationRow   `json:"consigned"`
+	Totals            InventoryValuationRow   `json:"totals"`
+	ExposureFineGold  money.Weight            `json:"exposureFineGold"`
+}
+
+type SlowMovingItem struct {
+	InventoryItem
+	DaysInStock int `json:"daysInStock"`
+}
+
+// SlowMovers کالاهای موجودی که N روز یا بیشتر فروش نرفته‌اند، از قدیمی‌ترین
+type SlowMovers struct {
+	AsOf        string           `json:"asOf"`
+	Days        int              `json:"days"`
+	Items       []SlowMovingItem `json:"items"`
+	TotalWeight money.Weight     `json:"totalWeight"`
+	TotalCost   money.Amount     `json:"totalCost"`
+}
@@ -0,0 +1,196 @@
+package model
+
+import (
+	"errors"
+	"time"
+
+	"common-gold/money"
+
+	"gorm.io/gorm"
+)
+
+// حساب‌های معین که اسناد مالی روی آن‌ها ثبت می‌شوند
+const (
+	LedgerAccountPerson            = "person"
+	LedgerAccountFund              = "fund"
+	LedgerAccountBank              = "bank"
+	LedgerAccountChequesReceivable = "cheques_receivable"
+	LedgerAccountChequesCollection = "cheques_in_collection"
+	LedgerAccountChequesBounced    = "cheques_bounced"
+	LedgerAccountChequesPayable    = "cheques_payable"
+	LedgerAccountOpeningBalance    = "opening_balance"
+	LedgerAccountBankFees          = "bank_fees"
+	LedgerAccountExpense           = "expense"
+	LedgerAccountIncome            = "income"
+	// سطرهای حساب‌هایی از سرفصل که نوع سیستمی ندارند و فقط با کد حساب شناخته می‌شوند
+	LedgerAccountGeneral = "general"
+)
+
+const (
+	LedgerDocCheque      = "cheque"
+	LedgerDocFundOpening = "fund_opening"
+	LedgerDocCashVoucher = "cash_voucher"
+	LedgerDocBankOpening = "bank_opening"
+	LedgerDocBankTxn     = "bank_transaction"
+	LedgerDocTransfer    = "transfer"
+	LedgerDocCenter      = "center_voucher"
+	LedgerDocJournal     = "journal"
+	LedgerDocSaleInvoice = "sale_invoice"
+	// اسناد پایان سال مالی
+	LedgerDocYearOpening   = "year_opening"
+	LedgerDocProfitClosing = "profit_closing"
+	LedgerDocYearClosing   = "year_closing"
+)
+
+// ErrLedgerImmutable اسناد ثبت‌شده دفتر قابل ویرایش یا حذف نیستند.
+var ErrLedgerImmutable = errors.New("posted ledger entries are immutable; post a reversing entry instead")
+
+type LedgerEntry struct {
+	gorm.Model
+
+	BIDID       uint      `json:"bidId" gorm:"column:bid_id;not null;index"`
+	DocType     string    `json:"docType" gorm:"not null;size:50;index:idx_ledger_doc"`
+	DocID       uint      `json:"docId" gorm:"not null;index:idx_ledger_doc"`
+	Reference   string    `json:"reference" gorm:"not null;size:100;index"`
+	Description string    `json:"description" gorm:"size:500"`
+	PostedAt    time.Time `json:"postedAt" gorm:"not null;index"`
+	CreatedBy   string    `json:"createdBy" gorm:"size:100"`
+	// سند برگشتی: اسناد ثبت‌شده ویرایش نمی‌شوند و اصلاح با ثبت سند معکوس انجام می‌شود
+	ReversalOfID *uint        `json:"reversalOfId,omitempty" gorm:"index"`
+	Lines        []LedgerLine `json:"lines" gorm:"foreignKey:EntryID"`
+}
+
+type LedgerLine struct {
+	gorm.Model
+
+	EntryID     uint   `json:"entryId" gorm:"not null;index"`
+	BIDID       uint   `json:"bidId" gorm:"column:bid_id;not null;index"`
+	AccountKind string `json:"accountKind" gorm:"not null;size:50;index:idx_ledger_account"`
+	AccountID   *uint  `json:"accountId,omitempty" gorm:"index:idx_ledger_account"`
+	// کد حساب معین در سرفصل حساب‌ها؛ هنگام ثبت از روی AccountKind تعیین می‌شود
+	AccountCode string       `json:"accountCode" gorm:"size:20;index"`
+	Debit       money.Amount `json:"debit" gorm:"not null;default:0"`
+	Credit      money.Amount `json:"credit" gorm:"not null;default:0"`
+	// مقدار وزنی سطر به گرم طلا؛ هر سند باید هم از نظر ریالی و هم از نظر وزنی تراز باشد
+	GoldDebit  money.Weight `json:"goldDebit" gorm:"not null;default:0"`
+	GoldCredit money.Weight `json:"goldCredit" gorm:"not null;default:0"`
+	// مبلغ به ارز خارجی در صورت وجود؛ جهت آن همان جهت بدهکار/بستانکار سطر است
+	CurrencyID     *uint         `json:"currencyId,omitempty" gorm:"index"`
+	CurrencyAmount money.Foreign `json:"currencyAmount,omitzero" gorm:"not null;default:0"`
+	Description    string        `json:"description,omitempty" gorm:"size:500"`
+}
+
+// Reversal builds the mirror entry that cancels e, dated at.
+func (e *LedgerEntry) Reversal(at time.Time, actor, reason string) *LedgerEntry {
+	lines := make([]LedgerLine, 0, len(e.Lines))
+	for _, l := range e.Lines {
+		lines = append(lines, LedgerLine{
+			AccountKind:    l.AccountKind,
+			AccountID:      l.AccountID,
+			AccountCode:    l.AccountCode,
+			Debit:          l.Credit,
+			Credit:         l.Debit,
+			GoldDebit:      l.GoldCredit,
+			GoldCredit:     l.GoldDebit,
+			CurrencyID:     l.CurrencyID,
+			CurrencyAmount: l.CurrencyAmount,
+			Description:    reason,
+		})
+	}
+	id := e.ID
+	return &LedgerEntry{
+		BIDID:        e.BIDID,
+		DocType:      e.DocType,
+		DocID:        e.DocID,
+		Reference:    e.Reference + "-REV",
+		Description:  reason,
+		PostedAt:     at,
+		CreatedBy:    actor,
+		ReversalOfID: &id,
+		Lines:        lines,
+	}
+}
+
+type StatementLine struct {
+	EntryID     uint         `json:"entryId"`
+	Date        time.Time    `json:"date"`
+	DateJalali  string       `json:"dateJalali"`
+	DocType     string       `json:"docType"`
+	DocID       uint         `json:"docId"`
+	Reference   string       `json:"reference"`
+	Description string       `json:"description"`
+	Debit       money.Amount `json:"debit"`
+	Credit      money.Amount `json:"credit"`
+	Balance     money.Amount `json:"balance"`
+	// Currency گردش ارزی سطر؛ فقط در صورت‌حساب یک ارز پر می‌شود.
+	Currency *StatementCurrencyLine `json:"currency,omitempty"`
+}
+
+// StatementCurrencyLine بدهکار، بستانکار و مانده سطر به واحد ارز
+type StatementCurrencyLine struct {
+	Debit   money.Foreign `json:"debit"`
+	Credit  money.Foreign `json:"credit"`
+	Balance money.Foreign `json:"balance"`
+}
+
+// StatementCurrencyTotals مانده و جمع‌های صورت‌حساب به واحد ارز
+type StatementCurrencyTotals struct {
+	OpeningBalance money.Foreign `json:"openingBalance"`
+	TotalDebit     money.Foreign `json:"totalDebit"`
+	TotalCredit    money.Foreign `json:"totalCredit"`
+	ClosingBalance money.Foreign `json:"closingBalance"`
+}
+
+type AccountStatement struct {
+	AccountKind    string       `json:"accountKind"`
+	AccountID      uint         `json:"accountId"`
+	CurrencyID     *uint        `json:"currencyId,omitempty"`
+	From           string       `json:"from"`
+	To             string       `json:"to"`
+	OpeningBalance money.Amount `json:"openingBalance"`
+	TotalDebit     money.Amount `json:"totalDebit"`
+	TotalCredit    money.Amount `json:"totalCredit"`
+	ClosingBalance money.Amount `json:"closingBalance"`
+	// Currency مانده و جمع‌های ارزی؛ فقط وقتی CurrencyID داده شده باشد.
+	Currency *StatementCurrencyTotals `json:"currency,omitempty"`
+	Lines    []StatementLine          `json:"lines"`
+}
+
+// LedgerLineWithEntry سطر دفتر به همراه مشخصات سند آن برای گزارش گردش حساب
+type LedgerLineWithEntry struct {
+	LedgerLine
+	PostedAt  time.Time
+	DocType   string
+	DocID     uint
+	Reference string
+}
+
+// IsBalanced reports whether the entry has at least two lines and balances in both Toman and gold grams.
+func (e *LedgerEntry) IsBalanced() bool {
+	if len(e.Lines) < 2 {
+		return false
+	}
+	var debit, credit money.Amount
+	var goldDebit, goldCredit money.Weight
+	for _, l := range e.Lines {
+		if l.Debit.IsNegative() || l.Credit.IsNegative() || l.GoldDebit.IsNegative() || l.GoldCredit.IsNegative() {
+			return false
+		}
+		debit = debit.Add(l.Debit)
+		credit = credit.Add(l.Credit)
+		goldDebit = goldDebit.Add(l.GoldDebit)
+		goldCredit = goldCredit.Add(l.GoldCredit)
+	}
+	if debit.IsZero() && goldDebit.IsZero() {
+		return false
+	}
+	return debit.Cmp(credit) == 0 && goldDebit.Cmp(goldCredit) == 0
+}
+
+func (e *LedgerEntry) BeforeUpdate(tx *gorm.DB) error { return ErrLedgerImmutable }
+
+func (e *LedgerEntry) BeforeDelete(tx *gorm.DB) error { return ErrLedgerImmutable }
+
+func (l *LedgerLine) BeforeUpdate(tx *gorm.DB) error { return ErrLedgerImmutable }
+
+func (l *LedgerLine) BeforeDelete(tx *gorm.DB) error { return ErrLedgerImmutable }
@@ -0,0 +1,40 @@
+package model
+
+import (
+	"testing"
+
+	"common-gold/money"
+)
+
+func TestLedgerEntryIsBalanced(t *testing.T) {
+	toman := func(debit, credit float64) LedgerLine {
+		return LedgerLine{Debit: money.NewAmount(debit), Credit: money.NewAmount(credit)}
+	}
+	gold := func(debit, credit float64) LedgerLine {
+		return LedgerLine{GoldDebit: money.NewWeight(debit), GoldCredit: money.NewWeight(credit)}
+	}
+	tests := []struct {
+		name  string
+		lines []LedgerLine
+		want  bool
+	}{
+		{"balanced toman", []LedgerLine{toman(1000, 0), toman(0, 1000)}, true},
+		{"balanced gold", []LedgerLine{gold(2.5, 0), gold(0, 2.5)}, true},
+		{"balanced toman and gold", []LedgerLine{toman(500, 0), gold(1.25, 0), toman(0, 500), gold(0, 1.25)}, true},
+		{"fractions add up exactly", []LedgerLine{toman(0.1, 0), toman(0.2, 0), toman(0, 0.3)}, true},
+		{"single line", []LedgerLine{toman(1000, 1000)}, false},
+		{"no lines", nil, false},
+		{"toman mismatch", []LedgerLine{toman(1000, 0), toman(0, 999.99)}, false},
+		{"gold mismatch", []LedgerLine{toman(100, 0), gold(1, 0), toman(0, 100), gold(0, 1.001)}, false},
+		{"all zero", []LedgerLine{toman(0, 0), toman(0, 0)}, false},
+		{"negative amount", []LedgerLine{toman(-100, 0), toman(0, -100)}, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			e := &LedgerEntry{Lines: tt.lines}
+			if got := e.IsBalanced(); got != tt.want {
+				t.Errorf("IsBalanced() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
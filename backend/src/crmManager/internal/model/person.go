@@ -3,6 +3,8 @@ package model
 import (
 	"time"
 
+	"common-gold/money"
+
 	"gorm.io/gorm"
 )
 
@@ -42,12 +44,12 @@ type Customer struct {
 	LastActivityDate *time.Time `json:"lastActivityDate,omitempty"`
 	InternalNotes    *string    `json:"internalNotes,omitempty" gorm:"type:text"`
 
-	InitialBalanceToman float64 `json:"initialBalanceToman" gorm:"default:0"`
-	InitialBalanceGold  float64 `json:"initialBalanceGold" gorm:"default:0"`
+	InitialBalanceToman money.Amount `json:"initialBalanceToman" gorm:"not null;default:0"`
+	InitialBalanceGold  money.Weight `json:"initialBalanceGold" gorm:"not null;default:0"`
 
-	GoldRateType        *string  `json:"goldRateType,omitempty" gorm:"size:50"`
-	DefaultGoldUnit     *string  `json:"defaultGoldUnit,omitempty" gorm:"size:50"`
-	DefaultGoldUnitRate *float64 `json:"defaultGoldUnitRate,omitempty"`
+	GoldRateType        *string       `json:"goldRateType,omitempty" gorm:"size:50"`
+	DefaultGoldUnit     *string       `json:"defaultGoldUnit,omitempty" gorm:"size:50"`
+	DefaultGoldUnitRate *money.Amount `json:"defaultGoldUnitRate,omitempty"`
 
 	BankAccounts      []CusCard `gorm:"foreignKey:PersonID"`
 	CustomerTypes     []CusType `gorm:"many2many:customer_customer_types;"`
@@ -97,6 +99,8 @@ type PaymentTerm struct {
 type Employee struct {
 	gorm.Model
 	Name string `json:"name" gorm:"not null"`
+	// UserRef شناسه UUID کاربر همین کارمند در profileManager؛ JWT و اعلان‌ها کاربر را با آن می‌شناسند.
+	UserRef *string `json:"userRef,omitempty" gorm:"type:uuid;uniqueIndex"`
 }
 
 type PersonPrelabel struct {
@@ -130,11 +134,11 @@ type CreateCustomerRequest struct {
 	Sabt          string `json:"sabt"`
 	TaxID         string `json:"taxID"`
 
-	InitialBalanceToman float64 `json:"initialBalanceToman"`
-	InitialBalanceGold  float64 `json:"initialBalanceGold"`
+	InitialBalanceToman money.Amount `json:"initialBalanceToman"`
+	InitialBalanceGold  money.Weight `json:"initialBalanceGold"`
 
-	GoldRateType        string  `json:"goldRateType"`
-	DefaultGoldUnit     string  `json:"defaultGoldUnit"`
-	DefaultGoldUnitRate float64 `json:"defaultGoldUnitRate"`
-	CustomerCategory    string  `json:"customerCategory"`
+	GoldRateType        string       `json:"goldRateType"`
+	DefaultGoldUnit     string       `json:"defaultGoldUnit"`
+	DefaultGoldUnitRate money.Amount `json:"defaultGoldUnitRate"`
+	CustomerCategory    string       `json:"customerCategory"`
 }
@@ -0,0 +1,94 @@
+package model
+
+import (
+	"time"
+
+	"common-gold/money"
+)
+
+// AccountTotalRow جمع گردش یک حساب معین در یک بازه
+type AccountTotalRow struct {
+	AccountCode string
+	Debit       money.Amount
+	Credit      money.Amount
+	GoldDebit   money.Weight
+	GoldCredit  money.Weight
+}
+
+// ReportFilter پارامترهای گزارش‌های مالی؛ To انحصاری است (روز بعد از پایان بازه).
+// با GoldRate بزرگ‌تر از صفر، مانده وزنی هر حساب با همین نرخ (تومان به ازای هر گرم) به مبلغ ریالی افزوده می‌شود.
+type ReportFilter struct {
+	BIDID    uint
+	From     time.Time
+	To       time.Time
+	GoldRate money.Amount
+	// تعداد ستون تراز آزمایشی: ۲ (فقط مانده) یا ۴ (گردش و مانده)
+	Columns int
+	// سطح حساب‌ها در گزارش: group، general یا subsidiary
+	Level string
+}
+
+type TrialBalanceRow struct {
+	AccountCode        string       `json:"accountCode"`
+	AccountName        string       `json:"accountName"`
+	Category           string       `json:"category,omitempty"`
+	TurnoverDebit      money.Amount `json:"turnoverDebit"`
+	TurnoverCredit     money.Amount `json:"turnoverCredit"`
+	BalanceDebit       money.Amount `json:"balanceDebit"`
+	BalanceCredit      money.Amount `json:"balanceCredit"`
+	GoldTurnoverDebit  money.Weight `json:"goldTurnoverDebit"`
+	GoldTurnoverCredit money.Weight `json:"goldTurnoverCredit"`
+	// مانده وزنی؛ مثبت بدهکار و منفی بستانکار
+	GoldBalance money.Weight `json:"goldBalance"`
+}
+
+// TrialBalance تراز آزمایشی؛ مانده شامل گردش پیش از ابتدای بازه نیز هست و اسناد بستن سال در آن لحاظ نمی‌شوند.
+type TrialBalance struct {
+	From     string            `json:"from"`
+	To       string            `json:"to"`
+	Columns  int               `json:"columns"`
+	Level    string            `json:"level"`
+	GoldRate money.Amount      `json:"goldRate"`
+	Rows     []TrialBalanceRow `json:"rows"`
+	Totals   TrialBalanceRow   `json:"totals"`
+}
+
+// FinancialStatementLine سطر سود و زیان یا ترازنامه؛ مبلغ بر اساس ماهیت بخش (درآمد، هزینه، دارایی، ...) مثبت است.
+type FinancialStatementLine struct {
+	AccountCode string       `json:"accountCode"`
+	AccountName string       `json:"accountName"`
+	Amount      money.Amount `json:"amount"`
+	Gold        money.Weight `json:"gold"`
+}
+
+type ProfitLoss struct {
+	From             string                   `json:"from"`
+	To               string                   `json:"to"`
+	GoldRate         money.Amount             `json:"goldRate"`
+	Revenues         []FinancialStatementLine `json:"revenues"`
+	Expenses         []FinancialStatementLine `json:"expenses"`
+	TotalRevenue     money.Amount             `json:"totalRevenue"`
+	TotalExpense     money.Amount             `json:"totalExpense"`
+	NetProfit        money.Amount             `json:"netProfit"`
+	TotalRevenueGold money.Weight             `json:"totalRevenueGold"`
+	TotalExpenseGold money.Weight             `json:"totalExpenseGold"`
+	NetProfitGold    money.Weight             `json:"netProfitGold"`
+}
+
+// BalanceSheet ترازنامه در پایان بازه؛ سود (زیان) بسته‌نشده سال جاری جزو حقوق صاحبان سرمایه نمایش داده می‌شود.
+type BalanceSheet struct {
+	From                          string                   `json:"from"`
+	To                            string                   `json:"to"`
+	GoldRate                      money.Amount             `json:"goldRate"`
+	Assets                        []FinancialStatementLine `json:"assets"`
+	Liabilities                   []FinancialStatementLine `json:"liabilities"`
+	Equity                        []FinancialStatementLine `json:"equity"`
+	CurrentProfit                 money.Amount             `json:"currentProfit"`
+	CurrentProfitGold             money.Weight             `json:"currentProfitGold"`
+	TotalAssets                   money.Amount             `json:"totalAssets"`
+	TotalLiabilities              money.Amount             `json:"totalLiabilities"`
+	TotalEquity                   money.Amount             `json:"totalEquity"`
+	TotalLiabilitiesAndEquity     money.Amount             `json:"totalLiabilitiesAndEquity"`
+	TotalAssetsGold               money.Weight             `json:"totalAssetsGold"`
+	TotalLiabilitiesAndEquityGold money.Weight             `json:"totalLiabilitiesAndEquityGold"`
+}
@@ -0,0 +1,174 @@
+package model
+
+import (
+	"time"
+
+	"common-gold/money"
+
+	"gorm.io/gorm"
+)
+
+const (
+	SaleInvoiceIssued = "issued"
+	SaleInvoiceVoid   = "void"
+)
+
+// کدهای حساب معین فروش در سرفصل پیش‌فرض؛ این حساب‌ها نوع سیستمی ندارند و سطرهایشان با LedgerAccountGeneral ثبت می‌شود.
+const (
+	AccountCodeInventory   = "1107"
+	AccountCodeSales       = "4101"
+	AccountCodeLaborIncome = "4103"
+	AccountCodeCOGS        = "5101"
+)
+
+// SaleInvoice فاکتور فروش؛ SalespersonID شناسه کارمند فروشنده است (مانند Customer.AssignedEmployeeID).
+type SaleInvoice struct {
+	gorm.Model
+
+	BIDID         uint      `json:"bidId" gorm:"column:bid_id;not null;index;uniqueIndex:idx_sale_invoice_number"`
+	Number        string    `json:"number" gorm:"not null;size:50;uniqueIndex:idx_sale_invoice_number"`
+	Date          time.Time `json:"date" gorm:"not null;index"`
+	DateJalali    string    `json:"dateJalali" gorm:"size:10"`
+	CustomerID    uint      `json:"customerId" gorm:"not null;index"`
+	SalespersonID *uint     `json:"salespersonId,omitempty" gorm:"index"`
+	Description   *string   `json:"description,omitempty" gorm:"type:text"`
+
+	// جمع سطرها: وزن به گرم و مبالغ به تومان
+	TotalWeight money.Weight `json:"totalWeight" gorm:"not null;default:0"`
+	GoldAmount  money.Amount `json:"goldAmount" gorm:"not null;default:0"`
+	LaborFee    money.Amount `json:"laborFee" gorm:"not null;default:0"`
+	Profit      money.Amount `json:"profit" gorm:"not null;default:0"`
+	Cost        money.Amount `json:"cost" gorm:"not null;default:0"`
+	// مالیات بر ارزش افزوده طبق قاعده مالیاتی معتبر در تاریخ فاکتور؛ Total شامل آن است
+	TaxRuleID *uint        `json:"taxRuleId,omitempty"`
+	TaxAmount money.Amount `json:"taxAmount" gorm:"not null;default:0"`
+	Total     money.Amount `json:"total" gorm:"not null;default:0"`
+	// فاکتور ارزی: مبالغ به تومان ثبت می‌شوند و CurrencyTotal معادل ارزی Total با نرخ روز فاکتور است
+	CurrencyID    *uint         `json:"currencyId,omitempty" gorm:"index"`
+	ExchangeRate  money.Rate    `json:"exchangeRate,omitzero" gorm:"not null;default:0"`
+	CurrencyTotal money.Foreign `json:"currencyTotal,omitzero" gorm:"not null;default:0"`
+
+	Lines         []SaleInvoiceLine `json:"lines" gorm:"foreignKey:InvoiceID"`
+	LedgerEntryID *uint             `json:"ledgerEntryId,omitempty"`
+
+	Status     string     `json:"status" gorm:"not null;size:20;index"`
+	VoidedAt   *time.Time `json:"voidedAt,omitempty"`
+	VoidedBy   string     `json:"voidedBy,omitempty" gorm:"size:100"`
+	VoidReason *string    `json:"voidReason,omitempty" gorm:"type:text"`
+	CreatedBy  string     `json:"createdBy" gorm:"size:100"`
+}
+
+// SaleInvoiceLine سطر فاکتور؛ Amount = وزن × نرخ طلا + اجرت + سود (بدون مالیات). Cost بهای تمام‌شده سطر برای محاسبه حاشیه سود است.
+type SaleInvoiceLine struct {
+	gorm.Model
+
+	InvoiceID uint  `json:"invoiceId" gorm:"not null;index"`
+	ItemID    *uint `json:"itemId,omitempty" gorm:"index"`
+	// امانت‌گذار کالای امانی؛ بهای تمام‌شده این سطر به حساب او بستانکار می‌شود
+	ConsignorID *uint        `json:"consignorId,omitempty"`
+	Description string       `json:"description" gorm:"not null;size:255"`
+	Category    string       `json:"category" gorm:"size:100;index"`
+	Weight      money.Weight `json:"weight" gorm:"not null;default:0"`
+	GoldRate    money.Amount `json:"goldRate" gorm:"not null;default:0"`
+	GoldAmount  money.Amount `json:"goldAmount" gorm:"not null;default:0"`
+	LaborFee    money.Amount `json:"laborFee" gorm:"not null;default:0"`
+	Profit      money.Amount `json:"profit" gorm:"not null;default:0"`
+	Cost        money.Amount `json:"cost" gorm:"not null;default:0"`
+	Amount      money.Amount `json:"amount" gorm:"not null;default:0"`
+	// نرخ مالیات به درصد
+	TaxRate   float64      `json:"taxRate" gorm:"not null;default:0"`
+	TaxAmount money.Amount `json:"taxAmount" gorm:"not null;default:0"`
+}
+
+// SaleInvoiceLineRequest با ItemID، شرح، دسته‌بندی، وزن و بهای تمام‌شده خالی از کالای موجودی پر می‌شوند.
+type SaleInvoiceLineRequest struct {
+	ItemID      *uint        `json:"itemId"`
+	Description string       `json:"description"`
+	Category    string       `json:"category"`
+	Weight      money.Weight `json:"weight"`
+	GoldRate    money.Amount `json:"goldRate"`
+	LaborFee    money.Amount `json:"laborFee"`
+	Profit      money.Amount `json:"profit"`
+	Cost        money.Amount `json:"cost"`
+}
+
+type CreateSaleInvoiceRequest struct {
+	BIDID         uint                     `json:"bidId" validate:"required"`
+	Date          string                   `json:"date" validate:"required"`
+	CustomerID    uint                     `json:"customerId" validate:"required"`
+	SalespersonID *uint                    `json:"salespersonId"`
+	CurrencyID    *uint                    `json:"currencyId"`
+	Description   string                   `json:"description"`
+	Lines         []SaleInvoiceLineRequest `json:"lines" validate:"required"`
+}
+
+type VoidSaleInvoiceRequest struct {
+	Reason string `json:"reason" validate:"required"`
+	Date   string `json:"date"`
+}
+
+type SaleInvoiceFilter struct {
+	BIDID         uint
+	CustomerID    uint
+	SalespersonID *uint
+	Status        string
+	From          *time.Time
+	To            *time.Time
+}
+
+// گروه‌بندی گزارش فروش
+const (
+	SalesGroupDay         = "day"
+	SalesGroupWeek        = "week"
+	SalesGroupMonth       = "month"
+	SalesGroupCategory    = "category"
+	SalesGroupSalesperson = "salesperson"
+	SalesGroupCustomer    = "customer"
+)
+
+// SalesReportFilter بازه From تا To (انحصاری)؛ SalespersonID گزارش را به فاکتورهای یک فروشنده محدود می‌کند.
+type SalesReportFilter struct {
+	BIDID         uint
+	From          time.Time
+	To            time.Time
+	GroupBy       string
+	SalespersonID *uint
+}
+
+// SaleLineFact سطر فاکتور صادرشده همراه با اطلاعات سربرگ و نام‌ها برای گروه‌بندی گزارش فروش
+type SaleLineFact struct {
+	InvoiceID       uint
+	Date            time.Time
+	CustomerID      uint
+	CustomerName    string
+	SalespersonID   *uint
+	SalespersonName string
+	Category        string
+	Weight          money.Weight
+	LaborFee        money.Amount
+	Cost            money.Amount
+	Amount          money.Amount
+}
+
+// SalesReportRow درآمد جمع مبلغ فروش است و حاشیه سود = درآمد - بهای تمام‌شده.
+type SalesReportRow struct {
+	Key           string       `json:"key"`
+	Label         string       `json:"label"`
+	InvoiceCount  int          `json:"invoiceCount"`
+	Weight        money.Weight `json:"weight"`
+	Revenue       money.Amount `json:"revenue"`
+	LaborFee      money.Amount `json:"laborFee"`
+	Cost          money.Amount `json:"cost"`
+	Margin        money.Amount `json:"margin"`
+	MarginPercent float64      `json:"marginPercent"`
+}
+
+type SalesReport struct {
+	From    string `json:"from"`
+	To      string `json:"to"`
+	GroupBy string `json:"groupBy"`
+	// شناسه فروشنده‌ای که گزارش به او محدود شده است
+	SalespersonID *uint            `json:"salespersonId,omitempty"`
+	Rows          []SalesReportRow `json:"rows"`
+	Totals        SalesReportRow   `json:"totals"`
+}
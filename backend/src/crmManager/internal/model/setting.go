@@ -0,0 +1,31 @@
+package model
+
+import (
+	"time"
+
+	"common-gold/money"
+
+	"gorm.io/gorm"
+)
+
+// BusinessSetting تنظیمات مالی هر کسب‌وکار؛ در نبود ردیف، مقادیر پیش‌فرض اعمال می‌شوند.
+type BusinessSetting struct {
+	gorm.Model
+
+	BIDID uint `json:"bidId" gorm:"column:bid_id;not null;uniqueIndex"`
+	// انتقال از صندوق و بانک بیشتر از موجودی آن‌ها مجاز نیست
+	RequireSufficientBalance bool `json:"requireSufficientBalance" gorm:"not null;default:false"`
+	// نرخ روز هر گرم طلای ۱۸ عیار به تومان برای ارزش‌گذاری موجودی
+	GoldRate          money.Amount `json:"goldRate" gorm:"not null;default:0"`
+	GoldRateUpdatedAt *time.Time   `json:"goldRateUpdatedAt,omitempty"`
+}
+
+type UpdateBusinessSettingRequest struct {
+	BIDID                    uint  `json:"bidId" validate:"required"`
+	RequireSufficientBalance *bool `json:"requireSufficientBalance"`
+}
+
+type UpdateGoldRateRequest struct {
+	BIDID uint         `json:"bidId" validate:"required"`
+	Rate  money.Amount `json:"rate" validate:"required"`
+}
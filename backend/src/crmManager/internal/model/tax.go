@@ -0,0 +1,180 @@
+package model
+
+import (
+	"time"
+
+	"common-gold/money"
+
+	"github.com/shopspring/decimal"
+	"gorm.io/gorm"
+)
+
+const (
+	DefaultTaxMeasureUnit = "164" // گرم
+	TaxMemoryIDLength     = 6
+)
+
+// AccountCodeVATPayable حساب مالیات بر ارزش افزوده پرداختنی در سرفصل پیش‌فرض
+const AccountCodeVATPayable = "2102"
+
+// وضعیت ارسال صورتحساب به سامانه مودیان
+const (
+	TaxSubmissionPending = "pending" // ثبت شده ولی هنوز شماره پیگیری نگرفته (خطای ارسال)
+	TaxSubmissionSent    = "sent"    // در صف پردازش سامانه
+	TaxSubmissionSuccess = "success"
+	TaxSubmissionFailed  = "failed" // رد شده توسط سامانه
+)
+
+// TaxSetting اطلاعات حافظه مالیاتی هر کسب‌وکار؛ کلید خصوصی هرگز در پاسخ‌ها برگردانده نمی‌شود.
+type TaxSetting struct {
+	gorm.Model
+
+	BIDID        uint   `json:"bidId" gorm:"column:bid_id;not null;uniqueIndex"`
+	Enabled      bool   `json:"enabled" gorm:"not null;default:false"`
+	TaxMemoryID  string `json:"taxMemoryId" gorm:"size:6"`
+	EconomicCode string `json:"economicCode" gorm:"size:20"`
+	PrivateKey   string `json:"-" gorm:"type:text"`
+	// شناسه کالا/خدمت (sstid) و واحد اندازه‌گیری سطرهای طلا
+	ProductID   string `json:"productId" gorm:"size:20"`
+	MeasureUnit string `json:"measureUnit" gorm:"size:10"`
+
+	HasPrivateKey bool `json:"hasPrivateKey" gorm:"-"`
+}
+
+type UpdateTaxSettingRequest struct {
+	BIDID        uint    `json:"bidId" validate:"required"`
+	Enabled      *bool   `json:"enabled"`
+	TaxMemoryID  *string `json:"taxMemoryId"`
+	EconomicCode *string `json:"economicCode"`
+	PrivateKey   *string `json:"privateKey"`
+	ProductID    *string `json:"productId"`
+	MeasureUnit  *string `json:"measureUnit"`
+}
+
+// TaxSubmission هر ارسال صورتحساب فروش؛ شناسه همین ردیف سریال داخلی شماره مالیاتی است.
+// برای هر فاکتور و موضوع فقط یک ارسال رد‌نشده مجاز است تا درخواست‌های همزمان دو شماره مالیاتی نگیرند.
+type TaxSubmission struct {
+	gorm.Model
+
+	BIDID           uint       `json:"bidId" gorm:"column:bid_id;not null;index"`
+	SaleInvoiceID   uint       `json:"saleInvoiceId" gorm:"not null;index;uniqueIndex:idx_tax_submission_active,where:status <> 'failed'"`
+	Subject         int        `json:"subject" gorm:"not null;uniqueIndex:idx_tax_submission_active"`
+	TaxID           string     `json:"taxId" gorm:"size:22;index"`
+	ReferenceTaxID  string     `json:"referenceTaxId,omitempty" gorm:"size:22"`
+	UID             string     `json:"uid" gorm:"size:64"`
+	ReferenceNumber string     `json:"referenceNumber,omitempty" gorm:"size:64;index"`
+	Status          string     `json:"status" gorm:"not null;size:20;index"`
+	Payload         string     `json:"payload,omitempty" gorm:"type:text"`
+	Errors          *string    `json:"errors,omitempty" gorm:"type:text"`
+	Attempts        int        `json:"attempts" gorm:"not null;default:0"`
+	SubmittedAt     *time.Time `json:"submittedAt,omitempty"`
+	CheckedAt       *time.Time `json:"checkedAt,omitempty"`
+	CreatedBy       string     `json:"createdBy" gorm:"size:100"`
+}
+
+type TaxSubmissionFilter struct {
+	BIDID         uint
+	SaleInvoiceID uint
+	Status        string
+}
+
+// TaxRule قاعده مالیات بر ارزش افزوده از EffectiveFrom تا EffectiveTo (انحصاری، خالی یعنی بدون پایان).
+// فقط اجزای علامت‌خورده مشمول مالیات هستند؛ در فروش طلا معمولا اجرت، سود و حق‌العمل و نه ارزش خود طلا.
+type TaxRule struct {
+	gorm.Model
+
+	BIDID         uint       `json:"bidId" gorm:"column:bid_id;not null;index"`
+	Name          string     `json:"name" gorm:"not null;size:100"`
+	Rate          float64    `json:"rate" gorm:"not null"`
+	EffectiveFrom time.Time  `json:"effectiveFrom" gorm:"not null;index"`
+	EffectiveTo   *time.Time `json:"effectiveTo,omitempty"`
+
+	TaxGold       bool `json:"taxGold" gorm:"not null;default:false"`
+	TaxLabor      bool `json:"taxLabor" gorm:"not null;default:true"`
+	TaxProfit     bool `json:"taxProfit" gorm:"not null;default:true"`
+	TaxCommission bool `json:"taxCommission" gorm:"not null;default:true"`
+
+	// مشتریان دارای هر یک از این انواع از مالیات معاف هستند
+	ExemptCustomerTypes []CusType `json:"exemptCustomerTypes" gorm:"many2many:tax_rule_exempt_types;"`
+	CreatedBy           string    `json:"createdBy" gorm:"size:100"`
+}
+
+type TaxRuleRequest struct {
+	BIDID                 uint    `json:"bidId" validate:"required"`
+	Name                  string  `json:"name" validate:"required"`
+	Rate                  float64 `json:"rate"`
+	EffectiveFrom         string  `json:"effectiveFrom" validate:"required"`
+	EffectiveTo           string  `json:"effectiveTo"`
+	TaxGold               bool    `json:"taxGold"`
+	TaxLabor              bool    `json:"taxLabor"`
+	TaxProfit             bool    `json:"taxProfit"`
+	TaxCommission         bool    `json:"taxCommission"`
+	ExemptCustomerTypeIDs []uint  `json:"exemptCustomerTypeIds"`
+}
+
+// TaxComponents اجزای مبلغ یک سطر یا فاکتور به تومان
+type TaxComponents struct {
+	Gold       money.Amount `json:"gold"`
+	Labor      money.Amount `json:"labor"`
+	Profit     money.Amount `json:"profit"`
+	Commission money.Amount `json:"commission"`
+}
+
+// TaxAssessment نتیجه اعمال قاعده؛ بدون قاعده یا برای مشتری معاف، نرخ و مبلغ صفر است.
+type TaxAssessment struct {
+	RuleID *uint        `json:"ruleId,omitempty"`
+	Rate   float64      `json:"rate"`
+	Base   money.Amount `json:"base"`
+	Amount money.Amount `json:"amount"`
+	Exempt bool         `json:"exempt"`
+}
+
+type TaxPreviewRequest struct {
+	BIDID      uint   `json:"bidId" validate:"required"`
+	Date       string `json:"date"`
+	CustomerID uint   `json:"customerId"`
+	TaxComponents
+}
+
+// Covers قاعده در تاریخ at معتبر است.
+func (r *TaxRule) Covers(at time.Time) bool {
+	return !at.Before(r.EffectiveFrom) && (r.EffectiveTo == nil || at.Before(*r.EffectiveTo))
+}
+
+// Exempts مشتری با یکی از انواع معاف، مشمول این قاعده نیست.
+func (r *TaxRule) Exempts(customerTypeIDs []uint) bool {
+	for _, t := range r.ExemptCustomerTypes {
+		for _, id := range customerTypeIDs {
+			if t.ID == id {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// Assess مالیات اجزای مشمول را به نرخ قاعده حساب و به تومان گرد می‌کند.
+func (r *TaxRule) Assess(c TaxComponents, customerTypeIDs []uint) TaxAssessment {
+	if r == nil {
+		return TaxAssessment{}
+	}
+	id := r.ID
+	if r.Exempts(customerTypeIDs) {
+		return TaxAssessment{RuleID: &id, Exempt: true}
+	}
+	var base money.Amount
+	if r.TaxGold {
+		base = base.Add(c.Gold)
+	}
+	if r.TaxLabor {
+		base = base.Add(c.Labor)
+	}
+	if r.TaxProfit {
+		base = base.Add(c.Profit)
+	}
+	if r.TaxCommission {
+		base = base.Add(c.Commission)
+	}
+	tax := base.Decimal().Mul(decimal.NewFromFloat(r.Rate)).Div(decimal.NewFromInt(100)).Round(0)
+	return TaxAssessment{RuleID: &id, Rate: r.Rate, Base: base, Amount: money.AmountFromDecimal(tax)}
+}
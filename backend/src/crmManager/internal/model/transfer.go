@@ -0,0 +1,67 @@
+package model
+
+import (
+	"time"
+
+	"common-gold/money"
+
+	"gorm.io/gorm"
+)
+
+const (
+	TransferStatusPosted   = "posted"
+	TransferStatusReversed = "reversed"
+)
+
+// Transfer انتقال وجه بین صندوق، بانک و اشخاص؛ کارمزد از مبدا کسر می‌شود.
+type Transfer struct {
+	gorm.Model
+
+	BIDID      uint      `json:"bidId" gorm:"column:bid_id;not null;index"`
+	Number     string    `json:"number" gorm:"not null;size:50;uniqueIndex"`
+	Date       time.Time `json:"date" gorm:"not null;index"`
+	DateJalali string    `json:"dateJalali" gorm:"size:10"`
+
+	FromKind string `json:"fromKind" gorm:"not null;size:20"`
+	FromID   uint   `json:"fromId" gorm:"not null"`
+	ToKind   string `json:"toKind" gorm:"not null;size:20"`
+	ToID     uint   `json:"toId" gorm:"not null"`
+
+	Amount       money.Amount `json:"amount" gorm:"not null"`
+	Fee          money.Amount `json:"fee" gorm:"not null;default:0"`
+	TrackingCode *string      `json:"trackingCode,omitempty" gorm:"size:100"`
+	Description  *string      `json:"description,omitempty" gorm:"type:text"`
+
+	Status         string     `json:"status" gorm:"not null;size:20;index"`
+	ReversedAt     *time.Time `json:"reversedAt,omitempty"`
+	ReversedBy     string     `json:"reversedBy,omitempty" gorm:"size:100"`
+	ReversalReason *string    `json:"reversalReason,omitempty" gorm:"type:text"`
+	CreatedBy      string     `json:"createdBy" gorm:"size:100"`
+}
+
+type TransferFilter struct {
+	BIDID  uint
+	Kind   string
+	ID     uint
+	Status string
+	From   *time.Time
+	To     *time.Time
+}
+
+type CreateTransferRequest struct {
+	BIDID        uint         `json:"bidId" validate:"required"`
+	Date         string       `json:"date" validate:"required"`
+	FromKind     string       `json:"fromKind" validate:"required"`
+	FromID       uint         `json:"fromId" validate:"required"`
+	ToKind       string       `json:"toKind" validate:"required"`
+	ToID         uint         `json:"toId" validate:"required"`
+	Amount       money.Amount `json:"amount" validate:"required"`
+	Fee          money.Amount `json:"fee"`
+	TrackingCode string       `json:"trackingCode"`
+	Description  string       `json:"description"`
+}
+
+type ReverseTransferRequest struct {
+	Date   string `json:"date"`
+	Reason string `json:"reason" validate:"required"`
+}
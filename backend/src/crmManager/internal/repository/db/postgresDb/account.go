@@ -0,0 +1,208 @@
+package postgresDb
+
+import (
+	"context"
+	"crm-gold/internal/model"
+	"crm-gold/internal/repository/repo"
+	"errors"
+	"fmt"
+
+	"go.uber.org/zap"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+var ErrUnknownLedgerAccount = errors.New("ledger line has no matching account in the chart of accounts")
+
+type accountRepositoryImpl struct {
+	db     *gorm.DB
+	logger *zap.Logger
+}
+
+func NewAccountRepository(db *gorm.DB, logger *zap.Logger) (repo.AccountRepo, error) {
+	if db == nil {
+		return nil, errors.New("database connection cannot be nil for AccountRepository")
+	}
+	if logger == nil {
+		return nil, errors.New("logger cannot be nil for AccountRepository")
+	}
+	return &accountRepositoryImpl{
+		db:     db,
+		logger: logger,
+	}, nil
+}
+
+// seedDefaultChart سرفصل پیش‌فرض را برای کسب‌وکار می‌سازد؛ اجرای همزمان یا تکراری حساب تکراری ایجاد نمی‌کند.
+func seedDefaultChart(tx *gorm.DB, bidID uint) error {
+	var count int64
+	if err := tx.Model(&model.Account{}).Where("bid_id = ? AND is_system = ?", bidID, true).Count(&count).Error; err != nil {
+		return fmt.Errorf("failed to check chart of accounts: %w", err)
+	}
+	if count >= int64(len(model.DefaultChartOfAccounts)) {
+		return nil
+	}
+	ids := make(map[string]uint, len(model.DefaultChartOfAccounts))
+	for _, tmpl := range model.DefaultChartOfAccounts {
+		account := tmpl
+		account.BIDID = bidID
+		account.IsSystem = true
+		account.IsActive = true
+		if parentCode := parentAccountCode(account.Code); parentCode != "" {
+			parentID := ids[parentCode]
+			account.ParentID = &parentID
+		}
+		if err := tx.Clauses(clause.OnConflict{DoNothing: true}).Create(&account).Error; err != nil {
+			return fmt.Errorf("failed to seed account %s: %w", account.Code, err)
+		}
+		if account.ID == 0 {
+			var existing model.Account
+			if err := tx.Where("bid_id = ? AND code = ?", bidID, account.Code).First(&existing).Error; err != nil {
+				return fmt.Errorf("failed to load seeded account %s: %w", account.Code, err)
+			}
+			account.ID = existing.ID
+		}
+		ids[account.Code] = account.ID
+	}
+	return nil
+}
+
+// parentAccountCode کد حساب کل برای معین و کد گروه برای کل
+func parentAccountCode(code string) string {
+	switch {
+	case len(code) > 2:
+		return code[:2]
+	case len(code) == 2:
+		return code[:1]
+	default:
+		return ""
+	}
+}
+
+// resolveAccountCodes کد حساب معین سطرهایی را که فقط نوع حساب دارند از سرفصل همان کسب‌وکار تعیین می‌کند.
+func resolveAccountCodes(tx *gorm.DB, entry *model.LedgerEntry) error {
+	var kinds []string
+	for _, l := range entry.Lines {
+		if l.AccountCode == "" {
+			kinds = append(kinds, l.AccountKind)
+		}
+	}
+	if len(kinds) == 0 {
+		return nil
+	}
+	codes, err := accountCodesByKind(tx, entry.BIDID, kinds)
+	if err != nil {
+		return err
+	}
+	if len(codes) < len(uniqueStrings(kinds)) {
+		if err := seedDefaultChart(tx, entry.BIDID); err != nil {
+			return err
+		}
+		if codes, err = accountCodesByKind(tx, entry.BIDID, kinds); err != nil {
+			return err
+		}
+	}
+	for i := range entry.Lines {
+		if entry.Lines[i].AccountCode != "" {
+			continue
+		}
+		code, ok := codes[entry.Lines[i].AccountKind]
+		if !ok {
+			return fmt.Errorf("%w: %s", ErrUnknownLedgerAccount, entry.Lines[i].AccountKind)
+		}
+		entry.Lines[i].AccountCode = code
+	}
+	return nil
+}
+
+func accountCodesByKind(tx *gorm.DB, bidID uint, kinds []string) (map[string]string, error) {
+	var accounts []model.Account
+	if err := tx.Select("code", "kind").Where("bid_id = ? AND kind IN ?", bidID, kinds).Find(&accounts).Error; err != nil {
+		return nil, fmt.Errorf("failed to resolve ledger accounts: %w", err)
+	}
+	codes := make(map[string]string, len(accounts))
+	for _, a := range accounts {
+		codes[a.Kind] = a.Code
+	}
+	return codes, nil
+}
+
+func uniqueStrings(values []string) map[string]struct{} {
+	set := make(map[string]struct{}, len(values))
+	for _, v := range values {
+		set[v] = struct{}{}
+	}
+	return set
+}
+
+// backfillLedgerAccountCodes سطرهای ثبت‌شده پیش از ایجاد سرفصل حساب‌ها را به حساب معین متناظر وصل می‌کند.
+// با SQL خام اجرا می‌شود چون سطرهای دفتر از طریق مدل قابل ویرایش نیستند.
+func backfillLedgerAccountCodes(db *gorm.DB) error {
+	var bidIDs []uint
+	if err := db.Model(&model.LedgerLine{}).Where("account_code IS NULL OR account_code = ''").Distinct().Pluck("bid_id", &bidIDs).Error; err != nil {
+		return fmt.Errorf("failed to find ledger lines without account code: %w", err)
+	}
+	for _, bidID := range bidIDs {
+		if err := seedDefaultChart(db, bidID); err != nil {
+			return err
+		}
+	}
+	if len(bidIDs) == 0 {
+		return nil
+	}
+	return db.Exec(`UPDATE ledger_lines SET account_code = accounts.code FROM accounts
+		WHERE accounts.bid_id = ledger_lines.bid_id AND accounts.kind = ledger_lines.account_kind
+		AND accounts.deleted_at IS NULL AND (ledger_lines.account_code IS NULL OR ledger_lines.account_code = '')`).Error
+}
+
+func (r *accountRepositoryImpl) EnsureDefaultChart(ctx context.Context, bidID uint) error {
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		return seedDefaultChart(tx, bidID)
+	})
+	if err != nil {
+		r.logger.Error("failed to seed chart of accounts", zap.Uint("bid_id", bidID), zap.Error(err))
+	}
+	return err
+}
+
+func (r *accountRepositoryImpl) ListAccounts(ctx context.Context, bidID uint) ([]model.Account, error) {
+	var accounts []model.Account
+	if err := r.db.WithContext(ctx).Where("bid_id = ?", bidID).Order("code").Find(&accounts).Error; err != nil {
+		r.logger.Error("failed to list accounts", zap.Uint("bid_id", bidID), zap.Error(err))
+		return nil, err
+	}
+	return accounts, nil
+}
+
+func (r *accountRepositoryImpl) GetAccountByID(ctx context.Context, id uint) (*model.Account, error) {
+	var account model.Account
+	if err := r.db.WithContext(ctx).Scopes(businessScope(ctx)).First(&account, id).Error; err != nil {
+		r.logger.Error("failed to get account by ID", zap.Uint("id", id), zap.Error(err))
+		return nil, err
+	}
+	return &account, nil
+}
+
+func (r *accountRepositoryImpl) GetAccountByCode(ctx context.Context, bidID uint, code string) (*model.Account, error) {
+	var account model.Account
+	if err := r.db.WithContext(ctx).Where("bid_id = ? AND code = ?", bidID, code).First(&account).Error; err != nil {
+		r.logger.Error("failed to get account by code", zap.Uint("bid_id", bidID), zap.String("code", code), zap.Error(err))
+		return nil, err
+	}
+	return &account, nil
+}
+
+func (r *accountRepositoryImpl) CreateAccount(ctx context.Context, account *model.Account) (*model.Account, error) {
+	if err := r.db.WithContext(ctx).Create(account).Error; err != nil {
+		r.logger.Error("failed to save account", zap.String("code", account.Code), zap.Error(err))
+		return nil, err
+	}
+	return account, nil
+}
+
+func (r *accountRepositoryImpl) UpdateAccount(ctx context.Context, account *model.Account) error {
+	if err := r.db.WithContext(ctx).Save(account).Error; err != nil {
+		r.logger.Error("failed to update account", zap.Uint("id", account.ID), zap.Error(err))
+		return err
+	}
+	return nil
+}
@@ -0,0 +1,123 @@
+package postgresDb
+
+import (
+	"context"
+	"crm-gold/internal/model"
+	"crm-gold/internal/repository/repo"
+	"errors"
+	"strings"
+
+	"common-gold/audit"
+
+	"go.uber.org/zap"
+	"gorm.io/gorm"
+)
+
+// auditChainLockKey کلید قفل advisory که درج‌های هم‌زمان را پشت سر هم می‌اندازد تا هر رویداد به آخرین هش زنجیر شود.
+const auditChainLockKey = 7204118
+
+type auditRepositoryImpl struct {
+	db     *gorm.DB
+	logger *zap.Logger
+}
+
+func NewAuditRepository(db *gorm.DB, logger *zap.Logger) (repo.AuditRepo, error) {
+	if db == nil {
+		return nil, errors.New("database connection cannot be nil for AuditRepository")
+	}
+	if logger == nil {
+		return nil, errors.New("logger cannot be nil for AuditRepository")
+	}
+	return &auditRepositoryImpl{
+		db:     db,
+		logger: logger,
+	}, nil
+}
+
+func (r *auditRepositoryImpl) AppendAuditLog(ctx context.Context, log *model.AuditLog) error {
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Exec("SELECT pg_advisory_xact_lock(?)", auditChainLockKey).Error; err != nil {
+			return err
+		}
+		var last model.AuditLog
+		err := tx.Order("seq DESC").Limit(1).Find(&last).Error
+		if err != nil {
+			return err
+		}
+		log.Seq = last.Seq + 1
+		log.PrevHash = last.Hash
+		log.Hash = audit.Hash(log.PrevHash, log.Seq, log.Event())
+		return tx.Create(log).Error
+	})
+	if err != nil {
+		r.logger.Error("failed to append audit log", zap.String("action", log.Action), zap.Error(err))
+		return err
+	}
+	return nil
+}
+
+func (r *auditRepositoryImpl) ListAuditLogs(ctx context.Context, filter model.AuditFilter) ([]model.AuditLog, int64, error) {
+	query := r.db.WithContext(ctx).Model(&model.AuditLog{})
+	if filter.BIDID != nil {
+		query = query.Where("bid_id = ?", *filter.BIDID)
+	}
+	if filter.ActorID != "" {
+		query = query.Where("actor_id = ?", filter.ActorID)
+	}
+	if filter.Action != "" {
+		query = query.Where("action = ?", filter.Action)
+	}
+	if filter.Category != "" {
+		query = query.Where("category = ?", filter.Category)
+	}
+	if filter.TargetType != "" {
+		query = query.Where("target_type = ?", filter.TargetType)
+	}
+	if filter.TargetID != "" {
+		query = query.Where("target_id = ?", filter.TargetID)
+	}
+	if filter.CorrelationID != "" {
+		query = query.Where("correlation_id = ?", filter.CorrelationID)
+	}
+	if q := strings.TrimSpace(filter.Query); q != "" {
+		like := "%" + escapeLike(q) + "%"
+		query = query.Where("description ILIKE ? OR actor_name ILIKE ? OR ip_address ILIKE ? OR target_id ILIKE ?", like, like, like, like)
+	}
+	if filter.From != nil {
+		query = query.Where("occurred_at >= ?", *filter.From)
+	}
+	if filter.To != nil {
+		query = query.Where("occurred_at < ?", *filter.To)
+	}
+
+	var total int64
+	if err := query.Count(&total).Error; err != nil {
+		r.logger.Error("failed to count audit logs", zap.Error(err))
+		return nil, 0, err
+	}
+	var logs []model.AuditLog
+	err := query.Order("seq DESC").
+		Offset((filter.Page - 1) * filter.PageSize).
+		Limit(filter.PageSize).
+		Find(&logs).Error
+	if err != nil {
+		r.logger.Error("failed to list audit logs", zap.Error(err))
+		return nil, 0, err
+	}
+	return logs, total, nil
+}
+
+func (r *auditRepositoryImpl) ScanAuditLogs(ctx context.Context, afterSeq uint64, limit int) ([]model.AuditLog, error) {
+	var logs []model.AuditLog
+	err := r.db.WithContext(ctx).Where("seq > ?", afterSeq).Order("seq ASC").Limit(limit).Find(&logs).Error
+	if err != nil {
+		r.logger.Error("failed to scan audit logs", zap.Uint64("after_seq", afterSeq), zap.Error(err))
+		return nil, err
+	}
+	return logs, nil
+}
+
+// escapeLike نویسه‌های ویژه LIKE را در عبارت جستجوی کاربر خنثی می‌کند.
+func escapeLike(s string) string {
+	return strings.NewReplacer(`\`, `\\`, `%`, `\%`, `_`, `\_`).Replace(s)
+}
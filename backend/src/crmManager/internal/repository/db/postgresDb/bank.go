@@ -0,0 +1,252 @@
+package postgresDb
+
+import (
+	"context"
+	"crm-gold/internal/model"
+	"crm-gold/internal/repository/repo"
+	"errors"
+	"fmt"
+	"time"
+
+	"go.uber.org/zap"
+	"gorm.io/gorm"
+)
+
+var (
+	ErrStaleStatementLine    = errors.New("bank statement line status has changed")
+	ErrReconciledTransaction = errors.New("bank transaction is already reconciled with a statement line")
+)
+
+type bankRepositoryImpl struct {
+	db     *gorm.DB
+	logger *zap.Logger
+}
+
+func NewBankRepository(db *gorm.DB, logger *zap.Logger) (repo.BankRepo, error) {
+	if db == nil {
+		return nil, errors.New("database connection cannot be nil for BankRepository")
+	}
+	if logger == nil {
+		return nil, errors.New("logger cannot be nil for BankRepository")
+	}
+	return &bankRepositoryImpl{
+		db:     db,
+		logger: logger,
+	}, nil
+}
+
+func (r *bankRepositoryImpl) CreateBankAccount(ctx context.Context, account *model.BankAccount, openingEntry *model.LedgerEntry) (*model.BankAccount, error) {
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Create(account).Error; err != nil {
+			return fmt.Errorf("failed to save bank account: %w", err)
+		}
+		if openingEntry == nil {
+			return nil
+		}
+		openingEntry.DocID = account.ID
+		if err := postLedgerEntry(tx, openingEntry); err != nil {
+			return err
+		}
+		account.LedgerEntryID = &openingEntry.ID
+		return tx.Model(account).Update("ledger_entry_id", openingEntry.ID).Error
+	})
+	if err != nil {
+		r.logger.Error("Failed to create bank account", zap.String("account_num", account.AccountNum), zap.Error(err))
+		return nil, err
+	}
+	r.logger.Info("Bank account saved successfully to database.", zap.Uint("bank_account_id", account.ID))
+	return account, nil
+}
+
+func (r *bankRepositoryImpl) GetBankAccountByID(ctx context.Context, id uint) (*model.BankAccount, error) {
+	var account model.BankAccount
+	if err := r.db.WithContext(ctx).Scopes(businessScope(ctx)).Preload("POSTerminals").First(&account, id).Error; err != nil {
+		r.logger.Error("failed to get bank account by ID", zap.Uint("id", id), zap.Error(err))
+		return nil, err
+	}
+	return &account, nil
+}
+
+func (r *bankRepositoryImpl) ListBankAccounts(ctx context.Context, bidID uint) ([]model.BankAccount, error) {
+	var accounts []model.BankAccount
+	q := r.db.WithContext(ctx).Preload("POSTerminals")
+	if bidID != 0 {
+		q = q.Where("bid_id = ?", bidID)
+	}
+	if err := q.Order("code").Find(&accounts).Error; err != nil {
+		r.logger.Error("failed to list bank accounts", zap.Error(err))
+		return nil, err
+	}
+	return accounts, nil
+}
+
+func (r *bankRepositoryImpl) AddPOSTerminal(ctx context.Context, terminal *model.POSTerminal) (*model.POSTerminal, error) {
+	if err := r.db.WithContext(ctx).Create(terminal).Error; err != nil {
+		r.logger.Error("failed to save pos terminal", zap.String("terminal_id", terminal.TerminalID), zap.Error(err))
+		return nil, err
+	}
+	return terminal, nil
+}
+
+func (r *bankRepositoryImpl) CreateBankTransaction(ctx context.Context, txn *model.BankTransaction, entry *model.LedgerEntry) (*model.BankTransaction, error) {
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Create(txn).Error; err != nil {
+			return fmt.Errorf("failed to save bank transaction: %w", err)
+		}
+		entry.DocID = txn.ID
+		if err := postLedgerEntry(tx, entry); err != nil {
+			return err
+		}
+		txn.LedgerEntryID = &entry.ID
+		return tx.Model(txn).Update("ledger_entry_id", entry.ID).Error
+	})
+	if err != nil {
+		r.logger.Error("Failed to create bank transaction", zap.Uint("bank_account_id", txn.BankAccountID), zap.Error(err))
+		return nil, err
+	}
+	return txn, nil
+}
+
+func (r *bankRepositoryImpl) GetBankTransactionByID(ctx context.Context, id uint) (*model.BankTransaction, error) {
+	var txn model.BankTransaction
+	if err := r.db.WithContext(ctx).Scopes(businessScope(ctx)).First(&txn, id).Error; err != nil {
+		r.logger.Error("failed to get bank transaction by ID", zap.Uint("id", id), zap.Error(err))
+		return nil, err
+	}
+	return &txn, nil
+}
+
+func (r *bankRepositoryImpl) ListBankTransactions(ctx context.Context, accountID uint, from, to *time.Time) ([]model.BankTransaction, error) {
+	var txns []model.BankTransaction
+	q := r.db.WithContext(ctx).Where("bank_account_id = ?", accountID)
+	if from != nil {
+		q = q.Where("date >= ?", *from)
+	}
+	if to != nil {
+		q = q.Where("date < ?", *to)
+	}
+	if err := q.Order("date, id").Find(&txns).Error; err != nil {
+		r.logger.Error("failed to list bank transactions", zap.Uint("bank_account_id", accountID), zap.Error(err))
+		return nil, err
+	}
+	return txns, nil
+}
+
+// DeleteBankTransaction سندی که با صورتحساب بانک تطبیق خورده باشد قابل حذف نیست؛ ابتدا باید تطبیق لغو شود.
+func (r *bankRepositoryImpl) DeleteBankTransaction(ctx context.Context, txn *model.BankTransaction, reversal *model.LedgerEntry) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if txn.LedgerEntryID != nil {
+			var matched int64
+			err := tx.Model(&model.BankStatementLine{}).
+				Joins("JOIN ledger_lines ON ledger_lines.id = bank_statement_lines.matched_ledger_line_id").
+				Where("ledger_lines.entry_id = ?", *txn.LedgerEntryID).
+				Count(&matched).Error
+			if err != nil {
+				return fmt.Errorf("failed to check reconciliation state: %w", err)
+			}
+			if matched > 0 {
+				return ErrReconciledTransaction
+			}
+		}
+		if err := postLedgerEntry(tx, reversal); err != nil {
+			return err
+		}
+		if err := tx.Delete(txn).Error; err != nil {
+			return fmt.Errorf("failed to delete bank transaction: %w", err)
+		}
+		return nil
+	})
+}
+
+// candidatesQuery سطرهای دفتر حساب بانک که تطبیق نخورده‌اند؛ اسناد برگشتی، اسناد معکوس‌شده، مانده افتتاحیه و اسناد انتقال سال مالی در صورتحساب بانک دیده نمی‌شوند.
+func (r *bankRepositoryImpl) candidatesQuery(ctx context.Context, accountID uint) *gorm.DB {
+	return r.db.WithContext(ctx).Table("ledger_lines").
+		Joins("JOIN ledger_entries ON ledger_entries.id = ledger_lines.entry_id AND ledger_entries.deleted_at IS NULL").
+		Where("ledger_lines.deleted_at IS NULL").
+		Where("ledger_lines.account_kind = ? AND ledger_lines.account_id = ?", model.LedgerAccountBank, accountID).
+		Where("ledger_entries.doc_type NOT IN ?", []string{model.LedgerDocBankOpening, model.LedgerDocYearOpening, model.LedgerDocYearClosing}).
+		Where("ledger_entries.reversal_of_id IS NULL").
+		Where("NOT EXISTS (SELECT 1 FROM ledger_entries rev WHERE rev.reversal_of_id = ledger_entries.id AND rev.deleted_at IS NULL)").
+		Where("NOT EXISTS (SELECT 1 FROM bank_statement_lines bsl WHERE bsl.matched_ledger_line_id = ledger_lines.id AND bsl.deleted_at IS NULL)").
+		Select("ledger_lines.id AS ledger_line_id, ledger_lines.entry_id, ledger_entries.posted_at, ledger_entries.doc_type, ledger_entries.doc_id, ledger_entries.reference, ledger_lines.description, ledger_lines.debit, ledger_lines.credit")
+}
+
+func (r *bankRepositoryImpl) ListReconciliationCandidates(ctx context.Context, accountID uint, from, to time.Time) ([]model.ReconciliationCandidate, error) {
+	var candidates []model.ReconciliationCandidate
+	err := r.candidatesQuery(ctx, accountID).
+		Where("ledger_entries.posted_at >= ? AND ledger_entries.posted_at < ?", from, to).
+		Order("ledger_entries.posted_at, ledger_lines.id").
+		Scan(&candidates).Error
+	if err != nil {
+		r.logger.Error("failed to list reconciliation candidates", zap.Uint("bank_account_id", accountID), zap.Error(err))
+		return nil, err
+	}
+	return candidates, nil
+}
+
+func (r *bankRepositoryImpl) GetReconciliationCandidate(ctx context.Context, accountID, ledgerLineID uint) (*model.ReconciliationCandidate, error) {
+	var candidates []model.ReconciliationCandidate
+	err := r.candidatesQuery(ctx, accountID).
+		Where("ledger_lines.id = ?", ledgerLineID).
+		Limit(1).Scan(&candidates).Error
+	if err != nil {
+		r.logger.Error("failed to get reconciliation candidate", zap.Uint("ledger_line_id", ledgerLineID), zap.Error(err))
+		return nil, err
+	}
+	if len(candidates) == 0 {
+		return nil, gorm.ErrRecordNotFound
+	}
+	return &candidates[0], nil
+}
+
+func (r *bankRepositoryImpl) SaveStatementImport(ctx context.Context, imp *model.BankStatementImport) (*model.BankStatementImport, error) {
+	if err := r.db.WithContext(ctx).Create(imp).Error; err != nil {
+		r.logger.Error("Failed to save bank statement import", zap.Uint("bank_account_id", imp.BankAccountID), zap.Error(err))
+		return nil, err
+	}
+	r.logger.Info("Bank statement imported.", zap.Uint("import_id", imp.ID), zap.Int("lines", imp.LineCount), zap.Int("matched", imp.MatchedCount))
+	return imp, nil
+}
+
+func (r *bankRepositoryImpl) GetStatementLineByID(ctx context.Context, id uint) (*model.BankStatementLine, error) {
+	var line model.BankStatementLine
+	if err := r.db.WithContext(ctx).Scopes(bankAccountBusinessScope(ctx)).First(&line, id).Error; err != nil {
+		r.logger.Error("failed to get bank statement line by ID", zap.Uint("id", id), zap.Error(err))
+		return nil, err
+	}
+	return &line, nil
+}
+
+func (r *bankRepositoryImpl) ListStatementLines(ctx context.Context, accountID uint, status string) ([]model.BankStatementLine, error) {
+	var lines []model.BankStatementLine
+	q := r.db.WithContext(ctx).Where("bank_account_id = ?", accountID)
+	if status != "" {
+		q = q.Where("status = ?", status)
+	}
+	if err := q.Order("date, id").Find(&lines).Error; err != nil {
+		r.logger.Error("failed to list bank statement lines", zap.Uint("bank_account_id", accountID), zap.Error(err))
+		return nil, err
+	}
+	return lines, nil
+}
+
+// UpdateStatementLineMatch فقط در صورتی اعمال می‌شود که وضعیت ردیف از زمان خواندن تغییر نکرده باشد.
+func (r *bankRepositoryImpl) UpdateStatementLineMatch(ctx context.Context, line *model.BankStatementLine, fromStatus string) error {
+	res := r.db.WithContext(ctx).Model(&model.BankStatementLine{}).
+		Where("id = ? AND status = ?", line.ID, fromStatus).
+		Updates(map[string]interface{}{
+			"status":                 line.Status,
+			"matched_ledger_line_id": line.MatchedLedgerLineID,
+			"matched_at":             line.MatchedAt,
+			"matched_by":             line.MatchedBy,
+			"note":                   line.Note,
+		})
+	if res.Error != nil {
+		r.logger.Error("failed to update bank statement line", zap.Uint("id", line.ID), zap.Error(res.Error))
+		return res.Error
+	}
+	if res.RowsAffected == 0 {
+		return ErrStaleStatementLine
+	}
+	return nil
+}
@@ -0,0 +1,287 @@
+package postgresDb
+
+import (
+	"context"
+	"crm-gold/internal/model"
+	"crm-gold/internal/repository/repo"
+	"errors"
+	"fmt"
+	"time"
+
+	"go.uber.org/zap"
+	"gorm.io/gorm"
+)
+
+var (
+	ErrCenterVoucherReversed = errors.New("center voucher is already reversed")
+	ErrRecurringAlreadyRun   = errors.New("recurring expense has already been run for this date")
+)
+
+type centerRepositoryImpl struct {
+	db     *gorm.DB
+	logger *zap.Logger
+}
+
+func NewCenterRepository(db *gorm.DB, logger *zap.Logger) (repo.CenterRepo, error) {
+	if db == nil {
+		return nil, errors.New("database connection cannot be nil for CenterRepository")
+	}
+	if logger == nil {
+		return nil, errors.New("logger cannot be nil for CenterRepository")
+	}
+	return &centerRepositoryImpl{
+		db:     db,
+		logger: logger,
+	}, nil
+}
+
+// CreateCenter مسیر مرکز بعد از درج و با شناسه آن ساخته می‌شود.
+func (r *centerRepositoryImpl) CreateCenter(ctx context.Context, center *model.FinanceCenter) (*model.FinanceCenter, error) {
+	parentPath := center.Path
+	center.Path = "/"
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Create(center).Error; err != nil {
+			return fmt.Errorf("failed to save center: %w", err)
+		}
+		center.Path = fmt.Sprintf("%s%d/", parentPath, center.ID)
+		return tx.Model(center).Update("path", center.Path).Error
+	})
+	if err != nil {
+		r.logger.Error("Failed to create finance center", zap.String("code", center.Code), zap.Error(err))
+		return nil, err
+	}
+	return center, nil
+}
+
+func (r *centerRepositoryImpl) GetCenterByID(ctx context.Context, id uint) (*model.FinanceCenter, error) {
+	var center model.FinanceCenter
+	if err := r.db.WithContext(ctx).Scopes(businessScope(ctx)).First(&center, id).Error; err != nil {
+		r.logger.Error("failed to get finance center by ID", zap.Uint("id", id), zap.Error(err))
+		return nil, err
+	}
+	return &center, nil
+}
+
+func (r *centerRepositoryImpl) ListCenters(ctx context.Context, bidID uint, centerType string) ([]model.FinanceCenter, error) {
+	var centers []model.FinanceCenter
+	q := r.db.WithContext(ctx)
+	if bidID != 0 {
+		q = q.Where("bid_id = ?", bidID)
+	}
+	if centerType != "" {
+		q = q.Where("type = ?", centerType)
+	}
+	if err := q.Order("path").Find(&centers).Error; err != nil {
+		r.logger.Error("failed to list finance centers", zap.Error(err))
+		return nil, err
+	}
+	return centers, nil
+}
+
+// UpdateCenter در صورت جابه‌جایی مرکز، مسیر همه زیرشاخه‌ها هم اصلاح می‌شود.
+func (r *centerRepositoryImpl) UpdateCenter(ctx context.Context, center *model.FinanceCenter, oldPath string) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Save(center).Error; err != nil {
+			return fmt.Errorf("failed to update center: %w", err)
+		}
+		if oldPath == center.Path {
+			return nil
+		}
+		err := tx.Model(&model.FinanceCenter{}).
+			Where("bid_id = ? AND path LIKE ? AND id <> ?", center.BIDID, oldPath+"%", center.ID).
+			Update("path", gorm.Expr("? || SUBSTRING(path FROM ?)", center.Path, len(oldPath)+1)).Error
+		if err != nil {
+			return fmt.Errorf("failed to move center descendants: %w", err)
+		}
+		return nil
+	})
+}
+
+func (r *centerRepositoryImpl) CreateCenterVoucher(ctx context.Context, voucher *model.CenterVoucher, entry *model.LedgerEntry) (*model.CenterVoucher, error) {
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		return createCenterVoucherTx(tx, voucher, entry)
+	})
+	if err != nil {
+		r.logger.Error("Failed to create center voucher", zap.Uint("center_id", voucher.CenterID), zap.Error(err))
+		return nil, err
+	}
+	return voucher, nil
+}
+
+func createCenterVoucherTx(tx *gorm.DB, voucher *model.CenterVoucher, entry *model.LedgerEntry) error {
+	if voucher.AccountKind == model.LedgerAccountFund {
+		if err := ensureFundOpen(tx, voucher.AccountID, voucher.Date); err != nil {
+			return err
+		}
+	}
+	if err := tx.Create(voucher).Error; err != nil {
+		return fmt.Errorf("failed to save center voucher: %w", err)
+	}
+	entry.DocID = voucher.ID
+	if err := postLedgerEntry(tx, entry); err != nil {
+		return err
+	}
+	voucher.LedgerEntryID = &entry.ID
+	return tx.Model(voucher).Update("ledger_entry_id", entry.ID).Error
+}
+
+func (r *centerRepositoryImpl) GetCenterVoucherByID(ctx context.Context, id uint) (*model.CenterVoucher, error) {
+	var voucher model.CenterVoucher
+	if err := r.db.WithContext(ctx).Scopes(businessScope(ctx)).First(&voucher, id).Error; err != nil {
+		r.logger.Error("failed to get center voucher by ID", zap.Uint("id", id), zap.Error(err))
+		return nil, err
+	}
+	return &voucher, nil
+}
+
+func (r *centerRepositoryImpl) ListCenterVouchers(ctx context.Context, filter model.CenterVoucherFilter) ([]model.CenterVoucher, error) {
+	var vouchers []model.CenterVoucher
+	q := r.db.WithContext(ctx)
+	if filter.BIDID != 0 {
+		q = q.Where("bid_id = ?", filter.BIDID)
+	}
+	if filter.CenterID != 0 {
+		q = q.Where("center_id = ?", filter.CenterID)
+	}
+	if filter.CenterType != "" {
+		q = q.Where("center_type = ?", filter.CenterType)
+	}
+	if filter.Status != "" {
+		q = q.Where("status = ?", filter.Status)
+	}
+	if filter.From != nil {
+		q = q.Where("date >= ?", *filter.From)
+	}
+	if filter.To != nil {
+		q = q.Where("date < ?", *filter.To)
+	}
+	if err := q.Order("date DESC, id DESC").Find(&vouchers).Error; err != nil {
+		r.logger.Error("failed to list center vouchers", zap.Error(err))
+		return nil, err
+	}
+	return vouchers, nil
+}
+
+func (r *centerRepositoryImpl) ReverseCenterVoucher(ctx context.Context, voucher *model.CenterVoucher, reversal *model.LedgerEntry) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if voucher.AccountKind == model.LedgerAccountFund {
+			if err := ensureFundOpen(tx, voucher.AccountID, *voucher.ReversedAt); err != nil {
+				return err
+			}
+		}
+		res := tx.Model(&model.CenterVoucher{}).
+			Where("id = ? AND status = ?", voucher.ID, model.CenterVoucherPosted).
+			Updates(map[string]interface{}{
+				"status":          model.CenterVoucherReversed,
+				"reversed_at":     voucher.ReversedAt,
+				"reversed_by":     voucher.ReversedBy,
+				"reversal_reason": voucher.ReversalReason,
+			})
+		if res.Error != nil {
+			return fmt.Errorf("failed to update center voucher status: %w", res.Error)
+		}
+		if res.RowsAffected == 0 {
+			return ErrCenterVoucherReversed
+		}
+		return postLedgerEntry(tx, reversal)
+	})
+}
+
+// CenterTotals از دفتر محاسبه می‌شود تا اسناد برگشتی خودبه‌خود اثر سند اصلی را خنثی کنند.
+func (r *centerRepositoryImpl) CenterTotals(ctx context.Context, bidID uint, accountKind string, from, to time.Time) ([]repo.CenterAmount, error) {
+	sumExpr := "SUM(ledger_lines.debit - ledger_lines.credit)"
+	if accountKind == model.LedgerAccountIncome {
+		sumExpr = "SUM(ledger_lines.credit - ledger_lines.debit)"
+	}
+	var totals []repo.CenterAmount
+	err := r.db.WithContext(ctx).Table("ledger_lines").
+		Joins("JOIN ledger_entries ON ledger_entries.id = ledger_lines.entry_id AND ledger_entries.deleted_at IS NULL").
+		Where("ledger_lines.deleted_at IS NULL AND ledger_lines.bid_id = ?", bidID).
+		Where("ledger_lines.account_kind = ? AND ledger_lines.account_id IS NOT NULL", accountKind).
+		Where("ledger_entries.posted_at >= ? AND ledger_entries.posted_at < ?", from, to).
+		Where("ledger_entries.doc_type <> ?", model.LedgerDocProfitClosing).
+		Group("ledger_lines.account_id").
+		Select("ledger_lines.account_id AS center_id, " + sumExpr + " AS amount").
+		Scan(&totals).Error
+	if err != nil {
+		r.logger.Error("failed to compute center totals", zap.Uint("bid_id", bidID), zap.String("account_kind", accountKind), zap.Error(err))
+		return nil, err
+	}
+	return totals, nil
+}
+
+func (r *centerRepositoryImpl) CreateRecurringExpense(ctx context.Context, rec *model.RecurringExpense) (*model.RecurringExpense, error) {
+	if err := r.db.WithContext(ctx).Create(rec).Error; err != nil {
+		r.logger.Error("failed to save recurring expense", zap.String("title", rec.Title), zap.Error(err))
+		return nil, err
+	}
+	return rec, nil
+}
+
+func (r *centerRepositoryImpl) GetRecurringExpenseByID(ctx context.Context, id uint) (*model.RecurringExpense, error) {
+	var rec model.RecurringExpense
+	if err := r.db.WithContext(ctx).Scopes(businessScope(ctx)).First(&rec, id).Error; err != nil {
+		r.logger.Error("failed to get recurring expense by ID", zap.Uint("id", id), zap.Error(err))
+		return nil, err
+	}
+	return &rec, nil
+}
+
+func (r *centerRepositoryImpl) ListRecurringExpenses(ctx context.Context, bidID uint) ([]model.RecurringExpense, error) {
+	var recs []model.RecurringExpense
+	q := r.db.WithContext(ctx)
+	if bidID != 0 {
+		q = q.Where("bid_id = ?", bidID)
+	}
+	if err := q.Order("next_run_date, id").Find(&recs).Error; err != nil {
+		r.logger.Error("failed to list recurring expenses", zap.Error(err))
+		return nil, err
+	}
+	return recs, nil
+}
+
+func (r *centerRepositoryImpl) UpdateRecurringExpense(ctx context.Context, rec *model.RecurringExpense) error {
+	if err := r.db.WithContext(ctx).Save(rec).Error; err != nil {
+		r.logger.Error("failed to update recurring expense", zap.Uint("id", rec.ID), zap.Error(err))
+		return err
+	}
+	return nil
+}
+
+func (r *centerRepositoryImpl) ListDueRecurringExpenses(ctx context.Context, asOf time.Time) ([]model.RecurringExpense, error) {
+	var recs []model.RecurringExpense
+	err := r.db.WithContext(ctx).
+		Where("is_active = ? AND next_run_date <= ?", true, asOf).
+		Where("end_date IS NULL OR next_run_date <= end_date").
+		Order("next_run_date, id").Find(&recs).Error
+	if err != nil {
+		r.logger.Error("failed to list due recurring expenses", zap.Error(err))
+		return nil, err
+	}
+	return recs, nil
+}
+
+// RunRecurringExpense سند یک نوبت را ثبت و نوبت بعدی را جلو می‌برد؛ اجرای همزمان یک نوبت با شرط next_run_date رد می‌شود.
+func (r *centerRepositoryImpl) RunRecurringExpense(ctx context.Context, rec *model.RecurringExpense, voucher *model.CenterVoucher, entry *model.LedgerEntry, nextRun time.Time) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		now := time.Now()
+		res := tx.Model(&model.RecurringExpense{}).
+			Where("id = ? AND next_run_date = ?", rec.ID, rec.NextRunDate).
+			Updates(map[string]interface{}{"next_run_date": nextRun, "last_run_at": now})
+		if res.Error != nil {
+			return fmt.Errorf("failed to advance recurring expense: %w", res.Error)
+		}
+		if res.RowsAffected == 0 {
+			return ErrRecurringAlreadyRun
+		}
+		if err := createCenterVoucherTx(tx, voucher, entry); err != nil {
+			if IsUniqueViolation(err) {
+				return ErrRecurringAlreadyRun
+			}
+			return err
+		}
+		rec.NextRunDate = nextRun
+		rec.LastRunAt = &now
+		return nil
+	})
+}
@@ -0,0 +1,165 @@
+package postgresDb
+
+import (
+	"context"
+	"crm-gold/internal/model"
+	"crm-gold/internal/repository/repo"
+	"errors"
+	"fmt"
+
+	"go.uber.org/zap"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// ErrStaleChequeStatus یعنی وضعیت چک همزمان توسط درخواست دیگری تغییر کرده است.
+var ErrStaleChequeStatus = errors.New("cheque status was changed concurrently")
+
+type chequeRepositoryImpl struct {
+	db     *gorm.DB
+	logger *zap.Logger
+}
+
+func NewChequeRepository(db *gorm.DB, logger *zap.Logger) (repo.ChequeRepo, error) {
+	if db == nil {
+		return nil, errors.New("database connection cannot be nil for ChequeRepository")
+	}
+	if logger == nil {
+		return nil, errors.New("logger cannot be nil for ChequeRepository")
+	}
+	return &chequeRepositoryImpl{
+		db:     db,
+		logger: logger,
+	}, nil
+}
+
+func (r *chequeRepositoryImpl) CreateCheque(ctx context.Context, cheque *model.Cheque, history *model.ChequeStatusHistory, entry *model.LedgerEntry) (*model.Cheque, error) {
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Create(cheque).Error; err != nil {
+			return fmt.Errorf("failed to save cheque: %w", err)
+		}
+		if entry != nil {
+			entry.DocID = cheque.ID
+			if err := postLedgerEntry(tx, entry); err != nil {
+				return err
+			}
+			history.LedgerEntryID = &entry.ID
+		}
+		history.ChequeID = cheque.ID
+		if err := tx.Create(history).Error; err != nil {
+			return fmt.Errorf("failed to save cheque history: %w", err)
+		}
+		return nil
+	})
+	if err != nil {
+		r.logger.Error("Failed to create cheque", zap.String("sayad_id", cheque.SayadID), zap.Error(err))
+		return nil, err
+	}
+	r.logger.Info("Cheque saved successfully to database.", zap.Uint("cheque_id", cheque.ID))
+	return cheque, nil
+}
+
+func (r *chequeRepositoryImpl) GetChequeByID(ctx context.Context, id uint) (*model.Cheque, error) {
+	var cheque model.Cheque
+	err := r.db.WithContext(ctx).
+		Preload("Person").
+		Preload("EndorsedToPerson").
+		Preload("History", func(db *gorm.DB) *gorm.DB { return db.Order("changed_at, id") }).
+		First(&cheque, id).Error
+	if err != nil {
+		r.logger.Error("failed to get cheque by ID", zap.Uint("id", id), zap.Error(err))
+		return nil, err
+	}
+	return &cheque, nil
+}
+
+func (r *chequeRepositoryImpl) ListCheques(ctx context.Context, filter model.ChequeFilter) ([]model.Cheque, error) {
+	var cheques []model.Cheque
+	q := r.db.WithContext(ctx).Model(&model.Cheque{}).Preload("Person.AssignedEmployee")
+	if filter.BIDID != 0 {
+		q = q.Where("bid_id = ?", filter.BIDID)
+	}
+	if filter.Direction != "" {
+		q = q.Where("direction = ?", filter.Direction)
+	}
+	if filter.Status != "" {
+		q = q.Where("status = ?", filter.Status)
+	}
+	if len(filter.Statuses) > 0 {
+		q = q.Where("status IN ?", filter.Statuses)
+	}
+	if filter.PersonID != 0 {
+		q = q.Where("person_id = ?", filter.PersonID)
+	}
+	if filter.DueFrom != nil {
+		q = q.Where("due_date >= ?", *filter.DueFrom)
+	}
+	if filter.DueTo != nil {
+		q = q.Where("due_date < ?", *filter.DueTo)
+	}
+	if err := q.Order("due_date, id").Find(&cheques).Error; err != nil {
+		r.logger.Error("failed to list cheques", zap.Error(err))
+		return nil, err
+	}
+	return cheques, nil
+}
+
+func (r *chequeRepositoryImpl) ApplyChequeTransition(ctx context.Context, cheque *model.Cheque, fromStatus string, history *model.ChequeStatusHistory, entry *model.LedgerEntry) error {
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		result := tx.Model(&model.Cheque{}).
+			Where("id = ? AND status = ?", cheque.ID, fromStatus).
+			Updates(map[string]interface{}{
+				"status":                cheque.Status,
+				"status_changed_at":     cheque.StatusChangedAt,
+				"endorsed_to_person_id": cheque.EndorsedToPersonID,
+				"deposit_bank_id":       cheque.DepositBankID,
+			})
+		if result.Error != nil {
+			return fmt.Errorf("failed to update cheque status: %w", result.Error)
+		}
+		if result.RowsAffected == 0 {
+			return ErrStaleChequeStatus
+		}
+		if entry != nil {
+			if err := postLedgerEntry(tx, entry); err != nil {
+				return err
+			}
+			history.LedgerEntryID = &entry.ID
+		}
+		if err := tx.Create(history).Error; err != nil {
+			return fmt.Errorf("failed to save cheque history: %w", err)
+		}
+		return nil
+	})
+	if err != nil {
+		r.logger.Error("Failed to apply cheque transition", zap.Uint("cheque_id", cheque.ID),
+			zap.String("from", fromStatus), zap.String("to", cheque.Status), zap.Error(err))
+		return err
+	}
+	return nil
+}
+
+// ClaimChequeAlert returns the alert row for (cheque, kind, due date), creating it if needed.
+func (r *chequeRepositoryImpl) ClaimChequeAlert(ctx context.Context, alert *model.ChequeAlert) (*model.ChequeAlert, error) {
+	err := r.db.WithContext(ctx).Clauses(clause.OnConflict{DoNothing: true}).Create(alert).Error
+	if err != nil {
+		r.logger.Error("failed to claim cheque alert", zap.Uint("cheque_id", alert.ChequeID), zap.String("kind", alert.Kind), zap.Error(err))
+		return nil, err
+	}
+	var existing model.ChequeAlert
+	err = r.db.WithContext(ctx).
+		Where("cheque_id = ? AND kind = ? AND due_date = ?", alert.ChequeID, alert.Kind, alert.DueDate).
+		First(&existing).Error
+	if err != nil {
+		return nil, err
+	}
+	return &existing, nil
+}
+
+func (r *chequeRepositoryImpl) UpdateChequeAlert(ctx context.Context, alert *model.ChequeAlert) error {
+	return r.db.WithContext(ctx).Model(alert).Updates(map[string]interface{}{
+		"recipient_ref": alert.RecipientRef,
+		"sent_at":       alert.SentAt,
+		"last_error":    alert.LastError,
+	}).Error
+}
@@ -0,0 +1,120 @@
+package postgresDb
+
+import (
+	"context"
+	"crm-gold/internal/model"
+	"crm-gold/internal/repository/repo"
+	"errors"
+	"time"
+
+	"go.uber.org/zap"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type currencyRepositoryImpl struct {
+	db     *gorm.DB
+	logger *zap.Logger
+}
+
+func NewCurrencyRepository(db *gorm.DB, logger *zap.Logger) (repo.CurrencyRepo, error) {
+	if db == nil {
+		return nil, errors.New("database connection cannot be nil for CurrencyRepository")
+	}
+	if logger == nil {
+		return nil, errors.New("logger cannot be nil for CurrencyRepository")
+	}
+	return &currencyRepositoryImpl{
+		db:     db,
+		logger: logger,
+	}, nil
+}
+
+func (r *currencyRepositoryImpl) GetCurrencyByID(ctx context.Context, id uint) (*model.Currency, error) {
+	var currency model.Currency
+	if err := r.db.WithContext(ctx).First(&currency, id).Error; err != nil {
+		r.logger.Error("failed to get currency by ID", zap.Uint("id", id), zap.Error(err))
+		return nil, err
+	}
+	return &currency, nil
+}
+
+func (r *currencyRepositoryImpl) ListCurrencies(ctx context.Context) ([]model.Currency, error) {
+	var currencies []model.Currency
+	if err := r.db.WithContext(ctx).Order("id").Find(&currencies).Error; err != nil {
+		r.logger.Error("failed to list currencies", zap.Error(err))
+		return nil, err
+	}
+	return currencies, nil
+}
+
+// SaveExchangeRate برای هر کسب‌وکار، ارز و روز فقط یک نرخ نگه داشته می‌شود و ثبت دوباره آن را جایگزین می‌کند.
+func (r *currencyRepositoryImpl) SaveExchangeRate(ctx context.Context, rate *model.ExchangeRate) error {
+	err := r.db.WithContext(ctx).Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "bid_id"}, {Name: "currency_id"}, {Name: "date"}},
+		DoUpdates: clause.AssignmentColumns([]string{"rate", "date_jalali", "created_by", "updated_at", "deleted_at"}),
+	}).Create(rate).Error
+	if err != nil {
+		r.logger.Error("failed to save exchange rate", zap.Uint("bid_id", rate.BIDID), zap.Uint("currency_id", rate.CurrencyID), zap.Error(err))
+		return err
+	}
+	return nil
+}
+
+func (r *currencyRepositoryImpl) ListExchangeRates(ctx context.Context, filter model.ExchangeRateFilter) ([]model.ExchangeRate, error) {
+	var rates []model.ExchangeRate
+	q := r.db.WithContext(ctx).Preload("Currency").Where("bid_id = ?", filter.BIDID)
+	if filter.CurrencyID != 0 {
+		q = q.Where("currency_id = ?", filter.CurrencyID)
+	}
+	if filter.From != nil {
+		q = q.Where("date >= ?", *filter.From)
+	}
+	if filter.To != nil {
+		q = q.Where("date < ?", *filter.To)
+	}
+	if err := q.Order("date DESC, currency_id").Find(&rates).Error; err != nil {
+		r.logger.Error("failed to list exchange rates", zap.Uint("bid_id", filter.BIDID), zap.Error(err))
+		return nil, err
+	}
+	return rates, nil
+}
+
+// ExchangeRateAt آخرین نرخ ثبت‌شده تا تاریخ at؛ اگر نرخی نباشد nil برمی‌گردد.
+func (r *currencyRepositoryImpl) ExchangeRateAt(ctx context.Context, bidID, currencyID uint, at time.Time) (*model.ExchangeRate, error) {
+	var rate model.ExchangeRate
+	err := r.db.WithContext(ctx).
+		Where("bid_id = ? AND currency_id = ? AND date <= ?", bidID, currencyID, at).
+		Order("date DESC").
+		First(&rate).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, nil
+	}
+	if err != nil {
+		r.logger.Error("failed to find exchange rate", zap.Uint("bid_id", bidID), zap.Uint("currency_id", currencyID), zap.Error(err))
+		return nil, err
+	}
+	return &rate, nil
+}
+
+// CurrencyBalances مانده ارزی و تومانی حساب‌های تفصیلی دارای سطر ارزی پیش از تاریخ before.
+func (r *currencyRepositoryImpl) CurrencyBalances(ctx context.Context, bidID uint, before time.Time) ([]model.CurrencyBalanceRow, error) {
+	var rows []model.CurrencyBalanceRow
+	err := r.db.WithContext(ctx).Table("ledger_lines").
+		Joins("JOIN ledger_entries ON ledger_entries.id = ledger_lines.entry_id AND ledger_entries.deleted_at IS NULL").
+		Where("ledger_lines.deleted_at IS NULL").
+		Where("ledger_lines.bid_id = ? AND ledger_lines.currency_id IS NOT NULL AND ledger_lines.account_id IS NOT NULL", bidID).
+		Where("ledger_entries.posted_at < ?", before).
+		Group("ledger_lines.account_kind, ledger_lines.account_id, ledger_lines.currency_id").
+		Having("SUM(CASE WHEN ledger_lines.debit > 0 THEN ledger_lines.currency_amount ELSE -ledger_lines.currency_amount END) <> 0").
+		Order("ledger_lines.currency_id, ledger_lines.account_kind, ledger_lines.account_id").
+		Select(`ledger_lines.account_kind, ledger_lines.account_id, ledger_lines.currency_id,
+			SUM(CASE WHEN ledger_lines.debit > 0 THEN ledger_lines.currency_amount ELSE -ledger_lines.currency_amount END) AS foreign_balance,
+			SUM(ledger_lines.debit - ledger_lines.credit) AS book_balance`).
+		Scan(&rows).Error
+	if err != nil {
+		r.logger.Error("failed to compute currency balances", zap.Uint("bid_id", bidID), zap.Error(err))
+		return nil, err
+	}
+	return rows, nil
+}
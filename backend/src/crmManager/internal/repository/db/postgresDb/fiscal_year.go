@@ -0,0 +1,305 @@
+package postgresDb
+
+import (
+	"context"
+	"crm-gold/internal/model"
+	"crm-gold/internal/repository/repo"
+	"crm-gold/internal/utils"
+	"errors"
+	"fmt"
+	"time"
+
+	"go.uber.org/zap"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+var (
+	ErrFiscalYearOverlap  = errors.New("fiscal year overlaps an existing fiscal year")
+	ErrStaleFiscalYear    = errors.New("fiscal year state has changed, please retry")
+	ErrNextFiscalYearOpen = errors.New("the following fiscal year must be open")
+)
+
+type fiscalYearRepositoryImpl struct {
+	db     *gorm.DB
+	logger *zap.Logger
+}
+
+func NewFiscalYearRepository(db *gorm.DB, logger *zap.Logger) (repo.FiscalYearRepo, error) {
+	if db == nil {
+		return nil, errors.New("database connection cannot be nil for FiscalYearRepository")
+	}
+	if logger == nil {
+		return nil, errors.New("logger cannot be nil for FiscalYearRepository")
+	}
+	return &fiscalYearRepositoryImpl{
+		db:     db,
+		logger: logger,
+	}, nil
+}
+
+// ensureFiscalYearOpen از ثبت سند در سال مالی بسته جلوگیری می‌کند؛ قفل اشتراکی مانع بستن همزمان سال در حین ثبت سند می‌شود.
+// تاریخ‌هایی که در هیچ سال مالی تعریف‌شده‌ای نیستند آزادند.
+func ensureFiscalYearOpen(tx *gorm.DB, bidID uint, postedAt time.Time) error {
+	day := utils.StartOfDay(postedAt)
+	var years []model.FiscalYear
+	err := tx.Clauses(clause.Locking{Strength: "SHARE"}).
+		Where("bid_id = ? AND start_date <= ? AND end_date >= ?", bidID, day, day).
+		Limit(1).Find(&years).Error
+	if err != nil {
+		return fmt.Errorf("failed to check fiscal year: %w", err)
+	}
+	if len(years) > 0 && years[0].Status == model.FiscalYearClosed {
+		return fmt.Errorf("%w: %s", model.ErrFiscalYearClosed, years[0].Title)
+	}
+	return nil
+}
+
+func createFiscalYearTx(tx *gorm.DB, fy *model.FiscalYear) error {
+	var overlapping int64
+	err := tx.Model(&model.FiscalYear{}).
+		Where("bid_id = ? AND start_date <= ? AND end_date >= ?", fy.BIDID, fy.EndDate, fy.StartDate).
+		Count(&overlapping).Error
+	if err != nil {
+		return fmt.Errorf("failed to check fiscal year overlap: %w", err)
+	}
+	if overlapping > 0 {
+		return ErrFiscalYearOverlap
+	}
+	if err := tx.Create(fy).Error; err != nil {
+		return fmt.Errorf("failed to save fiscal year: %w", err)
+	}
+	return tx.Create(&model.FiscalYearEvent{
+		FiscalYearID: fy.ID,
+		BIDID:        fy.BIDID,
+		Action:       model.FiscalYearEventCreate,
+		Actor:        fy.CreatedBy,
+	}).Error
+}
+
+func (r *fiscalYearRepositoryImpl) CreateFiscalYear(ctx context.Context, fy *model.FiscalYear, opening *model.LedgerEntry) (*model.FiscalYear, error) {
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := createFiscalYearTx(tx, fy); err != nil {
+			return err
+		}
+		if opening == nil {
+			return nil
+		}
+		opening.DocID = fy.ID
+		if err := postLedgerEntry(tx, opening); err != nil {
+			return err
+		}
+		fy.OpeningEntryID = &opening.ID
+		return tx.Model(fy).Update("opening_entry_id", opening.ID).Error
+	})
+	if err != nil {
+		r.logger.Error("Failed to create fiscal year", zap.Uint("bid_id", fy.BIDID), zap.Int("jalali_year", fy.JalaliYear), zap.Error(err))
+		return nil, err
+	}
+	return fy, nil
+}
+
+func (r *fiscalYearRepositoryImpl) GetFiscalYearByID(ctx context.Context, id uint) (*model.FiscalYear, error) {
+	var fy model.FiscalYear
+	if err := r.db.WithContext(ctx).Scopes(businessScope(ctx)).First(&fy, id).Error; err != nil {
+		r.logger.Error("failed to get fiscal year by ID", zap.Uint("id", id), zap.Error(err))
+		return nil, err
+	}
+	return &fy, nil
+}
+
+func (r *fiscalYearRepositoryImpl) GetFiscalYearByJalaliYear(ctx context.Context, bidID uint, jalaliYear int) (*model.FiscalYear, error) {
+	var fy model.FiscalYear
+	if err := r.db.WithContext(ctx).Where("bid_id = ? AND jalali_year = ?", bidID, jalaliYear).First(&fy).Error; err != nil {
+		return nil, err
+	}
+	return &fy, nil
+}
+
+func (r *fiscalYearRepositoryImpl) ListFiscalYears(ctx context.Context, bidID uint) ([]model.FiscalYear, error) {
+	var years []model.FiscalYear
+	if err := r.db.WithContext(ctx).Where("bid_id = ?", bidID).Order("start_date").Find(&years).Error; err != nil {
+		r.logger.Error("failed to list fiscal years", zap.Uint("bid_id", bidID), zap.Error(err))
+		return nil, err
+	}
+	return years, nil
+}
+
+func (r *fiscalYearRepositoryImpl) ListFiscalYearEvents(ctx context.Context, fiscalYearID uint) ([]model.FiscalYearEvent, error) {
+	var events []model.FiscalYearEvent
+	if err := r.db.WithContext(ctx).Where("fiscal_year_id = ?", fiscalYearID).Order("id").Find(&events).Error; err != nil {
+		r.logger.Error("failed to list fiscal year events", zap.Uint("fiscal_year_id", fiscalYearID), zap.Error(err))
+		return nil, err
+	}
+	return events, nil
+}
+
+func yearEndLinesQuery(db *gorm.DB, bidID uint, before time.Time) *gorm.DB {
+	return db.Table("ledger_lines").
+		Joins("JOIN ledger_entries ON ledger_entries.id = ledger_lines.entry_id AND ledger_entries.deleted_at IS NULL").
+		Where("ledger_lines.deleted_at IS NULL AND ledger_lines.bid_id = ?", bidID).
+		Where("ledger_entries.posted_at < ?", before)
+}
+
+func lastLedgerLineID(db *gorm.DB, bidID uint, before time.Time) (uint, error) {
+	var lastID uint
+	err := yearEndLinesQuery(db, bidID, before).Select("COALESCE(MAX(ledger_lines.id), 0)").Scan(&lastID).Error
+	return lastID, err
+}
+
+// YearEndBalances مانده تجمعی همه حساب‌ها تا پیش از before به همراه شناسه آخرین سطر برای تشخیص تغییر همزمان
+func (r *fiscalYearRepositoryImpl) YearEndBalances(ctx context.Context, bidID uint, before time.Time) ([]model.AccountBalanceRow, uint, error) {
+	db := r.db.WithContext(ctx)
+	lastID, err := lastLedgerLineID(db, bidID, before)
+	if err != nil {
+		r.logger.Error("failed to read last ledger line", zap.Uint("bid_id", bidID), zap.Error(err))
+		return nil, 0, err
+	}
+	var rows []model.AccountBalanceRow
+	err = yearEndLinesQuery(db, bidID, before).
+		Where("ledger_lines.id <= ?", lastID).
+		Group("ledger_lines.account_code, ledger_lines.account_kind, ledger_lines.account_id, ledger_lines.currency_id").
+		Select(`ledger_lines.account_code, ledger_lines.account_kind, ledger_lines.account_id, ledger_lines.currency_id,
+			SUM(ledger_lines.debit - ledger_lines.credit) AS balance,
+			SUM(ledger_lines.gold_debit - ledger_lines.gold_credit) AS gold_balance,
+			SUM(CASE WHEN ledger_lines.debit > 0 THEN ledger_lines.currency_amount ELSE -ledger_lines.currency_amount END) AS currency_balance`).
+		Order("ledger_lines.account_code").
+		Scan(&rows).Error
+	if err != nil {
+		r.logger.Error("failed to compute year end balances", zap.Uint("bid_id", bidID), zap.Error(err))
+		return nil, 0, err
+	}
+	return rows, lastID, nil
+}
+
+// CloseFiscalYear اسناد پایان سال را ثبت و سال را می‌بندد؛ اگر پس از محاسبه مانده‌ها سندی ثبت شده باشد ErrStaleFiscalYear برمی‌گرداند.
+func (r *fiscalYearRepositoryImpl) CloseFiscalYear(ctx context.Context, fy, next *model.FiscalYear, lastLineID uint, profitClosing, closing, nextOpening *model.LedgerEntry) error {
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		var locked model.FiscalYear
+		if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).First(&locked, fy.ID).Error; err != nil {
+			return fmt.Errorf("failed to lock fiscal year: %w", err)
+		}
+		if locked.Status != model.FiscalYearOpen {
+			return ErrStaleFiscalYear
+		}
+		currentLast, err := lastLedgerLineID(tx, fy.BIDID, fy.EndDate.AddDate(0, 0, 1))
+		if err != nil {
+			return fmt.Errorf("failed to read last ledger line: %w", err)
+		}
+		if currentLast != lastLineID {
+			return ErrStaleFiscalYear
+		}
+
+		if next.ID == 0 {
+			if err := createFiscalYearTx(tx, next); err != nil {
+				return err
+			}
+		} else {
+			var lockedNext model.FiscalYear
+			if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).First(&lockedNext, next.ID).Error; err != nil {
+				return fmt.Errorf("failed to lock next fiscal year: %w", err)
+			}
+			if lockedNext.Status != model.FiscalYearOpen {
+				return ErrNextFiscalYearOpen
+			}
+		}
+
+		updates := map[string]interface{}{
+			"status":    model.FiscalYearClosed,
+			"closed_at": fy.ClosedAt,
+			"closed_by": fy.ClosedBy,
+		}
+		if profitClosing != nil {
+			profitClosing.DocID = fy.ID
+			if err := postLedgerEntry(tx, profitClosing); err != nil {
+				return err
+			}
+			updates["profit_closing_entry_id"] = profitClosing.ID
+			fy.ProfitClosingEntryID = &profitClosing.ID
+		}
+		if closing != nil {
+			closing.DocID = fy.ID
+			if err := postLedgerEntry(tx, closing); err != nil {
+				return err
+			}
+			updates["closing_entry_id"] = closing.ID
+			fy.ClosingEntryID = &closing.ID
+		}
+		if nextOpening != nil {
+			nextOpening.DocID = next.ID
+			if err := postLedgerEntry(tx, nextOpening); err != nil {
+				return err
+			}
+			next.OpeningEntryID = &nextOpening.ID
+			if err := tx.Model(&model.FiscalYear{}).Where("id = ?", next.ID).Update("opening_entry_id", nextOpening.ID).Error; err != nil {
+				return fmt.Errorf("failed to link opening entry: %w", err)
+			}
+		}
+
+		if err := tx.Model(&model.FiscalYear{}).Where("id = ?", fy.ID).Updates(updates).Error; err != nil {
+			return fmt.Errorf("failed to close fiscal year: %w", err)
+		}
+		return tx.Create(&model.FiscalYearEvent{
+			FiscalYearID: fy.ID,
+			BIDID:        fy.BIDID,
+			Action:       model.FiscalYearEventClose,
+			Actor:        fy.ClosedBy,
+		}).Error
+	})
+	if err != nil {
+		r.logger.Error("Failed to close fiscal year", zap.Uint("fiscal_year_id", fy.ID), zap.Error(err))
+	}
+	return err
+}
+
+// ReopenFiscalYear وضعیت سال پیش از ثبت اسناد معکوس باز می‌شود تا کنترل سال بسته مانع ثبت آن‌ها نشود.
+func (r *fiscalYearRepositoryImpl) ReopenFiscalYear(ctx context.Context, fy, next *model.FiscalYear, reversals []*model.LedgerEntry, actor, reason string) error {
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		var locked model.FiscalYear
+		if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).First(&locked, fy.ID).Error; err != nil {
+			return fmt.Errorf("failed to lock fiscal year: %w", err)
+		}
+		if locked.Status != model.FiscalYearClosed {
+			return ErrStaleFiscalYear
+		}
+		if next != nil {
+			var lockedNext model.FiscalYear
+			if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).First(&lockedNext, next.ID).Error; err != nil {
+				return fmt.Errorf("failed to lock next fiscal year: %w", err)
+			}
+			if lockedNext.Status != model.FiscalYearOpen {
+				return ErrNextFiscalYearOpen
+			}
+			if err := tx.Model(&model.FiscalYear{}).Where("id = ?", next.ID).Update("opening_entry_id", nil).Error; err != nil {
+				return fmt.Errorf("failed to unlink opening entry: %w", err)
+			}
+		}
+
+		err := tx.Model(&model.FiscalYear{}).Where("id = ?", fy.ID).Updates(map[string]interface{}{
+			"status":                  model.FiscalYearOpen,
+			"closed_at":               nil,
+			"closed_by":               "",
+			"profit_closing_entry_id": nil,
+			"closing_entry_id":        nil,
+		}).Error
+		if err != nil {
+			return fmt.Errorf("failed to reopen fiscal year: %w", err)
+		}
+		for _, reversal := range reversals {
+			if err := postLedgerEntry(tx, reversal); err != nil {
+				return err
+			}
+		}
+		return tx.Create(&model.FiscalYearEvent{
+			FiscalYearID: fy.ID,
+			BIDID:        fy.BIDID,
+			Action:       model.FiscalYearEventReopen,
+			Actor:        actor,
+			Reason:       utils.PtrString(reason),
+		}).Error
+	})
+	if err != nil {
+		r.logger.Error("Failed to reopen fiscal year", zap.Uint("fiscal_year_id", fy.ID), zap.Error(err))
+	}
+	return err
+}
@@ -0,0 +1,243 @@
+package postgresDb
+
+import (
+	"context"
+	"crm-gold/internal/model"
+	"crm-gold/internal/repository/repo"
+	"errors"
+	"fmt"
+	"time"
+
+	"go.uber.org/zap"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// ErrFundDayClosed یعنی تاریخ سند در بازه‌ای است که صندوق برای آن بسته شده است.
+var ErrFundDayClosed = errors.New("fund is closed for this date")
+
+// ErrStaleCashVoucher یعنی سند صندوق پس از خوانده شدن توسط درخواست دیگری اصلاح یا حذف شده و معکوسی که
+// برای آن ساخته شده دیگر به سند حسابداری فعلی‌اش اشاره نمی‌کند.
+var ErrStaleCashVoucher = errors.New("cash voucher was changed concurrently")
+
+type fundRepositoryImpl struct {
+	db     *gorm.DB
+	logger *zap.Logger
+}
+
+func NewFundRepository(db *gorm.DB, logger *zap.Logger) (repo.FundRepo, error) {
+	if db == nil {
+		return nil, errors.New("database connection cannot be nil for FundRepository")
+	}
+	if logger == nil {
+		return nil, errors.New("logger cannot be nil for FundRepository")
+	}
+	return &fundRepositoryImpl{
+		db:     db,
+		logger: logger,
+	}, nil
+}
+
+func (r *fundRepositoryImpl) CreateFund(ctx context.Context, fund *model.Fund, openingEntries []*model.LedgerEntry) (*model.Fund, error) {
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Create(fund).Error; err != nil {
+			return fmt.Errorf("failed to save fund: %w", err)
+		}
+		for i, entry := range openingEntries {
+			if entry == nil || i >= len(fund.OpeningBalances) {
+				continue
+			}
+			entry.DocID = fund.ID
+			if err := postLedgerEntry(tx, entry); err != nil {
+				return err
+			}
+			if err := tx.Model(&fund.OpeningBalances[i]).Update("ledger_entry_id", entry.ID).Error; err != nil {
+				return fmt.Errorf("failed to link fund opening balance entry: %w", err)
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		r.logger.Error("Failed to create fund", zap.String("code", fund.Code), zap.Error(err))
+		return nil, err
+	}
+	r.logger.Info("Fund saved successfully to database.", zap.Uint("fund_id", fund.ID))
+	return fund, nil
+}
+
+func (r *fundRepositoryImpl) GetFundByID(ctx context.Context, id uint) (*model.Fund, error) {
+	var fund model.Fund
+	if err := r.db.WithContext(ctx).Scopes(businessScope(ctx)).Preload("OpeningBalances").First(&fund, id).Error; err != nil {
+		r.logger.Error("failed to get fund by ID", zap.Uint("id", id), zap.Error(err))
+		return nil, err
+	}
+	return &fund, nil
+}
+
+func (r *fundRepositoryImpl) ListFunds(ctx context.Context, bidID uint) ([]model.Fund, error) {
+	var funds []model.Fund
+	q := r.db.WithContext(ctx).Preload("OpeningBalances")
+	if bidID != 0 {
+		q = q.Where("bid_id = ?", bidID)
+	}
+	if err := q.Order("code").Find(&funds).Error; err != nil {
+		r.logger.Error("failed to list funds", zap.Error(err))
+		return nil, err
+	}
+	return funds, nil
+}
+
+// ensureFundOpen قفل ردیف صندوق را می‌گیرد تا بستن روز و ثبت سند همزمان انجام نشوند.
+func ensureFundOpen(tx *gorm.DB, fundID uint, date time.Time) error {
+	var fund model.Fund
+	if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).First(&fund, fundID).Error; err != nil {
+		return fmt.Errorf("failed to lock fund %d: %w", fundID, err)
+	}
+	if fund.ClosedThrough != nil && !date.After(fund.ClosedThrough.Add(24*time.Hour-time.Nanosecond)) {
+		return fmt.Errorf("%w: fund %d is closed through %s", ErrFundDayClosed, fundID, fund.ClosedThrough.Format("2006-01-02"))
+	}
+	return nil
+}
+
+func voucherFunds(voucher *model.CashVoucher) []uint {
+	ids := []uint{voucher.FundID}
+	if voucher.CounterpartyKind == model.LedgerAccountFund && voucher.CounterpartyID != voucher.FundID {
+		ids = append(ids, voucher.CounterpartyID)
+	}
+	return ids
+}
+
+func (r *fundRepositoryImpl) CreateCashVoucher(ctx context.Context, voucher *model.CashVoucher, entry *model.LedgerEntry) (*model.CashVoucher, error) {
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		for _, fundID := range voucherFunds(voucher) {
+			if err := ensureFundOpen(tx, fundID, voucher.Date); err != nil {
+				return err
+			}
+		}
+		if err := tx.Create(voucher).Error; err != nil {
+			return fmt.Errorf("failed to save cash voucher: %w", err)
+		}
+		entry.DocID = voucher.ID
+		if err := postLedgerEntry(tx, entry); err != nil {
+			return err
+		}
+		voucher.LedgerEntryID = &entry.ID
+		return tx.Model(voucher).Update("ledger_entry_id", entry.ID).Error
+	})
+	if err != nil {
+		r.logger.Error("Failed to create cash voucher", zap.Uint("fund_id", voucher.FundID), zap.Error(err))
+		return nil, err
+	}
+	return voucher, nil
+}
+
+func (r *fundRepositoryImpl) GetCashVoucherByID(ctx context.Context, id uint) (*model.CashVoucher, error) {
+	var voucher model.CashVoucher
+	if err := r.db.WithContext(ctx).Scopes(businessScope(ctx)).First(&voucher, id).Error; err != nil {
+		r.logger.Error("failed to get cash voucher by ID", zap.Uint("id", id), zap.Error(err))
+		return nil, err
+	}
+	return &voucher, nil
+}
+
+func (r *fundRepositoryImpl) ListCashVouchers(ctx context.Context, fundID uint, from, to *time.Time) ([]model.CashVoucher, error) {
+	var vouchers []model.CashVoucher
+	q := r.db.WithContext(ctx).Where("fund_id = ?", fundID)
+	if from != nil {
+		q = q.Where("date >= ?", *from)
+	}
+	if to != nil {
+		q = q.Where("date < ?", *to)
+	}
+	if err := q.Order("date, id").Find(&vouchers).Error; err != nil {
+		r.logger.Error("failed to list cash vouchers", zap.Uint("fund_id", fundID), zap.Error(err))
+		return nil, err
+	}
+	return vouchers, nil
+}
+
+// UpdateCashVoucher سند قبلی را معکوس و سند جدید را ثبت می‌کند؛ هر دو تاریخ قدیم و جدید باید باز باشند.
+func (r *fundRepositoryImpl) UpdateCashVoucher(ctx context.Context, voucher *model.CashVoucher, reversal, entry *model.LedgerEntry) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		current, err := lockCashVoucher(tx, voucher.ID, reversal)
+		if err != nil {
+			return err
+		}
+		for _, fundID := range voucherFunds(current) {
+			if err := ensureFundOpen(tx, fundID, current.Date); err != nil {
+				return err
+			}
+		}
+		for _, fundID := range voucherFunds(voucher) {
+			if err := ensureFundOpen(tx, fundID, voucher.Date); err != nil {
+				return err
+			}
+		}
+		if err := postLedgerEntry(tx, reversal); err != nil {
+			return err
+		}
+		entry.DocID = voucher.ID
+		if err := postLedgerEntry(tx, entry); err != nil {
+			return err
+		}
+		voucher.LedgerEntryID = &entry.ID
+		if err := tx.Save(voucher).Error; err != nil {
+			return fmt.Errorf("failed to update cash voucher: %w", err)
+		}
+		return nil
+	})
+}
+
+func (r *fundRepositoryImpl) DeleteCashVoucher(ctx context.Context, voucher *model.CashVoucher, reversal *model.LedgerEntry) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		current, err := lockCashVoucher(tx, voucher.ID, reversal)
+		if err != nil {
+			return err
+		}
+		for _, fundID := range voucherFunds(current) {
+			if err := ensureFundOpen(tx, fundID, current.Date); err != nil {
+				return err
+			}
+		}
+		if err := postLedgerEntry(tx, reversal); err != nil {
+			return err
+		}
+		if err := tx.Delete(current).Error; err != nil {
+			return fmt.Errorf("failed to delete cash voucher: %w", err)
+		}
+		return nil
+	})
+}
+
+// lockCashVoucher ردیف سند را تا پایان تراکنش قفل می‌کند تا دو اصلاح یا حذف هم‌زمان یک سند حسابداری را
+// دو بار معکوس نکنند، و اگر سند حسابداری فعلی همان سندی نباشد که reversal معکوس می‌کند ErrStaleCashVoucher برمی‌گرداند.
+func lockCashVoucher(tx *gorm.DB, id uint, reversal *model.LedgerEntry) (*model.CashVoucher, error) {
+	var current model.CashVoucher
+	err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).First(&current, id).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, ErrStaleCashVoucher
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to lock cash voucher: %w", err)
+	}
+	if current.LedgerEntryID == nil || reversal.ReversalOfID == nil || *current.LedgerEntryID != *reversal.ReversalOfID {
+		return nil, ErrStaleCashVoucher
+	}
+	return &current, nil
+}
+
+func (r *fundRepositoryImpl) CloseFundDay(ctx context.Context, fund *model.Fund, dayClose *model.FundDayClose) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := ensureFundOpen(tx, fund.ID, dayClose.Date); err != nil {
+			return err
+		}
+		if err := tx.Create(dayClose).Error; err != nil {
+			return fmt.Errorf("failed to save fund day close: %w", err)
+		}
+		if err := tx.Model(&model.Fund{}).Where("id = ?", fund.ID).Update("closed_through", dayClose.Date).Error; err != nil {
+			return fmt.Errorf("failed to update fund closed date: %w", err)
+		}
+		fund.ClosedThrough = &dayClose.Date
+		return nil
+	})
+}
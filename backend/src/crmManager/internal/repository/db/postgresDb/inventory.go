@@ -0,0 +1,147 @@
+package postgresDb
+
+import (
+	"context"
+	"crm-gold/internal/model"
+	"crm-gold/internal/repository/repo"
+	"errors"
+	"fmt"
+	"time"
+
+	"go.uber.org/zap"
+	"gorm.io/gorm"
+)
+
+var ErrInventoryItemNotInStock = errors.New("inventory item is not in stock")
+
+type inventoryRepositoryImpl struct {
+	db     *gorm.DB
+	logger *zap.Logger
+}
+
+func NewInventoryRepository(db *gorm.DB, logger *zap.Logger) (repo.InventoryRepo, error) {
+	if db == nil {
+		return nil, errors.New("database connection cannot be nil for InventoryRepository")
+	}
+	if logger == nil {
+		return nil, errors.New("logger cannot be nil for InventoryRepository")
+	}
+	return &inventoryRepositoryImpl{
+		db:     db,
+		logger: logger,
+	}, nil
+}
+
+func (r *inventoryRepositoryImpl) CreateItem(ctx context.Context, item *model.InventoryItem) (*model.InventoryItem, error) {
+	if err := r.db.WithContext(ctx).Create(item).Error; err != nil {
+		r.logger.Error("Failed to create inventory item", zap.String("code", item.Code), zap.Error(err))
+		return nil, err
+	}
+	return item, nil
+}
+
+func (r *inventoryRepositoryImpl) GetItemByID(ctx context.Context, id uint) (*model.InventoryItem, error) {
+	var item model.InventoryItem
+	if err := r.db.WithContext(ctx).Scopes(businessScope(ctx)).First(&item, id).Error; err != nil {
+		r.logger.Error("failed to get inventory item by ID", zap.Uint("id", id), zap.Error(err))
+		return nil, err
+	}
+	return &item, nil
+}
+
+func (r *inventoryRepositoryImpl) ListItems(ctx context.Context, filter model.InventoryItemFilter) ([]model.InventoryItem, error) {
+	var items []model.InventoryItem
+	q := r.db.WithContext(ctx)
+	if filter.BIDID != 0 {
+		q = q.Where("bid_id = ?", filter.BIDID)
+	}
+	if filter.Category != "" {
+		q = q.Where("category = ?", filter.Category)
+	}
+	if filter.Status != "" {
+		q = q.Where("status = ?", filter.Status)
+	}
+	if filter.Ownership != "" {
+		q = q.Where("ownership = ?", filter.Ownership)
+	}
+	if err := q.Order("received_at DESC, id DESC").Find(&items).Error; err != nil {
+		r.logger.Error("failed to list inventory items", zap.Error(err))
+		return nil, err
+	}
+	return items, nil
+}
+
+func (r *inventoryRepositoryImpl) UpdateItem(ctx context.Context, item *model.InventoryItem) error {
+	err := r.db.WithContext(ctx).Model(item).Select("name", "category", "cost_price", "description").Updates(item).Error
+	if err != nil {
+		r.logger.Error("failed to update inventory item", zap.Uint("id", item.ID), zap.Error(err))
+		return err
+	}
+	return nil
+}
+
+func (r *inventoryRepositoryImpl) StockSummary(ctx context.Context, bidID uint) ([]model.InventoryStockRow, error) {
+	var rows []model.InventoryStockRow
+	err := r.db.WithContext(ctx).Model(&model.InventoryItem{}).
+		Where("bid_id = ? AND status = ?", bidID, model.InventoryItemInStock).
+		Group("category, purity, ownership").
+		Order("category, purity DESC, ownership").
+		Select("category, purity, ownership, COUNT(*) AS item_count, SUM(weight) AS weight, SUM(cost_price) AS cost").
+		Scan(&rows).Error
+	if err != nil {
+		r.logger.Error("failed to summarize inventory stock", zap.Uint("bid_id", bidID), zap.Error(err))
+		return nil, err
+	}
+	return rows, nil
+}
+
+func (r *inventoryRepositoryImpl) ListSlowMovers(ctx context.Context, bidID uint, receivedBefore time.Time) ([]model.InventoryItem, error) {
+	var items []model.InventoryItem
+	err := r.db.WithContext(ctx).
+		Where("bid_id = ? AND status = ? AND received_at < ?", bidID, model.InventoryItemInStock, receivedBefore).
+		Order("received_at, id").
+		Find(&items).Error
+	if err != nil {
+		r.logger.Error("failed to list slow-moving items", zap.Uint("bid_id", bidID), zap.Error(err))
+		return nil, err
+	}
+	return items, nil
+}
+
+// markItemsSold کالاهای سطرهای فاکتور را فروخته‌شده علامت می‌زند؛ کالایی که همزمان فروخته شده باشد کل فاکتور را رد می‌کند.
+func markItemsSold(tx *gorm.DB, invoice *model.SaleInvoice) error {
+	for _, l := range invoice.Lines {
+		if l.ItemID == nil {
+			continue
+		}
+		res := tx.Model(&model.InventoryItem{}).
+			Where("id = ? AND bid_id = ? AND status = ?", *l.ItemID, invoice.BIDID, model.InventoryItemInStock).
+			Updates(map[string]interface{}{
+				"status":          model.InventoryItemSold,
+				"sold_at":         invoice.Date,
+				"sale_invoice_id": invoice.ID,
+			})
+		if res.Error != nil {
+			return fmt.Errorf("failed to mark inventory item %d as sold: %w", *l.ItemID, res.Error)
+		}
+		if res.RowsAffected == 0 {
+			return fmt.Errorf("%w: item %d", ErrInventoryItemNotInStock, *l.ItemID)
+		}
+	}
+	return nil
+}
+
+// restockInvoiceItems کالاهای فاکتور ابطال‌شده به موجودی برمی‌گردند.
+func restockInvoiceItems(tx *gorm.DB, invoiceID uint) error {
+	err := tx.Model(&model.InventoryItem{}).
+		Where("sale_invoice_id = ? AND status = ?", invoiceID, model.InventoryItemSold).
+		Updates(map[string]interface{}{
+			"status":          model.InventoryItemInStock,
+			"sold_at":         nil,
+			"sale_invoice_id": nil,
+		}).Error
+	if err != nil {
+		return fmt.Errorf("failed to return invoice items to stock: %w", err)
+	}
+	return nil
+}
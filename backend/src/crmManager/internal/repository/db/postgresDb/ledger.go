@@ -0,0 +1,312 @@
+package postgresDb
+
+import (
+	"context"
+	"crm-gold/internal/model"
+	"crm-gold/internal/repository/repo"
+	"errors"
+	"fmt"
+	"time"
+
+	"common-gold/money"
+
+	"go.uber.org/zap"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+var (
+	ErrUnbalancedEntry      = errors.New("ledger entry is not balanced")
+	ErrEntryAlreadyReversed = errors.New("ledger entry is already reversed")
+)
+
+type ledgerRepositoryImpl struct {
+	db     *gorm.DB
+	logger *zap.Logger
+}
+
+func NewLedgerRepository(db *gorm.DB, logger *zap.Logger) (repo.LedgerRepo, error) {
+	if db == nil {
+		return nil, errors.New("database connection cannot be nil for LedgerRepository")
+	}
+	if logger == nil {
+		return nil, errors.New("logger cannot be nil for LedgerRepository")
+	}
+	return &ledgerRepositoryImpl{
+		db:     db,
+		logger: logger,
+	}, nil
+}
+
+// postLedgerEntry باید داخل تراکنش سند اصلی صدا زده شود تا سند و اثر حسابداری آن با هم ثبت شوند.
+func postLedgerEntry(tx *gorm.DB, entry *model.LedgerEntry) error {
+	if entry == nil {
+		return nil
+	}
+	if !entry.IsBalanced() {
+		return fmt.Errorf("%w: %s", ErrUnbalancedEntry, entry.Reference)
+	}
+	for i := range entry.Lines {
+		entry.Lines[i].BIDID = entry.BIDID
+	}
+	if err := ensureFiscalYearOpen(tx, entry.BIDID, entry.PostedAt); err != nil {
+		return err
+	}
+	if err := resolveAccountCodes(tx, entry); err != nil {
+		return err
+	}
+	if err := tx.Create(entry).Error; err != nil {
+		return fmt.Errorf("failed to post ledger entry: %w", err)
+	}
+	return nil
+}
+
+func (r *ledgerRepositoryImpl) GetEntriesByDocument(ctx context.Context, docType string, docID uint) ([]model.LedgerEntry, error) {
+	var entries []model.LedgerEntry
+	err := r.db.WithContext(ctx).Preload("Lines").
+		Where("doc_type = ? AND doc_id = ?", docType, docID).
+		Order("posted_at, id").Find(&entries).Error
+	if err != nil {
+		r.logger.Error("failed to get ledger entries by document", zap.String("doc_type", docType), zap.Uint("doc_id", docID), zap.Error(err))
+		return nil, err
+	}
+	return entries, nil
+}
+
+func (r *ledgerRepositoryImpl) GetEntryByID(ctx context.Context, id uint) (*model.LedgerEntry, error) {
+	var entry model.LedgerEntry
+	if err := r.db.WithContext(ctx).Scopes(businessScope(ctx)).Preload("Lines").First(&entry, id).Error; err != nil {
+		r.logger.Error("failed to get ledger entry by ID", zap.Uint("id", id), zap.Error(err))
+		return nil, err
+	}
+	return &entry, nil
+}
+
+// accountLinesQuery سطرهای یک حساب؛ برای ارز خارجی فقط سطرهای همان ارز در نظر گرفته می‌شوند.
+func accountLinesQuery(db *gorm.DB, accountKind string, accountID uint, currencyID *uint) *gorm.DB {
+	q := db.Table("ledger_lines").
+		Joins("JOIN ledger_entries ON ledger_entries.id = ledger_lines.entry_id AND ledger_entries.deleted_at IS NULL").
+		Where("ledger_lines.deleted_at IS NULL").
+		Where("ledger_lines.account_kind = ? AND ledger_lines.account_id = ?", accountKind, accountID)
+	if currencyID != nil {
+		q = q.Where("ledger_lines.currency_id = ?", *currencyID)
+	}
+	return q
+}
+
+// accountBalance مانده تومانی حساب پیش از تاریخ before؛ با currencyID فقط سطرهای آن ارز جمع می‌شوند. داخل تراکنش هم قابل استفاده است.
+func accountBalance(db *gorm.DB, accountKind string, accountID uint, currencyID *uint, before time.Time) (money.Amount, error) {
+	var balance struct {
+		Toman money.Amount
+	}
+	err := accountLinesQuery(db, accountKind, accountID, currencyID).
+		Where("ledger_entries.posted_at < ?", before).
+		Select("COALESCE(SUM(ledger_lines.debit - ledger_lines.credit), 0) AS toman").Scan(&balance).Error
+	return balance.Toman, err
+}
+
+// currencyBalance مانده ارزی حساب در ارز currencyID پیش از تاریخ before؛ جهت مبلغ ارزی همان جهت سطر است.
+func currencyBalance(db *gorm.DB, accountKind string, accountID uint, currencyID uint, before time.Time) (money.Foreign, error) {
+	var balance struct {
+		Amount money.Foreign
+	}
+	err := accountLinesQuery(db, accountKind, accountID, &currencyID).
+		Where("ledger_entries.posted_at < ?", before).
+		Select("COALESCE(SUM(CASE WHEN ledger_lines.debit > 0 THEN ledger_lines.currency_amount ELSE -ledger_lines.currency_amount END), 0) AS amount").
+		Scan(&balance).Error
+	return balance.Amount, err
+}
+
+// lowestBalanceFrom کمترین مانده پایان روز حساب از روز day به بعد، شامل اسنادی که با تاریخ بعدتر ثبت شده‌اند؛
+// برداشتی به تاریخ day که از این مقدار بیشتر باشد مانده یکی از روزهای بعد را منفی می‌کند.
+func lowestBalanceFrom(db *gorm.DB, accountKind string, accountID uint, day time.Time) (money.Amount, error) {
+	next := day.AddDate(0, 0, 1)
+	closing, err := accountBalance(db, accountKind, accountID, nil, next)
+	if err != nil {
+		return money.Amount{}, err
+	}
+	daily := accountLinesQuery(db, accountKind, accountID, nil).
+		Where("ledger_entries.posted_at >= ?", next).
+		Select("SUM(SUM(ledger_lines.debit - ledger_lines.credit)) OVER (ORDER BY DATE(ledger_entries.posted_at)) AS running").
+		Group("DATE(ledger_entries.posted_at)")
+	var lowest struct {
+		Lowest money.Amount
+	}
+	if err := db.Table("(?) AS daily", daily).Select("COALESCE(LEAST(MIN(running), 0), 0) AS lowest").Scan(&lowest).Error; err != nil {
+		return money.Amount{}, err
+	}
+	return closing.Add(lowest.Lowest), nil
+}
+
+func (r *ledgerRepositoryImpl) GetAccountBalance(ctx context.Context, accountKind string, accountID uint, currencyID *uint, before time.Time) (money.Amount, error) {
+	balance, err := accountBalance(r.db.WithContext(ctx), accountKind, accountID, currencyID, before)
+	if err != nil {
+		r.logger.Error("failed to compute account balance", zap.String("account_kind", accountKind), zap.Uint("account_id", accountID), zap.Error(err))
+		return money.Amount{}, err
+	}
+	return balance, nil
+}
+
+func (r *ledgerRepositoryImpl) GetCurrencyBalance(ctx context.Context, accountKind string, accountID uint, currencyID uint, before time.Time) (money.Foreign, error) {
+	balance, err := currencyBalance(r.db.WithContext(ctx), accountKind, accountID, currencyID, before)
+	if err != nil {
+		r.logger.Error("failed to compute account currency balance", zap.String("account_kind", accountKind), zap.Uint("account_id", accountID), zap.Uint("currency_id", currencyID), zap.Error(err))
+		return money.Foreign{}, err
+	}
+	return balance, nil
+}
+
+func (r *ledgerRepositoryImpl) GetAccountLines(ctx context.Context, accountKind string, accountID uint, currencyID *uint, from, to time.Time) ([]model.LedgerLineWithEntry, error) {
+	var lines []model.LedgerLineWithEntry
+	err := accountLinesQuery(r.db.WithContext(ctx), accountKind, accountID, currencyID).
+		Where("ledger_entries.posted_at >= ? AND ledger_entries.posted_at < ?", from, to).
+		Select("ledger_lines.*, ledger_entries.posted_at, ledger_entries.doc_type, ledger_entries.doc_id, ledger_entries.reference").
+		Order("ledger_entries.posted_at, ledger_entries.id, ledger_lines.id").
+		Scan(&lines).Error
+	if err != nil {
+		r.logger.Error("failed to get account lines", zap.String("account_kind", accountKind), zap.Uint("account_id", accountID), zap.Error(err))
+		return nil, err
+	}
+	return lines, nil
+}
+
+func (r *ledgerRepositoryImpl) PostJournalEntry(ctx context.Context, entry *model.LedgerEntry) (*model.LedgerEntry, error) {
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := ensureFundLinesOpen(tx, entry); err != nil {
+			return err
+		}
+		if err := postLedgerEntry(tx, entry); err != nil {
+			return err
+		}
+		// سند دستی سند مبدا جداگانه ندارد؛ شناسه خود سند به عنوان DocID استفاده می‌شود
+		entry.DocID = entry.ID
+		return tx.Exec("UPDATE ledger_entries SET doc_id = ? WHERE id = ?", entry.ID, entry.ID).Error
+	})
+	if err != nil {
+		r.logger.Error("Failed to post journal entry", zap.String("reference", entry.Reference), zap.Error(err))
+		return nil, err
+	}
+	return entry, nil
+}
+
+// ReverseJournalEntry سطر سند اصلی قفل می‌شود تا دو درخواست همزمان دو سند معکوس ثبت نکنند.
+func (r *ledgerRepositoryImpl) ReverseJournalEntry(ctx context.Context, entry *model.LedgerEntry, reversal *model.LedgerEntry) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		var locked model.LedgerEntry
+		if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).First(&locked, entry.ID).Error; err != nil {
+			return fmt.Errorf("failed to lock ledger entry: %w", err)
+		}
+		var reversed int64
+		if err := tx.Model(&model.LedgerEntry{}).Where("reversal_of_id = ?", entry.ID).Count(&reversed).Error; err != nil {
+			return fmt.Errorf("failed to check ledger entry reversal: %w", err)
+		}
+		if reversed > 0 {
+			return ErrEntryAlreadyReversed
+		}
+		if err := ensureFundLinesOpen(tx, reversal); err != nil {
+			return err
+		}
+		return postLedgerEntry(tx, reversal)
+	})
+}
+
+// ensureFundLinesOpen سند دستی روی صندوقی که روز آن بسته شده ثبت نمی‌شود.
+func ensureFundLinesOpen(tx *gorm.DB, entry *model.LedgerEntry) error {
+	for _, l := range entry.Lines {
+		if l.AccountKind == model.LedgerAccountFund && l.AccountID != nil {
+			if err := ensureFundOpen(tx, *l.AccountID, entry.PostedAt); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+func (r *ledgerRepositoryImpl) ListEntries(ctx context.Context, filter model.JournalFilter) ([]model.LedgerEntry, error) {
+	var entries []model.LedgerEntry
+	q := r.db.WithContext(ctx).Preload("Lines").Where("bid_id = ?", filter.BIDID)
+	if filter.DocType != "" {
+		q = q.Where("doc_type = ?", filter.DocType)
+	}
+	if filter.AccountCode != "" {
+		q = q.Where("EXISTS (SELECT 1 FROM ledger_lines ll WHERE ll.entry_id = ledger_entries.id AND ll.deleted_at IS NULL AND ll.account_code LIKE ?)", filter.AccountCode+"%")
+	}
+	if filter.From != nil {
+		q = q.Where("posted_at >= ?", *filter.From)
+	}
+	if filter.To != nil {
+		q = q.Where("posted_at < ?", *filter.To)
+	}
+	if err := q.Order("posted_at, id").Find(&entries).Error; err != nil {
+		r.logger.Error("failed to list ledger entries", zap.Uint("bid_id", filter.BIDID), zap.Error(err))
+		return nil, err
+	}
+	return entries, nil
+}
+
+// codeLinesQuery سطرهای یک حساب از سرفصل؛ کد حساب کل یا گروه همه معین‌های زیر آن را شامل می‌شود.
+func codeLinesQuery(db *gorm.DB, bidID uint, accountCode string, detailID *uint) *gorm.DB {
+	q := db.Table("ledger_lines").
+		Joins("JOIN ledger_entries ON ledger_entries.id = ledger_lines.entry_id AND ledger_entries.deleted_at IS NULL").
+		Where("ledger_lines.deleted_at IS NULL AND ledger_lines.bid_id = ?", bidID).
+		Where("ledger_lines.account_code LIKE ?", accountCode+"%")
+	if detailID != nil {
+		q = q.Where("ledger_lines.account_id = ?", *detailID)
+	}
+	return q
+}
+
+func (r *ledgerRepositoryImpl) GetCodeBalance(ctx context.Context, bidID uint, accountCode string, detailID *uint, before time.Time) (money.Amount, money.Weight, error) {
+	var balance struct {
+		Toman money.Amount
+		Gold  money.Weight
+	}
+	err := codeLinesQuery(r.db.WithContext(ctx), bidID, accountCode, detailID).
+		Where("ledger_entries.posted_at < ?", before).
+		Select("COALESCE(SUM(ledger_lines.debit - ledger_lines.credit), 0) AS toman, COALESCE(SUM(ledger_lines.gold_debit - ledger_lines.gold_credit), 0) AS gold").
+		Scan(&balance).Error
+	if err != nil {
+		r.logger.Error("failed to compute account code balance", zap.String("account_code", accountCode), zap.Error(err))
+		return money.Amount{}, money.Weight{}, err
+	}
+	return balance.Toman, balance.Gold, nil
+}
+
+func (r *ledgerRepositoryImpl) GetCodeLines(ctx context.Context, bidID uint, accountCode string, detailID *uint, from, to time.Time) ([]model.LedgerLineWithEntry, error) {
+	var lines []model.LedgerLineWithEntry
+	err := codeLinesQuery(r.db.WithContext(ctx), bidID, accountCode, detailID).
+		Where("ledger_entries.posted_at >= ? AND ledger_entries.posted_at < ?", from, to).
+		Select("ledger_lines.*, ledger_entries.posted_at, ledger_entries.doc_type, ledger_entries.doc_id, ledger_entries.reference").
+		Order("ledger_entries.posted_at, ledger_entries.id, ledger_lines.id").
+		Scan(&lines).Error
+	if err != nil {
+		r.logger.Error("failed to get account code lines", zap.String("account_code", accountCode), zap.Error(err))
+		return nil, err
+	}
+	return lines, nil
+}
+
+// GetCodeTotals جمع گردش ریالی و وزنی هر حساب معین در بازه؛ from خالی یعنی از ابتدای دفتر.
+func (r *ledgerRepositoryImpl) GetCodeTotals(ctx context.Context, bidID uint, from *time.Time, to time.Time, excludeDocTypes []string) ([]model.AccountTotalRow, error) {
+	q := r.db.WithContext(ctx).Table("ledger_lines").
+		Joins("JOIN ledger_entries ON ledger_entries.id = ledger_lines.entry_id AND ledger_entries.deleted_at IS NULL").
+		Where("ledger_lines.deleted_at IS NULL AND ledger_lines.bid_id = ?", bidID).
+		Where("ledger_entries.posted_at < ?", to)
+	if from != nil {
+		q = q.Where("ledger_entries.posted_at >= ?", *from)
+	}
+	if len(excludeDocTypes) > 0 {
+		q = q.Where("ledger_entries.doc_type NOT IN ?", excludeDocTypes)
+	}
+	var rows []model.AccountTotalRow
+	err := q.Group("ledger_lines.account_code").
+		Select(`ledger_lines.account_code, SUM(ledger_lines.debit) AS debit, SUM(ledger_lines.credit) AS credit,
+			SUM(ledger_lines.gold_debit) AS gold_debit, SUM(ledger_lines.gold_credit) AS gold_credit`).
+		Order("ledger_lines.account_code").
+		Scan(&rows).Error
+	if err != nil {
+		r.logger.Error("failed to compute account code totals", zap.Uint("bid_id", bidID), zap.Error(err))
+		return nil, err
+	}
+	return rows, nil
+}
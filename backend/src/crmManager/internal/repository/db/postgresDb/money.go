@@ -0,0 +1,85 @@
+package postgresDb
+
+import (
+	"fmt"
+
+	"common-gold/money"
+
+	"gorm.io/gorm"
+)
+
+// moneyColumns ستون‌هایی که از float به نوع‌های دقیق بسته money منتقل شده‌اند
+var moneyColumns = []struct {
+	table, column string
+	scale         int
+	notNull       bool
+}{
+	{"customers", "initial_balance_toman", money.AmountScale, true},
+	{"customers", "initial_balance_gold", money.WeightScale, true},
+	{"customers", "default_gold_unit_rate", money.AmountScale, false},
+	{"ledger_lines", "debit", money.AmountScale, true},
+	{"ledger_lines", "credit", money.AmountScale, true},
+	{"ledger_lines", "gold_debit", money.WeightScale, true},
+	{"ledger_lines", "gold_credit", money.WeightScale, true},
+	{"sale_invoices", "total_weight", money.WeightScale, true},
+	{"sale_invoices", "gold_amount", money.AmountScale, true},
+	{"sale_invoices", "labor_fee", money.AmountScale, true},
+	{"sale_invoices", "profit", money.AmountScale, true},
+	{"sale_invoices", "cost", money.AmountScale, true},
+	{"sale_invoices", "tax_amount", money.AmountScale, true},
+	{"sale_invoices", "total", money.AmountScale, true},
+	{"sale_invoice_lines", "weight", money.WeightScale, true},
+	{"sale_invoice_lines", "gold_rate", money.AmountScale, true},
+	{"sale_invoice_lines", "gold_amount", money.AmountScale, true},
+	{"sale_invoice_lines", "labor_fee", money.AmountScale, true},
+	{"sale_invoice_lines", "profit", money.AmountScale, true},
+	{"sale_invoice_lines", "cost", money.AmountScale, true},
+	{"sale_invoice_lines", "amount", money.AmountScale, true},
+	{"sale_invoice_lines", "tax_amount", money.AmountScale, true},
+	{"cheques", "amount", money.AmountScale, true},
+	{"inventory_items", "weight", money.WeightScale, true},
+	{"inventory_items", "cost_price", money.AmountScale, true},
+	{"bank_accounts", "opening_balance", money.AmountScale, true},
+	{"bank_transactions", "amount", money.AmountScale, true},
+	{"bank_statement_lines", "deposit", money.AmountScale, true},
+	{"bank_statement_lines", "withdrawal", money.AmountScale, true},
+	{"bank_statement_lines", "balance", money.AmountScale, false},
+	{"fund_opening_balances", "amount", money.ForeignScale, true},
+	{"fund_opening_balances", "amount_in_toman", money.AmountScale, true},
+	{"cash_vouchers", "amount", money.AmountScale, true},
+	{"fund_day_closes", "balance", money.AmountScale, false},
+	{"transfers", "amount", money.AmountScale, true},
+	{"transfers", "fee", money.AmountScale, true},
+	{"center_vouchers", "amount", money.AmountScale, true},
+	{"recurring_expenses", "amount", money.AmountScale, true},
+	{"business_settings", "gold_rate", money.AmountScale, true},
+}
+
+// migrateMoneyColumns ستون‌های float قدیمی را پیش از AutoMigrate به NUMERIC تبدیل می‌کند؛ مقدارها به دقت نوع جدید گرد می‌شوند
+// و NULL در ستون‌های اجباری صفر می‌شود. ستونی که وجود ندارد یا قبلا تبدیل شده دست نمی‌خورد.
+func migrateMoneyColumns(db *gorm.DB) error {
+	for _, c := range moneyColumns {
+		var dataType string
+		err := db.Raw(`SELECT data_type FROM information_schema.columns
+			WHERE table_schema = current_schema() AND table_name = ? AND column_name = ?`, c.table, c.column).Scan(&dataType).Error
+		if err != nil {
+			return fmt.Errorf("failed to inspect column %s.%s: %w", c.table, c.column, err)
+		}
+		if dataType != "double precision" && dataType != "real" {
+			continue
+		}
+		err = db.Transaction(func(tx *gorm.DB) error {
+			if c.notNull {
+				if err := tx.Exec(fmt.Sprintf(`UPDATE %q SET %q = 0 WHERE %q IS NULL`, c.table, c.column, c.column)).Error; err != nil {
+					return err
+				}
+			}
+			return tx.Exec(fmt.Sprintf(`ALTER TABLE %q ALTER COLUMN %q TYPE numeric(24,%d) USING round(%q::numeric, %d)`,
+				c.table, c.column, c.scale, c.column, c.scale)).Error
+		})
+		if err != nil {
+			return fmt.Errorf("failed to convert column %s.%s to numeric: %w", c.table, c.column, err)
+		}
+	}
+	return nil
+}
@@ -52,16 +52,42 @@ func (r *customerRepositoryImpl) CreateCustomer(ctx context.Context, customer *m
 
 func (r *customerRepositoryImpl) GetAllCustomers(ctx context.Context) ([]model.Customer, error) {
 	var customers []model.Customer
-	if err := r.db.WithContext(ctx).Find(&customers).Error; err != nil {
+	if err := r.db.WithContext(ctx).Scopes(ownerScope(ctx)).Find(&customers).Error; err != nil {
 		r.logger.Error("failed to get all customers", zap.Error(err))
 		return nil, err
 	}
 	return customers, nil
 }
 
+// ListCustomersWithInitialBalance اشخاص کسب‌وکار با مانده اولیه غیر صفر برای سند افتتاحیه؛ محدودیت مالکیت کاربر اعمال نمی‌شود
+// چون سند افتتاحیه باید مانده همه اشخاص را در بر بگیرد.
+func (r *customerRepositoryImpl) ListCustomersWithInitialBalance(ctx context.Context, bidID uint) ([]model.Customer, error) {
+	var customers []model.Customer
+	err := r.db.WithContext(ctx).
+		Where("bid_id = ? AND (initial_balance_toman <> 0 OR initial_balance_gold <> 0)", bidID).
+		Order("id").Find(&customers).Error
+	if err != nil {
+		r.logger.Error("failed to list customers with initial balance", zap.Uint("bid_id", bidID), zap.Error(err))
+		return nil, err
+	}
+	return customers, nil
+}
+
+// GetEmployeeByUserRef کارمندی که به کاربر profileManager با شناسه userRef متصل است.
+func (r *customerRepositoryImpl) GetEmployeeByUserRef(ctx context.Context, userRef string) (*model.Employee, error) {
+	var employee model.Employee
+	if err := r.db.WithContext(ctx).Where("user_ref = ?", userRef).First(&employee).Error; err != nil {
+		if !errors.Is(err, gorm.ErrRecordNotFound) {
+			r.logger.Error("failed to get employee by user ref", zap.String("user_ref", userRef), zap.Error(err))
+		}
+		return nil, err
+	}
+	return &employee, nil
+}
+
 func (r *customerRepositoryImpl) GetCustomerByCode(ctx context.Context, code string) (*model.Customer, error) {
 	var customer model.Customer
-	if err := r.db.WithContext(ctx).Where("code = ?", code).First(&customer).Error; err != nil {
+	if err := r.db.WithContext(ctx).Scopes(ownerScope(ctx)).Where("code = ?", code).First(&customer).Error; err != nil {
 		r.logger.Error("failed to get customer by code", zap.String("code", code), zap.Error(err))
 		return nil, err
 	}
@@ -70,7 +96,7 @@ func (r *customerRepositoryImpl) GetCustomerByCode(ctx context.Context, code str
 
 func (r *customerRepositoryImpl) GetCustomerByID(ctx context.Context, id uint) (*model.Customer, error) {
 	var customer model.Customer
-	if err := r.db.WithContext(ctx).First(&customer, id).Error; err != nil {
+	if err := r.db.WithContext(ctx).Scopes(ownerScope(ctx), businessScope(ctx)).First(&customer, id).Error; err != nil {
 		r.logger.Error("failed to get customer by ID", zap.Uint("id", id), zap.Error(err))
 		return nil, err
 	}
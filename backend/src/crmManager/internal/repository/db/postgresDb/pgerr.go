@@ -0,0 +1,16 @@
+package postgresDb
+
+import (
+	"errors"
+
+	"github.com/jackc/pgx/v5/pgconn"
+)
+
+// uniqueViolation کد خطای PostgreSQL برای نقض قید یکتایی است.
+const uniqueViolation = "23505"
+
+// IsUniqueViolation مشخص می‌کند خطا از نقض یک قید یا ایندکس یکتا در PostgreSQL آمده است.
+func IsUniqueViolation(err error) bool {
+	var pgErr *pgconn.PgError
+	return errors.As(err, &pgErr) && pgErr.Code == uniqueViolation
+}
@@ -68,6 +68,10 @@ func InitDB() error {
 
 	utils.Log.Info("PostgreSQL database connected successfully. Attempting AutoMigrate...")
 
+	if err := migrateMoneyColumns(DB); err != nil {
+		return fmt.Errorf("failed to migrate money columns: %w", err)
+	}
+
 	err = DB.AutoMigrate(
 		&model.Customer{},
 		&model.CusCard{},
@@ -76,6 +80,36 @@ func InitDB() error {
 		&model.PaymentTerm{},
 		&model.Employee{},
 		&model.PersonPrelabel{},
+		&model.LedgerEntry{},
+		&model.LedgerLine{},
+		&model.Cheque{},
+		&model.ChequeStatusHistory{},
+		&model.ChequeAlert{},
+		&model.Fund{},
+		&model.FundOpeningBalance{},
+		&model.CashVoucher{},
+		&model.FundDayClose{},
+		&model.BankAccount{},
+		&model.POSTerminal{},
+		&model.BankTransaction{},
+		&model.BankStatementImport{},
+		&model.BankStatementLine{},
+		&model.BusinessSetting{},
+		&model.Transfer{},
+		&model.FinanceCenter{},
+		&model.CenterVoucher{},
+		&model.RecurringExpense{},
+		&model.Account{},
+		&model.FiscalYear{},
+		&model.FiscalYearEvent{},
+		&model.SaleInvoice{},
+		&model.SaleInvoiceLine{},
+		&model.InventoryItem{},
+		&model.TaxSetting{},
+		&model.TaxSubmission{},
+		&model.TaxRule{},
+		&model.ExchangeRate{},
+		&model.AuditLog{},
 	)
 
 	if err != nil {
@@ -83,6 +117,10 @@ func InitDB() error {
 	}
 	utils.Log.Info("Database schemas auto-migrated successfully.")
 
+	if err := backfillLedgerAccountCodes(DB); err != nil {
+		return fmt.Errorf("failed to backfill ledger account codes: %w", err)
+	}
+
 	return nil
 
 }
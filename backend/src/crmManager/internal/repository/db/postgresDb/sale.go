@@ -0,0 +1,146 @@
+package postgresDb
+
+import (
+	"context"
+	"crm-gold/internal/model"
+	"crm-gold/internal/repository/repo"
+	"errors"
+	"fmt"
+
+	"go.uber.org/zap"
+	"gorm.io/gorm"
+)
+
+var ErrSaleInvoiceVoided = errors.New("sale invoice is already void")
+
+type saleRepositoryImpl struct {
+	db     *gorm.DB
+	logger *zap.Logger
+}
+
+func NewSaleRepository(db *gorm.DB, logger *zap.Logger) (repo.SaleRepo, error) {
+	if db == nil {
+		return nil, errors.New("database connection cannot be nil for SaleRepository")
+	}
+	if logger == nil {
+		return nil, errors.New("logger cannot be nil for SaleRepository")
+	}
+	return &saleRepositoryImpl{
+		db:     db,
+		logger: logger,
+	}, nil
+}
+
+// CreateSaleInvoice فاکتور، سطرها و سند فروش در یک تراکنش ثبت می‌شوند. حساب‌های فروش نوع سیستمی ندارند،
+// پس سرفصل پیش‌فرض پیش از ثبت تکمیل می‌شود تا حساب‌های تازه‌اضافه‌شده برای کسب‌وکارهای قدیمی هم ساخته شوند.
+func (r *saleRepositoryImpl) CreateSaleInvoice(ctx context.Context, invoice *model.SaleInvoice, entry *model.LedgerEntry) (*model.SaleInvoice, error) {
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := seedDefaultChart(tx, invoice.BIDID); err != nil {
+			return err
+		}
+		if err := tx.Create(invoice).Error; err != nil {
+			return fmt.Errorf("failed to save sale invoice: %w", err)
+		}
+		if err := markItemsSold(tx, invoice); err != nil {
+			return err
+		}
+		entry.DocID = invoice.ID
+		if err := postLedgerEntry(tx, entry); err != nil {
+			return err
+		}
+		invoice.LedgerEntryID = &entry.ID
+		return tx.Model(invoice).Update("ledger_entry_id", entry.ID).Error
+	})
+	if err != nil {
+		r.logger.Error("Failed to create sale invoice", zap.Uint("customer_id", invoice.CustomerID), zap.Error(err))
+		return nil, err
+	}
+	return invoice, nil
+}
+
+func (r *saleRepositoryImpl) GetSaleInvoiceByID(ctx context.Context, id uint) (*model.SaleInvoice, error) {
+	var invoice model.SaleInvoice
+	if err := r.db.WithContext(ctx).Scopes(businessScope(ctx)).Preload("Lines").First(&invoice, id).Error; err != nil {
+		r.logger.Error("failed to get sale invoice by ID", zap.Uint("id", id), zap.Error(err))
+		return nil, err
+	}
+	return &invoice, nil
+}
+
+func (r *saleRepositoryImpl) ListSaleInvoices(ctx context.Context, filter model.SaleInvoiceFilter) ([]model.SaleInvoice, error) {
+	var invoices []model.SaleInvoice
+	q := r.db.WithContext(ctx)
+	if filter.BIDID != 0 {
+		q = q.Where("bid_id = ?", filter.BIDID)
+	}
+	if filter.CustomerID != 0 {
+		q = q.Where("customer_id = ?", filter.CustomerID)
+	}
+	if filter.SalespersonID != nil {
+		q = q.Where("salesperson_id = ?", *filter.SalespersonID)
+	}
+	if filter.Status != "" {
+		q = q.Where("status = ?", filter.Status)
+	}
+	if filter.From != nil {
+		q = q.Where("date >= ?", *filter.From)
+	}
+	if filter.To != nil {
+		q = q.Where("date < ?", *filter.To)
+	}
+	if err := q.Order("date DESC, id DESC").Find(&invoices).Error; err != nil {
+		r.logger.Error("failed to list sale invoices", zap.Error(err))
+		return nil, err
+	}
+	return invoices, nil
+}
+
+func (r *saleRepositoryImpl) VoidSaleInvoice(ctx context.Context, invoice *model.SaleInvoice, reversal *model.LedgerEntry) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		res := tx.Model(&model.SaleInvoice{}).
+			Where("id = ? AND status = ?", invoice.ID, model.SaleInvoiceIssued).
+			Updates(map[string]interface{}{
+				"status":      model.SaleInvoiceVoid,
+				"voided_at":   invoice.VoidedAt,
+				"voided_by":   invoice.VoidedBy,
+				"void_reason": invoice.VoidReason,
+			})
+		if res.Error != nil {
+			return fmt.Errorf("failed to update sale invoice status: %w", res.Error)
+		}
+		if res.RowsAffected == 0 {
+			return ErrSaleInvoiceVoided
+		}
+		if err := restockInvoiceItems(tx, invoice.ID); err != nil {
+			return err
+		}
+		return postLedgerEntry(tx, reversal)
+	})
+}
+
+// SaleLineFacts سطرهای فاکتورهای صادرشده (باطل‌نشده) در بازه را همراه با نام مشتری و فروشنده برمی‌گرداند.
+func (r *saleRepositoryImpl) SaleLineFacts(ctx context.Context, filter model.SalesReportFilter) ([]model.SaleLineFact, error) {
+	var facts []model.SaleLineFact
+	q := r.db.WithContext(ctx).Table("sale_invoice_lines").
+		Joins("JOIN sale_invoices ON sale_invoices.id = sale_invoice_lines.invoice_id AND sale_invoices.deleted_at IS NULL").
+		Joins("LEFT JOIN customers ON customers.id = sale_invoices.customer_id").
+		Joins("LEFT JOIN employees ON employees.id = sale_invoices.salesperson_id").
+		Where("sale_invoice_lines.deleted_at IS NULL").
+		Where("sale_invoices.bid_id = ? AND sale_invoices.status = ?", filter.BIDID, model.SaleInvoiceIssued).
+		Where("sale_invoices.date >= ? AND sale_invoices.date < ?", filter.From, filter.To)
+	if filter.SalespersonID != nil {
+		q = q.Where("sale_invoices.salesperson_id = ?", *filter.SalespersonID)
+	}
+	err := q.Select(`sale_invoices.id AS invoice_id, sale_invoices.date, sale_invoices.customer_id,
+		TRIM(COALESCE(customers.name, '') || ' ' || COALESCE(customers.family_name, '')) AS customer_name,
+		sale_invoices.salesperson_id, COALESCE(employees.name, '') AS salesperson_name,
+		sale_invoice_lines.category, sale_invoice_lines.weight, sale_invoice_lines.labor_fee,
+		sale_invoice_lines.cost, sale_invoice_lines.amount`).
+		Order("sale_invoices.date, sale_invoices.id").
+		Scan(&facts).Error
+	if err != nil {
+		r.logger.Error("failed to load sale lines for report", zap.Uint("bid_id", filter.BIDID), zap.Error(err))
+		return nil, err
+	}
+	return facts, nil
+}
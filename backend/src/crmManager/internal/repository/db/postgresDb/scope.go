@@ -0,0 +1,57 @@
+package postgresDb
+
+import (
+	"context"
+
+	"common-gold/authz"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// ownerScope محدودیت ردیفی که AuthZMiddleware برای مجوز مسیر در ctx گذاشته روی کوئری اعمال می‌کند.
+// ستون مالک در crmManager شناسه کارمند است و کاربر (UUID داخل JWT) از طریق Employee.UserRef به کارمند نگاشت می‌شود.
+// فراخوانی‌های داخلی بدون Access دست‌نخورده می‌مانند؛ کاربری که شناسه معتبر یا کارمند متناظر ندارد هیچ ردیفی نمی‌بیند.
+func ownerScope(ctx context.Context) func(*gorm.DB) *gorm.DB {
+	return func(db *gorm.DB) *gorm.DB {
+		access, ok := authz.AccessFrom(ctx)
+		if !ok || access.OwnerColumn == "" {
+			return db
+		}
+		ref, err := uuid.Parse(access.OwnerID)
+		if err != nil {
+			return db.Where("1 = 0")
+		}
+		return db.Where(clause.Expr{
+			SQL:  "? IN (SELECT id FROM employees WHERE user_ref = ? AND deleted_at IS NULL)",
+			Vars: []interface{}{clause.Column{Table: clause.CurrentTable, Name: access.OwnerColumn}, ref.String()},
+		})
+	}
+}
+
+// businessScope رکوردی را که با شناسه خوانده می‌شود به کسب‌وکاری که درخواست به آن محدود است (Access.BusinessID) محدود
+// می‌کند؛ رکورد کسب‌وکار دیگر مانند رکورد ناموجود به ErrRecordNotFound می‌رسد. جدول باید ستون bid_id داشته باشد.
+func businessScope(ctx context.Context) func(*gorm.DB) *gorm.DB {
+	return func(db *gorm.DB) *gorm.DB {
+		access, ok := authz.AccessFrom(ctx)
+		if !ok || access.BusinessID == 0 {
+			return db
+		}
+		return db.Where(clause.Eq{Column: clause.Column{Table: clause.CurrentTable, Name: "bid_id"}, Value: access.BusinessID})
+	}
+}
+
+// bankAccountBusinessScope همان businessScope برای جدول‌هایی که bid_id ندارند و از طریق حساب بانکی به کسب‌وکار می‌رسند.
+func bankAccountBusinessScope(ctx context.Context) func(*gorm.DB) *gorm.DB {
+	return func(db *gorm.DB) *gorm.DB {
+		access, ok := authz.AccessFrom(ctx)
+		if !ok || access.BusinessID == 0 {
+			return db
+		}
+		return db.Where(clause.Expr{
+			SQL:  "? IN (SELECT id FROM bank_accounts WHERE bid_id = ?)",
+			Vars: []interface{}{clause.Column{Table: clause.CurrentTable, Name: "bank_account_id"}, access.BusinessID},
+		})
+	}
+}
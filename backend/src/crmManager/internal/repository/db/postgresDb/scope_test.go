@@ -0,0 +1,128 @@
+package postgresDb
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"common-gold/authz"
+
+	"crm-gold/internal/model"
+
+	"gorm.io/driver/postgres"
+	"gorm.io/gorm"
+)
+
+// dryRunDB فقط SQL می‌سازد و به پایگاه داده وصل نمی‌شود.
+func dryRunDB(t *testing.T) *gorm.DB {
+	t.Helper()
+	db, err := gorm.Open(postgres.New(postgres.Config{DSN: "host=localhost dbname=test"}), &gorm.Config{DryRun: true, DisableAutomaticPing: true})
+	if err != nil {
+		t.Fatal(err)
+	}
+	return db
+}
+
+func TestOwnerScope(t *testing.T) {
+	const userRef = "3f1c2d4e-0000-4000-8000-000000000001"
+	restricted := authz.Access{OwnerColumn: "assigned_employee_id", OwnerID: userRef}
+	tests := []struct {
+		name     string
+		ctx      context.Context
+		wantSQL  string
+		wantVars []interface{}
+	}{
+		{
+			name:    "internal call",
+			ctx:     context.Background(),
+			wantSQL: `SELECT * FROM "customers" WHERE "customers"."deleted_at" IS NULL`,
+		},
+		{
+			name:    "unrestricted user",
+			ctx:     authz.WithAccess(context.Background(), authz.Access{}),
+			wantSQL: `SELECT * FROM "customers" WHERE "customers"."deleted_at" IS NULL`,
+		},
+		{
+			name:     "restricted user",
+			ctx:      authz.WithAccess(context.Background(), restricted),
+			wantSQL:  `SELECT * FROM "customers" WHERE ("customers"."assigned_employee_id" IN (SELECT id FROM employees WHERE user_ref = $1 AND deleted_at IS NULL)) AND "customers"."deleted_at" IS NULL`,
+			wantVars: []interface{}{userRef},
+		},
+		{
+			name:    "numeric owner id",
+			ctx:     authz.WithAccess(context.Background(), authz.Access{OwnerColumn: "assigned_employee_id", OwnerID: "42"}),
+			wantSQL: `SELECT * FROM "customers" WHERE 1 = 0 AND "customers"."deleted_at" IS NULL`,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			stmt := dryRunDB(t).Scopes(ownerScope(tt.ctx)).Find(&[]model.Customer{}).Statement
+			if got := strings.TrimSpace(stmt.SQL.String()); got != tt.wantSQL {
+				t.Errorf("SQL = %s\nwant  %s", got, tt.wantSQL)
+			}
+			if len(stmt.Vars) != len(tt.wantVars) {
+				t.Fatalf("vars = %v, want %v", stmt.Vars, tt.wantVars)
+			}
+			for i := range tt.wantVars {
+				if stmt.Vars[i] != tt.wantVars[i] {
+					t.Errorf("var %d = %v, want %v", i, stmt.Vars[i], tt.wantVars[i])
+				}
+			}
+		})
+	}
+}
+
+func TestBusinessScope(t *testing.T) {
+	tests := []struct {
+		name     string
+		ctx      context.Context
+		scope    func(context.Context) func(*gorm.DB) *gorm.DB
+		model    interface{}
+		wantSQL  string
+		wantVars []interface{}
+	}{
+		{
+			name:    "internal call",
+			ctx:     context.Background(),
+			scope:   businessScope,
+			model:   &model.SaleInvoice{},
+			wantSQL: `SELECT * FROM "sale_invoices" WHERE "sale_invoices"."id" = $1 AND "sale_invoices"."deleted_at" IS NULL ORDER BY "sale_invoices"."id" LIMIT $2`,
+		},
+		{
+			name:    "token without business",
+			ctx:     authz.WithAccess(context.Background(), authz.Access{OwnerColumn: "salesperson_id", OwnerID: "42"}),
+			scope:   businessScope,
+			model:   &model.SaleInvoice{},
+			wantSQL: `SELECT * FROM "sale_invoices" WHERE "sale_invoices"."id" = $1 AND "sale_invoices"."deleted_at" IS NULL ORDER BY "sale_invoices"."id" LIMIT $2`,
+		},
+		{
+			name:     "token limited to a business",
+			ctx:      authz.WithAccess(context.Background(), authz.Access{BusinessID: 3}),
+			scope:    businessScope,
+			model:    &model.SaleInvoice{},
+			wantSQL:  `SELECT * FROM "sale_invoices" WHERE "sale_invoices"."id" = $1 AND "sale_invoices"."bid_id" = $2 AND "sale_invoices"."deleted_at" IS NULL ORDER BY "sale_invoices"."id" LIMIT $3`,
+			wantVars: []interface{}{7, uint(3)},
+		},
+		{
+			name:     "statement line through its bank account",
+			ctx:      authz.WithAccess(context.Background(), authz.Access{BusinessID: 3}),
+			scope:    bankAccountBusinessScope,
+			model:    &model.BankStatementLine{},
+			wantSQL:  `SELECT * FROM "bank_statement_lines" WHERE "bank_statement_lines"."id" = $1 AND "bank_statement_lines"."bank_account_id" IN (SELECT id FROM bank_accounts WHERE bid_id = $2) AND "bank_statement_lines"."deleted_at" IS NULL ORDER BY "bank_statement_lines"."id" LIMIT $3`,
+			wantVars: []interface{}{7, uint(3)},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			stmt := dryRunDB(t).Scopes(tt.scope(tt.ctx)).First(tt.model, 7).Statement
+			if got := strings.TrimSpace(stmt.SQL.String()); got != tt.wantSQL {
+				t.Errorf("SQL = %s\nwant  %s", got, tt.wantSQL)
+			}
+			for i := range tt.wantVars {
+				if stmt.Vars[i] != tt.wantVars[i] {
+					t.Errorf("var %d = %v, want %v", i, stmt.Vars[i], tt.wantVars[i])
+				}
+			}
+		})
+	}
+}
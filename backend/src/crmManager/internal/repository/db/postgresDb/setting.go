@@ -0,0 +1,56 @@
+package postgresDb
+
+import (
+	"context"
+	"crm-gold/internal/model"
+	"crm-gold/internal/repository/repo"
+	"errors"
+
+	"go.uber.org/zap"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type settingRepositoryImpl struct {
+	db     *gorm.DB
+	logger *zap.Logger
+}
+
+func NewSettingRepository(db *gorm.DB, logger *zap.Logger) (repo.SettingRepo, error) {
+	if db == nil {
+		return nil, errors.New("database connection cannot be nil for SettingRepository")
+	}
+	if logger == nil {
+		return nil, errors.New("logger cannot be nil for SettingRepository")
+	}
+	return &settingRepositoryImpl{
+		db:     db,
+		logger: logger,
+	}, nil
+}
+
+// GetBusinessSetting اگر کسب‌وکار هنوز تنظیماتی ذخیره نکرده باشد، مقادیر پیش‌فرض را برمی‌گرداند.
+func (r *settingRepositoryImpl) GetBusinessSetting(ctx context.Context, bidID uint) (*model.BusinessSetting, error) {
+	var setting model.BusinessSetting
+	err := r.db.WithContext(ctx).Where("bid_id = ?", bidID).First(&setting).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return &model.BusinessSetting{BIDID: bidID}, nil
+	}
+	if err != nil {
+		r.logger.Error("failed to get business setting", zap.Uint("bid_id", bidID), zap.Error(err))
+		return nil, err
+	}
+	return &setting, nil
+}
+
+func (r *settingRepositoryImpl) SaveBusinessSetting(ctx context.Context, setting *model.BusinessSetting) error {
+	err := r.db.WithContext(ctx).Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "bid_id"}},
+		DoUpdates: clause.AssignmentColumns([]string{"require_sufficient_balance", "gold_rate", "gold_rate_updated_at", "updated_at"}),
+	}).Create(setting).Error
+	if err != nil {
+		r.logger.Error("failed to save business setting", zap.Uint("bid_id", setting.BIDID), zap.Error(err))
+		return err
+	}
+	return nil
+}
@@ -0,0 +1,201 @@
+package postgresDb
+
+import (
+	"context"
+	"crm-gold/internal/model"
+	"crm-gold/internal/repository/repo"
+	"errors"
+	"time"
+
+	"go.uber.org/zap"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// ErrTaxSubmissionExists یعنی فاکتور برای همین موضوع ارسال رد‌نشده دیگری دارد.
+var ErrTaxSubmissionExists = errors.New("invoice already has an active tax submission for this subject")
+
+type taxRepositoryImpl struct {
+	db     *gorm.DB
+	logger *zap.Logger
+}
+
+func NewTaxRepository(db *gorm.DB, logger *zap.Logger) (repo.TaxRepo, error) {
+	if db == nil {
+		return nil, errors.New("database connection cannot be nil for TaxRepository")
+	}
+	if logger == nil {
+		return nil, errors.New("logger cannot be nil for TaxRepository")
+	}
+	return &taxRepositoryImpl{
+		db:     db,
+		logger: logger,
+	}, nil
+}
+
+// GetTaxSetting در نبود ردیف، تنظیمات غیرفعال با مقادیر پیش‌فرض برگردانده می‌شود.
+func (r *taxRepositoryImpl) GetTaxSetting(ctx context.Context, bidID uint) (*model.TaxSetting, error) {
+	var setting model.TaxSetting
+	err := r.db.WithContext(ctx).Where("bid_id = ?", bidID).First(&setting).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return &model.TaxSetting{BIDID: bidID, MeasureUnit: model.DefaultTaxMeasureUnit}, nil
+	}
+	if err != nil {
+		r.logger.Error("failed to get tax setting", zap.Uint("bid_id", bidID), zap.Error(err))
+		return nil, err
+	}
+	setting.HasPrivateKey = setting.PrivateKey != ""
+	return &setting, nil
+}
+
+func (r *taxRepositoryImpl) SaveTaxSetting(ctx context.Context, setting *model.TaxSetting) error {
+	err := r.db.WithContext(ctx).Clauses(clause.OnConflict{
+		Columns: []clause.Column{{Name: "bid_id"}},
+		DoUpdates: clause.AssignmentColumns([]string{"enabled", "tax_memory_id", "economic_code", "private_key",
+			"product_id", "measure_unit", "updated_at"}),
+	}).Create(setting).Error
+	if err != nil {
+		r.logger.Error("failed to save tax setting", zap.Uint("bid_id", setting.BIDID), zap.Error(err))
+		return err
+	}
+	return nil
+}
+
+func (r *taxRepositoryImpl) CreateTaxSubmission(ctx context.Context, submission *model.TaxSubmission) error {
+	if err := r.db.WithContext(ctx).Create(submission).Error; err != nil {
+		if IsUniqueViolation(err) {
+			return ErrTaxSubmissionExists
+		}
+		r.logger.Error("failed to create tax submission", zap.Uint("sale_invoice_id", submission.SaleInvoiceID), zap.Error(err))
+		return err
+	}
+	return nil
+}
+
+func (r *taxRepositoryImpl) UpdateTaxSubmission(ctx context.Context, submission *model.TaxSubmission) error {
+	err := r.db.WithContext(ctx).Model(submission).
+		Select("tax_id", "uid", "reference_number", "status", "payload", "errors", "attempts", "submitted_at", "checked_at").
+		Updates(submission).Error
+	if err != nil {
+		r.logger.Error("failed to update tax submission", zap.Uint("id", submission.ID), zap.Error(err))
+		return err
+	}
+	return nil
+}
+
+func (r *taxRepositoryImpl) GetTaxSubmissionByID(ctx context.Context, id uint) (*model.TaxSubmission, error) {
+	var submission model.TaxSubmission
+	if err := r.db.WithContext(ctx).Scopes(businessScope(ctx)).First(&submission, id).Error; err != nil {
+		r.logger.Error("failed to get tax submission by ID", zap.Uint("id", id), zap.Error(err))
+		return nil, err
+	}
+	return &submission, nil
+}
+
+func (r *taxRepositoryImpl) ListTaxSubmissions(ctx context.Context, filter model.TaxSubmissionFilter) ([]model.TaxSubmission, error) {
+	var submissions []model.TaxSubmission
+	q := r.db.WithContext(ctx)
+	if filter.BIDID != 0 {
+		q = q.Where("bid_id = ?", filter.BIDID)
+	}
+	if filter.SaleInvoiceID != 0 {
+		q = q.Where("sale_invoice_id = ?", filter.SaleInvoiceID)
+	}
+	if filter.Status != "" {
+		q = q.Where("status = ?", filter.Status)
+	}
+	if err := q.Order("id DESC").Find(&submissions).Error; err != nil {
+		r.logger.Error("failed to list tax submissions", zap.Error(err))
+		return nil, err
+	}
+	return submissions, nil
+}
+
+func (r *taxRepositoryImpl) CreateTaxRule(ctx context.Context, rule *model.TaxRule) error {
+	if err := r.db.WithContext(ctx).Create(rule).Error; err != nil {
+		r.logger.Error("failed to create tax rule", zap.Uint("bid_id", rule.BIDID), zap.Error(err))
+		return err
+	}
+	return nil
+}
+
+// UpdateTaxRule فیلدها و فهرست انواع مشتری معاف در یک تراکنش جایگزین می‌شوند.
+func (r *taxRepositoryImpl) UpdateTaxRule(ctx context.Context, rule *model.TaxRule) error {
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		err := tx.Model(rule).
+			Select("name", "rate", "effective_from", "effective_to", "tax_gold", "tax_labor", "tax_profit", "tax_commission").
+			Updates(rule).Error
+		if err != nil {
+			return err
+		}
+		return tx.Model(rule).Association("ExemptCustomerTypes").Replace(rule.ExemptCustomerTypes)
+	})
+	if err != nil {
+		r.logger.Error("failed to update tax rule", zap.Uint("id", rule.ID), zap.Error(err))
+		return err
+	}
+	return nil
+}
+
+func (r *taxRepositoryImpl) GetTaxRuleByID(ctx context.Context, id uint) (*model.TaxRule, error) {
+	var rule model.TaxRule
+	if err := r.db.WithContext(ctx).Scopes(businessScope(ctx)).Preload("ExemptCustomerTypes").First(&rule, id).Error; err != nil {
+		r.logger.Error("failed to get tax rule by ID", zap.Uint("id", id), zap.Error(err))
+		return nil, err
+	}
+	return &rule, nil
+}
+
+func (r *taxRepositoryImpl) ListTaxRules(ctx context.Context, bidID uint) ([]model.TaxRule, error) {
+	var rules []model.TaxRule
+	err := r.db.WithContext(ctx).Preload("ExemptCustomerTypes").
+		Where("bid_id = ?", bidID).
+		Order("effective_from DESC, id DESC").
+		Find(&rules).Error
+	if err != nil {
+		r.logger.Error("failed to list tax rules", zap.Uint("bid_id", bidID), zap.Error(err))
+		return nil, err
+	}
+	return rules, nil
+}
+
+// TaxRuleAt قاعده معتبر در تاریخ at؛ اگر قاعده‌ای نباشد nil برمی‌گردد.
+func (r *taxRepositoryImpl) TaxRuleAt(ctx context.Context, bidID uint, at time.Time) (*model.TaxRule, error) {
+	var rule model.TaxRule
+	err := r.db.WithContext(ctx).Preload("ExemptCustomerTypes").
+		Where("bid_id = ? AND effective_from <= ? AND (effective_to IS NULL OR effective_to > ?)", bidID, at, at).
+		Order("effective_from DESC").
+		First(&rule).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, nil
+	}
+	if err != nil {
+		r.logger.Error("failed to find tax rule", zap.Uint("bid_id", bidID), zap.Time("at", at), zap.Error(err))
+		return nil, err
+	}
+	return &rule, nil
+}
+
+func (r *taxRepositoryImpl) GetCusTypesByIDs(ctx context.Context, ids []uint) ([]model.CusType, error) {
+	var types []model.CusType
+	if len(ids) == 0 {
+		return types, nil
+	}
+	if err := r.db.WithContext(ctx).Where("id IN ?", ids).Find(&types).Error; err != nil {
+		r.logger.Error("failed to load customer types", zap.Error(err))
+		return nil, err
+	}
+	return types, nil
+}
+
+func (r *taxRepositoryImpl) CustomerTypeIDs(ctx context.Context, customerID uint) ([]uint, error) {
+	var ids []uint
+	err := r.db.WithContext(ctx).Table("customer_customer_types").
+		Where("customer_id = ?", customerID).
+		Pluck("cus_type_id", &ids).Error
+	if err != nil {
+		r.logger.Error("failed to load customer type ids", zap.Uint("customer_id", customerID), zap.Error(err))
+		return nil, err
+	}
+	return ids, nil
+}
@@ -0,0 +1,154 @@
+package postgresDb
+
+import (
+	"context"
+	"crm-gold/internal/model"
+	"crm-gold/internal/repository/repo"
+	"errors"
+	"fmt"
+	"time"
+
+	"go.uber.org/zap"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+var (
+	ErrInsufficientBalance     = errors.New("source account does not have enough balance")
+	ErrTransferAlreadyReversed = errors.New("transfer is already reversed")
+)
+
+type transferRepositoryImpl struct {
+	db     *gorm.DB
+	logger *zap.Logger
+}
+
+func NewTransferRepository(db *gorm.DB, logger *zap.Logger) (repo.TransferRepo, error) {
+	if db == nil {
+		return nil, errors.New("database connection cannot be nil for TransferRepository")
+	}
+	if logger == nil {
+		return nil, errors.New("logger cannot be nil for TransferRepository")
+	}
+	return &transferRepositoryImpl{
+		db:     db,
+		logger: logger,
+	}, nil
+}
+
+// lockTransferEnds ردیف صندوق‌ها و حساب‌های بانکی دو طرف انتقال را قفل می‌کند تا کنترل موجودی و بستن روز با ثبت همزمان تداخل نکند.
+func lockTransferEnds(tx *gorm.DB, transfer *model.Transfer, date time.Time) error {
+	ends := []struct {
+		kind string
+		id   uint
+	}{{transfer.FromKind, transfer.FromID}, {transfer.ToKind, transfer.ToID}}
+	for _, end := range ends {
+		switch end.kind {
+		case model.LedgerAccountFund:
+			if err := ensureFundOpen(tx, end.id, date); err != nil {
+				return err
+			}
+		case model.LedgerAccountBank:
+			var account model.BankAccount
+			if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).First(&account, end.id).Error; err != nil {
+				return fmt.Errorf("failed to lock bank account %d: %w", end.id, err)
+			}
+		}
+	}
+	return nil
+}
+
+func (r *transferRepositoryImpl) CreateTransfer(ctx context.Context, transfer *model.Transfer, entries []*model.LedgerEntry, requireBalance bool) (*model.Transfer, error) {
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := lockTransferEnds(tx, transfer, transfer.Date); err != nil {
+			return err
+		}
+		if requireBalance && transfer.FromKind != model.LedgerAccountPerson {
+			balance, err := lowestBalanceFrom(tx, transfer.FromKind, transfer.FromID, transfer.Date)
+			if err != nil {
+				return fmt.Errorf("failed to compute source balance: %w", err)
+			}
+			if required := transfer.Amount.Add(transfer.Fee); balance.Cmp(required) < 0 {
+				return fmt.Errorf("%w: available %s, required %s", ErrInsufficientBalance, balance, required)
+			}
+		}
+		if err := tx.Create(transfer).Error; err != nil {
+			return fmt.Errorf("failed to save transfer: %w", err)
+		}
+		for _, entry := range entries {
+			entry.DocID = transfer.ID
+			if err := postLedgerEntry(tx, entry); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		r.logger.Error("Failed to create transfer", zap.String("number", transfer.Number), zap.Error(err))
+		return nil, err
+	}
+	r.logger.Info("Transfer saved successfully to database.", zap.Uint("transfer_id", transfer.ID))
+	return transfer, nil
+}
+
+func (r *transferRepositoryImpl) GetTransferByID(ctx context.Context, id uint) (*model.Transfer, error) {
+	var transfer model.Transfer
+	if err := r.db.WithContext(ctx).Scopes(businessScope(ctx)).First(&transfer, id).Error; err != nil {
+		r.logger.Error("failed to get transfer by ID", zap.Uint("id", id), zap.Error(err))
+		return nil, err
+	}
+	return &transfer, nil
+}
+
+func (r *transferRepositoryImpl) ListTransfers(ctx context.Context, filter model.TransferFilter) ([]model.Transfer, error) {
+	var transfers []model.Transfer
+	q := r.db.WithContext(ctx)
+	if filter.BIDID != 0 {
+		q = q.Where("bid_id = ?", filter.BIDID)
+	}
+	if filter.Kind != "" && filter.ID != 0 {
+		q = q.Where("(from_kind = ? AND from_id = ?) OR (to_kind = ? AND to_id = ?)", filter.Kind, filter.ID, filter.Kind, filter.ID)
+	}
+	if filter.Status != "" {
+		q = q.Where("status = ?", filter.Status)
+	}
+	if filter.From != nil {
+		q = q.Where("date >= ?", *filter.From)
+	}
+	if filter.To != nil {
+		q = q.Where("date < ?", *filter.To)
+	}
+	if err := q.Order("date DESC, id DESC").Find(&transfers).Error; err != nil {
+		r.logger.Error("failed to list transfers", zap.Error(err))
+		return nil, err
+	}
+	return transfers, nil
+}
+
+func (r *transferRepositoryImpl) ReverseTransfer(ctx context.Context, transfer *model.Transfer, reversals []*model.LedgerEntry) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := lockTransferEnds(tx, transfer, *transfer.ReversedAt); err != nil {
+			return err
+		}
+		res := tx.Model(&model.Transfer{}).
+			Where("id = ? AND status = ?", transfer.ID, model.TransferStatusPosted).
+			Updates(map[string]interface{}{
+				"status":          model.TransferStatusReversed,
+				"reversed_at":     transfer.ReversedAt,
+				"reversed_by":     transfer.ReversedBy,
+				"reversal_reason": transfer.ReversalReason,
+			})
+		if res.Error != nil {
+			return fmt.Errorf("failed to update transfer status: %w", res.Error)
+		}
+		if res.RowsAffected == 0 {
+			return ErrTransferAlreadyReversed
+		}
+		for _, entry := range reversals {
+			if err := postLedgerEntry(tx, entry); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
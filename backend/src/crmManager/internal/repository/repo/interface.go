@@ -3,6 +3,9 @@ package repo
 import (
 	"context"
 	"crm-gold/internal/model"
+	"time"
+
+	"common-gold/money"
 )
 
 type CustRepo interface {
@@ -13,5 +16,166 @@ type CustRepo interface {
 	CheckCustomerCodeExists(ctx context.Context, bidID uint, code string) (bool, error)
 	FindOrCreateCusType(ctx context.Context, label string) (*model.CusType, error)
 	GetAllCustomers(ctx context.Context) ([]model.Customer, error)
+	ListCustomersWithInitialBalance(ctx context.Context, bidID uint) ([]model.Customer, error)
+	GetEmployeeByUserRef(ctx context.Context, userRef string) (*model.Employee, error)
 	// ... سایر متدهای CRUD (Update, Delete, ListWithFilters)
 }
+
+type ChequeRepo interface {
+	CreateCheque(ctx context.Context, cheque *model.Cheque, history *model.ChequeStatusHistory, entry *model.LedgerEntry) (*model.Cheque, error)
+	GetChequeByID(ctx context.Context, id uint) (*model.Cheque, error)
+	ListCheques(ctx context.Context, filter model.ChequeFilter) ([]model.Cheque, error)
+	ApplyChequeTransition(ctx context.Context, cheque *model.Cheque, fromStatus string, history *model.ChequeStatusHistory, entry *model.LedgerEntry) error
+	ClaimChequeAlert(ctx context.Context, alert *model.ChequeAlert) (*model.ChequeAlert, error)
+	UpdateChequeAlert(ctx context.Context, alert *model.ChequeAlert) error
+}
+
+type LedgerRepo interface {
+	GetEntriesByDocument(ctx context.Context, docType string, docID uint) ([]model.LedgerEntry, error)
+	GetEntryByID(ctx context.Context, id uint) (*model.LedgerEntry, error)
+	// GetAccountBalance مانده تومانی؛ با currencyID فقط سطرهای آن ارز جمع می‌شوند.
+	GetAccountBalance(ctx context.Context, accountKind string, accountID uint, currencyID *uint, before time.Time) (money.Amount, error)
+	GetCurrencyBalance(ctx context.Context, accountKind string, accountID uint, currencyID uint, before time.Time) (money.Foreign, error)
+	GetAccountLines(ctx context.Context, accountKind string, accountID uint, currencyID *uint, from, to time.Time) ([]model.LedgerLineWithEntry, error)
+	PostJournalEntry(ctx context.Context, entry *model.LedgerEntry) (*model.LedgerEntry, error)
+	ReverseJournalEntry(ctx context.Context, entry *model.LedgerEntry, reversal *model.LedgerEntry) error
+	ListEntries(ctx context.Context, filter model.JournalFilter) ([]model.LedgerEntry, error)
+	GetCodeBalance(ctx context.Context, bidID uint, accountCode string, detailID *uint, before time.Time) (money.Amount, money.Weight, error)
+	GetCodeLines(ctx context.Context, bidID uint, accountCode string, detailID *uint, from, to time.Time) ([]model.LedgerLineWithEntry, error)
+	GetCodeTotals(ctx context.Context, bidID uint, from *time.Time, to time.Time, excludeDocTypes []string) ([]model.AccountTotalRow, error)
+}
+
+type AccountRepo interface {
+	EnsureDefaultChart(ctx context.Context, bidID uint) error
+	ListAccounts(ctx context.Context, bidID uint) ([]model.Account, error)
+	GetAccountByID(ctx context.Context, id uint) (*model.Account, error)
+	GetAccountByCode(ctx context.Context, bidID uint, code string) (*model.Account, error)
+	CreateAccount(ctx context.Context, account *model.Account) (*model.Account, error)
+	UpdateAccount(ctx context.Context, account *model.Account) error
+}
+
+type FundRepo interface {
+	CreateFund(ctx context.Context, fund *model.Fund, openingEntries []*model.LedgerEntry) (*model.Fund, error)
+	GetFundByID(ctx context.Context, id uint) (*model.Fund, error)
+	ListFunds(ctx context.Context, bidID uint) ([]model.Fund, error)
+	CreateCashVoucher(ctx context.Context, voucher *model.CashVoucher, entry *model.LedgerEntry) (*model.CashVoucher, error)
+	GetCashVoucherByID(ctx context.Context, id uint) (*model.CashVoucher, error)
+	ListCashVouchers(ctx context.Context, fundID uint, from, to *time.Time) ([]model.CashVoucher, error)
+	UpdateCashVoucher(ctx context.Context, voucher *model.CashVoucher, reversal, entry *model.LedgerEntry) error
+	DeleteCashVoucher(ctx context.Context, voucher *model.CashVoucher, reversal *model.LedgerEntry) error
+	CloseFundDay(ctx context.Context, fund *model.Fund, dayClose *model.FundDayClose) error
+}
+
+type BankRepo interface {
+	CreateBankAccount(ctx context.Context, account *model.BankAccount, openingEntry *model.LedgerEntry) (*model.BankAccount, error)
+	GetBankAccountByID(ctx context.Context, id uint) (*model.BankAccount, error)
+	ListBankAccounts(ctx context.Context, bidID uint) ([]model.BankAccount, error)
+	AddPOSTerminal(ctx context.Context, terminal *model.POSTerminal) (*model.POSTerminal, error)
+	CreateBankTransaction(ctx context.Context, txn *model.BankTransaction, entry *model.LedgerEntry) (*model.BankTransaction, error)
+	GetBankTransactionByID(ctx context.Context, id uint) (*model.BankTransaction, error)
+	ListBankTransactions(ctx context.Context, accountID uint, from, to *time.Time) ([]model.BankTransaction, error)
+	DeleteBankTransaction(ctx context.Context, txn *model.BankTransaction, reversal *model.LedgerEntry) error
+	ListReconciliationCandidates(ctx context.Context, accountID uint, from, to time.Time) ([]model.ReconciliationCandidate, error)
+	GetReconciliationCandidate(ctx context.Context, accountID, ledgerLineID uint) (*model.ReconciliationCandidate, error)
+	SaveStatementImport(ctx context.Context, imp *model.BankStatementImport) (*model.BankStatementImport, error)
+	GetStatementLineByID(ctx context.Context, id uint) (*model.BankStatementLine, error)
+	ListStatementLines(ctx context.Context, accountID uint, status string) ([]model.BankStatementLine, error)
+	UpdateStatementLineMatch(ctx context.Context, line *model.BankStatementLine, fromStatus string) error
+}
+
+// AuditRepo دفتر رویداد فقط درج و خواندن دارد.
+type AuditRepo interface {
+	AppendAuditLog(ctx context.Context, log *model.AuditLog) error
+	ListAuditLogs(ctx context.Context, filter model.AuditFilter) ([]model.AuditLog, int64, error)
+	// ScanAuditLogs حداکثر limit رویداد با Seq بزرگ‌تر از afterSeq را به ترتیب Seq برمی‌گرداند.
+	ScanAuditLogs(ctx context.Context, afterSeq uint64, limit int) ([]model.AuditLog, error)
+}
+
+type SettingRepo interface {
+	GetBusinessSetting(ctx context.Context, bidID uint) (*model.BusinessSetting, error)
+	SaveBusinessSetting(ctx context.Context, setting *model.BusinessSetting) error
+}
+
+type TransferRepo interface {
+	CreateTransfer(ctx context.Context, transfer *model.Transfer, entries []*model.LedgerEntry, requireBalance bool) (*model.Transfer, error)
+	GetTransferByID(ctx context.Context, id uint) (*model.Transfer, error)
+	ListTransfers(ctx context.Context, filter model.TransferFilter) ([]model.Transfer, error)
+	ReverseTransfer(ctx context.Context, transfer *model.Transfer, reversals []*model.LedgerEntry) error
+}
+
+// CenterAmount جمع خالص سطرهای دفتر یک مرکز هزینه یا درآمد
+type CenterAmount struct {
+	CenterID uint
+	Amount   money.Amount
+}
+
+type CenterRepo interface {
+	CreateCenter(ctx context.Context, center *model.FinanceCenter) (*model.FinanceCenter, error)
+	GetCenterByID(ctx context.Context, id uint) (*model.FinanceCenter, error)
+	ListCenters(ctx context.Context, bidID uint, centerType string) ([]model.FinanceCenter, error)
+	UpdateCenter(ctx context.Context, center *model.FinanceCenter, oldPath string) error
+	CreateCenterVoucher(ctx context.Context, voucher *model.CenterVoucher, entry *model.LedgerEntry) (*model.CenterVoucher, error)
+	GetCenterVoucherByID(ctx context.Context, id uint) (*model.CenterVoucher, error)
+	ListCenterVouchers(ctx context.Context, filter model.CenterVoucherFilter) ([]model.CenterVoucher, error)
+	ReverseCenterVoucher(ctx context.Context, voucher *model.CenterVoucher, reversal *model.LedgerEntry) error
+	CenterTotals(ctx context.Context, bidID uint, accountKind string, from, to time.Time) ([]CenterAmount, error)
+	CreateRecurringExpense(ctx context.Context, rec *model.RecurringExpense) (*model.RecurringExpense, error)
+	GetRecurringExpenseByID(ctx context.Context, id uint) (*model.RecurringExpense, error)
+	ListRecurringExpenses(ctx context.Context, bidID uint) ([]model.RecurringExpense, error)
+	UpdateRecurringExpense(ctx context.Context, rec *model.RecurringExpense) error
+	ListDueRecurringExpenses(ctx context.Context, asOf time.Time) ([]model.RecurringExpense, error)
+	RunRecurringExpense(ctx context.Context, rec *model.RecurringExpense, voucher *model.CenterVoucher, entry *model.LedgerEntry, nextRun time.Time) error
+}
+
+type FiscalYearRepo interface {
+	CreateFiscalYear(ctx context.Context, fy *model.FiscalYear, opening *model.LedgerEntry) (*model.FiscalYear, error)
+	GetFiscalYearByID(ctx context.Context, id uint) (*model.FiscalYear, error)
+	GetFiscalYearByJalaliYear(ctx context.Context, bidID uint, jalaliYear int) (*model.FiscalYear, error)
+	ListFiscalYears(ctx context.Context, bidID uint) ([]model.FiscalYear, error)
+	ListFiscalYearEvents(ctx context.Context, fiscalYearID uint) ([]model.FiscalYearEvent, error)
+	YearEndBalances(ctx context.Context, bidID uint, before time.Time) ([]model.AccountBalanceRow, uint, error)
+	CloseFiscalYear(ctx context.Context, fy, next *model.FiscalYear, lastLineID uint, profitClosing, closing, nextOpening *model.LedgerEntry) error
+	ReopenFiscalYear(ctx context.Context, fy, next *model.FiscalYear, reversals []*model.LedgerEntry, actor, reason string) error
+}
+
+type SaleRepo interface {
+	CreateSaleInvoice(ctx context.Context, invoice *model.SaleInvoice, entry *model.LedgerEntry) (*model.SaleInvoice, error)
+	GetSaleInvoiceByID(ctx context.Context, id uint) (*model.SaleInvoice, error)
+	ListSaleInvoices(ctx context.Context, filter model.SaleInvoiceFilter) ([]model.SaleInvoice, error)
+	VoidSaleInvoice(ctx context.Context, invoice *model.SaleInvoice, reversal *model.LedgerEntry) error
+	SaleLineFacts(ctx context.Context, filter model.SalesReportFilter) ([]model.SaleLineFact, error)
+}
+
+type InventoryRepo interface {
+	CreateItem(ctx context.Context, item *model.InventoryItem) (*model.InventoryItem, error)
+	GetItemByID(ctx context.Context, id uint) (*model.InventoryItem, error)
+	ListItems(ctx context.Context, filter model.InventoryItemFilter) ([]model.InventoryItem, error)
+	UpdateItem(ctx context.Context, item *model.InventoryItem) error
+	StockSummary(ctx context.Context, bidID uint) ([]model.InventoryStockRow, error)
+	ListSlowMovers(ctx context.Context, bidID uint, receivedBefore time.Time) ([]model.InventoryItem, error)
+}
+
+type TaxRepo interface {
+	GetTaxSetting(ctx context.Context, bidID uint) (*model.TaxSetting, error)
+	SaveTaxSetting(ctx context.Context, setting *model.TaxSetting) error
+	CreateTaxSubmission(ctx context.Context, submission *model.TaxSubmission) error
+	UpdateTaxSubmission(ctx context.Context, submission *model.TaxSubmission) error
+	GetTaxSubmissionByID(ctx context.Context, id uint) (*model.TaxSubmission, error)
+	ListTaxSubmissions(ctx context.Context, filter model.TaxSubmissionFilter) ([]model.TaxSubmission, error)
+	CreateTaxRule(ctx context.Context, rule *model.TaxRule) error
+	UpdateTaxRule(ctx context.Context, rule *model.TaxRule) error
+	GetTaxRuleByID(ctx context.Context, id uint) (*model.TaxRule, error)
+	ListTaxRules(ctx context.Context, bidID uint) ([]model.TaxRule, error)
+	TaxRuleAt(ctx context.Context, bidID uint, at time.Time) (*model.TaxRule, error)
+	GetCusTypesByIDs(ctx context.Context, ids []uint) ([]model.CusType, error)
+	CustomerTypeIDs(ctx context.Context, customerID uint) ([]uint, error)
+}
+
+type CurrencyRepo interface {
+	GetCurrencyByID(ctx context.Context, id uint) (*model.Currency, error)
+	ListCurrencies(ctx context.Context) ([]model.Currency, error)
+	SaveExchangeRate(ctx context.Context, rate *model.ExchangeRate) error
+	ListExchangeRates(ctx context.Context, filter model.ExchangeRateFilter) ([]model.ExchangeRate, error)
+	ExchangeRateAt(ctx context.Context, bidID, currencyID uint, at time.Time) (*model.ExchangeRate, error)
+	CurrencyBalances(ctx context.Context, bidID uint, before time.Time) ([]model.CurrencyBalanceRow, error)
+}
@@ -0,0 +1,131 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"common-gold/audit"
+
+	"crm-gold/internal/model"
+	"crm-gold/internal/repository/repo"
+
+	"go.uber.org/zap"
+)
+
+const (
+	defaultAuditPageSize = 50
+	maxAuditPageSize     = 200
+	auditVerifyBatch     = 500
+)
+
+// AuditRecorder سرویس‌هایی که رویداد تجاری ثبت می‌کنند فقط به همین متد نیاز دارند.
+type AuditRecorder interface {
+	Record(ctx context.Context, event audit.Event) error
+}
+
+type AuditService interface {
+	AuditRecorder
+	ListEvents(ctx context.Context, filter model.AuditFilter) (*model.AuditLogPage, error)
+	VerifyChain(ctx context.Context) (*model.AuditChainReport, error)
+}
+
+type auditServiceImpl struct {
+	auditRepo repo.AuditRepo
+	logger    *zap.Logger
+}
+
+func NewAuditService(auditRepo repo.AuditRepo, logger *zap.Logger) (AuditService, error) {
+	if auditRepo == nil {
+		return nil, errors.New("auditRepository cannot be nil for AuditService")
+	}
+	if logger == nil {
+		return nil, errors.New("logger cannot be nil for AuditService")
+	}
+	return &auditServiceImpl{auditRepo: auditRepo, logger: logger}, nil
+}
+
+// Record فیلدهای خالی رویداد را از درخواست جاری (کاربر احرازشده، IP، مرورگر و شناسه همبستگی) پر می‌کند.
+func (s *auditServiceImpl) Record(ctx context.Context, event audit.Event) error {
+	if event.Action == "" || event.Category == "" {
+		return fmt.Errorf("%w: audit action and category are required", ErrValidation)
+	}
+	if event.ActorID == "" {
+		event.ActorID, _ = ctx.Value("userID").(string)
+	}
+	if event.ActorName == "" {
+		event.ActorName, _ = ctx.Value("username").(string)
+	}
+	if meta, ok := audit.MetaFrom(ctx); ok {
+		meta.Apply(&event)
+	}
+	if event.OccurredAt.IsZero() {
+		event.OccurredAt = time.Now()
+	}
+	if err := s.auditRepo.AppendAuditLog(ctx, model.NewAuditLog(event)); err != nil {
+		return fmt.Errorf("failed to append audit log: %w", err)
+	}
+	return nil
+}
+
+func (s *auditServiceImpl) ListEvents(ctx context.Context, filter model.AuditFilter) (*model.AuditLogPage, error) {
+	if filter.Page <= 0 {
+		filter.Page = 1
+	}
+	if filter.PageSize <= 0 {
+		filter.PageSize = defaultAuditPageSize
+	}
+	filter.PageSize = min(filter.PageSize, maxAuditPageSize)
+	filter.Query = strings.TrimSpace(filter.Query)
+
+	logs, total, err := s.auditRepo.ListAuditLogs(ctx, filter)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list audit logs: %w", err)
+	}
+	return &model.AuditLogPage{Items: logs, Total: total, Page: filter.Page, PageSize: filter.PageSize}, nil
+}
+
+// VerifyChain همه رویدادها را به ترتیب Seq می‌خواند و هش هر کدام را از نو می‌سازد؛ اولین ناهمخوانی گزارش می‌شود.
+func (s *auditServiceImpl) VerifyChain(ctx context.Context) (*model.AuditChainReport, error) {
+	report := &model.AuditChainReport{Valid: true}
+	var prevHash string
+	for {
+		logs, err := s.auditRepo.ScanAuditLogs(ctx, report.LastSeq, auditVerifyBatch)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan audit logs: %w", err)
+		}
+		for i := range logs {
+			log := &logs[i]
+			var reason string
+			switch {
+			case log.Seq != report.LastSeq+1:
+				reason = fmt.Sprintf("events %d to %d are missing", report.LastSeq+1, log.Seq-1)
+			case log.PrevHash != prevHash:
+				reason = "previous hash does not match the preceding event"
+			case audit.Hash(prevHash, log.Seq, log.Event()) != log.Hash:
+				reason = "event content does not match its hash"
+			}
+			if reason != "" {
+				seq := log.Seq
+				report.Valid, report.BrokenAtSeq, report.Reason = false, &seq, reason
+				s.logger.Warn("Audit chain verification failed", zap.Uint64("seq", seq), zap.String("reason", reason))
+				return report, nil
+			}
+			report.Checked++
+			report.LastSeq = log.Seq
+			prevHash = log.Hash
+		}
+		if len(logs) < auditVerifyBatch {
+			return report, nil
+		}
+	}
+}
+
+// recordAudit خطای ثبت رویداد را فقط گزارش می‌کند تا عملیاتی که انجام شده است به خاطر دفتر رویداد خطا برنگرداند.
+func recordAudit(ctx context.Context, recorder AuditRecorder, logger *zap.Logger, event audit.Event) {
+	if err := recorder.Record(ctx, event); err != nil {
+		logger.Error("Failed to record audit event", zap.String("action", event.Action), zap.String("target_id", event.TargetID), zap.Error(err))
+	}
+}
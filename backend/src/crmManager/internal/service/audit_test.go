@@ -0,0 +1,111 @@
+package service
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"common-gold/audit"
+
+	"go.uber.org/zap"
+
+	"crm-gold/internal/model"
+	"crm-gold/internal/repository/repo"
+)
+
+type fakeAuditRepo struct {
+	repo.AuditRepo
+	logs []model.AuditLog
+}
+
+func (r *fakeAuditRepo) ScanAuditLogs(ctx context.Context, afterSeq uint64, limit int) ([]model.AuditLog, error) {
+	var out []model.AuditLog
+	for _, l := range r.logs {
+		if l.Seq > afterSeq && len(out) < limit {
+			out = append(out, l)
+		}
+	}
+	return out, nil
+}
+
+// auditChain n رویداد زنجیرشده می‌سازد، همان‌طور که مخزن هنگام درج می‌سازد.
+func auditChain(n int) []model.AuditLog {
+	logs := make([]model.AuditLog, n)
+	var prevHash string
+	for i := range logs {
+		log := model.NewAuditLog(audit.Event{
+			Action:     audit.ActionLogin,
+			Category:   audit.CategoryAuth,
+			ActorID:    "user",
+			OccurredAt: time.Date(2024, 3, 20, 10, 0, i, 0, time.UTC),
+		})
+		log.Seq = uint64(i + 1)
+		log.PrevHash = prevHash
+		log.Hash = audit.Hash(prevHash, log.Seq, log.Event())
+		prevHash = log.Hash
+		logs[i] = *log
+	}
+	return logs
+}
+
+func TestVerifyChain(t *testing.T) {
+	tests := []struct {
+		name       string
+		size       int
+		tamper     func([]model.AuditLog) []model.AuditLog
+		wantValid  bool
+		wantBroken uint64
+	}{
+		{name: "empty", size: 0, wantValid: true},
+		{name: "intact", size: 5, wantValid: true},
+		{name: "spans several batches", size: auditVerifyBatch*2 + 3, wantValid: true},
+		{
+			name: "edited content", size: 5, wantBroken: 3,
+			tamper: func(l []model.AuditLog) []model.AuditLog { l[2].ActorID = "attacker"; return l },
+		},
+		{
+			name: "deleted event", size: 5, wantBroken: 4,
+			tamper: func(l []model.AuditLog) []model.AuditLog { return append(l[:2], l[3:]...) },
+		},
+		{
+			name: "deleted last event goes unnoticed by hashes alone", size: 5, wantValid: true,
+			tamper: func(l []model.AuditLog) []model.AuditLog { return l[:4] },
+		},
+		{
+			name: "rehashed event breaks its successor", size: 5, wantBroken: 4,
+			tamper: func(l []model.AuditLog) []model.AuditLog {
+				l[2].Description = "rewritten"
+				l[2].Hash = audit.Hash(l[2].PrevHash, l[2].Seq, l[2].Event())
+				return l
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			logs := auditChain(tt.size)
+			if tt.tamper != nil {
+				logs = tt.tamper(logs)
+			}
+			svc, err := NewAuditService(&fakeAuditRepo{logs: logs}, zap.NewNop())
+			if err != nil {
+				t.Fatal(err)
+			}
+			report, err := svc.VerifyChain(context.Background())
+			if err != nil {
+				t.Fatal(err)
+			}
+			if report.Valid != tt.wantValid {
+				t.Fatalf("Valid = %v, want %v (reason %q)", report.Valid, tt.wantValid, report.Reason)
+			}
+			if tt.wantValid {
+				if report.Checked != int64(len(logs)) {
+					t.Errorf("Checked = %d, want %d", report.Checked, len(logs))
+				}
+				return
+			}
+			if report.BrokenAtSeq == nil || *report.BrokenAtSeq != tt.wantBroken {
+				t.Errorf("BrokenAtSeq = %v, want %d", report.BrokenAtSeq, tt.wantBroken)
+			}
+		})
+	}
+}
@@ -0,0 +1,680 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+	"time"
+
+	"crm-gold/internal/model"
+	"crm-gold/internal/repository/db/postgresDb"
+	"crm-gold/internal/repository/repo"
+	"crm-gold/internal/utils"
+
+	"go.uber.org/zap"
+	"gorm.io/gorm"
+)
+
+// reconcileDateTolerance فاصله مجاز بین تاریخ سند و تاریخ ثبت آن در صورتحساب بانک
+const reconcileDateTolerance = 3 * 24 * time.Hour
+
+type BankService interface {
+	CreateBankAccount(ctx context.Context, req *model.CreateBankAccountRequest, actor string) (*model.BankAccount, error)
+	GetBankAccount(ctx context.Context, id uint) (*model.BankAccount, error)
+	ListBankAccounts(ctx context.Context, bidID uint) ([]model.BankAccount, error)
+	AddPOSTerminal(ctx context.Context, accountID uint, req *model.POSTerminalRequest) (*model.POSTerminal, error)
+	CreateTransaction(ctx context.Context, accountID uint, req *model.BankTransactionRequest, actor string) (*model.BankTransaction, error)
+	ListTransactions(ctx context.Context, accountID uint, from, to *time.Time) ([]model.BankTransaction, error)
+	DeleteTransaction(ctx context.Context, txnID uint, actor string) error
+	GetStatement(ctx context.Context, accountID uint, from, to time.Time) (*model.AccountStatement, error)
+	ImportStatement(ctx context.Context, accountID uint, fileName string, file io.Reader, unit, actor string) (*model.ReconciliationResult, error)
+	ListStatementLines(ctx context.Context, accountID uint, status string) ([]model.BankStatementLine, error)
+	ListCandidates(ctx context.Context, accountID uint, from, to time.Time) ([]model.ReconciliationCandidate, error)
+	MatchStatementLine(ctx context.Context, lineID uint, req *model.MatchStatementLineRequest, actor string) (*model.BankStatementLine, error)
+	RecordStatementLine(ctx context.Context, lineID uint, req *model.RecordStatementLineRequest, actor string) (*model.BankStatementLine, error)
+	IgnoreStatementLine(ctx context.Context, lineID uint, req *model.IgnoreStatementLineRequest, actor string) (*model.BankStatementLine, error)
+	UnmatchStatementLine(ctx context.Context, lineID uint, actor string) (*model.BankStatementLine, error)
+}
+
+type bankServiceImpl struct {
+	bankRepo     repo.BankRepo
+	fundRepo     repo.FundRepo
+	customerRepo repo.CustRepo
+	ledgerRepo   repo.LedgerRepo
+	logger       *zap.Logger
+}
+
+func NewBankService(bankRepo repo.BankRepo, fundRepo repo.FundRepo, customerRepo repo.CustRepo, ledgerRepo repo.LedgerRepo, logger *zap.Logger) (BankService, error) {
+	if bankRepo == nil {
+		return nil, errors.New("bankRepository cannot be nil for BankService")
+	}
+	if fundRepo == nil {
+		return nil, errors.New("fundRepository cannot be nil for BankService")
+	}
+	if customerRepo == nil {
+		return nil, errors.New("customerRepository cannot be nil for BankService")
+	}
+	if ledgerRepo == nil {
+		return nil, errors.New("ledgerRepository cannot be nil for BankService")
+	}
+	if logger == nil {
+		return nil, errors.New("logger cannot be nil for BankService")
+	}
+	return &bankServiceImpl{
+		bankRepo:     bankRepo,
+		fundRepo:     fundRepo,
+		customerRepo: customerRepo,
+		ledgerRepo:   ledgerRepo,
+		logger:       logger,
+	}, nil
+}
+
+func (s *bankServiceImpl) CreateBankAccount(ctx context.Context, req *model.CreateBankAccountRequest, actor string) (*model.BankAccount, error) {
+	if req.BIDID == 0 {
+		return nil, fmt.Errorf("%w: bidId is required", ErrValidation)
+	}
+	if strings.TrimSpace(req.Code) == "" || strings.TrimSpace(req.Name) == "" || strings.TrimSpace(req.BankName) == "" {
+		return nil, fmt.Errorf("%w: code, name and bankName are required", ErrValidation)
+	}
+	accountNum := utils.NormalizeDigits(strings.TrimSpace(req.AccountNum))
+	if accountNum == "" {
+		return nil, fmt.Errorf("%w: accountNum is required", ErrValidation)
+	}
+	var shaba, card string
+	if req.ShabaNum != "" {
+		shaba = utils.NormalizeSheba(req.ShabaNum)
+		if !utils.IsValidSheba(shaba) {
+			return nil, fmt.Errorf("%w: invalid sheba number", ErrValidation)
+		}
+	}
+	if req.CardNum != "" {
+		card = strings.NewReplacer(" ", "", "-", "").Replace(utils.NormalizeDigits(req.CardNum))
+		if !utils.IsValidCardNumber(card) {
+			return nil, fmt.Errorf("%w: invalid card number", ErrValidation)
+		}
+	}
+	openingDate := utils.StartOfDay(time.Now())
+	if req.OpeningDate != "" {
+		d, err := utils.ParseDateParam(req.OpeningDate)
+		if err != nil {
+			return nil, fmt.Errorf("%w: invalid opening date: %v", ErrValidation, err)
+		}
+		openingDate = utils.StartOfDay(d)
+	}
+
+	account := &model.BankAccount{
+		BIDID:          req.BIDID,
+		Code:           strings.TrimSpace(req.Code),
+		Name:           strings.TrimSpace(req.Name),
+		BankName:       strings.TrimSpace(req.BankName),
+		Branch:         utils.PtrString(req.Branch),
+		AccountNum:     accountNum,
+		ShabaNum:       utils.PtrString(shaba),
+		CardNum:        utils.PtrString(card),
+		HolderName:     utils.PtrString(req.HolderName),
+		Description:    utils.PtrString(req.Description),
+		IsActive:       true,
+		OpeningDate:    openingDate,
+		OpeningBalance: req.OpeningBalance,
+	}
+	for _, t := range req.POSTerminals {
+		terminal, err := newPOSTerminal(&t)
+		if err != nil {
+			return nil, err
+		}
+		account.POSTerminals = append(account.POSTerminals, *terminal)
+	}
+
+	var entry *model.LedgerEntry
+	if !req.OpeningBalance.IsZero() {
+		bankLine := model.LedgerLine{AccountKind: model.LedgerAccountBank, AccountID: &account.ID}
+		openingLine := model.LedgerLine{AccountKind: model.LedgerAccountOpeningBalance}
+		// مانده منفی یعنی حساب در ابتدای دوره اضافه برداشت داشته است
+		opening := req.OpeningBalance
+		if opening.IsPositive() {
+			bankLine.Debit, openingLine.Credit = opening, opening
+		} else {
+			bankLine.Credit, openingLine.Debit = opening.Neg(), opening.Neg()
+		}
+		entry = &model.LedgerEntry{
+			BIDID:       req.BIDID,
+			DocType:     model.LedgerDocBankOpening,
+			Reference:   fmt.Sprintf("BANK-%s-OPEN", account.Code),
+			Description: fmt.Sprintf("موجودی اول دوره حساب %s", account.Name),
+			PostedAt:    openingDate,
+			CreatedBy:   actor,
+			Lines:       []model.LedgerLine{bankLine, openingLine},
+		}
+	}
+
+	created, err := s.bankRepo.CreateBankAccount(ctx, account, entry)
+	if err != nil {
+		if postgresDb.IsUniqueViolation(err) {
+			return nil, fmt.Errorf("%w: bank account or pos terminal already exists", ErrConflict)
+		}
+		return nil, fmt.Errorf("failed to save bank account: %w", err)
+	}
+	s.logger.Info("Bank account created.", zap.Uint("bank_account_id", created.ID), zap.String("code", created.Code))
+	return created, nil
+}
+
+func newPOSTerminal(req *model.POSTerminalRequest) (*model.POSTerminal, error) {
+	terminalID := utils.NormalizeDigits(strings.TrimSpace(req.TerminalID))
+	if terminalID == "" {
+		return nil, fmt.Errorf("%w: terminalId is required", ErrValidation)
+	}
+	return &model.POSTerminal{
+		TerminalID:  terminalID,
+		MerchantID:  utils.PtrString(utils.NormalizeDigits(strings.TrimSpace(req.MerchantID))),
+		Provider:    utils.PtrString(req.Provider),
+		Description: utils.PtrString(req.Description),
+		IsActive:    true,
+	}, nil
+}
+
+func (s *bankServiceImpl) GetBankAccount(ctx context.Context, id uint) (*model.BankAccount, error) {
+	account, err := s.bankRepo.GetBankAccountByID(ctx, id)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, fmt.Errorf("%w: bank account %d", ErrNotFound, id)
+		}
+		return nil, fmt.Errorf("failed to fetch bank account: %w", err)
+	}
+	return account, nil
+}
+
+func (s *bankServiceImpl) ListBankAccounts(ctx context.Context, bidID uint) ([]model.BankAccount, error) {
+	accounts, err := s.bankRepo.ListBankAccounts(ctx, bidID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list bank accounts: %w", err)
+	}
+	return accounts, nil
+}
+
+func (s *bankServiceImpl) AddPOSTerminal(ctx context.Context, accountID uint, req *model.POSTerminalRequest) (*model.POSTerminal, error) {
+	if _, err := s.GetBankAccount(ctx, accountID); err != nil {
+		return nil, err
+	}
+	terminal, err := newPOSTerminal(req)
+	if err != nil {
+		return nil, err
+	}
+	terminal.BankAccountID = accountID
+	created, err := s.bankRepo.AddPOSTerminal(ctx, terminal)
+	if err != nil {
+		if postgresDb.IsUniqueViolation(err) {
+			return nil, fmt.Errorf("%w: pos terminal %s is already registered", ErrConflict, terminal.TerminalID)
+		}
+		return nil, fmt.Errorf("failed to save pos terminal: %w", err)
+	}
+	return created, nil
+}
+
+func (s *bankServiceImpl) CreateTransaction(ctx context.Context, accountID uint, req *model.BankTransactionRequest, actor string) (*model.BankTransaction, error) {
+	account, err := s.GetBankAccount(ctx, accountID)
+	if err != nil {
+		return nil, err
+	}
+	if !account.IsActive {
+		return nil, fmt.Errorf("%w: bank account %d is inactive", ErrValidation, accountID)
+	}
+	if req.Type != model.BankTransactionDeposit && req.Type != model.BankTransactionWithdrawal {
+		return nil, fmt.Errorf("%w: transaction type must be deposit or withdrawal", ErrValidation)
+	}
+	if !req.Amount.IsPositive() {
+		return nil, fmt.Errorf("%w: transaction amount must be positive", ErrValidation)
+	}
+	date, err := utils.ParseDateParam(req.Date)
+	if err != nil {
+		return nil, fmt.Errorf("%w: invalid transaction date: %v", ErrValidation, err)
+	}
+	date = utils.StartOfDay(date)
+	if date.Before(account.OpeningDate) {
+		return nil, fmt.Errorf("%w: transaction date is before the account opening date", ErrValidation)
+	}
+	if req.POSTerminalID != nil {
+		if req.Type != model.BankTransactionDeposit {
+			return nil, fmt.Errorf("%w: pos terminals only deposit into the account", ErrValidation)
+		}
+		if !hasPOSTerminal(account, *req.POSTerminalID) {
+			return nil, fmt.Errorf("%w: pos terminal %d does not belong to this account", ErrValidation, *req.POSTerminalID)
+		}
+	}
+	if err := s.validateCounterparty(ctx, account, req.CounterpartyKind, req.CounterpartyID); err != nil {
+		return nil, err
+	}
+
+	code, err := utils.GenerateSecureRandomString(8)
+	if err != nil {
+		return nil, fmt.Errorf("failed to generate transaction number: %w", err)
+	}
+	prefix := "DEP"
+	if req.Type == model.BankTransactionWithdrawal {
+		prefix = "WDR"
+	}
+	txn := &model.BankTransaction{
+		BIDID:            account.BIDID,
+		BankAccountID:    account.ID,
+		Type:             req.Type,
+		Number:           fmt.Sprintf("%s-%s", prefix, code),
+		Date:             date,
+		DateJalali:       utils.FormatJalali(date),
+		CounterpartyKind: req.CounterpartyKind,
+		CounterpartyID:   req.CounterpartyID,
+		Amount:           req.Amount,
+		POSTerminalID:    req.POSTerminalID,
+		TrackingCode:     utils.PtrString(utils.NormalizeDigits(strings.TrimSpace(req.TrackingCode))),
+		Description:      utils.PtrString(req.Description),
+		CreatedBy:        actor,
+	}
+	created, err := s.bankRepo.CreateBankTransaction(ctx, txn, bankTransactionEntry(txn, actor))
+	if err != nil {
+		return nil, fmt.Errorf("failed to save bank transaction: %w", err)
+	}
+	s.logger.Info("Bank transaction created.", zap.Uint("txn_id", created.ID), zap.String("type", created.Type), zap.String("amount", created.Amount.String()))
+	return created, nil
+}
+
+func hasPOSTerminal(account *model.BankAccount, terminalID uint) bool {
+	for _, t := range account.POSTerminals {
+		if t.ID == terminalID {
+			return true
+		}
+	}
+	return false
+}
+
+func (s *bankServiceImpl) validateCounterparty(ctx context.Context, account *model.BankAccount, kind string, id *uint) error {
+	switch kind {
+	case model.LedgerAccountBankFees:
+		if id != nil {
+			return fmt.Errorf("%w: bank fees do not take a counterpartyId", ErrValidation)
+		}
+		return nil
+	case model.LedgerAccountPerson, model.LedgerAccountFund, model.LedgerAccountBank:
+		if id == nil || *id == 0 {
+			return fmt.Errorf("%w: counterpartyId is required", ErrValidation)
+		}
+	default:
+		return fmt.Errorf("%w: counterparty must be a person, fund, bank or bank_fees", ErrValidation)
+	}
+
+	switch kind {
+	case model.LedgerAccountPerson:
+		if _, err := s.customerRepo.GetCustomerByID(ctx, *id); err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				return fmt.Errorf("%w: person %d does not exist", ErrValidation, *id)
+			}
+			return fmt.Errorf("failed to load counterparty person: %w", err)
+		}
+	case model.LedgerAccountFund:
+		fund, err := s.fundRepo.GetFundByID(ctx, *id)
+		if err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				return fmt.Errorf("%w: fund %d does not exist", ErrValidation, *id)
+			}
+			return fmt.Errorf("failed to load counterparty fund: %w", err)
+		}
+		if fund.BIDID != account.BIDID {
+			return fmt.Errorf("%w: counterparty fund belongs to another business", ErrValidation)
+		}
+	case model.LedgerAccountBank:
+		if *id == account.ID {
+			return fmt.Errorf("%w: counterparty account must differ from the transaction account", ErrValidation)
+		}
+		other, err := s.GetBankAccount(ctx, *id)
+		if err != nil {
+			return err
+		}
+		if other.BIDID != account.BIDID {
+			return fmt.Errorf("%w: counterparty bank account belongs to another business", ErrValidation)
+		}
+	}
+	return nil
+}
+
+// bankTransactionEntry واریز: بانک بدهکار و طرف حساب بستانکار؛ برداشت برعکس.
+func bankTransactionEntry(txn *model.BankTransaction, actor string) *model.LedgerEntry {
+	accountID := txn.BankAccountID
+	bankLine := model.LedgerLine{AccountKind: model.LedgerAccountBank, AccountID: &accountID}
+	otherLine := model.LedgerLine{AccountKind: txn.CounterpartyKind, AccountID: txn.CounterpartyID}
+
+	amount := txn.Amount
+	desc := "واریز به حساب"
+	if txn.Type == model.BankTransactionDeposit {
+		bankLine.Debit = amount
+		otherLine.Credit = amount
+	} else {
+		desc = "برداشت از حساب"
+		otherLine.Debit = amount
+		bankLine.Credit = amount
+	}
+	if txn.TrackingCode != nil {
+		desc = desc + " - کد پیگیری " + *txn.TrackingCode
+	}
+	if txn.Description != nil {
+		desc = desc + " - " + *txn.Description
+	}
+	bankLine.Description = desc
+	otherLine.Description = desc
+
+	return &model.LedgerEntry{
+		BIDID:       txn.BIDID,
+		DocType:     model.LedgerDocBankTxn,
+		DocID:       txn.ID,
+		Reference:   txn.Number,
+		Description: desc,
+		PostedAt:    txn.Date,
+		CreatedBy:   actor,
+		Lines:       []model.LedgerLine{bankLine, otherLine},
+	}
+}
+
+func (s *bankServiceImpl) ListTransactions(ctx context.Context, accountID uint, from, to *time.Time) ([]model.BankTransaction, error) {
+	if _, err := s.GetBankAccount(ctx, accountID); err != nil {
+		return nil, err
+	}
+	txns, err := s.bankRepo.ListBankTransactions(ctx, accountID, from, to)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list bank transactions: %w", err)
+	}
+	return txns, nil
+}
+
+func (s *bankServiceImpl) DeleteTransaction(ctx context.Context, txnID uint, actor string) error {
+	txn, err := s.bankRepo.GetBankTransactionByID(ctx, txnID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return fmt.Errorf("%w: bank transaction %d", ErrNotFound, txnID)
+		}
+		return fmt.Errorf("failed to fetch bank transaction: %w", err)
+	}
+	if txn.LedgerEntryID == nil {
+		return fmt.Errorf("bank transaction %d has no posted ledger entry", txn.ID)
+	}
+	original, err := s.ledgerRepo.GetEntryByID(ctx, *txn.LedgerEntryID)
+	if err != nil {
+		return fmt.Errorf("failed to load transaction ledger entry: %w", err)
+	}
+	reversal := original.Reversal(original.PostedAt, actor, "ابطال سند بانکی")
+	if err := s.bankRepo.DeleteBankTransaction(ctx, txn, reversal); err != nil {
+		if errors.Is(err, postgresDb.ErrReconciledTransaction) {
+			return fmt.Errorf("%w: %v", ErrConflict, err)
+		}
+		return fmt.Errorf("failed to delete bank transaction: %w", err)
+	}
+	s.logger.Info("Bank transaction deleted.", zap.Uint("txn_id", txn.ID), zap.String("actor", actor))
+	return nil
+}
+
+func (s *bankServiceImpl) GetStatement(ctx context.Context, accountID uint, from, to time.Time) (*model.AccountStatement, error) {
+	if _, err := s.GetBankAccount(ctx, accountID); err != nil {
+		return nil, err
+	}
+	return buildAccountStatement(ctx, s.ledgerRepo, model.LedgerAccountBank, accountID, nil, from, to)
+}
+
+func (s *bankServiceImpl) ImportStatement(ctx context.Context, accountID uint, fileName string, file io.Reader, unit, actor string) (*model.ReconciliationResult, error) {
+	if _, err := s.GetBankAccount(ctx, accountID); err != nil {
+		return nil, err
+	}
+	rows, format, err := parseBankStatementCSV(file, unit)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrValidation, err)
+	}
+
+	minDate, maxDate := rows[0].Date, rows[0].Date
+	lines := make([]model.BankStatementLine, 0, len(rows))
+	for _, r := range rows {
+		if r.Date.Before(minDate) {
+			minDate = r.Date
+		}
+		if r.Date.After(maxDate) {
+			maxDate = r.Date
+		}
+		lines = append(lines, model.BankStatementLine{
+			BankAccountID: accountID,
+			RowNumber:     r.Row,
+			Date:          r.Date,
+			DateJalali:    utils.FormatJalali(r.Date),
+			Description:   r.Description,
+			Deposit:       r.Deposit,
+			Withdrawal:    r.Withdrawal,
+			Balance:       r.Balance,
+			TrackingCode:  utils.PtrString(r.TrackingCode),
+			Status:        model.StatementLineUnmatched,
+		})
+	}
+
+	candidates, err := s.bankRepo.ListReconciliationCandidates(ctx, accountID,
+		minDate.Add(-reconcileDateTolerance), maxDate.Add(reconcileDateTolerance+24*time.Hour))
+	if err != nil {
+		return nil, fmt.Errorf("failed to load reconciliation candidates: %w", err)
+	}
+	matched := autoMatchStatementLines(lines, candidates, actor, time.Now())
+
+	imp := &model.BankStatementImport{
+		BankAccountID: accountID,
+		FileName:      fileName,
+		Format:        format,
+		LineCount:     len(lines),
+		MatchedCount:  matched,
+		ImportedBy:    actor,
+		Lines:         lines,
+	}
+	saved, err := s.bankRepo.SaveStatementImport(ctx, imp)
+	if err != nil {
+		if postgresDb.IsUniqueViolation(err) {
+			return nil, fmt.Errorf("%w: another reconciliation matched the same transactions, retry the import", ErrConflict)
+		}
+		return nil, fmt.Errorf("failed to save bank statement: %w", err)
+	}
+
+	result := &model.ReconciliationResult{Matched: matched, Unmatched: []model.BankStatementLine{}}
+	for _, l := range saved.Lines {
+		if l.Status == model.StatementLineUnmatched {
+			result.Unmatched = append(result.Unmatched, l)
+		}
+	}
+	saved.Lines = nil
+	result.Import = *saved
+	return result, nil
+}
+
+// autoMatchStatementLines هر ردیف را با سطری از دفتر که مبلغ و جهت برابر و تاریخ نزدیک دارد تطبیق می‌دهد.
+// کد پیگیری مشترک اولویت دارد؛ اگر چند سند بدون کد پیگیری به یک اندازه نزدیک باشند، ردیف برای تطبیق دستی باقی می‌ماند.
+func autoMatchStatementLines(lines []model.BankStatementLine, candidates []model.ReconciliationCandidate, actor string, now time.Time) int {
+	used := make(map[uint]bool, len(candidates))
+	matched := 0
+	for i := range lines {
+		line := &lines[i]
+		best, tied := -1, false
+		var bestDiff time.Duration
+		bestTracking := false
+		for j, c := range candidates {
+			if used[c.LedgerLineID] || !amountMatches(line, &c) {
+				continue
+			}
+			diff := utils.StartOfDay(c.PostedAt).Sub(line.Date)
+			if diff < 0 {
+				diff = -diff
+			}
+			if diff > reconcileDateTolerance {
+				continue
+			}
+			tracking := line.TrackingCode != nil && strings.Contains(c.Reference+" "+c.Description, *line.TrackingCode)
+			switch {
+			case best < 0, tracking && !bestTracking, tracking == bestTracking && diff < bestDiff:
+				best, bestDiff, bestTracking, tied = j, diff, tracking, false
+			case tracking == bestTracking && diff == bestDiff:
+				tied = true
+			}
+		}
+		if best < 0 || (tied && !bestTracking) {
+			continue
+		}
+		id := candidates[best].LedgerLineID
+		used[id] = true
+		line.Status = model.StatementLineMatched
+		line.MatchedLedgerLineID = &id
+		line.MatchedAt = &now
+		line.MatchedBy = actor
+		matched++
+	}
+	return matched
+}
+
+// amountMatches واریز صورتحساب با بدهکار حساب بانک و برداشت با بستانکار آن متناظر است.
+func amountMatches(line *model.BankStatementLine, c *model.ReconciliationCandidate) bool {
+	if line.Deposit.IsPositive() {
+		return c.Debit.IsPositive() && c.Debit.Cmp(line.Deposit) == 0
+	}
+	return c.Credit.IsPositive() && c.Credit.Cmp(line.Withdrawal) == 0
+}
+
+func (s *bankServiceImpl) ListStatementLines(ctx context.Context, accountID uint, status string) ([]model.BankStatementLine, error) {
+	if _, err := s.GetBankAccount(ctx, accountID); err != nil {
+		return nil, err
+	}
+	lines, err := s.bankRepo.ListStatementLines(ctx, accountID, status)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list bank statement lines: %w", err)
+	}
+	return lines, nil
+}
+
+func (s *bankServiceImpl) ListCandidates(ctx context.Context, accountID uint, from, to time.Time) ([]model.ReconciliationCandidate, error) {
+	if _, err := s.GetBankAccount(ctx, accountID); err != nil {
+		return nil, err
+	}
+	candidates, err := s.bankRepo.ListReconciliationCandidates(ctx, accountID, utils.StartOfDay(from), utils.StartOfDay(to).AddDate(0, 0, 1))
+	if err != nil {
+		return nil, fmt.Errorf("failed to list reconciliation candidates: %w", err)
+	}
+	return candidates, nil
+}
+
+func (s *bankServiceImpl) getStatementLine(ctx context.Context, id uint) (*model.BankStatementLine, error) {
+	line, err := s.bankRepo.GetStatementLineByID(ctx, id)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, fmt.Errorf("%w: bank statement line %d", ErrNotFound, id)
+		}
+		return nil, fmt.Errorf("failed to fetch bank statement line: %w", err)
+	}
+	return line, nil
+}
+
+func (s *bankServiceImpl) MatchStatementLine(ctx context.Context, lineID uint, req *model.MatchStatementLineRequest, actor string) (*model.BankStatementLine, error) {
+	line, err := s.getStatementLine(ctx, lineID)
+	if err != nil {
+		return nil, err
+	}
+	if line.Status != model.StatementLineUnmatched {
+		return nil, fmt.Errorf("%w: statement line is already %s", ErrInvalidTransition, line.Status)
+	}
+	candidate, err := s.bankRepo.GetReconciliationCandidate(ctx, line.BankAccountID, req.LedgerLineID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, fmt.Errorf("%w: ledger line %d is not an unreconciled line of this account", ErrValidation, req.LedgerLineID)
+		}
+		return nil, fmt.Errorf("failed to load ledger line: %w", err)
+	}
+	if !amountMatches(line, candidate) {
+		return nil, fmt.Errorf("%w: ledger line amount or direction does not match the statement line", ErrValidation)
+	}
+	return s.setLineMatch(ctx, line, &candidate.LedgerLineID, model.StatementLineMatched, req.Note, actor)
+}
+
+func (s *bankServiceImpl) RecordStatementLine(ctx context.Context, lineID uint, req *model.RecordStatementLineRequest, actor string) (*model.BankStatementLine, error) {
+	line, err := s.getStatementLine(ctx, lineID)
+	if err != nil {
+		return nil, err
+	}
+	if line.Status != model.StatementLineUnmatched {
+		return nil, fmt.Errorf("%w: statement line is already %s", ErrInvalidTransition, line.Status)
+	}
+	txnReq := &model.BankTransactionRequest{
+		Type:             model.BankTransactionDeposit,
+		Date:             line.Date.Format(time.RFC3339),
+		CounterpartyKind: req.CounterpartyKind,
+		CounterpartyID:   req.CounterpartyID,
+		Amount:           line.Deposit,
+		Description:      req.Description,
+	}
+	if line.Withdrawal.IsPositive() {
+		txnReq.Type = model.BankTransactionWithdrawal
+		txnReq.Amount = line.Withdrawal
+	}
+	if line.TrackingCode != nil {
+		txnReq.TrackingCode = *line.TrackingCode
+	}
+	if txnReq.Description == "" {
+		txnReq.Description = line.Description
+	}
+	txn, err := s.CreateTransaction(ctx, line.BankAccountID, txnReq, actor)
+	if err != nil {
+		return nil, err
+	}
+	entry, err := s.ledgerRepo.GetEntryByID(ctx, *txn.LedgerEntryID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load recorded transaction entry: %w", err)
+	}
+	for _, l := range entry.Lines {
+		if l.AccountKind == model.LedgerAccountBank && l.AccountID != nil && *l.AccountID == line.BankAccountID {
+			id := l.ID
+			return s.setLineMatch(ctx, line, &id, model.StatementLineMatched, "", actor)
+		}
+	}
+	return nil, fmt.Errorf("recorded transaction %d has no bank line", txn.ID)
+}
+
+func (s *bankServiceImpl) IgnoreStatementLine(ctx context.Context, lineID uint, req *model.IgnoreStatementLineRequest, actor string) (*model.BankStatementLine, error) {
+	line, err := s.getStatementLine(ctx, lineID)
+	if err != nil {
+		return nil, err
+	}
+	if line.Status != model.StatementLineUnmatched {
+		return nil, fmt.Errorf("%w: statement line is already %s", ErrInvalidTransition, line.Status)
+	}
+	return s.setLineMatch(ctx, line, nil, model.StatementLineIgnored, req.Note, actor)
+}
+
+func (s *bankServiceImpl) UnmatchStatementLine(ctx context.Context, lineID uint, actor string) (*model.BankStatementLine, error) {
+	line, err := s.getStatementLine(ctx, lineID)
+	if err != nil {
+		return nil, err
+	}
+	if line.Status == model.StatementLineUnmatched {
+		return nil, fmt.Errorf("%w: statement line is not matched", ErrInvalidTransition)
+	}
+	return s.setLineMatch(ctx, line, nil, model.StatementLineUnmatched, "", actor)
+}
+
+func (s *bankServiceImpl) setLineMatch(ctx context.Context, line *model.BankStatementLine, ledgerLineID *uint, status, note, actor string) (*model.BankStatementLine, error) {
+	from := line.Status
+	line.Status = status
+	line.MatchedLedgerLineID = ledgerLineID
+	line.Note = utils.PtrString(note)
+	if status == model.StatementLineUnmatched {
+		line.MatchedAt = nil
+		line.MatchedBy = ""
+	} else {
+		now := time.Now()
+		line.MatchedAt = &now
+		line.MatchedBy = actor
+	}
+	if err := s.bankRepo.UpdateStatementLineMatch(ctx, line, from); err != nil {
+		if errors.Is(err, postgresDb.ErrStaleStatementLine) {
+			return nil, fmt.Errorf("%w: statement line changed by another request, reload and retry", ErrConflict)
+		}
+		if postgresDb.IsUniqueViolation(err) {
+			return nil, fmt.Errorf("%w: ledger line is already matched to another statement line", ErrConflict)
+		}
+		return nil, fmt.Errorf("failed to update bank statement line: %w", err)
+	}
+	s.logger.Info("Bank statement line updated.", zap.Uint("line_id", line.ID), zap.String("from", from), zap.String("to", status), zap.String("actor", actor))
+	return line, nil
+}
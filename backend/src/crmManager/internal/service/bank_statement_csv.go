@@ -0,0 +1,311 @@
+package service
+
+import (
+	"bytes"
+	"encoding/csv"
+	"fmt"
+	"io"
+	"strings"
+	"time"
+
+	"crm-gold/internal/utils"
+
+	"common-gold/money"
+
+	"github.com/shopspring/decimal"
+)
+
+const (
+	StatementUnitRial  = "rial"
+	StatementUnitToman = "toman"
+)
+
+// قالب‌های رایج خروجی بانک‌ها: ستون جداگانه بدهکار/بستانکار، یک ستون مبلغ علامت‌دار، یا ستون مبلغ به همراه ستون نوع تراکنش
+const (
+	statementFormatSplit  = "debit_credit_columns"
+	statementFormatSigned = "signed_amount"
+	statementFormatTyped  = "amount_with_type"
+)
+
+const (
+	colDate = iota
+	colTime
+	colDescription
+	colDeposit
+	colWithdrawal
+	colAmount
+	colType
+	colBalance
+	colTracking
+	colCount
+)
+
+// statementHeaderKeywords به ترتیب بررسی می‌شوند؛ مثلاً «مبلغ واریز» باید پیش از «مبلغ» به ستون واریز برسد.
+var statementHeaderKeywords = []struct {
+	col      int
+	keywords []string
+}{
+	{colBalance, []string{"مانده", "موجودی", "balance"}},
+	{colTracking, []string{"پیگیری", "مرجع", "شماره سند", "شماره تراکنش", "سریال", "reference", "tracking", "ref no"}},
+	{colDate, []string{"تاریخ", "date"}},
+	{colTime, []string{"ساعت", "زمان", "time"}},
+	{colDeposit, []string{"بستانکار", "واریز", "credit", "deposit"}},
+	{colWithdrawal, []string{"بدهکار", "برداشت", "debit", "withdraw"}},
+	{colType, []string{"نوع", "type"}},
+	{colDescription, []string{"شرح", "توضیح", "description", "narration", "remark"}},
+	{colAmount, []string{"مبلغ", "amount"}},
+}
+
+// statementHeaderScanRows بسیاری از بانک‌ها چند سطر مشخصات حساب را پیش از سرستون‌ها می‌نویسند.
+const statementHeaderScanRows = 20
+
+type parsedStatementRow struct {
+	Row          int
+	Date         time.Time
+	Description  string
+	Deposit      money.Amount
+	Withdrawal   money.Amount
+	Balance      *money.Amount
+	TrackingCode string
+}
+
+// parseBankStatementCSV صورتحساب CSV بانک را می‌خواند و مبالغ را به تومان برمی‌گرداند.
+func parseBankStatementCSV(r io.Reader, unit string) ([]parsedStatementRow, string, error) {
+	divisor := decimal.NewFromInt(10)
+	switch unit {
+	case "", StatementUnitRial:
+	case StatementUnitToman:
+		divisor = decimal.NewFromInt(1)
+	default:
+		return nil, "", fmt.Errorf("unknown amount unit %q, expected rial or toman", unit)
+	}
+
+	raw, err := io.ReadAll(r)
+	if err != nil {
+		return nil, "", fmt.Errorf("failed to read statement file: %w", err)
+	}
+	raw = bytes.TrimPrefix(raw, []byte("\xef\xbb\xbf"))
+	if len(bytes.TrimSpace(raw)) == 0 {
+		return nil, "", fmt.Errorf("statement file is empty")
+	}
+
+	reader := csv.NewReader(bytes.NewReader(raw))
+	reader.Comma = detectDelimiter(raw)
+	reader.FieldsPerRecord = -1
+	reader.LazyQuotes = true
+	reader.TrimLeadingSpace = true
+	records, err := reader.ReadAll()
+	if err != nil {
+		return nil, "", fmt.Errorf("failed to parse statement csv: %w", err)
+	}
+
+	headerIdx, cols := -1, [colCount]int{}
+	for i := 0; i < len(records) && i < statementHeaderScanRows; i++ {
+		if c, ok := mapStatementHeader(records[i]); ok {
+			headerIdx, cols = i, c
+			break
+		}
+	}
+	if headerIdx < 0 {
+		return nil, "", fmt.Errorf("could not find a header row with date and amount columns")
+	}
+
+	format := statementFormatSplit
+	if cols[colDeposit] < 0 || cols[colWithdrawal] < 0 {
+		if cols[colType] >= 0 {
+			format = statementFormatTyped
+		} else {
+			format = statementFormatSigned
+		}
+	}
+
+	var rows []parsedStatementRow
+	for i := headerIdx + 1; i < len(records); i++ {
+		rec := records[i]
+		rowNum := i + 1
+		dateCell := cell(rec, cols[colDate])
+		if dateCell == "" {
+			// سطرهای جمع و پاورقی تاریخ ندارند
+			continue
+		}
+		date, err := parseStatementDate(dateCell)
+		if err != nil {
+			return nil, "", fmt.Errorf("row %d: %w", rowNum, err)
+		}
+
+		var deposit, withdrawal decimal.Decimal
+		switch format {
+		case statementFormatSplit:
+			if deposit, err = parseStatementAmount(cell(rec, cols[colDeposit])); err != nil {
+				return nil, "", fmt.Errorf("row %d: %w", rowNum, err)
+			}
+			if withdrawal, err = parseStatementAmount(cell(rec, cols[colWithdrawal])); err != nil {
+				return nil, "", fmt.Errorf("row %d: %w", rowNum, err)
+			}
+		default:
+			amountCol := cols[colAmount]
+			if amountCol < 0 {
+				// فقط یکی از ستون‌های واریز یا برداشت وجود دارد
+				if cols[colDeposit] >= 0 {
+					amountCol = cols[colDeposit]
+				} else {
+					amountCol = cols[colWithdrawal]
+				}
+			}
+			amount, err := parseStatementAmount(cell(rec, amountCol))
+			if err != nil {
+				return nil, "", fmt.Errorf("row %d: %w", rowNum, err)
+			}
+			isWithdrawal := amount.IsNegative() || (cols[colAmount] < 0 && cols[colDeposit] < 0)
+			if format == statementFormatTyped {
+				isWithdrawal = isWithdrawalType(cell(rec, cols[colType]))
+			}
+			amount = amount.Abs()
+			if isWithdrawal {
+				withdrawal = amount
+			} else {
+				deposit = amount
+			}
+		}
+		if deposit.IsNegative() || withdrawal.IsNegative() {
+			return nil, "", fmt.Errorf("row %d: negative amount in a debit/credit column", rowNum)
+		}
+		if deposit.IsPositive() && withdrawal.IsPositive() {
+			return nil, "", fmt.Errorf("row %d: both deposit and withdrawal are set", rowNum)
+		}
+		if deposit.IsZero() && withdrawal.IsZero() {
+			continue
+		}
+
+		row := parsedStatementRow{
+			Row:          rowNum,
+			Date:         date,
+			Description:  cell(rec, cols[colDescription]),
+			Deposit:      money.AmountFromDecimal(deposit.Div(divisor)),
+			Withdrawal:   money.AmountFromDecimal(withdrawal.Div(divisor)),
+			TrackingCode: utils.NormalizeDigits(cell(rec, cols[colTracking])),
+		}
+		if v := cell(rec, cols[colBalance]); v != "" {
+			if b, err := parseStatementAmount(v); err == nil {
+				balance := money.AmountFromDecimal(b.Div(divisor))
+				row.Balance = &balance
+			}
+		}
+		rows = append(rows, row)
+	}
+	if len(rows) == 0 {
+		return nil, "", fmt.Errorf("statement file has no transaction rows")
+	}
+	return rows, format, nil
+}
+
+// detectDelimiter جداکننده را از چند سطر اول تشخیص می‌دهد؛ خروجی برخی بانک‌ها با ; یا tab است.
+func detectDelimiter(raw []byte) rune {
+	lines := bytes.SplitN(raw, []byte("\n"), 6)
+	if len(lines) > 5 {
+		lines = lines[:5]
+	}
+	sample := bytes.Join(lines, []byte("\n"))
+	best, bestCount := ',', -1
+	for _, d := range []rune{',', ';', '\t', '|'} {
+		if n := bytes.Count(sample, []byte(string(d))); n > bestCount {
+			best, bestCount = d, n
+		}
+	}
+	return best
+}
+
+func normalizeHeader(s string) string {
+	s = strings.ToLower(strings.TrimSpace(s))
+	s = strings.NewReplacer("‌", " ", "ي", "ی", "ك", "ک", "_", " ").Replace(s)
+	return strings.Join(strings.Fields(s), " ")
+}
+
+func mapStatementHeader(rec []string) ([colCount]int, bool) {
+	var cols [colCount]int
+	for i := range cols {
+		cols[i] = -1
+	}
+	for idx, h := range rec {
+		h = normalizeHeader(h)
+		if h == "" {
+			continue
+		}
+		for _, kw := range statementHeaderKeywords {
+			if !containsAny(h, kw.keywords) {
+				continue
+			}
+			if cols[kw.col] < 0 {
+				cols[kw.col] = idx
+			}
+			break
+		}
+	}
+	hasAmount := cols[colAmount] >= 0 || cols[colDeposit] >= 0 || cols[colWithdrawal] >= 0
+	return cols, cols[colDate] >= 0 && hasAmount
+}
+
+func containsAny(s string, keywords []string) bool {
+	for _, k := range keywords {
+		if strings.Contains(s, k) {
+			return true
+		}
+	}
+	return false
+}
+
+func cell(rec []string, idx int) string {
+	if idx < 0 || idx >= len(rec) {
+		return ""
+	}
+	return strings.TrimSpace(rec[idx])
+}
+
+// parseStatementDate تاریخ‌هایی مثل 1403/01/15، 1403/01/15 10:20، 14030115 یا 2024-04-03 را می‌پذیرد.
+func parseStatementDate(s string) (time.Time, error) {
+	s = strings.TrimSpace(utils.NormalizeDigits(s))
+	if fields := strings.Fields(s); len(fields) > 0 {
+		s = fields[0]
+	}
+	if len(s) == 8 && strings.Trim(s, "0123456789") == "" {
+		s = s[:4] + "/" + s[4:6] + "/" + s[6:]
+	}
+	t, err := utils.ParseDateParam(s)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("invalid date %q", s)
+	}
+	return utils.StartOfDay(t), nil
+}
+
+// parseStatementAmount جداکننده هزارگان، ارقام فارسی و منفی به صورت (123) یا 123- را پشتیبانی می‌کند.
+func parseStatementAmount(s string) (decimal.Decimal, error) {
+	s = strings.TrimSpace(utils.NormalizeDigits(s))
+	if s == "" || s == "-" {
+		return decimal.Zero, nil
+	}
+	s = strings.NewReplacer(",", "", "٬", "", "،", "", " ", "", "‌", "", "٫", ".").Replace(s)
+	negative := false
+	switch {
+	case strings.HasPrefix(s, "(") && strings.HasSuffix(s, ")"):
+		negative, s = true, s[1:len(s)-1]
+	case strings.HasSuffix(s, "-"):
+		negative, s = true, strings.TrimSuffix(s, "-")
+	case strings.HasPrefix(s, "-"):
+		negative, s = true, strings.TrimPrefix(s, "-")
+	case strings.HasPrefix(s, "+"):
+		s = strings.TrimPrefix(s, "+")
+	}
+	v, err := decimal.NewFromString(s)
+	if err != nil {
+		return decimal.Zero, fmt.Errorf("invalid amount %q", s)
+	}
+	if negative {
+		v = v.Neg()
+	}
+	return v, nil
+}
+
+func isWithdrawalType(s string) bool {
+	s = normalizeHeader(s)
+	return containsAny(s, []string{"برداشت", "بدهکار", "debit", "withdraw", "پرداخت"})
+}
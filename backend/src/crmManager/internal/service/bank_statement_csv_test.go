@@ -0,0 +1,144 @@
+package service
+
+import (
+	"strings"
+	"testing"
+
+	"common-gold/money"
+
+	"github.com/shopspring/decimal"
+)
+
+func TestParseStatementAmount(t *testing.T) {
+	tests := []struct {
+		in      string
+		want    float64
+		wantErr bool
+	}{
+		{"", 0, false},
+		{"-", 0, false},
+		{"1,250,000", 1250000, false},
+		{"۱٬۲۵۰٬۰۰۰", 1250000, false},
+		{"(5000)", -5000, false},
+		{"5000-", -5000, false},
+		{"-5000", -5000, false},
+		{"+5000", 5000, false},
+		{"12٫5", 12.5, false},
+		{"abc", 0, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.in, func(t *testing.T) {
+			got, err := parseStatementAmount(tt.in)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("err = %v, wantErr %v", err, tt.wantErr)
+			}
+			if !got.Equal(decimal.NewFromFloat(tt.want)) {
+				t.Errorf("got %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestParseStatementDate(t *testing.T) {
+	tests := []struct {
+		in      string
+		want    string
+		wantErr bool
+	}{
+		{"1403/01/15", "2024-04-03", false},
+		{"۱۴۰۳/۰۱/۱۵ ۱۰:۲۰", "2024-04-03", false},
+		{"14030115", "2024-04-03", false},
+		{"2024-04-03", "2024-04-03", false},
+		{"yesterday", "", true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.in, func(t *testing.T) {
+			got, err := parseStatementDate(tt.in)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("err = %v, wantErr %v", err, tt.wantErr)
+			}
+			if !tt.wantErr && got.Format("2006-01-02") != tt.want {
+				t.Errorf("got %s, want %s", got.Format("2006-01-02"), tt.want)
+			}
+		})
+	}
+}
+
+func TestParseBankStatementCSV(t *testing.T) {
+	type row struct {
+		deposit, withdrawal float64
+		tracking            string
+	}
+	tests := []struct {
+		name       string
+		csv        string
+		unit       string
+		wantFormat string
+		want       []row
+		wantErr    string
+	}{
+		{
+			name: "debit and credit columns in rial",
+			csv: "شماره حساب: 0101\n" +
+				"تاریخ,شرح,برداشت,واریز,مانده,شماره پیگیری\n" +
+				"1403/01/15,خرید,50000,,950000,۱۲۳\n" +
+				"1403/01/16,واریز نقدی,,200000,1150000,124\n" +
+				",جمع,50000,200000,,\n",
+			wantFormat: statementFormatSplit,
+			want:       []row{{0, 5000, "123"}, {20000, 0, "124"}},
+		},
+		{
+			name:       "signed amount in toman with semicolons and BOM",
+			csv:        "\xef\xbb\xbfDate;Description;Amount\n2024-04-03;fee;(1500)\n2024-04-04;salary;30000\n",
+			unit:       StatementUnitToman,
+			wantFormat: statementFormatSigned,
+			want:       []row{{0, 1500, ""}, {30000, 0, ""}},
+		},
+		{
+			name:       "amount with type column",
+			csv:        "تاریخ,مبلغ,نوع تراکنش\n14030115,7000,برداشت\n14030115,9000,واریز\n",
+			unit:       StatementUnitToman,
+			wantFormat: statementFormatTyped,
+			want:       []row{{0, 7000, ""}, {9000, 0, ""}},
+		},
+		{
+			name:       "zero rows are skipped",
+			csv:        "تاریخ,بدهکار,بستانکار\n1403/01/15,0,0\n1403/01/15,10,\n",
+			unit:       StatementUnitToman,
+			wantFormat: statementFormatSplit,
+			want:       []row{{0, 10, ""}},
+		},
+		{name: "unknown unit", csv: "date,amount\n", unit: "dollar", wantErr: "unknown amount unit"},
+		{name: "empty file", csv: " \n", wantErr: "empty"},
+		{name: "no header", csv: "a,b\n1,2\n", wantErr: "header row"},
+		{name: "bad date", csv: "date,amount\nsoon,10\n", wantErr: "row 2"},
+		{name: "both sides set", csv: "تاریخ,بدهکار,بستانکار\n1403/01/15,10,20\n", wantErr: "both deposit and withdrawal"},
+		{name: "no transactions", csv: "تاریخ,مبلغ\n,جمع\n", wantErr: "no transaction rows"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rows, format, err := parseBankStatementCSV(strings.NewReader(tt.csv), tt.unit)
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Fatalf("err = %v, want it to contain %q", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			if format != tt.wantFormat {
+				t.Errorf("format = %s, want %s", format, tt.wantFormat)
+			}
+			if len(rows) != len(tt.want) {
+				t.Fatalf("got %d rows, want %d", len(rows), len(tt.want))
+			}
+			for i, w := range tt.want {
+				got := rows[i]
+				if got.Deposit.Cmp(money.NewAmount(w.deposit)) != 0 || got.Withdrawal.Cmp(money.NewAmount(w.withdrawal)) != 0 || got.TrackingCode != w.tracking {
+					t.Errorf("row %d = {%v %v %q}, want {%v %v %q}", i, got.Deposit, got.Withdrawal, got.TrackingCode, w.deposit, w.withdrawal, w.tracking)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,538 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"crm-gold/internal/model"
+	"crm-gold/internal/repository/db/postgresDb"
+	"crm-gold/internal/repository/repo"
+	"crm-gold/internal/utils"
+
+	"common-gold/money"
+
+	"go.uber.org/zap"
+	"gorm.io/gorm"
+)
+
+const (
+	defaultTopCenters = 5
+	maxTopCenters     = 50
+)
+
+type CenterService interface {
+	CreateCenter(ctx context.Context, req *model.CreateCenterRequest) (*model.FinanceCenter, error)
+	UpdateCenter(ctx context.Context, id uint, req *model.UpdateCenterRequest) (*model.FinanceCenter, error)
+	ListCenters(ctx context.Context, bidID uint, centerType string) ([]model.FinanceCenter, error)
+	GetCenterTree(ctx context.Context, bidID uint, centerType string) ([]model.FinanceCenter, error)
+	CreateVoucher(ctx context.Context, req *model.CreateCenterVoucherRequest, actor string) (*model.CenterVoucher, error)
+	GetVoucher(ctx context.Context, id uint) (*model.CenterVoucher, error)
+	ListVouchers(ctx context.Context, filter model.CenterVoucherFilter) ([]model.CenterVoucher, error)
+	ReverseVoucher(ctx context.Context, id uint, req *model.ReverseCenterVoucherRequest, actor string) (*model.CenterVoucher, error)
+	TopCenters(ctx context.Context, bidID uint, centerType string, from, to time.Time, limit int) (*model.TopCentersResponse, error)
+}
+
+type centerServiceImpl struct {
+	centerRepo   repo.CenterRepo
+	fundRepo     repo.FundRepo
+	bankRepo     repo.BankRepo
+	customerRepo repo.CustRepo
+	ledgerRepo   repo.LedgerRepo
+	logger       *zap.Logger
+}
+
+func NewCenterService(centerRepo repo.CenterRepo, fundRepo repo.FundRepo, bankRepo repo.BankRepo, customerRepo repo.CustRepo, ledgerRepo repo.LedgerRepo, logger *zap.Logger) (CenterService, error) {
+	if centerRepo == nil {
+		return nil, errors.New("centerRepository cannot be nil for CenterService")
+	}
+	if fundRepo == nil {
+		return nil, errors.New("fundRepository cannot be nil for CenterService")
+	}
+	if bankRepo == nil {
+		return nil, errors.New("bankRepository cannot be nil for CenterService")
+	}
+	if customerRepo == nil {
+		return nil, errors.New("customerRepository cannot be nil for CenterService")
+	}
+	if ledgerRepo == nil {
+		return nil, errors.New("ledgerRepository cannot be nil for CenterService")
+	}
+	if logger == nil {
+		return nil, errors.New("logger cannot be nil for CenterService")
+	}
+	return &centerServiceImpl{
+		centerRepo:   centerRepo,
+		fundRepo:     fundRepo,
+		bankRepo:     bankRepo,
+		customerRepo: customerRepo,
+		ledgerRepo:   ledgerRepo,
+		logger:       logger,
+	}, nil
+}
+
+func (s *centerServiceImpl) CreateCenter(ctx context.Context, req *model.CreateCenterRequest) (*model.FinanceCenter, error) {
+	if req.BIDID == 0 {
+		return nil, fmt.Errorf("%w: bidId is required", ErrValidation)
+	}
+	if req.Type != model.CenterTypeCost && req.Type != model.CenterTypeIncome {
+		return nil, fmt.Errorf("%w: center type must be cost or income", ErrValidation)
+	}
+	code := strings.TrimSpace(utils.NormalizeDigits(req.Code))
+	name := strings.TrimSpace(req.Name)
+	if code == "" || name == "" {
+		return nil, fmt.Errorf("%w: center code and name are required", ErrValidation)
+	}
+	center := &model.FinanceCenter{
+		BIDID:       req.BIDID,
+		Type:        req.Type,
+		Code:        code,
+		Name:        name,
+		Description: utils.PtrString(req.Description),
+		IsActive:    true,
+		Path:        "/",
+	}
+	if req.ParentID != nil {
+		parent, err := s.loadCenter(ctx, *req.ParentID)
+		if err != nil {
+			return nil, err
+		}
+		if parent.BIDID != req.BIDID || parent.Type != req.Type {
+			return nil, fmt.Errorf("%w: parent center must belong to the same business and type", ErrValidation)
+		}
+		center.ParentID = &parent.ID
+		center.Path = parent.Path
+	}
+	created, err := s.centerRepo.CreateCenter(ctx, center)
+	if err != nil {
+		if postgresDb.IsUniqueViolation(err) {
+			return nil, fmt.Errorf("%w: center code %s already exists", ErrConflict, code)
+		}
+		return nil, fmt.Errorf("failed to save center: %w", err)
+	}
+	return created, nil
+}
+
+func (s *centerServiceImpl) loadCenter(ctx context.Context, id uint) (*model.FinanceCenter, error) {
+	center, err := s.centerRepo.GetCenterByID(ctx, id)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, fmt.Errorf("%w: center %d", ErrNotFound, id)
+		}
+		return nil, fmt.Errorf("failed to fetch center: %w", err)
+	}
+	return center, nil
+}
+
+func (s *centerServiceImpl) UpdateCenter(ctx context.Context, id uint, req *model.UpdateCenterRequest) (*model.FinanceCenter, error) {
+	center, err := s.loadCenter(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	oldPath := center.Path
+	if req.Name != nil {
+		name := strings.TrimSpace(*req.Name)
+		if name == "" {
+			return nil, fmt.Errorf("%w: center name cannot be empty", ErrValidation)
+		}
+		center.Name = name
+	}
+	if req.Description != nil {
+		center.Description = utils.PtrString(*req.Description)
+	}
+	if req.IsActive != nil {
+		center.IsActive = *req.IsActive
+	}
+	if req.ParentID != nil {
+		// شناسه صفر یعنی انتقال مرکز به ریشه
+		parentPath := "/"
+		var parentID *uint
+		if *req.ParentID != 0 {
+			parent, err := s.loadCenter(ctx, *req.ParentID)
+			if err != nil {
+				return nil, err
+			}
+			if parent.BIDID != center.BIDID || parent.Type != center.Type {
+				return nil, fmt.Errorf("%w: parent center must belong to the same business and type", ErrValidation)
+			}
+			if strings.HasPrefix(parent.Path, oldPath) {
+				return nil, fmt.Errorf("%w: a center cannot be moved under itself or its descendants", ErrValidation)
+			}
+			parentPath, parentID = parent.Path, &parent.ID
+		}
+		center.ParentID = parentID
+		center.Path = fmt.Sprintf("%s%d/", parentPath, center.ID)
+	}
+	if err := s.centerRepo.UpdateCenter(ctx, center, oldPath); err != nil {
+		return nil, fmt.Errorf("failed to update center: %w", err)
+	}
+	return center, nil
+}
+
+func (s *centerServiceImpl) ListCenters(ctx context.Context, bidID uint, centerType string) ([]model.FinanceCenter, error) {
+	centers, err := s.centerRepo.ListCenters(ctx, bidID, centerType)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list centers: %w", err)
+	}
+	return centers, nil
+}
+
+// GetCenterTree مراکز را به صورت درختی برمی‌گرداند؛ ترتیب بر اساس Path تضمین می‌کند والد قبل از فرزند دیده شود.
+func (s *centerServiceImpl) GetCenterTree(ctx context.Context, bidID uint, centerType string) ([]model.FinanceCenter, error) {
+	centers, err := s.ListCenters(ctx, bidID, centerType)
+	if err != nil {
+		return nil, err
+	}
+	children := make(map[uint][]int)
+	var roots []int
+	for i := range centers {
+		if centers[i].ParentID == nil {
+			roots = append(roots, i)
+			continue
+		}
+		children[*centers[i].ParentID] = append(children[*centers[i].ParentID], i)
+	}
+	var build func(i int) model.FinanceCenter
+	build = func(i int) model.FinanceCenter {
+		node := centers[i]
+		for _, c := range children[node.ID] {
+			node.Children = append(node.Children, build(c))
+		}
+		return node
+	}
+	tree := make([]model.FinanceCenter, 0, len(roots))
+	for _, i := range roots {
+		tree = append(tree, build(i))
+	}
+	return tree, nil
+}
+
+// validateCashAccount صندوق یا حساب بانکی پرداخت‌کننده/دریافت‌کننده را بررسی می‌کند.
+func validateCashAccount(ctx context.Context, fundRepo repo.FundRepo, bankRepo repo.BankRepo, bidID uint, kind string, id uint, date time.Time) error {
+	var ownerBID uint
+	var openingDate time.Time
+	var active bool
+	switch kind {
+	case model.LedgerAccountFund:
+		fund, err := fundRepo.GetFundByID(ctx, id)
+		if err != nil {
+			return endpointLoadError(err, kind, id)
+		}
+		ownerBID, openingDate, active = fund.BIDID, fund.OpeningDate, fund.IsActive
+	case model.LedgerAccountBank:
+		account, err := bankRepo.GetBankAccountByID(ctx, id)
+		if err != nil {
+			return endpointLoadError(err, kind, id)
+		}
+		ownerBID, openingDate, active = account.BIDID, account.OpeningDate, account.IsActive
+	default:
+		return fmt.Errorf("%w: account kind must be fund or bank", ErrValidation)
+	}
+	if !active {
+		return fmt.Errorf("%w: %s %d is inactive", ErrValidation, kind, id)
+	}
+	if ownerBID != bidID {
+		return fmt.Errorf("%w: %s %d belongs to another business", ErrValidation, kind, id)
+	}
+	if date.Before(openingDate) {
+		return fmt.Errorf("%w: date is before the opening date of %s %d", ErrValidation, kind, id)
+	}
+	return nil
+}
+
+func (s *centerServiceImpl) CreateVoucher(ctx context.Context, req *model.CreateCenterVoucherRequest, actor string) (*model.CenterVoucher, error) {
+	if !req.Amount.IsPositive() {
+		return nil, fmt.Errorf("%w: voucher amount must be positive", ErrValidation)
+	}
+	date, err := utils.ParseDateParam(req.Date)
+	if err != nil {
+		return nil, fmt.Errorf("%w: invalid voucher date: %v", ErrValidation, err)
+	}
+	date = utils.StartOfDay(date)
+	center, err := s.loadCenter(ctx, req.CenterID)
+	if err != nil {
+		return nil, err
+	}
+	if !center.IsActive {
+		return nil, fmt.Errorf("%w: center %d is inactive", ErrValidation, center.ID)
+	}
+	if err := validateCashAccount(ctx, s.fundRepo, s.bankRepo, center.BIDID, req.AccountKind, req.AccountID, date); err != nil {
+		return nil, err
+	}
+	if req.PersonID != nil {
+		person, err := s.customerRepo.GetCustomerByID(ctx, *req.PersonID)
+		if err != nil {
+			return nil, endpointLoadError(err, model.LedgerAccountPerson, *req.PersonID)
+		}
+		if person.BIDID != center.BIDID {
+			return nil, fmt.Errorf("%w: person %d belongs to another business", ErrValidation, person.ID)
+		}
+	}
+
+	voucher, err := newCenterVoucher(center, date, req.Amount, req.AccountKind, req.AccountID, req.PersonID, req.Description, actor)
+	if err != nil {
+		return nil, err
+	}
+	created, err := s.centerRepo.CreateCenterVoucher(ctx, voucher, centerVoucherEntry(voucher, center, actor))
+	if err != nil {
+		if errors.Is(err, postgresDb.ErrFundDayClosed) {
+			return nil, fmt.Errorf("%w: %v", ErrConflict, err)
+		}
+		return nil, fmt.Errorf("failed to save center voucher: %w", err)
+	}
+	s.logger.Info("Center voucher created.", zap.Uint("voucher_id", created.ID), zap.String("number", created.Number), zap.String("amount", created.Amount.String()))
+	return created, nil
+}
+
+func newCenterVoucher(center *model.FinanceCenter, date time.Time, amount money.Amount, accountKind string, accountID uint, personID *uint, description, actor string) (*model.CenterVoucher, error) {
+	prefix := "EXP-"
+	if center.Type == model.CenterTypeIncome {
+		prefix = "INC-"
+	}
+	code, err := utils.GenerateSecureRandomString(8)
+	if err != nil {
+		return nil, fmt.Errorf("failed to generate voucher number: %w", err)
+	}
+	return &model.CenterVoucher{
+		BIDID:       center.BIDID,
+		CenterID:    center.ID,
+		CenterType:  center.Type,
+		Number:      prefix + code,
+		Date:        date,
+		DateJalali:  utils.FormatJalali(date),
+		Amount:      amount,
+		AccountKind: accountKind,
+		AccountID:   accountID,
+		PersonID:    personID,
+		Description: utils.PtrString(description),
+		Status:      model.CenterVoucherPosted,
+		CreatedBy:   actor,
+	}, nil
+}
+
+// centerVoucherEntry هزینه: مرکز هزینه بدهکار و صندوق/بانک بستانکار؛ درآمد برعکس.
+func centerVoucherEntry(voucher *model.CenterVoucher, center *model.FinanceCenter, actor string) *model.LedgerEntry {
+	centerID, accountID := voucher.CenterID, voucher.AccountID
+	centerKind := model.LedgerAccountExpense
+	desc := "هزینه " + center.Name
+	if voucher.CenterType == model.CenterTypeIncome {
+		centerKind = model.LedgerAccountIncome
+		desc = "درآمد " + center.Name
+	}
+	if voucher.Description != nil {
+		desc = desc + " - " + *voucher.Description
+	}
+	centerLine := model.LedgerLine{AccountKind: centerKind, AccountID: &centerID, Description: desc}
+	cashLine := model.LedgerLine{AccountKind: voucher.AccountKind, AccountID: &accountID, Description: desc}
+	amount := voucher.Amount
+	if centerKind == model.LedgerAccountExpense {
+		centerLine.Debit, cashLine.Credit = amount, amount
+	} else {
+		cashLine.Debit, centerLine.Credit = amount, amount
+	}
+	return &model.LedgerEntry{
+		BIDID:       voucher.BIDID,
+		DocType:     model.LedgerDocCenter,
+		Reference:   voucher.Number,
+		Description: desc,
+		PostedAt:    voucher.Date,
+		CreatedBy:   actor,
+		Lines:       []model.LedgerLine{centerLine, cashLine},
+	}
+}
+
+func (s *centerServiceImpl) GetVoucher(ctx context.Context, id uint) (*model.CenterVoucher, error) {
+	voucher, err := s.centerRepo.GetCenterVoucherByID(ctx, id)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, fmt.Errorf("%w: center voucher %d", ErrNotFound, id)
+		}
+		return nil, fmt.Errorf("failed to fetch center voucher: %w", err)
+	}
+	return voucher, nil
+}
+
+func (s *centerServiceImpl) ListVouchers(ctx context.Context, filter model.CenterVoucherFilter) ([]model.CenterVoucher, error) {
+	vouchers, err := s.centerRepo.ListCenterVouchers(ctx, filter)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list center vouchers: %w", err)
+	}
+	return vouchers, nil
+}
+
+func (s *centerServiceImpl) ReverseVoucher(ctx context.Context, id uint, req *model.ReverseCenterVoucherRequest, actor string) (*model.CenterVoucher, error) {
+	voucher, err := s.GetVoucher(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if voucher.Status != model.CenterVoucherPosted {
+		return nil, fmt.Errorf("%w: voucher is already %s", ErrInvalidTransition, voucher.Status)
+	}
+	if strings.TrimSpace(req.Reason) == "" {
+		return nil, fmt.Errorf("%w: reversal reason is required", ErrValidation)
+	}
+	at := utils.StartOfDay(time.Now())
+	if req.Date != "" {
+		d, err := utils.ParseDateParam(req.Date)
+		if err != nil {
+			return nil, fmt.Errorf("%w: invalid reversal date: %v", ErrValidation, err)
+		}
+		at = utils.StartOfDay(d)
+	}
+	if at.Before(voucher.Date) {
+		return nil, fmt.Errorf("%w: reversal date cannot be before the voucher date", ErrValidation)
+	}
+	if voucher.LedgerEntryID == nil {
+		return nil, fmt.Errorf("center voucher %d has no ledger entry", voucher.ID)
+	}
+	entry, err := s.ledgerRepo.GetEntryByID(ctx, *voucher.LedgerEntryID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load voucher ledger entry: %w", err)
+	}
+
+	voucher.ReversedAt = &at
+	voucher.ReversedBy = actor
+	voucher.ReversalReason = utils.PtrString(strings.TrimSpace(req.Reason))
+	if err := s.centerRepo.ReverseCenterVoucher(ctx, voucher, entry.Reversal(at, actor, req.Reason)); err != nil {
+		switch {
+		case errors.Is(err, postgresDb.ErrCenterVoucherReversed):
+			return nil, fmt.Errorf("%w: %v", ErrConflict, err)
+		case errors.Is(err, postgresDb.ErrFundDayClosed):
+			return nil, fmt.Errorf("%w: %v", ErrConflict, err)
+		}
+		return nil, fmt.Errorf("failed to reverse center voucher: %w", err)
+	}
+	voucher.Status = model.CenterVoucherReversed
+	s.logger.Info("Center voucher reversed.", zap.Uint("voucher_id", voucher.ID), zap.String("actor", actor))
+	return voucher, nil
+}
+
+// TopCenters مبالغ زیرشاخه‌ها به مرکز ریشه خود جمع می‌شوند و بقیه مراکز خارج از N مورد اول در Others قرار می‌گیرند.
+// centerType خالی هر دو نمودار را برمی‌گرداند.
+func (s *centerServiceImpl) TopCenters(ctx context.Context, bidID uint, centerType string, from, to time.Time, limit int) (*model.TopCentersResponse, error) {
+	if bidID == 0 {
+		return nil, fmt.Errorf("%w: bidId is required", ErrValidation)
+	}
+	if !from.Before(to) {
+		return nil, fmt.Errorf("%w: 'from' must be before 'to'", ErrValidation)
+	}
+	if limit <= 0 {
+		limit = defaultTopCenters
+	}
+	if limit > maxTopCenters {
+		limit = maxTopCenters
+	}
+	resp := &model.TopCentersResponse{}
+	switch centerType {
+	case "":
+		cost, err := s.centerChart(ctx, bidID, model.CenterTypeCost, from, to, limit)
+		if err != nil {
+			return nil, err
+		}
+		income, err := s.centerChart(ctx, bidID, model.CenterTypeIncome, from, to, limit)
+		if err != nil {
+			return nil, err
+		}
+		resp.CostCenters, resp.IncomeCenters = cost, income
+	case model.CenterTypeCost:
+		cost, err := s.centerChart(ctx, bidID, centerType, from, to, limit)
+		if err != nil {
+			return nil, err
+		}
+		resp.CostCenters = cost
+	case model.CenterTypeIncome:
+		income, err := s.centerChart(ctx, bidID, centerType, from, to, limit)
+		if err != nil {
+			return nil, err
+		}
+		resp.IncomeCenters = income
+	default:
+		return nil, fmt.Errorf("%w: center type must be cost or income", ErrValidation)
+	}
+	return resp, nil
+}
+
+func (s *centerServiceImpl) centerChart(ctx context.Context, bidID uint, centerType string, from, to time.Time, limit int) (*model.CenterChartData, error) {
+	accountKind := model.LedgerAccountExpense
+	if centerType == model.CenterTypeIncome {
+		accountKind = model.LedgerAccountIncome
+	}
+	amounts, err := s.centerRepo.CenterTotals(ctx, bidID, accountKind, from, to)
+	if err != nil {
+		return nil, fmt.Errorf("failed to compute center totals: %w", err)
+	}
+	centers, err := s.centerRepo.ListCenters(ctx, bidID, centerType)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list centers: %w", err)
+	}
+	byID := make(map[uint]*model.FinanceCenter, len(centers))
+	for i := range centers {
+		byID[centers[i].ID] = &centers[i]
+	}
+
+	rolled := make(map[uint]money.Amount)
+	for _, a := range amounts {
+		root := rootCenterID(byID, a.CenterID)
+		rolled[root] = rolled[root].Add(a.Amount)
+	}
+	items := make([]model.CenterTotal, 0, len(rolled))
+	var total money.Amount
+	for id, amount := range rolled {
+		if amount.IsZero() {
+			continue
+		}
+		item := model.CenterTotal{CenterID: id, Amount: amount}
+		if c, ok := byID[id]; ok {
+			item.Code, item.Name = c.Code, c.Name
+		}
+		items = append(items, item)
+		total = total.Add(amount)
+	}
+	sort.Slice(items, func(i, j int) bool {
+		if c := items[i].Amount.Cmp(items[j].Amount); c != 0 {
+			return c > 0
+		}
+		return items[i].Code < items[j].Code
+	})
+
+	chart := &model.CenterChartData{
+		Type:   centerType,
+		From:   utils.FormatJalali(from),
+		To:     utils.FormatJalali(to.AddDate(0, 0, -1)),
+		Total:  total,
+		Labels: []string{},
+		Values: []money.Amount{},
+		Items:  []model.CenterTotal{},
+	}
+	for i := range items {
+		if !total.IsZero() {
+			items[i].Percent = items[i].Amount.Decimal().Div(total.Decimal()).Shift(2).Round(2).InexactFloat64()
+		}
+		if i < limit {
+			chart.Items = append(chart.Items, items[i])
+			chart.Labels = append(chart.Labels, items[i].Name)
+			chart.Values = append(chart.Values, items[i].Amount)
+		} else {
+			chart.Others = chart.Others.Add(items[i].Amount)
+		}
+	}
+	return chart, nil
+}
+
+// rootCenterID شناسه اولین جزء Path مرکز را برمی‌گرداند.
+func rootCenterID(byID map[uint]*model.FinanceCenter, id uint) uint {
+	c, ok := byID[id]
+	if !ok {
+		return id
+	}
+	first := strings.SplitN(strings.Trim(c.Path, "/"), "/", 2)[0]
+	root, err := strconv.ParseUint(first, 10, 64)
+	if err != nil {
+		return id
+	}
+	return uint(root)
+}
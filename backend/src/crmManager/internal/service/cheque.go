@@ -0,0 +1,377 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"crm-gold/internal/model"
+	"crm-gold/internal/repository/db/postgresDb"
+	"crm-gold/internal/repository/repo"
+	"crm-gold/internal/utils"
+
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+	"gorm.io/gorm"
+)
+
+type ChequeService interface {
+	CreateCheque(ctx context.Context, direction string, req *model.CreateChequeRequest, actor string) (*model.Cheque, error)
+	GetCheque(ctx context.Context, bidID, id uint) (*model.Cheque, error)
+	ListCheques(ctx context.Context, filter model.ChequeFilter) ([]model.Cheque, error)
+	ChangeStatus(ctx context.Context, bidID, id uint, req *model.ChequeTransitionRequest, actor string) (*model.Cheque, error)
+	GetChequeEntries(ctx context.Context, bidID, id uint) ([]model.LedgerEntry, error)
+}
+
+// chequeTransitions وضعیت‌های مجاز بعدی هر چک را بر اساس نوع آن مشخص می‌کند.
+var chequeTransitions = map[string]map[string][]string{
+	model.ChequeDirectionReceived: {
+		model.ChequeStatusInHand:    {model.ChequeStatusDeposited, model.ChequeStatusEndorsed, model.ChequeStatusReturned},
+		model.ChequeStatusDeposited: {model.ChequeStatusCleared, model.ChequeStatusBounced},
+		model.ChequeStatusBounced:   {model.ChequeStatusDeposited, model.ChequeStatusReturned},
+	},
+	model.ChequeDirectionIssued: {
+		model.ChequeStatusIssued:  {model.ChequeStatusCleared, model.ChequeStatusBounced, model.ChequeStatusReturned},
+		model.ChequeStatusBounced: {model.ChequeStatusCleared},
+	},
+}
+
+type chequeServiceImpl struct {
+	chequeRepo   repo.ChequeRepo
+	customerRepo repo.CustRepo
+	bankRepo     repo.BankRepo
+	ledgerRepo   repo.LedgerRepo
+	logger       *zap.Logger
+}
+
+func NewChequeService(chequeRepo repo.ChequeRepo, customerRepo repo.CustRepo, bankRepo repo.BankRepo, ledgerRepo repo.LedgerRepo, logger *zap.Logger) (ChequeService, error) {
+	if chequeRepo == nil {
+		return nil, errors.New("chequeRepository cannot be nil for ChequeService")
+	}
+	if customerRepo == nil {
+		return nil, errors.New("customerRepository cannot be nil for ChequeService")
+	}
+	if bankRepo == nil {
+		return nil, errors.New("bankRepository cannot be nil for ChequeService")
+	}
+	if ledgerRepo == nil {
+		return nil, errors.New("ledgerRepository cannot be nil for ChequeService")
+	}
+	if logger == nil {
+		return nil, errors.New("logger cannot be nil for ChequeService")
+	}
+	return &chequeServiceImpl{
+		chequeRepo:   chequeRepo,
+		customerRepo: customerRepo,
+		bankRepo:     bankRepo,
+		ledgerRepo:   ledgerRepo,
+		logger:       logger,
+	}, nil
+}
+
+func (s *chequeServiceImpl) CreateCheque(ctx context.Context, direction string, req *model.CreateChequeRequest, actor string) (*model.Cheque, error) {
+	if direction != model.ChequeDirectionReceived && direction != model.ChequeDirectionIssued {
+		return nil, fmt.Errorf("%w: unknown cheque direction %q", ErrValidation, direction)
+	}
+	if req.BIDID == 0 {
+		return nil, fmt.Errorf("%w: bidId is required", ErrValidation)
+	}
+	sayadID := utils.NormalizeDigits(strings.TrimSpace(req.SayadID))
+	if len(sayadID) != 16 || strings.Trim(sayadID, "0123456789") != "" {
+		return nil, fmt.Errorf("%w: sayad id must be exactly 16 digits", ErrValidation)
+	}
+	if strings.TrimSpace(req.Serial) == "" {
+		return nil, fmt.Errorf("%w: cheque serial is required", ErrValidation)
+	}
+	if strings.TrimSpace(req.BankName) == "" {
+		return nil, fmt.Errorf("%w: bank name is required", ErrValidation)
+	}
+	if !req.Amount.IsPositive() {
+		return nil, fmt.Errorf("%w: cheque amount must be positive", ErrValidation)
+	}
+	dueDate, err := utils.ParseDateParam(req.DueDate)
+	if err != nil {
+		return nil, fmt.Errorf("%w: invalid due date: %v", ErrValidation, err)
+	}
+	var issueDate *time.Time
+	if req.IssueDate != "" {
+		d, err := utils.ParseDateParam(req.IssueDate)
+		if err != nil {
+			return nil, fmt.Errorf("%w: invalid issue date: %v", ErrValidation, err)
+		}
+		issueDate = &d
+	}
+
+	var responsibleRef *string
+	if req.ResponsibleUserRef != nil && strings.TrimSpace(*req.ResponsibleUserRef) != "" {
+		ref, err := uuid.Parse(strings.TrimSpace(*req.ResponsibleUserRef))
+		if err != nil {
+			return nil, fmt.Errorf("%w: responsibleUserRef must be a user id (UUID)", ErrValidation)
+		}
+		responsibleRef = utils.PtrString(ref.String())
+	}
+
+	person, err := s.customerRepo.GetCustomerByID(ctx, req.PersonID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, fmt.Errorf("%w: person %d does not exist", ErrValidation, req.PersonID)
+		}
+		return nil, fmt.Errorf("failed to load cheque person: %w", err)
+	}
+	bidID := req.BIDID
+	if person.BIDID != bidID {
+		return nil, fmt.Errorf("%w: person %d belongs to another business", ErrValidation, person.ID)
+	}
+
+	now := time.Now()
+	status := model.ChequeStatusInHand
+	if direction == model.ChequeDirectionIssued {
+		status = model.ChequeStatusIssued
+	}
+	cheque := &model.Cheque{
+		BIDID:              bidID,
+		Direction:          direction,
+		Status:             status,
+		Serial:             strings.TrimSpace(req.Serial),
+		SayadID:            sayadID,
+		BankName:           strings.TrimSpace(req.BankName),
+		Branch:             utils.PtrString(req.Branch),
+		Amount:             req.Amount,
+		IssueDate:          issueDate,
+		DueDate:            dueDate,
+		DueDateJalali:      utils.FormatJalali(dueDate),
+		PersonID:           person.ID,
+		ResponsibleUserRef: responsibleRef,
+		StatusChangedAt:    &now,
+		Description:        utils.PtrString(req.Description),
+	}
+
+	entry := s.buildEntry(cheque, "", status, actor, now)
+	history := &model.ChequeStatusHistory{
+		ToStatus:  status,
+		ChangedAt: now,
+		ChangedBy: actor,
+	}
+
+	created, err := s.chequeRepo.CreateCheque(ctx, cheque, history, entry)
+	if err != nil {
+		if postgresDb.IsUniqueViolation(err) {
+			return nil, fmt.Errorf("%w: cheque with this sayad id already exists", ErrConflict)
+		}
+		return nil, fmt.Errorf("failed to save cheque: %w", err)
+	}
+	s.logger.Info("Cheque registered.", zap.Uint("cheque_id", created.ID), zap.String("direction", direction), zap.String("amount", created.Amount.String()))
+	return created, nil
+}
+
+// GetCheque چک کسب‌وکار دیگر مانند چک ناموجود گزارش می‌شود تا وجودش هم فاش نشود.
+func (s *chequeServiceImpl) GetCheque(ctx context.Context, bidID, id uint) (*model.Cheque, error) {
+	if bidID == 0 {
+		return nil, fmt.Errorf("%w: bidId is required", ErrValidation)
+	}
+	cheque, err := s.chequeRepo.GetChequeByID(ctx, id)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, fmt.Errorf("%w: cheque %d", ErrNotFound, id)
+		}
+		return nil, fmt.Errorf("failed to fetch cheque: %w", err)
+	}
+	if cheque.BIDID != bidID {
+		return nil, fmt.Errorf("%w: cheque %d", ErrNotFound, id)
+	}
+	return cheque, nil
+}
+
+func (s *chequeServiceImpl) ListCheques(ctx context.Context, filter model.ChequeFilter) ([]model.Cheque, error) {
+	if filter.BIDID == 0 {
+		return nil, fmt.Errorf("%w: bidId is required", ErrValidation)
+	}
+	cheques, err := s.chequeRepo.ListCheques(ctx, filter)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list cheques: %w", err)
+	}
+	return cheques, nil
+}
+
+func (s *chequeServiceImpl) ChangeStatus(ctx context.Context, bidID, id uint, req *model.ChequeTransitionRequest, actor string) (*model.Cheque, error) {
+	cheque, err := s.GetCheque(ctx, bidID, id)
+	if err != nil {
+		return nil, err
+	}
+	from := cheque.Status
+	if !isAllowedChequeTransition(cheque.Direction, from, req.Status) {
+		return nil, fmt.Errorf("%w: %s cheque cannot move from %s to %s", ErrInvalidTransition, cheque.Direction, from, req.Status)
+	}
+
+	switch req.Status {
+	case model.ChequeStatusEndorsed:
+		if req.EndorsedToPersonID == nil {
+			return nil, fmt.Errorf("%w: endorsedToPersonId is required to endorse a cheque", ErrValidation)
+		}
+		if *req.EndorsedToPersonID == cheque.PersonID {
+			return nil, fmt.Errorf("%w: a cheque cannot be endorsed back to its drawer, return it instead", ErrValidation)
+		}
+		endorsee, err := s.customerRepo.GetCustomerByID(ctx, *req.EndorsedToPersonID)
+		if err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				return nil, fmt.Errorf("%w: person %d does not exist", ErrValidation, *req.EndorsedToPersonID)
+			}
+			return nil, fmt.Errorf("failed to load endorsee: %w", err)
+		}
+		if endorsee.BIDID != cheque.BIDID {
+			return nil, fmt.Errorf("%w: person %d belongs to another business", ErrValidation, endorsee.ID)
+		}
+		cheque.EndorsedToPersonID = req.EndorsedToPersonID
+	case model.ChequeStatusDeposited:
+		if req.BankID == nil {
+			return nil, fmt.Errorf("%w: bankId is required to deposit a cheque", ErrValidation)
+		}
+		if err := s.validateBankAccount(ctx, cheque, *req.BankID); err != nil {
+			return nil, err
+		}
+		cheque.DepositBankID = req.BankID
+	case model.ChequeStatusCleared:
+		if cheque.Direction == model.ChequeDirectionIssued {
+			if req.BankID == nil {
+				return nil, fmt.Errorf("%w: bankId is required to clear an issued cheque", ErrValidation)
+			}
+			if err := s.validateBankAccount(ctx, cheque, *req.BankID); err != nil {
+				return nil, err
+			}
+			cheque.DepositBankID = req.BankID
+		}
+	}
+
+	now := time.Now()
+	cheque.Status = req.Status
+	cheque.StatusChangedAt = &now
+
+	entry := s.buildEntry(cheque, from, req.Status, actor, now)
+	history := &model.ChequeStatusHistory{
+		ChequeID:   cheque.ID,
+		FromStatus: from,
+		ToStatus:   req.Status,
+		ChangedAt:  now,
+		ChangedBy:  actor,
+		Note:       utils.PtrString(req.Note),
+	}
+
+	if err := s.chequeRepo.ApplyChequeTransition(ctx, cheque, from, history, entry); err != nil {
+		if errors.Is(err, postgresDb.ErrStaleChequeStatus) {
+			return nil, fmt.Errorf("%w: cheque status changed by another request, reload and retry", ErrConflict)
+		}
+		return nil, fmt.Errorf("failed to change cheque status: %w", err)
+	}
+
+	s.logger.Info("Cheque status changed.", zap.Uint("cheque_id", cheque.ID), zap.String("from", from), zap.String("to", req.Status), zap.String("actor", actor))
+	return s.GetCheque(ctx, bidID, id)
+}
+
+func (s *chequeServiceImpl) GetChequeEntries(ctx context.Context, bidID, id uint) ([]model.LedgerEntry, error) {
+	if _, err := s.GetCheque(ctx, bidID, id); err != nil {
+		return nil, err
+	}
+	entries, err := s.ledgerRepo.GetEntriesByDocument(ctx, model.LedgerDocCheque, id)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch cheque ledger entries: %w", err)
+	}
+	return entries, nil
+}
+
+func (s *chequeServiceImpl) validateBankAccount(ctx context.Context, cheque *model.Cheque, bankID uint) error {
+	account, err := s.bankRepo.GetBankAccountByID(ctx, bankID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return fmt.Errorf("%w: bank account %d does not exist", ErrValidation, bankID)
+		}
+		return fmt.Errorf("failed to load bank account: %w", err)
+	}
+	if account.BIDID != cheque.BIDID {
+		return fmt.Errorf("%w: bank account belongs to another business", ErrValidation)
+	}
+	return nil
+}
+
+func isAllowedChequeTransition(direction, from, to string) bool {
+	for _, next := range chequeTransitions[direction][from] {
+		if next == to {
+			return true
+		}
+	}
+	return false
+}
+
+// buildEntry سند حسابداری متناظر با ورود چک به وضعیت جدید را می‌سازد.
+func (s *chequeServiceImpl) buildEntry(cheque *model.Cheque, from, to, actor string, at time.Time) *model.LedgerEntry {
+	person := cheque.PersonID
+	debit, credit := chequeEffect(cheque, from, to)
+	if debit.AccountKind == "" {
+		return nil
+	}
+	if debit.AccountKind == model.LedgerAccountPerson && debit.AccountID == nil {
+		debit.AccountID = &person
+	}
+	if credit.AccountKind == model.LedgerAccountPerson && credit.AccountID == nil {
+		credit.AccountID = &person
+	}
+	debit.Debit = cheque.Amount
+	credit.Credit = cheque.Amount
+
+	desc := fmt.Sprintf("چک %s صیادی %s: %s", cheque.Serial, cheque.SayadID, to)
+	debit.Description = desc
+	credit.Description = desc
+	return &model.LedgerEntry{
+		BIDID:       cheque.BIDID,
+		DocType:     model.LedgerDocCheque,
+		DocID:       cheque.ID,
+		Reference:   fmt.Sprintf("CHQ-%s-%s", cheque.SayadID, to),
+		Description: desc,
+		PostedAt:    at,
+		CreatedBy:   actor,
+		Lines:       []model.LedgerLine{debit, credit},
+	}
+}
+
+func chequeEffect(cheque *model.Cheque, from, to string) (model.LedgerLine, model.LedgerLine) {
+	line := func(kind string, id *uint) model.LedgerLine {
+		return model.LedgerLine{AccountKind: kind, AccountID: id}
+	}
+	chequeID := &cheque.ID
+
+	if cheque.Direction == model.ChequeDirectionReceived {
+		switch {
+		case from == "" && to == model.ChequeStatusInHand:
+			return line(model.LedgerAccountChequesReceivable, chequeID), line(model.LedgerAccountPerson, nil)
+		case to == model.ChequeStatusDeposited && from == model.ChequeStatusInHand:
+			return line(model.LedgerAccountChequesCollection, chequeID), line(model.LedgerAccountChequesReceivable, chequeID)
+		case to == model.ChequeStatusDeposited && from == model.ChequeStatusBounced:
+			return line(model.LedgerAccountChequesCollection, chequeID), line(model.LedgerAccountChequesBounced, chequeID)
+		case to == model.ChequeStatusCleared:
+			return line(model.LedgerAccountBank, cheque.DepositBankID), line(model.LedgerAccountChequesCollection, chequeID)
+		case to == model.ChequeStatusBounced:
+			return line(model.LedgerAccountChequesBounced, chequeID), line(model.LedgerAccountChequesCollection, chequeID)
+		case to == model.ChequeStatusEndorsed:
+			return line(model.LedgerAccountPerson, cheque.EndorsedToPersonID), line(model.LedgerAccountChequesReceivable, chequeID)
+		case to == model.ChequeStatusReturned && from == model.ChequeStatusInHand:
+			return line(model.LedgerAccountPerson, nil), line(model.LedgerAccountChequesReceivable, chequeID)
+		case to == model.ChequeStatusReturned && from == model.ChequeStatusBounced:
+			return line(model.LedgerAccountPerson, nil), line(model.LedgerAccountChequesBounced, chequeID)
+		}
+		return model.LedgerLine{}, model.LedgerLine{}
+	}
+
+	switch {
+	case from == "" && to == model.ChequeStatusIssued:
+		return line(model.LedgerAccountPerson, nil), line(model.LedgerAccountChequesPayable, chequeID)
+	case to == model.ChequeStatusCleared && from == model.ChequeStatusIssued:
+		return line(model.LedgerAccountChequesPayable, chequeID), line(model.LedgerAccountBank, cheque.DepositBankID)
+	case to == model.ChequeStatusCleared && from == model.ChequeStatusBounced:
+		// بدهی به طرف حساب پس از برگشت چک دوباره برقرار شده بود؛ اکنون از بانک تسویه می‌شود
+		return line(model.LedgerAccountPerson, nil), line(model.LedgerAccountBank, cheque.DepositBankID)
+	case to == model.ChequeStatusBounced, to == model.ChequeStatusReturned:
+		return line(model.LedgerAccountChequesPayable, chequeID), line(model.LedgerAccountPerson, nil)
+	}
+	return model.LedgerLine{}, model.LedgerLine{}
+}
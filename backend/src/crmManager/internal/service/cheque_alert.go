@@ -0,0 +1,195 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"crm-gold/internal/model"
+	"crm-gold/internal/repository/repo"
+	"crm-gold/internal/service/notification"
+	"crm-gold/internal/utils"
+
+	"go.uber.org/zap"
+)
+
+const chequeAlertNotificationType = "CHEQUE_DUE"
+
+type ChequeAlertService interface {
+	RunDueAlerts(ctx context.Context, now time.Time) (int, error)
+	GetDueSummary(ctx context.Context, bidID uint, now time.Time) (*model.ChequeDueSummary, error)
+}
+
+type chequeAlertServiceImpl struct {
+	chequeRepo repo.ChequeRepo
+	notifier   notification.NotificationClient
+	windowDays int
+	logger     *zap.Logger
+}
+
+func NewChequeAlertService(chequeRepo repo.ChequeRepo, notifier notification.NotificationClient, windowDays int, logger *zap.Logger) (ChequeAlertService, error) {
+	if chequeRepo == nil {
+		return nil, errors.New("chequeRepository cannot be nil for ChequeAlertService")
+	}
+	if notifier == nil {
+		return nil, errors.New("notification client cannot be nil for ChequeAlertService")
+	}
+	if logger == nil {
+		return nil, errors.New("logger cannot be nil for ChequeAlertService")
+	}
+	if windowDays < 0 {
+		return nil, errors.New("cheque alert window cannot be negative")
+	}
+	return &chequeAlertServiceImpl{
+		chequeRepo: chequeRepo,
+		notifier:   notifier,
+		windowDays: windowDays,
+		logger:     logger,
+	}, nil
+}
+
+// outstandingChequeStatuses چک‌هایی که هنوز نیاز به اقدام (واگذاری به بانک یا تأمین موجودی) دارند.
+var outstandingChequeStatuses = []string{model.ChequeStatusInHand, model.ChequeStatusIssued}
+
+// RunDueAlerts برای هر چک سررسید شده یا نزدیک به سررسید حداکثر یک اعلان از هر نوع می‌فرستد.
+// اجرای مجدد در همان روز اعلان تکراری ایجاد نمی‌کند و ارسال‌های ناموفق در اجرای بعدی دوباره تلاش می‌شوند.
+func (s *chequeAlertServiceImpl) RunDueAlerts(ctx context.Context, now time.Time) (int, error) {
+	today := utils.StartOfDay(now)
+	until := today.AddDate(0, 0, s.windowDays+1)
+
+	cheques, err := s.chequeRepo.ListCheques(ctx, model.ChequeFilter{
+		Statuses: outstandingChequeStatuses,
+		DueTo:    &until,
+	})
+	if err != nil {
+		return 0, fmt.Errorf("failed to load due cheques: %w", err)
+	}
+
+	sent := 0
+	for i := range cheques {
+		cheque := &cheques[i]
+		kind := chequeAlertKind(cheque.DueDate, today)
+
+		alert, err := s.chequeRepo.ClaimChequeAlert(ctx, &model.ChequeAlert{
+			ChequeID: cheque.ID,
+			Kind:     kind,
+			DueDate:  cheque.DueDate,
+		})
+		if err != nil {
+			s.logger.Error("Failed to claim cheque alert", zap.Uint("cheque_id", cheque.ID), zap.Error(err))
+			continue
+		}
+		if alert.SentAt != nil {
+			continue
+		}
+
+		recipient := chequeAlertRecipient(cheque)
+		if recipient == nil {
+			s.logger.Warn("Cheque has no responsible user, skipping due alert.", zap.Uint("cheque_id", cheque.ID))
+			msg := "no responsible user for cheque"
+			alert.LastError = &msg
+			_ = s.chequeRepo.UpdateChequeAlert(ctx, alert)
+			continue
+		}
+		alert.RecipientRef = recipient
+
+		if err := s.notifier.SendToUser(ctx, *recipient, chequeAlertNotificationType, chequeAlertMessage(cheque, kind)); err != nil {
+			s.logger.Error("Failed to send cheque due alert", zap.Uint("cheque_id", cheque.ID), zap.Error(err))
+			msg := err.Error()
+			alert.LastError = &msg
+			_ = s.chequeRepo.UpdateChequeAlert(ctx, alert)
+			continue
+		}
+
+		sentAt := time.Now()
+		alert.SentAt = &sentAt
+		alert.LastError = nil
+		if err := s.chequeRepo.UpdateChequeAlert(ctx, alert); err != nil {
+			s.logger.Error("Failed to mark cheque alert as sent", zap.Uint("cheque_id", cheque.ID), zap.Error(err))
+		}
+		sent++
+	}
+
+	s.logger.Info("Cheque due alert run finished.", zap.Int("candidates", len(cheques)), zap.Int("sent", sent))
+	return sent, nil
+}
+
+// GetDueSummary دسته‌ها جدا از هم هستند: سررسید گذشته، امروز، و از فردا تا پایان هفته (جمعه).
+func (s *chequeAlertServiceImpl) GetDueSummary(ctx context.Context, bidID uint, now time.Time) (*model.ChequeDueSummary, error) {
+	today := utils.StartOfDay(now)
+	tomorrow := today.AddDate(0, 0, 1)
+	daysToFriday := (int(time.Friday) - int(today.Weekday()) + 7) % 7
+	weekEnd := today.AddDate(0, 0, daysToFriday+1)
+
+	cheques, err := s.chequeRepo.ListCheques(ctx, model.ChequeFilter{
+		BIDID:    bidID,
+		Statuses: outstandingChequeStatuses,
+		DueTo:    &weekEnd,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to load cheques for due summary: %w", err)
+	}
+
+	summary := &model.ChequeDueSummary{Date: utils.FormatJalali(today)}
+	for _, cheque := range cheques {
+		totals := &summary.Received
+		if cheque.Direction == model.ChequeDirectionIssued {
+			totals = &summary.Issued
+		}
+		var bucket *model.ChequeDueBucket
+		switch {
+		case cheque.DueDate.Before(today):
+			bucket = &totals.Overdue
+		case cheque.DueDate.Before(tomorrow):
+			bucket = &totals.DueToday
+		default:
+			bucket = &totals.DueThisWeek
+		}
+		bucket.Count++
+		bucket.Amount = bucket.Amount.Add(cheque.Amount)
+	}
+	return summary, nil
+}
+
+func chequeAlertKind(dueDate, today time.Time) string {
+	switch {
+	case dueDate.Before(today):
+		return model.ChequeAlertOverdue
+	case dueDate.Before(today.AddDate(0, 0, 1)):
+		return model.ChequeAlertDueToday
+	default:
+		return model.ChequeAlertUpcoming
+	}
+}
+
+// chequeAlertRecipient مسئول چک و در نبود او کاربرِ کارمند مسئول طرف حساب؛ هر دو شناسه UUID کاربر هستند.
+func chequeAlertRecipient(cheque *model.Cheque) *string {
+	if cheque.ResponsibleUserRef != nil {
+		return cheque.ResponsibleUserRef
+	}
+	if cheque.Person != nil && cheque.Person.AssignedEmployee != nil && cheque.Person.AssignedEmployee.UserRef != nil {
+		return cheque.Person.AssignedEmployee.UserRef
+	}
+	return nil
+}
+
+func chequeAlertMessage(cheque *model.Cheque, kind string) string {
+	kindLabel := "دریافتی"
+	if cheque.Direction == model.ChequeDirectionIssued {
+		kindLabel = "پرداختی"
+	}
+	personName := ""
+	if cheque.Person != nil {
+		personName = cheque.Person.Name
+	}
+	amount := cheque.Amount.Decimal().StringFixed(0)
+	switch kind {
+	case model.ChequeAlertOverdue:
+		return fmt.Sprintf("سررسید چک %s شماره %s (%s) به مبلغ %s تومان در تاریخ %s گذشته است.", kindLabel, cheque.Serial, personName, amount, cheque.DueDateJalali)
+	case model.ChequeAlertDueToday:
+		return fmt.Sprintf("چک %s شماره %s (%s) به مبلغ %s تومان امروز سررسید می‌شود.", kindLabel, cheque.Serial, personName, amount)
+	default:
+		return fmt.Sprintf("چک %s شماره %s (%s) به مبلغ %s تومان در تاریخ %s سررسید می‌شود.", kindLabel, cheque.Serial, personName, amount, cheque.DueDateJalali)
+	}
+}
@@ -0,0 +1,113 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"common-gold/money"
+
+	"go.uber.org/zap"
+	"gorm.io/gorm"
+
+	"crm-gold/internal/model"
+	"crm-gold/internal/repository/repo"
+)
+
+type fakeChequeRepo struct {
+	repo.ChequeRepo
+	cheques map[uint]model.Cheque
+}
+
+func (r *fakeChequeRepo) GetChequeByID(ctx context.Context, id uint) (*model.Cheque, error) {
+	cheque, ok := r.cheques[id]
+	if !ok {
+		return nil, gorm.ErrRecordNotFound
+	}
+	return &cheque, nil
+}
+
+func newTestChequeService(t *testing.T, cheques ...model.Cheque) ChequeService {
+	t.Helper()
+	chequeRepo := &fakeChequeRepo{cheques: map[uint]model.Cheque{}}
+	for _, c := range cheques {
+		chequeRepo.cheques[c.ID] = c
+	}
+	svc, err := NewChequeService(chequeRepo, struct{ repo.CustRepo }{}, struct{ repo.BankRepo }{}, struct{ repo.LedgerRepo }{}, zap.NewNop())
+	if err != nil {
+		t.Fatalf("NewChequeService: %v", err)
+	}
+	return svc
+}
+
+func TestChequeTransitions(t *testing.T) {
+	received, issued := model.ChequeDirectionReceived, model.ChequeDirectionIssued
+	tests := []struct {
+		direction, from, to string
+		allowed             bool
+	}{
+		{received, model.ChequeStatusInHand, model.ChequeStatusDeposited, true},
+		{received, model.ChequeStatusInHand, model.ChequeStatusEndorsed, true},
+		{received, model.ChequeStatusInHand, model.ChequeStatusReturned, true},
+		{received, model.ChequeStatusDeposited, model.ChequeStatusCleared, true},
+		{received, model.ChequeStatusDeposited, model.ChequeStatusBounced, true},
+		{received, model.ChequeStatusBounced, model.ChequeStatusDeposited, true},
+		{received, model.ChequeStatusBounced, model.ChequeStatusReturned, true},
+		{issued, model.ChequeStatusIssued, model.ChequeStatusCleared, true},
+		{issued, model.ChequeStatusIssued, model.ChequeStatusBounced, true},
+		{issued, model.ChequeStatusIssued, model.ChequeStatusReturned, true},
+		{issued, model.ChequeStatusBounced, model.ChequeStatusCleared, true},
+
+		{received, model.ChequeStatusInHand, model.ChequeStatusCleared, false},
+		{received, model.ChequeStatusInHand, model.ChequeStatusBounced, false},
+		{received, model.ChequeStatusDeposited, model.ChequeStatusEndorsed, false},
+		{received, model.ChequeStatusCleared, model.ChequeStatusBounced, false},
+		{received, model.ChequeStatusEndorsed, model.ChequeStatusDeposited, false},
+		{received, model.ChequeStatusReturned, model.ChequeStatusInHand, false},
+		{received, model.ChequeStatusInHand, model.ChequeStatusIssued, false},
+		{issued, model.ChequeStatusIssued, model.ChequeStatusDeposited, false},
+		{issued, model.ChequeStatusIssued, model.ChequeStatusEndorsed, false},
+		{issued, model.ChequeStatusCleared, model.ChequeStatusBounced, false},
+		{issued, model.ChequeStatusReturned, model.ChequeStatusIssued, false},
+		{issued, model.ChequeStatusBounced, model.ChequeStatusReturned, false},
+		{"unknown", model.ChequeStatusInHand, model.ChequeStatusDeposited, false},
+	}
+	for _, tt := range tests {
+		if got := isAllowedChequeTransition(tt.direction, tt.from, tt.to); got != tt.allowed {
+			t.Errorf("%s cheque %s -> %s: allowed = %v, want %v", tt.direction, tt.from, tt.to, got, tt.allowed)
+		}
+	}
+}
+
+func TestChangeStatusRejectsForbiddenTransition(t *testing.T) {
+	svc := newTestChequeService(t, model.Cheque{
+		Model:     gorm.Model{ID: 7},
+		BIDID:     1,
+		Direction: model.ChequeDirectionReceived,
+		Status:    model.ChequeStatusCleared,
+		Amount:    money.NewAmount(1000),
+	})
+
+	_, err := svc.ChangeStatus(context.Background(), 1, 7, &model.ChequeTransitionRequest{Status: model.ChequeStatusBounced}, "tester")
+	if !errors.Is(err, ErrInvalidTransition) {
+		t.Fatalf("error = %v, want ErrInvalidTransition", err)
+	}
+	if _, err := svc.ChangeStatus(context.Background(), 2, 7, &model.ChequeTransitionRequest{Status: model.ChequeStatusBounced}, "tester"); !errors.Is(err, ErrNotFound) {
+		t.Fatalf("cheque of another business: error = %v, want ErrNotFound", err)
+	}
+}
+
+func TestCreateChequeRequiresBusiness(t *testing.T) {
+	svc := newTestChequeService(t)
+	req := &model.CreateChequeRequest{
+		Serial:   "123456",
+		SayadID:  "1234567890123456",
+		BankName: "ملت",
+		Amount:   money.NewAmount(1000),
+		DueDate:  "1405/08/01",
+		PersonID: 3,
+	}
+	if _, err := svc.CreateCheque(context.Background(), model.ChequeDirectionReceived, req, "tester"); !errors.Is(err, ErrValidation) {
+		t.Fatalf("error = %v, want ErrValidation", err)
+	}
+}
@@ -0,0 +1,171 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"crm-gold/internal/model"
+	"crm-gold/internal/repository/repo"
+	"crm-gold/internal/utils"
+
+	"go.uber.org/zap"
+	"gorm.io/gorm"
+)
+
+// CurrencyConverter نرخ ارز معتبر در یک تاریخ؛ فاکتورها و اسناد ارزی مبلغ تومانی را از آن می‌گیرند.
+type CurrencyConverter interface {
+	RateAt(ctx context.Context, bidID, currencyID uint, at time.Time) (*model.ExchangeRate, error)
+}
+
+type CurrencyService interface {
+	CurrencyConverter
+	ListCurrencies(ctx context.Context) ([]model.Currency, error)
+	SetRate(ctx context.Context, req *model.SetExchangeRateRequest, actor string) (*model.ExchangeRate, error)
+	ListRates(ctx context.Context, filter model.ExchangeRateFilter) ([]model.ExchangeRate, error)
+	Revaluation(ctx context.Context, bidID uint, asOf time.Time) (*model.RevaluationReport, error)
+}
+
+type currencyServiceImpl struct {
+	currencyRepo repo.CurrencyRepo
+	logger       *zap.Logger
+}
+
+func NewCurrencyService(currencyRepo repo.CurrencyRepo, logger *zap.Logger) (CurrencyService, error) {
+	if currencyRepo == nil {
+		return nil, errors.New("currencyRepository cannot be nil for CurrencyService")
+	}
+	if logger == nil {
+		return nil, errors.New("logger cannot be nil for CurrencyService")
+	}
+	return &currencyServiceImpl{currencyRepo: currencyRepo, logger: logger}, nil
+}
+
+func (s *currencyServiceImpl) ListCurrencies(ctx context.Context) ([]model.Currency, error) {
+	currencies, err := s.currencyRepo.ListCurrencies(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list currencies: %w", err)
+	}
+	return currencies, nil
+}
+
+func (s *currencyServiceImpl) SetRate(ctx context.Context, req *model.SetExchangeRateRequest, actor string) (*model.ExchangeRate, error) {
+	if req.BIDID == 0 {
+		return nil, fmt.Errorf("%w: bidId is required", ErrValidation)
+	}
+	if !req.Rate.IsPositive() {
+		return nil, fmt.Errorf("%w: exchange rate must be positive", ErrValidation)
+	}
+	date, err := utils.ParseDateParam(req.Date)
+	if err != nil {
+		return nil, fmt.Errorf("%w: invalid rate date: %v", ErrValidation, err)
+	}
+	date = utils.StartOfDay(date)
+	if _, err := s.currencyRepo.GetCurrencyByID(ctx, req.CurrencyID); err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, fmt.Errorf("%w: currency %d does not exist", ErrValidation, req.CurrencyID)
+		}
+		return nil, fmt.Errorf("failed to load currency: %w", err)
+	}
+	rate := &model.ExchangeRate{
+		BIDID:      req.BIDID,
+		CurrencyID: req.CurrencyID,
+		Date:       date,
+		DateJalali: utils.FormatJalali(date),
+		Rate:       req.Rate,
+		CreatedBy:  actor,
+	}
+	if err := s.currencyRepo.SaveExchangeRate(ctx, rate); err != nil {
+		return nil, fmt.Errorf("failed to save exchange rate: %w", err)
+	}
+	s.logger.Info("Exchange rate saved.", zap.Uint("bid_id", rate.BIDID), zap.Uint("currency_id", rate.CurrencyID),
+		zap.String("date", rate.DateJalali), zap.String("rate", rate.Rate.String()), zap.String("actor", actor))
+	return rate, nil
+}
+
+func (s *currencyServiceImpl) ListRates(ctx context.Context, filter model.ExchangeRateFilter) ([]model.ExchangeRate, error) {
+	if filter.BIDID == 0 {
+		return nil, fmt.Errorf("%w: bidId is required", ErrValidation)
+	}
+	rates, err := s.currencyRepo.ListExchangeRates(ctx, filter)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list exchange rates: %w", err)
+	}
+	return rates, nil
+}
+
+// RateAt نبود نرخ تا تاریخ at خطای اعتبارسنجی است تا سند ارزی بدون نرخ ثبت نشود.
+func (s *currencyServiceImpl) RateAt(ctx context.Context, bidID, currencyID uint, at time.Time) (*model.ExchangeRate, error) {
+	if bidID == 0 {
+		return nil, fmt.Errorf("%w: bidId is required", ErrValidation)
+	}
+	rate, err := s.currencyRepo.ExchangeRateAt(ctx, bidID, currencyID, at)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load exchange rate: %w", err)
+	}
+	if rate == nil {
+		return nil, fmt.Errorf("%w: no exchange rate for currency %d on or before %s", ErrValidation, currencyID, utils.FormatJalali(at))
+	}
+	return rate, nil
+}
+
+// Revaluation مانده‌های ارزی تا پایان روز asOf را با آخرین نرخ همان روز تسعیر می‌کند.
+func (s *currencyServiceImpl) Revaluation(ctx context.Context, bidID uint, asOf time.Time) (*model.RevaluationReport, error) {
+	if bidID == 0 {
+		return nil, fmt.Errorf("%w: bidId is required", ErrValidation)
+	}
+	asOf = utils.StartOfDay(asOf)
+	balances, err := s.currencyRepo.CurrencyBalances(ctx, bidID, asOf.AddDate(0, 0, 1))
+	if err != nil {
+		return nil, fmt.Errorf("failed to load currency balances: %w", err)
+	}
+	currencies, err := s.currencyRepo.ListCurrencies(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list currencies: %w", err)
+	}
+	names := make(map[uint]string, len(currencies))
+	for _, c := range currencies {
+		names[c.ID] = c.Name
+	}
+
+	report := &model.RevaluationReport{AsOf: utils.FormatJalali(asOf), Rows: make([]model.RevaluationRow, 0, len(balances))}
+	rates := map[uint]*model.ExchangeRate{}
+	missing := map[uint]bool{}
+	for _, b := range balances {
+		rate, ok := rates[b.CurrencyID]
+		if !ok {
+			if rate, err = s.currencyRepo.ExchangeRateAt(ctx, bidID, b.CurrencyID, asOf); err != nil {
+				return nil, fmt.Errorf("failed to load exchange rate: %w", err)
+			}
+			rates[b.CurrencyID] = rate
+		}
+		if rate == nil {
+			if !missing[b.CurrencyID] {
+				missing[b.CurrencyID] = true
+				report.MissingRates = append(report.MissingRates, b.CurrencyID)
+			}
+			continue
+		}
+		row := model.RevaluationRow{
+			AccountKind:    b.AccountKind,
+			AccountID:      b.AccountID,
+			CurrencyID:     b.CurrencyID,
+			CurrencyName:   names[b.CurrencyID],
+			ForeignBalance: b.ForeignBalance,
+			BookBalance:    b.BookBalance,
+			Rate:           rate.Rate,
+			RateDate:       rate.DateJalali,
+			RevaluedValue:  b.ForeignBalance.ToToman(rate.Rate),
+		}
+		row.Difference = row.RevaluedValue.Sub(row.BookBalance)
+		if row.Difference.IsPositive() {
+			report.TotalGain = report.TotalGain.Add(row.Difference)
+		} else {
+			report.TotalLoss = report.TotalLoss.Add(row.Difference.Neg())
+		}
+		report.Rows = append(report.Rows, row)
+	}
+	report.NetGainLoss = report.TotalGain.Sub(report.TotalLoss)
+	return report, nil
+}
@@ -4,6 +4,9 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"strconv"
+
+	"common-gold/audit"
 
 	"crm-gold/internal/model"
 	"crm-gold/internal/repository/repo"
@@ -19,20 +22,25 @@ type CusService interface {
 }
 
 type customerServiceImpl struct {
-	customerRepo repo.CustRepo
-	logger       *zap.Logger
+	customerRepo  repo.CustRepo
+	auditRecorder AuditRecorder
+	logger        *zap.Logger
 }
 
-func NewCustomerService(customerRepo repo.CustRepo, logger *zap.Logger) (CusService, error) {
+func NewCustomerService(customerRepo repo.CustRepo, auditRecorder AuditRecorder, logger *zap.Logger) (CusService, error) {
 	if customerRepo == nil {
 		return nil, errors.New("customerRepository cannot be nil for CustomerService")
 	}
+	if auditRecorder == nil {
+		return nil, errors.New("auditRecorder cannot be nil for CustomerService")
+	}
 	if logger == nil {
 		return nil, errors.New("logger cannot be nil for CustomerService")
 	}
 	return &customerServiceImpl{
-		customerRepo: customerRepo,
-		logger:       logger,
+		customerRepo:  customerRepo,
+		auditRecorder: auditRecorder,
+		logger:        logger,
 	}, nil
 }
 
@@ -110,7 +118,7 @@ func (s *customerServiceImpl) CreateCustomer(ctx context.Context, req *model.Cre
 		InitialBalanceGold:  req.InitialBalanceGold,
 		GoldRateType:        utils.PtrString(req.GoldRateType),
 		DefaultGoldUnit:     utils.PtrString(req.DefaultGoldUnit),
-		DefaultGoldUnitRate: utils.PtrFloat64(req.DefaultGoldUnitRate),
+		DefaultGoldUnitRate: &req.DefaultGoldUnitRate,
 		CustomerTypes:       customerTypes,
 	}
 
@@ -121,6 +129,15 @@ func (s *customerServiceImpl) CreateCustomer(ctx context.Context, req *model.Cre
 	}
 
 	s.logger.Info("Customer created successfully in service layer.", zap.Uint("customer_id", createdCustomer.ID))
+	recordAudit(ctx, s.auditRecorder, s.logger, audit.Event{
+		BusinessID:  &createdCustomer.BIDID,
+		Action:      audit.ActionCustomerCreate,
+		Category:    audit.CategoryCustomer,
+		TargetType:  "customer",
+		TargetID:    strconv.FormatUint(uint64(createdCustomer.ID), 10),
+		Description: fmt.Sprintf("ایجاد شخص %s (%s)", createdCustomer.Nikename, createdCustomer.Code),
+		After:       audit.Snapshot(createdCustomer),
+	})
 	return createdCustomer, nil
 }
 
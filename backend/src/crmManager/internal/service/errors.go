@@ -0,0 +1,11 @@
+package service
+
+import "errors"
+
+var (
+	ErrNotFound          = errors.New("record not found")
+	ErrValidation        = errors.New("validation failed")
+	ErrInvalidTransition = errors.New("invalid status transition")
+	ErrConflict          = errors.New("conflict with current state")
+	ErrForbidden         = errors.New("access denied")
+)
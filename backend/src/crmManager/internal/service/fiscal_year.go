@@ -0,0 +1,402 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"crm-gold/internal/model"
+	"crm-gold/internal/repository/db/postgresDb"
+	"crm-gold/internal/repository/repo"
+	"crm-gold/internal/utils"
+
+	"common-gold/money"
+
+	"go.uber.org/zap"
+	"gorm.io/gorm"
+)
+
+type FiscalYearService interface {
+	CreateFiscalYear(ctx context.Context, req *model.CreateFiscalYearRequest, actor string) (*model.FiscalYear, error)
+	ListFiscalYears(ctx context.Context, bidID uint) ([]model.FiscalYear, error)
+	GetFiscalYear(ctx context.Context, id uint) (*model.FiscalYear, error)
+	ListEvents(ctx context.Context, id uint) ([]model.FiscalYearEvent, error)
+	CloseFiscalYear(ctx context.Context, id uint, actor string) (*model.FiscalYear, error)
+	ReopenFiscalYear(ctx context.Context, id uint, req *model.ReopenFiscalYearRequest, actor string) (*model.FiscalYear, error)
+}
+
+type fiscalYearServiceImpl struct {
+	fiscalYearRepo repo.FiscalYearRepo
+	accountRepo    repo.AccountRepo
+	ledgerRepo     repo.LedgerRepo
+	customerRepo   repo.CustRepo
+	logger         *zap.Logger
+}
+
+func NewFiscalYearService(fiscalYearRepo repo.FiscalYearRepo, accountRepo repo.AccountRepo, ledgerRepo repo.LedgerRepo, customerRepo repo.CustRepo, logger *zap.Logger) (FiscalYearService, error) {
+	if fiscalYearRepo == nil {
+		return nil, errors.New("fiscalYearRepository cannot be nil for FiscalYearService")
+	}
+	if accountRepo == nil {
+		return nil, errors.New("accountRepository cannot be nil for FiscalYearService")
+	}
+	if ledgerRepo == nil {
+		return nil, errors.New("ledgerRepository cannot be nil for FiscalYearService")
+	}
+	if customerRepo == nil {
+		return nil, errors.New("customerRepository cannot be nil for FiscalYearService")
+	}
+	if logger == nil {
+		return nil, errors.New("logger cannot be nil for FiscalYearService")
+	}
+	return &fiscalYearServiceImpl{
+		fiscalYearRepo: fiscalYearRepo,
+		accountRepo:    accountRepo,
+		ledgerRepo:     ledgerRepo,
+		customerRepo:   customerRepo,
+		logger:         logger,
+	}, nil
+}
+
+func newFiscalYear(bidID uint, jalaliYear int, actor string) *model.FiscalYear {
+	return &model.FiscalYear{
+		BIDID:      bidID,
+		JalaliYear: jalaliYear,
+		Title:      fmt.Sprintf("سال مالی %d", jalaliYear),
+		StartDate:  utils.JalaliToGregorian(jalaliYear, 1, 1),
+		EndDate:    utils.JalaliToGregorian(jalaliYear, 12, utils.JalaliMonthLength(jalaliYear, 12)),
+		Status:     model.FiscalYearOpen,
+		CreatedBy:  actor,
+	}
+}
+
+// CreateFiscalYear سال‌ها پشت سر هم تعریف می‌شوند؛ در اولین سال، مانده اولیه اشخاص به عنوان سند افتتاحیه ثبت می‌شود.
+func (s *fiscalYearServiceImpl) CreateFiscalYear(ctx context.Context, req *model.CreateFiscalYearRequest, actor string) (*model.FiscalYear, error) {
+	if req.BIDID == 0 {
+		return nil, fmt.Errorf("%w: bidId is required", ErrValidation)
+	}
+	if req.JalaliYear < 1300 || req.JalaliYear > 1700 {
+		return nil, fmt.Errorf("%w: invalid Jalali year %d", ErrValidation, req.JalaliYear)
+	}
+	years, err := s.fiscalYearRepo.ListFiscalYears(ctx, req.BIDID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list fiscal years: %w", err)
+	}
+	if n := len(years); n > 0 && years[n-1].JalaliYear+1 != req.JalaliYear {
+		return nil, fmt.Errorf("%w: the next fiscal year must be %d", ErrValidation, years[n-1].JalaliYear+1)
+	}
+
+	fy := newFiscalYear(req.BIDID, req.JalaliYear, actor)
+	var opening *model.LedgerEntry
+	if len(years) == 0 {
+		if opening, err = s.initialBalancesEntry(ctx, fy, actor); err != nil {
+			return nil, err
+		}
+	}
+	created, err := s.fiscalYearRepo.CreateFiscalYear(ctx, fy, opening)
+	if err != nil {
+		if errors.Is(err, postgresDb.ErrFiscalYearOverlap) || postgresDb.IsUniqueViolation(err) {
+			return nil, fmt.Errorf("%w: fiscal year %d already exists", ErrConflict, req.JalaliYear)
+		}
+		return nil, fmt.Errorf("failed to create fiscal year: %w", err)
+	}
+	s.logger.Info("Fiscal year created.", zap.Uint("fiscal_year_id", created.ID), zap.Int("jalali_year", created.JalaliYear), zap.String("actor", actor))
+	return created, nil
+}
+
+// initialBalancesEntry مانده اولیه مثبت یعنی شخص بدهکار است؛ طرف مقابل حساب تراز افتتاحیه است.
+func (s *fiscalYearServiceImpl) initialBalancesEntry(ctx context.Context, fy *model.FiscalYear, actor string) (*model.LedgerEntry, error) {
+	customers, err := s.customerRepo.ListCustomersWithInitialBalance(ctx, fy.BIDID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load persons: %w", err)
+	}
+	var lines []model.LedgerLine
+	var toman money.Amount
+	var gold money.Weight
+	for _, c := range customers {
+		id := c.ID
+		line := model.LedgerLine{AccountKind: model.LedgerAccountPerson, AccountID: &id, Description: "مانده اولیه"}
+		setLineAmounts(&line, c.InitialBalanceToman, c.InitialBalanceGold)
+		lines = append(lines, line)
+		toman = toman.Add(c.InitialBalanceToman)
+		gold = gold.Add(c.InitialBalanceGold)
+	}
+	if len(lines) == 0 {
+		return nil, nil
+	}
+	offset := model.LedgerLine{AccountKind: model.LedgerAccountOpeningBalance, Description: "مانده اولیه"}
+	setLineAmounts(&offset, toman.Neg(), gold.Neg())
+	lines = append(lines, offset)
+	return &model.LedgerEntry{
+		BIDID:       fy.BIDID,
+		DocType:     model.LedgerDocYearOpening,
+		Reference:   fmt.Sprintf("FY-%d-OPEN", fy.JalaliYear),
+		Description: "سند افتتاحیه " + fy.Title,
+		PostedAt:    fy.StartDate,
+		CreatedBy:   actor,
+		Lines:       lines,
+	}, nil
+}
+
+// setLineAmounts مقدار مثبت در ستون بدهکار و منفی در ستون بستانکار قرار می‌گیرد.
+func setLineAmounts(line *model.LedgerLine, toman money.Amount, gold money.Weight) {
+	if toman.IsPositive() {
+		line.Debit = toman
+	} else {
+		line.Credit = toman.Neg()
+	}
+	if gold.IsPositive() {
+		line.GoldDebit = gold
+	} else {
+		line.GoldCredit = gold.Neg()
+	}
+}
+
+func isZeroBalance(toman money.Amount, gold money.Weight) bool {
+	return toman.IsZero() && gold.IsZero()
+}
+
+func (s *fiscalYearServiceImpl) ListFiscalYears(ctx context.Context, bidID uint) ([]model.FiscalYear, error) {
+	if bidID == 0 {
+		return nil, fmt.Errorf("%w: bidId is required", ErrValidation)
+	}
+	years, err := s.fiscalYearRepo.ListFiscalYears(ctx, bidID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list fiscal years: %w", err)
+	}
+	return years, nil
+}
+
+func (s *fiscalYearServiceImpl) GetFiscalYear(ctx context.Context, id uint) (*model.FiscalYear, error) {
+	fy, err := s.fiscalYearRepo.GetFiscalYearByID(ctx, id)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, fmt.Errorf("%w: fiscal year %d", ErrNotFound, id)
+		}
+		return nil, fmt.Errorf("failed to fetch fiscal year: %w", err)
+	}
+	return fy, nil
+}
+
+func (s *fiscalYearServiceImpl) ListEvents(ctx context.Context, id uint) ([]model.FiscalYearEvent, error) {
+	if _, err := s.GetFiscalYear(ctx, id); err != nil {
+		return nil, err
+	}
+	events, err := s.fiscalYearRepo.ListFiscalYearEvents(ctx, id)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list fiscal year events: %w", err)
+	}
+	return events, nil
+}
+
+// CloseFiscalYear حساب‌های درآمد و هزینه را به سود (زیان) انباشته می‌بندد، حساب‌های دائمی را با سند اختتامیه صفر می‌کند
+// و همان مانده‌ها (اشخاص، صندوق، بانک، طلا و ارز) را به عنوان سند افتتاحیه سال بعد ثبت می‌کند. سال بعد در صورت نبود ساخته می‌شود.
+func (s *fiscalYearServiceImpl) CloseFiscalYear(ctx context.Context, id uint, actor string) (*model.FiscalYear, error) {
+	fy, err := s.GetFiscalYear(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if fy.Status != model.FiscalYearOpen {
+		return nil, fmt.Errorf("%w: %s is already closed", ErrInvalidTransition, fy.Title)
+	}
+	years, err := s.fiscalYearRepo.ListFiscalYears(ctx, fy.BIDID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list fiscal years: %w", err)
+	}
+	for _, y := range years {
+		if y.StartDate.Before(fy.StartDate) && y.Status != model.FiscalYearClosed {
+			return nil, fmt.Errorf("%w: %s must be closed first", ErrInvalidTransition, y.Title)
+		}
+	}
+
+	next, err := s.fiscalYearRepo.GetFiscalYearByJalaliYear(ctx, fy.BIDID, fy.JalaliYear+1)
+	if err != nil {
+		if !errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, fmt.Errorf("failed to fetch next fiscal year: %w", err)
+		}
+		next = newFiscalYear(fy.BIDID, fy.JalaliYear+1, actor)
+	} else if next.Status != model.FiscalYearOpen {
+		return nil, fmt.Errorf("%w: %s is closed", ErrInvalidTransition, next.Title)
+	}
+
+	if err := s.accountRepo.EnsureDefaultChart(ctx, fy.BIDID); err != nil {
+		return nil, fmt.Errorf("failed to prepare chart of accounts: %w", err)
+	}
+	accounts, err := s.accountRepo.ListAccounts(ctx, fy.BIDID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list accounts: %w", err)
+	}
+	categories := make(map[string]string, len(accounts))
+	for _, a := range accounts {
+		categories[a.Code] = a.Category
+	}
+
+	rows, lastLineID, err := s.fiscalYearRepo.YearEndBalances(ctx, fy.BIDID, fy.EndDate.AddDate(0, 0, 1))
+	if err != nil {
+		return nil, fmt.Errorf("failed to compute year end balances: %w", err)
+	}
+
+	var profitLines, closingLines, openingLines []model.LedgerLine
+	var profitToman money.Amount
+	var profitGold money.Weight
+	for _, row := range rows {
+		if isZeroBalance(row.Balance, row.GoldBalance) {
+			continue
+		}
+		switch categories[row.AccountCode] {
+		case model.AccountCategoryRevenue, model.AccountCategoryExpense:
+			profitLines = append(profitLines, balanceLine(row, -1, "بستن حساب‌های موقت"))
+			profitToman = profitToman.Sub(row.Balance)
+			profitGold = profitGold.Sub(row.GoldBalance)
+		default:
+			closingLines = append(closingLines, balanceLine(row, -1, "سند اختتامیه"))
+			openingLines = append(openingLines, balanceLine(row, 1, "انتقال مانده از "+fy.Title))
+		}
+	}
+
+	// سود خالص (مانده بستانکار) به حساب سود (زیان) انباشته منتقل و همراه سایر حساب‌های دائمی به سال بعد برده می‌شود.
+	var profitClosing, closing, nextOpening *model.LedgerEntry
+	if len(profitLines) > 0 {
+		retained := model.AccountBalanceRow{AccountCode: model.AccountCodeRetainedEarnings, AccountKind: model.LedgerAccountGeneral, Balance: profitToman.Neg(), GoldBalance: profitGold.Neg()}
+		if !isZeroBalance(profitToman, profitGold) {
+			profitLines = append(profitLines, balanceLine(retained, 1, "انتقال سود (زیان) "+fy.Title))
+			closingLines = mergeRetainedEarnings(closingLines, retained, -1, "سند اختتامیه")
+			openingLines = mergeRetainedEarnings(openingLines, retained, 1, "انتقال مانده از "+fy.Title)
+		}
+		profitClosing = s.yearEndEntry(fy, model.LedgerDocProfitClosing, "PL", "بستن حساب‌های موقت "+fy.Title, fy.EndDate, actor, profitLines)
+	}
+	if len(closingLines) > 0 {
+		closing = s.yearEndEntry(fy, model.LedgerDocYearClosing, "CLOSE", "سند اختتامیه "+fy.Title, fy.EndDate, actor, closingLines)
+		nextOpening = s.yearEndEntry(next, model.LedgerDocYearOpening, "OPEN", "سند افتتاحیه "+next.Title, next.StartDate, actor, openingLines)
+	}
+	for _, entry := range []*model.LedgerEntry{profitClosing, closing, nextOpening} {
+		if entry != nil && !entry.IsBalanced() {
+			return nil, fmt.Errorf("%w: ledger of %s is not balanced, year end entries cannot be posted", ErrValidation, fy.Title)
+		}
+	}
+
+	now := time.Now()
+	fy.ClosedAt = &now
+	fy.ClosedBy = actor
+	if err := s.fiscalYearRepo.CloseFiscalYear(ctx, fy, next, lastLineID, profitClosing, closing, nextOpening); err != nil {
+		switch {
+		case errors.Is(err, postgresDb.ErrStaleFiscalYear), errors.Is(err, postgresDb.ErrNextFiscalYearOpen),
+			errors.Is(err, postgresDb.ErrFiscalYearOverlap):
+			return nil, fmt.Errorf("%w: %v", ErrConflict, err)
+		}
+		return nil, fmt.Errorf("failed to close fiscal year: %w", err)
+	}
+	fy.Status = model.FiscalYearClosed
+	s.logger.Info("Fiscal year closed.", zap.Uint("fiscal_year_id", fy.ID), zap.Uint("next_fiscal_year_id", next.ID), zap.String("actor", actor))
+	return fy, nil
+}
+
+// balanceLine سطری با مانده row ضرب در sign؛ sign منفی مانده را صفر می‌کند و مثبت آن را دوباره برقرار می‌کند.
+func balanceLine(row model.AccountBalanceRow, sign int, description string) model.LedgerLine {
+	line := model.LedgerLine{
+		AccountKind:    row.AccountKind,
+		AccountID:      row.AccountID,
+		AccountCode:    row.AccountCode,
+		CurrencyID:     row.CurrencyID,
+		CurrencyAmount: row.CurrencyBalance.Abs(),
+		Description:    description,
+	}
+	toman, gold := row.Balance, row.GoldBalance
+	if sign < 0 {
+		toman, gold = toman.Neg(), gold.Neg()
+	}
+	setLineAmounts(&line, toman, gold)
+	return line
+}
+
+// mergeRetainedEarnings سود سال را با مانده قبلی حساب سود (زیان) انباشته در یک سطر جمع می‌کند.
+func mergeRetainedEarnings(lines []model.LedgerLine, retained model.AccountBalanceRow, sign int, description string) []model.LedgerLine {
+	for i, l := range lines {
+		if l.AccountCode != retained.AccountCode || l.AccountID != nil || l.CurrencyID != nil {
+			continue
+		}
+		add := balanceLine(retained, sign, description)
+		toman := l.Debit.Sub(l.Credit).Add(add.Debit).Sub(add.Credit)
+		gold := l.GoldDebit.Sub(l.GoldCredit).Add(add.GoldDebit).Sub(add.GoldCredit)
+		if isZeroBalance(toman, gold) {
+			return append(lines[:i], lines[i+1:]...)
+		}
+		lines[i].Debit, lines[i].Credit, lines[i].GoldDebit, lines[i].GoldCredit = money.Amount{}, money.Amount{}, money.Weight{}, money.Weight{}
+		setLineAmounts(&lines[i], toman, gold)
+		return lines
+	}
+	return append(lines, balanceLine(retained, sign, description))
+}
+
+func (s *fiscalYearServiceImpl) yearEndEntry(fy *model.FiscalYear, docType, suffix, description string, at time.Time, actor string, lines []model.LedgerLine) *model.LedgerEntry {
+	return &model.LedgerEntry{
+		BIDID:       fy.BIDID,
+		DocType:     docType,
+		DocID:       fy.ID,
+		Reference:   fmt.Sprintf("FY-%d-%s", fy.JalaliYear, suffix),
+		Description: description,
+		PostedAt:    at,
+		CreatedBy:   actor,
+		Lines:       lines,
+	}
+}
+
+// ReopenFiscalYear فقط آخرین سال بسته‌شده قابل بازگشایی است؛ اسناد پایان سال و افتتاحیه سال بعد با سند معکوس خنثی می‌شوند.
+func (s *fiscalYearServiceImpl) ReopenFiscalYear(ctx context.Context, id uint, req *model.ReopenFiscalYearRequest, actor string) (*model.FiscalYear, error) {
+	reason := strings.TrimSpace(req.Reason)
+	if reason == "" {
+		return nil, fmt.Errorf("%w: reopen reason is required", ErrValidation)
+	}
+	fy, err := s.GetFiscalYear(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if fy.Status != model.FiscalYearClosed {
+		return nil, fmt.Errorf("%w: %s is not closed", ErrInvalidTransition, fy.Title)
+	}
+	next, err := s.fiscalYearRepo.GetFiscalYearByJalaliYear(ctx, fy.BIDID, fy.JalaliYear+1)
+	if err != nil {
+		if !errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, fmt.Errorf("failed to fetch next fiscal year: %w", err)
+		}
+		next = nil
+	} else if next.Status != model.FiscalYearOpen {
+		return nil, fmt.Errorf("%w: %s must be reopened first", ErrInvalidTransition, next.Title)
+	}
+
+	var reversals []*model.LedgerEntry
+	reverse := func(entryID *uint, at time.Time) error {
+		if entryID == nil {
+			return nil
+		}
+		entry, err := s.ledgerRepo.GetEntryByID(ctx, *entryID)
+		if err != nil {
+			return fmt.Errorf("failed to fetch year end entry %d: %w", *entryID, err)
+		}
+		reversals = append(reversals, entry.Reversal(at, actor, reason))
+		return nil
+	}
+	if next != nil {
+		if err := reverse(next.OpeningEntryID, next.StartDate); err != nil {
+			return nil, err
+		}
+	}
+	if err := reverse(fy.ClosingEntryID, fy.EndDate); err != nil {
+		return nil, err
+	}
+	if err := reverse(fy.ProfitClosingEntryID, fy.EndDate); err != nil {
+		return nil, err
+	}
+
+	if err := s.fiscalYearRepo.ReopenFiscalYear(ctx, fy, next, reversals, actor, reason); err != nil {
+		switch {
+		case errors.Is(err, postgresDb.ErrStaleFiscalYear), errors.Is(err, postgresDb.ErrNextFiscalYearOpen):
+			return nil, fmt.Errorf("%w: %v", ErrConflict, err)
+		}
+		return nil, fmt.Errorf("failed to reopen fiscal year: %w", err)
+	}
+	s.logger.Warn("Fiscal year reopened.", zap.Uint("fiscal_year_id", fy.ID), zap.String("actor", actor), zap.String("reason", reason))
+	return s.GetFiscalYear(ctx, id)
+}
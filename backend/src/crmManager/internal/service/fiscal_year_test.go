@@ -0,0 +1,202 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"common-gold/money"
+
+	"go.uber.org/zap"
+	"gorm.io/gorm"
+
+	"crm-gold/internal/model"
+	"crm-gold/internal/repository/db/postgresDb"
+	"crm-gold/internal/repository/repo"
+)
+
+type fakeFiscalYearRepo struct {
+	repo.FiscalYearRepo
+	years    []model.FiscalYear
+	rows     []model.AccountBalanceRow
+	closeErr error
+
+	closedNext                          *model.FiscalYear
+	profitClosing, closing, nextOpening *model.LedgerEntry
+}
+
+func (r *fakeFiscalYearRepo) GetFiscalYearByID(ctx context.Context, id uint) (*model.FiscalYear, error) {
+	for _, y := range r.years {
+		if y.ID == id {
+			return &y, nil
+		}
+	}
+	return nil, gorm.ErrRecordNotFound
+}
+
+func (r *fakeFiscalYearRepo) GetFiscalYearByJalaliYear(ctx context.Context, bidID uint, jalaliYear int) (*model.FiscalYear, error) {
+	for _, y := range r.years {
+		if y.BIDID == bidID && y.JalaliYear == jalaliYear {
+			return &y, nil
+		}
+	}
+	return nil, gorm.ErrRecordNotFound
+}
+
+func (r *fakeFiscalYearRepo) ListFiscalYears(ctx context.Context, bidID uint) ([]model.FiscalYear, error) {
+	return r.years, nil
+}
+
+func (r *fakeFiscalYearRepo) YearEndBalances(ctx context.Context, bidID uint, before time.Time) ([]model.AccountBalanceRow, uint, error) {
+	return r.rows, 99, nil
+}
+
+func (r *fakeFiscalYearRepo) CloseFiscalYear(ctx context.Context, fy, next *model.FiscalYear, lastLineID uint, profitClosing, closing, nextOpening *model.LedgerEntry) error {
+	if r.closeErr != nil {
+		return r.closeErr
+	}
+	r.closedNext, r.profitClosing, r.closing, r.nextOpening = next, profitClosing, closing, nextOpening
+	return nil
+}
+
+type fakeAccountRepo struct {
+	repo.AccountRepo
+	accounts []model.Account
+}
+
+func (r *fakeAccountRepo) EnsureDefaultChart(ctx context.Context, bidID uint) error { return nil }
+
+func (r *fakeAccountRepo) ListAccounts(ctx context.Context, bidID uint) ([]model.Account, error) {
+	return r.accounts, nil
+}
+
+const (
+	testAccountReceivable = "1103"
+	testBID               = 7
+)
+
+func testChart() []model.Account {
+	return []model.Account{
+		{Code: testAccountReceivable, Category: model.AccountCategoryAsset},
+		{Code: model.AccountCodeRetainedEarnings, Category: model.AccountCategoryEquity},
+		{Code: model.AccountCodeSales, Category: model.AccountCategoryRevenue},
+		{Code: model.AccountCodeCOGS, Category: model.AccountCategoryExpense},
+	}
+}
+
+func openYear(id uint, jalaliYear int) model.FiscalYear {
+	fy := newFiscalYear(testBID, jalaliYear, "admin")
+	fy.ID = id
+	return *fy
+}
+
+// yearRows دفتر متوازن: فروش ۱۰۰۰ تومان و ۲ گرم، بهای تمام‌شده ۴۰۰ تومان و نیم گرم، طلب از مشتری
+// و ۱۰۰ تومان سود انباشته سال‌های قبل.
+func yearRows() []model.AccountBalanceRow {
+	customer := uint(9)
+	return []model.AccountBalanceRow{
+		{AccountCode: model.AccountCodeSales, AccountKind: model.LedgerAccountGeneral, Balance: money.NewAmount(-1000), GoldBalance: money.NewWeight(-2)},
+		{AccountCode: model.AccountCodeCOGS, AccountKind: model.LedgerAccountGeneral, Balance: money.NewAmount(400), GoldBalance: money.NewWeight(0.5)},
+		{AccountCode: testAccountReceivable, AccountKind: model.LedgerAccountPerson, AccountID: &customer, Balance: money.NewAmount(700), GoldBalance: money.NewWeight(1.5)},
+		{AccountCode: model.AccountCodeRetainedEarnings, AccountKind: model.LedgerAccountGeneral, Balance: money.NewAmount(-100)},
+	}
+}
+
+func newTestFiscalYearService(t *testing.T, fyRepo *fakeFiscalYearRepo) FiscalYearService {
+	t.Helper()
+	svc, err := NewFiscalYearService(fyRepo, &fakeAccountRepo{accounts: testChart()}, struct{ repo.LedgerRepo }{}, struct{ repo.CustRepo }{}, zap.NewNop())
+	if err != nil {
+		t.Fatal(err)
+	}
+	return svc
+}
+
+func findLine(t *testing.T, entry *model.LedgerEntry, code string) model.LedgerLine {
+	t.Helper()
+	for _, l := range entry.Lines {
+		if l.AccountCode == code {
+			return l
+		}
+	}
+	t.Fatalf("entry %s has no line for account %s", entry.Reference, code)
+	return model.LedgerLine{}
+}
+
+func TestCloseFiscalYear(t *testing.T) {
+	fyRepo := &fakeFiscalYearRepo{years: []model.FiscalYear{openYear(1, 1402)}, rows: yearRows()}
+	fy, err := newTestFiscalYearService(t, fyRepo).CloseFiscalYear(context.Background(), 1, "admin")
+	if err != nil {
+		t.Fatalf("CloseFiscalYear: %v", err)
+	}
+	if fy.Status != model.FiscalYearClosed || fy.ClosedBy != "admin" {
+		t.Errorf("year status %s closed by %q, want closed by admin", fy.Status, fy.ClosedBy)
+	}
+	if fyRepo.closedNext == nil || fyRepo.closedNext.JalaliYear != 1403 {
+		t.Fatalf("next year = %+v, want a new 1403", fyRepo.closedNext)
+	}
+	for _, entry := range []*model.LedgerEntry{fyRepo.profitClosing, fyRepo.closing, fyRepo.nextOpening} {
+		if entry == nil || !entry.IsBalanced() {
+			t.Fatalf("year end entry %+v is missing or unbalanced", entry)
+		}
+	}
+
+	// سود ۶۰۰ تومان و ۱.۵ گرم به سود انباشته منتقل می‌شود.
+	retained := findLine(t, fyRepo.profitClosing, model.AccountCodeRetainedEarnings)
+	if retained.Credit.Cmp(money.NewAmount(600)) != 0 || retained.GoldCredit.Cmp(money.NewWeight(1.5)) != 0 {
+		t.Errorf("profit transfer credit %s / %s g, want 600 / 1.5 g", retained.Credit, retained.GoldCredit)
+	}
+	// سود سال با مانده قبلی در یک سطر جمع و سود انباشته ۷۰۰ تومانی به سال بعد برده می‌شود.
+	if n := len(fyRepo.closing.Lines); n != 2 {
+		t.Errorf("closing entry has %d lines, want 2", n)
+	}
+	opening := findLine(t, fyRepo.nextOpening, model.AccountCodeRetainedEarnings)
+	if opening.Credit.Cmp(money.NewAmount(700)) != 0 || opening.GoldCredit.Cmp(money.NewWeight(1.5)) != 0 {
+		t.Errorf("opening retained earnings %s / %s g, want credit 700 / 1.5 g", opening.Credit, opening.GoldCredit)
+	}
+	customer := findLine(t, fyRepo.nextOpening, testAccountReceivable)
+	if customer.Debit.Cmp(money.NewAmount(700)) != 0 || customer.GoldDebit.Cmp(money.NewWeight(1.5)) != 0 {
+		t.Errorf("opening receivable %s / %s g, want debit 700 / 1.5 g", customer.Debit, customer.GoldDebit)
+	}
+	for _, l := range fyRepo.nextOpening.Lines {
+		if l.AccountCode == model.AccountCodeSales || l.AccountCode == model.AccountCodeCOGS {
+			t.Errorf("temporary account %s carried into the next year", l.AccountCode)
+		}
+	}
+}
+
+func TestCloseFiscalYearRejects(t *testing.T) {
+	closed := openYear(1, 1402)
+	closed.Status = model.FiscalYearClosed
+	unbalanced := yearRows()
+	unbalanced[2].Balance = money.NewAmount(699.99)
+	tests := []struct {
+		name    string
+		repo    *fakeFiscalYearRepo
+		wantErr error
+	}{
+		{"already closed", &fakeFiscalYearRepo{years: []model.FiscalYear{closed}}, ErrInvalidTransition},
+		{"earlier year open", &fakeFiscalYearRepo{years: []model.FiscalYear{openYear(1, 1402), openYear(2, 1401)}, rows: yearRows()}, ErrInvalidTransition},
+		{"unbalanced ledger", &fakeFiscalYearRepo{years: []model.FiscalYear{openYear(1, 1402)}, rows: unbalanced}, ErrValidation},
+		{"concurrent change", &fakeFiscalYearRepo{years: []model.FiscalYear{openYear(1, 1402)}, rows: yearRows(), closeErr: postgresDb.ErrStaleFiscalYear}, ErrConflict},
+		{"missing year", &fakeFiscalYearRepo{}, ErrNotFound},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := newTestFiscalYearService(t, tt.repo).CloseFiscalYear(context.Background(), 1, "admin")
+			if !errors.Is(err, tt.wantErr) {
+				t.Errorf("err = %v, want %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestMergeRetainedEarningsDropsZeroLine(t *testing.T) {
+	// زیان انباشته ۶۰۰ تومانی با سود ۶۰۰ تومانی سال صفر می‌شود و سطری برای آن نمی‌ماند.
+	previous := model.AccountBalanceRow{AccountCode: model.AccountCodeRetainedEarnings, AccountKind: model.LedgerAccountGeneral, Balance: money.NewAmount(600)}
+	lines := []model.LedgerLine{balanceLine(previous, 1, "")}
+	profit := model.AccountBalanceRow{AccountCode: model.AccountCodeRetainedEarnings, AccountKind: model.LedgerAccountGeneral, Balance: money.NewAmount(-600)}
+	if got := mergeRetainedEarnings(lines, profit, 1, ""); len(got) != 0 {
+		t.Errorf("merged lines = %+v, want none", got)
+	}
+}
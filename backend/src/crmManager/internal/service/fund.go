@@ -0,0 +1,447 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"crm-gold/internal/model"
+	"crm-gold/internal/repository/db/postgresDb"
+	"crm-gold/internal/repository/repo"
+	"crm-gold/internal/utils"
+
+	"common-gold/money"
+
+	"go.uber.org/zap"
+	"gorm.io/gorm"
+)
+
+type FundService interface {
+	CreateFund(ctx context.Context, req *model.CreateFundRequest, actor string) (*model.Fund, error)
+	GetFund(ctx context.Context, id uint) (*model.Fund, error)
+	ListFunds(ctx context.Context, bidID uint) ([]model.Fund, error)
+	CreateVoucher(ctx context.Context, fundID uint, req *model.CashVoucherRequest, actor string) (*model.CashVoucher, error)
+	UpdateVoucher(ctx context.Context, voucherID uint, req *model.CashVoucherRequest, actor string) (*model.CashVoucher, error)
+	DeleteVoucher(ctx context.Context, voucherID uint, actor string) error
+	ListVouchers(ctx context.Context, fundID uint, from, to *time.Time) ([]model.CashVoucher, error)
+	GetStatement(ctx context.Context, fundID uint, currencyID *uint, from, to time.Time) (*model.AccountStatement, error)
+	CloseDay(ctx context.Context, fundID uint, req *model.CloseFundDayRequest, actor string) (*model.FundDayClose, error)
+}
+
+type fundServiceImpl struct {
+	fundRepo     repo.FundRepo
+	customerRepo repo.CustRepo
+	bankRepo     repo.BankRepo
+	ledgerRepo   repo.LedgerRepo
+	converter    CurrencyConverter
+	logger       *zap.Logger
+}
+
+func NewFundService(fundRepo repo.FundRepo, customerRepo repo.CustRepo, bankRepo repo.BankRepo, ledgerRepo repo.LedgerRepo, converter CurrencyConverter, logger *zap.Logger) (FundService, error) {
+	if fundRepo == nil {
+		return nil, errors.New("fundRepository cannot be nil for FundService")
+	}
+	if customerRepo == nil {
+		return nil, errors.New("customerRepository cannot be nil for FundService")
+	}
+	if bankRepo == nil {
+		return nil, errors.New("bankRepository cannot be nil for FundService")
+	}
+	if ledgerRepo == nil {
+		return nil, errors.New("ledgerRepository cannot be nil for FundService")
+	}
+	if converter == nil {
+		return nil, errors.New("currency converter cannot be nil for FundService")
+	}
+	if logger == nil {
+		return nil, errors.New("logger cannot be nil for FundService")
+	}
+	return &fundServiceImpl{
+		fundRepo:     fundRepo,
+		customerRepo: customerRepo,
+		bankRepo:     bankRepo,
+		ledgerRepo:   ledgerRepo,
+		converter:    converter,
+		logger:       logger,
+	}, nil
+}
+
+func (s *fundServiceImpl) CreateFund(ctx context.Context, req *model.CreateFundRequest, actor string) (*model.Fund, error) {
+	if req.BIDID == 0 {
+		return nil, fmt.Errorf("%w: bidId is required", ErrValidation)
+	}
+	if strings.TrimSpace(req.Code) == "" || strings.TrimSpace(req.Name) == "" {
+		return nil, fmt.Errorf("%w: fund code and name are required", ErrValidation)
+	}
+	openingDate := utils.StartOfDay(time.Now())
+	if req.OpeningDate != "" {
+		d, err := utils.ParseDateParam(req.OpeningDate)
+		if err != nil {
+			return nil, fmt.Errorf("%w: invalid opening date: %v", ErrValidation, err)
+		}
+		openingDate = d
+	}
+
+	fund := &model.Fund{
+		BIDID:       req.BIDID,
+		BranchID:    req.BranchID,
+		Code:        strings.TrimSpace(req.Code),
+		Name:        strings.TrimSpace(req.Name),
+		Description: utils.PtrString(req.Description),
+		IsActive:    true,
+		OpeningDate: openingDate,
+	}
+
+	seenCurrency := map[uint]bool{}
+	var entries []*model.LedgerEntry
+	for _, ob := range req.OpeningBalances {
+		if ob.Amount.IsNegative() {
+			return nil, fmt.Errorf("%w: opening balance cannot be negative", ErrValidation)
+		}
+		key := uint(0)
+		if ob.CurrencyID != nil {
+			key = *ob.CurrencyID
+		}
+		if seenCurrency[key] {
+			return nil, fmt.Errorf("%w: duplicate opening balance for the same currency", ErrValidation)
+		}
+		seenCurrency[key] = true
+
+		toman := money.AmountFromDecimal(ob.Amount.Decimal())
+		if ob.CurrencyID != nil {
+			if !ob.AmountInToman.IsPositive() && ob.Amount.IsPositive() {
+				return nil, fmt.Errorf("%w: amountInToman is required for foreign currency opening balances", ErrValidation)
+			}
+			toman = ob.AmountInToman
+		}
+		fund.OpeningBalances = append(fund.OpeningBalances, model.FundOpeningBalance{
+			CurrencyID:    ob.CurrencyID,
+			Amount:        ob.Amount,
+			AmountInToman: toman,
+		})
+		if ob.Amount.IsZero() {
+			entries = append(entries, nil)
+			continue
+		}
+		fundLine := model.LedgerLine{AccountKind: model.LedgerAccountFund, AccountID: &fund.ID, Debit: toman}
+		if ob.CurrencyID != nil {
+			fundLine.CurrencyID = ob.CurrencyID
+			fundLine.CurrencyAmount = ob.Amount
+		}
+		entries = append(entries, &model.LedgerEntry{
+			BIDID:       req.BIDID,
+			DocType:     model.LedgerDocFundOpening,
+			Reference:   fmt.Sprintf("FUND-%s-OPEN", fund.Code),
+			Description: fmt.Sprintf("موجودی اول دوره صندوق %s", fund.Name),
+			PostedAt:    openingDate,
+			CreatedBy:   actor,
+			Lines: []model.LedgerLine{
+				fundLine,
+				{AccountKind: model.LedgerAccountOpeningBalance, Credit: fundLine.Debit},
+			},
+		})
+	}
+
+	created, err := s.fundRepo.CreateFund(ctx, fund, entries)
+	if err != nil {
+		if postgresDb.IsUniqueViolation(err) {
+			return nil, fmt.Errorf("%w: fund with this code already exists", ErrConflict)
+		}
+		return nil, fmt.Errorf("failed to save fund: %w", err)
+	}
+	s.logger.Info("Fund created.", zap.Uint("fund_id", created.ID), zap.String("code", created.Code))
+	return created, nil
+}
+
+func (s *fundServiceImpl) GetFund(ctx context.Context, id uint) (*model.Fund, error) {
+	fund, err := s.fundRepo.GetFundByID(ctx, id)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, fmt.Errorf("%w: fund %d", ErrNotFound, id)
+		}
+		return nil, fmt.Errorf("failed to fetch fund: %w", err)
+	}
+	return fund, nil
+}
+
+func (s *fundServiceImpl) ListFunds(ctx context.Context, bidID uint) ([]model.Fund, error) {
+	funds, err := s.fundRepo.ListFunds(ctx, bidID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list funds: %w", err)
+	}
+	return funds, nil
+}
+
+func (s *fundServiceImpl) CreateVoucher(ctx context.Context, fundID uint, req *model.CashVoucherRequest, actor string) (*model.CashVoucher, error) {
+	fund, err := s.GetFund(ctx, fundID)
+	if err != nil {
+		return nil, err
+	}
+	if !fund.IsActive {
+		return nil, fmt.Errorf("%w: fund %d is inactive", ErrValidation, fundID)
+	}
+	voucher := &model.CashVoucher{BIDID: fund.BIDID, FundID: fund.ID, CreatedBy: actor}
+	if err := s.applyVoucherRequest(ctx, fund, voucher, req); err != nil {
+		return nil, err
+	}
+	code, err := utils.GenerateSecureRandomString(8)
+	if err != nil {
+		return nil, fmt.Errorf("failed to generate voucher number: %w", err)
+	}
+	prefix := "RCV"
+	if voucher.Type == model.CashVoucherPayment {
+		prefix = "PAY"
+	}
+	voucher.Number = fmt.Sprintf("%s-%s", prefix, code)
+
+	created, err := s.fundRepo.CreateCashVoucher(ctx, voucher, voucherEntry(voucher, actor))
+	if err != nil {
+		return nil, mapFundRepoError(err, "failed to save cash voucher")
+	}
+	s.logger.Info("Cash voucher created.", zap.Uint("voucher_id", created.ID), zap.String("type", created.Type), zap.String("amount", created.Amount.String()))
+	return created, nil
+}
+
+func (s *fundServiceImpl) UpdateVoucher(ctx context.Context, voucherID uint, req *model.CashVoucherRequest, actor string) (*model.CashVoucher, error) {
+	voucher, err := s.getVoucher(ctx, voucherID)
+	if err != nil {
+		return nil, err
+	}
+	fund, err := s.GetFund(ctx, voucher.FundID)
+	if err != nil {
+		return nil, err
+	}
+	reversal, err := s.voucherReversal(ctx, voucher, actor, "اصلاح سند صندوق")
+	if err != nil {
+		return nil, err
+	}
+	if err := s.applyVoucherRequest(ctx, fund, voucher, req); err != nil {
+		return nil, err
+	}
+	if err := s.fundRepo.UpdateCashVoucher(ctx, voucher, reversal, voucherEntry(voucher, actor)); err != nil {
+		return nil, mapFundRepoError(err, "failed to update cash voucher")
+	}
+	s.logger.Info("Cash voucher updated.", zap.Uint("voucher_id", voucher.ID), zap.String("actor", actor))
+	return voucher, nil
+}
+
+func (s *fundServiceImpl) DeleteVoucher(ctx context.Context, voucherID uint, actor string) error {
+	voucher, err := s.getVoucher(ctx, voucherID)
+	if err != nil {
+		return err
+	}
+	reversal, err := s.voucherReversal(ctx, voucher, actor, "ابطال سند صندوق")
+	if err != nil {
+		return err
+	}
+	if err := s.fundRepo.DeleteCashVoucher(ctx, voucher, reversal); err != nil {
+		return mapFundRepoError(err, "failed to delete cash voucher")
+	}
+	s.logger.Info("Cash voucher deleted.", zap.Uint("voucher_id", voucher.ID), zap.String("actor", actor))
+	return nil
+}
+
+func (s *fundServiceImpl) ListVouchers(ctx context.Context, fundID uint, from, to *time.Time) ([]model.CashVoucher, error) {
+	if _, err := s.GetFund(ctx, fundID); err != nil {
+		return nil, err
+	}
+	vouchers, err := s.fundRepo.ListCashVouchers(ctx, fundID, from, to)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list cash vouchers: %w", err)
+	}
+	return vouchers, nil
+}
+
+func (s *fundServiceImpl) GetStatement(ctx context.Context, fundID uint, currencyID *uint, from, to time.Time) (*model.AccountStatement, error) {
+	if _, err := s.GetFund(ctx, fundID); err != nil {
+		return nil, err
+	}
+	return buildAccountStatement(ctx, s.ledgerRepo, model.LedgerAccountFund, fundID, currencyID, from, to)
+}
+
+func (s *fundServiceImpl) CloseDay(ctx context.Context, fundID uint, req *model.CloseFundDayRequest, actor string) (*model.FundDayClose, error) {
+	fund, err := s.GetFund(ctx, fundID)
+	if err != nil {
+		return nil, err
+	}
+	day, err := utils.ParseDateParam(req.Date)
+	if err != nil {
+		return nil, fmt.Errorf("%w: invalid close date: %v", ErrValidation, err)
+	}
+	day = utils.StartOfDay(day)
+	if day.After(utils.StartOfDay(time.Now())) {
+		return nil, fmt.Errorf("%w: cannot close a future day", ErrValidation)
+	}
+	balance, err := s.ledgerRepo.GetAccountBalance(ctx, model.LedgerAccountFund, fund.ID, nil, day.AddDate(0, 0, 1))
+	if err != nil {
+		return nil, fmt.Errorf("failed to compute fund balance: %w", err)
+	}
+	dayClose := &model.FundDayClose{
+		FundID:     fund.ID,
+		Date:       day,
+		DateJalali: utils.FormatJalali(day),
+		Balance:    balance,
+		ClosedBy:   actor,
+	}
+	if err := s.fundRepo.CloseFundDay(ctx, fund, dayClose); err != nil {
+		return nil, mapFundRepoError(err, "failed to close fund day")
+	}
+	s.logger.Info("Fund day closed.", zap.Uint("fund_id", fund.ID), zap.String("date", dayClose.DateJalali), zap.String("balance", balance.String()))
+	return dayClose, nil
+}
+
+func (s *fundServiceImpl) getVoucher(ctx context.Context, id uint) (*model.CashVoucher, error) {
+	voucher, err := s.fundRepo.GetCashVoucherByID(ctx, id)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, fmt.Errorf("%w: cash voucher %d", ErrNotFound, id)
+		}
+		return nil, fmt.Errorf("failed to fetch cash voucher: %w", err)
+	}
+	return voucher, nil
+}
+
+func (s *fundServiceImpl) voucherReversal(ctx context.Context, voucher *model.CashVoucher, actor, reason string) (*model.LedgerEntry, error) {
+	if voucher.LedgerEntryID == nil {
+		return nil, fmt.Errorf("cash voucher %d has no posted ledger entry", voucher.ID)
+	}
+	original, err := s.ledgerRepo.GetEntryByID(ctx, *voucher.LedgerEntryID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load voucher ledger entry: %w", err)
+	}
+	return original.Reversal(original.PostedAt, actor, reason), nil
+}
+
+func (s *fundServiceImpl) applyVoucherRequest(ctx context.Context, fund *model.Fund, voucher *model.CashVoucher, req *model.CashVoucherRequest) error {
+	if req.Type != model.CashVoucherReceipt && req.Type != model.CashVoucherPayment {
+		return fmt.Errorf("%w: voucher type must be receipt or payment", ErrValidation)
+	}
+	date, err := utils.ParseDateParam(req.Date)
+	if err != nil {
+		return fmt.Errorf("%w: invalid voucher date: %v", ErrValidation, err)
+	}
+	date = utils.StartOfDay(date)
+	if req.CurrencyID != nil {
+		if !req.CurrencyAmount.IsPositive() {
+			return fmt.Errorf("%w: currencyAmount is required for foreign currency vouchers", ErrValidation)
+		}
+		if req.Amount.IsZero() {
+			rate, err := s.converter.RateAt(ctx, fund.BIDID, *req.CurrencyID, date)
+			if err != nil {
+				return err
+			}
+			req.Amount = req.CurrencyAmount.ToToman(rate.Rate)
+		}
+	} else if !req.CurrencyAmount.IsZero() {
+		return fmt.Errorf("%w: currencyAmount requires currencyId", ErrValidation)
+	}
+	if !req.Amount.IsPositive() {
+		return fmt.Errorf("%w: voucher amount must be positive", ErrValidation)
+	}
+	if date.Before(fund.OpeningDate) {
+		return fmt.Errorf("%w: voucher date is before the fund opening date", ErrValidation)
+	}
+
+	switch req.CounterpartyKind {
+	case model.LedgerAccountPerson:
+		if _, err := s.customerRepo.GetCustomerByID(ctx, req.CounterpartyID); err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				return fmt.Errorf("%w: person %d does not exist", ErrValidation, req.CounterpartyID)
+			}
+			return fmt.Errorf("failed to load counterparty person: %w", err)
+		}
+	case model.LedgerAccountFund:
+		if req.CounterpartyID == fund.ID {
+			return fmt.Errorf("%w: counterparty fund must differ from the voucher fund", ErrValidation)
+		}
+		other, err := s.GetFund(ctx, req.CounterpartyID)
+		if err != nil {
+			return err
+		}
+		if other.BIDID != fund.BIDID {
+			return fmt.Errorf("%w: counterparty fund belongs to another business", ErrValidation)
+		}
+	case model.LedgerAccountBank:
+		account, err := s.bankRepo.GetBankAccountByID(ctx, req.CounterpartyID)
+		if err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				return fmt.Errorf("%w: bank account %d does not exist", ErrValidation, req.CounterpartyID)
+			}
+			return fmt.Errorf("failed to load counterparty bank account: %w", err)
+		}
+		if account.BIDID != fund.BIDID {
+			return fmt.Errorf("%w: counterparty bank account belongs to another business", ErrValidation)
+		}
+	default:
+		return fmt.Errorf("%w: counterparty must be a person, fund or bank", ErrValidation)
+	}
+
+	voucher.Type = req.Type
+	voucher.Date = date
+	voucher.DateJalali = utils.FormatJalali(date)
+	voucher.CounterpartyKind = req.CounterpartyKind
+	voucher.CounterpartyID = req.CounterpartyID
+	voucher.Amount = req.Amount
+	voucher.CurrencyID = req.CurrencyID
+	voucher.CurrencyAmount = req.CurrencyAmount
+	voucher.Description = utils.PtrString(req.Description)
+	return nil
+}
+
+// voucherEntry دریافت: صندوق بدهکار و طرف حساب بستانکار؛ پرداخت برعکس.
+func voucherEntry(voucher *model.CashVoucher, actor string) *model.LedgerEntry {
+	fundID := voucher.FundID
+	counterpartyID := voucher.CounterpartyID
+	fundLine := model.LedgerLine{AccountKind: model.LedgerAccountFund, AccountID: &fundID}
+	otherLine := model.LedgerLine{AccountKind: voucher.CounterpartyKind, AccountID: &counterpartyID}
+	if voucher.CurrencyID != nil {
+		fundLine.CurrencyID = voucher.CurrencyID
+		fundLine.CurrencyAmount = voucher.CurrencyAmount
+		if voucher.CounterpartyKind != model.LedgerAccountPerson {
+			otherLine.CurrencyID = voucher.CurrencyID
+			otherLine.CurrencyAmount = voucher.CurrencyAmount
+		}
+	}
+
+	amount := voucher.Amount
+	desc := "دریافت نقدی"
+	if voucher.Type == model.CashVoucherReceipt {
+		fundLine.Debit = amount
+		otherLine.Credit = amount
+	} else {
+		desc = "پرداخت نقدی"
+		otherLine.Debit = amount
+		fundLine.Credit = amount
+	}
+	if voucher.Description != nil {
+		desc = desc + " - " + *voucher.Description
+	}
+	fundLine.Description = desc
+	otherLine.Description = desc
+
+	return &model.LedgerEntry{
+		BIDID:       voucher.BIDID,
+		DocType:     model.LedgerDocCashVoucher,
+		DocID:       voucher.ID,
+		Reference:   voucher.Number,
+		Description: desc,
+		PostedAt:    voucher.Date,
+		CreatedBy:   actor,
+		Lines:       []model.LedgerLine{fundLine, otherLine},
+	}
+}
+
+func mapFundRepoError(err error, msg string) error {
+	if errors.Is(err, postgresDb.ErrFundDayClosed) {
+		return fmt.Errorf("%w: %v", ErrConflict, err)
+	}
+	if errors.Is(err, postgresDb.ErrStaleCashVoucher) {
+		return fmt.Errorf("%w: %s", ErrConflict, "cash voucher was changed by another request, reload and retry")
+	}
+	if postgresDb.IsUniqueViolation(err) {
+		return fmt.Errorf("%w: %s", ErrConflict, "this day is already closed")
+	}
+	return fmt.Errorf("%s: %w", msg, err)
+}
@@ -0,0 +1,293 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math"
+	"strings"
+	"time"
+
+	"crm-gold/internal/model"
+	"crm-gold/internal/repository/db/postgresDb"
+	"crm-gold/internal/repository/repo"
+	"crm-gold/internal/utils"
+
+	"common-gold/money"
+
+	"github.com/shopspring/decimal"
+	"go.uber.org/zap"
+	"gorm.io/gorm"
+)
+
+const (
+	defaultSlowMoverDays = 90
+	maxSlowMoverDays     = 3650
+)
+
+type InventoryService interface {
+	CreateItem(ctx context.Context, req *model.CreateInventoryItemRequest, actor string) (*model.InventoryItem, error)
+	GetItem(ctx context.Context, id uint) (*model.InventoryItem, error)
+	ListItems(ctx context.Context, filter model.InventoryItemFilter) ([]model.InventoryItem, error)
+	UpdateItem(ctx context.Context, id uint, req *model.UpdateInventoryItemRequest) (*model.InventoryItem, error)
+	Valuation(ctx context.Context, bidID uint, goldRate money.Amount) (*model.InventoryValuation, error)
+	SlowMovers(ctx context.Context, bidID uint, days int) (*model.SlowMovers, error)
+}
+
+type inventoryServiceImpl struct {
+	inventoryRepo repo.InventoryRepo
+	customerRepo  repo.CustRepo
+	settingRepo   repo.SettingRepo
+	logger        *zap.Logger
+}
+
+func NewInventoryService(inventoryRepo repo.InventoryRepo, customerRepo repo.CustRepo, settingRepo repo.SettingRepo, logger *zap.Logger) (InventoryService, error) {
+	if inventoryRepo == nil {
+		return nil, errors.New("inventoryRepository cannot be nil for InventoryService")
+	}
+	if customerRepo == nil {
+		return nil, errors.New("customerRepository cannot be nil for InventoryService")
+	}
+	if settingRepo == nil {
+		return nil, errors.New("settingRepository cannot be nil for InventoryService")
+	}
+	if logger == nil {
+		return nil, errors.New("logger cannot be nil for InventoryService")
+	}
+	return &inventoryServiceImpl{
+		inventoryRepo: inventoryRepo,
+		customerRepo:  customerRepo,
+		settingRepo:   settingRepo,
+		logger:        logger,
+	}, nil
+}
+
+// CreateItem کالای امانی باید امانت‌گذار داشته باشد؛ کالای ملکی امانت‌گذار ندارد.
+func (s *inventoryServiceImpl) CreateItem(ctx context.Context, req *model.CreateInventoryItemRequest, actor string) (*model.InventoryItem, error) {
+	if req.BIDID == 0 {
+		return nil, fmt.Errorf("%w: bidId is required", ErrValidation)
+	}
+	code := strings.TrimSpace(utils.NormalizeDigits(req.Code))
+	name := strings.TrimSpace(req.Name)
+	if code == "" || name == "" {
+		return nil, fmt.Errorf("%w: item code and name are required", ErrValidation)
+	}
+	if req.Purity <= 0 || req.Purity > model.FineGoldPurity {
+		return nil, fmt.Errorf("%w: purity must be between 1 and %d (per mille)", ErrValidation, model.FineGoldPurity)
+	}
+	if !req.Weight.IsPositive() {
+		return nil, fmt.Errorf("%w: item weight must be positive", ErrValidation)
+	}
+	if req.CostPrice.IsNegative() {
+		return nil, fmt.Errorf("%w: cost price cannot be negative", ErrValidation)
+	}
+	ownership := req.Ownership
+	if ownership == "" {
+		ownership = model.InventoryOwned
+	}
+	switch ownership {
+	case model.InventoryOwned:
+		if req.ConsignorID != nil {
+			return nil, fmt.Errorf("%w: owned items cannot have a consignor", ErrValidation)
+		}
+	case model.InventoryConsigned:
+		if req.ConsignorID == nil {
+			return nil, fmt.Errorf("%w: consigned items require a consignor", ErrValidation)
+		}
+		consignor, err := s.customerRepo.GetCustomerByID(ctx, *req.ConsignorID)
+		if err != nil {
+			return nil, endpointLoadError(err, model.LedgerAccountPerson, *req.ConsignorID)
+		}
+		if consignor.BIDID != req.BIDID {
+			return nil, fmt.Errorf("%w: consignor %d belongs to another business", ErrValidation, consignor.ID)
+		}
+	default:
+		return nil, fmt.Errorf("%w: ownership must be owned or consigned", ErrValidation)
+	}
+	receivedAt := utils.StartOfDay(time.Now())
+	if req.ReceivedAt != "" {
+		d, err := utils.ParseDateParam(req.ReceivedAt)
+		if err != nil {
+			return nil, fmt.Errorf("%w: invalid received date: %v", ErrValidation, err)
+		}
+		receivedAt = utils.StartOfDay(d)
+	}
+
+	item := &model.InventoryItem{
+		BIDID:       req.BIDID,
+		Code:        code,
+		Name:        name,
+		Category:    strings.TrimSpace(req.Category),
+		Purity:      req.Purity,
+		Weight:      req.Weight,
+		CostPrice:   req.CostPrice,
+		Ownership:   ownership,
+		ConsignorID: req.ConsignorID,
+		ReceivedAt:  receivedAt,
+		Status:      model.InventoryItemInStock,
+		Description: utils.PtrString(req.Description),
+		CreatedBy:   actor,
+	}
+	created, err := s.inventoryRepo.CreateItem(ctx, item)
+	if err != nil {
+		if postgresDb.IsUniqueViolation(err) {
+			return nil, fmt.Errorf("%w: item code %s already exists", ErrConflict, code)
+		}
+		return nil, fmt.Errorf("failed to save inventory item: %w", err)
+	}
+	s.logger.Info("Inventory item created.", zap.Uint("item_id", created.ID), zap.String("code", created.Code), zap.String("ownership", created.Ownership))
+	return created, nil
+}
+
+func (s *inventoryServiceImpl) GetItem(ctx context.Context, id uint) (*model.InventoryItem, error) {
+	item, err := s.inventoryRepo.GetItemByID(ctx, id)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, fmt.Errorf("%w: inventory item %d", ErrNotFound, id)
+		}
+		return nil, fmt.Errorf("failed to fetch inventory item: %w", err)
+	}
+	return item, nil
+}
+
+func (s *inventoryServiceImpl) ListItems(ctx context.Context, filter model.InventoryItemFilter) ([]model.InventoryItem, error) {
+	items, err := s.inventoryRepo.ListItems(ctx, filter)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list inventory items: %w", err)
+	}
+	return items, nil
+}
+
+// UpdateItem وزن، عیار و مالکیت پس از ثبت تغییر نمی‌کنند تا با فاکتورهای فروش و گزارش‌ها سازگار بمانند.
+func (s *inventoryServiceImpl) UpdateItem(ctx context.Context, id uint, req *model.UpdateInventoryItemRequest) (*model.InventoryItem, error) {
+	item, err := s.GetItem(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if req.Name != nil {
+		name := strings.TrimSpace(*req.Name)
+		if name == "" {
+			return nil, fmt.Errorf("%w: item name cannot be empty", ErrValidation)
+		}
+		item.Name = name
+	}
+	if req.Category != nil {
+		item.Category = strings.TrimSpace(*req.Category)
+	}
+	if req.CostPrice != nil {
+		if req.CostPrice.IsNegative() {
+			return nil, fmt.Errorf("%w: cost price cannot be negative", ErrValidation)
+		}
+		if item.Status != model.InventoryItemInStock {
+			return nil, fmt.Errorf("%w: cost price of a sold item cannot change", ErrInvalidTransition)
+		}
+		item.CostPrice = *req.CostPrice
+	}
+	if req.Description != nil {
+		item.Description = utils.PtrString(*req.Description)
+	}
+	if err := s.inventoryRepo.UpdateItem(ctx, item); err != nil {
+		return nil, fmt.Errorf("failed to update inventory item: %w", err)
+	}
+	return item, nil
+}
+
+// Valuation موجودی فعلی را به بهای تمام‌شده و به نرخ روز ارزش‌گذاری می‌کند؛ goldRate صفر یعنی نرخ ذخیره‌شده در تنظیمات.
+func (s *inventoryServiceImpl) Valuation(ctx context.Context, bidID uint, goldRate money.Amount) (*model.InventoryValuation, error) {
+	if bidID == 0 {
+		return nil, fmt.Errorf("%w: bidId is required", ErrValidation)
+	}
+	if goldRate.IsNegative() {
+		return nil, fmt.Errorf("%w: gold rate cannot be negative", ErrValidation)
+	}
+	valuation := &model.InventoryValuation{AsOf: utils.FormatJalali(time.Now()), GoldRate: goldRate, Rows: []model.InventoryValuationRow{}}
+	if goldRate.IsZero() {
+		setting, err := s.settingRepo.GetBusinessSetting(ctx, bidID)
+		if err != nil {
+			return nil, fmt.Errorf("failed to load gold rate: %w", err)
+		}
+		if !setting.GoldRate.IsPositive() {
+			return nil, fmt.Errorf("%w: no gold rate is set for this business; pass goldRate or set it in settings", ErrValidation)
+		}
+		valuation.GoldRate = setting.GoldRate
+		valuation.GoldRateUpdatedAt = setting.GoldRateUpdatedAt
+	}
+
+	stock, err := s.inventoryRepo.StockSummary(ctx, bidID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to summarize inventory: %w", err)
+	}
+	rows := map[string]*model.InventoryValuationRow{}
+	var order []string
+	for _, st := range stock {
+		key := fmt.Sprintf("%s\x00%d", st.Category, st.Purity)
+		row, ok := rows[key]
+		if !ok {
+			row = &model.InventoryValuationRow{Category: st.Category, Purity: st.Purity}
+			rows[key] = row
+			order = append(order, key)
+		}
+		part := valuationRow(st, valuation.GoldRate)
+		addValuation(row, part)
+		addValuation(&valuation.Totals, part)
+		if st.Ownership == model.InventoryConsigned {
+			addValuation(&valuation.Consigned, part)
+		} else {
+			addValuation(&valuation.Owned, part)
+		}
+	}
+	for _, key := range order {
+		valuation.Rows = append(valuation.Rows, *rows[key])
+	}
+	valuation.ExposureFineGold = valuation.Owned.FineWeight
+	return valuation, nil
+}
+
+// valuationRow ارزش روز = وزن × (عیار ÷ ۷۵۰) × نرخ هر گرم ۱۸ عیار
+func valuationRow(st model.InventoryStockRow, goldRate money.Amount) model.InventoryValuationRow {
+	purity := decimal.NewFromInt(int64(st.Purity))
+	return model.InventoryValuationRow{
+		Category:    st.Category,
+		Purity:      st.Purity,
+		ItemCount:   st.ItemCount,
+		Weight:      st.Weight,
+		FineWeight:  money.WeightFromDecimal(st.Weight.Decimal().Mul(purity).Div(decimal.NewFromInt(model.FineGoldPurity))),
+		CostValue:   st.Cost,
+		MarketValue: money.AmountFromDecimal(st.Weight.Decimal().Mul(purity).Mul(goldRate.Decimal()).Div(decimal.NewFromInt(model.GoldRatePurity))),
+	}
+}
+
+func addValuation(dst *model.InventoryValuationRow, src model.InventoryValuationRow) {
+	dst.ItemCount += src.ItemCount
+	dst.Weight = dst.Weight.Add(src.Weight)
+	dst.FineWeight = dst.FineWeight.Add(src.FineWeight)
+	dst.CostValue = dst.CostValue.Add(src.CostValue)
+	dst.MarketValue = dst.MarketValue.Add(src.MarketValue)
+}
+
+func (s *inventoryServiceImpl) SlowMovers(ctx context.Context, bidID uint, days int) (*model.SlowMovers, error) {
+	if bidID == 0 {
+		return nil, fmt.Errorf("%w: bidId is required", ErrValidation)
+	}
+	if days == 0 {
+		days = defaultSlowMoverDays
+	}
+	if days < 0 || days > maxSlowMoverDays {
+		return nil, fmt.Errorf("%w: days must be between 1 and %d", ErrValidation, maxSlowMoverDays)
+	}
+	today := utils.StartOfDay(time.Now())
+	items, err := s.inventoryRepo.ListSlowMovers(ctx, bidID, today.AddDate(0, 0, -days+1))
+	if err != nil {
+		return nil, fmt.Errorf("failed to list slow-moving items: %w", err)
+	}
+	result := &model.SlowMovers{AsOf: utils.FormatJalali(today), Days: days, Items: make([]model.SlowMovingItem, 0, len(items))}
+	for _, item := range items {
+		result.Items = append(result.Items, model.SlowMovingItem{
+			InventoryItem: item,
+			DaysInStock:   int(math.Round(today.Sub(utils.StartOfDay(item.ReceivedAt)).Hours() / 24)),
+		})
+		result.TotalWeight = result.TotalWeight.Add(item.Weight)
+		result.TotalCost = result.TotalCost.Add(item.CostPrice)
+	}
+	return result, nil
+}
@@ -0,0 +1,451 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"crm-gold/internal/model"
+	"crm-gold/internal/repository/db/postgresDb"
+	"crm-gold/internal/repository/repo"
+	"crm-gold/internal/utils"
+
+	"common-gold/money"
+
+	"go.uber.org/zap"
+	"gorm.io/gorm"
+)
+
+type LedgerService interface {
+	ListAccounts(ctx context.Context, bidID uint) ([]model.Account, error)
+	CreateAccount(ctx context.Context, req *model.CreateAccountRequest) (*model.Account, error)
+	UpdateAccount(ctx context.Context, id uint, req *model.UpdateAccountRequest) (*model.Account, error)
+	CreateJournalEntry(ctx context.Context, req *model.CreateJournalEntryRequest, actor string) (*model.LedgerEntry, error)
+	GetEntry(ctx context.Context, id uint) (*model.LedgerEntry, error)
+	ListEntries(ctx context.Context, filter model.JournalFilter) ([]model.LedgerEntry, error)
+	ReverseEntry(ctx context.Context, id uint, req *model.ReverseJournalEntryRequest, actor string) (*model.LedgerEntry, error)
+	GetAccountLedger(ctx context.Context, bidID uint, accountCode string, detailID *uint, from, to time.Time) (*model.AccountLedger, error)
+}
+
+type ledgerServiceImpl struct {
+	accountRepo  repo.AccountRepo
+	ledgerRepo   repo.LedgerRepo
+	customerRepo repo.CustRepo
+	fundRepo     repo.FundRepo
+	bankRepo     repo.BankRepo
+	centerRepo   repo.CenterRepo
+	logger       *zap.Logger
+}
+
+func NewLedgerService(accountRepo repo.AccountRepo, ledgerRepo repo.LedgerRepo, customerRepo repo.CustRepo, fundRepo repo.FundRepo, bankRepo repo.BankRepo, centerRepo repo.CenterRepo, logger *zap.Logger) (LedgerService, error) {
+	if accountRepo == nil {
+		return nil, errors.New("accountRepository cannot be nil for LedgerService")
+	}
+	if ledgerRepo == nil {
+		return nil, errors.New("ledgerRepository cannot be nil for LedgerService")
+	}
+	if customerRepo == nil {
+		return nil, errors.New("customerRepository cannot be nil for LedgerService")
+	}
+	if fundRepo == nil {
+		return nil, errors.New("fundRepository cannot be nil for LedgerService")
+	}
+	if bankRepo == nil {
+		return nil, errors.New("bankRepository cannot be nil for LedgerService")
+	}
+	if centerRepo == nil {
+		return nil, errors.New("centerRepository cannot be nil for LedgerService")
+	}
+	if logger == nil {
+		return nil, errors.New("logger cannot be nil for LedgerService")
+	}
+	return &ledgerServiceImpl{
+		accountRepo:  accountRepo,
+		ledgerRepo:   ledgerRepo,
+		customerRepo: customerRepo,
+		fundRepo:     fundRepo,
+		bankRepo:     bankRepo,
+		centerRepo:   centerRepo,
+		logger:       logger,
+	}, nil
+}
+
+// ListAccounts سرفصل پیش‌فرض در اولین مراجعه ساخته می‌شود.
+func (s *ledgerServiceImpl) ListAccounts(ctx context.Context, bidID uint) ([]model.Account, error) {
+	if bidID == 0 {
+		return nil, fmt.Errorf("%w: bidId is required", ErrValidation)
+	}
+	if err := s.accountRepo.EnsureDefaultChart(ctx, bidID); err != nil {
+		return nil, fmt.Errorf("failed to prepare chart of accounts: %w", err)
+	}
+	accounts, err := s.accountRepo.ListAccounts(ctx, bidID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list accounts: %w", err)
+	}
+	return accounts, nil
+}
+
+// CreateAccount حساب کل زیر گروه (کد ۲ رقمی) یا حساب معین زیر کل (کد ۴ رقمی) می‌سازد؛ کد فرزند با کد والد شروع می‌شود.
+func (s *ledgerServiceImpl) CreateAccount(ctx context.Context, req *model.CreateAccountRequest) (*model.Account, error) {
+	if req.BIDID == 0 {
+		return nil, fmt.Errorf("%w: bidId is required", ErrValidation)
+	}
+	code := strings.TrimSpace(utils.NormalizeDigits(req.Code))
+	name := strings.TrimSpace(req.Name)
+	if code == "" || name == "" {
+		return nil, fmt.Errorf("%w: account code and name are required", ErrValidation)
+	}
+	if err := s.accountRepo.EnsureDefaultChart(ctx, req.BIDID); err != nil {
+		return nil, fmt.Errorf("failed to prepare chart of accounts: %w", err)
+	}
+	parent, err := s.accountRepo.GetAccountByCode(ctx, req.BIDID, strings.TrimSpace(utils.NormalizeDigits(req.ParentCode)))
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, fmt.Errorf("%w: parent account %s does not exist", ErrValidation, req.ParentCode)
+		}
+		return nil, fmt.Errorf("failed to fetch parent account: %w", err)
+	}
+
+	var level string
+	var codeLen int
+	switch parent.Level {
+	case model.AccountLevelGroup:
+		level, codeLen = model.AccountLevelGeneral, 2
+	case model.AccountLevelGeneral:
+		level, codeLen = model.AccountLevelSubsidiary, 4
+	default:
+		return nil, fmt.Errorf("%w: accounts can only be added under a group or general account", ErrValidation)
+	}
+	if len(code) != codeLen || !strings.HasPrefix(code, parent.Code) || strings.Trim(code, "0123456789") != "" {
+		return nil, fmt.Errorf("%w: account code must be %d digits starting with %s", ErrValidation, codeLen, parent.Code)
+	}
+	switch req.DetailType {
+	case "", model.DetailTypePerson, model.DetailTypeFund, model.DetailTypeBank, model.DetailTypeCenter:
+	default:
+		return nil, fmt.Errorf("%w: invalid detail type %q", ErrValidation, req.DetailType)
+	}
+	if level != model.AccountLevelSubsidiary && req.DetailType != "" {
+		return nil, fmt.Errorf("%w: only subsidiary accounts can have detail accounts", ErrValidation)
+	}
+
+	account := &model.Account{
+		BIDID:      req.BIDID,
+		Code:       code,
+		Name:       name,
+		Level:      level,
+		Category:   parent.Category,
+		Nature:     parent.Nature,
+		ParentID:   &parent.ID,
+		DetailType: req.DetailType,
+		IsActive:   true,
+	}
+	created, err := s.accountRepo.CreateAccount(ctx, account)
+	if err != nil {
+		if postgresDb.IsUniqueViolation(err) {
+			return nil, fmt.Errorf("%w: account code %s already exists", ErrConflict, code)
+		}
+		return nil, fmt.Errorf("failed to save account: %w", err)
+	}
+	return created, nil
+}
+
+func (s *ledgerServiceImpl) UpdateAccount(ctx context.Context, id uint, req *model.UpdateAccountRequest) (*model.Account, error) {
+	account, err := s.accountRepo.GetAccountByID(ctx, id)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, fmt.Errorf("%w: account %d", ErrNotFound, id)
+		}
+		return nil, fmt.Errorf("failed to fetch account: %w", err)
+	}
+	if req.Name != nil {
+		name := strings.TrimSpace(*req.Name)
+		if name == "" {
+			return nil, fmt.Errorf("%w: account name cannot be empty", ErrValidation)
+		}
+		account.Name = name
+	}
+	if req.IsActive != nil {
+		if account.IsSystem && !*req.IsActive {
+			return nil, fmt.Errorf("%w: system accounts cannot be deactivated", ErrValidation)
+		}
+		account.IsActive = *req.IsActive
+	}
+	if err := s.accountRepo.UpdateAccount(ctx, account); err != nil {
+		return nil, fmt.Errorf("failed to update account: %w", err)
+	}
+	return account, nil
+}
+
+// CreateJournalEntry سند حسابداری دستی؛ فقط روی حساب‌های معین فعال و با تفصیلی معتبر ثبت می‌شود.
+func (s *ledgerServiceImpl) CreateJournalEntry(ctx context.Context, req *model.CreateJournalEntryRequest, actor string) (*model.LedgerEntry, error) {
+	if req.BIDID == 0 {
+		return nil, fmt.Errorf("%w: bidId is required", ErrValidation)
+	}
+	description := strings.TrimSpace(req.Description)
+	if description == "" {
+		return nil, fmt.Errorf("%w: description is required", ErrValidation)
+	}
+	date, err := utils.ParseDateParam(req.Date)
+	if err != nil {
+		return nil, fmt.Errorf("%w: invalid entry date: %v", ErrValidation, err)
+	}
+	date = utils.StartOfDay(date)
+	if err := s.accountRepo.EnsureDefaultChart(ctx, req.BIDID); err != nil {
+		return nil, fmt.Errorf("failed to prepare chart of accounts: %w", err)
+	}
+
+	lines := make([]model.LedgerLine, 0, len(req.Lines))
+	for i, l := range req.Lines {
+		line, err := s.journalLine(ctx, req.BIDID, l)
+		if err != nil {
+			return nil, fmt.Errorf("line %d: %w", i+1, err)
+		}
+		if line.Description == "" {
+			line.Description = description
+		}
+		lines = append(lines, *line)
+	}
+
+	code, err := utils.GenerateSecureRandomString(8)
+	if err != nil {
+		return nil, fmt.Errorf("failed to generate journal number: %w", err)
+	}
+	entry := &model.LedgerEntry{
+		BIDID:       req.BIDID,
+		DocType:     model.LedgerDocJournal,
+		Reference:   "JRN-" + code,
+		Description: description,
+		PostedAt:    date,
+		CreatedBy:   actor,
+		Lines:       lines,
+	}
+	if !entry.IsBalanced() {
+		return nil, fmt.Errorf("%w: journal entry must balance in both Toman and gold grams", ErrValidation)
+	}
+	created, err := s.ledgerRepo.PostJournalEntry(ctx, entry)
+	if err != nil {
+		if errors.Is(err, postgresDb.ErrFundDayClosed) {
+			return nil, fmt.Errorf("%w: %v", ErrConflict, err)
+		}
+		return nil, fmt.Errorf("failed to post journal entry: %w", err)
+	}
+	s.logger.Info("Journal entry posted.", zap.Uint("entry_id", created.ID), zap.String("reference", created.Reference))
+	return created, nil
+}
+
+func (s *ledgerServiceImpl) journalLine(ctx context.Context, bidID uint, req model.JournalLineRequest) (*model.LedgerLine, error) {
+	account, err := s.accountRepo.GetAccountByCode(ctx, bidID, strings.TrimSpace(utils.NormalizeDigits(req.AccountCode)))
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, fmt.Errorf("%w: account %s does not exist", ErrValidation, req.AccountCode)
+		}
+		return nil, fmt.Errorf("failed to fetch account: %w", err)
+	}
+	if account.Level != model.AccountLevelSubsidiary {
+		return nil, fmt.Errorf("%w: entries can only be posted to subsidiary accounts", ErrValidation)
+	}
+	if !account.IsActive {
+		return nil, fmt.Errorf("%w: account %s is inactive", ErrValidation, account.Code)
+	}
+	if (req.Debit.IsPositive() || req.GoldDebit.IsPositive()) && (req.Credit.IsPositive() || req.GoldCredit.IsPositive()) {
+		return nil, fmt.Errorf("%w: a line cannot be both debit and credit", ErrValidation)
+	}
+
+	var detailID *uint
+	if account.DetailType != "" {
+		if req.DetailID == nil || *req.DetailID == 0 {
+			return nil, fmt.Errorf("%w: account %s requires a %s detail", ErrValidation, account.Code, account.DetailType)
+		}
+		if err := s.validateDetail(ctx, bidID, account.DetailType, *req.DetailID); err != nil {
+			return nil, err
+		}
+		detailID = req.DetailID
+	} else if req.DetailID != nil {
+		return nil, fmt.Errorf("%w: account %s has no detail accounts", ErrValidation, account.Code)
+	}
+
+	kind := account.Kind
+	if kind == "" {
+		kind = model.LedgerAccountGeneral
+	}
+	return &model.LedgerLine{
+		AccountKind: kind,
+		AccountID:   detailID,
+		AccountCode: account.Code,
+		Debit:       req.Debit,
+		Credit:      req.Credit,
+		GoldDebit:   req.GoldDebit,
+		GoldCredit:  req.GoldCredit,
+		Description: strings.TrimSpace(req.Description),
+	}, nil
+}
+
+func (s *ledgerServiceImpl) validateDetail(ctx context.Context, bidID uint, detailType string, id uint) error {
+	var ownerBID uint
+	switch detailType {
+	case model.DetailTypePerson:
+		person, err := s.customerRepo.GetCustomerByID(ctx, id)
+		if err != nil {
+			return endpointLoadError(err, detailType, id)
+		}
+		ownerBID = person.BIDID
+	case model.DetailTypeFund:
+		fund, err := s.fundRepo.GetFundByID(ctx, id)
+		if err != nil {
+			return endpointLoadError(err, detailType, id)
+		}
+		ownerBID = fund.BIDID
+	case model.DetailTypeBank:
+		account, err := s.bankRepo.GetBankAccountByID(ctx, id)
+		if err != nil {
+			return endpointLoadError(err, detailType, id)
+		}
+		ownerBID = account.BIDID
+	case model.DetailTypeCenter:
+		center, err := s.centerRepo.GetCenterByID(ctx, id)
+		if err != nil {
+			return endpointLoadError(err, detailType, id)
+		}
+		ownerBID = center.BIDID
+	}
+	if ownerBID != bidID {
+		return fmt.Errorf("%w: %s %d belongs to another business", ErrValidation, detailType, id)
+	}
+	return nil
+}
+
+func (s *ledgerServiceImpl) GetEntry(ctx context.Context, id uint) (*model.LedgerEntry, error) {
+	entry, err := s.ledgerRepo.GetEntryByID(ctx, id)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, fmt.Errorf("%w: ledger entry %d", ErrNotFound, id)
+		}
+		return nil, fmt.Errorf("failed to fetch ledger entry: %w", err)
+	}
+	return entry, nil
+}
+
+func (s *ledgerServiceImpl) ListEntries(ctx context.Context, filter model.JournalFilter) ([]model.LedgerEntry, error) {
+	if filter.BIDID == 0 {
+		return nil, fmt.Errorf("%w: bidId is required", ErrValidation)
+	}
+	entries, err := s.ledgerRepo.ListEntries(ctx, filter)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list ledger entries: %w", err)
+	}
+	return entries, nil
+}
+
+// ReverseEntry فقط اسناد دستی را برمی‌گرداند؛ اسناد سیستمی باید از طریق سند مبدا خود (چک، انتقال و ...) اصلاح شوند.
+func (s *ledgerServiceImpl) ReverseEntry(ctx context.Context, id uint, req *model.ReverseJournalEntryRequest, actor string) (*model.LedgerEntry, error) {
+	entry, err := s.GetEntry(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if entry.DocType != model.LedgerDocJournal {
+		return nil, fmt.Errorf("%w: %s entries must be corrected through their source document", ErrInvalidTransition, entry.DocType)
+	}
+	if entry.ReversalOfID != nil {
+		return nil, fmt.Errorf("%w: a reversing entry cannot be reversed", ErrInvalidTransition)
+	}
+	if strings.TrimSpace(req.Reason) == "" {
+		return nil, fmt.Errorf("%w: reversal reason is required", ErrValidation)
+	}
+	at := utils.StartOfDay(time.Now())
+	if req.Date != "" {
+		d, err := utils.ParseDateParam(req.Date)
+		if err != nil {
+			return nil, fmt.Errorf("%w: invalid reversal date: %v", ErrValidation, err)
+		}
+		at = utils.StartOfDay(d)
+	}
+	if at.Before(entry.PostedAt) {
+		return nil, fmt.Errorf("%w: reversal date cannot be before the entry date", ErrValidation)
+	}
+
+	reversal := entry.Reversal(at, actor, strings.TrimSpace(req.Reason))
+	if err := s.ledgerRepo.ReverseJournalEntry(ctx, entry, reversal); err != nil {
+		switch {
+		case errors.Is(err, postgresDb.ErrEntryAlreadyReversed):
+			return nil, fmt.Errorf("%w: %v", ErrConflict, err)
+		case errors.Is(err, postgresDb.ErrFundDayClosed):
+			return nil, fmt.Errorf("%w: %v", ErrConflict, err)
+		}
+		return nil, fmt.Errorf("failed to reverse ledger entry: %w", err)
+	}
+	s.logger.Info("Journal entry reversed.", zap.Uint("entry_id", entry.ID), zap.Uint("reversal_id", reversal.ID), zap.String("actor", actor))
+	return reversal, nil
+}
+
+// GetAccountLedger دفتر حساب با مانده ریالی و وزنی؛ مانده بر اساس ماهیت حساب (بدهکار/بستانکار) مثبت نمایش داده می‌شود.
+func (s *ledgerServiceImpl) GetAccountLedger(ctx context.Context, bidID uint, accountCode string, detailID *uint, from, to time.Time) (*model.AccountLedger, error) {
+	if bidID == 0 {
+		return nil, fmt.Errorf("%w: bidId is required", ErrValidation)
+	}
+	if !from.Before(to) {
+		return nil, fmt.Errorf("%w: 'from' must be before 'to'", ErrValidation)
+	}
+	account, err := s.accountRepo.GetAccountByCode(ctx, bidID, accountCode)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, fmt.Errorf("%w: account %s", ErrNotFound, accountCode)
+		}
+		return nil, fmt.Errorf("failed to fetch account: %w", err)
+	}
+	// مانده حساب‌های با ماهیت بستانکار با علامت معکوس نمایش داده می‌شود.
+	creditNature := account.Nature == model.AccountNatureCredit
+	signed := func(toman money.Amount, gold money.Weight) (money.Amount, money.Weight) {
+		if creditNature {
+			return toman.Neg(), gold.Neg()
+		}
+		return toman, gold
+	}
+
+	opening, openingGold, err := s.ledgerRepo.GetCodeBalance(ctx, bidID, account.Code, detailID, from)
+	if err != nil {
+		return nil, fmt.Errorf("failed to compute opening balance: %w", err)
+	}
+	lines, err := s.ledgerRepo.GetCodeLines(ctx, bidID, account.Code, detailID, from, to)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load account lines: %w", err)
+	}
+
+	ledger := &model.AccountLedger{
+		Account:  *account,
+		DetailID: detailID,
+		From:     utils.FormatJalali(from),
+		To:       utils.FormatJalali(to.AddDate(0, 0, -1)),
+		Lines:    make([]model.AccountLedgerLine, 0, len(lines)),
+	}
+	ledger.OpeningBalance, ledger.OpeningGold = signed(opening, openingGold)
+	balance, gold := ledger.OpeningBalance, ledger.OpeningGold
+	for _, l := range lines {
+		toman, weight := signed(l.Debit.Sub(l.Credit), l.GoldDebit.Sub(l.GoldCredit))
+		balance = balance.Add(toman)
+		gold = gold.Add(weight)
+		ledger.TotalDebit = ledger.TotalDebit.Add(l.Debit)
+		ledger.TotalCredit = ledger.TotalCredit.Add(l.Credit)
+		ledger.TotalGoldDebit = ledger.TotalGoldDebit.Add(l.GoldDebit)
+		ledger.TotalGoldCredit = ledger.TotalGoldCredit.Add(l.GoldCredit)
+		ledger.Lines = append(ledger.Lines, model.AccountLedgerLine{
+			EntryID:     l.EntryID,
+			Date:        l.PostedAt,
+			DateJalali:  utils.FormatJalali(l.PostedAt),
+			DocType:     l.DocType,
+			DocID:       l.DocID,
+			Reference:   l.Reference,
+			Description: l.Description,
+			DetailID:    l.AccountID,
+			Debit:       l.Debit,
+			Credit:      l.Credit,
+			GoldDebit:   l.GoldDebit,
+			GoldCredit:  l.GoldCredit,
+			Balance:     balance,
+			GoldBalance: gold,
+		})
+	}
+	ledger.ClosingBalance, ledger.ClosingGold = balance, gold
+	return ledger, nil
+}
@@ -0,0 +1,10 @@
+package notification
+
+import "context"
+
+// NotificationClient ارسال اعلان از طریق سرویس notificationManager
+type NotificationClient interface {
+	// SendToUser کاربر را با شناسه UUID او در profileManager (user_ref) پیدا می‌کند.
+	SendToUser(ctx context.Context, userRef, notificationType, message string) error
+	BaseURL() string
+}
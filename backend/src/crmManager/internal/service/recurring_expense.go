@@ -0,0 +1,232 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"crm-gold/internal/model"
+	"crm-gold/internal/repository/db/postgresDb"
+	"crm-gold/internal/repository/repo"
+	"crm-gold/internal/utils"
+
+	"go.uber.org/zap"
+	"gorm.io/gorm"
+)
+
+type RecurringExpenseService interface {
+	CreateRecurringExpense(ctx context.Context, req *model.CreateRecurringExpenseRequest, actor string) (*model.RecurringExpense, error)
+	ListRecurringExpenses(ctx context.Context, bidID uint) ([]model.RecurringExpense, error)
+	UpdateRecurringExpense(ctx context.Context, id uint, req *model.UpdateRecurringExpenseRequest) (*model.RecurringExpense, error)
+	RunDue(ctx context.Context, now time.Time) (int, error)
+}
+
+type recurringExpenseServiceImpl struct {
+	centerRepo repo.CenterRepo
+	fundRepo   repo.FundRepo
+	bankRepo   repo.BankRepo
+	logger     *zap.Logger
+}
+
+func NewRecurringExpenseService(centerRepo repo.CenterRepo, fundRepo repo.FundRepo, bankRepo repo.BankRepo, logger *zap.Logger) (RecurringExpenseService, error) {
+	if centerRepo == nil {
+		return nil, errors.New("centerRepository cannot be nil for RecurringExpenseService")
+	}
+	if fundRepo == nil {
+		return nil, errors.New("fundRepository cannot be nil for RecurringExpenseService")
+	}
+	if bankRepo == nil {
+		return nil, errors.New("bankRepository cannot be nil for RecurringExpenseService")
+	}
+	if logger == nil {
+		return nil, errors.New("logger cannot be nil for RecurringExpenseService")
+	}
+	return &recurringExpenseServiceImpl{
+		centerRepo: centerRepo,
+		fundRepo:   fundRepo,
+		bankRepo:   bankRepo,
+		logger:     logger,
+	}, nil
+}
+
+func (s *recurringExpenseServiceImpl) CreateRecurringExpense(ctx context.Context, req *model.CreateRecurringExpenseRequest, actor string) (*model.RecurringExpense, error) {
+	if !req.Amount.IsPositive() {
+		return nil, fmt.Errorf("%w: amount must be positive", ErrValidation)
+	}
+	switch req.Frequency {
+	case model.RecurrenceWeekly, model.RecurrenceMonthly, model.RecurrenceYearly:
+	default:
+		return nil, fmt.Errorf("%w: frequency must be weekly, monthly or yearly", ErrValidation)
+	}
+	title := strings.TrimSpace(req.Title)
+	if title == "" {
+		return nil, fmt.Errorf("%w: title is required", ErrValidation)
+	}
+	start, err := utils.ParseDateParam(req.StartDate)
+	if err != nil {
+		return nil, fmt.Errorf("%w: invalid start date: %v", ErrValidation, err)
+	}
+	start = utils.StartOfDay(start)
+	var end *time.Time
+	if req.EndDate != "" {
+		d, err := utils.ParseDateParam(req.EndDate)
+		if err != nil {
+			return nil, fmt.Errorf("%w: invalid end date: %v", ErrValidation, err)
+		}
+		d = utils.StartOfDay(d)
+		if d.Before(start) {
+			return nil, fmt.Errorf("%w: end date cannot be before start date", ErrValidation)
+		}
+		end = &d
+	}
+
+	center, err := s.centerRepo.GetCenterByID(ctx, req.CenterID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, fmt.Errorf("%w: center %d does not exist", ErrValidation, req.CenterID)
+		}
+		return nil, fmt.Errorf("failed to fetch center: %w", err)
+	}
+	if center.Type != model.CenterTypeCost {
+		return nil, fmt.Errorf("%w: recurring expenses require a cost center", ErrValidation)
+	}
+	if !center.IsActive {
+		return nil, fmt.Errorf("%w: center %d is inactive", ErrValidation, center.ID)
+	}
+	if err := validateCashAccount(ctx, s.fundRepo, s.bankRepo, center.BIDID, req.AccountKind, req.AccountID, start); err != nil {
+		return nil, err
+	}
+
+	_, _, day := utils.GregorianToJalali(start)
+	rec := &model.RecurringExpense{
+		BIDID:       center.BIDID,
+		CenterID:    center.ID,
+		Title:       title,
+		Amount:      req.Amount,
+		AccountKind: req.AccountKind,
+		AccountID:   req.AccountID,
+		PersonID:    req.PersonID,
+		Frequency:   req.Frequency,
+		DayOfMonth:  day,
+		StartDate:   start,
+		EndDate:     end,
+		NextRunDate: start,
+		IsActive:    true,
+		CreatedBy:   actor,
+	}
+	created, err := s.centerRepo.CreateRecurringExpense(ctx, rec)
+	if err != nil {
+		return nil, fmt.Errorf("failed to save recurring expense: %w", err)
+	}
+	return created, nil
+}
+
+func (s *recurringExpenseServiceImpl) ListRecurringExpenses(ctx context.Context, bidID uint) ([]model.RecurringExpense, error) {
+	recs, err := s.centerRepo.ListRecurringExpenses(ctx, bidID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list recurring expenses: %w", err)
+	}
+	return recs, nil
+}
+
+func (s *recurringExpenseServiceImpl) UpdateRecurringExpense(ctx context.Context, id uint, req *model.UpdateRecurringExpenseRequest) (*model.RecurringExpense, error) {
+	rec, err := s.centerRepo.GetRecurringExpenseByID(ctx, id)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, fmt.Errorf("%w: recurring expense %d", ErrNotFound, id)
+		}
+		return nil, fmt.Errorf("failed to fetch recurring expense: %w", err)
+	}
+	if req.Amount != nil {
+		if !req.Amount.IsPositive() {
+			return nil, fmt.Errorf("%w: amount must be positive", ErrValidation)
+		}
+		rec.Amount = *req.Amount
+	}
+	if req.EndDate != nil {
+		if *req.EndDate == "" {
+			rec.EndDate = nil
+		} else {
+			d, err := utils.ParseDateParam(*req.EndDate)
+			if err != nil {
+				return nil, fmt.Errorf("%w: invalid end date: %v", ErrValidation, err)
+			}
+			d = utils.StartOfDay(d)
+			if d.Before(rec.StartDate) {
+				return nil, fmt.Errorf("%w: end date cannot be before start date", ErrValidation)
+			}
+			rec.EndDate = &d
+		}
+	}
+	if req.IsActive != nil {
+		rec.IsActive = *req.IsActive
+	}
+	if err := s.centerRepo.UpdateRecurringExpense(ctx, rec); err != nil {
+		return nil, fmt.Errorf("failed to update recurring expense: %w", err)
+	}
+	return rec, nil
+}
+
+// RunDue نوبت‌های عقب‌افتاده هر هزینه تکراری را تا امروز یکی‌یکی ثبت می‌کند؛ خطای یک مورد مانع اجرای بقیه نمی‌شود.
+func (s *recurringExpenseServiceImpl) RunDue(ctx context.Context, now time.Time) (int, error) {
+	today := utils.StartOfDay(now)
+	recs, err := s.centerRepo.ListDueRecurringExpenses(ctx, today)
+	if err != nil {
+		return 0, fmt.Errorf("failed to load due recurring expenses: %w", err)
+	}
+
+	created := 0
+	for i := range recs {
+		rec := &recs[i]
+		center, err := s.centerRepo.GetCenterByID(ctx, rec.CenterID)
+		if err != nil {
+			s.logger.Error("Failed to load center for recurring expense", zap.Uint("recurring_expense_id", rec.ID), zap.Error(err))
+			continue
+		}
+		for !rec.NextRunDate.After(today) && (rec.EndDate == nil || !rec.NextRunDate.After(*rec.EndDate)) {
+			voucher, err := newCenterVoucher(center, rec.NextRunDate, rec.Amount, rec.AccountKind, rec.AccountID, rec.PersonID, rec.Title, rec.CreatedBy)
+			if err != nil {
+				s.logger.Error("Failed to build recurring expense voucher", zap.Uint("recurring_expense_id", rec.ID), zap.Error(err))
+				break
+			}
+			recID := rec.ID
+			voucher.RecurringExpenseID = &recID
+			entry := centerVoucherEntry(voucher, center, rec.CreatedBy)
+			if err := s.centerRepo.RunRecurringExpense(ctx, rec, voucher, entry, nextRecurrence(rec)); err != nil {
+				if !errors.Is(err, postgresDb.ErrRecurringAlreadyRun) {
+					s.logger.Error("Failed to run recurring expense", zap.Uint("recurring_expense_id", rec.ID), zap.Time("date", rec.NextRunDate), zap.Error(err))
+				}
+				break
+			}
+			created++
+		}
+	}
+
+	s.logger.Info("Recurring expense run finished.", zap.Int("due", len(recs)), zap.Int("created", created))
+	return created, nil
+}
+
+// nextRecurrence نوبت بعدی؛ ماهانه و سالانه در تقویم شمسی و با حفظ روز ماه اصلی (در ماه‌های کوتاه‌تر روز آخر ماه).
+func nextRecurrence(rec *model.RecurringExpense) time.Time {
+	switch rec.Frequency {
+	case model.RecurrenceWeekly:
+		return rec.NextRunDate.AddDate(0, 0, 7)
+	case model.RecurrenceYearly:
+		return jalaliMonthDay(utils.AddJalaliMonths(rec.NextRunDate, 12), rec.DayOfMonth)
+	default:
+		return jalaliMonthDay(utils.AddJalaliMonths(rec.NextRunDate, 1), rec.DayOfMonth)
+	}
+}
+
+func jalaliMonthDay(t time.Time, day int) time.Time {
+	jy, jm, jd := utils.GregorianToJalali(t)
+	if l := utils.JalaliMonthLength(jy, jm); day > l {
+		day = l
+	}
+	if day == jd {
+		return t
+	}
+	return utils.JalaliToGregorian(jy, jm, day)
+}
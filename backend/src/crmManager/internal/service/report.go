@@ -0,0 +1,322 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+
+	"crm-gold/internal/model"
+	"crm-gold/internal/repository/repo"
+	"crm-gold/internal/utils"
+
+	"common-gold/money"
+
+	"go.uber.org/zap"
+)
+
+type ReportService interface {
+	TrialBalance(ctx context.Context, filter model.ReportFilter) (*model.TrialBalance, error)
+	ProfitLoss(ctx context.Context, filter model.ReportFilter) (*model.ProfitLoss, error)
+	BalanceSheet(ctx context.Context, filter model.ReportFilter) (*model.BalanceSheet, error)
+}
+
+type reportServiceImpl struct {
+	accountRepo    repo.AccountRepo
+	ledgerRepo     repo.LedgerRepo
+	fiscalYearRepo repo.FiscalYearRepo
+	logger         *zap.Logger
+}
+
+func NewReportService(accountRepo repo.AccountRepo, ledgerRepo repo.LedgerRepo, fiscalYearRepo repo.FiscalYearRepo, logger *zap.Logger) (ReportService, error) {
+	if accountRepo == nil {
+		return nil, errors.New("accountRepository cannot be nil for ReportService")
+	}
+	if ledgerRepo == nil {
+		return nil, errors.New("ledgerRepository cannot be nil for ReportService")
+	}
+	if fiscalYearRepo == nil {
+		return nil, errors.New("fiscalYearRepository cannot be nil for ReportService")
+	}
+	if logger == nil {
+		return nil, errors.New("logger cannot be nil for ReportService")
+	}
+	return &reportServiceImpl{
+		accountRepo:    accountRepo,
+		ledgerRepo:     ledgerRepo,
+		fiscalYearRepo: fiscalYearRepo,
+		logger:         logger,
+	}, nil
+}
+
+// accountBalance مانده ابتدای بازه و گردش داخل بازه یک حساب در سطح انتخاب‌شده
+type accountBalance struct {
+	openToman             money.Amount
+	openGold              money.Weight
+	debit, credit         money.Amount
+	goldDebit, goldCredit money.Weight
+}
+
+func (b *accountBalance) closingToman() money.Amount { return b.openToman.Add(b.debit).Sub(b.credit) }
+
+func (b *accountBalance) closingGold() money.Weight {
+	return b.openGold.Add(b.goldDebit).Sub(b.goldCredit)
+}
+
+type reportData struct {
+	accounts map[string]model.Account
+	balances map[string]*accountBalance
+	codes    []string
+}
+
+func (d *reportData) name(code string) string {
+	if a, ok := d.accounts[code]; ok {
+		return a.Name
+	}
+	return code
+}
+
+// category گروه هر حساب از خودش یا از حساب گروه (رقم اول کد) خوانده می‌شود.
+func (d *reportData) category(code string) string {
+	if a, ok := d.accounts[code]; ok && a.Category != "" {
+		return a.Category
+	}
+	if len(code) > 0 {
+		return d.accounts[code[:1]].Category
+	}
+	return ""
+}
+
+func levelCode(code, level string) string {
+	switch {
+	case level == model.AccountLevelGroup && len(code) > 1:
+		return code[:1]
+	case level == model.AccountLevelGeneral && len(code) > 2:
+		return code[:2]
+	default:
+		return code
+	}
+}
+
+func valued(toman money.Amount, gold money.Weight, rate money.Amount) money.Amount {
+	return toman.Add(gold.MulRate(rate))
+}
+
+func reportRange(filter model.ReportFilter) (string, string) {
+	return utils.FormatJalali(filter.From), utils.FormatJalali(filter.To.AddDate(0, 0, -1))
+}
+
+// load مانده ابتدای بازه را از همه اسناد و گردش بازه را بدون اسناد بستن سال محاسبه می‌کند؛
+// برای جلوگیری از شمارش دوباره مانده‌ها، بازه نباید از پایان یک سال مالی تعریف‌شده عبور کند.
+func (s *reportServiceImpl) load(ctx context.Context, filter *model.ReportFilter) (*reportData, error) {
+	if filter.BIDID == 0 {
+		return nil, fmt.Errorf("%w: bidId is required", ErrValidation)
+	}
+	if !filter.From.Before(filter.To) {
+		return nil, fmt.Errorf("%w: 'from' must not be after 'to'", ErrValidation)
+	}
+	if filter.GoldRate.IsNegative() {
+		return nil, fmt.Errorf("%w: gold rate cannot be negative", ErrValidation)
+	}
+	switch filter.Level {
+	case "":
+		filter.Level = model.AccountLevelSubsidiary
+	case model.AccountLevelGroup, model.AccountLevelGeneral, model.AccountLevelSubsidiary:
+	default:
+		return nil, fmt.Errorf("%w: invalid account level %q", ErrValidation, filter.Level)
+	}
+
+	years, err := s.fiscalYearRepo.ListFiscalYears(ctx, filter.BIDID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list fiscal years: %w", err)
+	}
+	for _, y := range years {
+		if !filter.From.Before(y.StartDate) && !filter.From.After(y.EndDate) && filter.To.After(y.EndDate.AddDate(0, 0, 1)) {
+			return nil, fmt.Errorf("%w: report range must stay within %s", ErrValidation, y.Title)
+		}
+	}
+
+	if err := s.accountRepo.EnsureDefaultChart(ctx, filter.BIDID); err != nil {
+		return nil, fmt.Errorf("failed to prepare chart of accounts: %w", err)
+	}
+	accounts, err := s.accountRepo.ListAccounts(ctx, filter.BIDID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list accounts: %w", err)
+	}
+	opening, err := s.ledgerRepo.GetCodeTotals(ctx, filter.BIDID, nil, filter.From, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to compute opening balances: %w", err)
+	}
+	period, err := s.ledgerRepo.GetCodeTotals(ctx, filter.BIDID, &filter.From, filter.To, []string{model.LedgerDocProfitClosing, model.LedgerDocYearClosing})
+	if err != nil {
+		return nil, fmt.Errorf("failed to compute period turnover: %w", err)
+	}
+
+	data := &reportData{accounts: make(map[string]model.Account, len(accounts)), balances: map[string]*accountBalance{}}
+	for _, a := range accounts {
+		data.accounts[a.Code] = a
+	}
+	get := func(code string) *accountBalance {
+		key := levelCode(code, filter.Level)
+		b, ok := data.balances[key]
+		if !ok {
+			b = &accountBalance{}
+			data.balances[key] = b
+			data.codes = append(data.codes, key)
+		}
+		return b
+	}
+	for _, row := range opening {
+		b := get(row.AccountCode)
+		b.openToman = b.openToman.Add(row.Debit).Sub(row.Credit)
+		b.openGold = b.openGold.Add(row.GoldDebit).Sub(row.GoldCredit)
+	}
+	for _, row := range period {
+		b := get(row.AccountCode)
+		b.debit = b.debit.Add(row.Debit)
+		b.credit = b.credit.Add(row.Credit)
+		b.goldDebit = b.goldDebit.Add(row.GoldDebit)
+		b.goldCredit = b.goldCredit.Add(row.GoldCredit)
+	}
+	sort.Strings(data.codes)
+	return data, nil
+}
+
+func isNegligible(toman []money.Amount, gold ...money.Weight) bool {
+	for _, v := range toman {
+		if !v.IsZero() {
+			return false
+		}
+	}
+	for _, v := range gold {
+		if !v.IsZero() {
+			return false
+		}
+	}
+	return true
+}
+
+// TrialBalance تراز آزمایشی دو ستونی (مانده) یا چهار ستونی (گردش و مانده)
+func (s *reportServiceImpl) TrialBalance(ctx context.Context, filter model.ReportFilter) (*model.TrialBalance, error) {
+	switch filter.Columns {
+	case 0:
+		filter.Columns = 4
+	case 2, 4:
+	default:
+		return nil, fmt.Errorf("%w: trial balance columns must be 2 or 4", ErrValidation)
+	}
+	data, err := s.load(ctx, &filter)
+	if err != nil {
+		return nil, err
+	}
+	from, to := reportRange(filter)
+	tb := &model.TrialBalance{From: from, To: to, Columns: filter.Columns, Level: filter.Level, GoldRate: filter.GoldRate, Rows: []model.TrialBalanceRow{}}
+	rate := filter.GoldRate
+	for _, code := range data.codes {
+		b := data.balances[code]
+		row := model.TrialBalanceRow{
+			AccountCode:        code,
+			AccountName:        data.name(code),
+			Category:           data.category(code),
+			TurnoverDebit:      valued(b.debit, b.goldDebit, rate),
+			TurnoverCredit:     valued(b.credit, b.goldCredit, rate),
+			GoldTurnoverDebit:  b.goldDebit,
+			GoldTurnoverCredit: b.goldCredit,
+			GoldBalance:        b.closingGold(),
+		}
+		if balance := valued(b.closingToman(), b.closingGold(), rate); balance.IsPositive() {
+			row.BalanceDebit = balance
+		} else {
+			row.BalanceCredit = balance.Neg()
+		}
+		if isNegligible([]money.Amount{row.TurnoverDebit, row.TurnoverCredit, row.BalanceDebit, row.BalanceCredit}, row.GoldTurnoverDebit, row.GoldTurnoverCredit, row.GoldBalance) {
+			continue
+		}
+		tb.Rows = append(tb.Rows, row)
+		tb.Totals.TurnoverDebit = tb.Totals.TurnoverDebit.Add(row.TurnoverDebit)
+		tb.Totals.TurnoverCredit = tb.Totals.TurnoverCredit.Add(row.TurnoverCredit)
+		tb.Totals.BalanceDebit = tb.Totals.BalanceDebit.Add(row.BalanceDebit)
+		tb.Totals.BalanceCredit = tb.Totals.BalanceCredit.Add(row.BalanceCredit)
+		tb.Totals.GoldTurnoverDebit = tb.Totals.GoldTurnoverDebit.Add(row.GoldTurnoverDebit)
+		tb.Totals.GoldTurnoverCredit = tb.Totals.GoldTurnoverCredit.Add(row.GoldTurnoverCredit)
+		tb.Totals.GoldBalance = tb.Totals.GoldBalance.Add(row.GoldBalance)
+	}
+	tb.Totals.AccountName = "جمع"
+	return tb, nil
+}
+
+// ProfitLoss سود و زیان بازه فقط از گردش همان بازه حساب‌های درآمد و هزینه محاسبه می‌شود.
+func (s *reportServiceImpl) ProfitLoss(ctx context.Context, filter model.ReportFilter) (*model.ProfitLoss, error) {
+	data, err := s.load(ctx, &filter)
+	if err != nil {
+		return nil, err
+	}
+	from, to := reportRange(filter)
+	pl := &model.ProfitLoss{From: from, To: to, GoldRate: filter.GoldRate, Revenues: []model.FinancialStatementLine{}, Expenses: []model.FinancialStatementLine{}}
+	for _, code := range data.codes {
+		b := data.balances[code]
+		toman, gold := b.debit.Sub(b.credit), b.goldDebit.Sub(b.goldCredit)
+		if isZeroBalance(toman, gold) {
+			continue
+		}
+		switch data.category(code) {
+		case model.AccountCategoryRevenue:
+			line := model.FinancialStatementLine{AccountCode: code, AccountName: data.name(code), Amount: valued(toman, gold, filter.GoldRate).Neg(), Gold: gold.Neg()}
+			pl.Revenues = append(pl.Revenues, line)
+			pl.TotalRevenue = pl.TotalRevenue.Add(line.Amount)
+			pl.TotalRevenueGold = pl.TotalRevenueGold.Add(line.Gold)
+		case model.AccountCategoryExpense:
+			line := model.FinancialStatementLine{AccountCode: code, AccountName: data.name(code), Amount: valued(toman, gold, filter.GoldRate), Gold: gold}
+			pl.Expenses = append(pl.Expenses, line)
+			pl.TotalExpense = pl.TotalExpense.Add(line.Amount)
+			pl.TotalExpenseGold = pl.TotalExpenseGold.Add(line.Gold)
+		}
+	}
+	pl.NetProfit = pl.TotalRevenue.Sub(pl.TotalExpense)
+	pl.NetProfitGold = pl.TotalRevenueGold.Sub(pl.TotalExpenseGold)
+	return pl, nil
+}
+
+// BalanceSheet ترازنامه در پایان بازه؛ مانده حساب‌های درآمد و هزینه که هنوز بسته نشده‌اند به عنوان سود (زیان) جاری در حقوق صاحبان سرمایه می‌آید.
+func (s *reportServiceImpl) BalanceSheet(ctx context.Context, filter model.ReportFilter) (*model.BalanceSheet, error) {
+	data, err := s.load(ctx, &filter)
+	if err != nil {
+		return nil, err
+	}
+	from, to := reportRange(filter)
+	bs := &model.BalanceSheet{From: from, To: to, GoldRate: filter.GoldRate, Assets: []model.FinancialStatementLine{}, Liabilities: []model.FinancialStatementLine{}, Equity: []model.FinancialStatementLine{}}
+	var totalLiabilitiesGold, totalEquityGold money.Weight
+	for _, code := range data.codes {
+		b := data.balances[code]
+		toman, gold := b.closingToman(), b.closingGold()
+		if isZeroBalance(toman, gold) {
+			continue
+		}
+		amount := valued(toman, gold, filter.GoldRate)
+		line := model.FinancialStatementLine{AccountCode: code, AccountName: data.name(code)}
+		switch data.category(code) {
+		case model.AccountCategoryAsset:
+			line.Amount, line.Gold = amount, gold
+			bs.Assets = append(bs.Assets, line)
+			bs.TotalAssets = bs.TotalAssets.Add(line.Amount)
+			bs.TotalAssetsGold = bs.TotalAssetsGold.Add(line.Gold)
+		case model.AccountCategoryLiability:
+			line.Amount, line.Gold = amount.Neg(), gold.Neg()
+			bs.Liabilities = append(bs.Liabilities, line)
+			bs.TotalLiabilities = bs.TotalLiabilities.Add(line.Amount)
+			totalLiabilitiesGold = totalLiabilitiesGold.Add(line.Gold)
+		case model.AccountCategoryEquity:
+			line.Amount, line.Gold = amount.Neg(), gold.Neg()
+			bs.Equity = append(bs.Equity, line)
+			bs.TotalEquity = bs.TotalEquity.Add(line.Amount)
+			totalEquityGold = totalEquityGold.Add(line.Gold)
+		case model.AccountCategoryRevenue, model.AccountCategoryExpense:
+			bs.CurrentProfit = bs.CurrentProfit.Sub(amount)
+			bs.CurrentProfitGold = bs.CurrentProfitGold.Sub(gold)
+		}
+	}
+	bs.TotalEquity = bs.TotalEquity.Add(bs.CurrentProfit)
+	bs.TotalLiabilitiesAndEquity = bs.TotalLiabilities.Add(bs.TotalEquity)
+	bs.TotalLiabilitiesAndEquityGold = totalLiabilitiesGold.Add(totalEquityGold).Add(bs.CurrentProfitGold)
+	return bs, nil
+}
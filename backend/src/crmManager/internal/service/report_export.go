@@ -0,0 +1,211 @@
+package service
+
+import (
+	"fmt"
+
+	"crm-gold/internal/export"
+	"crm-gold/internal/model"
+	"crm-gold/internal/utils"
+
+	"common-gold/money"
+)
+
+func reportSubtitle(from, to string, goldRate money.Amount) []string {
+	sub := []string{fmt.Sprintf("از %s تا %s", from, to)}
+	if goldRate.IsPositive() {
+		sub = append(sub, fmt.Sprintf("ارزش‌گذاری طلا با نرخ %s تومان به ازای هر گرم", goldRate.Decimal().StringFixed(0)))
+	}
+	return sub
+}
+
+// TrialBalanceTable جدول خروجی تراز آزمایشی با همان تعداد ستون گزارش
+func TrialBalanceTable(tb *model.TrialBalance) *export.Table {
+	title := "تراز آزمایشی چهار ستونی"
+	columns := []export.Column{{Header: "کد حساب", Width: 10}, {Header: "نام حساب", Width: 30}}
+	if tb.Columns == 2 {
+		title = "تراز آزمایشی دو ستونی"
+	} else {
+		columns = append(columns,
+			export.Column{Header: "گردش بدهکار", Width: 17, Numeric: true},
+			export.Column{Header: "گردش بستانکار", Width: 17, Numeric: true})
+	}
+	columns = append(columns,
+		export.Column{Header: "مانده بدهکار", Width: 17, Numeric: true},
+		export.Column{Header: "مانده بستانکار", Width: 17, Numeric: true},
+		export.Column{Header: "مانده طلا (گرم)", Width: 13, Numeric: true, Decimals: 3})
+
+	cells := func(r model.TrialBalanceRow) []interface{} {
+		c := []interface{}{r.AccountCode, r.AccountName}
+		if tb.Columns != 2 {
+			c = append(c, r.TurnoverDebit, r.TurnoverCredit)
+		}
+		return append(c, r.BalanceDebit, r.BalanceCredit, r.GoldBalance)
+	}
+	t := &export.Table{Title: title, Subtitle: reportSubtitle(tb.From, tb.To, tb.GoldRate), Columns: columns}
+	for _, r := range tb.Rows {
+		t.Rows = append(t.Rows, export.Row{Cells: cells(r)})
+	}
+	t.Rows = append(t.Rows, export.Row{Cells: cells(tb.Totals), Emphasis: true})
+	return t
+}
+
+var statementColumns = []export.Column{
+	{Header: "کد حساب", Width: 10},
+	{Header: "شرح", Width: 35},
+	{Header: "مبلغ (تومان)", Width: 20, Numeric: true},
+	{Header: "طلا (گرم)", Width: 13, Numeric: true, Decimals: 3},
+}
+
+func statementSection(t *export.Table, title string, lines []model.FinancialStatementLine, totalTitle string, total money.Amount, totalGold money.Weight) {
+	t.Rows = append(t.Rows, export.Row{Cells: []interface{}{"", title}, Emphasis: true})
+	for _, l := range lines {
+		t.Rows = append(t.Rows, export.Row{Cells: []interface{}{l.AccountCode, l.AccountName, l.Amount, l.Gold}})
+	}
+	t.Rows = append(t.Rows, export.Row{Cells: []interface{}{"", totalTitle, total, totalGold}, Emphasis: true})
+}
+
+func ProfitLossTable(pl *model.ProfitLoss) *export.Table {
+	t := &export.Table{Title: "صورت سود و زیان", Subtitle: reportSubtitle(pl.From, pl.To, pl.GoldRate), Columns: statementColumns}
+	statementSection(t, "درآمدها", pl.Revenues, "جمع درآمدها", pl.TotalRevenue, pl.TotalRevenueGold)
+	statementSection(t, "هزینه‌ها", pl.Expenses, "جمع هزینه‌ها", pl.TotalExpense, pl.TotalExpenseGold)
+	t.Rows = append(t.Rows, export.Row{Cells: []interface{}{"", "سود (زیان) خالص", pl.NetProfit, pl.NetProfitGold}, Emphasis: true})
+	return t
+}
+
+func BalanceSheetTable(bs *model.BalanceSheet) *export.Table {
+	t := &export.Table{Title: "ترازنامه", Subtitle: reportSubtitle(bs.From, bs.To, bs.GoldRate), Columns: statementColumns}
+	statementSection(t, "دارایی‌ها", bs.Assets, "جمع دارایی‌ها", bs.TotalAssets, bs.TotalAssetsGold)
+	var liabilitiesGold money.Weight
+	for _, l := range bs.Liabilities {
+		liabilitiesGold = liabilitiesGold.Add(l.Gold)
+	}
+	statementSection(t, "بدهی‌ها", bs.Liabilities, "جمع بدهی‌ها", bs.TotalLiabilities, liabilitiesGold)
+	equity := append(append([]model.FinancialStatementLine{}, bs.Equity...),
+		model.FinancialStatementLine{AccountName: "سود (زیان) سال جاری", Amount: bs.CurrentProfit, Gold: bs.CurrentProfitGold})
+	statementSection(t, "حقوق صاحبان سرمایه", equity, "جمع حقوق صاحبان سرمایه", bs.TotalEquity, bs.TotalLiabilitiesAndEquityGold.Sub(liabilitiesGold))
+	t.Rows = append(t.Rows, export.Row{Cells: []interface{}{"", "جمع بدهی‌ها و حقوق صاحبان سرمایه", bs.TotalLiabilitiesAndEquity, bs.TotalLiabilitiesAndEquityGold}, Emphasis: true})
+	return t
+}
+
+var salesGroupTitles = map[string]string{
+	model.SalesGroupDay:         "روز",
+	model.SalesGroupWeek:        "هفته",
+	model.SalesGroupMonth:       "ماه",
+	model.SalesGroupCategory:    "دسته‌بندی",
+	model.SalesGroupSalesperson: "فروشنده",
+	model.SalesGroupCustomer:    "مشتری",
+}
+
+func SalesReportTable(r *model.SalesReport) *export.Table {
+	columns := []export.Column{
+		{Header: salesGroupTitles[r.GroupBy], Width: 28},
+		{Header: "تعداد فاکتور", Width: 10, Numeric: true},
+		{Header: "وزن (گرم)", Width: 12, Numeric: true, Decimals: 3},
+		{Header: "درآمد", Width: 17, Numeric: true},
+		{Header: "درآمد اجرت", Width: 15, Numeric: true},
+		{Header: "بهای تمام‌شده", Width: 17, Numeric: true},
+		{Header: "حاشیه سود", Width: 17, Numeric: true},
+		{Header: "درصد حاشیه", Width: 10, Numeric: true, Decimals: 2},
+	}
+	cells := func(row model.SalesReportRow) []interface{} {
+		return []interface{}{row.Label, row.InvoiceCount, row.Weight, row.Revenue, row.LaborFee, row.Cost, row.Margin, row.MarginPercent}
+	}
+	t := &export.Table{Title: "گزارش فروش به تفکیک " + salesGroupTitles[r.GroupBy], Subtitle: reportSubtitle(r.From, r.To, money.Amount{}), Columns: columns}
+	for _, row := range r.Rows {
+		t.Rows = append(t.Rows, export.Row{Cells: cells(row)})
+	}
+	t.Rows = append(t.Rows, export.Row{Cells: cells(r.Totals), Emphasis: true})
+	return t
+}
+
+var ownershipTitles = map[string]string{
+	model.InventoryOwned:     "ملکی",
+	model.InventoryConsigned: "امانی",
+}
+
+func InventoryValuationTable(v *model.InventoryValuation) *export.Table {
+	columns := []export.Column{
+		{Header: "دسته‌بندی", Width: 22},
+		{Header: "عیار", Width: 8},
+		{Header: "تعداد", Width: 8, Numeric: true},
+		{Header: "وزن (گرم)", Width: 12, Numeric: true, Decimals: 3},
+		{Header: "وزن خالص (گرم)", Width: 12, Numeric: true, Decimals: 3},
+		{Header: "بهای تمام‌شده", Width: 17, Numeric: true},
+		{Header: "ارزش روز", Width: 17, Numeric: true},
+	}
+	cells := func(label, purity string, r model.InventoryValuationRow) []interface{} {
+		return []interface{}{label, purity, r.ItemCount, r.Weight, r.FineWeight, r.CostValue, r.MarketValue}
+	}
+	t := &export.Table{
+		Title:    "ارزش موجودی کالا",
+		Subtitle: []string{"تا تاریخ " + v.AsOf, fmt.Sprintf("ارزش روز با نرخ %s تومان به ازای هر گرم طلای ۱۸ عیار", v.GoldRate.Decimal().StringFixed(0))},
+		Columns:  columns,
+	}
+	for _, r := range v.Rows {
+		category := r.Category
+		if category == "" {
+			category = "بدون دسته‌بندی"
+		}
+		t.Rows = append(t.Rows, export.Row{Cells: cells(category, fmt.Sprint(r.Purity), r)})
+	}
+	t.Rows = append(t.Rows,
+		export.Row{Cells: cells("موجودی "+ownershipTitles[model.InventoryOwned], "", v.Owned), Emphasis: true},
+		export.Row{Cells: cells("موجودی "+ownershipTitles[model.InventoryConsigned], "", v.Consigned), Emphasis: true},
+		export.Row{Cells: cells("جمع کل", "", v.Totals), Emphasis: true})
+	return t
+}
+
+func SlowMoversTable(sm *model.SlowMovers) *export.Table {
+	columns := []export.Column{
+		{Header: "کد کالا", Width: 12},
+		{Header: "نام کالا", Width: 24},
+		{Header: "دسته‌بندی", Width: 16},
+		{Header: "عیار", Width: 7},
+		{Header: "مالکیت", Width: 8},
+		{Header: "وزن (گرم)", Width: 11, Numeric: true, Decimals: 3},
+		{Header: "بهای تمام‌شده", Width: 15, Numeric: true},
+		{Header: "تاریخ ورود", Width: 11},
+		{Header: "روز در انبار", Width: 9, Numeric: true},
+	}
+	t := &export.Table{
+		Title:    "کالاهای کم‌گردش",
+		Subtitle: []string{fmt.Sprintf("کالاهای فروش‌نرفته در %d روز گذشته تا تاریخ %s", sm.Days, sm.AsOf)},
+		Columns:  columns,
+	}
+	for _, it := range sm.Items {
+		t.Rows = append(t.Rows, export.Row{Cells: []interface{}{
+			it.Code, it.Name, it.Category, fmt.Sprint(it.Purity), ownershipTitles[it.Ownership],
+			it.Weight, it.CostPrice, utils.FormatJalali(it.ReceivedAt), it.DaysInStock,
+		}})
+	}
+	t.Rows = append(t.Rows, export.Row{Cells: []interface{}{"", "جمع", "", "", "", sm.TotalWeight, sm.TotalCost, "", ""}, Emphasis: true})
+	return t
+}
+
+// CurrencyRevaluationTable مبالغ decimal برای خروجی به float تبدیل می‌شوند؛ دقت نمایش از Decimals ستون می‌آید.
+func CurrencyRevaluationTable(r *model.RevaluationReport) *export.Table {
+	columns := []export.Column{
+		{Header: "نوع حساب", Width: 14},
+		{Header: "شناسه حساب", Width: 10},
+		{Header: "ارز", Width: 12},
+		{Header: "مانده ارزی", Width: 15, Numeric: true, Decimals: 2},
+		{Header: "مانده دفتری (تومان)", Width: 17, Numeric: true},
+		{Header: "نرخ", Width: 13, Numeric: true},
+		{Header: "تاریخ نرخ", Width: 11},
+		{Header: "ارزش روز (تومان)", Width: 17, Numeric: true},
+		{Header: "سود (زیان) تسعیر", Width: 17, Numeric: true},
+	}
+	t := &export.Table{Title: "تسعیر ارز", Subtitle: []string{"تا تاریخ " + r.AsOf}, Columns: columns}
+	for _, row := range r.Rows {
+		t.Rows = append(t.Rows, export.Row{Cells: []interface{}{
+			row.AccountKind, fmt.Sprint(row.AccountID), row.CurrencyName, row.ForeignBalance,
+			row.BookBalance, row.Rate, row.RateDate,
+			row.RevaluedValue, row.Difference,
+		}})
+	}
+	t.Rows = append(t.Rows,
+		export.Row{Cells: []interface{}{"", "", "جمع سود تسعیر", nil, nil, nil, "", nil, r.TotalGain}, Emphasis: true},
+		export.Row{Cells: []interface{}{"", "", "جمع زیان تسعیر", nil, nil, nil, "", nil, r.TotalLoss.Neg()}, Emphasis: true},
+		export.Row{Cells: []interface{}{"", "", "خالص", nil, nil, nil, "", nil, r.NetGainLoss}, Emphasis: true})
+	return t
+}
@@ -0,0 +1,549 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"common-gold/audit"
+	"common-gold/money"
+
+	"crm-gold/internal/model"
+	"crm-gold/internal/repository/db/postgresDb"
+	"crm-gold/internal/repository/repo"
+	"crm-gold/internal/utils"
+
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+	"gorm.io/gorm"
+)
+
+// maxSalesReportDays سقف بازه گزارش روزانه و هفتگی
+const maxSalesReportDays = 366
+
+type SaleService interface {
+	CreateInvoice(ctx context.Context, req *model.CreateSaleInvoiceRequest, actor string) (*model.SaleInvoice, error)
+	GetInvoice(ctx context.Context, id uint) (*model.SaleInvoice, error)
+	ListInvoices(ctx context.Context, filter model.SaleInvoiceFilter) ([]model.SaleInvoice, error)
+	VoidInvoice(ctx context.Context, id uint, req *model.VoidSaleInvoiceRequest, actor string) (*model.SaleInvoice, error)
+	SalesReport(ctx context.Context, filter model.SalesReportFilter) (*model.SalesReport, error)
+	SalespersonOf(ctx context.Context, userRef string) (uint, error)
+}
+
+type saleServiceImpl struct {
+	auditRecorder AuditRecorder
+	saleRepo      repo.SaleRepo
+	inventoryRepo repo.InventoryRepo
+	customerRepo  repo.CustRepo
+	ledgerRepo    repo.LedgerRepo
+	taxEngine     TaxEngine
+	converter     CurrencyConverter
+	logger        *zap.Logger
+}
+
+func NewSaleService(saleRepo repo.SaleRepo, inventoryRepo repo.InventoryRepo, customerRepo repo.CustRepo, ledgerRepo repo.LedgerRepo, taxEngine TaxEngine, converter CurrencyConverter, auditRecorder AuditRecorder, logger *zap.Logger) (SaleService, error) {
+	if saleRepo == nil {
+		return nil, errors.New("saleRepository cannot be nil for SaleService")
+	}
+	if inventoryRepo == nil {
+		return nil, errors.New("inventoryRepository cannot be nil for SaleService")
+	}
+	if customerRepo == nil {
+		return nil, errors.New("customerRepository cannot be nil for SaleService")
+	}
+	if ledgerRepo == nil {
+		return nil, errors.New("ledgerRepository cannot be nil for SaleService")
+	}
+	if taxEngine == nil {
+		return nil, errors.New("taxEngine cannot be nil for SaleService")
+	}
+	if converter == nil {
+		return nil, errors.New("currency converter cannot be nil for SaleService")
+	}
+	if auditRecorder == nil {
+		return nil, errors.New("auditRecorder cannot be nil for SaleService")
+	}
+	if logger == nil {
+		return nil, errors.New("logger cannot be nil for SaleService")
+	}
+	return &saleServiceImpl{
+		saleRepo:      saleRepo,
+		inventoryRepo: inventoryRepo,
+		customerRepo:  customerRepo,
+		ledgerRepo:    ledgerRepo,
+		taxEngine:     taxEngine,
+		converter:     converter,
+		auditRecorder: auditRecorder,
+		logger:        logger,
+	}, nil
+}
+
+// CreateInvoice فروشنده در صورت خالی بودن، کاربر ثبت‌کننده فاکتور در نظر گرفته می‌شود.
+func (s *saleServiceImpl) CreateInvoice(ctx context.Context, req *model.CreateSaleInvoiceRequest, actor string) (*model.SaleInvoice, error) {
+	if req.BIDID == 0 {
+		return nil, fmt.Errorf("%w: bidId is required", ErrValidation)
+	}
+	if len(req.Lines) == 0 {
+		return nil, fmt.Errorf("%w: invoice must have at least one line", ErrValidation)
+	}
+	date, err := utils.ParseDateParam(req.Date)
+	if err != nil {
+		return nil, fmt.Errorf("%w: invalid invoice date: %v", ErrValidation, err)
+	}
+	date = utils.StartOfDay(date)
+	customer, err := s.customerRepo.GetCustomerByID(ctx, req.CustomerID)
+	if err != nil {
+		return nil, endpointLoadError(err, model.LedgerAccountPerson, req.CustomerID)
+	}
+	if customer.BIDID != req.BIDID {
+		return nil, fmt.Errorf("%w: customer %d belongs to another business", ErrValidation, customer.ID)
+	}
+	salespersonID := req.SalespersonID
+	if salespersonID == nil {
+		id, err := s.SalespersonOf(ctx, actor)
+		switch {
+		case err == nil:
+			salespersonID = &id
+		case !errors.Is(err, ErrNotFound):
+			return nil, err
+		}
+	}
+
+	items, err := s.loadInvoiceItems(ctx, req)
+	if err != nil {
+		return nil, err
+	}
+	invoice, err := newSaleInvoice(req, items, date, customer.ID, salespersonID, actor)
+	if err != nil {
+		return nil, err
+	}
+	policy, err := s.taxEngine.Policy(ctx, invoice.BIDID, date, customer.ID)
+	if err != nil {
+		return nil, err
+	}
+	applySaleTax(invoice, policy)
+	if req.CurrencyID != nil {
+		rate, err := s.converter.RateAt(ctx, invoice.BIDID, *req.CurrencyID, date)
+		if err != nil {
+			return nil, err
+		}
+		invoice.CurrencyID = req.CurrencyID
+		invoice.ExchangeRate = rate.Rate
+		invoice.CurrencyTotal = invoice.Total.ToForeign(rate.Rate)
+	}
+	created, err := s.saleRepo.CreateSaleInvoice(ctx, invoice, saleInvoiceEntry(invoice, actor))
+	if err != nil {
+		switch {
+		case errors.Is(err, model.ErrFiscalYearClosed):
+			return nil, err
+		case errors.Is(err, postgresDb.ErrInventoryItemNotInStock):
+			return nil, fmt.Errorf("%w: %v", ErrConflict, err)
+		}
+		return nil, fmt.Errorf("failed to save sale invoice: %w", err)
+	}
+	s.logger.Info("Sale invoice created.", zap.Uint("invoice_id", created.ID), zap.String("number", created.Number), zap.String("total", created.Total.String()))
+	return created, nil
+}
+
+// loadInvoiceItems کالاهای موجودی سطرهای فاکتور را بارگذاری و موجود بودن آن‌ها را بررسی می‌کند.
+func (s *saleServiceImpl) loadInvoiceItems(ctx context.Context, req *model.CreateSaleInvoiceRequest) (map[uint]*model.InventoryItem, error) {
+	items := map[uint]*model.InventoryItem{}
+	for i, l := range req.Lines {
+		if l.ItemID == nil {
+			continue
+		}
+		if _, dup := items[*l.ItemID]; dup {
+			return nil, fmt.Errorf("%w: item %d appears in more than one line", ErrValidation, *l.ItemID)
+		}
+		item, err := s.inventoryRepo.GetItemByID(ctx, *l.ItemID)
+		if err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				return nil, fmt.Errorf("%w: line %d item %d does not exist", ErrValidation, i+1, *l.ItemID)
+			}
+			return nil, fmt.Errorf("failed to load inventory item %d: %w", *l.ItemID, err)
+		}
+		if item.BIDID != req.BIDID {
+			return nil, fmt.Errorf("%w: item %d belongs to another business", ErrValidation, item.ID)
+		}
+		if item.Status != model.InventoryItemInStock {
+			return nil, fmt.Errorf("%w: item %d is already %s", ErrConflict, item.ID, item.Status)
+		}
+		items[item.ID] = item
+	}
+	return items, nil
+}
+
+func newSaleInvoice(req *model.CreateSaleInvoiceRequest, items map[uint]*model.InventoryItem, date time.Time, customerID uint, salespersonID *uint, actor string) (*model.SaleInvoice, error) {
+	code, err := utils.GenerateSecureRandomString(8)
+	if err != nil {
+		return nil, fmt.Errorf("failed to generate invoice number: %w", err)
+	}
+	invoice := &model.SaleInvoice{
+		BIDID:         req.BIDID,
+		Number:        "INV-" + code,
+		Date:          date,
+		DateJalali:    utils.FormatJalali(date),
+		CustomerID:    customerID,
+		SalespersonID: salespersonID,
+		Description:   utils.PtrString(strings.TrimSpace(req.Description)),
+		Status:        model.SaleInvoiceIssued,
+		CreatedBy:     actor,
+	}
+	for i, l := range req.Lines {
+		var consignorID *uint
+		if l.ItemID != nil {
+			item := items[*l.ItemID]
+			if strings.TrimSpace(l.Description) == "" {
+				l.Description = item.Name
+			}
+			if strings.TrimSpace(l.Category) == "" {
+				l.Category = item.Category
+			}
+			if l.Weight.IsZero() {
+				l.Weight = item.Weight
+			}
+			if l.Cost.IsZero() {
+				l.Cost = item.CostPrice
+			}
+			if item.Ownership == model.InventoryConsigned {
+				consignorID = item.ConsignorID
+			}
+		}
+		desc := strings.TrimSpace(l.Description)
+		if desc == "" {
+			return nil, fmt.Errorf("%w: line %d description is required", ErrValidation, i+1)
+		}
+		if l.Weight.IsNegative() || l.GoldRate.IsNegative() || l.LaborFee.IsNegative() || l.Profit.IsNegative() || l.Cost.IsNegative() {
+			return nil, fmt.Errorf("%w: line %d amounts cannot be negative", ErrValidation, i+1)
+		}
+		line := model.SaleInvoiceLine{
+			ItemID:      l.ItemID,
+			ConsignorID: consignorID,
+			Description: desc,
+			Category:    strings.TrimSpace(l.Category),
+			Weight:      l.Weight,
+			GoldRate:    l.GoldRate,
+			GoldAmount:  money.AmountFromDecimal(l.Weight.Decimal().Mul(l.GoldRate.Decimal()).Round(0)),
+			LaborFee:    l.LaborFee,
+			Profit:      l.Profit,
+			Cost:        l.Cost,
+		}
+		line.Amount = line.GoldAmount.Add(line.LaborFee).Add(line.Profit)
+		if !line.Amount.IsPositive() {
+			return nil, fmt.Errorf("%w: line %d amount must be positive", ErrValidation, i+1)
+		}
+		invoice.Lines = append(invoice.Lines, line)
+		invoice.TotalWeight = invoice.TotalWeight.Add(line.Weight)
+		invoice.GoldAmount = invoice.GoldAmount.Add(line.GoldAmount)
+		invoice.LaborFee = invoice.LaborFee.Add(line.LaborFee)
+		invoice.Profit = invoice.Profit.Add(line.Profit)
+		invoice.Cost = invoice.Cost.Add(line.Cost)
+		invoice.Total = invoice.Total.Add(line.Amount)
+	}
+	return invoice, nil
+}
+
+// applySaleTax مالیات هر سطر طبق قاعده معتبر در تاریخ فاکتور؛ سود سطر امانی حق‌العمل حساب می‌شود.
+func applySaleTax(invoice *model.SaleInvoice, policy *TaxPolicy) {
+	for i := range invoice.Lines {
+		l := &invoice.Lines[i]
+		c := model.TaxComponents{Gold: l.GoldAmount, Labor: l.LaborFee, Profit: l.Profit}
+		if l.ConsignorID != nil {
+			c.Profit, c.Commission = money.Amount{}, l.Profit
+		}
+		a := policy.Assess(c)
+		l.TaxRate = a.Rate
+		l.TaxAmount = a.Amount
+		invoice.TaxRuleID = a.RuleID
+		invoice.TaxAmount = invoice.TaxAmount.Add(l.TaxAmount)
+	}
+	invoice.Total = invoice.Total.Add(invoice.TaxAmount)
+}
+
+// saleInvoiceEntry فروش: مشتری بدهکار، فروش (طلا و سود)، درآمد اجرت و مالیات پرداختنی بستانکار؛
+// بهای تمام‌شده در همان سند بدهکار می‌شود و طرف آن موجودی کالا یا، برای کالای امانی، حساب امانت‌گذار است.
+func saleInvoiceEntry(invoice *model.SaleInvoice, actor string) *model.LedgerEntry {
+	customerID := invoice.CustomerID
+	desc := "فاکتور فروش " + invoice.Number
+	if invoice.Description != nil {
+		desc = desc + " - " + *invoice.Description
+	}
+	customerLine := model.LedgerLine{AccountKind: model.LedgerAccountPerson, AccountID: &customerID, Debit: invoice.Total, Description: desc}
+	if invoice.CurrencyID != nil {
+		customerLine.CurrencyID = invoice.CurrencyID
+		customerLine.CurrencyAmount = invoice.CurrencyTotal
+	}
+	lines := []model.LedgerLine{customerLine}
+	if sales := invoice.GoldAmount.Add(invoice.Profit); sales.IsPositive() {
+		lines = append(lines, model.LedgerLine{AccountKind: model.LedgerAccountGeneral, AccountCode: model.AccountCodeSales, Credit: sales, Description: desc})
+	}
+	if invoice.LaborFee.IsPositive() {
+		lines = append(lines, model.LedgerLine{AccountKind: model.LedgerAccountGeneral, AccountCode: model.AccountCodeLaborIncome, Credit: invoice.LaborFee, Description: desc})
+	}
+	if invoice.TaxAmount.IsPositive() {
+		lines = append(lines, model.LedgerLine{AccountKind: model.LedgerAccountGeneral, AccountCode: model.AccountCodeVATPayable, Credit: invoice.TaxAmount, Description: desc})
+	}
+	if invoice.Cost.IsPositive() {
+		lines = append(lines, model.LedgerLine{AccountKind: model.LedgerAccountGeneral, AccountCode: model.AccountCodeCOGS, Debit: invoice.Cost, Description: desc})
+		var ownedCost money.Amount
+		consigned := map[uint]money.Amount{}
+		var consignors []uint
+		for _, l := range invoice.Lines {
+			if l.ConsignorID == nil {
+				ownedCost = ownedCost.Add(l.Cost)
+				continue
+			}
+			if _, ok := consigned[*l.ConsignorID]; !ok {
+				consignors = append(consignors, *l.ConsignorID)
+			}
+			consigned[*l.ConsignorID] = consigned[*l.ConsignorID].Add(l.Cost)
+		}
+		if ownedCost.IsPositive() {
+			lines = append(lines, model.LedgerLine{AccountKind: model.LedgerAccountGeneral, AccountCode: model.AccountCodeInventory, Credit: ownedCost, Description: desc})
+		}
+		for _, id := range consignors {
+			if !consigned[id].IsPositive() {
+				continue
+			}
+			consignorID := id
+			lines = append(lines, model.LedgerLine{AccountKind: model.LedgerAccountPerson, AccountID: &consignorID, Credit: consigned[id], Description: desc + " - کالای امانی"})
+		}
+	}
+	return &model.LedgerEntry{
+		BIDID:       invoice.BIDID,
+		DocType:     model.LedgerDocSaleInvoice,
+		Reference:   invoice.Number,
+		Description: desc,
+		PostedAt:    invoice.Date,
+		CreatedBy:   actor,
+		Lines:       lines,
+	}
+}
+
+// SalespersonOf شناسه کارمند متصل به کاربر userRef (UUID داخل JWT)؛ کاربری که کارمند ندارد ErrNotFound می‌گیرد.
+func (s *saleServiceImpl) SalespersonOf(ctx context.Context, userRef string) (uint, error) {
+	ref, err := uuid.Parse(strings.TrimSpace(userRef))
+	if err != nil {
+		return 0, fmt.Errorf("%w: user %q is not linked to an employee", ErrNotFound, userRef)
+	}
+	employee, err := s.customerRepo.GetEmployeeByUserRef(ctx, ref.String())
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return 0, fmt.Errorf("%w: user %s is not linked to an employee", ErrNotFound, ref)
+		}
+		return 0, fmt.Errorf("failed to resolve salesperson: %w", err)
+	}
+	return employee.ID, nil
+}
+
+func (s *saleServiceImpl) GetInvoice(ctx context.Context, id uint) (*model.SaleInvoice, error) {
+	invoice, err := s.saleRepo.GetSaleInvoiceByID(ctx, id)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, fmt.Errorf("%w: sale invoice %d", ErrNotFound, id)
+		}
+		return nil, fmt.Errorf("failed to fetch sale invoice: %w", err)
+	}
+	return invoice, nil
+}
+
+func (s *saleServiceImpl) ListInvoices(ctx context.Context, filter model.SaleInvoiceFilter) ([]model.SaleInvoice, error) {
+	invoices, err := s.saleRepo.ListSaleInvoices(ctx, filter)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list sale invoices: %w", err)
+	}
+	return invoices, nil
+}
+
+// VoidInvoice فاکتور ابطال‌شده حذف نمی‌شود؛ سند معکوس آن در تاریخ ابطال ثبت می‌شود.
+func (s *saleServiceImpl) VoidInvoice(ctx context.Context, id uint, req *model.VoidSaleInvoiceRequest, actor string) (*model.SaleInvoice, error) {
+	invoice, err := s.GetInvoice(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if invoice.Status != model.SaleInvoiceIssued {
+		return nil, fmt.Errorf("%w: invoice is already %s", ErrInvalidTransition, invoice.Status)
+	}
+	if strings.TrimSpace(req.Reason) == "" {
+		return nil, fmt.Errorf("%w: void reason is required", ErrValidation)
+	}
+	at := utils.StartOfDay(time.Now())
+	if req.Date != "" {
+		d, err := utils.ParseDateParam(req.Date)
+		if err != nil {
+			return nil, fmt.Errorf("%w: invalid void date: %v", ErrValidation, err)
+		}
+		at = utils.StartOfDay(d)
+	}
+	if at.Before(invoice.Date) {
+		return nil, fmt.Errorf("%w: void date cannot be before the invoice date", ErrValidation)
+	}
+	if invoice.LedgerEntryID == nil {
+		return nil, fmt.Errorf("sale invoice %d has no ledger entry", invoice.ID)
+	}
+	before := audit.Snapshot(invoice)
+	entry, err := s.ledgerRepo.GetEntryByID(ctx, *invoice.LedgerEntryID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load invoice ledger entry: %w", err)
+	}
+
+	invoice.VoidedAt = &at
+	invoice.VoidedBy = actor
+	invoice.VoidReason = utils.PtrString(strings.TrimSpace(req.Reason))
+	if err := s.saleRepo.VoidSaleInvoice(ctx, invoice, entry.Reversal(at, actor, req.Reason)); err != nil {
+		switch {
+		case errors.Is(err, postgresDb.ErrSaleInvoiceVoided):
+			return nil, fmt.Errorf("%w: %v", ErrConflict, err)
+		case errors.Is(err, model.ErrFiscalYearClosed):
+			return nil, err
+		}
+		return nil, fmt.Errorf("failed to void sale invoice: %w", err)
+	}
+	invoice.Status = model.SaleInvoiceVoid
+	s.logger.Info("Sale invoice voided.", zap.Uint("invoice_id", invoice.ID), zap.String("actor", actor))
+	recordAudit(ctx, s.auditRecorder, s.logger, audit.Event{
+		BusinessID:  &invoice.BIDID,
+		Action:      audit.ActionInvoiceVoid,
+		Category:    audit.CategoryInvoice,
+		TargetType:  "sale_invoice",
+		TargetID:    strconv.FormatUint(uint64(invoice.ID), 10),
+		Description: fmt.Sprintf("ابطال فاکتور فروش %s: %s", invoice.Number, *invoice.VoidReason),
+		Before:      before,
+		After:       audit.Snapshot(invoice),
+	})
+	return invoice, nil
+}
+
+// SalesReport سطرهای فاکتورهای صادرشده را بر اساس روز، هفته (شنبه تا جمعه)، ماه شمسی، دسته‌بندی، فروشنده یا مشتری جمع می‌زند.
+// گروه‌های زمانی به ترتیب تاریخ و بقیه به ترتیب نزولی درآمد مرتب می‌شوند.
+func (s *saleServiceImpl) SalesReport(ctx context.Context, filter model.SalesReportFilter) (*model.SalesReport, error) {
+	if filter.BIDID == 0 {
+		return nil, fmt.Errorf("%w: bidId is required", ErrValidation)
+	}
+	if !filter.To.After(filter.From) {
+		return nil, fmt.Errorf("%w: report range end must be after its start", ErrValidation)
+	}
+	if filter.GroupBy == "" {
+		filter.GroupBy = model.SalesGroupDay
+	}
+	keyOf, err := salesGroupKey(filter.GroupBy)
+	if err != nil {
+		return nil, err
+	}
+	if (filter.GroupBy == model.SalesGroupDay || filter.GroupBy == model.SalesGroupWeek) &&
+		filter.To.Sub(filter.From) > maxSalesReportDays*24*time.Hour {
+		return nil, fmt.Errorf("%w: daily and weekly sales reports are limited to %d days", ErrValidation, maxSalesReportDays)
+	}
+
+	facts, err := s.saleRepo.SaleLineFacts(ctx, filter)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load sales: %w", err)
+	}
+
+	rows := map[string]*model.SalesReportRow{}
+	invoices := map[string]map[uint]struct{}{}
+	var order []string
+	allInvoices := map[uint]struct{}{}
+	report := &model.SalesReport{
+		From:          utils.FormatJalali(filter.From),
+		To:            utils.FormatJalali(filter.To.AddDate(0, 0, -1)),
+		GroupBy:       filter.GroupBy,
+		SalespersonID: filter.SalespersonID,
+		Rows:          []model.SalesReportRow{},
+	}
+	for _, f := range facts {
+		key, label := keyOf(f)
+		row, ok := rows[key]
+		if !ok {
+			row = &model.SalesReportRow{Key: key, Label: label}
+			rows[key] = row
+			invoices[key] = map[uint]struct{}{}
+			order = append(order, key)
+		}
+		addSaleFact(row, f)
+		invoices[key][f.InvoiceID] = struct{}{}
+		addSaleFact(&report.Totals, f)
+		allInvoices[f.InvoiceID] = struct{}{}
+	}
+	for _, key := range order {
+		row := rows[key]
+		row.InvoiceCount = len(invoices[key])
+		finishSalesRow(row)
+		report.Rows = append(report.Rows, *row)
+	}
+	switch filter.GroupBy {
+	case model.SalesGroupDay, model.SalesGroupWeek, model.SalesGroupMonth:
+		sort.SliceStable(report.Rows, func(i, j int) bool { return report.Rows[i].Key < report.Rows[j].Key })
+	default:
+		sort.SliceStable(report.Rows, func(i, j int) bool { return report.Rows[i].Revenue.Cmp(report.Rows[j].Revenue) > 0 })
+	}
+	report.Totals.Label = "جمع"
+	report.Totals.InvoiceCount = len(allInvoices)
+	finishSalesRow(&report.Totals)
+	return report, nil
+}
+
+func addSaleFact(row *model.SalesReportRow, f model.SaleLineFact) {
+	row.Weight = row.Weight.Add(f.Weight)
+	row.Revenue = row.Revenue.Add(f.Amount)
+	row.LaborFee = row.LaborFee.Add(f.LaborFee)
+	row.Cost = row.Cost.Add(f.Cost)
+}
+
+func finishSalesRow(row *model.SalesReportRow) {
+	row.Margin = row.Revenue.Sub(row.Cost)
+	if row.Revenue.IsPositive() {
+		row.MarginPercent = row.Margin.Decimal().Div(row.Revenue.Decimal()).Shift(2).Round(2).InexactFloat64()
+	}
+}
+
+// salesGroupKey کلید و عنوان گروه هر سطر؛ کلیدهای زمانی تاریخ شمسی قابل مرتب‌سازی هستند.
+func salesGroupKey(groupBy string) (func(model.SaleLineFact) (string, string), error) {
+	switch groupBy {
+	case model.SalesGroupDay:
+		return func(f model.SaleLineFact) (string, string) {
+			d := utils.FormatJalali(f.Date)
+			return d, d
+		}, nil
+	case model.SalesGroupWeek:
+		return func(f model.SaleLineFact) (string, string) {
+			day := utils.StartOfDay(f.Date)
+			// هفته شمسی از شنبه شروع می‌شود
+			start := day.AddDate(0, 0, -((int(day.Weekday()) + 1) % 7))
+			d := utils.FormatJalali(start)
+			return d, "هفته " + d + " تا " + utils.FormatJalali(start.AddDate(0, 0, 6))
+		}, nil
+	case model.SalesGroupMonth:
+		return func(f model.SaleLineFact) (string, string) {
+			jy, jm, _ := utils.GregorianToJalali(f.Date)
+			return fmt.Sprintf("%04d/%02d", jy, jm), fmt.Sprintf("%s %d", utils.JalaliMonthNames[jm], jy)
+		}, nil
+	case model.SalesGroupCategory:
+		return func(f model.SaleLineFact) (string, string) {
+			if f.Category == "" {
+				return "", "بدون دسته‌بندی"
+			}
+			return f.Category, f.Category
+		}, nil
+	case model.SalesGroupSalesperson:
+		return func(f model.SaleLineFact) (string, string) {
+			if f.SalespersonID == nil {
+				return "", "بدون فروشنده"
+			}
+			key := strconv.FormatUint(uint64(*f.SalespersonID), 10)
+			if f.SalespersonName == "" {
+				return key, "کارمند " + key
+			}
+			return key, f.SalespersonName
+		}, nil
+	case model.SalesGroupCustomer:
+		return func(f model.SaleLineFact) (string, string) {
+			return strconv.FormatUint(uint64(f.CustomerID), 10), f.CustomerName
+		}, nil
+	}
+	return nil, fmt.Errorf("%w: groupBy must be one of day, week, month, category, salesperson or customer", ErrValidation)
+}
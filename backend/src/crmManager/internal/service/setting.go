@@ -0,0 +1,104 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strconv"
+	"time"
+
+	"common-gold/audit"
+	"common-gold/money"
+
+	"crm-gold/internal/model"
+	"crm-gold/internal/repository/repo"
+
+	"go.uber.org/zap"
+)
+
+type SettingService interface {
+	GetBusinessSetting(ctx context.Context, bidID uint) (*model.BusinessSetting, error)
+	UpdateBusinessSetting(ctx context.Context, req *model.UpdateBusinessSettingRequest, actor string) (*model.BusinessSetting, error)
+	UpdateGoldRate(ctx context.Context, req *model.UpdateGoldRateRequest, actor string) (*model.BusinessSetting, error)
+}
+
+type settingServiceImpl struct {
+	settingRepo   repo.SettingRepo
+	auditRecorder AuditRecorder
+	logger        *zap.Logger
+}
+
+func NewSettingService(settingRepo repo.SettingRepo, auditRecorder AuditRecorder, logger *zap.Logger) (SettingService, error) {
+	if settingRepo == nil {
+		return nil, errors.New("settingRepository cannot be nil for SettingService")
+	}
+	if auditRecorder == nil {
+		return nil, errors.New("auditRecorder cannot be nil for SettingService")
+	}
+	if logger == nil {
+		return nil, errors.New("logger cannot be nil for SettingService")
+	}
+	return &settingServiceImpl{settingRepo: settingRepo, auditRecorder: auditRecorder, logger: logger}, nil
+}
+
+func (s *settingServiceImpl) GetBusinessSetting(ctx context.Context, bidID uint) (*model.BusinessSetting, error) {
+	if bidID == 0 {
+		return nil, fmt.Errorf("%w: bidId is required", ErrValidation)
+	}
+	setting, err := s.settingRepo.GetBusinessSetting(ctx, bidID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch business setting: %w", err)
+	}
+	return setting, nil
+}
+
+// UpdateBusinessSetting فقط فیلدهای ارسال‌شده را تغییر می‌دهد.
+func (s *settingServiceImpl) UpdateBusinessSetting(ctx context.Context, req *model.UpdateBusinessSettingRequest, actor string) (*model.BusinessSetting, error) {
+	setting, err := s.GetBusinessSetting(ctx, req.BIDID)
+	if err != nil {
+		return nil, err
+	}
+	if req.RequireSufficientBalance != nil {
+		setting.RequireSufficientBalance = *req.RequireSufficientBalance
+	}
+	if err := s.settingRepo.SaveBusinessSetting(ctx, setting); err != nil {
+		return nil, fmt.Errorf("failed to save business setting: %w", err)
+	}
+	s.logger.Info("Business setting updated.", zap.Uint("bid_id", setting.BIDID), zap.String("actor", actor))
+	return setting, nil
+}
+
+func (s *settingServiceImpl) UpdateGoldRate(ctx context.Context, req *model.UpdateGoldRateRequest, actor string) (*model.BusinessSetting, error) {
+	if !req.Rate.IsPositive() {
+		return nil, fmt.Errorf("%w: gold rate must be positive", ErrValidation)
+	}
+	setting, err := s.GetBusinessSetting(ctx, req.BIDID)
+	if err != nil {
+		return nil, err
+	}
+	before := goldRateSnapshot{Rate: setting.GoldRate, UpdatedAt: setting.GoldRateUpdatedAt}
+	now := time.Now()
+	setting.GoldRate = req.Rate
+	setting.GoldRateUpdatedAt = &now
+	if err := s.settingRepo.SaveBusinessSetting(ctx, setting); err != nil {
+		return nil, fmt.Errorf("failed to save gold rate: %w", err)
+	}
+	s.logger.Info("Gold rate updated.", zap.Uint("bid_id", setting.BIDID), zap.String("rate", req.Rate.String()), zap.String("actor", actor))
+	recordAudit(ctx, s.auditRecorder, s.logger, audit.Event{
+		BusinessID:  &setting.BIDID,
+		Action:      audit.ActionPriceOverride,
+		Category:    audit.CategoryPrice,
+		TargetType:  "gold_rate",
+		TargetID:    strconv.FormatUint(uint64(setting.BIDID), 10),
+		Description: fmt.Sprintf("تغییر نرخ طلا از %s به %s", before.Rate, req.Rate),
+		Before:      audit.Snapshot(before),
+		After:       audit.Snapshot(goldRateSnapshot{Rate: setting.GoldRate, UpdatedAt: setting.GoldRateUpdatedAt}),
+	})
+	return setting, nil
+}
+
+// goldRateSnapshot تصویر نرخ طلا در دفتر رویداد
+type goldRateSnapshot struct {
+	Rate      money.Amount `json:"goldRate"`
+	UpdatedAt *time.Time   `json:"goldRateUpdatedAt,omitempty"`
+}
@@ -0,0 +1,85 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"crm-gold/internal/model"
+	"crm-gold/internal/repository/repo"
+	"crm-gold/internal/utils"
+)
+
+// buildAccountStatement گردش حساب با مانده تجمعی را برای بازه [from, to] (شامل روز پایانی) می‌سازد.
+func buildAccountStatement(ctx context.Context, ledgerRepo repo.LedgerRepo, accountKind string, accountID uint, currencyID *uint, from, to time.Time) (*model.AccountStatement, error) {
+	from = utils.StartOfDay(from)
+	toExclusive := utils.StartOfDay(to).AddDate(0, 0, 1)
+	if !from.Before(toExclusive) {
+		return nil, fmt.Errorf("%w: statement start date must not be after end date", ErrValidation)
+	}
+
+	opening, err := ledgerRepo.GetAccountBalance(ctx, accountKind, accountID, currencyID, from)
+	if err != nil {
+		return nil, fmt.Errorf("failed to compute opening balance: %w", err)
+	}
+	lines, err := ledgerRepo.GetAccountLines(ctx, accountKind, accountID, currencyID, from, toExclusive)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load statement lines: %w", err)
+	}
+
+	statement := &model.AccountStatement{
+		AccountKind:    accountKind,
+		AccountID:      accountID,
+		CurrencyID:     currencyID,
+		From:           utils.FormatJalali(from),
+		To:             utils.FormatJalali(to),
+		OpeningBalance: opening,
+		Lines:          make([]model.StatementLine, 0, len(lines)),
+	}
+	// ستون‌های تومانی همیشه پر می‌شوند؛ در صورت‌حساب یک ارز، گردش به واحد همان ارز کنار آن‌ها می‌آید.
+	balance := opening
+	var currency *model.StatementCurrencyTotals
+	if currencyID != nil {
+		openingForeign, err := ledgerRepo.GetCurrencyBalance(ctx, accountKind, accountID, *currencyID, from)
+		if err != nil {
+			return nil, fmt.Errorf("failed to compute opening currency balance: %w", err)
+		}
+		currency = &model.StatementCurrencyTotals{OpeningBalance: openingForeign, ClosingBalance: openingForeign}
+		statement.Currency = currency
+	}
+	for _, l := range lines {
+		balance = balance.Add(l.Debit).Sub(l.Credit)
+		statement.TotalDebit = statement.TotalDebit.Add(l.Debit)
+		statement.TotalCredit = statement.TotalCredit.Add(l.Credit)
+		line := model.StatementLine{
+			EntryID:     l.EntryID,
+			Date:        l.PostedAt,
+			DateJalali:  utils.FormatJalali(l.PostedAt),
+			DocType:     l.DocType,
+			DocID:       l.DocID,
+			Reference:   l.Reference,
+			Description: l.Description,
+			Debit:       l.Debit,
+			Credit:      l.Credit,
+			Balance:     balance,
+		}
+		if currency != nil {
+			// مبلغ ارزی سطر بی‌علامت است؛ جهت آن از ستون تومانی می‌آید.
+			cl := &model.StatementCurrencyLine{}
+			if l.Debit.IsPositive() {
+				cl.Debit = l.CurrencyAmount
+				currency.TotalDebit = currency.TotalDebit.Add(cl.Debit)
+				currency.ClosingBalance = currency.ClosingBalance.Add(cl.Debit)
+			} else {
+				cl.Credit = l.CurrencyAmount
+				currency.TotalCredit = currency.TotalCredit.Add(cl.Credit)
+				currency.ClosingBalance = currency.ClosingBalance.Sub(cl.Credit)
+			}
+			cl.Balance = currency.ClosingBalance
+			line.Currency = cl
+		}
+		statement.Lines = append(statement.Lines, line)
+	}
+	statement.ClosingBalance = balance
+	return statement, nil
+}
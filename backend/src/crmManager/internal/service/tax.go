@@ -0,0 +1,501 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"crm-gold/internal/moadian"
+	"crm-gold/internal/model"
+	"crm-gold/internal/repository/db/postgresDb"
+	"crm-gold/internal/repository/repo"
+	"crm-gold/internal/utils"
+
+	"common-gold/money"
+
+	"go.uber.org/zap"
+	"gorm.io/gorm"
+)
+
+// maxTaxSendAttempts سقف ارسال دوباره خودکار صورتحسابی که به سامانه نرسیده است.
+const maxTaxSendAttempts = 5
+
+type TaxService interface {
+	GetSettings(ctx context.Context, bidID uint) (*model.TaxSetting, error)
+	UpdateSettings(ctx context.Context, req *model.UpdateTaxSettingRequest, actor string) (*model.TaxSetting, error)
+	SubmitInvoice(ctx context.Context, bidID, saleInvoiceID uint, actor string) (*model.TaxSubmission, error)
+	GetSubmission(ctx context.Context, bidID, id uint) (*model.TaxSubmission, error)
+	ListSubmissions(ctx context.Context, filter model.TaxSubmissionFilter) ([]model.TaxSubmission, error)
+	RefreshSubmission(ctx context.Context, bidID, id uint) (*model.TaxSubmission, error)
+	ProcessPending(ctx context.Context) (int, error)
+}
+
+type taxServiceImpl struct {
+	taxRepo       repo.TaxRepo
+	saleRepo      repo.SaleRepo
+	customerRepo  repo.CustRepo
+	clientFactory moadian.ClientFactory
+	keyBox        *utils.SecretBox
+	logger        *zap.Logger
+}
+
+// NewTaxService کلید خصوصی حافظه مالیاتی با keyBox رمز شده ذخیره و فقط هنگام امضا باز می‌شود.
+func NewTaxService(taxRepo repo.TaxRepo, saleRepo repo.SaleRepo, customerRepo repo.CustRepo, clientFactory moadian.ClientFactory, keyBox *utils.SecretBox, logger *zap.Logger) (TaxService, error) {
+	if taxRepo == nil {
+		return nil, errors.New("taxRepository cannot be nil for TaxService")
+	}
+	if saleRepo == nil {
+		return nil, errors.New("saleRepository cannot be nil for TaxService")
+	}
+	if customerRepo == nil {
+		return nil, errors.New("customerRepository cannot be nil for TaxService")
+	}
+	if clientFactory == nil {
+		return nil, errors.New("moadian client factory cannot be nil for TaxService")
+	}
+	if keyBox == nil {
+		return nil, errors.New("key box cannot be nil for TaxService")
+	}
+	if logger == nil {
+		return nil, errors.New("logger cannot be nil for TaxService")
+	}
+	return &taxServiceImpl{
+		taxRepo:       taxRepo,
+		saleRepo:      saleRepo,
+		customerRepo:  customerRepo,
+		clientFactory: clientFactory,
+		keyBox:        keyBox,
+		logger:        logger,
+	}, nil
+}
+
+func (s *taxServiceImpl) GetSettings(ctx context.Context, bidID uint) (*model.TaxSetting, error) {
+	if bidID == 0 {
+		return nil, fmt.Errorf("%w: bidId is required", ErrValidation)
+	}
+	setting, err := s.taxRepo.GetTaxSetting(ctx, bidID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch tax setting: %w", err)
+	}
+	return setting, nil
+}
+
+// UpdateSettings فقط فیلدهای ارسال‌شده تغییر می‌کنند؛ فعال‌سازی بدون شناسه حافظه، کد اقتصادی و کلید خصوصی مجاز نیست.
+func (s *taxServiceImpl) UpdateSettings(ctx context.Context, req *model.UpdateTaxSettingRequest, actor string) (*model.TaxSetting, error) {
+	setting, err := s.GetSettings(ctx, req.BIDID)
+	if err != nil {
+		return nil, err
+	}
+	if req.TaxMemoryID != nil {
+		memoryID := strings.ToUpper(strings.TrimSpace(*req.TaxMemoryID))
+		if len(memoryID) != model.TaxMemoryIDLength || strings.Trim(memoryID, "ABCDEFGHIJKLMNOPQRSTUVWXYZ0123456789") != "" {
+			return nil, fmt.Errorf("%w: tax memory id must be %d letters or digits", ErrValidation, model.TaxMemoryIDLength)
+		}
+		setting.TaxMemoryID = memoryID
+	}
+	if req.EconomicCode != nil {
+		setting.EconomicCode = strings.TrimSpace(utils.NormalizeDigits(*req.EconomicCode))
+	}
+	if req.PrivateKey != nil {
+		key := strings.TrimSpace(*req.PrivateKey)
+		if key != "" {
+			if _, err := moadian.ParsePrivateKey(key); err != nil {
+				return nil, fmt.Errorf("%w: %v", ErrValidation, err)
+			}
+		}
+		setting.PrivateKey = key
+	}
+	if req.ProductID != nil {
+		setting.ProductID = strings.TrimSpace(utils.NormalizeDigits(*req.ProductID))
+	}
+	if req.MeasureUnit != nil {
+		setting.MeasureUnit = strings.TrimSpace(*req.MeasureUnit)
+	}
+	if req.Enabled != nil {
+		setting.Enabled = *req.Enabled
+	}
+	if setting.Enabled && (setting.TaxMemoryID == "" || setting.EconomicCode == "" || setting.PrivateKey == "") {
+		return nil, fmt.Errorf("%w: tax memory id, economic code and private key are required to enable tax submission", ErrValidation)
+	}
+	// کلیدی که پیش از رمزنگاری ذخیره شده بود هم در اولین ذخیره رمز می‌شود.
+	if !utils.IsSealed(setting.PrivateKey) {
+		sealed, err := s.keyBox.Seal(setting.PrivateKey)
+		if err != nil {
+			return nil, fmt.Errorf("failed to encrypt tax private key: %w", err)
+		}
+		setting.PrivateKey = sealed
+	}
+	if err := s.taxRepo.SaveTaxSetting(ctx, setting); err != nil {
+		return nil, fmt.Errorf("failed to save tax setting: %w", err)
+	}
+	setting.HasPrivateKey = setting.PrivateKey != ""
+	s.logger.Info("Tax setting updated.", zap.Uint("bid_id", setting.BIDID), zap.Bool("enabled", setting.Enabled), zap.String("actor", actor))
+	return setting, nil
+}
+
+// SubmitInvoice فاکتور صادرشده به‌صورت اصلی و فاکتور ابطال‌شده به‌صورت ابطالِ صورتحساب اصلیِ پذیرفته‌شده ارسال می‌شود.
+// خطای ارسال ثبت می‌شود و کار زمان‌بندی‌شده آن را دوباره می‌فرستد.
+func (s *taxServiceImpl) SubmitInvoice(ctx context.Context, bidID, saleInvoiceID uint, actor string) (*model.TaxSubmission, error) {
+	if bidID == 0 {
+		return nil, fmt.Errorf("%w: bidId is required", ErrValidation)
+	}
+	invoice, err := s.saleRepo.GetSaleInvoiceByID(ctx, saleInvoiceID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, fmt.Errorf("%w: sale invoice %d", ErrNotFound, saleInvoiceID)
+		}
+		return nil, fmt.Errorf("failed to fetch sale invoice: %w", err)
+	}
+	if invoice.BIDID != bidID {
+		return nil, fmt.Errorf("%w: sale invoice %d", ErrNotFound, saleInvoiceID)
+	}
+	setting, err := s.enabledSettings(ctx, invoice.BIDID)
+	if err != nil {
+		return nil, err
+	}
+	client, signer, err := s.clientFor(setting)
+	if err != nil {
+		return nil, err
+	}
+	previous, err := s.taxRepo.ListTaxSubmissions(ctx, model.TaxSubmissionFilter{SaleInvoiceID: invoice.ID})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list previous tax submissions: %w", err)
+	}
+
+	subject := moadian.SubjectOriginal
+	if invoice.Status == model.SaleInvoiceVoid {
+		subject = moadian.SubjectCancel
+	}
+	var referenceTaxID string
+	for _, p := range previous {
+		if p.Subject == subject && p.Status != model.TaxSubmissionFailed {
+			return nil, fmt.Errorf("%w: invoice already has a %s submission (%d)", ErrConflict, p.Status, p.ID)
+		}
+		if subject == moadian.SubjectCancel && p.Subject == moadian.SubjectOriginal && p.Status == model.TaxSubmissionSuccess {
+			referenceTaxID = p.TaxID
+		}
+	}
+	if subject == moadian.SubjectCancel && referenceTaxID == "" {
+		return nil, fmt.Errorf("%w: void invoice was never accepted by the tax system, nothing to cancel", ErrInvalidTransition)
+	}
+
+	customer, err := s.customerRepo.GetCustomerByID(ctx, invoice.CustomerID)
+	if err != nil {
+		return nil, endpointLoadError(err, model.LedgerAccountPerson, invoice.CustomerID)
+	}
+
+	submission := &model.TaxSubmission{
+		BIDID:          invoice.BIDID,
+		SaleInvoiceID:  invoice.ID,
+		Subject:        subject,
+		ReferenceTaxID: referenceTaxID,
+		Status:         model.TaxSubmissionPending,
+		CreatedBy:      actor,
+	}
+	if err := s.taxRepo.CreateTaxSubmission(ctx, submission); err != nil {
+		if errors.Is(err, postgresDb.ErrTaxSubmissionExists) {
+			return nil, fmt.Errorf("%w: %v", ErrConflict, err)
+		}
+		return nil, fmt.Errorf("failed to save tax submission: %w", err)
+	}
+	submission.TaxID, err = moadian.GenerateTaxID(setting.TaxMemoryID, invoice.Date, uint64(submission.ID))
+	if err != nil {
+		submission.Status = model.TaxSubmissionFailed
+		submission.Errors = utils.PtrString(err.Error())
+		if uerr := s.taxRepo.UpdateTaxSubmission(ctx, submission); uerr != nil {
+			s.logger.Error("Failed to mark tax submission as failed", zap.Uint("submission_id", submission.ID), zap.Error(uerr))
+		}
+		return nil, fmt.Errorf("%w: %v", ErrValidation, err)
+	}
+	payload := buildTaxInvoice(invoice, customer, setting, submission)
+	data, err := json.Marshal(payload)
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode tax invoice: %w", err)
+	}
+	submission.Payload = string(data)
+	submission.UID = fmt.Sprintf("%s-%d", setting.TaxMemoryID, submission.ID)
+
+	s.send(ctx, client, signer, setting, submission, payload)
+	if err := s.taxRepo.UpdateTaxSubmission(ctx, submission); err != nil {
+		return nil, fmt.Errorf("failed to update tax submission: %w", err)
+	}
+	s.logger.Info("Sale invoice submitted to tax system.", zap.Uint("invoice_id", invoice.ID), zap.Uint("submission_id", submission.ID),
+		zap.String("tax_id", submission.TaxID), zap.String("status", submission.Status), zap.String("actor", actor))
+	return submission, nil
+}
+
+// buildTaxInvoice صورتحساب الگوی طلا؛ مبالغ فاکتور تومان هستند و به ریال تبدیل می‌شوند. سود سطرهای امانی حق‌العمل حساب می‌شود
+// و نرخ و مبلغ مالیات همان است که موتور قواعد هنگام صدور فاکتور ثبت کرده است.
+func buildTaxInvoice(invoice *model.SaleInvoice, customer *model.Customer, setting *model.TaxSetting, submission *model.TaxSubmission) moadian.Invoice {
+	header := moadian.Header{
+		TaxID:        submission.TaxID,
+		IssuedAt:     invoice.Date.UnixMilli(),
+		CreatedAt:    time.Now().UnixMilli(),
+		Type:         moadian.InvoiceTypeWithBuyer,
+		Serial:       invoice.Number,
+		ReferenceTax: submission.ReferenceTaxID,
+		Pattern:      moadian.PatternGold,
+		Subject:      submission.Subject,
+		SellerTIN:    setting.EconomicCode,
+		BuyerType:    moadian.BuyerNatural,
+		BuyerID:      customer.Shenasemeli,
+		Settlement:   moadian.SettlementCredit,
+	}
+	if customer.Company != nil && strings.TrimSpace(*customer.Company) != "" {
+		header.BuyerType = moadian.BuyerLegal
+	}
+	switch {
+	case customer.Codeeghtesadi != nil && *customer.Codeeghtesadi != "":
+		header.BuyerTIN = *customer.Codeeghtesadi
+	case customer.TaxID != nil && *customer.TaxID != "":
+		header.BuyerTIN = *customer.TaxID
+	}
+	if customer.Postalcode != nil {
+		header.BuyerPostal = *customer.Postalcode
+	}
+
+	unit := setting.MeasureUnit
+	if unit == "" {
+		unit = model.DefaultTaxMeasureUnit
+	}
+	result := moadian.Invoice{Header: header, Body: make([]moadian.Item, 0, len(invoice.Lines)), Payments: []moadian.Payment{}}
+	for _, l := range invoice.Lines {
+		profit, commission := tomanToRial(l.Profit), int64(0)
+		if l.ConsignorID != nil {
+			profit, commission = 0, profit
+		}
+		result.Body = append(result.Body, moadian.GoldItem(setting.ProductID, unit, l.Description, l.Weight.Float64(),
+			tomanToRial(l.GoldRate), tomanToRial(l.LaborFee), profit, commission, l.TaxRate, tomanToRial(l.TaxAmount)))
+	}
+	result.Totalize()
+	return result
+}
+
+func tomanToRial(v money.Amount) int64 {
+	return v.Decimal().Shift(1).Round(0).IntPart()
+}
+
+// send نتیجه ارسال روی submission ثبت می‌شود: شماره پیگیری یعنی sent، خطای سامانه یعنی failed و خطای شبکه pending می‌ماند.
+func (s *taxServiceImpl) send(ctx context.Context, client moadian.Client, signer *moadian.Signer, setting *model.TaxSetting, submission *model.TaxSubmission, payload moadian.Invoice) {
+	submission.Attempts++
+	now := time.Now()
+	submission.SubmittedAt = &now
+	signature, err := signer.SignData(payload)
+	if err != nil {
+		submission.Errors = utils.PtrString(err.Error())
+		return
+	}
+	results, err := client.SendInvoices(ctx, []moadian.Packet{{
+		UID:           submission.UID,
+		PacketType:    moadian.PacketTypeInvoice,
+		Retry:         submission.Attempts > 1,
+		FiscalID:      setting.TaxMemoryID,
+		Data:          payload,
+		DataSignature: signature,
+	}})
+	if err != nil {
+		s.logger.Warn("Tax invoice send failed", zap.Uint("submission_id", submission.ID), zap.Int("attempts", submission.Attempts), zap.Error(err))
+		submission.Errors = utils.PtrString(err.Error())
+		return
+	}
+	if len(results) == 0 {
+		submission.Errors = utils.PtrString("tax system returned no result for the packet")
+		return
+	}
+	res := results[0]
+	if res.ErrorCode != "" || res.ReferenceNumber == "" {
+		submission.Status = model.TaxSubmissionFailed
+		submission.Errors = utils.PtrString(strings.TrimSpace(res.ErrorCode + " " + res.ErrorDetail))
+		return
+	}
+	submission.Status = model.TaxSubmissionSent
+	submission.ReferenceNumber = res.ReferenceNumber
+	submission.Errors = nil
+}
+
+func (s *taxServiceImpl) enabledSettings(ctx context.Context, bidID uint) (*model.TaxSetting, error) {
+	setting, err := s.GetSettings(ctx, bidID)
+	if err != nil {
+		return nil, err
+	}
+	if !setting.Enabled {
+		return nil, fmt.Errorf("%w: tax submission is not enabled for this business", ErrValidation)
+	}
+	return setting, nil
+}
+
+func (s *taxServiceImpl) clientFor(setting *model.TaxSetting) (moadian.Client, *moadian.Signer, error) {
+	key, err := s.keyBox.Open(setting.PrivateKey)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to decrypt tax private key: %w", err)
+	}
+	signer, err := moadian.NewSigner(key)
+	if err != nil {
+		return nil, nil, fmt.Errorf("%w: invalid tax private key: %v", ErrValidation, err)
+	}
+	client, err := s.clientFactory(setting.TaxMemoryID, signer)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to create moadian client: %w", err)
+	}
+	return client, signer, nil
+}
+
+// GetSubmission ارسال کسب‌وکار دیگر مانند ارسال ناموجود گزارش می‌شود.
+func (s *taxServiceImpl) GetSubmission(ctx context.Context, bidID, id uint) (*model.TaxSubmission, error) {
+	if bidID == 0 {
+		return nil, fmt.Errorf("%w: bidId is required", ErrValidation)
+	}
+	submission, err := s.taxRepo.GetTaxSubmissionByID(ctx, id)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, fmt.Errorf("%w: tax submission %d", ErrNotFound, id)
+		}
+		return nil, fmt.Errorf("failed to fetch tax submission: %w", err)
+	}
+	if submission.BIDID != bidID {
+		return nil, fmt.Errorf("%w: tax submission %d", ErrNotFound, id)
+	}
+	return submission, nil
+}
+
+func (s *taxServiceImpl) ListSubmissions(ctx context.Context, filter model.TaxSubmissionFilter) ([]model.TaxSubmission, error) {
+	if filter.BIDID == 0 {
+		return nil, fmt.Errorf("%w: bidId is required", ErrValidation)
+	}
+	submissions, err := s.taxRepo.ListTaxSubmissions(ctx, filter)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list tax submissions: %w", err)
+	}
+	return submissions, nil
+}
+
+// RefreshSubmission وضعیت یک ارسال را همان لحظه از سامانه استعلام می‌کند؛ ارسال ناموفق شبکه دوباره فرستاده می‌شود.
+func (s *taxServiceImpl) RefreshSubmission(ctx context.Context, bidID, id uint) (*model.TaxSubmission, error) {
+	submission, err := s.GetSubmission(ctx, bidID, id)
+	if err != nil {
+		return nil, err
+	}
+	if submission.Status != model.TaxSubmissionSent && submission.Status != model.TaxSubmissionPending {
+		return submission, nil
+	}
+	setting, err := s.enabledSettings(ctx, submission.BIDID)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.process(ctx, setting, []*model.TaxSubmission{submission}); err != nil {
+		return nil, err
+	}
+	return submission, nil
+}
+
+// ProcessPending کار زمان‌بندی‌شده: ارسال‌های در صف سامانه استعلام و ارسال‌های ناموفق شبکه دوباره فرستاده می‌شوند.
+func (s *taxServiceImpl) ProcessPending(ctx context.Context) (int, error) {
+	var open []model.TaxSubmission
+	for _, status := range []string{model.TaxSubmissionSent, model.TaxSubmissionPending} {
+		list, err := s.taxRepo.ListTaxSubmissions(ctx, model.TaxSubmissionFilter{Status: status})
+		if err != nil {
+			return 0, fmt.Errorf("failed to list %s tax submissions: %w", status, err)
+		}
+		open = append(open, list...)
+	}
+	byBusiness := map[uint][]*model.TaxSubmission{}
+	var order []uint
+	for i := range open {
+		sub := &open[i]
+		if sub.Status == model.TaxSubmissionPending && sub.Attempts >= maxTaxSendAttempts {
+			continue
+		}
+		if _, ok := byBusiness[sub.BIDID]; !ok {
+			order = append(order, sub.BIDID)
+		}
+		byBusiness[sub.BIDID] = append(byBusiness[sub.BIDID], sub)
+	}
+
+	processed := 0
+	var errs []error
+	for _, bidID := range order {
+		setting, err := s.enabledSettings(ctx, bidID)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("business %d: %w", bidID, err))
+			continue
+		}
+		if err := s.process(ctx, setting, byBusiness[bidID]); err != nil {
+			errs = append(errs, fmt.Errorf("business %d: %w", bidID, err))
+			continue
+		}
+		processed += len(byBusiness[bidID])
+	}
+	if processed > 0 {
+		s.logger.Info("Tax submissions processed.", zap.Int("count", processed))
+	}
+	return processed, errors.Join(errs...)
+}
+
+func (s *taxServiceImpl) process(ctx context.Context, setting *model.TaxSetting, submissions []*model.TaxSubmission) error {
+	client, signer, err := s.clientFor(setting)
+	if err != nil {
+		return err
+	}
+	sent := map[string]*model.TaxSubmission{}
+	var refs []string
+	for _, sub := range submissions {
+		switch sub.Status {
+		case model.TaxSubmissionSent:
+			sent[sub.ReferenceNumber] = sub
+			refs = append(refs, sub.ReferenceNumber)
+		case model.TaxSubmissionPending:
+			var payload moadian.Invoice
+			if err := json.Unmarshal([]byte(sub.Payload), &payload); err != nil {
+				return fmt.Errorf("failed to decode payload of tax submission %d: %w", sub.ID, err)
+			}
+			s.send(ctx, client, signer, setting, sub, payload)
+			if err := s.taxRepo.UpdateTaxSubmission(ctx, sub); err != nil {
+				return fmt.Errorf("failed to update tax submission: %w", err)
+			}
+		}
+	}
+	if len(refs) == 0 {
+		return nil
+	}
+	results, err := client.InquiryByReference(ctx, refs)
+	if err != nil {
+		return fmt.Errorf("tax status inquiry failed: %w", err)
+	}
+	now := time.Now()
+	for _, res := range results {
+		sub, ok := sent[res.ReferenceNumber]
+		if !ok {
+			continue
+		}
+		sub.CheckedAt = &now
+		switch res.Status {
+		case moadian.StatusSuccess:
+			sub.Status = model.TaxSubmissionSuccess
+			sub.Errors = joinTaxMessages(res.Warnings)
+		case moadian.StatusFailed, moadian.StatusNotFound:
+			sub.Status = model.TaxSubmissionFailed
+			sub.Errors = joinTaxMessages(res.Errors)
+			if res.Status == moadian.StatusNotFound {
+				sub.Errors = utils.PtrString("reference number not found in tax system")
+			}
+		}
+		if err := s.taxRepo.UpdateTaxSubmission(ctx, sub); err != nil {
+			return fmt.Errorf("failed to update tax submission: %w", err)
+		}
+	}
+	return nil
+}
+
+func joinTaxMessages(msgs []moadian.Message) *string {
+	if len(msgs) == 0 {
+		return nil
+	}
+	parts := make([]string, 0, len(msgs))
+	for _, m := range msgs {
+		parts = append(parts, strings.TrimSpace(m.Code+" "+m.Message))
+	}
+	return utils.PtrString(strings.Join(parts, "; "))
+}
@@ -0,0 +1,242 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"crm-gold/internal/model"
+	"crm-gold/internal/repository/repo"
+	"crm-gold/internal/utils"
+
+	"go.uber.org/zap"
+	"gorm.io/gorm"
+)
+
+// TaxEngine موتور قواعد مالیاتی؛ هر مسیری که مبلغ یا جمع فاکتور را حساب می‌کند مالیات را از آن می‌گیرد
+// تا محاسبه‌ها در همه جا یکسان باشند.
+type TaxEngine interface {
+	Policy(ctx context.Context, bidID uint, at time.Time, customerID uint) (*TaxPolicy, error)
+}
+
+// TaxPolicy قاعده معتبر در یک تاریخ همراه با انواع مشتری؛ Rule خالی یعنی مالیاتی تعلق نمی‌گیرد.
+type TaxPolicy struct {
+	Rule            *model.TaxRule
+	CustomerTypeIDs []uint
+}
+
+func (p *TaxPolicy) Assess(c model.TaxComponents) model.TaxAssessment {
+	return p.Rule.Assess(c, p.CustomerTypeIDs)
+}
+
+type TaxRuleService interface {
+	TaxEngine
+	CreateRule(ctx context.Context, req *model.TaxRuleRequest, actor string) (*model.TaxRule, error)
+	UpdateRule(ctx context.Context, id uint, req *model.TaxRuleRequest, actor string) (*model.TaxRule, error)
+	GetRule(ctx context.Context, id uint) (*model.TaxRule, error)
+	ListRules(ctx context.Context, bidID uint) ([]model.TaxRule, error)
+	Preview(ctx context.Context, req *model.TaxPreviewRequest) (*model.TaxAssessment, error)
+}
+
+type taxRuleServiceImpl struct {
+	taxRepo      repo.TaxRepo
+	customerRepo repo.CustRepo
+	logger       *zap.Logger
+}
+
+func NewTaxRuleService(taxRepo repo.TaxRepo, customerRepo repo.CustRepo, logger *zap.Logger) (TaxRuleService, error) {
+	if taxRepo == nil {
+		return nil, errors.New("taxRepository cannot be nil for TaxRuleService")
+	}
+	if customerRepo == nil {
+		return nil, errors.New("customerRepository cannot be nil for TaxRuleService")
+	}
+	if logger == nil {
+		return nil, errors.New("logger cannot be nil for TaxRuleService")
+	}
+	return &taxRuleServiceImpl{
+		taxRepo:      taxRepo,
+		customerRepo: customerRepo,
+		logger:       logger,
+	}, nil
+}
+
+// Policy مشتری صفر (مثلا پیش‌نمایش بدون مشتری) معافیتی ندارد.
+func (s *taxRuleServiceImpl) Policy(ctx context.Context, bidID uint, at time.Time, customerID uint) (*TaxPolicy, error) {
+	rule, err := s.taxRepo.TaxRuleAt(ctx, bidID, at)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load tax rule: %w", err)
+	}
+	policy := &TaxPolicy{Rule: rule}
+	if rule != nil && customerID != 0 && len(rule.ExemptCustomerTypes) > 0 {
+		if policy.CustomerTypeIDs, err = s.taxRepo.CustomerTypeIDs(ctx, customerID); err != nil {
+			return nil, fmt.Errorf("failed to load customer types: %w", err)
+		}
+	}
+	return policy, nil
+}
+
+func (s *taxRuleServiceImpl) CreateRule(ctx context.Context, req *model.TaxRuleRequest, actor string) (*model.TaxRule, error) {
+	rule := &model.TaxRule{BIDID: req.BIDID, CreatedBy: actor}
+	if err := s.applyRuleRequest(ctx, rule, req); err != nil {
+		return nil, err
+	}
+	if err := s.taxRepo.CreateTaxRule(ctx, rule); err != nil {
+		return nil, fmt.Errorf("failed to save tax rule: %w", err)
+	}
+	s.logger.Info("Tax rule created.", zap.Uint("rule_id", rule.ID), zap.Uint("bid_id", rule.BIDID), zap.Float64("rate", rule.Rate), zap.String("actor", actor))
+	return rule, nil
+}
+
+// UpdateRule فاکتورهای صادرشده نرخ و مبلغ مالیات خود را نگه می‌دارند؛ تغییر قاعده فقط روی فاکتورهای بعدی اثر دارد.
+func (s *taxRuleServiceImpl) UpdateRule(ctx context.Context, id uint, req *model.TaxRuleRequest, actor string) (*model.TaxRule, error) {
+	rule, err := s.GetRule(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if req.BIDID != 0 && req.BIDID != rule.BIDID {
+		return nil, fmt.Errorf("%w: tax rule belongs to another business", ErrValidation)
+	}
+	req.BIDID = rule.BIDID
+	if err := s.applyRuleRequest(ctx, rule, req); err != nil {
+		return nil, err
+	}
+	if err := s.taxRepo.UpdateTaxRule(ctx, rule); err != nil {
+		return nil, fmt.Errorf("failed to update tax rule: %w", err)
+	}
+	s.logger.Info("Tax rule updated.", zap.Uint("rule_id", rule.ID), zap.Float64("rate", rule.Rate), zap.String("actor", actor))
+	return rule, nil
+}
+
+// applyRuleRequest بازه‌های قواعد یک کسب‌وکار نباید هم‌پوشانی داشته باشند تا در هر تاریخ فقط یک نرخ معتبر باشد.
+func (s *taxRuleServiceImpl) applyRuleRequest(ctx context.Context, rule *model.TaxRule, req *model.TaxRuleRequest) error {
+	if req.BIDID == 0 {
+		return fmt.Errorf("%w: bidId is required", ErrValidation)
+	}
+	name := strings.TrimSpace(req.Name)
+	if name == "" {
+		return fmt.Errorf("%w: rule name is required", ErrValidation)
+	}
+	if req.Rate < 0 || req.Rate > 100 {
+		return fmt.Errorf("%w: tax rate must be between 0 and 100", ErrValidation)
+	}
+	from, err := utils.ParseDateParam(req.EffectiveFrom)
+	if err != nil {
+		return fmt.Errorf("%w: invalid effective from date: %v", ErrValidation, err)
+	}
+	from = utils.StartOfDay(from)
+	var to *time.Time
+	if req.EffectiveTo != "" {
+		d, err := utils.ParseDateParam(req.EffectiveTo)
+		if err != nil {
+			return fmt.Errorf("%w: invalid effective to date: %v", ErrValidation, err)
+		}
+		d = utils.StartOfDay(d)
+		if !d.After(from) {
+			return fmt.Errorf("%w: effective to must be after effective from", ErrValidation)
+		}
+		to = &d
+	}
+	if !req.TaxGold && !req.TaxLabor && !req.TaxProfit && !req.TaxCommission && req.Rate > 0 {
+		return fmt.Errorf("%w: at least one invoice component must be taxable", ErrValidation)
+	}
+
+	types, err := s.taxRepo.GetCusTypesByIDs(ctx, req.ExemptCustomerTypeIDs)
+	if err != nil {
+		return fmt.Errorf("failed to load customer types: %w", err)
+	}
+	if len(types) != len(uniqueIDs(req.ExemptCustomerTypeIDs)) {
+		return fmt.Errorf("%w: one or more exempt customer types do not exist", ErrValidation)
+	}
+
+	existing, err := s.taxRepo.ListTaxRules(ctx, req.BIDID)
+	if err != nil {
+		return fmt.Errorf("failed to list tax rules: %w", err)
+	}
+	for _, other := range existing {
+		if other.ID == rule.ID {
+			continue
+		}
+		if periodsOverlap(from, to, other.EffectiveFrom, other.EffectiveTo) {
+			return fmt.Errorf("%w: rule overlaps %q effective from %s", ErrConflict, other.Name, utils.FormatJalali(other.EffectiveFrom))
+		}
+	}
+
+	rule.Name = name
+	rule.Rate = req.Rate
+	rule.EffectiveFrom = from
+	rule.EffectiveTo = to
+	rule.TaxGold = req.TaxGold
+	rule.TaxLabor = req.TaxLabor
+	rule.TaxProfit = req.TaxProfit
+	rule.TaxCommission = req.TaxCommission
+	rule.ExemptCustomerTypes = types
+	return nil
+}
+
+// periodsOverlap بازه‌های نیمه‌باز [from, to)؛ to خالی یعنی بی‌پایان.
+func periodsOverlap(aFrom time.Time, aTo *time.Time, bFrom time.Time, bTo *time.Time) bool {
+	return (bTo == nil || aFrom.Before(*bTo)) && (aTo == nil || bFrom.Before(*aTo))
+}
+
+func uniqueIDs(ids []uint) map[uint]struct{} {
+	set := make(map[uint]struct{}, len(ids))
+	for _, id := range ids {
+		set[id] = struct{}{}
+	}
+	return set
+}
+
+func (s *taxRuleServiceImpl) GetRule(ctx context.Context, id uint) (*model.TaxRule, error) {
+	rule, err := s.taxRepo.GetTaxRuleByID(ctx, id)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, fmt.Errorf("%w: tax rule %d", ErrNotFound, id)
+		}
+		return nil, fmt.Errorf("failed to fetch tax rule: %w", err)
+	}
+	return rule, nil
+}
+
+func (s *taxRuleServiceImpl) ListRules(ctx context.Context, bidID uint) ([]model.TaxRule, error) {
+	if bidID == 0 {
+		return nil, fmt.Errorf("%w: bidId is required", ErrValidation)
+	}
+	rules, err := s.taxRepo.ListTaxRules(ctx, bidID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list tax rules: %w", err)
+	}
+	return rules, nil
+}
+
+// Preview مالیات اجزای داده‌شده را در تاریخ (پیش‌فرض امروز) و برای مشتری اختیاری محاسبه می‌کند.
+func (s *taxRuleServiceImpl) Preview(ctx context.Context, req *model.TaxPreviewRequest) (*model.TaxAssessment, error) {
+	if req.BIDID == 0 {
+		return nil, fmt.Errorf("%w: bidId is required", ErrValidation)
+	}
+	at := utils.StartOfDay(time.Now())
+	if req.Date != "" {
+		d, err := utils.ParseDateParam(req.Date)
+		if err != nil {
+			return nil, fmt.Errorf("%w: invalid date: %v", ErrValidation, err)
+		}
+		at = utils.StartOfDay(d)
+	}
+	if req.CustomerID != 0 {
+		customer, err := s.customerRepo.GetCustomerByID(ctx, req.CustomerID)
+		if err != nil {
+			return nil, endpointLoadError(err, model.LedgerAccountPerson, req.CustomerID)
+		}
+		if customer.BIDID != req.BIDID {
+			return nil, fmt.Errorf("%w: customer %d belongs to another business", ErrValidation, customer.ID)
+		}
+	}
+	policy, err := s.Policy(ctx, req.BIDID, at, req.CustomerID)
+	if err != nil {
+		return nil, err
+	}
+	assessment := policy.Assess(req.TaxComponents)
+	return &assessment, nil
+}
@@ -0,0 +1,301 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"crm-gold/internal/model"
+	"crm-gold/internal/repository/db/postgresDb"
+	"crm-gold/internal/repository/repo"
+	"crm-gold/internal/utils"
+
+	"go.uber.org/zap"
+	"gorm.io/gorm"
+)
+
+type TransferService interface {
+	CreateTransfer(ctx context.Context, req *model.CreateTransferRequest, actor string) (*model.Transfer, error)
+	GetTransfer(ctx context.Context, id uint) (*model.Transfer, error)
+	ListTransfers(ctx context.Context, filter model.TransferFilter) ([]model.Transfer, error)
+	ReverseTransfer(ctx context.Context, id uint, req *model.ReverseTransferRequest, actor string) (*model.Transfer, error)
+	GetTransferEntries(ctx context.Context, id uint) ([]model.LedgerEntry, error)
+}
+
+type transferServiceImpl struct {
+	transferRepo repo.TransferRepo
+	fundRepo     repo.FundRepo
+	bankRepo     repo.BankRepo
+	customerRepo repo.CustRepo
+	settingRepo  repo.SettingRepo
+	ledgerRepo   repo.LedgerRepo
+	logger       *zap.Logger
+}
+
+func NewTransferService(transferRepo repo.TransferRepo, fundRepo repo.FundRepo, bankRepo repo.BankRepo, customerRepo repo.CustRepo, settingRepo repo.SettingRepo, ledgerRepo repo.LedgerRepo, logger *zap.Logger) (TransferService, error) {
+	if transferRepo == nil {
+		return nil, errors.New("transferRepository cannot be nil for TransferService")
+	}
+	if fundRepo == nil {
+		return nil, errors.New("fundRepository cannot be nil for TransferService")
+	}
+	if bankRepo == nil {
+		return nil, errors.New("bankRepository cannot be nil for TransferService")
+	}
+	if customerRepo == nil {
+		return nil, errors.New("customerRepository cannot be nil for TransferService")
+	}
+	if settingRepo == nil {
+		return nil, errors.New("settingRepository cannot be nil for TransferService")
+	}
+	if ledgerRepo == nil {
+		return nil, errors.New("ledgerRepository cannot be nil for TransferService")
+	}
+	if logger == nil {
+		return nil, errors.New("logger cannot be nil for TransferService")
+	}
+	return &transferServiceImpl{
+		transferRepo: transferRepo,
+		fundRepo:     fundRepo,
+		bankRepo:     bankRepo,
+		customerRepo: customerRepo,
+		settingRepo:  settingRepo,
+		ledgerRepo:   ledgerRepo,
+		logger:       logger,
+	}, nil
+}
+
+func (s *transferServiceImpl) CreateTransfer(ctx context.Context, req *model.CreateTransferRequest, actor string) (*model.Transfer, error) {
+	if req.BIDID == 0 {
+		return nil, fmt.Errorf("%w: bidId is required", ErrValidation)
+	}
+	if !req.Amount.IsPositive() {
+		return nil, fmt.Errorf("%w: transfer amount must be positive", ErrValidation)
+	}
+	if req.Fee.IsNegative() {
+		return nil, fmt.Errorf("%w: transfer fee cannot be negative", ErrValidation)
+	}
+	if req.FromKind == req.ToKind && req.FromID == req.ToID {
+		return nil, fmt.Errorf("%w: source and destination must differ", ErrValidation)
+	}
+	date, err := utils.ParseDateParam(req.Date)
+	if err != nil {
+		return nil, fmt.Errorf("%w: invalid transfer date: %v", ErrValidation, err)
+	}
+	date = utils.StartOfDay(date)
+	if err := s.validateEndpoint(ctx, req.BIDID, req.FromKind, req.FromID, date); err != nil {
+		return nil, err
+	}
+	if err := s.validateEndpoint(ctx, req.BIDID, req.ToKind, req.ToID, date); err != nil {
+		return nil, err
+	}
+	setting, err := s.settingRepo.GetBusinessSetting(ctx, req.BIDID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load business setting: %w", err)
+	}
+
+	code, err := utils.GenerateSecureRandomString(8)
+	if err != nil {
+		return nil, fmt.Errorf("failed to generate transfer number: %w", err)
+	}
+	transfer := &model.Transfer{
+		BIDID:        req.BIDID,
+		Number:       "TRF-" + code,
+		Date:         date,
+		DateJalali:   utils.FormatJalali(date),
+		FromKind:     req.FromKind,
+		FromID:       req.FromID,
+		ToKind:       req.ToKind,
+		ToID:         req.ToID,
+		Amount:       req.Amount,
+		Fee:          req.Fee,
+		TrackingCode: utils.PtrString(utils.NormalizeDigits(strings.TrimSpace(req.TrackingCode))),
+		Description:  utils.PtrString(req.Description),
+		Status:       model.TransferStatusPosted,
+		CreatedBy:    actor,
+	}
+
+	created, err := s.transferRepo.CreateTransfer(ctx, transfer, transferEntries(transfer, actor), setting.RequireSufficientBalance)
+	if err != nil {
+		switch {
+		case errors.Is(err, postgresDb.ErrInsufficientBalance):
+			return nil, fmt.Errorf("%w: %v", ErrValidation, err)
+		case errors.Is(err, postgresDb.ErrFundDayClosed):
+			return nil, fmt.Errorf("%w: %v", ErrConflict, err)
+		}
+		return nil, fmt.Errorf("failed to save transfer: %w", err)
+	}
+	s.logger.Info("Transfer created.", zap.Uint("transfer_id", created.ID), zap.String("number", created.Number), zap.String("amount", created.Amount.String()))
+	return created, nil
+}
+
+// validateEndpoint وجود طرف انتقال و تعلق آن به همان کسب‌وکار را بررسی می‌کند.
+func (s *transferServiceImpl) validateEndpoint(ctx context.Context, bidID uint, kind string, id uint, date time.Time) error {
+	if id == 0 {
+		return fmt.Errorf("%w: transfer endpoint id is required", ErrValidation)
+	}
+	var ownerBID uint
+	var openingDate *time.Time
+	switch kind {
+	case model.LedgerAccountFund:
+		fund, err := s.fundRepo.GetFundByID(ctx, id)
+		if err != nil {
+			return endpointLoadError(err, kind, id)
+		}
+		if !fund.IsActive {
+			return fmt.Errorf("%w: fund %d is inactive", ErrValidation, id)
+		}
+		ownerBID, openingDate = fund.BIDID, &fund.OpeningDate
+	case model.LedgerAccountBank:
+		account, err := s.bankRepo.GetBankAccountByID(ctx, id)
+		if err != nil {
+			return endpointLoadError(err, kind, id)
+		}
+		if !account.IsActive {
+			return fmt.Errorf("%w: bank account %d is inactive", ErrValidation, id)
+		}
+		ownerBID, openingDate = account.BIDID, &account.OpeningDate
+	case model.LedgerAccountPerson:
+		person, err := s.customerRepo.GetCustomerByID(ctx, id)
+		if err != nil {
+			return endpointLoadError(err, kind, id)
+		}
+		ownerBID = person.BIDID
+	default:
+		return fmt.Errorf("%w: transfer endpoints must be a fund, bank or person", ErrValidation)
+	}
+	if ownerBID != bidID {
+		return fmt.Errorf("%w: %s %d belongs to another business", ErrValidation, kind, id)
+	}
+	if openingDate != nil && date.Before(*openingDate) {
+		return fmt.Errorf("%w: transfer date is before the opening date of %s %d", ErrValidation, kind, id)
+	}
+	return nil
+}
+
+func endpointLoadError(err error, kind string, id uint) error {
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return fmt.Errorf("%w: %s %d does not exist", ErrValidation, kind, id)
+	}
+	return fmt.Errorf("failed to load %s %d: %w", kind, id, err)
+}
+
+// transferEntries انتقال: مقصد بدهکار و مبدا بستانکار؛ کارمزد در سند جداگانه با همان شماره مرجع ثبت می‌شود.
+func transferEntries(transfer *model.Transfer, actor string) []*model.LedgerEntry {
+	fromID, toID := transfer.FromID, transfer.ToID
+	desc := fmt.Sprintf("انتقال از %s %d به %s %d", transfer.FromKind, fromID, transfer.ToKind, toID)
+	if transfer.Description != nil {
+		desc = desc + " - " + *transfer.Description
+	}
+	entries := []*model.LedgerEntry{{
+		BIDID:       transfer.BIDID,
+		DocType:     model.LedgerDocTransfer,
+		Reference:   transfer.Number,
+		Description: desc,
+		PostedAt:    transfer.Date,
+		CreatedBy:   actor,
+		Lines: []model.LedgerLine{
+			{AccountKind: transfer.ToKind, AccountID: &toID, Debit: transfer.Amount, Description: desc},
+			{AccountKind: transfer.FromKind, AccountID: &fromID, Credit: transfer.Amount, Description: desc},
+		},
+	}}
+	if transfer.Fee.IsPositive() {
+		feeDesc := "کارمزد " + desc
+		entries = append(entries, &model.LedgerEntry{
+			BIDID:       transfer.BIDID,
+			DocType:     model.LedgerDocTransfer,
+			Reference:   transfer.Number,
+			Description: feeDesc,
+			PostedAt:    transfer.Date,
+			CreatedBy:   actor,
+			Lines: []model.LedgerLine{
+				{AccountKind: model.LedgerAccountBankFees, Debit: transfer.Fee, Description: feeDesc},
+				{AccountKind: transfer.FromKind, AccountID: &fromID, Credit: transfer.Fee, Description: feeDesc},
+			},
+		})
+	}
+	return entries
+}
+
+func (s *transferServiceImpl) GetTransfer(ctx context.Context, id uint) (*model.Transfer, error) {
+	transfer, err := s.transferRepo.GetTransferByID(ctx, id)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, fmt.Errorf("%w: transfer %d", ErrNotFound, id)
+		}
+		return nil, fmt.Errorf("failed to fetch transfer: %w", err)
+	}
+	return transfer, nil
+}
+
+func (s *transferServiceImpl) ListTransfers(ctx context.Context, filter model.TransferFilter) ([]model.Transfer, error) {
+	transfers, err := s.transferRepo.ListTransfers(ctx, filter)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list transfers: %w", err)
+	}
+	return transfers, nil
+}
+
+func (s *transferServiceImpl) ReverseTransfer(ctx context.Context, id uint, req *model.ReverseTransferRequest, actor string) (*model.Transfer, error) {
+	transfer, err := s.GetTransfer(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if transfer.Status != model.TransferStatusPosted {
+		return nil, fmt.Errorf("%w: transfer is already %s", ErrInvalidTransition, transfer.Status)
+	}
+	if strings.TrimSpace(req.Reason) == "" {
+		return nil, fmt.Errorf("%w: reversal reason is required", ErrValidation)
+	}
+	at := utils.StartOfDay(time.Now())
+	if req.Date != "" {
+		d, err := utils.ParseDateParam(req.Date)
+		if err != nil {
+			return nil, fmt.Errorf("%w: invalid reversal date: %v", ErrValidation, err)
+		}
+		at = utils.StartOfDay(d)
+	}
+	if at.Before(transfer.Date) {
+		return nil, fmt.Errorf("%w: reversal date cannot be before the transfer date", ErrValidation)
+	}
+
+	entries, err := s.ledgerRepo.GetEntriesByDocument(ctx, model.LedgerDocTransfer, transfer.ID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load transfer ledger entries: %w", err)
+	}
+	var reversals []*model.LedgerEntry
+	for i := range entries {
+		if entries[i].ReversalOfID == nil {
+			reversals = append(reversals, entries[i].Reversal(at, actor, req.Reason))
+		}
+	}
+
+	transfer.ReversedAt = &at
+	transfer.ReversedBy = actor
+	transfer.ReversalReason = utils.PtrString(strings.TrimSpace(req.Reason))
+	if err := s.transferRepo.ReverseTransfer(ctx, transfer, reversals); err != nil {
+		switch {
+		case errors.Is(err, postgresDb.ErrTransferAlreadyReversed):
+			return nil, fmt.Errorf("%w: %v", ErrConflict, err)
+		case errors.Is(err, postgresDb.ErrFundDayClosed):
+			return nil, fmt.Errorf("%w: %v", ErrConflict, err)
+		}
+		return nil, fmt.Errorf("failed to reverse transfer: %w", err)
+	}
+	transfer.Status = model.TransferStatusReversed
+	s.logger.Info("Transfer reversed.", zap.Uint("transfer_id", transfer.ID), zap.String("actor", actor))
+	return transfer, nil
+}
+
+func (s *transferServiceImpl) GetTransferEntries(ctx context.Context, id uint) ([]model.LedgerEntry, error) {
+	if _, err := s.GetTransfer(ctx, id); err != nil {
+		return nil, err
+	}
+	entries, err := s.ledgerRepo.GetEntriesByDocument(ctx, model.LedgerDocTransfer, id)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch transfer ledger entries: %w", err)
+	}
+	return entries, nil
+}
@@ -0,0 +1,45 @@
+package utils
+
+import (
+	"math/big"
+	"strings"
+)
+
+// NormalizeSheba removes spaces and dashes and upper-cases the IR prefix.
+func NormalizeSheba(s string) string {
+	s = strings.ToUpper(NormalizeDigits(s))
+	return strings.NewReplacer(" ", "", "-", "").Replace(s)
+}
+
+// IsValidSheba checks the IR prefix, length and the IBAN mod-97 checksum of an Iranian Sheba number.
+func IsValidSheba(s string) bool {
+	if len(s) != 26 || !strings.HasPrefix(s, "IR") || strings.Trim(s[2:], "0123456789") != "" {
+		return false
+	}
+	// IR = 18 27
+	rearranged := s[4:] + "1827" + s[2:4]
+	n, ok := new(big.Int).SetString(rearranged, 10)
+	if !ok {
+		return false
+	}
+	return new(big.Int).Mod(n, big.NewInt(97)).Int64() == 1
+}
+
+// IsValidCardNumber checks a 16 digit bank card number with the Luhn algorithm.
+func IsValidCardNumber(s string) bool {
+	if len(s) != 16 || strings.Trim(s, "0123456789") != "" {
+		return false
+	}
+	sum := 0
+	for i := 0; i < 16; i++ {
+		d := int(s[i] - '0')
+		if i%2 == 0 {
+			d *= 2
+			if d > 9 {
+				d -= 9
+			}
+		}
+		sum += d
+	}
+	return sum%10 == 0
+}
@@ -0,0 +1,192 @@
+package utils
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// TehranLocation زمان محلی ایران برای تبدیل تاریخ‌های شمسی
+var TehranLocation = loadTehranLocation()
+
+func loadTehranLocation() *time.Location {
+	loc, err := time.LoadLocation("Asia/Tehran")
+	if err != nil {
+		return time.FixedZone("IRST", 3*3600+1800)
+	}
+	return loc
+}
+
+// GregorianToJalali converts a Gregorian date to its Jalali (Solar Hijri) year, month and day.
+func GregorianToJalali(t time.Time) (int, int, int) {
+	t = t.In(TehranLocation)
+	gy, gm, gd := t.Year(), int(t.Month()), t.Day()
+
+	gDaysInMonth := []int{0, 31, 59, 90, 120, 151, 181, 212, 243, 273, 304, 334}
+	gy2 := gy
+	if gm > 2 {
+		gy2 = gy + 1
+	}
+	days := 355666 + (365 * gy) + ((gy2 + 3) / 4) - ((gy2 + 99) / 100) + ((gy2 + 399) / 400) + gd + gDaysInMonth[gm-1]
+	jy := -1595 + (33 * (days / 12053))
+	days %= 12053
+	jy += 4 * (days / 1461)
+	days %= 1461
+	if days > 365 {
+		jy += (days - 1) / 365
+		days = (days - 1) % 365
+	}
+	var jm, jd int
+	if days < 186 {
+		jm = 1 + (days / 31)
+		jd = 1 + (days % 31)
+	} else {
+		jm = 7 + ((days - 186) / 30)
+		jd = 1 + ((days - 186) % 30)
+	}
+	return jy, jm, jd
+}
+
+// JalaliToGregorian returns midnight (Tehran time) of the given Jalali date.
+func JalaliToGregorian(jy, jm, jd int) time.Time {
+	jy += 1595
+	days := -355668 + (365 * jy) + ((jy / 33) * 8) + (((jy % 33) + 3) / 4) + jd
+	if jm < 7 {
+		days += (jm - 1) * 31
+	} else {
+		days += ((jm - 7) * 30) + 186
+	}
+	gy := 400 * (days / 146097)
+	days %= 146097
+	if days > 36524 {
+		days--
+		gy += 100 * (days / 36524)
+		days %= 36524
+		if days >= 365 {
+			days++
+		}
+	}
+	gy += 4 * (days / 1461)
+	days %= 1461
+	if days > 365 {
+		gy += (days - 1) / 365
+		days = (days - 1) % 365
+	}
+	gd := days + 1
+	leap := (gy%4 == 0 && gy%100 != 0) || gy%400 == 0
+	monthDays := []int{0, 31, 28, 31, 30, 31, 30, 31, 31, 30, 31, 30, 31}
+	if leap {
+		monthDays[2] = 29
+	}
+	gm := 1
+	for gm = 1; gm <= 12 && gd > monthDays[gm]; gm++ {
+		gd -= monthDays[gm]
+	}
+	return time.Date(gy, time.Month(gm), gd, 0, 0, 0, 0, TehranLocation)
+}
+
+// IsJalaliLeapYear reports whether the given Jalali year has 30 days in Esfand.
+func IsJalaliLeapYear(jy int) bool {
+	next := JalaliToGregorian(jy+1, 1, 1)
+	last := JalaliToGregorian(jy, 12, 29)
+	return next.Sub(last) > 24*time.Hour+time.Hour
+}
+
+// JalaliMonthLength returns the number of days in the given Jalali month.
+func JalaliMonthLength(jy, jm int) int {
+	switch {
+	case jm <= 6:
+		return 31
+	case jm <= 11:
+		return 30
+	case IsJalaliLeapYear(jy):
+		return 30
+	default:
+		return 29
+	}
+}
+
+// ParseJalaliDate parses dates like "1403/05/12" or "1403-05-12" (Persian digits are accepted).
+func ParseJalaliDate(s string) (time.Time, error) {
+	s = strings.TrimSpace(NormalizeDigits(s))
+	s = strings.ReplaceAll(s, "-", "/")
+	parts := strings.Split(s, "/")
+	if len(parts) != 3 {
+		return time.Time{}, fmt.Errorf("invalid jalali date %q, expected YYYY/MM/DD", s)
+	}
+	jy, errY := strconv.Atoi(parts[0])
+	jm, errM := strconv.Atoi(parts[1])
+	jd, errD := strconv.Atoi(parts[2])
+	if errY != nil || errM != nil || errD != nil {
+		return time.Time{}, fmt.Errorf("invalid jalali date %q", s)
+	}
+	if jy < 1 || jm < 1 || jm > 12 || jd < 1 || jd > JalaliMonthLength(jy, jm) {
+		return time.Time{}, fmt.Errorf("jalali date %q is out of range", s)
+	}
+	return JalaliToGregorian(jy, jm, jd), nil
+}
+
+// FormatJalali formats a time as YYYY/MM/DD in the Jalali calendar.
+func FormatJalali(t time.Time) string {
+	jy, jm, jd := GregorianToJalali(t)
+	return fmt.Sprintf("%04d/%02d/%02d", jy, jm, jd)
+}
+
+// ParseDateParam accepts either a Jalali (YYYY/MM/DD, year < 1700) or an RFC3339/ISO (YYYY-MM-DD) date.
+func ParseDateParam(s string) (time.Time, error) {
+	s = strings.TrimSpace(NormalizeDigits(s))
+	if s == "" {
+		return time.Time{}, fmt.Errorf("date is empty")
+	}
+	if t, err := time.Parse(time.RFC3339, s); err == nil {
+		return t, nil
+	}
+	if len(s) >= 4 {
+		if y, err := strconv.Atoi(s[:4]); err == nil && y >= 1700 {
+			t, err := time.ParseInLocation("2006-01-02", s, TehranLocation)
+			if err != nil {
+				return time.Time{}, fmt.Errorf("invalid date %q: %w", s, err)
+			}
+			return t, nil
+		}
+	}
+	return ParseJalaliDate(s)
+}
+
+// StartOfDay returns midnight of t in Tehran time.
+func StartOfDay(t time.Time) time.Time {
+	t = t.In(TehranLocation)
+	return time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, TehranLocation)
+}
+
+// NormalizeDigits replaces Persian and Arabic-Indic digits with ASCII digits.
+func NormalizeDigits(s string) string {
+	var b strings.Builder
+	for _, r := range s {
+		switch {
+		case r >= '۰' && r <= '۹':
+			b.WriteRune('0' + (r - '۰'))
+		case r >= '٠' && r <= '٩':
+			b.WriteRune('0' + (r - '٠'))
+		default:
+			b.WriteRune(r)
+		}
+	}
+	return b.String()
+}
+
+// AddJalaliMonths moves t by n Jalali months, clamping the day to the target month's length
+// (e.g. 1403/06/31 + 1 month = 1403/07/30).
+func AddJalaliMonths(t time.Time, n int) time.Time {
+	jy, jm, jd := GregorianToJalali(t)
+	total := jy*12 + (jm - 1) + n
+	jy, jm = total/12, total%12+1
+	if l := JalaliMonthLength(jy, jm); jd > l {
+		jd = l
+	}
+	return JalaliToGregorian(jy, jm, jd)
+}
+
+// JalaliMonthNames holds the Persian month names indexed by month number (index 0 is unused).
+var JalaliMonthNames = [...]string{"", "فروردین", "اردیبهشت", "خرداد", "تیر", "مرداد", "شهریور", "مهر", "آبان", "آذر", "دی", "بهمن", "اسفند"}
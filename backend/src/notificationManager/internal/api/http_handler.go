@@ -19,18 +19,31 @@ func NewHTTPHandler(s service.NotificationService) *HTTPHandler {
 	return &HTTPHandler{service: s}
 }
 
+// userRefBody carries the recipient for services whose user IDs are not numeric.
+type userRefBody struct {
+	UserRef string `json:"user_ref"`
+}
+
 // CreateNotification handles the creation of a new notification.
 func (h *HTTPHandler) CreateNotification(c *fiber.Ctx) error {
 	req := new(pb.CreateNotificationRequest)
 	if err := c.BodyParser(req); err != nil {
 		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "cannot parse request"})
 	}
+	ref := new(userRefBody)
+	if err := c.BodyParser(ref); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "cannot parse request"})
+	}
 
 	var userID *uint
 	if req.UserId != 0 {
 		u := uint(req.UserId)
 		userID = &u
 	}
+	var userRef *string
+	if ref.UserRef != "" {
+		userRef = &ref.UserRef
+	}
 
 	var scheduledAt *time.Time
 	if req.ScheduledAt != nil {
@@ -38,7 +51,7 @@ func (h *HTTPHandler) CreateNotification(c *fiber.Ctx) error {
 		scheduledAt = &t
 	}
 
-	notification, err := h.service.CreateNotification(req.Message, req.Type, req.RecipientType, userID, scheduledAt)
+	notification, err := h.service.CreateNotification(req.Message, req.Type, req.RecipientType, userID, userRef, scheduledAt)
 	if err != nil {
 		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": err.Error()})
 	}
@@ -47,10 +60,15 @@ func (h *HTTPHandler) CreateNotification(c *fiber.Ctx) error {
 }
 
 // GetUserNotifications handles fetching notifications for a specific user.
+// A non-numeric user_id is treated as a user_ref.
 func (h *HTTPHandler) GetUserNotifications(c *fiber.Ctx) error {
 	userID, err := strconv.ParseUint(c.Params("user_id"), 10, 32)
 	if err != nil {
-		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "invalid user ID"})
+		notifications, err := h.service.GetUserNotificationsByRef(c.Params("user_id"))
+		if err != nil {
+			return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": err.Error()})
+		}
+		return c.JSON(notifications)
 	}
 
 	notifications, err := h.service.GetUserNotifications(uint(userID))
@@ -8,6 +8,8 @@ type Notification struct {
 	ID          uint      `gorm:"primaryKey"`
 	SenderID    uint      `gorm:"not null"`
 	RecipientID *uint
+	// RecipientRef is the recipient's ID in services that identify users by UUID (profileManager).
+	RecipientRef *string `gorm:"index;size:64"`
 	IsBroadcast bool      `gorm:"not null;default:false"`
 	Message     string    `gorm:"not null"`
 	Type        string    `gorm:"not null;type:varchar(50)"`
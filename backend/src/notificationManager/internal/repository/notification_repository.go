@@ -10,6 +10,7 @@ import (
 type NotificationRepository interface {
 	Create(notification *model.Notification) error
 	GetUserNotifications(userID uint) ([]model.Notification, error)
+	GetUserNotificationsByRef(userRef string) ([]model.Notification, error)
 	MarkAsRead(notificationID, userID uint) error
 	GetAll() ([]model.Notification, error)
 	GetByID(id uint) (*model.Notification, error)
@@ -36,6 +37,12 @@ func (r *notificationRepository) GetUserNotifications(userID uint) ([]model.Noti
 	return notifications, err
 }
 
+func (r *notificationRepository) GetUserNotificationsByRef(userRef string) ([]model.Notification, error) {
+	var notifications []model.Notification
+	err := r.db.Where("recipient_ref = ? OR is_broadcast = ?", userRef, true).Find(&notifications).Error
+	return notifications, err
+}
+
 func (r *notificationRepository) MarkAsRead(notificationID, userID uint) error {
 	read := model.NotificationRead{
 		NotificationID: notificationID,
@@ -7,8 +7,9 @@ import (
 )
 
 type NotificationService interface {
-	CreateNotification(message, notificationType, recipientType string, userID *uint, scheduledAt *time.Time) (*model.Notification, error)
+	CreateNotification(message, notificationType, recipientType string, userID *uint, userRef *string, scheduledAt *time.Time) (*model.Notification, error)
 	GetUserNotifications(userID uint) ([]model.Notification, error)
+	GetUserNotificationsByRef(userRef string) ([]model.Notification, error)
 	MarkNotificationAsRead(notificationID, userID uint) error
 	GetAllNotifications() ([]model.Notification, error)
 	GetNotificationReadStatus(notificationID uint) ([]model.NotificationRead, error)
@@ -23,7 +24,7 @@ func NewNotificationService(repo repository.NotificationRepository) Notification
 	return &notificationService{repo: repo}
 }
 
-func (s *notificationService) CreateNotification(message, notificationType, recipientType string, userID *uint, scheduledAt *time.Time) (*model.Notification, error) {
+func (s *notificationService) CreateNotification(message, notificationType, recipientType string, userID *uint, userRef *string, scheduledAt *time.Time) (*model.Notification, error) {
 	notification := &model.Notification{
 		Message:     message,
 		Type:        notificationType,
@@ -34,6 +35,7 @@ func (s *notificationService) CreateNotification(message, notificationType, reci
 		notification.IsBroadcast = true
 	} else {
 		notification.RecipientID = userID
+		notification.RecipientRef = userRef
 	}
 
 	if scheduledAt != nil {
@@ -52,6 +54,10 @@ func (s *notificationService) GetUserNotifications(userID uint) ([]model.Notific
 	return s.repo.GetUserNotifications(userID)
 }
 
+func (s *notificationService) GetUserNotificationsByRef(userRef string) ([]model.Notification, error) {
+	return s.repo.GetUserNotificationsByRef(userRef)
+}
+
 func (s *notificationService) MarkNotificationAsRead(notificationID, userID uint) error {
 	return s.repo.MarkAsRead(notificationID, userID)
 }
@@ -3,6 +3,7 @@ module profile-gold
 go 1.24.3
 
 require (
+	common-gold v0.0.0
 	github.com/go-redis/redis/v8 v8.11.5
 	github.com/gofiber/fiber/v2 v2.52.8
 	github.com/golang-jwt/jwt/v5 v5.2.2
@@ -43,3 +44,5 @@ require (
 	golang.org/x/text v0.27.0 // indirect
 	gorm.io/driver/mysql v1.5.6 // indirect
 )
+
+replace common-gold => ../common
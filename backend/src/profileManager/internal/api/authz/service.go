@@ -2,119 +2,90 @@ package authz
 
 import (
 	"fmt"
-	"profile-gold/internal/model"
+	"sync"
+	"time"
 
-	"go.uber.org/zap"
-)
-
-var rolePermissionsMap = map[string][]string{
-	model.RoleAdmin: {
-		model.PermInventoryReadItem, model.PermInventoryCreateItem, model.PermInventoryUpdateItem, model.PermInventoryDeleteItem,
-		model.PermInventoryIncreaseStock, model.PermInventoryDecreaseStock, model.PermInventoryReadCategory, model.PermInventoryManageCategory,
-		model.PermInventoryReadGoldPrice, model.PermInventoryUpdateGoldPrice,
-		model.PermCRMReadCustomer, model.PermCRMCreateCustomer, model.PermCRMUpdateCustomer, model.PermCRMDeleteCustomer,
-		model.PermCRMReadSupplier, model.PermCRMCreateSupplier, model.PermCRMUpdateSupplier, model.PermCRMDeleteSupplier,
-		model.PermCRMViewCustomerBalance, model.PermCRMViewSupplierBalance,
-		model.PermTransactionReadSaleInvoice, model.PermTransactionCreateSaleInvoice, model.PermTransactionUpdateSaleInvoice, model.PermTransactionDeleteSaleInvoice,
-		model.PermTransactionReadPurchaseInvoice, model.PermTransactionCreatePurchaseInvoice, model.PermTransactionUpdatePurchaseInvoice, model.PermTransactionDeletePurchaseInvoice,
-		model.PermTransactionManagePayments, model.PermTransactionManageExpenses, model.PermTransactionManageCheques,
-		model.PermReportViewSalesSummary, model.PermReportViewInventorySummary, model.PermReportViewProfitLoss, model.PermReportViewBalances, model.PermReportExportData,
-		model.PermUserRead, model.PermUserCreate, model.PermUserUpdate, model.PermUserDelete, model.PermUserChangeAnyPassword,
-		model.PermSystemSettingsRead, model.PermSystemSettingsManage,
-	},
-	model.RoleOwner: {
-		model.PermInventoryReadItem, model.PermInventoryCreateItem, model.PermInventoryUpdateItem, model.PermInventoryDeleteItem,
-		model.PermInventoryIncreaseStock, model.PermInventoryDecreaseStock, model.PermInventoryReadCategory, model.PermInventoryManageCategory,
-		model.PermInventoryReadGoldPrice, model.PermInventoryUpdateGoldPrice,
+	"profile-gold/internal/repository/db/postgresDb"
 
-		model.PermCRMReadCustomer, model.PermCRMCreateCustomer, model.PermCRMUpdateCustomer, model.PermCRMDeleteCustomer,
-		model.PermCRMReadSupplier, model.PermCRMCreateSupplier, model.PermCRMUpdateSupplier, model.PermCRMDeleteSupplier,
-		model.PermCRMViewCustomerBalance, model.PermCRMViewSupplierBalance,
+	"common-gold/authz"
 
-		model.PermTransactionReadSaleInvoice, model.PermTransactionCreateSaleInvoice, model.PermTransactionUpdateSaleInvoice, model.PermTransactionDeleteSaleInvoice,
-		model.PermTransactionReadPurchaseInvoice, model.PermTransactionCreatePurchaseInvoice, model.PermTransactionUpdatePurchaseInvoice, model.PermTransactionDeletePurchaseInvoice,
-		model.PermTransactionManagePayments, model.PermTransactionManageExpenses, model.PermTransactionManageCheques,
-
-		model.PermReportViewSalesSummary, model.PermReportViewInventorySummary, model.PermReportViewProfitLoss, model.PermReportViewBalances, model.PermReportExportData,
+	"go.uber.org/zap"
+)
 
-		model.PermUserRead,
-		model.PermSystemSettingsRead,
-	},
-	model.RoleSalesperson: {
-		model.PermInventoryReadItem,
-		model.PermInventoryReadGoldPrice,
-		model.PermCRMReadCustomer,
-		model.PermCRMCreateCustomer,
-		model.PermCRMUpdateCustomer,
-		model.PermTransactionReadSaleInvoice,
-		model.PermTransactionCreateSaleInvoice,
-		model.PermTransactionManagePayments,
-		model.PermReportViewSalesSummary,
-		model.PermInventoryDecreaseStock,
-	},
-	model.RoleAccountant: {
-		model.PermInventoryReadItem,
-		model.PermInventoryReadGoldPrice,
-		model.PermCRMReadCustomer,
-		model.PermCRMReadSupplier,
-		model.PermCRMViewCustomerBalance,
-		model.PermCRMViewSupplierBalance,
-		model.PermTransactionReadSaleInvoice, model.PermTransactionUpdateSaleInvoice, model.PermTransactionDeleteSaleInvoice,
-		model.PermTransactionReadPurchaseInvoice, model.PermTransactionCreatePurchaseInvoice, model.PermTransactionUpdatePurchaseInvoice, model.PermTransactionDeletePurchaseInvoice,
-		model.PermTransactionManagePayments, model.PermTransactionManageExpenses, model.PermTransactionManageCheques,
-		model.PermReportViewSalesSummary, model.PermReportViewInventorySummary, model.PermReportViewProfitLoss, model.PermReportViewBalances, model.PermReportExportData,
-	},
-}
+// policyCheckInterval is how long a loaded policy is trusted before the version row is checked again,
+// so changes made through another Profile Manager instance are picked up quickly.
+const policyCheckInterval = 5 * time.Second
 
 type PermissionService interface {
-	HashPermission(userRoles []string, requiredPermission string) bool
-	IsValidRole(role string) bool
-	
+	HasPermission(userID string, userRoles []string, requiredPermission string) (bool, error)
+	IsValidRole(role string) (bool, error)
+	// Invalidate makes the next check reload the policy; called after RBAC changes made by this instance.
+	Invalidate()
 }
 
 type permissionServiceImpl struct {
-	logger *zap.Logger
-	validRoles      map[string]bool
-	rolePermissions map[string][]string 
+	logger    *zap.Logger
+	rbacRepo  postgresDb.RBACRepository
+	mu        sync.Mutex
+	policy    *authz.Policy
+	checkedAt time.Time
 }
 
-func NewPermissionService(logger *zap.Logger) (PermissionService, error) { 
+func NewPermissionService(logger *zap.Logger, rbacRepo postgresDb.RBACRepository) (PermissionService, error) {
 	if logger == nil {
 		return nil, fmt.Errorf("logger cannot be nil for PermissionService")
 	}
-
-	validRoles := map[string]bool{
-		"admin": true,
-		"user":  true,
-		"guest": true,
+	if rbacRepo == nil {
+		return nil, fmt.Errorf("RBACRepository cannot be nil for PermissionService")
 	}
-	rolePermissions := map[string][]string{
-		"admin": {"PermUserRead", "PermUserCreate", "PermUserUpdate", "PermUserDelete", "PermUserChangeAnyPassword"},
-		"user":  {"PermUserRead"},
-		"guest": {},
+	return &permissionServiceImpl{logger: logger, rbacRepo: rbacRepo}, nil
+}
+
+func (s *permissionServiceImpl) HasPermission(userID string, userRoles []string, requiredPermission string) (bool, error) {
+	policy, err := s.current()
+	if err != nil {
+		return false, err
 	}
+	return policy.Allows(userID, userRoles, requiredPermission), nil
+}
 
-	return &permissionServiceImpl{
-		logger:          logger,
-		validRoles:      validRoles,
-		rolePermissions: rolePermissions,
-	}, nil
+func (s *permissionServiceImpl) IsValidRole(role string) (bool, error) {
+	policy, err := s.current()
+	if err != nil {
+		return false, err
+	}
+	return policy.IsRole(role), nil
 }
 
-func (s *permissionServiceImpl) IsValidRole(role string) bool {
-	_, ok := s.validRoles[role]
-	return ok
+func (s *permissionServiceImpl) Invalidate() {
+	s.mu.Lock()
+	s.checkedAt = time.Time{}
+	s.mu.Unlock()
 }
 
-func (s *permissionServiceImpl) HashPermission(userRoles []string, requiredPermission string) bool {
-	for _, userRole := range userRoles {
-		if perms, ok := s.rolePermissions[userRole]; ok {
-			for _, perm := range perms {
-				if perm == requiredPermission {
-					return true
-				}
-			}
+func (s *permissionServiceImpl) current() (*authz.Policy, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.policy != nil && time.Since(s.checkedAt) < policyCheckInterval {
+		return s.policy, nil
+	}
+
+	if s.policy != nil {
+		version, err := s.rbacRepo.PolicyVersion()
+		if err != nil {
+			return nil, fmt.Errorf("failed to check policy version: %w", err)
+		}
+		if version == s.policy.Version {
+			s.checkedAt = time.Now()
+			return s.policy, nil
 		}
 	}
-	return false
+
+	policy, err := s.rbacRepo.LoadPolicy()
+	if err != nil {
+		return nil, fmt.Errorf("failed to load policy: %w", err)
+	}
+	s.logger.Info("Authorization policy loaded", zap.Int64("version", policy.Version), zap.Int("roles", len(policy.Roles)))
+	s.policy, s.checkedAt = policy, time.Now()
+	return policy, nil
 }
@@ -1,7 +1,10 @@
 package handler
 
 import (
-	service "profile-gold/internal/service/account"
+	"errors"
+	"profile-gold/internal/model"
+	accountService "profile-gold/internal/service/account"
+	service "profile-gold/internal/service/common"
 	"profile-gold/internal/utils"
 
 	"github.com/gofiber/fiber/v2"
@@ -9,10 +12,10 @@ import (
 )
 
 type AccountHandler struct {
-	userService service.AccountService
+	userService accountService.AccountService
 }
 
-func NewAccountHandler(us service.AccountService) *AccountHandler {
+func NewAccountHandler(us accountService.AccountService) *AccountHandler {
 	if us == nil {
 		utils.Log.Fatal("UserService cannot be nil for ProfileHandler in Profile Manager.")
 	}
@@ -25,9 +28,26 @@ func (h *AccountHandler) ChangeUsername(c *fiber.Ctx) error {
 	return c.Status(fiber.StatusOK).JSON(fiber.Map{"message": "Change Username endpoint hit (placeholder)"})
 }
 
+// ChangePassword keeps the caller's session, passed as ?current=, and signs out all the others.
 func (h *AccountHandler) ChangePassword(c *fiber.Ctx) error {
-	utils.Log.Info("ChangePassword endpoint hit. Placeholder.", zap.String("ip", c.IP()))
-	return c.Status(fiber.StatusOK).JSON(fiber.Map{"message": "Change Password endpoint hit (placeholder)"})
+	var req model.ChangePasswordRequest
+	if err := c.BodyParser(&req); err != nil || req.OldPassword == "" || req.NewPassword == "" {
+		return c.Status(fiber.StatusBadRequest).JSON(model.ErrorResponse{Message: "Old and new passwords are required.", Code: "400"})
+	}
+
+	err := h.userService.ChangePassword(c.Params("userID"), c.Query("current"), req.OldPassword, req.NewPassword)
+	if err != nil {
+		switch {
+		case errors.Is(err, service.ErrWeakPassword):
+			return c.Status(fiber.StatusBadRequest).JSON(model.ErrorResponse{Message: "Password does not meet the password policy.", Code: "400", Details: err.Error()})
+		case errors.Is(err, service.ErrUserNotFound), errors.Is(err, service.ErrInvalidCredentials):
+			return writeTwoFAError(c, err)
+		default:
+			utils.Log.Error("Profile Manager Handler: password change failed", zap.Error(err))
+			return c.Status(fiber.StatusInternalServerError).JSON(model.ErrorResponse{Message: "Internal server error.", Code: "500"})
+		}
+	}
+	return c.Status(fiber.StatusOK).JSON(model.AuthResponse{Message: "Password changed successfully. Other sessions were signed out."})
 }
 
 func (h *AccountHandler) UploadProfilePicture(c *fiber.Ctx) error {
@@ -36,18 +56,117 @@ func (h *AccountHandler) UploadProfilePicture(c *fiber.Ctx) error {
 }
 
 func (h *AccountHandler) GenerateTwoFASetup(c *fiber.Ctx) error {
-	utils.Log.Info("GenerateTwoFASetup endpoint hit. Placeholder.", zap.String("ip", c.IP()))
-	return c.Status(fiber.StatusOK).JSON(fiber.Map{"message": "Generate Two-Factor Authentication Setup endpoint hit (placeholder)"})
+	userID := c.Params("userID")
+	setup, err := h.userService.GenerateTwoFASetup(userID)
+	if err != nil {
+		return writeTwoFAError(c, err)
+	}
+	return c.Status(fiber.StatusOK).JSON(setup)
 }
 
 func (h *AccountHandler) VerifyAndEnableTwoFA(c *fiber.Ctx) error {
-	utils.Log.Info("VerifyAndEnableTwoFA endpoint hit. Placeholder.", zap.String("ip", c.IP()))
-	return c.Status(fiber.StatusOK).JSON(fiber.Map{"message": "Verify and Enable Two-Factor Authentication endpoint hit (placeholder)"})
+	var req model.EnableTwoFARequest
+	if err := c.BodyParser(&req); err != nil || req.Code == "" {
+		return c.Status(fiber.StatusBadRequest).JSON(model.ErrorResponse{Message: "2FA code is required.", Code: "400"})
+	}
+
+	codes, err := h.userService.VerifyAndEnableTwoFA(c.Params("userID"), req.Code)
+	if err != nil {
+		return writeTwoFAError(c, err)
+	}
+	return c.Status(fiber.StatusOK).JSON(model.RecoveryCodesResponse{
+		Message:       "Two-factor authentication enabled. Store the recovery codes in a safe place.",
+		RecoveryCodes: codes,
+		Remaining:     int64(len(codes)),
+	})
 }
 
 func (h *AccountHandler) DisableTwoFA(c *fiber.Ctx) error {
-	utils.Log.Info("DisableTwoFA endpoint hit. Placeholder.", zap.String("ip", c.IP()))
-	return c.Status(fiber.StatusOK).JSON(fiber.Map{"message": "Disable Two-Factor Authentication endpoint hit (placeholder)"})
+	var req model.DisableTwoFARequest
+	if err := c.BodyParser(&req); err != nil || req.Password == "" || req.Code == "" {
+		return c.Status(fiber.StatusBadRequest).JSON(model.ErrorResponse{Message: "Current password and 2FA code are required.", Code: "400"})
+	}
+
+	if err := h.userService.DisableTwoFA(c.Params("userID"), req.Password, req.Code, clientInfo(c, "")); err != nil {
+		return writeTwoFAError(c, err)
+	}
+	return c.Status(fiber.StatusOK).JSON(model.AuthResponse{Message: "Two-factor authentication disabled."})
 }
 
+func (h *AccountHandler) RegenerateRecoveryCodes(c *fiber.Ctx) error {
+	var req model.EnableTwoFARequest
+	if err := c.BodyParser(&req); err != nil || req.Code == "" {
+		return c.Status(fiber.StatusBadRequest).JSON(model.ErrorResponse{Message: "2FA code is required.", Code: "400"})
+	}
+
+	codes, err := h.userService.RegenerateRecoveryCodes(c.Params("userID"), req.Code, clientInfo(c, ""))
+	if err != nil {
+		return writeTwoFAError(c, err)
+	}
+	return c.Status(fiber.StatusOK).JSON(model.RecoveryCodesResponse{
+		Message:       "New recovery codes issued; the previous ones no longer work.",
+		RecoveryCodes: codes,
+		Remaining:     int64(len(codes)),
+	})
+}
 
+func (h *AccountHandler) GetRecoveryCodesStatus(c *fiber.Ctx) error {
+	remaining, err := h.userService.RecoveryCodesRemaining(c.Params("userID"))
+	if err != nil {
+		return writeTwoFAError(c, err)
+	}
+	return c.Status(fiber.StatusOK).JSON(model.RecoveryCodesResponse{Remaining: remaining})
+}
+
+func (h *AccountHandler) RequestMobileVerification(c *fiber.Ctx) error {
+	var req model.OTPRequest
+	if err := c.BodyParser(&req); err != nil || req.Mobile == "" {
+		return c.Status(fiber.StatusBadRequest).JSON(model.ErrorResponse{Message: "Mobile number is required.", Code: "400"})
+	}
+
+	if err := h.userService.RequestMobileVerification(c.Params("userID"), req.Mobile, clientInfo(c, "")); err != nil {
+		return writeOTPError(c, err)
+	}
+	return c.Status(fiber.StatusOK).JSON(model.MobileResponse{Message: "Verification code sent."})
+}
+
+func (h *AccountHandler) VerifyMobile(c *fiber.Ctx) error {
+	var req model.VerifyMobileRequest
+	if err := c.BodyParser(&req); err != nil || req.Code == "" {
+		return c.Status(fiber.StatusBadRequest).JSON(model.ErrorResponse{Message: "Code is required.", Code: "400"})
+	}
+
+	user, err := h.userService.VerifyMobile(c.Params("userID"), req.Code, clientInfo(c, ""))
+	if err != nil {
+		return writeOTPError(c, err)
+	}
+	resp := model.MobileResponse{Message: "Mobile number verified.", MobileVerifiedAt: user.MobileVerifiedAt}
+	if user.Mobile != nil {
+		resp.Mobile = *user.Mobile
+	}
+	return c.Status(fiber.StatusOK).JSON(resp)
+}
+
+// writeTwoFAError puts a stable reason in Details so the API Gateway can map it back to the same error.
+func writeTwoFAError(c *fiber.Ctx, err error) error {
+	if locked, werr := lockoutResponse(c, err); locked {
+		return werr
+	}
+	switch {
+	case errors.Is(err, service.ErrUserNotFound):
+		return c.Status(fiber.StatusNotFound).JSON(model.ErrorResponse{Message: "User not found.", Code: "404"})
+	case errors.Is(err, service.ErrTwoFAAlreadyEnabled):
+		return c.Status(fiber.StatusConflict).JSON(model.ErrorResponse{Message: "Two-factor authentication is already enabled.", Code: "409", Details: "already_enabled"})
+	case errors.Is(err, service.ErrTwoFANotEnabled):
+		return c.Status(fiber.StatusBadRequest).JSON(model.ErrorResponse{Message: "Two-factor authentication is not enabled.", Code: "400", Details: "not_enabled"})
+	case errors.Is(err, service.ErrTwoFASetupNotStarted):
+		return c.Status(fiber.StatusBadRequest).JSON(model.ErrorResponse{Message: "Generate a new 2FA secret first.", Code: "400", Details: "setup_not_started"})
+	case errors.Is(err, service.ErrInvalidTwoFACode):
+		return c.Status(fiber.StatusBadRequest).JSON(model.ErrorResponse{Message: "Invalid 2FA code.", Code: "400", Details: "invalid_code"})
+	case errors.Is(err, service.ErrInvalidCredentials):
+		return c.Status(fiber.StatusForbidden).JSON(model.ErrorResponse{Message: "Current password is incorrect.", Code: "403", Details: "invalid_password"})
+	default:
+		utils.Log.Error("Profile Manager Handler: 2FA account operation failed", zap.Error(err))
+		return c.Status(fiber.StatusInternalServerError).JSON(model.ErrorResponse{Message: "Internal server error.", Code: "500"})
+	}
+}
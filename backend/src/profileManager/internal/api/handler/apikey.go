@@ -0,0 +1,89 @@
+package handler
+
+import (
+	"errors"
+
+	"profile-gold/internal/model"
+	apiKeyService "profile-gold/internal/service/apikey"
+	service "profile-gold/internal/service/common"
+	"profile-gold/internal/utils"
+
+	"github.com/gofiber/fiber/v2"
+	"go.uber.org/zap"
+)
+
+type APIKeyHandler struct {
+	apiKeyService apiKeyService.APIKeyService
+}
+
+func NewAPIKeyHandler(as apiKeyService.APIKeyService) *APIKeyHandler {
+	if as == nil {
+		utils.Log.Fatal("APIKeyService cannot be nil for APIKeyHandler in Profile Manager.")
+	}
+	return &APIKeyHandler{apiKeyService: as}
+}
+
+func (h *APIKeyHandler) ListAPIKeys(c *fiber.Ctx) error {
+	keys, err := h.apiKeyService.ListAPIKeys(c.Params("userID"))
+	if err != nil {
+		return writeAPIKeyError(c, err)
+	}
+	return c.Status(fiber.StatusOK).JSON(model.APIKeysResponse{APIKeys: keys})
+}
+
+func (h *APIKeyHandler) CreateAPIKey(c *fiber.Ctx) error {
+	var req model.CreateAPIKeyRequest
+	if err := c.BodyParser(&req); err != nil || req.UserID == "" {
+		return c.Status(fiber.StatusBadRequest).JSON(model.ErrorResponse{Message: "Invalid request body.", Code: "400"})
+	}
+	secret, key, err := h.apiKeyService.CreateAPIKey(req)
+	if err != nil {
+		return writeAPIKeyError(c, err)
+	}
+	return c.Status(fiber.StatusCreated).JSON(model.CreateAPIKeyResponse{
+		Message: "API key created. Copy it now; it will not be shown again.",
+		Key:     secret,
+		APIKey:  *key,
+	})
+}
+
+func (h *APIKeyHandler) RevokeAPIKey(c *fiber.Ctx) error {
+	key, err := h.apiKeyService.RevokeAPIKey(c.Params("userID"), c.Params("keyID"))
+	if err != nil {
+		return writeAPIKeyError(c, err)
+	}
+	return c.Status(fiber.StatusOK).JSON(key)
+}
+
+// VerifyAPIKey is called by the API Gateway for every key it has not verified recently.
+func (h *APIKeyHandler) VerifyAPIKey(c *fiber.Ctx) error {
+	var req model.VerifyAPIKeyRequest
+	if err := c.BodyParser(&req); err != nil || req.Key == "" {
+		return c.Status(fiber.StatusBadRequest).JSON(model.ErrorResponse{Message: "API key is required.", Code: "400"})
+	}
+	principal, err := h.apiKeyService.VerifyAPIKey(req.Key, clientInfo(c, ""))
+	if err != nil {
+		return writeAPIKeyError(c, err)
+	}
+	return c.Status(fiber.StatusOK).JSON(principal)
+}
+
+// writeAPIKeyError puts a stable reason in Details so the gateway can map it back to the same error.
+func writeAPIKeyError(c *fiber.Ctx, err error) error {
+	switch {
+	case errors.Is(err, service.ErrInvalidAPIKey):
+		return c.Status(fiber.StatusUnauthorized).JSON(model.ErrorResponse{Message: "Invalid, expired or revoked API key.", Code: "401", Details: "invalid_api_key"})
+	case errors.Is(err, service.ErrAPIKeyNotFound):
+		return c.Status(fiber.StatusNotFound).JSON(model.ErrorResponse{Message: "API key not found.", Code: "404", Details: "api_key_not_found"})
+	case errors.Is(err, service.ErrUserNotFound):
+		return c.Status(fiber.StatusNotFound).JSON(model.ErrorResponse{Message: "User not found.", Code: "404", Details: "user_not_found"})
+	case errors.Is(err, service.ErrInvalidAPIKeyRequest):
+		return c.Status(fiber.StatusBadRequest).JSON(model.ErrorResponse{Message: err.Error(), Code: "400", Details: "invalid_api_key_request"})
+	case errors.Is(err, service.ErrUnknownPermission):
+		return c.Status(fiber.StatusBadRequest).JSON(model.ErrorResponse{Message: err.Error(), Code: "400", Details: "unknown_permission"})
+	case errors.Is(err, service.ErrPermissionNotHeld):
+		return c.Status(fiber.StatusForbidden).JSON(model.ErrorResponse{Message: err.Error(), Code: "403", Details: "permission_not_held"})
+	}
+	utils.Log.Error("Profile Manager Handler: API key operation failed", zap.Error(err))
+	return c.Status(fiber.StatusInternalServerError).JSON(model.ErrorResponse{Message: "Internal server error.", Code: "500"})
+}
@@ -1,12 +1,16 @@
 package handler
 
 import (
+	"crypto/subtle"
 	"errors"
+	"os"
 	"profile-gold/internal/model"
 	authService "profile-gold/internal/service/auth"
 	service "profile-gold/internal/service/common"
 	"profile-gold/internal/utils"
+	"strconv"
 	"strings"
+	"time"
 
 	"github.com/gofiber/fiber/v2"
 	"go.uber.org/zap"
@@ -51,6 +55,13 @@ func (h *AuthHandler) Register(c *fiber.Ctx) error {
 				Code:    "409",
 			})
 		}
+		if errors.Is(err, service.ErrWeakPassword) {
+			return c.Status(fiber.StatusBadRequest).JSON(model.ErrorResponse{
+				Message: "Password does not meet the password policy.",
+				Code:    "400",
+				Details: err.Error(),
+			})
+		}
 		return c.Status(fiber.StatusInternalServerError).JSON(model.ErrorResponse{
 			Message: "Internal server error during registration.",
 			Code:    "500",
@@ -81,10 +92,11 @@ func (h *AuthHandler) Login(c *fiber.Ctx) error {
 		})
 	}
 
-	user, token, claims, err := h.userService.AuthenticateUser(req.Username, req.Password)
+	result, err := h.userService.AuthenticateUser(req.Username, req.Password, clientInfo(c, req.DeviceName))
 	if err != nil {
-		utils.Log.Error("User authentication failed in Profile Manager service", zap.String("username", req.Username), zap.Error(err))
-
+		if locked, werr := lockoutResponse(c, err); locked {
+			return werr
+		}
 		if errors.Is(err, service.ErrInvalidCredentials) {
 			return c.Status(fiber.StatusUnauthorized).JSON(model.ErrorResponse{
 				Code:    "401",
@@ -97,20 +109,89 @@ func (h *AuthHandler) Login(c *fiber.Ctx) error {
 				Message: "Invalid username or password",
 			})
 		}
+		utils.Log.Error("User authentication failed in Profile Manager service", zap.String("username", req.Username), zap.Error(err))
 		return c.Status(fiber.StatusInternalServerError).JSON(model.ErrorResponse{
 			Message: "Internal server error during authentication",
 			Code:    "500",
 		})
 	}
 
-	utils.Log.Info("User logged in successfully in Profile Manager", zap.String("username", user.Username), zap.String("role", string(user.Roles)))
+	if result.Challenge != nil {
+		return c.Status(fiber.StatusOK).JSON(model.AuthResponse{
+			Message:        "Two-factor authentication required",
+			TwoFARequired:  true,
+			ChallengeToken: result.Challenge.Token,
+			Exp:            result.Challenge.ExpiresAt.Unix(),
+		})
+	}
 
-	return c.Status(fiber.StatusOK).JSON(model.AuthResponse{
-		Message: "Login successful",
-		Token:   token,
-		User:    user,
-		Exp:     claims.ExpiresAt.Unix(),
-	})
+	utils.Log.Info("User logged in successfully in Profile Manager", zap.String("username", result.User.Username), zap.String("role", string(result.User.Roles)))
+
+	return c.Status(fiber.StatusOK).JSON(sessionResponse("Login successful", result))
+}
+
+// Refresh rotates the refresh token: the old one stops working and a new pair is returned.
+func (h *AuthHandler) Refresh(c *fiber.Ctx) error {
+	var req model.RefreshTokenRequest
+	if err := c.BodyParser(&req); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(model.ErrorResponse{
+			Message: "Invalid request body format.",
+			Code:    "400",
+		})
+	}
+	if req.RefreshToken == "" {
+		return c.Status(fiber.StatusBadRequest).JSON(model.ErrorResponse{
+			Message: "Refresh token is required.",
+			Code:    "400",
+		})
+	}
+
+	result, err := h.userService.RefreshSession(req.RefreshToken, clientInfo(c, ""))
+	if err != nil {
+		switch {
+		case errors.Is(err, service.ErrRefreshTokenReused):
+			return c.Status(fiber.StatusUnauthorized).JSON(model.ErrorResponse{Message: "Session was revoked because its refresh token was reused. Please log in again.", Code: "401", Details: "refresh_token_reused"})
+		case errors.Is(err, service.ErrInvalidToken):
+			return c.Status(fiber.StatusUnauthorized).JSON(model.ErrorResponse{Message: "Refresh token is invalid or has expired.", Code: "401"})
+		default:
+			utils.Log.Error("Profile Manager Handler: token refresh failed in service layer", zap.Error(err))
+			return c.Status(fiber.StatusInternalServerError).JSON(model.ErrorResponse{Message: "Internal server error during token refresh.", Code: "500"})
+		}
+	}
+	return c.Status(fiber.StatusOK).JSON(sessionResponse("Token refreshed", result))
+}
+
+func sessionResponse(message string, result *model.LoginResult) model.AuthResponse {
+	return model.AuthResponse{
+		Message:      message,
+		Token:        result.Token,
+		User:         result.User,
+		Exp:          result.Claims.ExpiresAt.Unix(),
+		RefreshToken: result.RefreshToken,
+		RefreshExp:   result.RefreshExpiresAt.Unix(),
+		SessionID:    result.Claims.SessionID,
+	}
+}
+
+// clientInfo describes the end user's device. The API Gateway forwards the original address and user agent
+// in X-Client-IP and X-Client-User-Agent; they are trusted only with a valid X-Service-Secret, otherwise any
+// direct caller could pick its own IP and slip past the per-IP throttle.
+func clientInfo(c *fiber.Ctx, deviceName string) model.ClientInfo {
+	ip, userAgent := c.IP(), c.Get(fiber.HeaderUserAgent)
+	if fromGateway(c) {
+		if forwarded := c.Get("X-Client-IP"); forwarded != "" {
+			ip = forwarded
+		}
+		if forwarded := c.Get("X-Client-User-Agent"); forwarded != "" {
+			userAgent = forwarded
+		}
+	}
+	return model.ClientInfo{IPAddress: ip, UserAgent: userAgent, DeviceName: deviceName}
+}
+
+func fromGateway(c *fiber.Ctx) bool {
+	expected := os.Getenv("PROFILE_MANAGER_SERVICE_SECRET")
+	return expected != "" && subtle.ConstantTimeCompare([]byte(c.Get("X-Service-Secret")), []byte(expected)) == 1
 }
 func (h *AuthHandler) Logout(c *fiber.Ctx) error {
 	authHeader := c.Get("Authorization")
@@ -178,8 +259,11 @@ func (h *AuthHandler) RequestPasswordReset(c *fiber.Ctx) error {
         })
     }
 
-    err := h.userService.RequestPasswordReset(req.Email) 
+    err := h.userService.RequestPasswordReset(req.Email, clientInfo(c, ""))
     if err != nil {
+        if locked, werr := lockoutResponse(c, err); locked {
+            return werr
+        }
         utils.Log.Error("Profile Manager Handler: Failed to request password reset in service layer", zap.String("email", req.Email), zap.Error(err))
         if errors.Is(err, service.ErrUserNotFound) {
             utils.Log.Warn("Profile Manager Handler: Password reset requested for non-existent user, but returning success for security.", zap.String("email", req.Email))
@@ -193,11 +277,163 @@ func (h *AuthHandler) RequestPasswordReset(c *fiber.Ctx) error {
 }
 
 func (h *AuthHandler) ResetPassword(c *fiber.Ctx) error {
-	utils.Log.Info("ResetPassword endpoint hit. Placeholder.", zap.String("ip", c.IP()))
-	return c.Status(fiber.StatusOK).JSON(fiber.Map{"message": "Reset Password endpoint hit (placeholder)"})
+	var req model.ResetPasswordRequest
+	if err := c.BodyParser(&req); err != nil {
+		utils.Log.Error("Profile Manager Handler: Failed to parse reset password body", zap.Error(err))
+		return c.Status(fiber.StatusBadRequest).JSON(model.ErrorResponse{
+			Message: "Invalid request body format.",
+			Code:    "400",
+		})
+	}
+	if req.Token == "" || req.NewPassword == "" {
+		return c.Status(fiber.StatusBadRequest).JSON(model.ErrorResponse{
+			Message: "Token and new password are required.",
+			Code:    "400",
+		})
+	}
+
+	err := h.userService.ResetPassword(req.Token, req.NewPassword)
+	if err != nil {
+		switch {
+		case errors.Is(err, service.ErrWeakPassword):
+			return c.Status(fiber.StatusBadRequest).JSON(model.ErrorResponse{
+				Message: "Password does not meet the password policy.",
+				Code:    "400",
+				Details: err.Error(),
+			})
+		case errors.Is(err, service.ErrInvalidToken):
+			return c.Status(fiber.StatusUnauthorized).JSON(model.ErrorResponse{
+				Message: "Reset link is invalid or has expired.",
+				Code:    "401",
+			})
+		default:
+			utils.Log.Error("Profile Manager Handler: Failed to reset password in service layer", zap.Error(err))
+			return c.Status(fiber.StatusInternalServerError).JSON(model.ErrorResponse{Message: "Internal server error during password reset.", Code: "500"})
+		}
+	}
+
+	return c.Status(fiber.StatusOK).JSON(model.AuthResponse{Message: "رمز عبور با موفقیت تغییر کرد. لطفاً دوباره وارد شوید."})
 }
 
 func (h *AuthHandler) VerifyTwoFA(c *fiber.Ctx) error {
-	utils.Log.Info("VerifyTwoFA endpoint hit. Placeholder.", zap.String("ip", c.IP()))
-	return c.Status(fiber.StatusOK).JSON(fiber.Map{"message": "Verify Two-Factor Authentication endpoint hit (placeholder)"})
+	var req model.TwoFALoginRequest
+	if err := c.BodyParser(&req); err != nil {
+		utils.Log.Error("Profile Manager Handler: Failed to parse 2FA verification body", zap.Error(err))
+		return c.Status(fiber.StatusBadRequest).JSON(model.ErrorResponse{
+			Message: "Invalid request body format.",
+			Code:    "400",
+		})
+	}
+	if req.ChallengeToken == "" || req.Code == "" {
+		return c.Status(fiber.StatusBadRequest).JSON(model.ErrorResponse{
+			Message: "Challenge token and 2FA code are required.",
+			Code:    "400",
+		})
+	}
+
+	result, err := h.userService.VerifyTwoFA(req.ChallengeToken, req.Code, clientInfo(c, req.DeviceName))
+	if err != nil {
+		if locked, werr := lockoutResponse(c, err); locked {
+			return werr
+		}
+		switch {
+		case errors.Is(err, service.ErrInvalidTwoFACode):
+			return c.Status(fiber.StatusUnauthorized).JSON(model.ErrorResponse{Message: "Invalid 2FA code.", Code: "401"})
+		case errors.Is(err, service.ErrInvalidToken):
+			return c.Status(fiber.StatusUnauthorized).JSON(model.ErrorResponse{Message: "2FA session is invalid or has expired. Please log in again.", Code: "401", Details: "challenge_expired"})
+		case errors.Is(err, service.ErrTooManyAttempts):
+			return c.Status(fiber.StatusTooManyRequests).JSON(model.ErrorResponse{Message: "Too many invalid 2FA codes. Please log in again.", Code: "429"})
+		default:
+			utils.Log.Error("Profile Manager Handler: 2FA verification failed in service layer", zap.Error(err))
+			return c.Status(fiber.StatusInternalServerError).JSON(model.ErrorResponse{Message: "Internal server error during 2FA verification.", Code: "500"})
+		}
+	}
+
+	return c.Status(fiber.StatusOK).JSON(sessionResponse("Login successful with 2FA", result))
+}
+
+func (h *AuthHandler) RequestLoginOTP(c *fiber.Ctx) error {
+	var req model.OTPRequest
+	if err := c.BodyParser(&req); err != nil || req.Mobile == "" {
+		return c.Status(fiber.StatusBadRequest).JSON(model.ErrorResponse{Message: "Mobile number is required.", Code: "400"})
+	}
+
+	if err := h.userService.RequestLoginOTP(req.Mobile, clientInfo(c, "")); err != nil {
+		return writeOTPError(c, err)
+	}
+	return c.Status(fiber.StatusOK).JSON(model.AuthResponse{Message: "اگر این شماره برای حسابی تأیید شده باشد، کد ورود برای آن پیامک می‌شود."})
+}
+
+func (h *AuthHandler) VerifyLoginOTP(c *fiber.Ctx) error {
+	var req model.OTPLoginRequest
+	if err := c.BodyParser(&req); err != nil || req.Mobile == "" || req.Code == "" {
+		return c.Status(fiber.StatusBadRequest).JSON(model.ErrorResponse{Message: "Mobile number and code are required.", Code: "400"})
+	}
+
+	result, err := h.userService.VerifyLoginOTP(req.Mobile, req.Code, clientInfo(c, req.DeviceName))
+	if err != nil {
+		return writeOTPError(c, err)
+	}
+	if result.Challenge != nil {
+		return c.Status(fiber.StatusOK).JSON(model.AuthResponse{
+			Message:        "Two-factor authentication required",
+			TwoFARequired:  true,
+			ChallengeToken: result.Challenge.Token,
+			Exp:            result.Challenge.ExpiresAt.Unix(),
+		})
+	}
+	return c.Status(fiber.StatusOK).JSON(sessionResponse("Login successful with SMS code", result))
+}
+
+// writeOTPError is shared by SMS login and mobile verification. Details carries a stable reason for the API Gateway.
+func writeOTPError(c *fiber.Ctx, err error) error {
+	if locked, werr := lockoutResponse(c, err); locked {
+		return werr
+	}
+	switch {
+	case errors.Is(err, service.ErrInvalidMobile):
+		return c.Status(fiber.StatusBadRequest).JSON(model.ErrorResponse{Message: "Invalid mobile number.", Code: "400", Details: "invalid_mobile"})
+	case errors.Is(err, service.ErrInvalidOTP):
+		return c.Status(fiber.StatusUnauthorized).JSON(model.ErrorResponse{Message: "Invalid or expired code.", Code: "401", Details: "invalid_code"})
+	case errors.Is(err, service.ErrTooManyAttempts):
+		return c.Status(fiber.StatusTooManyRequests).JSON(model.ErrorResponse{Message: "Too many invalid codes. Please request a new code.", Code: "429", Details: "too_many_attempts"})
+	case errors.Is(err, service.ErrMobileInUse):
+		return c.Status(fiber.StatusConflict).JSON(model.ErrorResponse{Message: "This mobile number is already used by another user.", Code: "409", Details: "mobile_in_use"})
+	case errors.Is(err, service.ErrUserNotFound):
+		return c.Status(fiber.StatusNotFound).JSON(model.ErrorResponse{Message: "User not found.", Code: "404"})
+	case errors.Is(err, service.ErrSMSDeliveryFailed):
+		return c.Status(fiber.StatusBadGateway).JSON(model.ErrorResponse{Message: "The SMS could not be sent. Please try again.", Code: "502", Details: "sms_failed"})
+	default:
+		utils.Log.Error("Profile Manager Handler: SMS code operation failed", zap.Error(err))
+		return c.Status(fiber.StatusInternalServerError).JSON(model.ErrorResponse{Message: "Internal server error.", Code: "500"})
+	}
+}
+
+// UnlockUser is called by the gateway for an admin to lift a user's lockout before it expires.
+func (h *AuthHandler) UnlockUser(c *fiber.Ctx) error {
+	userID := c.Params("userID")
+	if err := h.userService.UnlockUser(userID); err != nil {
+		if errors.Is(err, service.ErrUserNotFound) {
+			return c.Status(fiber.StatusNotFound).JSON(model.ErrorResponse{Message: "User not found.", Code: "404"})
+		}
+		utils.Log.Error("Profile Manager Handler: Failed to unlock user", zap.String("user_id", userID), zap.Error(err))
+		return c.Status(fiber.StatusInternalServerError).JSON(model.ErrorResponse{Message: "Internal server error while unlocking user.", Code: "500"})
+	}
+	return c.Status(fiber.StatusOK).JSON(model.AuthResponse{Message: "User unlocked successfully."})
+}
+
+// lockoutResponse writes 429 with Retry-After in seconds and reports true when err is a lockout; the returned error is
+// the result of writing the response.
+func lockoutResponse(c *fiber.Ctx, err error) (bool, error) {
+	var lockout *service.LockoutError
+	if !errors.As(err, &lockout) {
+		return false, nil
+	}
+	seconds := int64((lockout.RetryAfter + time.Second - 1) / time.Second)
+	c.Set(fiber.HeaderRetryAfter, strconv.FormatInt(seconds, 10))
+	return true, c.Status(fiber.StatusTooManyRequests).JSON(model.ErrorResponse{
+		Message: "Too many failed attempts. Please try again later.",
+		Code:    "429",
+		Details: "account_locked",
+	})
 }
@@ -0,0 +1,137 @@
+package handler
+
+import (
+	"errors"
+	"strconv"
+	"strings"
+
+	"profile-gold/internal/model"
+	service "profile-gold/internal/service/common"
+	rbacService "profile-gold/internal/service/rbac"
+	"profile-gold/internal/utils"
+
+	"common-gold/authz"
+
+	"github.com/gofiber/fiber/v2"
+	"go.uber.org/zap"
+)
+
+type RBACHandler struct {
+	rbacService rbacService.RBACService
+}
+
+func NewRBACHandler(rs rbacService.RBACService) *RBACHandler {
+	if rs == nil {
+		utils.Log.Fatal("RBACService cannot be nil for RBACHandler in Profile Manager.")
+	}
+	return &RBACHandler{rbacService: rs}
+}
+
+// GetPolicy answers 304 when If-None-Match already names the current version, so polling services stay cheap.
+func (h *RBACHandler) GetPolicy(c *fiber.Ctx) error {
+	var known int64
+	if tag := strings.Trim(c.Get(fiber.HeaderIfNoneMatch), `"`); tag != "" {
+		known, _ = strconv.ParseInt(tag, 10, 64)
+	}
+	policy, err := h.rbacService.Policy(known)
+	if err != nil {
+		return writeRBACError(c, err)
+	}
+	if policy == nil {
+		c.Set(fiber.HeaderETag, authz.ETag(known))
+		return c.SendStatus(fiber.StatusNotModified)
+	}
+	c.Set(fiber.HeaderETag, authz.ETag(policy.Version))
+	return c.Status(fiber.StatusOK).JSON(policy)
+}
+
+func (h *RBACHandler) ListRoles(c *fiber.Ctx) error {
+	roles, err := h.rbacService.ListRoles()
+	if err != nil {
+		return writeRBACError(c, err)
+	}
+	return c.Status(fiber.StatusOK).JSON(model.RolesResponse{Roles: roles})
+}
+
+func (h *RBACHandler) CreateRole(c *fiber.Ctx) error {
+	var req model.RoleRequest
+	if err := c.BodyParser(&req); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(model.ErrorResponse{Message: "Invalid request body.", Code: "400"})
+	}
+	role, err := h.rbacService.CreateRole(req)
+	if err != nil {
+		return writeRBACError(c, err)
+	}
+	return c.Status(fiber.StatusCreated).JSON(role)
+}
+
+func (h *RBACHandler) UpdateRole(c *fiber.Ctx) error {
+	var req model.RoleRequest
+	if err := c.BodyParser(&req); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(model.ErrorResponse{Message: "Invalid request body.", Code: "400"})
+	}
+	role, err := h.rbacService.UpdateRole(c.Params("name"), req)
+	if err != nil {
+		return writeRBACError(c, err)
+	}
+	return c.Status(fiber.StatusOK).JSON(role)
+}
+
+func (h *RBACHandler) DeleteRole(c *fiber.Ctx) error {
+	if err := h.rbacService.DeleteRole(c.Params("name")); err != nil {
+		return writeRBACError(c, err)
+	}
+	return c.SendStatus(fiber.StatusNoContent)
+}
+
+func (h *RBACHandler) ListPermissions(c *fiber.Ctx) error {
+	perms, err := h.rbacService.ListPermissions()
+	if err != nil {
+		return writeRBACError(c, err)
+	}
+	return c.Status(fiber.StatusOK).JSON(model.PermissionsResponse{Permissions: perms})
+}
+
+func (h *RBACHandler) GetUserPermissions(c *fiber.Ctx) error {
+	resp, err := h.rbacService.GetUserPermissions(c.Params("userID"))
+	if err != nil {
+		return writeRBACError(c, err)
+	}
+	return c.Status(fiber.StatusOK).JSON(resp)
+}
+
+func (h *RBACHandler) SetUserPermissions(c *fiber.Ctx) error {
+	var req model.UserPermissionsRequest
+	if err := c.BodyParser(&req); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(model.ErrorResponse{Message: "Invalid request body.", Code: "400"})
+	}
+	resp, err := h.rbacService.SetUserPermissions(c.Params("userID"), req)
+	if err != nil {
+		return writeRBACError(c, err)
+	}
+	return c.Status(fiber.StatusOK).JSON(resp)
+}
+
+// writeRBACError puts a stable reason in Details so the gateway can map it back to the same error.
+func writeRBACError(c *fiber.Ctx, err error) error {
+	switch {
+	case errors.Is(err, service.ErrRoleNotFound):
+		return c.Status(fiber.StatusNotFound).JSON(model.ErrorResponse{Message: "Role not found.", Code: "404", Details: "role_not_found"})
+	case errors.Is(err, service.ErrUserNotFound):
+		return c.Status(fiber.StatusNotFound).JSON(model.ErrorResponse{Message: "User not found.", Code: "404", Details: "user_not_found"})
+	case errors.Is(err, service.ErrRoleAlreadyExists):
+		return c.Status(fiber.StatusConflict).JSON(model.ErrorResponse{Message: "A role with this name already exists.", Code: "409", Details: "role_exists"})
+	case errors.Is(err, service.ErrRoleInUse):
+		return c.Status(fiber.StatusConflict).JSON(model.ErrorResponse{Message: err.Error(), Code: "409", Details: "role_in_use"})
+	case errors.Is(err, service.ErrSystemRole):
+		return c.Status(fiber.StatusConflict).JSON(model.ErrorResponse{Message: "Built-in roles cannot be deleted.", Code: "409", Details: "system_role"})
+	case errors.Is(err, service.ErrUnknownPermission):
+		return c.Status(fiber.StatusBadRequest).JSON(model.ErrorResponse{Message: err.Error(), Code: "400", Details: "unknown_permission"})
+	case errors.Is(err, service.ErrInvalidRoleName):
+		return c.Status(fiber.StatusBadRequest).JSON(model.ErrorResponse{Message: err.Error(), Code: "400", Details: "invalid_role_name"})
+	case errors.Is(err, service.ErrConflictingOverride):
+		return c.Status(fiber.StatusBadRequest).JSON(model.ErrorResponse{Message: err.Error(), Code: "400", Details: "conflicting_override"})
+	}
+	utils.Log.Error("Profile Manager Handler: RBAC operation failed", zap.Error(err))
+	return c.Status(fiber.StatusInternalServerError).JSON(model.ErrorResponse{Message: "Internal server error.", Code: "500"})
+}
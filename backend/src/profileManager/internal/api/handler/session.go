@@ -0,0 +1,59 @@
+package handler
+
+import (
+	"errors"
+	"profile-gold/internal/model"
+	service "profile-gold/internal/service/common"
+	sessionService "profile-gold/internal/service/session"
+	"profile-gold/internal/utils"
+
+	"github.com/gofiber/fiber/v2"
+	"go.uber.org/zap"
+)
+
+type SessionHandler struct {
+	sessionService sessionService.SessionService
+}
+
+func NewSessionHandler(ss sessionService.SessionService) *SessionHandler {
+	if ss == nil {
+		utils.Log.Fatal("SessionService cannot be nil for SessionHandler in Profile Manager.")
+	}
+	return &SessionHandler{sessionService: ss}
+}
+
+// ListSessions expects the caller's own session ID in the "current" query parameter so it can be flagged.
+func (h *SessionHandler) ListSessions(c *fiber.Ctx) error {
+	sessions, err := h.sessionService.ListSessions(c.Params("userID"), c.Query("current"))
+	if err != nil {
+		return writeSessionError(c, err)
+	}
+	return c.Status(fiber.StatusOK).JSON(model.SessionsResponse{Sessions: sessions})
+}
+
+func (h *SessionHandler) RevokeSession(c *fiber.Ctx) error {
+	if err := h.sessionService.RevokeSession(c.Params("userID"), c.Params("sessionID")); err != nil {
+		return writeSessionError(c, err)
+	}
+	return c.Status(fiber.StatusOK).JSON(model.RevokeSessionsResponse{Message: "Session signed out.", Revoked: 1})
+}
+
+func (h *SessionHandler) RevokeOtherSessions(c *fiber.Ctx) error {
+	current := c.Query("current")
+	if current == "" {
+		return c.Status(fiber.StatusBadRequest).JSON(model.ErrorResponse{Message: "Current session ID is required.", Code: "400"})
+	}
+	revoked, err := h.sessionService.RevokeOtherSessions(c.Params("userID"), current)
+	if err != nil {
+		return writeSessionError(c, err)
+	}
+	return c.Status(fiber.StatusOK).JSON(model.RevokeSessionsResponse{Message: "All other sessions signed out.", Revoked: revoked})
+}
+
+func writeSessionError(c *fiber.Ctx, err error) error {
+	if errors.Is(err, service.ErrSessionNotFound) {
+		return c.Status(fiber.StatusNotFound).JSON(model.ErrorResponse{Message: "Session not found.", Code: "404"})
+	}
+	utils.Log.Error("Profile Manager Handler: session operation failed", zap.Error(err))
+	return c.Status(fiber.StatusInternalServerError).JSON(model.ErrorResponse{Message: "Internal server error.", Code: "500"})
+}
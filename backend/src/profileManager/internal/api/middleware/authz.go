@@ -11,6 +11,7 @@ import (
 
 	"profile-gold/internal/api/authz"
 	"profile-gold/internal/model"
+	redisdb "profile-gold/internal/repository/db/redisDb"
 	"profile-gold/internal/utils"
 )
 
@@ -18,6 +19,7 @@ type AuthZMiddleware struct {
 	logger            *zap.Logger
 	permissionService authz.PermissionService
 	jwtValidator      utils.JWTValidator
+	tokenRepo         redisdb.TokenRepository
 }
 
 func (m *AuthZMiddleware) AuthorizeMiddleware(requiredPermission string) fiber.Handler {
@@ -43,6 +45,15 @@ func (m *AuthZMiddleware) AuthorizeMiddleware(requiredPermission string) fiber.H
 			return c.Status(fiber.StatusUnauthorized).JSON(model.ErrorResponse{Message: "Invalid or expired token", Details: err.Error()})
 		}
 
+		if revoked, err := m.isRevoked(tokenString, claims); err != nil {
+			m.logger.Error("Failed to check token revocation", zap.Error(err), zap.String("userID", claims.UserID))
+			return c.Status(fiber.StatusInternalServerError).JSON(model.ErrorResponse{Message: "Internal server error: token check failed."})
+		} else if revoked {
+			m.logger.Warn("Revoked token used for protected route",
+				zap.String("userID", claims.UserID), zap.String("path", c.OriginalURL()))
+			return c.Status(fiber.StatusUnauthorized).JSON(model.ErrorResponse{Message: "Invalid or expired token"})
+		}
+
 		c.Locals("userID", claims.UserID)
 		c.Locals("username", claims.Username)
 
@@ -54,7 +65,12 @@ func (m *AuthZMiddleware) AuthorizeMiddleware(requiredPermission string) fiber.H
 		}
 		c.Locals("userRoles", userRoles)
 
-		if !m.permissionService.HashPermission(userRoles, requiredPermission) {
+		allowed, err := m.permissionService.HasPermission(claims.UserID, userRoles, requiredPermission)
+		if err != nil {
+			m.logger.Error("Failed to evaluate permission", zap.Error(err), zap.String("userID", claims.UserID))
+			return c.Status(fiber.StatusInternalServerError).JSON(model.ErrorResponse{Message: "Internal server error: permission check failed."})
+		}
+		if !allowed {
 			m.logger.Warn("Access denied: User does not have required permission",
 				zap.String("userID", claims.UserID),
 				zap.Strings("user_roles", userRoles),
@@ -66,7 +82,26 @@ func (m *AuthZMiddleware) AuthorizeMiddleware(requiredPermission string) fiber.H
 		return c.Next()
 	}
 }
-func NewAuthZMiddleware(permService authz.PermissionService, logger *zap.Logger, jwtValidator utils.JWTValidator) (*AuthZMiddleware, error) {
+// isRevoked reports whether the token was logged out, belongs to a revoked session or was issued before
+// the user's sessions were revoked.
+func (m *AuthZMiddleware) isRevoked(tokenString string, claims *model.CustomClaims) (bool, error) {
+	blacklisted, err := m.tokenRepo.IsTokenBlacklisted(tokenString)
+	if err != nil || blacklisted {
+		return blacklisted, err
+	}
+	if claims.SessionID != "" {
+		revoked, err := m.tokenRepo.IsSessionRevoked(claims.SessionID)
+		if err != nil || revoked {
+			return revoked, err
+		}
+	}
+	if claims.IssuedAt == nil {
+		return false, nil
+	}
+	return m.tokenRepo.IsUserTokenRevoked(claims.UserID, claims.IssuedAt.Time)
+}
+
+func NewAuthZMiddleware(permService authz.PermissionService, logger *zap.Logger, jwtValidator utils.JWTValidator, tokenRepo redisdb.TokenRepository) (*AuthZMiddleware, error) {
 	if permService == nil {
 		return nil, fmt.Errorf("permissionService cannot be nil for AuthZMiddleware")
 	}
@@ -76,11 +111,15 @@ func NewAuthZMiddleware(permService authz.PermissionService, logger *zap.Logger,
 	if jwtValidator == nil {
 		return nil, fmt.Errorf("JWTValidator cannot be nil for AuthZMiddleware")
 	}
+	if tokenRepo == nil {
+		return nil, fmt.Errorf("TokenRepository cannot be nil for AuthZMiddleware")
+	}
 
 	return &AuthZMiddleware{
 		permissionService: permService,
 		logger:            logger,
 		jwtValidator:      jwtValidator,
+		tokenRepo:         tokenRepo,
 	}, nil
 
 }
@@ -122,9 +161,15 @@ func (m *AuthZMiddleware) AuthorizePermission(requiredPermission string) fiber.H
 			return c.Status(fiber.StatusInternalServerError).JSON(model.ErrorResponse{Message: "Internal server error: User roles not available."})
 		}
 
-		if !m.permissionService.HashPermission(userRoles, requiredPermission) {
+		userID, _ := c.Locals("userID").(string)
+		allowed, err := m.permissionService.HasPermission(userID, userRoles, requiredPermission)
+		if err != nil {
+			m.logger.Error("Profile Manager: Failed to evaluate permission", zap.Error(err), zap.String("userID", userID))
+			return c.Status(fiber.StatusInternalServerError).JSON(model.ErrorResponse{Message: "Internal server error: permission check failed."})
+		}
+		if !allowed {
 			m.logger.Warn("Profile Manager: Access denied: User does not have required permission for internal operation",
-				zap.String("userID", c.Locals("userID").(string)),
+				zap.String("userID", userID),
 				zap.Strings("user_roles", userRoles),
 				zap.String("required_permission", requiredPermission),
 				zap.String("path", c.OriginalURL()))
@@ -27,14 +27,22 @@ func SetUpAccountRoutes(app *fiber.App, accountHandler *handler.AccountHandler,
 	// Further authorization logic (e.g., user can only modify their own profile)
 	// should be handled within the accountHandler methods or service layer.
 	accountGroup.Post("/change-username", authZMiddleware.VerifyServiceToken(), accountHandler.ChangeUsername)
-	accountGroup.Post("/change-password", authZMiddleware.VerifyServiceToken(), accountHandler.ChangePassword)
+	accountGroup.Post("/:userID/change-password", authZMiddleware.VerifyServiceToken(), accountHandler.ChangePassword)
 	accountGroup.Post("/profile-picture", authZMiddleware.VerifyServiceToken(), accountHandler.UploadProfilePicture)
 
 	// --- 2FA Setup Routes under Account ---
-	twoFASetupGroup := accountGroup.Group("/2fa")
+	// The API Gateway authenticates the user and passes their ID in the path.
+	twoFASetupGroup := accountGroup.Group("/:userID/2fa")
 	twoFASetupGroup.Post("/generate-secret", authZMiddleware.VerifyServiceToken(), accountHandler.GenerateTwoFASetup)
 	twoFASetupGroup.Post("/enable", authZMiddleware.VerifyServiceToken(), accountHandler.VerifyAndEnableTwoFA)
 	twoFASetupGroup.Post("/disable", authZMiddleware.VerifyServiceToken(), accountHandler.DisableTwoFA)
+	twoFASetupGroup.Get("/recovery-codes", authZMiddleware.VerifyServiceToken(), accountHandler.GetRecoveryCodesStatus)
+	twoFASetupGroup.Post("/recovery-codes/regenerate", authZMiddleware.VerifyServiceToken(), accountHandler.RegenerateRecoveryCodes)
+
+	// --- Mobile verification: the number is saved once the code sent to it is confirmed ---
+	mobileGroup := accountGroup.Group("/:userID/mobile")
+	mobileGroup.Post("/", authZMiddleware.VerifyServiceToken(), accountHandler.RequestMobileVerification)
+	mobileGroup.Post("/verify", authZMiddleware.VerifyServiceToken(), accountHandler.VerifyMobile)
 
 	utils.Log.Info("Profile Manager: /account and /account/2fa routes configured.")
 	return nil
@@ -0,0 +1,36 @@
+package router
+
+import (
+	"fmt"
+	"profile-gold/internal/api/handler"
+	"profile-gold/internal/api/middleware"
+	"profile-gold/internal/utils"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+func SetUpAPIKeyRoutes(app *fiber.App, apiKeyHandler *handler.APIKeyHandler, authZMiddleware *middleware.AuthZMiddleware) error {
+	if app == nil {
+		return fmt.Errorf("Fiber app instance is nil in Profile Manager's SetUpAPIKeyRoutes")
+	}
+	if apiKeyHandler == nil {
+		return fmt.Errorf("APIKeyHandler is nil in Profile Manager's SetUpAPIKeyRoutes")
+	}
+	if authZMiddleware == nil {
+		return fmt.Errorf("AuthZMiddleware is nil in Profile Manager's SetUpAPIKeyRoutes")
+	}
+
+	// Only reachable through the API Gateway: it checks the caller's permission for management and
+	// calls /verify when a request presents a key.
+	apiKeyGroup := app.Group("/api-keys")
+	apiKeyGroup.Post("/", authZMiddleware.VerifyServiceToken(), apiKeyHandler.CreateAPIKey)
+	apiKeyGroup.Post("/verify", authZMiddleware.VerifyServiceToken(), apiKeyHandler.VerifyAPIKey)
+
+	// Listing and revoking are limited to the keys of the user the API Gateway authenticated, passed in the path.
+	ownKeysGroup := app.Group("/account/:userID/api-keys")
+	ownKeysGroup.Get("/", authZMiddleware.VerifyServiceToken(), apiKeyHandler.ListAPIKeys)
+	ownKeysGroup.Delete("/:keyID", authZMiddleware.VerifyServiceToken(), apiKeyHandler.RevokeAPIKey)
+
+	utils.Log.Info("Profile Manager: /api-keys and /account/:userID/api-keys routes configured.")
+	return nil
+}
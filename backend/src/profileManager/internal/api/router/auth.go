@@ -28,6 +28,10 @@ func SetuUpAuthRoutes(app *fiber.App, authHandler *handler.AuthHandler, authZMid
     authGroup.Post("/password/request-reset", authHandler.RequestPasswordReset)
     authGroup.Post("/password/reset", authHandler.ResetPassword)
     authGroup.Post("/2fa/verify", authHandler.VerifyTwoFA)
+    authGroup.Post("/otp/request", authHandler.RequestLoginOTP)
+    authGroup.Post("/otp/verify", authHandler.VerifyLoginOTP)
+    authGroup.Post("/refresh", authHandler.Refresh)
+    authGroup.Post("/users/:userID/unlock", authZMiddleware.VerifyServiceToken(), authHandler.UnlockUser)
 
 
     authGroup.Post("/logout", authHandler.Logout) 
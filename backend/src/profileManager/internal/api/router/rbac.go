@@ -0,0 +1,40 @@
+package router
+
+import (
+	"fmt"
+	"profile-gold/internal/api/handler"
+	"profile-gold/internal/api/middleware"
+	"profile-gold/internal/utils"
+
+	"common-gold/authz"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+func SetUpRBACRoutes(app *fiber.App, rbacHandler *handler.RBACHandler, authZMiddleware *middleware.AuthZMiddleware) error {
+	if app == nil {
+		return fmt.Errorf("Fiber app instance is nil in Profile Manager's SetUpRBACRoutes")
+	}
+	if rbacHandler == nil {
+		return fmt.Errorf("RBACHandler is nil in Profile Manager's SetUpRBACRoutes")
+	}
+	if authZMiddleware == nil {
+		return fmt.Errorf("AuthZMiddleware is nil in Profile Manager's SetUpRBACRoutes")
+	}
+
+	// Polled by the API Gateway and CRM Manager to refresh their cached copy of the policy.
+	app.Get(authz.PolicyPath, authZMiddleware.VerifyServiceToken(), rbacHandler.GetPolicy)
+
+	// Management routes are only reachable through the API Gateway, which checks the caller's permission.
+	rbacGroup := app.Group("/rbac")
+	rbacGroup.Get("/roles", authZMiddleware.VerifyServiceToken(), rbacHandler.ListRoles)
+	rbacGroup.Post("/roles", authZMiddleware.VerifyServiceToken(), rbacHandler.CreateRole)
+	rbacGroup.Put("/roles/:name", authZMiddleware.VerifyServiceToken(), rbacHandler.UpdateRole)
+	rbacGroup.Delete("/roles/:name", authZMiddleware.VerifyServiceToken(), rbacHandler.DeleteRole)
+	rbacGroup.Get("/permissions", authZMiddleware.VerifyServiceToken(), rbacHandler.ListPermissions)
+	rbacGroup.Get("/users/:userID/permissions", authZMiddleware.VerifyServiceToken(), rbacHandler.GetUserPermissions)
+	rbacGroup.Put("/users/:userID/permissions", authZMiddleware.VerifyServiceToken(), rbacHandler.SetUserPermissions)
+
+	utils.Log.Info("Profile Manager: /authz/policy and /rbac routes configured.")
+	return nil
+}
@@ -0,0 +1,31 @@
+package router
+
+import (
+	"fmt"
+	"profile-gold/internal/api/handler"
+	"profile-gold/internal/api/middleware"
+	"profile-gold/internal/utils"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+func SetUpSessionRoutes(app *fiber.App, sessionHandler *handler.SessionHandler, authZMiddleware *middleware.AuthZMiddleware) error {
+	if app == nil {
+		return fmt.Errorf("Fiber app instance is nil in Profile Manager's SetUpSessionRoutes")
+	}
+	if sessionHandler == nil {
+		return fmt.Errorf("SessionHandler is nil in Profile Manager's SetUpSessionRoutes")
+	}
+	if authZMiddleware == nil {
+		return fmt.Errorf("AuthZMiddleware is nil in Profile Manager's SetUpSessionRoutes")
+	}
+
+	// The API Gateway authenticates the user and passes their ID in the path and their session ID as ?current=.
+	sessionGroup := app.Group("/account/:userID/sessions")
+	sessionGroup.Get("/", authZMiddleware.VerifyServiceToken(), sessionHandler.ListSessions)
+	sessionGroup.Post("/revoke-others", authZMiddleware.VerifyServiceToken(), sessionHandler.RevokeOtherSessions)
+	sessionGroup.Delete("/:sessionID", authZMiddleware.VerifyServiceToken(), sessionHandler.RevokeSession)
+
+	utils.Log.Info("Profile Manager: /account/:userID/sessions routes configured.")
+	return nil
+}
@@ -10,7 +10,7 @@ import (
 	"github.com/gofiber/fiber/v2"
 	"go.uber.org/zap"
 )
-func SetupAllRoutes(app *fiber.App, authHandler *handler.AuthHandler, accountHandler *handler.AccountHandler, userHandler *handler.UserHandler, authZMiddleware *middleware.AuthZMiddleware) error {
+func SetupAllRoutes(app *fiber.App, authHandler *handler.AuthHandler, accountHandler *handler.AccountHandler, userHandler *handler.UserHandler, sessionHandler *handler.SessionHandler, rbacHandler *handler.RBACHandler, apiKeyHandler *handler.APIKeyHandler, authZMiddleware *middleware.AuthZMiddleware) error {
     if app == nil {
         return fmt.Errorf("fiber app instance is nil in SetupAllRoutes")
     }
@@ -23,6 +23,15 @@ func SetupAllRoutes(app *fiber.App, authHandler *handler.AuthHandler, accountHan
     if userHandler == nil {
         return fmt.Errorf("userHandleris nil in SetupAllRoutes")
     }
+    if sessionHandler == nil {
+        return fmt.Errorf("sessionHandler is nil in SetupAllRoutes")
+    }
+    if rbacHandler == nil {
+        return fmt.Errorf("rbacHandler is nil in SetupAllRoutes")
+    }
+    if apiKeyHandler == nil {
+        return fmt.Errorf("apiKeyHandler is nil in SetupAllRoutes")
+    }
     if authZMiddleware == nil {
         return fmt.Errorf("authZMiddleware is nil in SetupAllRoutes")
     }
@@ -38,6 +47,21 @@ func SetupAllRoutes(app *fiber.App, authHandler *handler.AuthHandler, accountHan
 		return fmt.Errorf("failed to set up account routes: %w", err)
 	}
 
+	// --- Set up Session Routes ---
+	if err := SetUpSessionRoutes(app, sessionHandler, authZMiddleware); err != nil {
+		return fmt.Errorf("failed to set up session routes: %w", err)
+	}
+
+	// --- Set up RBAC Routes ---
+	if err := SetUpRBACRoutes(app, rbacHandler, authZMiddleware); err != nil {
+		return fmt.Errorf("failed to set up rbac routes: %w", err)
+	}
+
+	// --- Set up API Key Routes ---
+	if err := SetUpAPIKeyRoutes(app, apiKeyHandler, authZMiddleware); err != nil {
+		return fmt.Errorf("failed to set up API key routes: %w", err)
+	}
+
 	// --- Set up User Management Routes ---
 	if err := SetUpUserManagementRoutes(app, userHandler, authZMiddleware); err != nil {
 		return fmt.Errorf("failed to set up user management routes: %w", err)
@@ -3,13 +3,22 @@ package router
 import (
 	"fmt"
 	"log"
+	"os"
 	"profile-gold/internal/api/authz"
 	"profile-gold/internal/api/handler"
 	"profile-gold/internal/api/middleware"
 	"profile-gold/internal/repository/db/postgresDb"
 	redisdb "profile-gold/internal/repository/db/redisDb"
 	"profile-gold/internal/service/account"
+	"profile-gold/internal/service/apikey"
 	authService "profile-gold/internal/service/auth"
+	"profile-gold/internal/service/email"
+	"profile-gold/internal/service/notification"
+	"profile-gold/internal/service/otp"
+	"profile-gold/internal/service/rbac"
+	"profile-gold/internal/service/session"
+	"profile-gold/internal/service/sms"
+	"profile-gold/internal/service/throttle"
 	"profile-gold/internal/service/twofa"
 	"profile-gold/internal/service/user"
 	"profile-gold/internal/utils"
@@ -53,9 +62,36 @@ func StartServer(port string) error {
 	}
 	utils.Log.Info("TokenRepository initialized successfully.")
 
+	challengeRepo := redisdb.NewRedisTwoFAChallengeRepository(redisdb.RedisClient)
+	utils.Log.Info("TwoFAChallengeRepository initialized successfully.")
+
+	twoFARepo := postgresDb.NewPostgresTwoFARepository(postgresDb.DB)
+	utils.Log.Info("TwoFARepository initialized successfully.")
+
+	resetRepo := postgresDb.NewPostgresPasswordResetRepository(postgresDb.DB)
+	utils.Log.Info("PasswordResetRepository initialized successfully.")
+
+	sessionRepo := postgresDb.NewPostgresSessionRepository(postgresDb.DB)
+	utils.Log.Info("SessionRepository initialized successfully.")
+
+	rbacRepo := postgresDb.NewPostgresRBACRepository(postgresDb.DB)
+	utils.Log.Info("RBACRepository initialized successfully.")
+
+	apiKeyRepo := postgresDb.NewPostgresAPIKeyRepository(postgresDb.DB)
+	utils.Log.Info("APIKeyRepository initialized successfully.")
+
+	attemptRepo := redisdb.NewRedisAttemptRepository(redisdb.RedisClient)
+	utils.Log.Info("AttemptRepository initialized successfully.")
+
+	otpRepo := redisdb.NewRedisOTPRepository(redisdb.RedisClient)
+	utils.Log.Info("OTPRepository initialized successfully.")
+
+	mobileRepo := postgresDb.NewPostgresMobileRepository(postgresDb.DB)
+	utils.Log.Info("MobileRepository initialized successfully.")
+
 	utils.Log.Info("Initializing Services...")
 
-	permissionService, err := authz.NewPermissionService(utils.Log)
+	permissionService, err := authz.NewPermissionService(utils.Log, rbacRepo)
 	if err != nil {
 		utils.Log.Fatal("Failed to initialize PermissionService. Exiting application.", zap.Error(err))
 	}
@@ -70,13 +106,50 @@ func StartServer(port string) error {
 	}
 	utils.Log.Info("TwoFAService initialized successfully.")
 
-	authSvc := authService.NewAuthService(userRepo, tokenRepo, jwtValidator)
+	mailer, err := email.NewMailerFromEnv()
+	if err != nil {
+		utils.Log.Fatal("Failed to initialize Mailer. Exiting application.", zap.Error(err))
+	}
+	emailSvc, err := email.NewEmailService(mailer)
+	if err != nil {
+		utils.Log.Fatal("Failed to initialize EmailService. Exiting application.", zap.Error(err))
+	}
+	utils.Log.Info("EmailService initialized successfully.")
+
+	loginThrottle, err := throttle.NewThrottle(attemptRepo)
+	if err != nil {
+		utils.Log.Fatal("Failed to initialize Throttle. Exiting application.", zap.Error(err))
+	}
+	utils.Log.Info("Throttle initialized successfully.")
+
+	smsProvider, err := sms.NewProviderFromEnv()
+	if err != nil {
+		utils.Log.Fatal("Failed to initialize SMS provider. Exiting application.", zap.Error(err))
+	}
+	otpSvc, err := otp.NewOTPService(otpRepo, smsProvider, loginThrottle)
+	if err != nil {
+		utils.Log.Fatal("Failed to initialize OTPService. Exiting application.", zap.Error(err))
+	}
+	utils.Log.Info("OTPService initialized successfully.")
+
+	notificationURL := os.Getenv("NOTIFICATION_MANAGER_BASE_URL")
+	if notificationURL == "" {
+		notificationURL = "http://localhost:8084"
+		utils.Log.Warn("NOTIFICATION_MANAGER_BASE_URL not set, using default.", zap.String("url", notificationURL))
+	}
+	notifier, err := notification.NewClient(notificationURL)
+	if err != nil {
+		utils.Log.Fatal("Failed to initialize Notifier. Exiting application.", zap.Error(err))
+	}
+	utils.Log.Info("Notifier initialized successfully.")
+
+	authSvc := authService.NewAuthService(userRepo, tokenRepo, jwtValidator, resetRepo, emailSvc, challengeRepo, twoFAService, twoFARepo, sessionRepo, loginThrottle, notifier, mobileRepo, otpSvc)
 	if authSvc == nil {
 		utils.Log.Fatal("Failed to initialize AuthService. Exiting application.")
 	}
 	utils.Log.Info("AuthService initialized successfully.")
 
-	accountSvc, err := account.NewAccountService(userRepo, twoFAService)
+	accountSvc, err := account.NewAccountService(userRepo, twoFARepo, twoFAService, mobileRepo, otpSvc, sessionRepo, tokenRepo, loginThrottle, challengeRepo)
 	if err != nil {
 		utils.Log.Fatal("Failed to initialize AccountService. Exiting application.", zap.Error(err))
 	}
@@ -87,6 +160,21 @@ func StartServer(port string) error {
 		utils.Log.Fatal("Failed to initialize UserService. Exiting application.", zap.Error(err))
 	}
 
+	sessionSvc, err := session.NewSessionService(sessionRepo, tokenRepo)
+	if err != nil {
+		utils.Log.Fatal("Failed to initialize SessionService. Exiting application.", zap.Error(err))
+	}
+
+	rbacSvc, err := rbac.NewRBACService(rbacRepo, userRepo, permissionService)
+	if err != nil {
+		utils.Log.Fatal("Failed to initialize RBACService. Exiting application.", zap.Error(err))
+	}
+
+	apiKeySvc, err := apikey.NewAPIKeyService(apiKeyRepo, userRepo, rbacRepo, tokenRepo, permissionService)
+	if err != nil {
+		utils.Log.Fatal("Failed to initialize APIKeyService. Exiting application.", zap.Error(err))
+	}
+
 	utils.Log.Info("Initializing Handlers...")
 	authHandler := handler.NewAuthHandler(authSvc)
 	if authHandler == nil {
@@ -106,7 +194,16 @@ func StartServer(port string) error {
 	}
 	utils.Log.Info("UserHandler initialized.")
 
-	authZMiddleware, err := middleware.NewAuthZMiddleware(permissionService, utils.Log, jwtValidator)
+	sessionHandler := handler.NewSessionHandler(sessionSvc)
+	utils.Log.Info("SessionHandler initialized.")
+
+	rbacHandler := handler.NewRBACHandler(rbacSvc)
+	utils.Log.Info("RBACHandler initialized.")
+
+	apiKeyHandler := handler.NewAPIKeyHandler(apiKeySvc)
+	utils.Log.Info("APIKeyHandler initialized.")
+
+	authZMiddleware, err := middleware.NewAuthZMiddleware(permissionService, utils.Log, jwtValidator, tokenRepo)
 	if err != nil {
 		utils.Log.Fatal("Failed to initialize AuthZMiddleware. Exiting application.", zap.Error(err))
 	}
@@ -115,7 +212,7 @@ func StartServer(port string) error {
 	utils.Log.Info("All core dependencies initialized successfully.")
 	utils.Log.Info("Setting up Profile Manager API routes...")
 
-	if err := SetupAllRoutes(app, authHandler, accountHandler, userHandler, authZMiddleware); err != nil {
+	if err := SetupAllRoutes(app, authHandler, accountHandler, userHandler, sessionHandler, rbacHandler, apiKeyHandler, authZMiddleware); err != nil {
 		utils.Log.Fatal("Failed to set up Profile Manager API routes. Exiting application.", zap.Error(err))
 	}
 	utils.Log.Info("Profile Manager API routes configured successfully.")
@@ -16,9 +16,14 @@ type User struct {
 	ProfileImageURL string         `json:"profile_image_url,omitempty"`
 	CreatedAt       time.Time      `json:"created_at" gorm:"autoCreateTime"`
 	UpdatedAt       time.Time      `json:"updated_at" gorm:"autoUpdateTime"`
-	TwoFASecret     string         `json:"two_fa_secret,omitempty" gorm:"column:two_fa_secret"`
+	TwoFASecret     string         `json:"-" gorm:"column:two_fa_secret"`
 	TwoFAEnabled    bool           `json:"two_fa_enabled" gorm:"column:two_fa_enabled;default:false"`
-	ProfilePicture  []byte         `json:"profile_picture,omitempty" gorm:"column:profile_picture;type:bytea"`
+	// TwoFAPendingSecret holds a freshly generated secret until the user confirms it with a valid code.
+	TwoFAPendingSecret string `json:"-" gorm:"column:two_fa_pending_secret"`
+	ProfilePicture     []byte `json:"profile_picture,omitempty" gorm:"column:profile_picture;type:bytea"`
+	// Mobile is only set once the user has confirmed it with an SMS code, and can then be used to log in with one.
+	Mobile           *string    `json:"mobile,omitempty" gorm:"column:mobile;size:11;uniqueIndex"`
+	MobileVerifiedAt *time.Time `json:"mobile_verified_at,omitempty" gorm:"column:mobile_verified_at"`
 }
 
 type UserCreateRequest struct {
@@ -35,9 +40,12 @@ type UserUpdateRequest struct {
 }
 
 type Role struct {
-	ID          string    `json:"id" gorm:"primaryKey;type:uuid;default:gen_random_uuid()"`
-	Name        string    `json:"name" gorm:"unique;not null"`
-	Description string    `json:"description"`
+	ID          string `json:"id" gorm:"primaryKey;type:uuid;default:gen_random_uuid()"`
+	Name        string `json:"name" gorm:"unique;not null"`
+	Description string `json:"description"`
+	// IsSystem marks the seeded roles; they can be re-permissioned but not renamed or deleted.
+	IsSystem    bool      `json:"is_system" gorm:"not null;default:false"`
+	Permissions []string  `json:"permissions" gorm:"-"`
 	CreatedAt   time.Time `json:"created_at" gorm:"autoCreateTime"`
 	UpdatedAt   time.Time `json:"updated_at" gorm:"autoUpdateTime"`
 }
@@ -57,12 +65,77 @@ type RolePermission struct {
 	UpdatedAt    time.Time `json:"updated_at" gorm:"autoUpdateTime"`
 }
 
+// UserPermission is a per-user override on top of the user's roles; Effect is "grant" or "deny".
+type UserPermission struct {
+	UserID       string    `json:"user_id" gorm:"primaryKey;type:uuid"`
+	PermissionID string    `json:"permission_id" gorm:"primaryKey;type:uuid"`
+	Effect       string    `json:"effect" gorm:"size:8;not null"`
+	CreatedAt    time.Time `json:"created_at" gorm:"autoCreateTime"`
+}
+
+// PolicyVersion is a single-row counter bumped by every RBAC change so other services know when to reload.
+type PolicyVersion struct {
+	ID        int       `gorm:"primaryKey"`
+	Version   int64     `gorm:"not null;default:1"`
+	UpdatedAt time.Time `gorm:"autoUpdateTime"`
+}
+
+const (
+	PermissionEffectGrant = "grant"
+	PermissionEffectDeny  = "deny"
+)
+
+// PasswordResetToken only the SHA-256 hash of the emailed token is stored; UsedAt makes it single-use.
 type PasswordResetToken struct {
-	ID        string    `json:"id" gorm:"primaryKey;type:uuid;default:gen_random_uuid()"`
-	UserID    string    `json:"user_id" gorm:"type:uuid;not null"`
-	Token     string    `json:"token" gorm:"unique;not null"`
-	ExpiresAt time.Time `json:"expires_at" gorm:"not null"`
-	CreatedAt time.Time `json:"created_at" gorm:"autoCreateTime"`
+	ID        string     `json:"id" gorm:"primaryKey;type:uuid;default:gen_random_uuid()"`
+	UserID    string     `json:"user_id" gorm:"type:uuid;not null;index"`
+	TokenHash string     `json:"-" gorm:"column:token_hash;size:64;uniqueIndex;not null"`
+	ExpiresAt time.Time  `json:"expires_at" gorm:"not null"`
+	UsedAt    *time.Time `json:"used_at,omitempty"`
+	CreatedAt time.Time  `json:"created_at" gorm:"autoCreateTime"`
+}
+
+// RecoveryCode is a one-time 2FA backup code; only its SHA-256 hash is stored.
+type RecoveryCode struct {
+	ID        string     `json:"id" gorm:"primaryKey;type:uuid;default:gen_random_uuid()"`
+	UserID    string     `json:"user_id" gorm:"type:uuid;not null;index"`
+	CodeHash  string     `json:"-" gorm:"column:code_hash;size:64;uniqueIndex;not null"`
+	UsedAt    *time.Time `json:"used_at,omitempty"`
+	CreatedAt time.Time  `json:"created_at" gorm:"autoCreateTime"`
+}
+
+// Session is one signed-in device. Access tokens carry its ID in the "sid" claim; revoking it ends the
+// session on every service, and its refresh tokens stop working.
+type Session struct {
+	ID            string     `json:"id" gorm:"primaryKey;type:uuid;default:gen_random_uuid()"`
+	UserID        string     `json:"user_id" gorm:"type:uuid;not null;index"`
+	DeviceName    string     `json:"device_name"`
+	IPAddress     string     `json:"ip_address"`
+	UserAgent     string     `json:"user_agent"`
+	CreatedAt     time.Time  `json:"created_at" gorm:"autoCreateTime"`
+	LastUsedAt    time.Time  `json:"last_used_at"`
+	ExpiresAt     time.Time  `json:"expires_at" gorm:"not null"`
+	RevokedAt     *time.Time `json:"revoked_at,omitempty"`
+	RevokedReason string     `json:"revoked_reason,omitempty"`
+	Current       bool       `json:"current" gorm:"-"`
+}
+
+// RefreshToken is one link of a session's rotation chain; only its SHA-256 hash is stored.
+// A token is exchanged once; presenting a used token again revokes the whole session.
+type RefreshToken struct {
+	ID        string     `json:"id" gorm:"primaryKey;type:uuid;default:gen_random_uuid()"`
+	SessionID string     `json:"session_id" gorm:"type:uuid;not null;index"`
+	TokenHash string     `json:"-" gorm:"column:token_hash;size:64;uniqueIndex;not null"`
+	ExpiresAt time.Time  `json:"expires_at" gorm:"not null"`
+	UsedAt    *time.Time `json:"used_at,omitempty"`
+	CreatedAt time.Time  `json:"created_at" gorm:"autoCreateTime"`
+}
+
+// ClientInfo describes the device a session is opened from, as forwarded by the API Gateway.
+type ClientInfo struct {
+	IPAddress  string
+	UserAgent  string
+	DeviceName string
 }
 
 type RegisterRequest struct {
@@ -73,15 +146,135 @@ type RegisterRequest struct {
 }
 
 type LoginRequest struct {
-	Username string `json:"username"`
-	Password string `json:"password"`
+	Username   string `json:"username"`
+	Password   string `json:"password"`
+	DeviceName string `json:"device_name,omitempty"`
 }
 
 type AuthResponse struct {
+	Message        string `json:"message"`
+	Token          string `json:"token,omitempty"`
+	User           *User  `json:"user,omitempty"`
+	Exp            int64  `json:"exp,omitempty"`
+	TwoFARequired  bool   `json:"two_fa_required,omitempty"`
+	ChallengeToken string `json:"challenge_token,omitempty"`
+	RefreshToken   string `json:"refresh_token,omitempty"`
+	RefreshExp     int64  `json:"refresh_exp,omitempty"`
+	SessionID      string `json:"session_id,omitempty"`
+}
+
+// TwoFAChallenge is returned by login instead of a session when the user has 2FA enabled.
+type TwoFAChallenge struct {
+	Token     string
+	ExpiresAt time.Time
+}
+
+// LoginResult holds either a session (Token, RefreshToken and Claims) or a pending 2FA Challenge.
+type LoginResult struct {
+	User             *User
+	Token            string
+	Claims           *CustomClaims
+	RefreshToken     string
+	RefreshExpiresAt time.Time
+	Challenge        *TwoFAChallenge
+}
+
+type TwoFALoginRequest struct {
+	ChallengeToken string `json:"challenge_token"`
+	Code           string `json:"code"`
+	DeviceName     string `json:"device_name,omitempty"`
+}
+
+// OTPRequest asks for an SMS code, either to log in or to verify a new mobile number.
+type OTPRequest struct {
+	Mobile string `json:"mobile"`
+}
+
+type OTPLoginRequest struct {
+	Mobile     string `json:"mobile"`
+	Code       string `json:"code"`
+	DeviceName string `json:"device_name,omitempty"`
+}
+
+type VerifyMobileRequest struct {
+	Code string `json:"code"`
+}
+
+type MobileResponse struct {
+	Message          string     `json:"message"`
+	Mobile           string     `json:"mobile,omitempty"`
+	MobileVerifiedAt *time.Time `json:"mobile_verified_at,omitempty"`
+}
+
+type RefreshTokenRequest struct {
+	RefreshToken string `json:"refresh_token"`
+}
+
+type SessionsResponse struct {
+	Sessions []Session `json:"sessions"`
+}
+
+type RevokeSessionsResponse struct {
+	Message string `json:"message"`
+	Revoked int    `json:"revoked"`
+}
+
+// APIKey is a credential for integrations and kiosk devices that cannot log in interactively. It acts for the
+// user who created it, limited to Permissions and, when BusinessID is set, to that one business.
+// Only the SHA-256 hash of the key is stored; the key itself is shown once, when it is created.
+type APIKey struct {
+	ID   string `json:"id" gorm:"primaryKey;type:uuid;default:gen_random_uuid()"`
+	Name string `json:"name" gorm:"not null"`
+	// Prefix is the start of the key, kept so users can tell their keys apart.
+	Prefix      string                      `json:"prefix" gorm:"size:16;not null"`
+	KeyHash     string                      `json:"-" gorm:"column:key_hash;size:64;uniqueIndex;not null"`
+	UserID      string                      `json:"user_id" gorm:"type:uuid;not null;index"`
+	Permissions datatypes.JSONSlice[string] `json:"permissions" gorm:"type:jsonb;not null"`
+	BusinessID  *uint                       `json:"business_id,omitempty"`
+	ExpiresAt   *time.Time                  `json:"expires_at,omitempty"`
+	LastUsedAt  *time.Time                  `json:"last_used_at,omitempty"`
+	LastUsedIP  string                      `json:"last_used_ip,omitempty"`
+	CreatedAt   time.Time                   `json:"created_at" gorm:"autoCreateTime"`
+	RevokedAt   *time.Time                  `json:"revoked_at,omitempty"`
+}
+
+// CreateAPIKeyRequest is sent by the API Gateway, which fills in UserID with the caller.
+type CreateAPIKeyRequest struct {
+	UserID      string     `json:"user_id"`
+	Name        string     `json:"name"`
+	Permissions []string   `json:"permissions"`
+	BusinessID  *uint      `json:"business_id,omitempty"`
+	ExpiresAt   *time.Time `json:"expires_at,omitempty"`
+}
+
+type CreateAPIKeyResponse struct {
 	Message string `json:"message"`
-	Token   string `json:"token,omitempty"`
-	User    *User  `json:"user,omitempty"`
-	Exp     int64  `json:"exp,omitempty"`
+	// Key is the full secret. It is not stored and cannot be shown again.
+	Key    string `json:"key"`
+	APIKey APIKey `json:"api_key"`
+}
+
+type APIKeysResponse struct {
+	APIKeys []APIKey `json:"api_keys"`
+}
+
+type VerifyAPIKeyRequest struct {
+	Key string `json:"key"`
+}
+
+// APIKeyPrincipal is what the API Gateway learns about a valid key. Token is a short-lived access token of the
+// key's owner that the gateway forwards to downstream services in place of the key.
+type APIKeyPrincipal struct {
+	KeyID       string     `json:"key_id"`
+	Name        string     `json:"name"`
+	UserID      string     `json:"user_id"`
+	Username    string     `json:"username"`
+	Roles       []string   `json:"roles"`
+	Permissions []string   `json:"permissions"`
+	BusinessID  *uint      `json:"business_id,omitempty"`
+	ExpiresAt   *time.Time `json:"expires_at,omitempty"`
+	Token       string     `json:"token"`
+	TokenExp    int64      `json:"token_exp"`
 }
 
 type ErrorResponse struct {
@@ -90,12 +283,76 @@ type ErrorResponse struct {
 	Details string `json:"details,omitempty"`
 }
 type CustomClaims struct {
-	UserID   string         `json:"user_id"`
-	Username string         `json:"username"`
-	Roles    datatypes.JSON `json:"roles"`
+	UserID    string         `json:"user_id"`
+	Username  string         `json:"username"`
+	Roles     datatypes.JSON `json:"roles"`
+	SessionID string         `json:"sid,omitempty"`
+	// APIKeyID is set on tokens issued for an API key instead of a login.
+	APIKeyID string `json:"akid,omitempty"`
+	// BusinessID limits a token issued for a business-scoped API key to that business; CRM Manager enforces it.
+	BusinessID *uint `json:"bid,omitempty"`
 	jwt.RegisteredClaims
 }
 
 type RequestPasswordReset struct {
 	Email string `json:"email"`
 }
+
+type ResetPasswordRequest struct {
+	Token       string `json:"token"`
+	NewPassword string `json:"new_password"`
+}
+
+type TwoFASetupResponse struct {
+	Secret     string `json:"secret"`
+	OTPAuthURL string `json:"otpauth_url"`
+	QRCodePNG  string `json:"qr_code_png"` // data:image/png;base64,...
+}
+
+type EnableTwoFARequest struct {
+	Code string `json:"totp_code"`
+}
+
+type ChangePasswordRequest struct {
+	OldPassword string `json:"old_password"`
+	NewPassword string `json:"new_password"`
+}
+
+type DisableTwoFARequest struct {
+	Password string `json:"current_password"`
+	Code     string `json:"totp_code"`
+}
+
+type RecoveryCodesResponse struct {
+	Message       string   `json:"message,omitempty"`
+	RecoveryCodes []string `json:"recovery_codes,omitempty"`
+	Remaining     int64    `json:"remaining"`
+}
+
+type RoleRequest struct {
+	Name        string   `json:"name"`
+	Description string   `json:"description"`
+	Permissions []string `json:"permissions"`
+}
+
+type RolesResponse struct {
+	Roles []Role `json:"roles"`
+}
+
+type PermissionsResponse struct {
+	Permissions []Permission `json:"permissions"`
+}
+
+type UserPermissionsRequest struct {
+	Grant []string `json:"grant"`
+	Deny  []string `json:"deny"`
+}
+
+// UserPermissionsResponse shows a user's roles, their overrides and the permissions that result from both.
+type UserPermissionsResponse struct {
+	UserID    string   `json:"user_id"`
+	Roles     []string `json:"roles"`
+	Grant     []string `json:"grant"`
+	Deny      []string `json:"deny"`
+	Effective []string `json:"effective"`
+}
@@ -0,0 +1,95 @@
+package postgresDb
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"profile-gold/internal/model"
+	service "profile-gold/internal/service/common"
+	"profile-gold/internal/utils"
+
+	"go.uber.org/zap"
+	"gorm.io/gorm"
+)
+
+// APIKeyRepository stores API keys, looked up by the hash of their secret.
+type APIKeyRepository interface {
+	CreateAPIKey(key *model.APIKey) error
+	// ListAPIKeys returns every key of userID, revoked and expired ones included, newest first.
+	ListAPIKeys(userID string) ([]model.APIKey, error)
+	// GetAPIKeyByHash returns service.ErrInvalidAPIKey if no key has this hash.
+	GetAPIKeyByHash(keyHash string) (*model.APIKey, error)
+	// RevokeAPIKey returns service.ErrAPIKeyNotFound if userID has no unrevoked key with this ID.
+	RevokeAPIKey(userID, keyID string, now time.Time) (*model.APIKey, error)
+	// TouchAPIKey records when and from where the key was last used.
+	TouchAPIKey(keyID, ipAddress string, at time.Time) error
+}
+
+type postgresAPIKeyRepository struct {
+	db *gorm.DB
+}
+
+func NewPostgresAPIKeyRepository(db *gorm.DB) APIKeyRepository {
+	if db == nil {
+		utils.Log.Fatal("GORM DB instance is nil for PostgresAPIKeyRepository.")
+	}
+	return &postgresAPIKeyRepository{db: db}
+}
+
+func (r *postgresAPIKeyRepository) CreateAPIKey(key *model.APIKey) error {
+	if err := r.db.Create(key).Error; err != nil {
+		return fmt.Errorf("failed to create API key in DB: %w", err)
+	}
+	return nil
+}
+
+func (r *postgresAPIKeyRepository) ListAPIKeys(userID string) ([]model.APIKey, error) {
+	var keys []model.APIKey
+	if err := r.db.Where("user_id = ?", userID).Order("created_at DESC").Find(&keys).Error; err != nil {
+		return nil, fmt.Errorf("failed to list API keys from DB: %w", err)
+	}
+	return keys, nil
+}
+
+func (r *postgresAPIKeyRepository) GetAPIKeyByHash(keyHash string) (*model.APIKey, error) {
+	var key model.APIKey
+	if err := r.db.Where("key_hash = ?", keyHash).First(&key).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, service.ErrInvalidAPIKey
+		}
+		return nil, fmt.Errorf("failed to get API key from DB: %w", err)
+	}
+	return &key, nil
+}
+
+func (r *postgresAPIKeyRepository) RevokeAPIKey(userID, keyID string, now time.Time) (*model.APIKey, error) {
+	var key model.APIKey
+	err := r.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Where("id = ? AND user_id = ? AND revoked_at IS NULL", keyID, userID).First(&key).Error; err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				return service.ErrAPIKeyNotFound
+			}
+			return err
+		}
+		key.RevokedAt = &now
+		return tx.Model(&key).Update("revoked_at", now).Error
+	})
+	if err != nil {
+		if errors.Is(err, service.ErrAPIKeyNotFound) {
+			return nil, err
+		}
+		return nil, fmt.Errorf("failed to revoke API key in DB: %w", err)
+	}
+	utils.Log.Info("API key revoked", zap.String("api_key_id", keyID), zap.String("user_id", key.UserID))
+	return &key, nil
+}
+
+func (r *postgresAPIKeyRepository) TouchAPIKey(keyID, ipAddress string, at time.Time) error {
+	err := r.db.Model(&model.APIKey{}).Where("id = ?", keyID).
+		Updates(map[string]interface{}{"last_used_at": at, "last_used_ip": ipAddress}).Error
+	if err != nil {
+		return fmt.Errorf("failed to record API key use in DB: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,67 @@
+package postgresDb
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"profile-gold/internal/model"
+	service "profile-gold/internal/service/common"
+	"profile-gold/internal/utils"
+
+	"gorm.io/gorm"
+)
+
+// MobileRepository stores users' verified mobile numbers. A number belongs to at most one user.
+type MobileRepository interface {
+	// GetUserByMobile returns service.ErrUserNotFound when no user has verified the number.
+	GetUserByMobile(mobile string) (*model.User, error)
+	// SetVerifiedMobile returns service.ErrMobileInUse if another user has already verified the number.
+	SetVerifiedMobile(userID, mobile string, verifiedAt time.Time) error
+}
+
+type postgresMobileRepository struct {
+	db *gorm.DB
+}
+
+func NewPostgresMobileRepository(db *gorm.DB) MobileRepository {
+	if db == nil {
+		utils.Log.Fatal("GORM DB instance is nil for PostgresMobileRepository.")
+	}
+	return &postgresMobileRepository{db: db}
+}
+
+func (r *postgresMobileRepository) GetUserByMobile(mobile string) (*model.User, error) {
+	var user model.User
+	result := r.db.Where("mobile = ?", mobile).First(&user)
+	if result.Error != nil {
+		if errors.Is(result.Error, gorm.ErrRecordNotFound) {
+			return nil, service.ErrUserNotFound
+		}
+		return nil, fmt.Errorf("failed to get user by mobile from DB: %w", result.Error)
+	}
+	return &user, nil
+}
+
+func (r *postgresMobileRepository) SetVerifiedMobile(userID, mobile string, verifiedAt time.Time) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		var taken int64
+		if err := tx.Model(&model.User{}).Where("mobile = ? AND id <> ?", mobile, userID).Count(&taken).Error; err != nil {
+			return fmt.Errorf("failed to check mobile owner in DB: %w", err)
+		}
+		if taken > 0 {
+			return service.ErrMobileInUse
+		}
+		result := tx.Model(&model.User{}).Where("id = ?", userID).Updates(map[string]interface{}{
+			"mobile":             mobile,
+			"mobile_verified_at": verifiedAt,
+		})
+		if result.Error != nil {
+			return fmt.Errorf("failed to set verified mobile in DB: %w", result.Error)
+		}
+		if result.RowsAffected == 0 {
+			return service.ErrUserNotFound
+		}
+		return nil
+	})
+}
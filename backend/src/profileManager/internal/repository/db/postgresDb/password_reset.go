@@ -0,0 +1,103 @@
+package postgresDb
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"profile-gold/internal/model"
+	service "profile-gold/internal/service/common"
+	"profile-gold/internal/utils"
+
+	"go.uber.org/zap"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type PasswordResetRepository interface {
+	// CreateResetToken stores a new token and invalidates the user's earlier unused tokens,
+	// so only the most recent email link works.
+	CreateResetToken(token *model.PasswordResetToken) error
+	// GetActiveResetToken returns service.ErrInvalidToken if the token is unknown, used or expired.
+	GetActiveResetToken(tokenHash string, now time.Time) (*model.PasswordResetToken, error)
+	// ResetPassword consumes the token and sets the new password hash in one transaction.
+	// It returns service.ErrInvalidToken if the token is unknown, used or expired.
+	ResetPassword(tokenHash, passwordHash string, now time.Time) (*model.User, error)
+}
+
+type postgresPasswordResetRepository struct {
+	db *gorm.DB
+}
+
+func NewPostgresPasswordResetRepository(db *gorm.DB) PasswordResetRepository {
+	if db == nil {
+		utils.Log.Fatal("GORM DB instance is nil for PostgresPasswordResetRepository.")
+	}
+	return &postgresPasswordResetRepository{db: db}
+}
+
+func (r *postgresPasswordResetRepository) CreateResetToken(token *model.PasswordResetToken) error {
+	err := r.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Model(&model.PasswordResetToken{}).
+			Where("user_id = ? AND used_at IS NULL", token.UserID).
+			Update("used_at", time.Now()).Error; err != nil {
+			return err
+		}
+		return tx.Create(token).Error
+	})
+	if err != nil {
+		return fmt.Errorf("failed to store password reset token: %w", err)
+	}
+	return nil
+}
+
+func (r *postgresPasswordResetRepository) GetActiveResetToken(tokenHash string, now time.Time) (*model.PasswordResetToken, error) {
+	var token model.PasswordResetToken
+	err := r.db.Where("token_hash = ? AND used_at IS NULL AND expires_at > ?", tokenHash, now).First(&token).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, service.ErrInvalidToken
+		}
+		return nil, fmt.Errorf("failed to get password reset token from DB: %w", err)
+	}
+	return &token, nil
+}
+
+func (r *postgresPasswordResetRepository) ResetPassword(tokenHash, passwordHash string, now time.Time) (*model.User, error) {
+	var user model.User
+	err := r.db.Transaction(func(tx *gorm.DB) error {
+		var token model.PasswordResetToken
+		result := tx.Model(&token).
+			Clauses(clause.Returning{}).
+			Where("token_hash = ? AND used_at IS NULL AND expires_at > ?", tokenHash, now).
+			Update("used_at", now)
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			return service.ErrInvalidToken
+		}
+		if err := tx.Model(&model.PasswordResetToken{}).
+			Where("user_id = ? AND used_at IS NULL", token.UserID).
+			Update("used_at", now).Error; err != nil {
+			return err
+		}
+		update := tx.Model(&model.User{}).Where("id = ?", token.UserID).
+			Updates(map[string]interface{}{"password_hash": passwordHash, "updated_at": now})
+		if update.Error != nil {
+			return update.Error
+		}
+		if update.RowsAffected == 0 {
+			return service.ErrInvalidToken
+		}
+		return tx.First(&user, "id = ?", token.UserID).Error
+	})
+	if err != nil {
+		if errors.Is(err, service.ErrInvalidToken) {
+			return nil, err
+		}
+		return nil, fmt.Errorf("failed to reset password in DB: %w", err)
+	}
+	utils.Log.Info("Password reset token consumed", zap.String("user_id", user.ID))
+	return &user, nil
+}
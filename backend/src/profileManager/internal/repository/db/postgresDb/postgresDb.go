@@ -71,8 +71,15 @@ func InitDB() {
 	err = DB.AutoMigrate(
 		&model.User{},
 		&model.PasswordResetToken{},
+		&model.RecoveryCode{},
+		&model.Session{},
+		&model.RefreshToken{},
+		&model.Role{},
 		&model.Permission{},    
 		&model.RolePermission{}, 
+		&model.UserPermission{},
+		&model.PolicyVersion{},
+		&model.APIKey{},
 	)
 
 	if err != nil {
@@ -80,5 +87,12 @@ func InitDB() {
 	}
 	utils.Log.Info("Database schemas auto-migrated successfully.")
 
+	// Reset tokens used to be stored in plain text; the table was never written to, so the column is simply dropped.
+	if DB.Migrator().HasColumn(&model.PasswordResetToken{}, "token") {
+		if err := DB.Migrator().DropColumn(&model.PasswordResetToken{}, "token"); err != nil {
+			utils.Log.Fatal("Failed to drop plain-text password reset token column", zap.Error(err))
+		}
+	}
+
 	SeedInitialData(DB) // We'll update this function next
 }
@@ -0,0 +1,357 @@
+package postgresDb
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"profile-gold/internal/model"
+	service "profile-gold/internal/service/common"
+	"profile-gold/internal/utils"
+
+	"common-gold/authz"
+
+	"gorm.io/gorm"
+)
+
+// RBACRepository owns roles, permissions and per-user overrides. Every write bumps the policy version in the
+// same transaction so cached copies in other services can tell that they are stale.
+type RBACRepository interface {
+	ListRoles() ([]model.Role, error)
+	// GetRole returns service.ErrRoleNotFound if no role has this name.
+	GetRole(name string) (*model.Role, error)
+	// CreateRole returns service.ErrRoleAlreadyExists or service.ErrUnknownPermission.
+	CreateRole(role *model.Role) error
+	// UpdateRole replaces the description and the full permission set of the role.
+	UpdateRole(name, description string, permissions []string) (*model.Role, error)
+	// DeleteRole returns service.ErrRoleInUse while any user still has the role.
+	DeleteRole(name string) error
+	ListPermissions() ([]model.Permission, error)
+	GetUserOverrides(userID string) (authz.Overrides, error)
+	// SetUserOverrides replaces all of the user's overrides.
+	SetUserOverrides(userID string, overrides authz.Overrides) error
+	// LoadPolicy reads a consistent snapshot of roles and overrides together with its version.
+	LoadPolicy() (*authz.Policy, error)
+	PolicyVersion() (int64, error)
+}
+
+type postgresRBACRepository struct {
+	db *gorm.DB
+}
+
+func NewPostgresRBACRepository(db *gorm.DB) RBACRepository {
+	if db == nil {
+		utils.Log.Fatal("GORM DB instance is nil for PostgresRBACRepository.")
+	}
+	return &postgresRBACRepository{db: db}
+}
+
+type rolePermissionRow struct {
+	Role       string
+	Permission sql.NullString
+}
+
+type userOverrideRow struct {
+	UserID     string
+	Effect     string
+	Permission string
+}
+
+func (r *postgresRBACRepository) ListRoles() ([]model.Role, error) {
+	var roles []model.Role
+	if err := r.db.Order("name").Find(&roles).Error; err != nil {
+		return nil, fmt.Errorf("failed to list roles from DB: %w", err)
+	}
+	perms, err := rolePermissions(r.db)
+	if err != nil {
+		return nil, err
+	}
+	for i := range roles {
+		roles[i].Permissions = perms[roles[i].Name]
+	}
+	return roles, nil
+}
+
+func (r *postgresRBACRepository) GetRole(name string) (*model.Role, error) {
+	var role model.Role
+	if err := r.db.Where("name = ?", name).First(&role).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, service.ErrRoleNotFound
+		}
+		return nil, fmt.Errorf("failed to get role from DB: %w", err)
+	}
+	perms, err := rolePermissions(r.db.Where("roles.id = ?", role.ID))
+	if err != nil {
+		return nil, err
+	}
+	role.Permissions = perms[role.Name]
+	return &role, nil
+}
+
+func (r *postgresRBACRepository) CreateRole(role *model.Role) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		var count int64
+		if err := tx.Model(&model.Role{}).Where("name = ?", role.Name).Count(&count).Error; err != nil {
+			return fmt.Errorf("failed to check existing role: %w", err)
+		}
+		if count > 0 {
+			return service.ErrRoleAlreadyExists
+		}
+		ids, err := permissionIDs(tx, role.Permissions)
+		if err != nil {
+			return err
+		}
+		if err := tx.Create(role).Error; err != nil {
+			return fmt.Errorf("failed to create role in DB: %w", err)
+		}
+		if err := replaceRolePermissions(tx, role.ID, ids); err != nil {
+			return err
+		}
+		return bumpPolicyVersion(tx)
+	})
+}
+
+func (r *postgresRBACRepository) UpdateRole(name, description string, permissions []string) (*model.Role, error) {
+	var role model.Role
+	err := r.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Where("name = ?", name).First(&role).Error; err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				return service.ErrRoleNotFound
+			}
+			return fmt.Errorf("failed to get role for update: %w", err)
+		}
+		ids, err := permissionIDs(tx, permissions)
+		if err != nil {
+			return err
+		}
+		if err := tx.Model(&role).Update("description", description).Error; err != nil {
+			return fmt.Errorf("failed to update role in DB: %w", err)
+		}
+		if err := replaceRolePermissions(tx, role.ID, ids); err != nil {
+			return err
+		}
+		return bumpPolicyVersion(tx)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return r.GetRole(name)
+}
+
+func (r *postgresRBACRepository) DeleteRole(name string) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		var role model.Role
+		if err := tx.Where("name = ?", name).First(&role).Error; err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				return service.ErrRoleNotFound
+			}
+			return fmt.Errorf("failed to get role for delete: %w", err)
+		}
+		var users int64
+		if err := tx.Model(&model.User{}).Where("roles @> ?::jsonb", fmt.Sprintf("[%q]", name)).Count(&users).Error; err != nil {
+			return fmt.Errorf("failed to count users with role: %w", err)
+		}
+		if users > 0 {
+			return fmt.Errorf("%w: %d users", service.ErrRoleInUse, users)
+		}
+		if err := tx.Where("role_id = ?", role.ID).Delete(&model.RolePermission{}).Error; err != nil {
+			return fmt.Errorf("failed to delete role permissions: %w", err)
+		}
+		if err := tx.Delete(&role).Error; err != nil {
+			return fmt.Errorf("failed to delete role: %w", err)
+		}
+		return bumpPolicyVersion(tx)
+	})
+}
+
+func (r *postgresRBACRepository) ListPermissions() ([]model.Permission, error) {
+	var perms []model.Permission
+	if err := r.db.Order("name").Find(&perms).Error; err != nil {
+		return nil, fmt.Errorf("failed to list permissions from DB: %w", err)
+	}
+	return perms, nil
+}
+
+func (r *postgresRBACRepository) GetUserOverrides(userID string) (authz.Overrides, error) {
+	overrides, err := userOverrides(r.db.Where("user_permissions.user_id = ?", userID))
+	if err != nil {
+		return authz.Overrides{}, err
+	}
+	return overrides[userID], nil
+}
+
+func (r *postgresRBACRepository) SetUserOverrides(userID string, overrides authz.Overrides) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		grantIDs, err := permissionIDs(tx, overrides.Grant)
+		if err != nil {
+			return err
+		}
+		denyIDs, err := permissionIDs(tx, overrides.Deny)
+		if err != nil {
+			return err
+		}
+		if err := tx.Where("user_id = ?", userID).Delete(&model.UserPermission{}).Error; err != nil {
+			return fmt.Errorf("failed to clear user permissions: %w", err)
+		}
+		rows := make([]model.UserPermission, 0, len(grantIDs)+len(denyIDs))
+		for _, id := range grantIDs {
+			rows = append(rows, model.UserPermission{UserID: userID, PermissionID: id, Effect: model.PermissionEffectGrant})
+		}
+		for _, id := range denyIDs {
+			rows = append(rows, model.UserPermission{UserID: userID, PermissionID: id, Effect: model.PermissionEffectDeny})
+		}
+		if len(rows) > 0 {
+			if err := tx.Create(&rows).Error; err != nil {
+				return fmt.Errorf("failed to store user permissions: %w", err)
+			}
+		}
+		return bumpPolicyVersion(tx)
+	})
+}
+
+func (r *postgresRBACRepository) LoadPolicy() (*authz.Policy, error) {
+	policy := &authz.Policy{}
+	err := r.db.Transaction(func(tx *gorm.DB) error {
+		version, err := policyVersion(tx)
+		if err != nil {
+			return err
+		}
+		roles, err := rolePermissions(tx)
+		if err != nil {
+			return err
+		}
+		overrides, err := userOverrides(tx)
+		if err != nil {
+			return err
+		}
+		policy.Version, policy.Roles, policy.UserOverrides = version, roles, overrides
+		return nil
+	}, &sql.TxOptions{Isolation: sql.LevelRepeatableRead, ReadOnly: true})
+	if err != nil {
+		return nil, err
+	}
+	return policy, nil
+}
+
+func (r *postgresRBACRepository) PolicyVersion() (int64, error) {
+	return policyVersion(r.db)
+}
+
+// rolePermissions maps every role in scope to its permission names; roles without permissions map to an empty list.
+func rolePermissions(scope *gorm.DB) (map[string][]string, error) {
+	var rows []rolePermissionRow
+	err := scope.Table("roles").
+		Select("roles.name AS role, permissions.name AS permission").
+		Joins("LEFT JOIN role_permissions ON role_permissions.role_id = roles.id").
+		Joins("LEFT JOIN permissions ON permissions.id = role_permissions.permission_id").
+		Order("roles.name, permissions.name").
+		Scan(&rows).Error
+	if err != nil {
+		return nil, fmt.Errorf("failed to load role permissions from DB: %w", err)
+	}
+	roles := make(map[string][]string)
+	for _, row := range rows {
+		if _, ok := roles[row.Role]; !ok {
+			roles[row.Role] = []string{}
+		}
+		if row.Permission.Valid {
+			roles[row.Role] = append(roles[row.Role], row.Permission.String)
+		}
+	}
+	return roles, nil
+}
+
+func userOverrides(scope *gorm.DB) (map[string]authz.Overrides, error) {
+	var rows []userOverrideRow
+	err := scope.Table("user_permissions").
+		Select("user_permissions.user_id, user_permissions.effect, permissions.name AS permission").
+		Joins("JOIN permissions ON permissions.id = user_permissions.permission_id").
+		Order("user_permissions.user_id, permissions.name").
+		Scan(&rows).Error
+	if err != nil {
+		return nil, fmt.Errorf("failed to load user permissions from DB: %w", err)
+	}
+	overrides := make(map[string]authz.Overrides)
+	for _, row := range rows {
+		o := overrides[row.UserID]
+		if row.Effect == model.PermissionEffectDeny {
+			o.Deny = append(o.Deny, row.Permission)
+		} else {
+			o.Grant = append(o.Grant, row.Permission)
+		}
+		overrides[row.UserID] = o
+	}
+	return overrides, nil
+}
+
+// permissionIDs resolves permission names and rejects any that are not defined.
+func permissionIDs(tx *gorm.DB, names []string) ([]string, error) {
+	if len(names) == 0 {
+		return nil, nil
+	}
+	var perms []model.Permission
+	if err := tx.Where("name IN ?", names).Find(&perms).Error; err != nil {
+		return nil, fmt.Errorf("failed to resolve permissions: %w", err)
+	}
+	known := make(map[string]string, len(perms))
+	for _, p := range perms {
+		known[p.Name] = p.ID
+	}
+	var ids, unknown []string
+	seen := make(map[string]bool, len(names))
+	for _, name := range names {
+		if seen[name] {
+			continue
+		}
+		seen[name] = true
+		id, ok := known[name]
+		if !ok {
+			unknown = append(unknown, name)
+			continue
+		}
+		ids = append(ids, id)
+	}
+	if len(unknown) > 0 {
+		return nil, fmt.Errorf("%w: %s", service.ErrUnknownPermission, strings.Join(unknown, ", "))
+	}
+	return ids, nil
+}
+
+func replaceRolePermissions(tx *gorm.DB, roleID string, permissionIDs []string) error {
+	if err := tx.Where("role_id = ?", roleID).Delete(&model.RolePermission{}).Error; err != nil {
+		return fmt.Errorf("failed to clear role permissions: %w", err)
+	}
+	if len(permissionIDs) == 0 {
+		return nil
+	}
+	rows := make([]model.RolePermission, 0, len(permissionIDs))
+	for _, id := range permissionIDs {
+		rows = append(rows, model.RolePermission{RoleID: roleID, PermissionID: id})
+	}
+	if err := tx.Create(&rows).Error; err != nil {
+		return fmt.Errorf("failed to store role permissions: %w", err)
+	}
+	return nil
+}
+
+func policyVersion(db *gorm.DB) (int64, error) {
+	var pv model.PolicyVersion
+	if err := db.Where("id = ?", 1).First(&pv).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return 0, nil
+		}
+		return 0, fmt.Errorf("failed to read policy version: %w", err)
+	}
+	return pv.Version, nil
+}
+
+func bumpPolicyVersion(tx *gorm.DB) error {
+	err := tx.Exec(`INSERT INTO policy_versions (id, version, updated_at) VALUES (1, 1, ?)
+		ON CONFLICT (id) DO UPDATE SET version = policy_versions.version + 1, updated_at = EXCLUDED.updated_at`, time.Now()).Error
+	if err != nil {
+		return fmt.Errorf("failed to bump policy version: %w", err)
+	}
+	return nil
+}
@@ -13,10 +13,10 @@ func SeedInitialData(db *gorm.DB) error{
 	utils.Log.Info("Attempting to seed initial data (roles and permissions)...")
 
 	rolesToSeed := []model.Role{
-		{Name: model.RoleAdmin, Description: "System Administrator with full access."},
-		{Name: model.RoleOwner, Description: "Business Owner with oversight and key financial access."},
-		{Name: model.RoleSalesperson, Description: "Sales representative, manages sales and customer interactions."},
-		{Name: model.RoleAccountant, Description: "Manages financial transactions, reports, and accounts."},
+		{Name: model.RoleAdmin, Description: "System Administrator with full access.", IsSystem: true},
+		{Name: model.RoleOwner, Description: "Business Owner with oversight and key financial access.", IsSystem: true},
+		{Name: model.RoleSalesperson, Description: "Sales representative, manages sales and customer interactions.", IsSystem: true},
+		{Name: model.RoleAccountant, Description: "Manages financial transactions, reports, and accounts.", IsSystem: true},
 	}
 
 	createdRoles := make(map[string]model.Role)
@@ -38,6 +38,12 @@ func SeedInitialData(db *gorm.DB) error{
 			continue
 		} else {
 			utils.Log.Info("Role already exists, skipping seed", zap.String("role_name", role.Name))
+			if !existingRole.IsSystem {
+				// Roles seeded before custom roles existed were not flagged as built-in.
+				if err := db.Model(&existingRole).Update("is_system", true).Error; err != nil {
+					utils.Log.Error("Failed to mark role as system role", zap.String("role_name", role.Name), zap.Error(err))
+				}
+			}
 			createdRoles[role.Name] = existingRole
 		}
 	}
@@ -56,6 +62,7 @@ func SeedInitialData(db *gorm.DB) error{
 		{Name: model.PermInventoryUpdateGoldPrice, Description: "Allows manual update of gold prices."},
 
 		{Name: model.PermCRMReadCustomer, Description: "Allows reading customer details."},
+		{Name: model.PermCRMReadAllCustomers, Description: "Allows reading customers assigned to other employees."},
 		{Name: model.PermCRMCreateCustomer, Description: "Allows creating new customers."},
 		{Name: model.PermCRMUpdateCustomer, Description: "Allows updating customer details."},
 		{Name: model.PermCRMDeleteCustomer, Description: "Allows soft-deleting customers."},
@@ -79,10 +86,12 @@ func SeedInitialData(db *gorm.DB) error{
 		{Name: model.PermTransactionManageCheques, Description: "Allows managing cheques."},
 
 		{Name: model.PermReportViewSalesSummary, Description: "Allows viewing sales summary reports."},
+		{Name: model.PermReportViewAllSales, Description: "Allows viewing sales figures of every salesperson, not only one's own."},
 		{Name: model.PermReportViewInventorySummary, Description: "Allows viewing inventory summary reports."},
 		{Name: model.PermReportViewProfitLoss, Description: "Allows viewing profit and loss reports."},
 		{Name: model.PermReportViewBalances, Description: "Allows viewing account balances reports (AR/AP)."},
 		{Name: model.PermReportExportData, Description: "Allows exporting report data."},
+		{Name: model.PermReportImportData, Description: "Allows importing data from spreadsheets."},
 
 		{Name: model.PermUserRead, Description: "Allows reading user details."},
 		{Name: model.PermUserCreate, Description: "Allows creating new users."},
@@ -91,6 +100,8 @@ func SeedInitialData(db *gorm.DB) error{
 		{Name: model.PermUserChangeAnyPassword, Description: "Allows changing any user's password."},
 		{Name: model.PermSystemSettingsRead, Description: "Allows reading system settings."},
 		{Name: model.PermSystemSettingsManage, Description: "Allows managing system settings."},
+		{Name: model.PermAuditRead, Description: "Allows searching the audit log and verifying its hash chain."},
+		{Name: model.PermAPIKeyManage, Description: "Allows issuing, listing and revoking API keys for integrations and devices."},
 	}
 
 	createdPerms := make(map[string]model.Permission)
@@ -120,29 +131,32 @@ func SeedInitialData(db *gorm.DB) error{
 			model.PermInventoryReadItem, model.PermInventoryCreateItem, model.PermInventoryUpdateItem, model.PermInventoryDeleteItem,
 			model.PermInventoryIncreaseStock, model.PermInventoryDecreaseStock, model.PermInventoryReadCategory, model.PermInventoryManageCategory,
 			model.PermInventoryReadGoldPrice, model.PermInventoryUpdateGoldPrice,
-			model.PermCRMReadCustomer, model.PermCRMCreateCustomer, model.PermCRMUpdateCustomer, model.PermCRMDeleteCustomer,
+			model.PermCRMReadCustomer, model.PermCRMReadAllCustomers, model.PermCRMCreateCustomer, model.PermCRMUpdateCustomer, model.PermCRMDeleteCustomer,
 			model.PermCRMReadSupplier, model.PermCRMCreateSupplier, model.PermCRMUpdateSupplier, model.PermCRMDeleteSupplier,
 			model.PermCRMViewCustomerBalance, model.PermCRMViewSupplierBalance,
 			model.PermTransactionReadSaleInvoice, model.PermTransactionCreateSaleInvoice, model.PermTransactionUpdateSaleInvoice, model.PermTransactionDeleteSaleInvoice,
 			model.PermTransactionReadPurchaseInvoice, model.PermTransactionCreatePurchaseInvoice, model.PermTransactionUpdatePurchaseInvoice, model.PermTransactionDeletePurchaseInvoice,
 			model.PermTransactionManagePayments, model.PermTransactionManageExpenses, model.PermTransactionManageCheques,
-			model.PermReportViewSalesSummary, model.PermReportViewInventorySummary, model.PermReportViewProfitLoss, model.PermReportViewBalances, model.PermReportExportData,
+			model.PermReportViewSalesSummary, model.PermReportViewAllSales, model.PermReportViewInventorySummary, model.PermReportViewProfitLoss, model.PermReportViewBalances, model.PermReportExportData,
+			model.PermReportImportData,
 			model.PermUserRead, model.PermUserCreate, model.PermUserUpdate, model.PermUserDelete, model.PermUserChangeAnyPassword,
 			model.PermSystemSettingsRead, model.PermSystemSettingsManage,
+			model.PermAuditRead, model.PermAPIKeyManage,
 		},
 		model.RoleOwner: {
 			model.PermInventoryReadItem, model.PermInventoryCreateItem, model.PermInventoryUpdateItem, model.PermInventoryDeleteItem,
 			model.PermInventoryIncreaseStock, model.PermInventoryDecreaseStock, model.PermInventoryReadCategory, model.PermInventoryManageCategory,
 			model.PermInventoryReadGoldPrice, model.PermInventoryUpdateGoldPrice,
-			model.PermCRMReadCustomer, model.PermCRMCreateCustomer, model.PermCRMUpdateCustomer, model.PermCRMDeleteCustomer,
+			model.PermCRMReadCustomer, model.PermCRMReadAllCustomers, model.PermCRMCreateCustomer, model.PermCRMUpdateCustomer, model.PermCRMDeleteCustomer,
 			model.PermCRMReadSupplier, model.PermCRMCreateSupplier, model.PermCRMUpdateSupplier, model.PermCRMDeleteSupplier,
 			model.PermCRMViewCustomerBalance, model.PermCRMViewSupplierBalance,
 			model.PermTransactionReadSaleInvoice, model.PermTransactionCreateSaleInvoice, model.PermTransactionUpdateSaleInvoice, model.PermTransactionDeleteSaleInvoice,
 			model.PermTransactionReadPurchaseInvoice, model.PermTransactionCreatePurchaseInvoice, model.PermTransactionUpdatePurchaseInvoice, model.PermTransactionDeletePurchaseInvoice,
 			model.PermTransactionManagePayments, model.PermTransactionManageExpenses, model.PermTransactionManageCheques,
-			model.PermReportViewSalesSummary, model.PermReportViewInventorySummary, model.PermReportViewProfitLoss, model.PermReportViewBalances, model.PermReportExportData,
+			model.PermReportViewSalesSummary, model.PermReportViewAllSales, model.PermReportViewInventorySummary, model.PermReportViewProfitLoss, model.PermReportViewBalances, model.PermReportExportData,
 			model.PermUserRead,
 			model.PermSystemSettingsRead,
+			model.PermAuditRead, model.PermAPIKeyManage,
 		},
 		model.RoleSalesperson: {
 			model.PermInventoryReadItem,
@@ -160,16 +174,18 @@ func SeedInitialData(db *gorm.DB) error{
 			model.PermInventoryReadItem,
 			model.PermInventoryReadGoldPrice,
 			model.PermCRMReadCustomer,
+			model.PermCRMReadAllCustomers,
 			model.PermCRMReadSupplier,
 			model.PermCRMViewCustomerBalance,
 			model.PermCRMViewSupplierBalance,
 			model.PermTransactionReadSaleInvoice, model.PermTransactionUpdateSaleInvoice, model.PermTransactionDeleteSaleInvoice,
 			model.PermTransactionReadPurchaseInvoice, model.PermTransactionCreatePurchaseInvoice, model.PermTransactionUpdatePurchaseInvoice, model.PermTransactionDeletePurchaseInvoice,
 			model.PermTransactionManagePayments, model.PermTransactionManageExpenses, model.PermTransactionManageCheques,
-			model.PermReportViewSalesSummary, model.PermReportViewInventorySummary, model.PermReportViewProfitLoss, model.PermReportViewBalances, model.PermReportExportData,
+			model.PermReportViewSalesSummary, model.PermReportViewAllSales, model.PermReportViewInventorySummary, model.PermReportViewProfitLoss, model.PermReportViewBalances, model.PermReportExportData,
 		},
 	}
 
+	mappingsAdded := false
 	for roleName, permNames := range rolePermissionMap {
 		role, roleExists := createdRoles[roleName]
 		if !roleExists {
@@ -193,6 +209,7 @@ func SeedInitialData(db *gorm.DB) error{
 					utils.Log.Error("Failed to seed role-permission mapping",
 						zap.String("role", role.Name), zap.String("permission", permission.Name), zap.Error(err))
 				} else {
+					mappingsAdded = true
 					utils.Log.Info("Role-permission mapping seeded successfully",
 						zap.String("role", role.Name), zap.String("permission", permission.Name))
 				}
@@ -205,6 +222,15 @@ func SeedInitialData(db *gorm.DB) error{
 			}
 		}
 	}
+	// Mappings seeded above bypass RBACRepository. New grants on an existing database (e.g. a permission added
+	// in a release) must still bump the version, otherwise running services keep serving their cached policy.
+	if mappingsAdded {
+		if err := bumpPolicyVersion(db); err != nil {
+			utils.Log.Error("Failed to bump policy version after seeding", zap.Error(err))
+		}
+	} else if err := db.Exec("INSERT INTO policy_versions (id, version, updated_at) VALUES (1, 1, NOW()) ON CONFLICT (id) DO NOTHING").Error; err != nil {
+		utils.Log.Error("Failed to initialise policy version", zap.Error(err))
+	}
 	utils.Log.Info("Initial data seeding completed.")
 
 	
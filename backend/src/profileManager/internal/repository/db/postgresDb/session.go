@@ -0,0 +1,187 @@
+package postgresDb
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"profile-gold/internal/model"
+	service "profile-gold/internal/service/common"
+	"profile-gold/internal/utils"
+
+	"go.uber.org/zap"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// SessionRepository stores signed-in devices and the refresh tokens that keep them alive.
+type SessionRepository interface {
+	// CreateSession stores the session together with its first refresh token.
+	CreateSession(session *model.Session, refreshToken *model.RefreshToken) error
+	// RotateRefreshToken consumes the refresh token and stores next in its place; next never outlives the session.
+	// It returns service.ErrInvalidToken if the token is unknown or expired or its session is no longer active.
+	// If the token was already used, the session is revoked and service.ErrRefreshTokenReused is returned with it.
+	RotateRefreshToken(tokenHash string, next *model.RefreshToken, client model.ClientInfo, now time.Time) (*model.Session, error)
+	ListActiveSessions(userID string, now time.Time) ([]model.Session, error)
+	// RevokeSession returns service.ErrSessionNotFound if the user has no active session with this ID.
+	RevokeSession(userID, sessionID, reason string, now time.Time) error
+	// RevokeOtherSessions and RevokeAllSessions return the IDs of the sessions they revoked.
+	RevokeOtherSessions(userID, keepSessionID, reason string, now time.Time) ([]string, error)
+	RevokeAllSessions(userID, reason string, now time.Time) ([]string, error)
+}
+
+type postgresSessionRepository struct {
+	db *gorm.DB
+}
+
+func NewPostgresSessionRepository(db *gorm.DB) SessionRepository {
+	if db == nil {
+		utils.Log.Fatal("GORM DB instance is nil for PostgresSessionRepository.")
+	}
+	return &postgresSessionRepository{db: db}
+}
+
+func (r *postgresSessionRepository) CreateSession(session *model.Session, refreshToken *model.RefreshToken) error {
+	err := r.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Create(session).Error; err != nil {
+			return err
+		}
+		refreshToken.SessionID = session.ID
+		return tx.Create(refreshToken).Error
+	})
+	if err != nil {
+		return fmt.Errorf("failed to create session in DB: %w", err)
+	}
+	return nil
+}
+
+func (r *postgresSessionRepository) RotateRefreshToken(tokenHash string, next *model.RefreshToken, client model.ClientInfo, now time.Time) (*model.Session, error) {
+	var session model.Session
+	reused := false
+	err := r.db.Transaction(func(tx *gorm.DB) error {
+		var token model.RefreshToken
+		if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).Where("token_hash = ?", tokenHash).First(&token).Error; err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				return service.ErrInvalidToken
+			}
+			return err
+		}
+		if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).First(&session, "id = ?", token.SessionID).Error; err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				return service.ErrInvalidToken
+			}
+			return err
+		}
+		if session.RevokedAt != nil || !session.ExpiresAt.After(now) {
+			return service.ErrInvalidToken
+		}
+		if token.UsedAt != nil {
+			// The token was stolen or replayed: whoever holds the newer token is cut off as well.
+			reused = true
+			_, err := revokeSessions(tx, "refresh_token_reuse", now, "id = ?", session.ID)
+			return err
+		}
+		if !token.ExpiresAt.After(now) {
+			return service.ErrInvalidToken
+		}
+
+		if err := tx.Model(&token).Update("used_at", now).Error; err != nil {
+			return err
+		}
+		next.SessionID = session.ID
+		if next.ExpiresAt.After(session.ExpiresAt) {
+			next.ExpiresAt = session.ExpiresAt
+		}
+		if err := tx.Create(next).Error; err != nil {
+			return err
+		}
+		session.LastUsedAt = now
+		session.IPAddress = client.IPAddress
+		session.UserAgent = client.UserAgent
+		return tx.Model(&session).Updates(map[string]interface{}{
+			"last_used_at": now,
+			"ip_address":   client.IPAddress,
+			"user_agent":   client.UserAgent,
+		}).Error
+	})
+	if err != nil {
+		if errors.Is(err, service.ErrInvalidToken) {
+			return nil, err
+		}
+		return nil, fmt.Errorf("failed to rotate refresh token in DB: %w", err)
+	}
+	if reused {
+		utils.Log.Warn("Refresh token reuse detected, session revoked",
+			zap.String("user_id", session.UserID), zap.String("session_id", session.ID))
+		return &session, service.ErrRefreshTokenReused
+	}
+	return &session, nil
+}
+
+func (r *postgresSessionRepository) ListActiveSessions(userID string, now time.Time) ([]model.Session, error) {
+	var sessions []model.Session
+	err := r.db.Where("user_id = ? AND revoked_at IS NULL AND expires_at > ?", userID, now).
+		Order("last_used_at DESC").
+		Find(&sessions).Error
+	if err != nil {
+		return nil, fmt.Errorf("failed to list sessions from DB: %w", err)
+	}
+	return sessions, nil
+}
+
+func (r *postgresSessionRepository) RevokeSession(userID, sessionID, reason string, now time.Time) error {
+	result := r.db.Model(&model.Session{}).
+		Where("id = ? AND user_id = ? AND revoked_at IS NULL", sessionID, userID).
+		Updates(map[string]interface{}{"revoked_at": now, "revoked_reason": reason})
+	if result.Error != nil {
+		return fmt.Errorf("failed to revoke session in DB: %w", result.Error)
+	}
+	if result.RowsAffected == 0 {
+		return service.ErrSessionNotFound
+	}
+	utils.Log.Info("Session revoked", zap.String("user_id", userID), zap.String("session_id", sessionID), zap.String("reason", reason))
+	return nil
+}
+
+func (r *postgresSessionRepository) RevokeOtherSessions(userID, keepSessionID, reason string, now time.Time) ([]string, error) {
+	var ids []string
+	err := r.db.Transaction(func(tx *gorm.DB) error {
+		var err error
+		ids, err = revokeSessions(tx, reason, now, "user_id = ? AND id <> ?", userID, keepSessionID)
+		return err
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to revoke other sessions in DB: %w", err)
+	}
+	return ids, nil
+}
+
+func (r *postgresSessionRepository) RevokeAllSessions(userID, reason string, now time.Time) ([]string, error) {
+	var ids []string
+	err := r.db.Transaction(func(tx *gorm.DB) error {
+		var err error
+		ids, err = revokeSessions(tx, reason, now, "user_id = ?", userID)
+		return err
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to revoke sessions in DB: %w", err)
+	}
+	return ids, nil
+}
+
+// revokeSessions revokes the still-active sessions matching the condition and returns their IDs.
+func revokeSessions(tx *gorm.DB, reason string, now time.Time, query string, args ...interface{}) ([]string, error) {
+	var ids []string
+	if err := tx.Model(&model.Session{}).Where(query, args...).Where("revoked_at IS NULL").Pluck("id", &ids).Error; err != nil {
+		return nil, err
+	}
+	if len(ids) == 0 {
+		return nil, nil
+	}
+	err := tx.Model(&model.Session{}).Where("id IN ?", ids).
+		Updates(map[string]interface{}{"revoked_at": now, "revoked_reason": reason}).Error
+	if err != nil {
+		return nil, err
+	}
+	return ids, nil
+}
@@ -0,0 +1,147 @@
+package postgresDb
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"profile-gold/internal/model"
+	service "profile-gold/internal/service/common"
+	"profile-gold/internal/utils"
+
+	"go.uber.org/zap"
+	"gorm.io/gorm"
+)
+
+// TwoFARepository stores the 2FA enrollment state and recovery codes of a user.
+type TwoFARepository interface {
+	SetPendingTwoFASecret(userID, secret string) error
+	// ActivateTwoFA promotes the pending secret to the active one and replaces the recovery codes.
+	// It returns service.ErrTwoFASetupNotStarted if the pending secret changed in the meantime.
+	ActivateTwoFA(userID, pendingSecret string, codeHashes []string) error
+	// DisableTwoFA clears both secrets and deletes the recovery codes.
+	DisableTwoFA(userID string) error
+	ReplaceRecoveryCodes(userID string, codeHashes []string) error
+	// UseRecoveryCode marks the code as used; it returns service.ErrInvalidTwoFACode if it is unknown or already used.
+	UseRecoveryCode(userID, codeHash string, now time.Time) error
+	CountUnusedRecoveryCodes(userID string) (int64, error)
+}
+
+type postgresTwoFARepository struct {
+	db *gorm.DB
+}
+
+func NewPostgresTwoFARepository(db *gorm.DB) TwoFARepository {
+	if db == nil {
+		utils.Log.Fatal("GORM DB instance is nil for PostgresTwoFARepository.")
+	}
+	return &postgresTwoFARepository{db: db}
+}
+
+func (r *postgresTwoFARepository) SetPendingTwoFASecret(userID, secret string) error {
+	result := r.db.Model(&model.User{}).Where("id = ?", userID).Update("two_fa_pending_secret", secret)
+	if result.Error != nil {
+		return fmt.Errorf("failed to set pending 2FA secret in DB: %w", result.Error)
+	}
+	if result.RowsAffected == 0 {
+		return service.ErrUserNotFound
+	}
+	return nil
+}
+
+func (r *postgresTwoFARepository) ActivateTwoFA(userID, pendingSecret string, codeHashes []string) error {
+	err := r.db.Transaction(func(tx *gorm.DB) error {
+		result := tx.Model(&model.User{}).
+			Where("id = ? AND two_fa_pending_secret = ?", userID, pendingSecret).
+			Updates(map[string]interface{}{
+				"two_fa_secret":         pendingSecret,
+				"two_fa_pending_secret": "",
+				"two_fa_enabled":        true,
+			})
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			return service.ErrTwoFASetupNotStarted
+		}
+		return replaceRecoveryCodes(tx, userID, codeHashes)
+	})
+	if err != nil {
+		if errors.Is(err, service.ErrTwoFASetupNotStarted) {
+			return err
+		}
+		return fmt.Errorf("failed to activate 2FA in DB: %w", err)
+	}
+	utils.Log.Info("2FA activated", zap.String("user_id", userID))
+	return nil
+}
+
+func (r *postgresTwoFARepository) DisableTwoFA(userID string) error {
+	err := r.db.Transaction(func(tx *gorm.DB) error {
+		result := tx.Model(&model.User{}).Where("id = ?", userID).
+			Updates(map[string]interface{}{
+				"two_fa_secret":         "",
+				"two_fa_pending_secret": "",
+				"two_fa_enabled":        false,
+			})
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			return service.ErrUserNotFound
+		}
+		return tx.Where("user_id = ?", userID).Delete(&model.RecoveryCode{}).Error
+	})
+	if err != nil {
+		if errors.Is(err, service.ErrUserNotFound) {
+			return err
+		}
+		return fmt.Errorf("failed to disable 2FA in DB: %w", err)
+	}
+	return nil
+}
+
+func (r *postgresTwoFARepository) ReplaceRecoveryCodes(userID string, codeHashes []string) error {
+	if err := r.db.Transaction(func(tx *gorm.DB) error {
+		return replaceRecoveryCodes(tx, userID, codeHashes)
+	}); err != nil {
+		return fmt.Errorf("failed to replace recovery codes in DB: %w", err)
+	}
+	return nil
+}
+
+func replaceRecoveryCodes(tx *gorm.DB, userID string, codeHashes []string) error {
+	if err := tx.Where("user_id = ?", userID).Delete(&model.RecoveryCode{}).Error; err != nil {
+		return err
+	}
+	codes := make([]model.RecoveryCode, len(codeHashes))
+	for i, hash := range codeHashes {
+		codes[i] = model.RecoveryCode{UserID: userID, CodeHash: hash}
+	}
+	if len(codes) == 0 {
+		return nil
+	}
+	return tx.Create(&codes).Error
+}
+
+func (r *postgresTwoFARepository) UseRecoveryCode(userID, codeHash string, now time.Time) error {
+	result := r.db.Model(&model.RecoveryCode{}).
+		Where("user_id = ? AND code_hash = ? AND used_at IS NULL", userID, codeHash).
+		Update("used_at", now)
+	if result.Error != nil {
+		return fmt.Errorf("failed to use recovery code in DB: %w", result.Error)
+	}
+	if result.RowsAffected == 0 {
+		return service.ErrInvalidTwoFACode
+	}
+	utils.Log.Info("Recovery code used", zap.String("user_id", userID))
+	return nil
+}
+
+func (r *postgresTwoFARepository) CountUnusedRecoveryCodes(userID string) (int64, error) {
+	var count int64
+	if err := r.db.Model(&model.RecoveryCode{}).Where("user_id = ? AND used_at IS NULL", userID).Count(&count).Error; err != nil {
+		return 0, fmt.Errorf("failed to count recovery codes in DB: %w", err)
+	}
+	return count, nil
+}
@@ -0,0 +1,149 @@
+package redisdb
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"profile-gold/internal/utils"
+
+	"github.com/go-redis/redis/v8"
+)
+
+// AttemptRepository counts failed attempts and holds temporary locks for brute-force protection.
+// Keys are opaque subjects such as "login:user:alice" or "login:ip:10.0.0.1".
+type AttemptRepository interface {
+	// Reserve checks the lock and takes an attempt slot in one step, so concurrent requests cannot all pass the check
+	// before any of them is counted. Once the failures plus the attempts in flight reach maxFailures only one attempt
+	// may be in flight. It returns how long to wait when the slot is refused and 0 when it was taken; an untaken slot
+	// expires after hold.
+	Reserve(key string, maxFailures int64, hold time.Duration) (time.Duration, error)
+	// Release gives back a slot taken by Reserve without counting a failure.
+	Release(key string) error
+	// RecordFailure gives back a slot and counts a failure; the counter expires window after the latest failure.
+	RecordFailure(key string, window time.Duration) (int64, error)
+	Lock(key string, duration time.Duration) error
+	// Clear drops the counter, the slots in flight and the lock of each key.
+	Clear(keys ...string) error
+}
+
+type redisAttemptRepository struct {
+	client *redis.Client
+}
+
+func NewRedisAttemptRepository(client *redis.Client) AttemptRepository {
+	if client == nil {
+		utils.Log.Fatal("Redis client is nil for RedisAttemptRepository.")
+	}
+	return &redisAttemptRepository{client: client}
+}
+
+func attemptFailuresKey(key string) string {
+	return fmt.Sprintf("auth_failures:%s", key)
+}
+
+func attemptLockKey(key string) string {
+	return fmt.Sprintf("auth_lock:%s", key)
+}
+
+func attemptPendingKey(key string) string {
+	return fmt.Sprintf("auth_pending:%s", key)
+}
+
+// KEYS: lock, failures, pending. ARGV: max failures, hold in ms.
+var reserveAttemptScript = redis.NewScript(`
+local ttl = redis.call('PTTL', KEYS[1])
+if ttl > 0 then
+	return ttl
+end
+local pending = tonumber(redis.call('GET', KEYS[3]) or '0')
+if pending > 0 and tonumber(redis.call('GET', KEYS[2]) or '0') + pending >= tonumber(ARGV[1]) then
+	local wait = redis.call('PTTL', KEYS[3])
+	if wait > 0 then
+		return wait
+	end
+	return tonumber(ARGV[2])
+end
+redis.call('INCR', KEYS[3])
+redis.call('PEXPIRE', KEYS[3], ARGV[2])
+return 0
+`)
+
+// KEYS: pending.
+var releaseAttemptScript = redis.NewScript(`
+if tonumber(redis.call('GET', KEYS[1]) or '0') > 0 then
+	redis.call('DECR', KEYS[1])
+end
+return 0
+`)
+
+// KEYS: failures, pending. ARGV: window in ms.
+var recordFailureScript = redis.NewScript(`
+if tonumber(redis.call('GET', KEYS[2]) or '0') > 0 then
+	redis.call('DECR', KEYS[2])
+end
+local failures = redis.call('INCR', KEYS[1])
+redis.call('PEXPIRE', KEYS[1], ARGV[1])
+return failures
+`)
+
+func (r *redisAttemptRepository) Reserve(key string, maxFailures int64, hold time.Duration) (time.Duration, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	keys := []string{attemptLockKey(key), attemptFailuresKey(key), attemptPendingKey(key)}
+	wait, err := reserveAttemptScript.Run(ctx, r.client, keys, maxFailures, hold.Milliseconds()).Int64()
+	if err != nil {
+		return 0, fmt.Errorf("failed to reserve attempt: %w", err)
+	}
+	return time.Duration(wait) * time.Millisecond, nil
+}
+
+func (r *redisAttemptRepository) Release(key string) error {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	if err := releaseAttemptScript.Run(ctx, r.client, []string{attemptPendingKey(key)}).Err(); err != nil {
+		return fmt.Errorf("failed to release attempt: %w", err)
+	}
+	return nil
+}
+
+func (r *redisAttemptRepository) RecordFailure(key string, window time.Duration) (int64, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	keys := []string{attemptFailuresKey(key), attemptPendingKey(key)}
+	failures, err := recordFailureScript.Run(ctx, r.client, keys, window.Milliseconds()).Int64()
+	if err != nil {
+		return 0, fmt.Errorf("failed to record failed attempt: %w", err)
+	}
+	return failures, nil
+}
+
+func (r *redisAttemptRepository) Lock(key string, duration time.Duration) error {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	if err := r.client.SetEX(ctx, attemptLockKey(key), "locked", duration).Err(); err != nil {
+		return fmt.Errorf("failed to store lock: %w", err)
+	}
+	return nil
+}
+
+func (r *redisAttemptRepository) Clear(keys ...string) error {
+	if len(keys) == 0 {
+		return nil
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	redisKeys := make([]string, 0, 3*len(keys))
+	for _, key := range keys {
+		redisKeys = append(redisKeys, attemptFailuresKey(key), attemptPendingKey(key), attemptLockKey(key))
+	}
+	if err := r.client.Del(ctx, redisKeys...).Err(); err != nil {
+		return fmt.Errorf("failed to clear failed attempts: %w", err)
+	}
+	return nil
+}
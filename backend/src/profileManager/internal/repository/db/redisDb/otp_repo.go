@@ -0,0 +1,176 @@
+package redisdb
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"profile-gold/internal/utils"
+
+	"github.com/go-redis/redis/v8"
+	"go.uber.org/zap"
+)
+
+// OTPRepository keeps SMS codes waiting to be entered. Only the hash of a code is stored, next to a payload the
+// caller gets back when the code is accepted. Keys are chosen by the caller, e.g. "login:09123456789".
+type OTPRepository interface {
+	// ClaimCooldown starts the resend cooldown of key and returns 0, or returns the time left if one is running.
+	ClaimCooldown(key string, cooldown time.Duration) (time.Duration, error)
+	// ReleaseCooldown ends the cooldown early, e.g. when the code could not be delivered.
+	ReleaseCooldown(key string) error
+	// SaveCode replaces any pending code of key and resets its failure count.
+	SaveCode(key, codeHash, payload string, ttl time.Duration) error
+	// GetCode returns empty strings when no code is pending or it has expired.
+	GetCode(key string) (codeHash, payload string, err error)
+	// ConsumeCode takes the pending code out with GETDEL, so of two requests presenting the same code only one gets
+	// ok. If a newer code replaced the one that was read, ok is false and the newer code is gone too.
+	ConsumeCode(key, codeHash string) (payload string, ok bool, err error)
+	IncrementCodeFailures(key string) (int64, error)
+	DeleteCode(key string) error
+}
+
+type redisOTPRepository struct {
+	client *redis.Client
+}
+
+func NewRedisOTPRepository(client *redis.Client) OTPRepository {
+	if client == nil {
+		utils.Log.Fatal("Redis client is nil for RedisOTPRepository.")
+	}
+	return &redisOTPRepository{client: client}
+}
+
+func otpKey(key string) string {
+	return fmt.Sprintf("otp:%s", key)
+}
+
+func otpFailuresKey(key string) string {
+	return fmt.Sprintf("otp_failures:%s", key)
+}
+
+// otpValue stores the code hash (hex, so it has no colon) and the payload in one string, which GETDEL can take atomically.
+func otpValue(codeHash, payload string) string {
+	return codeHash + ":" + payload
+}
+
+func splitOTPValue(value string) (string, string) {
+	codeHash, payload, _ := strings.Cut(value, ":")
+	return codeHash, payload
+}
+
+func otpCooldownKey(key string) string {
+	return fmt.Sprintf("otp_cooldown:%s", key)
+}
+
+func (r *redisOTPRepository) ClaimCooldown(key string, cooldown time.Duration) (time.Duration, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	claimed, err := r.client.SetNX(ctx, otpCooldownKey(key), "1", cooldown).Result()
+	if err != nil {
+		return 0, fmt.Errorf("failed to start SMS code cooldown: %w", err)
+	}
+	if claimed {
+		return 0, nil
+	}
+	ttl, err := r.client.PTTL(ctx, otpCooldownKey(key)).Result()
+	if err != nil {
+		return 0, fmt.Errorf("failed to read SMS code cooldown: %w", err)
+	}
+	if ttl <= 0 {
+		// The cooldown ended between the two calls.
+		return time.Second, nil
+	}
+	return ttl, nil
+}
+
+func (r *redisOTPRepository) ReleaseCooldown(key string) error {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	if err := r.client.Del(ctx, otpCooldownKey(key)).Err(); err != nil {
+		return fmt.Errorf("failed to release SMS code cooldown: %w", err)
+	}
+	return nil
+}
+
+func (r *redisOTPRepository) SaveCode(key, codeHash, payload string, ttl time.Duration) error {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	_, err := r.client.TxPipelined(ctx, func(pipe redis.Pipeliner) error {
+		pipe.Set(ctx, otpKey(key), otpValue(codeHash, payload), ttl)
+		pipe.Del(ctx, otpFailuresKey(key))
+		return nil
+	})
+	if err != nil {
+		utils.Log.Error("Failed to store SMS code in Redis", zap.String("key", key), zap.Error(err))
+		return fmt.Errorf("failed to store SMS code: %w", err)
+	}
+	return nil
+}
+
+func (r *redisOTPRepository) GetCode(key string) (string, string, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	value, err := r.client.Get(ctx, otpKey(key)).Result()
+	if errors.Is(err, redis.Nil) {
+		return "", "", nil
+	}
+	if err != nil {
+		return "", "", fmt.Errorf("failed to get SMS code: %w", err)
+	}
+	codeHash, payload := splitOTPValue(value)
+	return codeHash, payload, nil
+}
+
+func (r *redisOTPRepository) ConsumeCode(key, codeHash string) (string, bool, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	value, err := r.client.GetDel(ctx, otpKey(key)).Result()
+	if errors.Is(err, redis.Nil) {
+		return "", false, nil
+	}
+	if err != nil {
+		return "", false, fmt.Errorf("failed to consume SMS code: %w", err)
+	}
+	r.client.Del(ctx, otpFailuresKey(key))
+	storedHash, payload := splitOTPValue(value)
+	if storedHash != codeHash {
+		return "", false, nil
+	}
+	return payload, true, nil
+}
+
+func (r *redisOTPRepository) IncrementCodeFailures(key string) (int64, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	pipe := r.client.TxPipeline()
+	failures := pipe.Incr(ctx, otpFailuresKey(key))
+	ttl := pipe.PTTL(ctx, otpKey(key))
+	if _, err := pipe.Exec(ctx); err != nil {
+		return 0, fmt.Errorf("failed to count SMS code failure: %w", err)
+	}
+	// The counter lives exactly as long as its code; if the code expired in between, do not leave it behind.
+	if ttl.Val() > 0 {
+		r.client.PExpire(ctx, otpFailuresKey(key), ttl.Val())
+	} else {
+		r.client.Del(ctx, otpFailuresKey(key))
+	}
+	return failures.Val(), nil
+}
+
+func (r *redisOTPRepository) DeleteCode(key string) error {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	if err := r.client.Del(ctx, otpKey(key), otpFailuresKey(key)).Err(); err != nil {
+		return fmt.Errorf("failed to delete SMS code: %w", err)
+	}
+	return nil
+}
@@ -2,6 +2,7 @@ package redisdb
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"profile-gold/internal/utils"
 	"time"
@@ -16,6 +17,15 @@ type redisTokenRepository struct {
 type TokenRepository interface {
 	AddTokenToBlacklist(token string, expiration time.Duration) error
 	IsTokenBlacklisted(token string) (bool, error)
+	// RevokeUserTokens invalidates every token of the user issued before the given time, compared in milliseconds
+	// so a token issued right after the revocation, e.g. at the next login, stays valid.
+	RevokeUserTokens(userID string, before time.Time, expiration time.Duration) error
+	IsUserTokenRevoked(userID string, issuedAt time.Time) (bool, error)
+	// RevokeSessions marks the sessions so their still-valid access tokens are rejected; the API Gateway reads the same keys.
+	RevokeSessions(sessionIDs []string, expiration time.Duration) error
+	IsSessionRevoked(sessionID string) (bool, error)
+	// RevokeAPIKey marks the key so the API Gateway stops accepting it before its cached verification runs out.
+	RevokeAPIKey(keyID string, expiration time.Duration) error
 }
 
 func (r *redisTokenRepository) IsTokenBlacklisted(token string) (bool, error) {
@@ -53,6 +63,80 @@ func (r *redisTokenRepository) AddTokenToBlacklist(token string, expiration time
 	return nil
 }
 
+func (r *redisTokenRepository) RevokeUserTokens(userID string, before time.Time, expiration time.Duration) error {
+	key := fmt.Sprintf("jwt_revoked_before:%s", userID)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	if err := r.client.SetEX(ctx, key, before.UnixMilli(), expiration).Err(); err != nil {
+		utils.Log.Error("Failed to store token revocation in Redis", zap.String("user_id", userID), zap.Error(err))
+		return fmt.Errorf("failed to revoke user tokens: %w", err)
+	}
+	utils.Log.Info("All tokens revoked for user", zap.String("user_id", userID), zap.Time("before", before))
+	return nil
+}
+
+func (r *redisTokenRepository) IsUserTokenRevoked(userID string, issuedAt time.Time) (bool, error) {
+	key := fmt.Sprintf("jwt_revoked_before:%s", userID)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	before, err := r.client.Get(ctx, key).Int64()
+	if errors.Is(err, redis.Nil) {
+		return false, nil
+	}
+	if err != nil {
+		utils.Log.Error("Failed to check token revocation in Redis", zap.String("user_id", userID), zap.Error(err))
+		return false, err
+	}
+	return issuedAt.UnixMilli() < before, nil
+}
+
+func (r *redisTokenRepository) RevokeSessions(sessionIDs []string, expiration time.Duration) error {
+	if len(sessionIDs) == 0 {
+		return nil
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	pipe := r.client.Pipeline()
+	for _, id := range sessionIDs {
+		pipe.SetEX(ctx, fmt.Sprintf("session_revoked:%s", id), "revoked", expiration)
+	}
+	if _, err := pipe.Exec(ctx); err != nil {
+		utils.Log.Error("Failed to store session revocation in Redis", zap.Strings("session_ids", sessionIDs), zap.Error(err))
+		return fmt.Errorf("failed to revoke sessions: %w", err)
+	}
+	return nil
+}
+
+func (r *redisTokenRepository) IsSessionRevoked(sessionID string) (bool, error) {
+	key := fmt.Sprintf("session_revoked:%s", sessionID)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	result, err := r.client.Exists(ctx, key).Result()
+	if err != nil {
+		utils.Log.Error("Failed to check session revocation in Redis", zap.String("session_id", sessionID), zap.Error(err))
+		return false, err
+	}
+	return result == 1, nil
+}
+
+func (r *redisTokenRepository) RevokeAPIKey(keyID string, expiration time.Duration) error {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	if err := r.client.SetEX(ctx, fmt.Sprintf("api_key_revoked:%s", keyID), "revoked", expiration).Err(); err != nil {
+		utils.Log.Error("Failed to store API key revocation in Redis", zap.String("api_key_id", keyID), zap.Error(err))
+		return fmt.Errorf("failed to revoke API key: %w", err)
+	}
+	return nil
+}
+
 func NewRedisTokenRepository(client *redis.Client) TokenRepository {
 	if client == nil {
 		utils.Log.Fatal("Redis client is nil for RedisTokenRepository.")
@@ -0,0 +1,111 @@
+package redisdb
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"profile-gold/internal/utils"
+
+	"github.com/go-redis/redis/v8"
+	"go.uber.org/zap"
+)
+
+// TwoFAChallengeRepository keeps pending 2FA logins and recently used TOTP codes.
+// Challenges are keyed by the hash of the token handed to the client.
+type TwoFAChallengeRepository interface {
+	CreateChallenge(challengeHash, userID string, ttl time.Duration) error
+	// GetChallengeUserID returns "" when the challenge does not exist or has expired.
+	GetChallengeUserID(challengeHash string) (string, error)
+	IncrementChallengeFailures(challengeHash string) (int64, error)
+	DeleteChallenge(challengeHash string) error
+	// MarkTOTPCodeUsed returns false if the code was already used by this user within ttl.
+	MarkTOTPCodeUsed(userID, code string, ttl time.Duration) (bool, error)
+}
+
+type redisTwoFAChallengeRepository struct {
+	client *redis.Client
+}
+
+func NewRedisTwoFAChallengeRepository(client *redis.Client) TwoFAChallengeRepository {
+	if client == nil {
+		utils.Log.Fatal("Redis client is nil for RedisTwoFAChallengeRepository.")
+	}
+	return &redisTwoFAChallengeRepository{client: client}
+}
+
+func challengeKey(challengeHash string) string {
+	return fmt.Sprintf("2fa_challenge:%s", challengeHash)
+}
+
+func challengeFailuresKey(challengeHash string) string {
+	return fmt.Sprintf("2fa_challenge_failures:%s", challengeHash)
+}
+
+func (r *redisTwoFAChallengeRepository) CreateChallenge(challengeHash, userID string, ttl time.Duration) error {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	if err := r.client.SetEX(ctx, challengeKey(challengeHash), userID, ttl).Err(); err != nil {
+		utils.Log.Error("Failed to store 2FA challenge in Redis", zap.String("user_id", userID), zap.Error(err))
+		return fmt.Errorf("failed to store 2FA challenge: %w", err)
+	}
+	return nil
+}
+
+func (r *redisTwoFAChallengeRepository) GetChallengeUserID(challengeHash string) (string, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	userID, err := r.client.Get(ctx, challengeKey(challengeHash)).Result()
+	if errors.Is(err, redis.Nil) {
+		return "", nil
+	}
+	if err != nil {
+		return "", fmt.Errorf("failed to get 2FA challenge: %w", err)
+	}
+	return userID, nil
+}
+
+func (r *redisTwoFAChallengeRepository) IncrementChallengeFailures(challengeHash string) (int64, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	key := challengeFailuresKey(challengeHash)
+	failures, err := r.client.Incr(ctx, key).Result()
+	if err != nil {
+		return 0, fmt.Errorf("failed to count 2FA challenge failure: %w", err)
+	}
+	if failures == 1 {
+		// The counter must not outlive the challenge it belongs to.
+		ttl, err := r.client.TTL(ctx, challengeKey(challengeHash)).Result()
+		if err != nil || ttl <= 0 {
+			ttl = time.Minute
+		}
+		r.client.Expire(ctx, key, ttl)
+	}
+	return failures, nil
+}
+
+func (r *redisTwoFAChallengeRepository) DeleteChallenge(challengeHash string) error {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	if err := r.client.Del(ctx, challengeKey(challengeHash), challengeFailuresKey(challengeHash)).Err(); err != nil {
+		return fmt.Errorf("failed to delete 2FA challenge: %w", err)
+	}
+	return nil
+}
+
+func (r *redisTwoFAChallengeRepository) MarkTOTPCodeUsed(userID, code string, ttl time.Duration) (bool, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	key := fmt.Sprintf("totp_used:%s:%s", userID, code)
+	ok, err := r.client.SetNX(ctx, key, "used", ttl).Result()
+	if err != nil {
+		return false, fmt.Errorf("failed to record used TOTP code: %w", err)
+	}
+	return ok, nil
+}
@@ -3,41 +3,97 @@ package account
 import (
 	"errors"
 	"fmt"
+	"profile-gold/internal/model"
 	"profile-gold/internal/repository/db/postgresDb"
+	redisdb "profile-gold/internal/repository/db/redisDb"
+	service "profile-gold/internal/service/common"
+	"profile-gold/internal/service/otp"
+	"profile-gold/internal/service/throttle"
 	"profile-gold/internal/service/twofa"
 	"profile-gold/internal/utils"
+	"strings"
+	"time"
 
 	"go.uber.org/zap"
 )
 
 type AccountService interface {
 	changeUsername(userID string, newUsername string) error
-	changePassword(userID string, oldPassword string, newPassword string) error
+	// ChangePassword checks the current password and the password policy, then signs out every other session of the user.
+	ChangePassword(userID string, currentSessionID string, oldPassword string, newPassword string) error
 	uploadProfilePicture(userID string, pictureData []byte) error
-	generateTwoFASetup(userID string) (string, error)
-	verifyAndEnableTwoFA(userID string, code string) error
-	disableTwoFA(userID string) error
+	GenerateTwoFASetup(userID string) (*model.TwoFASetupResponse, error)
+	VerifyAndEnableTwoFA(userID string, code string) ([]string, error)
+	// DisableTwoFA and RegenerateRecoveryCodes count wrong codes against the same lockout as the 2FA login step.
+	DisableTwoFA(userID string, password string, code string, client model.ClientInfo) error
+	RegenerateRecoveryCodes(userID string, code string, client model.ClientInfo) ([]string, error)
+	RecoveryCodesRemaining(userID string) (int64, error)
+	// RequestMobileVerification texts a code to a new mobile number; the number is saved once VerifyMobile accepts it.
+	RequestMobileVerification(userID string, mobile string, client model.ClientInfo) error
+	VerifyMobile(userID string, code string, client model.ClientInfo) (*model.User, error)
 }
 
 type accountService struct {
 	userRepo     postgresDb.UserRepository
+	twoFARepo    postgresDb.TwoFARepository
 	twoFAService twofa.TwoFAService
+	mobileRepo   postgresDb.MobileRepository
+	otpService   otp.OTPService
+	sessionRepo  postgresDb.SessionRepository
+	tokenRepo    redisdb.TokenRepository
+	throttle     throttle.Throttle
+	usedCodes    redisdb.TwoFAChallengeRepository
 }
 
-func NewAccountService(userRepo postgresDb.UserRepository, twoFAService twofa.TwoFAService) (AccountService, error) {
+func NewAccountService(userRepo postgresDb.UserRepository, twoFARepo postgresDb.TwoFARepository, twoFAService twofa.TwoFAService, mobileRepo postgresDb.MobileRepository, otpService otp.OTPService, sessionRepo postgresDb.SessionRepository, tokenRepo redisdb.TokenRepository, twoFAThrottle throttle.Throttle, usedCodes redisdb.TwoFAChallengeRepository) (AccountService, error) {
 
 	if userRepo == nil {
 		utils.Log.Error("UserRepository cannot be nil for AccountService.")
 		return nil, fmt.Errorf("UserRepository cannot be nil for AccountService.")
 	}
+	if twoFARepo == nil {
+		utils.Log.Error("TwoFARepository cannot be nil for AccountService.")
+		return nil, fmt.Errorf("TwoFARepository cannot be nil for AccountService.")
+	}
 	if twoFAService == nil {
 		utils.Log.Error("TwoFAService cannot be nil for AccountService.")
 		return nil, fmt.Errorf("TwoFAService cannot be nil for AccountService.") 
 	}
+	if mobileRepo == nil {
+		utils.Log.Error("MobileRepository cannot be nil for AccountService.")
+		return nil, fmt.Errorf("MobileRepository cannot be nil for AccountService.")
+	}
+	if otpService == nil {
+		utils.Log.Error("OTPService cannot be nil for AccountService.")
+		return nil, fmt.Errorf("OTPService cannot be nil for AccountService.")
+	}
+	if sessionRepo == nil {
+		utils.Log.Error("SessionRepository cannot be nil for AccountService.")
+		return nil, fmt.Errorf("SessionRepository cannot be nil for AccountService.")
+	}
+	if tokenRepo == nil {
+		utils.Log.Error("TokenRepository cannot be nil for AccountService.")
+		return nil, fmt.Errorf("TokenRepository cannot be nil for AccountService.")
+	}
+	if twoFAThrottle == nil {
+		utils.Log.Error("Throttle cannot be nil for AccountService.")
+		return nil, fmt.Errorf("Throttle cannot be nil for AccountService.")
+	}
+	if usedCodes == nil {
+		utils.Log.Error("TwoFAChallengeRepository cannot be nil for AccountService.")
+		return nil, fmt.Errorf("TwoFAChallengeRepository cannot be nil for AccountService.")
+	}
 	utils.Log.Info("AccountService initialized successfully.")
 	return &accountService{
 		userRepo:     userRepo,
+		twoFARepo:    twoFARepo,
 		twoFAService: twoFAService,
+		mobileRepo:   mobileRepo,
+		otpService:   otpService,
+		sessionRepo:  sessionRepo,
+		tokenRepo:    tokenRepo,
+		throttle:     twoFAThrottle,
+		usedCodes:    usedCodes,
 	}, nil
 }
 
@@ -68,30 +124,46 @@ func (s *accountService) changeUsername(userID string, newUsername string) error
 	return nil
 }
 
-func (s *accountService) changePassword(userID string, oldPassword string, newPassword string) error {
+// ChangePassword keeps the session the change was made from; without one every session of the user is revoked.
+func (s *accountService) ChangePassword(userID string, currentSessionID string, oldPassword string, newPassword string) error {
 	user, err := s.userRepo.GetUserByID(userID)
 	if err != nil {
-		return fmt.Errorf("failed to get user for password change: %w", err)
-	}
-	if user == nil {
-		return errors.New("user not found")
+		return err
 	}
 
 	if err := utils.CheckPasswordHash(oldPassword, user.PasswordHash); err != nil {
-		return errors.New("invalid old password")
+		return service.ErrInvalidCredentials
+	}
+	if err := utils.ValidatePasswordPolicy(newPassword, user.Username, user.Email); err != nil {
+		return fmt.Errorf("%w: %v", service.ErrWeakPassword, err)
 	}
 
 	newHashedPassword, err := utils.HashPassword(newPassword)
 	if err != nil {
-		return fmt.Errorf("failed to hash new password: %w", err)
+		return fmt.Errorf("%w: failed to hash new password", service.ErrInternalService)
 	}
 	user.PasswordHash = newHashedPassword
-	err = s.userRepo.UpdateUser(user)
-	if err != nil {
+	if err := s.userRepo.UpdateUser(user); err != nil {
 		return fmt.Errorf("failed to update password in repository: %w", err)
 	}
 
-	utils.Log.Info("Password changed successfully for user", zap.String("userID", userID))
+	now := time.Now()
+	var revoked []string
+	if currentSessionID != "" {
+		revoked, err = s.sessionRepo.RevokeOtherSessions(userID, currentSessionID, "password_changed", now)
+	} else {
+		revoked, err = s.sessionRepo.RevokeAllSessions(userID, "password_changed", now)
+	}
+	if err != nil {
+		utils.Log.Error("Password was changed but other sessions could not be revoked", zap.String("userID", userID), zap.Error(err))
+		return fmt.Errorf("%w: failed to revoke other sessions", service.ErrInternalService)
+	}
+	if err := s.tokenRepo.RevokeSessions(revoked, utils.TokenLifetime); err != nil {
+		utils.Log.Error("Password was changed but session tokens could not be revoked", zap.String("userID", userID), zap.Error(err))
+		return fmt.Errorf("%w: failed to revoke other sessions", service.ErrInternalService)
+	}
+
+	utils.Log.Info("Password changed successfully for user", zap.String("userID", userID), zap.Int("revoked_sessions", len(revoked)))
 	return nil
 }
 
@@ -116,70 +188,212 @@ func (s *accountService) uploadProfilePicture(userID string, pictureData []byte)
 	return nil
 }
 
-// generateTwoFASetup یک secret برای 2FA تولید می‌کند و آن را برمی‌گرداند.
-func (s *accountService) generateTwoFASetup(userID string) (string, error) {
-	// 1. یک secret جدید برای 2FA تولید کنید.
-	secret, err := s.twoFAService.GenerateSecret()
+// GenerateTwoFASetup یک secret جدید می‌سازد و آن را فقط به عنوان secret در انتظار ذخیره می‌کند؛
+// تا وقتی کاربر با یک کد معتبر تأیید نکند 2FA فعال نمی‌شود.
+func (s *accountService) GenerateTwoFASetup(userID string) (*model.TwoFASetupResponse, error) {
+	user, err := s.userRepo.GetUserByID(userID)
 	if err != nil {
-		return "", fmt.Errorf("failed to generate 2FA secret: %w", err)
+		return nil, err
+	}
+	if user.TwoFAEnabled {
+		return nil, service.ErrTwoFAAlreadyEnabled
 	}
 
-	// 2. secret را در دیتابیس برای کاربر ذخیره کنید (به عنوان 'pending' یا 'disabled').
-	// این secret هنوز فعال نیست تا زمانی که کاربر آن را تأیید کند.
-	// (فرض می‌کنیم userRepo متدی برای ذخیره secret 2FA کاربر دارد)
-	err = s.userRepo.SetUser2FASecret(userID, secret) // این متد باید در UserRepo باشد
+	setup, err := s.twoFAService.GenerateSetup(user.Username)
 	if err != nil {
-		return "", fmt.Errorf("failed to save 2FA secret for user: %w", err)
+		return nil, fmt.Errorf("%w: %v", service.ErrInternalService, err)
+	}
+	if err := s.twoFARepo.SetPendingTwoFASecret(userID, setup.Secret); err != nil {
+		return nil, fmt.Errorf("failed to save pending 2FA secret for user: %w", err)
 	}
 
-	utils.Log.Info("2FA setup generated for user ID: %s", zap.String("userID", userID))
-	return secret, nil
+	utils.Log.Info("2FA setup generated for user", zap.String("userID", userID))
+	return setup, nil
 }
 
-// verifyAndEnableTwoFA کد 2FA را تأیید و 2FA را برای کاربر فعال می‌کند.
-func (s *accountService) verifyAndEnableTwoFA(userID string, code string) error {
-	// 1. secret 2FA کاربر را از دیتابیس دریافت کنید.
-	secret, err := s.userRepo.GetUser2FASecret(userID) // این متد باید در UserRepo باشد
+// VerifyAndEnableTwoFA کد را با secret در انتظار بررسی می‌کند و در صورت صحت 2FA را فعال و کدهای بازیابی را صادر می‌کند.
+func (s *accountService) VerifyAndEnableTwoFA(userID string, code string) ([]string, error) {
+	user, err := s.userRepo.GetUserByID(userID)
 	if err != nil {
-		return fmt.Errorf("failed to get 2FA secret for user: %w", err)
+		return nil, err
+	}
+	if user.TwoFAEnabled {
+		return nil, service.ErrTwoFAAlreadyEnabled
+	}
+	if user.TwoFAPendingSecret == "" {
+		return nil, service.ErrTwoFASetupNotStarted
 	}
-	if secret == "" {
-		return errors.New("2FA not set up for this user")
+	if !s.twoFAService.VerifyTOTPCode(user.TwoFAPendingSecret, code) {
+		return nil, service.ErrInvalidTwoFACode
 	}
 
-	// 2. کد 2FA را با secret تأیید کنید.
-	isValid := s.twoFAService.VerifyTOTPCode(secret, code)
-	if !isValid {
-		return errors.New("invalid 2FA code")
+	codes, hashes, err := twofa.NewRecoveryCodes(twofa.RecoveryCodeCount)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", service.ErrInternalService, err)
+	}
+	if err := s.twoFARepo.ActivateTwoFA(userID, user.TwoFAPendingSecret, hashes); err != nil {
+		return nil, err
 	}
 
-	// 3. 2FA را برای کاربر فعال کنید (فیلد enabled در دیتابیس).
-	// (فرض می‌کنیم UserRepo متدی برای فعال/غیرفعال کردن 2FA دارد)
-	err = s.userRepo.SetUser2FAStatus(userID, true) // این متد باید در UserRepo باشد
+	utils.Log.Info("2FA successfully enabled for user", zap.String("userID", userID))
+	return codes, nil
+}
+
+// DisableTwoFA با رمز عبور فعلی و یک کد 2FA معتبر، 2FA و کدهای بازیابی را حذف می‌کند.
+func (s *accountService) DisableTwoFA(userID string, password string, code string, client model.ClientInfo) error {
+	user, err := s.userRepo.GetUserByID(userID)
 	if err != nil {
-		return fmt.Errorf("failed to enable 2FA for user: %w", err)
+		return err
+	}
+	if !user.TwoFAEnabled {
+		return service.ErrTwoFANotEnabled
+	}
+	if err := utils.CheckPasswordHash(password, user.PasswordHash); err != nil {
+		return service.ErrInvalidCredentials
+	}
+	if err := s.verifySecondFactor(user, code, true, client); err != nil {
+		return err
 	}
 
-	utils.Log.Info("2FA successfully enabled for user ID: %s", zap.String("userID", userID))
+	if err := s.twoFARepo.DisableTwoFA(userID); err != nil {
+		return err
+	}
+	utils.Log.Info("2FA successfully disabled for user", zap.String("userID", userID))
 	return nil
 }
 
-// disableTwoFA 2FA را برای کاربر غیرفعال می‌کند.
-func (s *accountService) disableTwoFA(userID string) error {
-	// 1. 2FA را برای کاربر غیرفعال کنید.
-	// (فرض می‌کنیم UserRepo متدی برای فعال/غیرفعال کردن 2FA دارد)
-	err := s.userRepo.SetUser2FAStatus(userID, false) // این متد باید در UserRepo باشد
+// RegenerateRecoveryCodes کدهای قبلی را باطل و مجموعه جدیدی صادر می‌کند.
+func (s *accountService) RegenerateRecoveryCodes(userID string, code string, client model.ClientInfo) ([]string, error) {
+	user, err := s.userRepo.GetUserByID(userID)
 	if err != nil {
-		return fmt.Errorf("failed to disable 2FA for user: %w", err)
+		return nil, err
+	}
+	if !user.TwoFAEnabled {
+		return nil, service.ErrTwoFANotEnabled
+	}
+	if err := s.verifySecondFactor(user, code, false, client); err != nil {
+		return nil, err
 	}
 
-	// 2. secret 2FA را از دیتابیس حذف کنید (اختیاری، اما توصیه می‌شود).
-	err = s.userRepo.RemoveUser2FASecret(userID) // این متد باید در UserRepo باشد
+	codes, hashes, err := twofa.NewRecoveryCodes(twofa.RecoveryCodeCount)
 	if err != nil {
-		// این یک خطای کشنده نیست، فقط لاگ کنید
-		utils.Log.Warn("Failed to remove 2FA secret for user", zap.String("userID", userID), zap.Error(err))
+		return nil, fmt.Errorf("%w: %v", service.ErrInternalService, err)
 	}
+	if err := s.twoFARepo.ReplaceRecoveryCodes(userID, hashes); err != nil {
+		return nil, err
+	}
+	utils.Log.Info("Recovery codes regenerated for user", zap.String("userID", userID))
+	return codes, nil
+}
 
-	utils.Log.Info("2FA successfully disabled for user ID: %s", zap.String("userID", userID))
+func (s *accountService) RecoveryCodesRemaining(userID string) (int64, error) {
+	user, err := s.userRepo.GetUserByID(userID)
+	if err != nil {
+		return 0, err
+	}
+	if !user.TwoFAEnabled {
+		return 0, service.ErrTwoFANotEnabled
+	}
+	return s.twoFARepo.CountUnusedRecoveryCodes(userID)
+}
+
+// verifySecondFactor یک کد TOTP (و اگر allowRecovery باشد یک کد بازیابی) را بررسی می‌کند. کد اشتباه در همان شمارنده‌های
+// قفل مرحله دوم ورود ثبت می‌شود و هر کد TOTP فقط یک بار پذیرفته می‌شود.
+func (s *accountService) verifySecondFactor(user *model.User, code string, allowRecovery bool, client model.ClientInfo) error {
+	subjects := twofa.ThrottleSubjects(user.ID, client.IPAddress)
+	if err := s.throttle.Check(subjects...); err != nil {
+		utils.Log.Warn("2FA check refused while locked out", zap.String("user_id", user.ID), zap.Error(err))
+		return err
+	}
+
+	if allowRecovery && twofa.IsRecoveryCodeFormat(code) {
+		if err := s.twoFARepo.UseRecoveryCode(user.ID, twofa.HashRecoveryCode(code), time.Now()); err != nil {
+			if errors.Is(err, service.ErrInvalidTwoFACode) {
+				return s.recordTwoFAFailure(user, subjects)
+			}
+			return fmt.Errorf("%w: failed to check recovery code", service.ErrInternalService)
+		}
+		s.clearTwoFAFailures(user, subjects)
+		return nil
+	}
+
+	if !s.twoFAService.VerifyTOTPCode(user.TwoFASecret, code) {
+		return s.recordTwoFAFailure(user, subjects)
+	}
+	fresh, err := s.usedCodes.MarkTOTPCodeUsed(user.ID, code, twofa.UsedCodeTTL)
+	if err != nil {
+		utils.Log.Error("Failed to record used TOTP code", zap.String("user_id", user.ID), zap.Error(err))
+		return fmt.Errorf("%w: failed to record used 2FA code", service.ErrInternalService)
+	}
+	if !fresh {
+		utils.Log.Warn("Replayed TOTP code rejected", zap.String("user_id", user.ID))
+		return s.recordTwoFAFailure(user, subjects)
+	}
+	s.clearTwoFAFailures(user, subjects)
 	return nil
 }
+
+// recordTwoFAFailure returns a *service.LockoutError when the wrong code starts a lockout, otherwise ErrInvalidTwoFACode.
+func (s *accountService) recordTwoFAFailure(user *model.User, subjects []throttle.Subject) error {
+	lockouts, err := s.throttle.Fail(subjects...)
+	if err != nil {
+		utils.Log.Error("Failed to count 2FA failure", zap.String("user_id", user.ID), zap.Error(err))
+		return service.ErrInvalidTwoFACode
+	}
+	var longest time.Duration
+	for _, lockout := range lockouts {
+		utils.Log.Warn("Locked out after too many wrong 2FA codes", zap.String("subject", lockout.Subject.Key), zap.Duration("lockout", lockout.Duration))
+		longest = max(longest, lockout.Duration)
+	}
+	if longest > 0 {
+		return &service.LockoutError{RetryAfter: longest}
+	}
+	utils.Log.Warn("Invalid 2FA code", zap.String("user_id", user.ID))
+	return service.ErrInvalidTwoFACode
+}
+
+func (s *accountService) clearTwoFAFailures(user *model.User, subjects []throttle.Subject) {
+	if err := s.throttle.Release(subjects...); err != nil {
+		utils.Log.Error("Failed to release 2FA attempt slots", zap.String("user_id", user.ID), zap.Error(err))
+	}
+	if err := s.throttle.Clear(twofa.UserThrottleKey(user.ID)); err != nil {
+		utils.Log.Error("Failed to clear 2FA failures", zap.String("user_id", user.ID), zap.Error(err))
+	}
+}
+
+func (s *accountService) RequestMobileVerification(userID string, mobile string, client model.ClientInfo) error {
+	mobile, err := utils.NormalizeMobile(mobile)
+	if err != nil {
+		return fmt.Errorf("%w: %v", service.ErrInvalidMobile, err)
+	}
+	if _, err := s.userRepo.GetUserByID(userID); err != nil {
+		return err
+	}
+	owner, err := s.mobileRepo.GetUserByMobile(mobile)
+	if err == nil && owner.ID != userID {
+		return service.ErrMobileInUse
+	}
+	if err != nil && !errors.Is(err, service.ErrUserNotFound) {
+		return fmt.Errorf("%w: failed to check mobile owner: %v", service.ErrInternalService, err)
+	}
+
+	return s.otpService.Issue(otp.Request{
+		Purpose: otp.PurposeVerifyMobile,
+		Subject: userID,
+		Mobile:  mobile,
+		Payload: mobile,
+		Client:  client,
+	})
+}
+
+func (s *accountService) VerifyMobile(userID string, code string, client model.ClientInfo) (*model.User, error) {
+	mobile, err := s.otpService.Verify(otp.PurposeVerifyMobile, userID, strings.TrimSpace(code), client)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.mobileRepo.SetVerifiedMobile(userID, mobile, time.Now()); err != nil {
+		return nil, err
+	}
+	utils.Log.Info("Mobile number verified for user", zap.String("userID", userID))
+	return s.userRepo.GetUserByID(userID)
+}
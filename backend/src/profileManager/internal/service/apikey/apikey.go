@@ -0,0 +1,267 @@
+package apikey
+
+import (
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	authzSvc "profile-gold/internal/api/authz"
+	"profile-gold/internal/model"
+	"profile-gold/internal/repository/db/postgresDb"
+	redisdb "profile-gold/internal/repository/db/redisDb"
+	service "profile-gold/internal/service/common"
+	"profile-gold/internal/utils"
+
+	"go.uber.org/zap"
+)
+
+// KeyPrefix starts every key. It makes keys easy to spot in configuration files and lets the API Gateway tell
+// a key from a JWT in the Authorization header.
+const KeyPrefix = "gk_"
+
+const (
+	maxNameLength = 64
+	// displayPrefixLength characters of the key are kept in clear so users can tell their keys apart.
+	displayPrefixLength = len(KeyPrefix) + 8
+)
+
+// APIKeyService issues and revokes API keys and checks them for the API Gateway.
+type APIKeyService interface {
+	// CreateAPIKey returns the full key, which is not stored and cannot be retrieved later. Every permission must
+	// exist and be held by the owner at creation time; service.ErrPermissionNotHeld names the first that is not.
+	CreateAPIKey(req model.CreateAPIKeyRequest) (string, *model.APIKey, error)
+	// ListAPIKeys returns only the keys of userID.
+	ListAPIKeys(userID string) ([]model.APIKey, error)
+	// RevokeAPIKey takes effect at once: the gateway checks the revocation marker on every request. A key of
+	// another user is reported as service.ErrAPIKeyNotFound.
+	RevokeAPIKey(userID, keyID string) (*model.APIKey, error)
+	// VerifyAPIKey returns service.ErrInvalidAPIKey for unknown, expired and revoked keys, and for keys whose owner
+	// no longer exists. It records the use and issues an access token of the owner for the gateway to forward.
+	VerifyAPIKey(key string, client model.ClientInfo) (*model.APIKeyPrincipal, error)
+}
+
+type apiKeyService struct {
+	apiKeyRepo        postgresDb.APIKeyRepository
+	userRepo          postgresDb.UserRepository
+	rbacRepo          postgresDb.RBACRepository
+	tokenRepo         redisdb.TokenRepository
+	permissionService authzSvc.PermissionService
+}
+
+func NewAPIKeyService(apiKeyRepo postgresDb.APIKeyRepository, userRepo postgresDb.UserRepository, rbacRepo postgresDb.RBACRepository, tokenRepo redisdb.TokenRepository, permService authzSvc.PermissionService) (APIKeyService, error) {
+	if apiKeyRepo == nil {
+		utils.Log.Error("APIKeyRepository cannot be nil for APIKeyService.")
+		return nil, fmt.Errorf("APIKeyRepository cannot be nil for APIKeyService")
+	}
+	if userRepo == nil {
+		utils.Log.Error("UserRepository cannot be nil for APIKeyService.")
+		return nil, fmt.Errorf("UserRepository cannot be nil for APIKeyService")
+	}
+	if rbacRepo == nil {
+		utils.Log.Error("RBACRepository cannot be nil for APIKeyService.")
+		return nil, fmt.Errorf("RBACRepository cannot be nil for APIKeyService")
+	}
+	if tokenRepo == nil {
+		utils.Log.Error("TokenRepository cannot be nil for APIKeyService.")
+		return nil, fmt.Errorf("TokenRepository cannot be nil for APIKeyService")
+	}
+	if permService == nil {
+		utils.Log.Error("PermissionService cannot be nil for APIKeyService.")
+		return nil, fmt.Errorf("PermissionService cannot be nil for APIKeyService")
+	}
+	utils.Log.Info("APIKeyService initialized successfully.")
+	return &apiKeyService{apiKeyRepo: apiKeyRepo, userRepo: userRepo, rbacRepo: rbacRepo, tokenRepo: tokenRepo, permissionService: permService}, nil
+}
+
+func (s *apiKeyService) CreateAPIKey(req model.CreateAPIKeyRequest) (string, *model.APIKey, error) {
+	name := strings.TrimSpace(req.Name)
+	if name == "" || len([]rune(name)) > maxNameLength {
+		return "", nil, fmt.Errorf("%w: name is required and must be at most %d characters", service.ErrInvalidAPIKeyRequest, maxNameLength)
+	}
+	if req.ExpiresAt != nil && !req.ExpiresAt.After(time.Now()) {
+		return "", nil, fmt.Errorf("%w: expiry must be in the future", service.ErrInvalidAPIKeyRequest)
+	}
+	if req.BusinessID != nil && *req.BusinessID == 0 {
+		return "", nil, fmt.Errorf("%w: business_id must be a valid business", service.ErrInvalidAPIKeyRequest)
+	}
+
+	owner, roles, err := s.owner(req.UserID)
+	if err != nil {
+		return "", nil, err
+	}
+	permissions, err := s.checkPermissions(owner.ID, roles, req.Permissions)
+	if err != nil {
+		return "", nil, err
+	}
+
+	secret, err := newSecret()
+	if err != nil {
+		utils.Log.Error("Failed to generate API key", zap.Error(err))
+		return "", nil, fmt.Errorf("%w: failed to generate API key", service.ErrInternalService)
+	}
+	key := &model.APIKey{
+		Name:        name,
+		Prefix:      secret[:displayPrefixLength],
+		KeyHash:     hashKey(secret),
+		UserID:      owner.ID,
+		Permissions: permissions,
+		BusinessID:  req.BusinessID,
+		ExpiresAt:   req.ExpiresAt,
+	}
+	if err := s.apiKeyRepo.CreateAPIKey(key); err != nil {
+		utils.Log.Error("Failed to store API key", zap.String("user_id", owner.ID), zap.Error(err))
+		return "", nil, fmt.Errorf("%w: failed to store API key", service.ErrInternalService)
+	}
+	utils.Log.Info("API key created", zap.String("api_key_id", key.ID), zap.String("user_id", owner.ID), zap.Strings("permissions", permissions))
+	return secret, key, nil
+}
+
+// checkPermissions drops duplicates and refuses permissions that do not exist or that the owner does not hold,
+// so a key can never do more than the user who created it.
+func (s *apiKeyService) checkPermissions(ownerID string, roles, requested []string) ([]string, error) {
+	if len(requested) == 0 {
+		return nil, fmt.Errorf("%w: at least one permission is required", service.ErrInvalidAPIKeyRequest)
+	}
+	catalog, err := s.rbacRepo.ListPermissions()
+	if err != nil {
+		utils.Log.Error("Failed to list permissions for API key", zap.Error(err))
+		return nil, fmt.Errorf("%w: failed to list permissions", service.ErrInternalService)
+	}
+	known := make(map[string]bool, len(catalog))
+	for _, perm := range catalog {
+		known[perm.Name] = true
+	}
+
+	seen := make(map[string]bool, len(requested))
+	var permissions []string
+	for _, perm := range requested {
+		perm = strings.TrimSpace(perm)
+		if seen[perm] {
+			continue
+		}
+		seen[perm] = true
+		if !known[perm] {
+			return nil, fmt.Errorf("%w: %s", service.ErrUnknownPermission, perm)
+		}
+		allowed, err := s.permissionService.HasPermission(ownerID, roles, perm)
+		if err != nil {
+			utils.Log.Error("Failed to check owner permission for API key", zap.String("user_id", ownerID), zap.Error(err))
+			return nil, fmt.Errorf("%w: failed to check permissions", service.ErrInternalService)
+		}
+		if !allowed {
+			return nil, fmt.Errorf("%w: %s", service.ErrPermissionNotHeld, perm)
+		}
+		permissions = append(permissions, perm)
+	}
+	return permissions, nil
+}
+
+func (s *apiKeyService) ListAPIKeys(userID string) ([]model.APIKey, error) {
+	keys, err := s.apiKeyRepo.ListAPIKeys(userID)
+	if err != nil {
+		utils.Log.Error("Failed to list API keys", zap.String("user_id", userID), zap.Error(err))
+		return nil, fmt.Errorf("%w: failed to list API keys", service.ErrInternalService)
+	}
+	return keys, nil
+}
+
+func (s *apiKeyService) RevokeAPIKey(userID, keyID string) (*model.APIKey, error) {
+	key, err := s.apiKeyRepo.RevokeAPIKey(userID, keyID, time.Now())
+	if err != nil {
+		if errors.Is(err, service.ErrAPIKeyNotFound) {
+			return nil, err
+		}
+		utils.Log.Error("Failed to revoke API key", zap.String("user_id", userID), zap.String("api_key_id", keyID), zap.Error(err))
+		return nil, fmt.Errorf("%w: failed to revoke API key", service.ErrInternalService)
+	}
+	// The marker has to outlive both the gateway's cached verification and the owner token issued with it.
+	if err := s.tokenRepo.RevokeAPIKey(keyID, utils.TokenLifetime); err != nil {
+		return nil, fmt.Errorf("%w: failed to revoke API key tokens", service.ErrInternalService)
+	}
+	return key, nil
+}
+
+func (s *apiKeyService) VerifyAPIKey(secret string, client model.ClientInfo) (*model.APIKeyPrincipal, error) {
+	if !strings.HasPrefix(secret, KeyPrefix) {
+		return nil, service.ErrInvalidAPIKey
+	}
+	key, err := s.apiKeyRepo.GetAPIKeyByHash(hashKey(secret))
+	if err != nil {
+		if errors.Is(err, service.ErrInvalidAPIKey) {
+			return nil, err
+		}
+		utils.Log.Error("Failed to look up API key", zap.Error(err))
+		return nil, fmt.Errorf("%w: failed to look up API key", service.ErrInternalService)
+	}
+	now := time.Now()
+	if key.RevokedAt != nil || (key.ExpiresAt != nil && !key.ExpiresAt.After(now)) {
+		utils.Log.Warn("Revoked or expired API key presented", zap.String("api_key_id", key.ID), zap.String("ip", client.IPAddress))
+		return nil, service.ErrInvalidAPIKey
+	}
+
+	owner, roles, err := s.owner(key.UserID)
+	if err != nil {
+		if errors.Is(err, service.ErrUserNotFound) {
+			utils.Log.Warn("API key presented for a deleted user", zap.String("api_key_id", key.ID), zap.String("user_id", key.UserID))
+			return nil, service.ErrInvalidAPIKey
+		}
+		return nil, err
+	}
+	token, claims, err := utils.GenerateAPIKeyToken(owner, key.ID, key.BusinessID)
+	if err != nil {
+		return nil, fmt.Errorf("%w: failed to issue API key token", service.ErrInternalService)
+	}
+	if err := s.apiKeyRepo.TouchAPIKey(key.ID, client.IPAddress, now); err != nil {
+		utils.Log.Error("Failed to record API key use", zap.String("api_key_id", key.ID), zap.Error(err))
+	}
+
+	return &model.APIKeyPrincipal{
+		KeyID:       key.ID,
+		Name:        key.Name,
+		UserID:      owner.ID,
+		Username:    owner.Username,
+		Roles:       roles,
+		Permissions: key.Permissions,
+		BusinessID:  key.BusinessID,
+		ExpiresAt:   key.ExpiresAt,
+		Token:       token,
+		TokenExp:    claims.ExpiresAt.Unix(),
+	}, nil
+}
+
+func (s *apiKeyService) owner(userID string) (*model.User, []string, error) {
+	user, err := s.userRepo.GetUserByID(userID)
+	if err != nil {
+		if errors.Is(err, service.ErrUserNotFound) {
+			return nil, nil, err
+		}
+		utils.Log.Error("Failed to get API key owner", zap.String("user_id", userID), zap.Error(err))
+		return nil, nil, fmt.Errorf("%w: failed to get user", service.ErrInternalService)
+	}
+	var roles []string
+	if err := json.Unmarshal(user.Roles, &roles); err != nil {
+		utils.Log.Error("Failed to unmarshal user roles", zap.String("user_id", userID), zap.Error(err))
+		return nil, nil, fmt.Errorf("%w: failed to read user roles", service.ErrInternalService)
+	}
+	return user, roles, nil
+}
+
+// newSecret returns KeyPrefix followed by 32 random bytes, URL-safe encoded.
+func newSecret() (string, error) {
+	b := make([]byte, 32)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	return KeyPrefix + base64.RawURLEncoding.EncodeToString(b), nil
+}
+
+func hashKey(secret string) string {
+	sum := sha256.Sum256([]byte(secret))
+	return hex.EncodeToString(sum[:])
+}
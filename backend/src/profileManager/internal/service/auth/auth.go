@@ -1,13 +1,23 @@
 package auth
 
 import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
 	"errors"
 	"fmt"
 	"profile-gold/internal/model"
 	"profile-gold/internal/repository/db/postgresDb"
 	redisdb "profile-gold/internal/repository/db/redisDb"
 	service "profile-gold/internal/service/common"
+	"profile-gold/internal/service/notification"
+	"profile-gold/internal/service/otp"
+	"profile-gold/internal/service/throttle"
+	"profile-gold/internal/service/twofa"
 	"profile-gold/internal/utils"
+	"strings"
 	"time"
 
 	"github.com/golang-jwt/jwt/v5"
@@ -16,24 +26,65 @@ import (
 )
 type AuthService interface {
 	RegisterUser(req model.RegisterRequest) error
-	AuthenticateUser(username, password string) (*model.User, string, *model.CustomClaims, error)
+	AuthenticateUser(username, password string, client model.ClientInfo) (*model.LoginResult, error)
 	LogoutUser(tokenString string) error
-	RequestPasswordReset(email string) error
+	RequestPasswordReset(email string, client model.ClientInfo) error
 	ResetPassword(token, newPassword string) error
-	VerifyTwoFA(username, code string) (model.User, model.CustomClaims, error)
+	VerifyTwoFA(challengeToken, code string, client model.ClientInfo) (*model.LoginResult, error)
+	RefreshSession(refreshToken string, client model.ClientInfo) (*model.LoginResult, error)
+	// RequestLoginOTP sends a login code by SMS when the number is a user's verified mobile.
+	RequestLoginOTP(mobile string, client model.ClientInfo) error
+	// VerifyLoginOTP logs in with an SMS code; like a password it leads to a 2FA challenge when 2FA is enabled.
+	VerifyLoginOTP(mobile, code string, client model.ClientInfo) (*model.LoginResult, error)
+	// UnlockUser lifts the login, 2FA and password-reset lockouts of a user before they expire.
+	UnlockUser(userID string) error
 }
 type EmailService interface {
-	SendPasswordResetEmail(toEmail, resetToken string) error
+	SendPasswordResetEmail(toEmail, resetToken string, ttl time.Duration) error
 }
 
+const (
+	// passwordResetTokenTTL is how long an emailed reset link stays valid.
+	passwordResetTokenTTL = 30 * time.Minute
+	// twoFAChallengeTTL is how long the user has to enter the TOTP code after a correct password.
+	twoFAChallengeTTL = 5 * time.Minute
+	// maxTwoFAAttempts wrong codes after which the challenge is dropped and the password must be entered again.
+	maxTwoFAAttempts = 5
+	// refreshTokenTTL is how long a session may stay idle before the user has to log in again.
+	refreshTokenTTL = 14 * 24 * time.Hour
+	// sessionMaxLifetime bounds a session no matter how often it is refreshed.
+	sessionMaxLifetime = 30 * 24 * time.Hour
+	// lockoutNotificationType is the notificationManager type of the message sent to a locked-out user.
+	lockoutNotificationType = "ACCOUNT_LOCKED"
+)
+
+// Brute-force limits. Per-user limits apply to the submitted username or email whether or not it exists, so a
+// lockout does not reveal which accounts exist. Per-IP limits are looser because an office shares one address.
+var (
+	loginUserLimit = throttle.Limit{MaxFailures: 5, BaseLockout: time.Minute, MaxLockout: time.Hour, Window: 24 * time.Hour}
+	loginIPLimit   = throttle.Limit{MaxFailures: 30, BaseLockout: time.Minute, MaxLockout: time.Hour, Window: time.Hour}
+	// Every reset request counts, not only failed ones, since each one sends an email.
+	resetEmailLimit = throttle.Limit{MaxFailures: 3, BaseLockout: 15 * time.Minute, MaxLockout: 6 * time.Hour, Window: time.Hour}
+	resetIPLimit    = throttle.Limit{MaxFailures: 10, BaseLockout: 15 * time.Minute, MaxLockout: 6 * time.Hour, Window: time.Hour}
+)
+
 type UserService struct {
 	userRepo     postgresDb.UserRepository
 	tokenRepo    redisdb.TokenRepository
-	jwtValidator utils.JWTValidator
-	emailService EmailService
+	resetRepo     postgresDb.PasswordResetRepository
+	challengeRepo redisdb.TwoFAChallengeRepository
+	twoFARepo     postgresDb.TwoFARepository
+	sessionRepo   postgresDb.SessionRepository
+	mobileRepo    postgresDb.MobileRepository
+	otpService    otp.OTPService
+	twoFAService  twofa.TwoFAService
+	jwtValidator  utils.JWTValidator
+	emailService  EmailService
+	throttle      throttle.Throttle
+	notifier      notification.Notifier
 }
 
-func NewAuthService(r postgresDb.UserRepository, t redisdb.TokenRepository, j utils.JWTValidator) AuthService {
+func NewAuthService(r postgresDb.UserRepository, t redisdb.TokenRepository, j utils.JWTValidator, rr postgresDb.PasswordResetRepository, e EmailService, cr redisdb.TwoFAChallengeRepository, tf twofa.TwoFAService, tr postgresDb.TwoFARepository, sr postgresDb.SessionRepository, th throttle.Throttle, n notification.Notifier, mr postgresDb.MobileRepository, o otp.OTPService) AuthService {
 	if r == nil {
 		utils.Log.Fatal("UserRepository cannot be nil for UserService.")
 	}
@@ -43,11 +94,44 @@ func NewAuthService(r postgresDb.UserRepository, t redisdb.TokenRepository, j ut
 	if j == nil {
 		utils.Log.Fatal("JWTValidator cannot be nil for UserService.")
 	}
+	if rr == nil {
+		utils.Log.Fatal("PasswordResetRepository cannot be nil for UserService.")
+	}
+	if e == nil {
+		utils.Log.Fatal("EmailService cannot be nil for UserService.")
+	}
+	if cr == nil {
+		utils.Log.Fatal("TwoFAChallengeRepository cannot be nil for UserService.")
+	}
+	if tf == nil {
+		utils.Log.Fatal("TwoFAService cannot be nil for UserService.")
+	}
+	if tr == nil {
+		utils.Log.Fatal("TwoFARepository cannot be nil for UserService.")
+	}
+	if sr == nil {
+		utils.Log.Fatal("SessionRepository cannot be nil for UserService.")
+	}
+	if th == nil {
+		utils.Log.Fatal("Throttle cannot be nil for UserService.")
+	}
+	if n == nil {
+		utils.Log.Fatal("Notifier cannot be nil for UserService.")
+	}
+	if mr == nil {
+		utils.Log.Fatal("MobileRepository cannot be nil for UserService.")
+	}
+	if o == nil {
+		utils.Log.Fatal("OTPService cannot be nil for UserService.")
+	}
 	utils.Log.Info("UserService initialized successfully with UserRepo and tokenRepo.")
-	return &UserService{userRepo: r, tokenRepo: t, jwtValidator: j}
+	return &UserService{userRepo: r, tokenRepo: t, resetRepo: rr, challengeRepo: cr, twoFARepo: tr, sessionRepo: sr, mobileRepo: mr, otpService: o, twoFAService: tf, jwtValidator: j, emailService: e, throttle: th, notifier: n}
 }
 
 func (s *UserService) RegisterUser(req model.RegisterRequest) error {
+	if err := utils.ValidatePasswordPolicy(req.Password, req.Username, req.Email); err != nil {
+		return fmt.Errorf("%w: %v", service.ErrWeakPassword, err)
+	}
 
 	_, err := s.userRepo.GetUserByUsername(req.Username)
 	if err == nil {
@@ -82,43 +166,152 @@ func (s *UserService) RegisterUser(req model.RegisterRequest) error {
 	return nil
 }
 
-func (s *UserService) AuthenticateUser(username, password string) (*model.User, string, *model.CustomClaims, error) {
+// AuthenticateUser checks the password after making sure neither the username nor the client IP is locked out.
+// A locked account is refused even with the right password.
+func (s *UserService) AuthenticateUser(username, password string, client model.ClientInfo) (*model.LoginResult, error) {
+	userKey := loginUserKey(username)
+	subjects := throttle.WithIP([]throttle.Subject{{Key: userKey, Limit: loginUserLimit}}, "login", loginIPLimit, client.IPAddress)
+	if err := s.throttle.Check(subjects...); err != nil {
+		utils.Log.Warn("UserService: Login refused while locked out", zap.String("username", username), zap.String("ip", client.IPAddress), zap.Error(err))
+		return nil, err
+	}
+
 	user, err := s.userRepo.GetUserByUsername(username)
 
 	if err != nil {
 		if errors.Is(err, service.ErrUserNotFound) {
-			utils.Log.Warn("UserService: Authentication failed - User not found in repo", zap.String("username", username))
-			return nil, "", nil, service.ErrInvalidCredentials
+			return nil, s.recordFailure(subjects, userKey, nil, service.ErrInvalidCredentials)
 		}
 
 		utils.Log.Error("UserService: Failed to get user by username from repo (DB error)", zap.String("username", username), zap.Error(err))
-		return nil, "", nil, fmt.Errorf("%w: failed to retrieve user from repository", service.ErrInternalService)
+		return nil, fmt.Errorf("%w: failed to retrieve user from repository", service.ErrInternalService)
 	}
 
 	if user == nil {
 		utils.Log.Fatal("UserService: GetUserByUsername returned a nil user object without an error. This is an unexpected state from the repository.", zap.String("username", username))
-		return nil, "", nil, fmt.Errorf("%w: unexpected nil user object from repository", service.ErrInternalService)
+		return nil, fmt.Errorf("%w: unexpected nil user object from repository", service.ErrInternalService)
 	}
 
 	err = utils.CheckPasswordHash(password, user.PasswordHash)
 	if err != nil {
 		if errors.Is(err, bcrypt.ErrMismatchedHashAndPassword) {
-			return nil, "", nil, service.ErrInvalidCredentials
+			return nil, s.recordFailure(subjects, userKey, user, service.ErrInvalidCredentials)
 		}
 		utils.Log.Error("Password hash comparison failed", zap.String("username", username), zap.Error(err))
-		return nil, "", nil, fmt.Errorf("%w: failed to compare password hash", service.ErrInternalService)
+		return nil, fmt.Errorf("%w: failed to compare password hash", service.ErrInternalService)
+	}
+	s.releaseAttempt(subjects)
+	s.clearFailures(userKey)
+
+	if user.TwoFAEnabled {
+		challenge, err := s.startTwoFAChallenge(user)
+		if err != nil {
+			return nil, err
+		}
+		utils.Log.Info("Password accepted, waiting for 2FA code", zap.String("username", user.Username))
+		return &model.LoginResult{User: user, Challenge: challenge}, nil
+	}
+
+	result, err := s.issueSession(user, client)
+	if err != nil {
+		return nil, err
+	}
+	utils.Log.Info("User authenticated successfully in service", zap.String("username", user.Username), zap.String("role", string(user.Roles)))
+	return result, nil
+}
+
+// issueSession opens a new session for the device and returns its first access and refresh tokens.
+func (s *UserService) issueSession(user *model.User, client model.ClientInfo) (*model.LoginResult, error) {
+	refreshToken, err := newRandomToken()
+	if err != nil {
+		utils.Log.Error("Failed to generate refresh token", zap.Error(err))
+		return nil, fmt.Errorf("%w: failed to generate refresh token", service.ErrInternalService)
+	}
+	now := time.Now()
+	deviceName := client.DeviceName
+	if deviceName == "" {
+		deviceName = utils.DeviceNameFromUserAgent(client.UserAgent)
+	}
+	session := &model.Session{
+		UserID:     user.ID,
+		DeviceName: deviceName,
+		IPAddress:  client.IPAddress,
+		UserAgent:  client.UserAgent,
+		LastUsedAt: now,
+		ExpiresAt:  now.Add(sessionMaxLifetime),
+	}
+	refresh := &model.RefreshToken{TokenHash: hashToken(refreshToken), ExpiresAt: now.Add(refreshTokenTTL)}
+	if err := s.sessionRepo.CreateSession(session, refresh); err != nil {
+		utils.Log.Error("Failed to store session", zap.String("user_id", user.ID), zap.Error(err))
+		return nil, fmt.Errorf("%w: failed to create session", service.ErrInternalService)
 	}
 
-	token, claims, err := utils.GenerateJWTToken(user)
+	token, claims, err := utils.GenerateJWTToken(user, session.ID)
 	if err != nil {
 		utils.Log.Error("Failed to generate JWT token in service", zap.String("username", user.Username), zap.Error(err))
-		return nil, "", nil, fmt.Errorf("%w: failed to generate token", service.ErrInternalService)
+		return nil, fmt.Errorf("%w: failed to generate token", service.ErrInternalService)
 	}
+	return &model.LoginResult{User: user, Token: token, Claims: claims, RefreshToken: refreshToken, RefreshExpiresAt: refresh.ExpiresAt}, nil
+}
 
-	utils.Log.Info("User authenticated successfully in service", zap.String("username", user.Username), zap.String("role", string(user.Roles)))
-	return user, token, claims, nil
+// RefreshSession exchanges a refresh token for a new access token and a new refresh token.
+// Presenting an already exchanged refresh token revokes the whole session.
+func (s *UserService) RefreshSession(refreshToken string, client model.ClientInfo) (*model.LoginResult, error) {
+	if refreshToken == "" {
+		return nil, service.ErrInvalidToken
+	}
+	nextToken, err := newRandomToken()
+	if err != nil {
+		utils.Log.Error("Failed to generate refresh token", zap.Error(err))
+		return nil, fmt.Errorf("%w: failed to generate refresh token", service.ErrInternalService)
+	}
+	now := time.Now()
+	next := &model.RefreshToken{TokenHash: hashToken(nextToken), ExpiresAt: now.Add(refreshTokenTTL)}
+
+	session, err := s.sessionRepo.RotateRefreshToken(hashToken(refreshToken), next, client, now)
+	if err != nil {
+		if errors.Is(err, service.ErrRefreshTokenReused) {
+			if err := s.tokenRepo.RevokeSessions([]string{session.ID}, utils.TokenLifetime); err != nil {
+				utils.Log.Error("Failed to mark reused session as revoked", zap.String("session_id", session.ID), zap.Error(err))
+			}
+			return nil, service.ErrRefreshTokenReused
+		}
+		if errors.Is(err, service.ErrInvalidToken) {
+			return nil, err
+		}
+		utils.Log.Error("Failed to rotate refresh token", zap.Error(err))
+		return nil, fmt.Errorf("%w: failed to rotate refresh token", service.ErrInternalService)
+	}
+
+	user, err := s.userRepo.GetUserByID(session.UserID)
+	if err != nil {
+		if errors.Is(err, service.ErrUserNotFound) {
+			return nil, service.ErrInvalidToken
+		}
+		return nil, fmt.Errorf("%w: failed to load user for token refresh", service.ErrInternalService)
+	}
+	token, claims, err := utils.GenerateJWTToken(user, session.ID)
+	if err != nil {
+		utils.Log.Error("Failed to generate JWT token in service", zap.String("username", user.Username), zap.Error(err))
+		return nil, fmt.Errorf("%w: failed to generate token", service.ErrInternalService)
+	}
+	utils.Log.Info("Session refreshed", zap.String("user_id", user.ID), zap.String("session_id", session.ID))
+	return &model.LoginResult{User: user, Token: token, Claims: claims, RefreshToken: nextToken, RefreshExpiresAt: next.ExpiresAt}, nil
+}
+
+func (s *UserService) startTwoFAChallenge(user *model.User) (*model.TwoFAChallenge, error) {
+	token, err := newRandomToken()
+	if err != nil {
+		utils.Log.Error("Failed to generate 2FA challenge token", zap.Error(err))
+		return nil, fmt.Errorf("%w: failed to generate 2FA challenge", service.ErrInternalService)
+	}
+	if err := s.challengeRepo.CreateChallenge(hashToken(token), user.ID, twoFAChallengeTTL); err != nil {
+		return nil, fmt.Errorf("%w: failed to store 2FA challenge", service.ErrInternalService)
+	}
+	return &model.TwoFAChallenge{Token: token, ExpiresAt: time.Now().Add(twoFAChallengeTTL)}, nil
 }
 
+
 func (s *UserService) LogoutUser(tokenString string) error {
 	utils.Log.Info("UserService: Attempting to logout user by blacklisting token.", zap.String("token_prefix", tokenString[:min(len(tokenString), 10)]))
 
@@ -157,27 +350,382 @@ func (s *UserService) LogoutUser(tokenString string) error {
 		return fmt.Errorf("%w: failed to blacklist token", service.ErrInternalService)
 	}
 
+	if claims.SessionID != "" {
+		err := s.sessionRepo.RevokeSession(claims.UserID, claims.SessionID, "logout", time.Now())
+		if err != nil && !errors.Is(err, service.ErrSessionNotFound) {
+			utils.Log.Error("UserService: Failed to revoke session on logout", zap.String("session_id", claims.SessionID), zap.Error(err))
+			return fmt.Errorf("%w: failed to revoke session", service.ErrInternalService)
+		}
+		if err := s.tokenRepo.RevokeSessions([]string{claims.SessionID}, utils.TokenLifetime); err != nil {
+			return fmt.Errorf("%w: failed to revoke session", service.ErrInternalService)
+		}
+	}
+
 	utils.Log.Info("UserService: Token successfully blacklisted.",
 		zap.String("username", claims.Username),
 		zap.Duration("ttl", ttl))
 	return nil
 }
 
+// ResetPassword consumes a single-use reset token, sets the new password and revokes every session issued before the reset.
 func (s *UserService) ResetPassword(token, newPassword string) error {
-	utils.Log.Info("ResetPassword called", zap.String("token_prefix", token[:min(len(token), 10)]))
-	// TODO: Implement reset password logic here.
-	return errors.New("ResetPassword not implemented")
+	if token == "" {
+		return service.ErrInvalidToken
+	}
+	tokenHash := hashToken(token)
+	now := time.Now()
+
+	pending, err := s.resetRepo.GetActiveResetToken(tokenHash, now)
+	if err != nil {
+		if errors.Is(err, service.ErrInvalidToken) {
+			utils.Log.Warn("Password reset attempted with an invalid or expired token")
+			return err
+		}
+		utils.Log.Error("Failed to look up password reset token", zap.Error(err))
+		return fmt.Errorf("%w: failed to look up reset token", service.ErrInternalService)
+	}
+	user, err := s.userRepo.GetUserByID(pending.UserID)
+	if err != nil {
+		if errors.Is(err, service.ErrUserNotFound) {
+			return service.ErrInvalidToken
+		}
+		return fmt.Errorf("%w: failed to load user for password reset", service.ErrInternalService)
+	}
+	if err := utils.ValidatePasswordPolicy(newPassword, user.Username, user.Email); err != nil {
+		return fmt.Errorf("%w: %v", service.ErrWeakPassword, err)
+	}
+
+	hashedPassword, err := utils.HashPassword(newPassword)
+	if err != nil {
+		utils.Log.Error("Failed to hash password for reset", zap.String("user_id", user.ID), zap.Error(err))
+		return fmt.Errorf("%w: failed to hash password", service.ErrInternalService)
+	}
+	if _, err := s.resetRepo.ResetPassword(tokenHash, hashedPassword, now); err != nil {
+		if errors.Is(err, service.ErrInvalidToken) {
+			return err
+		}
+		utils.Log.Error("Failed to reset password in repository", zap.String("user_id", user.ID), zap.Error(err))
+		return fmt.Errorf("%w: failed to reset password", service.ErrInternalService)
+	}
+
+	if err := s.tokenRepo.RevokeUserTokens(user.ID, now, utils.TokenLifetime); err != nil {
+		utils.Log.Error("Password was reset but existing sessions could not be revoked", zap.String("user_id", user.ID), zap.Error(err))
+		return fmt.Errorf("%w: failed to revoke existing sessions", service.ErrInternalService)
+	}
+	if _, err := s.sessionRepo.RevokeAllSessions(user.ID, "password_reset", now); err != nil {
+		utils.Log.Error("Password was reset but refresh tokens could not be revoked", zap.String("user_id", user.ID), zap.Error(err))
+		return fmt.Errorf("%w: failed to revoke existing sessions", service.ErrInternalService)
+	}
+
+	utils.Log.Info("Password reset successfully", zap.String("user_id", user.ID))
+	return nil
+}
+
+// RequestPasswordReset emails a reset link when the address belongs to a user. It returns nil for unknown
+// addresses and sends the email in the background, so neither the response nor its timing reveals whether the email exists.
+// Requests are counted per email and per client IP; past the limit a *service.LockoutError is returned for known and
+// unknown addresses alike.
+func (s *UserService) RequestPasswordReset(email string, client model.ClientInfo) error {
+	email = strings.TrimSpace(email)
+	subjects := throttle.WithIP([]throttle.Subject{{Key: resetEmailKey(email), Limit: resetEmailLimit}}, "reset", resetIPLimit, client.IPAddress)
+	if err := s.throttle.Check(subjects...); err != nil {
+		utils.Log.Warn("Password reset refused while locked out", zap.String("ip", client.IPAddress), zap.Error(err))
+		return err
+	}
+	if lockouts, err := s.throttle.Fail(subjects...); err != nil {
+		utils.Log.Error("Failed to count password reset request", zap.Error(err))
+	} else if len(lockouts) > 0 {
+		utils.Log.Warn("Password reset requests locked", zap.String("ip", client.IPAddress), zap.Duration("lockout", lockouts[0].Duration))
+	}
+
+	user, err := s.userRepo.GetUserByEmail(email)
+	if err != nil {
+		if errors.Is(err, service.ErrUserNotFound) {
+			utils.Log.Info("Password reset requested for an unknown email")
+			return nil
+		}
+		utils.Log.Error("Failed to look up user for password reset", zap.Error(err))
+		return fmt.Errorf("%w: failed to look up user", service.ErrInternalService)
+	}
+
+	token, err := newRandomToken()
+	if err != nil {
+		utils.Log.Error("Failed to generate password reset token", zap.Error(err))
+		return fmt.Errorf("%w: failed to generate reset token", service.ErrInternalService)
+	}
+	record := &model.PasswordResetToken{
+		UserID:    user.ID,
+		TokenHash: hashToken(token),
+		ExpiresAt: time.Now().Add(passwordResetTokenTTL),
+	}
+	if err := s.resetRepo.CreateResetToken(record); err != nil {
+		utils.Log.Error("Failed to store password reset token", zap.String("user_id", user.ID), zap.Error(err))
+		return fmt.Errorf("%w: failed to store reset token", service.ErrInternalService)
+	}
+
+	go func(to string) {
+		if err := s.emailService.SendPasswordResetEmail(to, token, passwordResetTokenTTL); err != nil {
+			utils.Log.Error("Failed to deliver password reset email", zap.String("user_id", user.ID), zap.Error(err))
+		}
+	}(user.Email)
+
+	utils.Log.Info("Password reset token issued", zap.String("user_id", user.ID))
+	return nil
+}
+
+// RequestLoginOTP answers the same way whether or not the number belongs to a user: limits apply to every number,
+// and the SMS is sent in the background, so neither the response nor its timing reveals which numbers are registered.
+func (s *UserService) RequestLoginOTP(mobile string, client model.ClientInfo) error {
+	mobile, err := utils.NormalizeMobile(mobile)
+	if err != nil {
+		return fmt.Errorf("%w: %v", service.ErrInvalidMobile, err)
+	}
+
+	req := otp.Request{Purpose: otp.PurposeLogin, Subject: mobile, Background: true, Client: client}
+	user, err := s.mobileRepo.GetUserByMobile(mobile)
+	switch {
+	case err == nil:
+		req.Mobile = mobile
+		req.Payload = user.ID
+	case errors.Is(err, service.ErrUserNotFound):
+		utils.Log.Info("SMS login requested for an unknown mobile")
+	default:
+		utils.Log.Error("Failed to look up user by mobile", zap.Error(err))
+		return fmt.Errorf("%w: failed to look up user", service.ErrInternalService)
+	}
+	return s.otpService.Issue(req)
+}
+
+func (s *UserService) VerifyLoginOTP(mobile, code string, client model.ClientInfo) (*model.LoginResult, error) {
+	mobile, err := utils.NormalizeMobile(mobile)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", service.ErrInvalidMobile, err)
+	}
+	userID, err := s.otpService.Verify(otp.PurposeLogin, mobile, strings.TrimSpace(code), client)
+	if err != nil {
+		return nil, err
+	}
+	if userID == "" {
+		// The code was issued for a number that belongs to no user and was never sent.
+		return nil, service.ErrInvalidOTP
+	}
+
+	user, err := s.userRepo.GetUserByID(userID)
+	if err != nil {
+		if errors.Is(err, service.ErrUserNotFound) {
+			return nil, service.ErrInvalidOTP
+		}
+		return nil, fmt.Errorf("%w: failed to load user for SMS login", service.ErrInternalService)
+	}
+	if user.Mobile == nil || *user.Mobile != mobile {
+		// The number was moved to another account after the code was sent.
+		return nil, service.ErrInvalidOTP
+	}
+
+	if user.TwoFAEnabled {
+		challenge, err := s.startTwoFAChallenge(user)
+		if err != nil {
+			return nil, err
+		}
+		utils.Log.Info("SMS code accepted, waiting for 2FA code", zap.String("username", user.Username))
+		return &model.LoginResult{User: user, Challenge: challenge}, nil
+	}
+
+	result, err := s.issueSession(user, client)
+	if err != nil {
+		return nil, err
+	}
+	utils.Log.Info("User authenticated successfully with an SMS code", zap.String("username", user.Username))
+	return result, nil
+}
+
+// newRandomToken returns 32 random bytes, URL-safe encoded. Only its hash is persisted.
+func newRandomToken() (string, error) {
+	b := make([]byte, 32)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	return base64.RawURLEncoding.EncodeToString(b), nil
+}
+
+func hashToken(token string) string {
+	sum := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(sum[:])
+}
+
+// VerifyTwoFA exchanges the challenge token issued at login plus a TOTP code (or an unused recovery code) for a session.
+// Each code is accepted once, and the challenge is dropped after maxTwoFAAttempts wrong codes.
+func (s *UserService) VerifyTwoFA(challengeToken, code string, client model.ClientInfo) (*model.LoginResult, error) {
+	if challengeToken == "" {
+		return nil, service.ErrInvalidToken
+	}
+	challengeHash := hashToken(challengeToken)
+
+	userID, err := s.challengeRepo.GetChallengeUserID(challengeHash)
+	if err != nil {
+		utils.Log.Error("Failed to look up 2FA challenge", zap.Error(err))
+		return nil, fmt.Errorf("%w: failed to look up 2FA challenge", service.ErrInternalService)
+	}
+	if userID == "" {
+		return nil, service.ErrInvalidToken
+	}
+	subjects := twofa.ThrottleSubjects(userID, client.IPAddress)
+	if err := s.throttle.Check(subjects...); err != nil {
+		utils.Log.Warn("2FA verification refused while locked out", zap.String("user_id", userID), zap.Error(err))
+		return nil, err
+	}
+
+	user, err := s.userRepo.GetUserByID(userID)
+	if err != nil {
+		if errors.Is(err, service.ErrUserNotFound) {
+			s.dropChallenge(challengeHash)
+			return nil, service.ErrInvalidToken
+		}
+		return nil, fmt.Errorf("%w: failed to load user for 2FA", service.ErrInternalService)
+	}
+	if !user.TwoFAEnabled || user.TwoFASecret == "" {
+		s.dropChallenge(challengeHash)
+		return nil, service.ErrInvalidToken
+	}
+
+	if twofa.IsRecoveryCodeFormat(code) {
+		if err := s.twoFARepo.UseRecoveryCode(user.ID, twofa.HashRecoveryCode(code), time.Now()); err != nil {
+			if errors.Is(err, service.ErrInvalidTwoFACode) {
+				return nil, s.recordTwoFAFailure(challengeHash, user, subjects)
+			}
+			return nil, fmt.Errorf("%w: failed to check recovery code", service.ErrInternalService)
+		}
+		return s.completeTwoFALogin(challengeHash, user, subjects, client)
+	}
+
+	if !s.twoFAService.VerifyTOTPCode(user.TwoFASecret, code) {
+		return nil, s.recordTwoFAFailure(challengeHash, user, subjects)
+	}
+	fresh, err := s.challengeRepo.MarkTOTPCodeUsed(user.ID, code, twofa.UsedCodeTTL)
+	if err != nil {
+		utils.Log.Error("Failed to record used TOTP code", zap.String("user_id", user.ID), zap.Error(err))
+		return nil, fmt.Errorf("%w: failed to record used 2FA code", service.ErrInternalService)
+	}
+	if !fresh {
+		utils.Log.Warn("Replayed TOTP code rejected", zap.String("user_id", user.ID))
+		return nil, s.recordTwoFAFailure(challengeHash, user, subjects)
+	}
+	return s.completeTwoFALogin(challengeHash, user, subjects, client)
+}
+
+func (s *UserService) completeTwoFALogin(challengeHash string, user *model.User, subjects []throttle.Subject, client model.ClientInfo) (*model.LoginResult, error) {
+	s.dropChallenge(challengeHash)
+	s.releaseAttempt(subjects)
+	s.clearFailures(twofa.UserThrottleKey(user.ID))
+	result, err := s.issueSession(user, client)
+	if err != nil {
+		return nil, err
+	}
+	utils.Log.Info("User authenticated successfully with 2FA", zap.String("username", user.Username))
+	return result, nil
+}
+
+// recordTwoFAFailure counts the wrong code against the challenge and against the user's and IP's lockout counters.
+func (s *UserService) recordTwoFAFailure(challengeHash string, user *model.User, subjects []throttle.Subject) error {
+	if err := s.recordFailure(subjects, twofa.UserThrottleKey(user.ID), user, nil); err != nil {
+		s.dropChallenge(challengeHash)
+		return err
+	}
+	failures, err := s.challengeRepo.IncrementChallengeFailures(challengeHash)
+	if err != nil {
+		utils.Log.Error("Failed to count 2FA failure", zap.String("user_id", user.ID), zap.Error(err))
+		return fmt.Errorf("%w: failed to count 2FA failure", service.ErrInternalService)
+	}
+	if failures >= maxTwoFAAttempts {
+		utils.Log.Warn("2FA challenge dropped after too many wrong codes", zap.String("user_id", user.ID))
+		s.dropChallenge(challengeHash)
+		return service.ErrTooManyAttempts
+	}
+	utils.Log.Warn("Invalid 2FA code", zap.String("user_id", user.ID), zap.Int64("failures", failures))
+	return service.ErrInvalidTwoFACode
+}
+
+func (s *UserService) dropChallenge(challengeHash string) {
+	if err := s.challengeRepo.DeleteChallenge(challengeHash); err != nil {
+		utils.Log.Error("Failed to delete 2FA challenge", zap.Error(err))
+	}
+}
+
+// UnlockUser clears every per-user counter of the account; per-IP counters are left alone.
+func (s *UserService) UnlockUser(userID string) error {
+	user, err := s.userRepo.GetUserByID(userID)
+	if err != nil {
+		if errors.Is(err, service.ErrUserNotFound) {
+			return err
+		}
+		return fmt.Errorf("%w: failed to load user to unlock", service.ErrInternalService)
+	}
+	if err := s.throttle.Clear(loginUserKey(user.Username), twofa.UserThrottleKey(user.ID), resetEmailKey(user.Email)); err != nil {
+		utils.Log.Error("Failed to unlock user", zap.String("user_id", user.ID), zap.Error(err))
+		return err
+	}
+	if user.Mobile != nil {
+		if err := s.otpService.ClearLimits(otp.PurposeLogin, *user.Mobile); err != nil {
+			utils.Log.Error("Failed to unlock user's SMS login", zap.String("user_id", user.ID), zap.Error(err))
+			return err
+		}
+	}
+	utils.Log.Info("User lockouts cleared", zap.String("user_id", user.ID))
+	return nil
+}
+
+// recordFailure counts a failed attempt. If it starts a lockout a *service.LockoutError is returned and, when the
+// per-user subject userKey of an existing user was locked, the user is notified. Otherwise fallback is returned.
+func (s *UserService) recordFailure(subjects []throttle.Subject, userKey string, user *model.User, fallback error) error {
+	lockouts, err := s.throttle.Fail(subjects...)
+	if err != nil {
+		utils.Log.Error("Failed to count failed attempt", zap.String("subject", userKey), zap.Error(err))
+		return fallback
+	}
+	if len(lockouts) == 0 {
+		return fallback
+	}
+	var longest time.Duration
+	for _, lockout := range lockouts {
+		utils.Log.Warn("Locked out after too many failed attempts", zap.String("subject", lockout.Subject.Key), zap.Duration("lockout", lockout.Duration))
+		longest = max(longest, lockout.Duration)
+		if lockout.Subject.Key == userKey && user != nil {
+			s.notifyLockout(user, lockout.Duration)
+		}
+	}
+	return &service.LockoutError{RetryAfter: longest}
+}
+
+// releaseAttempt gives back the attempt slots taken by Check once the attempt has succeeded.
+func (s *UserService) releaseAttempt(subjects []throttle.Subject) {
+	if err := s.throttle.Release(subjects...); err != nil {
+		utils.Log.Error("Failed to release attempt slots", zap.Error(err))
+	}
+}
+
+func (s *UserService) clearFailures(userKey string) {
+	if err := s.throttle.Clear(userKey); err != nil {
+		utils.Log.Error("Failed to clear failed attempts", zap.String("subject", userKey), zap.Error(err))
+	}
+}
+
+// notifyLockout is best effort and runs in the background so the response time does not depend on notificationManager.
+func (s *UserService) notifyLockout(user *model.User, duration time.Duration) {
+	message := fmt.Sprintf("به دلیل تلاش‌های ناموفق متعدد، ورود به حساب %s به مدت %d دقیقه مسدود شد. اگر این تلاش‌ها از طرف شما نبوده، رمز عبور خود را تغییر دهید.",
+		user.Username, int(duration.Round(time.Minute)/time.Minute))
+	go func() {
+		ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+		defer cancel()
+		if err := s.notifier.SendToUser(ctx, user.ID, lockoutNotificationType, message); err != nil {
+			utils.Log.Error("Failed to send lockout notification", zap.String("user_id", user.ID), zap.Error(err))
+		}
+	}()
 }
 
-// RequestPasswordReset implements the AuthService interface.
-func (s *UserService) RequestPasswordReset(email string) error {
-	utils.Log.Info("RequestPasswordReset called", zap.String("email", email))
-	// TODO: Implement password reset request logic here.
-	return errors.New("RequestPasswordReset not implemented")
+func loginUserKey(username string) string {
+	return "login:user:" + strings.ToLower(strings.TrimSpace(username))
 }
 
-func (s *UserService) VerifyTwoFA(username, code string) (model.User, model.CustomClaims, error) {
-	utils.Log.Info("VerifyTwoFA called", zap.String("username", username))
-	// TODO: Implement 2FA verification logic here.
-	return model.User{}, model.CustomClaims{}, errors.New("VerifyTwoFA not implemented")
+func resetEmailKey(email string) string {
+	return "reset:email:" + strings.ToLower(strings.TrimSpace(email))
 }
@@ -1,73 +1,69 @@
 package email
 
 import (
-    "fmt"
-    "net/smtp"
-    "os"
-    "profile-gold/internal/utils" 
-    "go.uber.org/zap"
+	"fmt"
+	"net/url"
+	"os"
+	"time"
+
+	"profile-gold/internal/utils"
+
+	"go.uber.org/zap"
 )
 
+const defaultResetURL = "http://localhost:5173/reset-password"
+
 type EmailServiceImpl struct {
-    smtpHost string
-    smtpPort string
-    smtpUser string
-    smtpPass string
-    senderEmail string
-    frontendResetURL string 
+	mailer           Mailer
+	frontendResetURL string
 }
 
-func NewEmailService() (*EmailServiceImpl, error) {
-    smtpHost := os.Getenv("SMTP_HOST")
-    smtpPort := os.Getenv("SMTP_PORT")
-    smtpUser := os.Getenv("SMTP_USER")
-    smtpPass := os.Getenv("SMTP_PASS")
-    senderEmail := os.Getenv("SENDER_EMAIL")
-    frontendResetURL := os.Getenv("http://localhost:5173/request-password-reset") 
+// NewEmailService reads the reset page address from FRONTEND_RESET_PASSWORD_URL.
+func NewEmailService(mailer Mailer) (*EmailServiceImpl, error) {
+	if mailer == nil {
+		return nil, fmt.Errorf("mailer cannot be nil for EmailService")
+	}
+	frontendResetURL := os.Getenv("FRONTEND_RESET_PASSWORD_URL")
+	if frontendResetURL == "" {
+		utils.Log.Warn("FRONTEND_RESET_PASSWORD_URL is not set, using default.", zap.String("url", defaultResetURL))
+		frontendResetURL = defaultResetURL
+	}
+	if _, err := url.Parse(frontendResetURL); err != nil {
+		return nil, fmt.Errorf("invalid FRONTEND_RESET_PASSWORD_URL: %w", err)
+	}
 
-    if smtpHost == "" || smtpPort == "" || smtpUser == "" || smtpPass == "" || senderEmail == "" || frontendResetURL == "" {
-        utils.Log.Fatal("Email service environment variables are not fully set (SMTP_HOST, SMTP_PORT, SMTP_USER, SMTP_PASS, SENDER_EMAIL, FRONTEND_RESET_PASSWORD_URL).")
-    }
-
-    return &EmailServiceImpl{
-        smtpHost: smtpHost,
-        smtpPort: smtpPort,
-        smtpUser: smtpUser,
-        smtpPass: smtpPass,
-        senderEmail: senderEmail,
-        frontendResetURL: frontendResetURL,
-    }, nil
+	return &EmailServiceImpl{
+		mailer:           mailer,
+		frontendResetURL: frontendResetURL,
+	}, nil
 }
 
-func (s *EmailServiceImpl) SendPasswordResetEmail(toEmail, resetToken string) error {
-    resetLink := fmt.Sprintf("%s?token=%s", s.frontendResetURL, resetToken)
-    
-    subject := "Subject: درخواست بازنشانی رمز عبور\r\n"
-    body := fmt.Sprintf(`
-    سلام،
-
-    شما درخواست بازنشانی رمز عبور را ارسال کرده‌اید.
-    برای بازنشانی رمز عبور خود، لطفاً روی لینک زیر کلیک کنید:
+func (s *EmailServiceImpl) SendPasswordResetEmail(toEmail, resetToken string, ttl time.Duration) error {
+	link, _ := url.Parse(s.frontendResetURL)
+	query := link.Query()
+	query.Set("token", resetToken)
+	link.RawQuery = query.Encode()
 
-    %s
+	subject := "درخواست بازنشانی رمز عبور"
+	body := fmt.Sprintf(`سلام،
 
-    اگر شما این درخواست را ارسال نکرده‌اید، لطفاً این ایمیل را نادیده بگیرید.
+شما درخواست بازنشانی رمز عبور را ارسال کرده‌اید.
+برای بازنشانی رمز عبور خود، لطفاً روی لینک زیر کلیک کنید:
 
-    با احترام،
-    تیم پشتیبانی
-    `, resetLink)
+%s
 
-    msg := []byte(subject + "\r\n" + body)
+این لینک تنها یک بار و تا %d دقیقه معتبر است.
+اگر شما این درخواست را ارسال نکرده‌اید، لطفاً این ایمیل را نادیده بگیرید.
 
-    auth := smtp.PlainAuth("", s.smtpUser, s.smtpPass, s.smtpHost)
-    addr := fmt.Sprintf("%s:%s", s.smtpHost, s.smtpPort)
+با احترام،
+تیم پشتیبانی
+`, link.String(), int(ttl.Minutes()))
 
-    err := smtp.SendMail(addr, auth, s.senderEmail, []string{toEmail}, msg)
-    if err != nil {
-        utils.Log.Error("Failed to send password reset email", zap.Error(err), zap.String("to_email", toEmail))
-        return fmt.Errorf("failed to send email: %w", err)
-    }
+	if err := s.mailer.Send(toEmail, subject, body); err != nil {
+		utils.Log.Error("Failed to send password reset email", zap.Error(err), zap.String("to_email", toEmail))
+		return fmt.Errorf("failed to send email: %w", err)
+	}
 
-    utils.Log.Info("Password reset email sent successfully", zap.String("to_email", toEmail))
-    return nil
-}
\ No newline at end of file
+	utils.Log.Info("Password reset email sent successfully", zap.String("to_email", toEmail))
+	return nil
+}
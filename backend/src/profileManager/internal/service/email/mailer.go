@@ -0,0 +1,91 @@
+package email
+
+import (
+	"fmt"
+	"mime"
+	"net/smtp"
+	"os"
+	"strings"
+
+	"profile-gold/internal/utils"
+
+	"go.uber.org/zap"
+)
+
+// Mailer delivers a single plain-text message. The reset flow only depends on this interface,
+// so SMTP can be swapped for another provider or for LogMailer in development.
+type Mailer interface {
+	Send(to, subject, body string) error
+}
+
+type SMTPMailer struct {
+	host string
+	port string
+	user string
+	pass string
+	from string
+}
+
+func NewSMTPMailer(host, port, user, pass, from string) (*SMTPMailer, error) {
+	if host == "" || port == "" || from == "" {
+		return nil, fmt.Errorf("SMTP host, port and sender address are required")
+	}
+	return &SMTPMailer{host: host, port: port, user: user, pass: pass, from: from}, nil
+}
+
+func (m *SMTPMailer) Send(to, subject, body string) error {
+	headers := []string{
+		"From: " + m.from,
+		"To: " + to,
+		"Subject: " + mime.QEncoding.Encode("UTF-8", subject),
+		"MIME-Version: 1.0",
+		"Content-Type: text/plain; charset=UTF-8",
+	}
+	msg := []byte(strings.Join(headers, "\r\n") + "\r\n\r\n" + body)
+
+	var auth smtp.Auth
+	if m.user != "" {
+		auth = smtp.PlainAuth("", m.user, m.pass, m.host)
+	}
+	addr := fmt.Sprintf("%s:%s", m.host, m.port)
+	if err := smtp.SendMail(addr, auth, m.from, []string{to}, msg); err != nil {
+		return fmt.Errorf("failed to send email via SMTP: %w", err)
+	}
+	return nil
+}
+
+// LogMailer writes messages to the log instead of sending them. Only meant for local development.
+type LogMailer struct{}
+
+func (LogMailer) Send(to, subject, body string) error {
+	utils.Log.Warn("LogMailer: email not sent, logging instead", zap.String("to", to), zap.String("subject", subject), zap.String("body", body))
+	return nil
+}
+
+// NewMailerFromEnv picks the mailer from MAILER_DRIVER ("smtp" or "log"); without it, SMTP is used when SMTP_HOST is set.
+// With neither, startup fails rather than falling back to logging. "log" writes whole messages, password-reset links
+// included, to the log, so it is accepted only when MAILER_ALLOW_LOG=true is also set for local development.
+func NewMailerFromEnv() (Mailer, error) {
+	driver := strings.ToLower(strings.TrimSpace(os.Getenv("MAILER_DRIVER")))
+	if driver == "" && os.Getenv("SMTP_HOST") != "" {
+		driver = "smtp"
+	}
+	switch driver {
+	case "":
+		return nil, fmt.Errorf("MAILER_DRIVER or SMTP_HOST is required")
+	case "smtp":
+		mailer, err := NewSMTPMailer(os.Getenv("SMTP_HOST"), os.Getenv("SMTP_PORT"), os.Getenv("SMTP_USER"), os.Getenv("SMTP_PASS"), os.Getenv("SENDER_EMAIL"))
+		if err != nil {
+			return nil, err
+		}
+		return mailer, nil
+	case "log":
+		if os.Getenv("MAILER_ALLOW_LOG") != "true" {
+			return nil, fmt.Errorf("MAILER_DRIVER=log writes reset links to the log; set MAILER_ALLOW_LOG=true to use it in development")
+		}
+		utils.Log.Warn("Using LogMailer: emails will be logged, not delivered. NOT FOR PRODUCTION USE.")
+		return LogMailer{}, nil
+	default:
+		return nil, fmt.Errorf("unknown MAILER_DRIVER %q", driver)
+	}
+}
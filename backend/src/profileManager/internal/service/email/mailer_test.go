@@ -0,0 +1,49 @@
+package email
+
+import (
+	"testing"
+
+	"profile-gold/internal/utils"
+
+	"go.uber.org/zap"
+)
+
+func TestNewMailerFromEnv(t *testing.T) {
+	utils.Log = zap.NewNop()
+	tests := []struct {
+		name     string
+		driver   string
+		allowLog string
+		smtpHost string
+		wantLog  bool
+		wantSMTP bool
+		wantErr  bool
+	}{
+		{name: "nothing configured", wantErr: true},
+		{name: "log without opt-in", driver: "log", wantErr: true},
+		{name: "log with opt-in", driver: "log", allowLog: "true", wantLog: true},
+		{name: "smtp from host", smtpHost: "mail.example.com", wantSMTP: true},
+		{name: "smtp driver without host", driver: "smtp", wantErr: true},
+		{name: "unknown driver", driver: "pigeon", wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Setenv("MAILER_DRIVER", tt.driver)
+			t.Setenv("MAILER_ALLOW_LOG", tt.allowLog)
+			t.Setenv("SMTP_HOST", tt.smtpHost)
+			t.Setenv("SMTP_PORT", "587")
+			t.Setenv("SENDER_EMAIL", "no-reply@example.com")
+
+			mailer, err := NewMailerFromEnv()
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if _, isLog := mailer.(LogMailer); isLog != tt.wantLog {
+				t.Errorf("mailer = %T, want log %v", mailer, tt.wantLog)
+			}
+			if _, isSMTP := mailer.(*SMTPMailer); isSMTP != tt.wantSMTP {
+				t.Errorf("mailer = %T, want SMTP %v", mailer, tt.wantSMTP)
+			}
+		})
+	}
+}
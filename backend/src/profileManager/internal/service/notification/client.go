@@ -0,0 +1,73 @@
+package notification
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"time"
+)
+
+// Notifier sends in-app notifications to users through notificationManager.
+type Notifier interface {
+	SendToUser(ctx context.Context, userID, notificationType, message string) error
+}
+
+type notificationHTTPClient struct {
+	baseURL string
+	client  *http.Client
+}
+
+// createNotificationRequest addresses the user by user_ref because Profile Manager user IDs are UUIDs.
+type createNotificationRequest struct {
+	Message       string `json:"message"`
+	Type          string `json:"type"`
+	RecipientType string `json:"recipient_type"`
+	UserRef       string `json:"user_ref"`
+}
+
+func NewClient(baseURL string) (Notifier, error) {
+	if baseURL == "" {
+		return nil, fmt.Errorf("notification manager base URL cannot be empty")
+	}
+	return &notificationHTTPClient{
+		baseURL: baseURL,
+		client:  &http.Client{Timeout: 10 * time.Second},
+	}, nil
+}
+
+func (c *notificationHTTPClient) SendToUser(ctx context.Context, userID, notificationType, message string) error {
+	if userID == "" {
+		return errors.New("notification recipient is required")
+	}
+	body, err := json.Marshal(createNotificationRequest{
+		Message:       message,
+		Type:          notificationType,
+		RecipientType: "USER",
+		UserRef:       userID,
+	})
+	if err != nil {
+		return fmt.Errorf("failed to marshal notification request: %w", err)
+	}
+
+	httpReq, err := http.NewRequestWithContext(ctx, http.MethodPost, c.baseURL+"/api/notifications", bytes.NewBuffer(body))
+	if err != nil {
+		return fmt.Errorf("failed to create notification request: %w", err)
+	}
+	httpReq.Header.Set("Content-Type", "application/json")
+
+	resp, err := c.client.Do(httpReq)
+	if err != nil {
+		return fmt.Errorf("failed to send notification to notification manager at %s: %w", c.baseURL, err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusCreated && resp.StatusCode != http.StatusOK {
+		respBody, _ := io.ReadAll(resp.Body)
+		return fmt.Errorf("notification manager returned status %d: %s", resp.StatusCode, string(respBody))
+	}
+	return nil
+}
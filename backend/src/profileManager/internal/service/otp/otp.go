@@ -0,0 +1,270 @@
+package otp
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/hex"
+	"fmt"
+	"math/big"
+	"time"
+
+	"profile-gold/internal/model"
+	redisdb "profile-gold/internal/repository/db/redisDb"
+	service "profile-gold/internal/service/common"
+	"profile-gold/internal/service/sms"
+	"profile-gold/internal/service/throttle"
+	"profile-gold/internal/utils"
+
+	"go.uber.org/zap"
+)
+
+// Purpose separates codes issued for different flows, so a login code cannot confirm a mobile number and vice versa.
+type Purpose string
+
+const (
+	PurposeLogin        Purpose = "login"
+	PurposeVerifyMobile Purpose = "verify_mobile"
+)
+
+const (
+	// CodeTTL is how long an SMS code can be entered.
+	CodeTTL = 2 * time.Minute
+	// resendCooldown is the minimum time between two codes for the same subject.
+	resendCooldown = time.Minute
+	// maxCodeAttempts wrong codes after which the code is dropped and a new one has to be requested.
+	maxCodeAttempts = 5
+	codeDigits      = 6
+	// deliveryTimeout bounds a single call to the SMS provider; it stays below the API Gateway's 10s client timeout.
+	deliveryTimeout = 8 * time.Second
+)
+
+// Every issued code counts against these limits, since each one costs an SMS. A per-number limit keeps one phone
+// from being flooded through many accounts; per-IP limits are looser because an office shares one address.
+var (
+	sendSubjectLimit   = throttle.Limit{MaxFailures: 5, BaseLockout: 30 * time.Minute, MaxLockout: 6 * time.Hour, Window: time.Hour}
+	sendMobileLimit    = throttle.Limit{MaxFailures: 5, BaseLockout: 30 * time.Minute, MaxLockout: 6 * time.Hour, Window: time.Hour}
+	sendIPLimit        = throttle.Limit{MaxFailures: 20, BaseLockout: 15 * time.Minute, MaxLockout: 6 * time.Hour, Window: time.Hour}
+	verifySubjectLimit = throttle.Limit{MaxFailures: 10, BaseLockout: 15 * time.Minute, MaxLockout: 6 * time.Hour, Window: 24 * time.Hour}
+	verifyIPLimit      = throttle.Limit{MaxFailures: 30, BaseLockout: time.Minute, MaxLockout: time.Hour, Window: time.Hour}
+)
+
+// Request describes a code to issue.
+type Request struct {
+	Purpose Purpose
+	// Subject identifies whose code it is: the mobile number for login, the user ID for mobile verification.
+	Subject string
+	// Mobile receives the code. When empty, limits still apply and a code is stored but nothing is sent,
+	// so a login request for an unknown number is answered exactly like one for a known number.
+	Mobile string
+	// Payload is handed back by Verify, e.g. the user ID for login or the number being verified.
+	Payload string
+	// Background sends the SMS after Issue returns, so the response time does not reveal whether one was sent.
+	Background bool
+	Client     model.ClientInfo
+}
+
+type OTPService interface {
+	// Issue stores a new code and sends it. While a limit or the resend cooldown applies it returns a
+	// *service.LockoutError; a failed synchronous delivery returns service.ErrSMSDeliveryFailed.
+	Issue(req Request) error
+	// Verify consumes the code and returns the payload it was issued with. A wrong or expired code returns
+	// service.ErrInvalidOTP, and after maxCodeAttempts wrong codes service.ErrTooManyAttempts.
+	Verify(purpose Purpose, subject, code string, client model.ClientInfo) (string, error)
+	// ClearLimits forgets the code requests and wrong codes counted for the subject, e.g. when an admin unlocks a user.
+	ClearLimits(purpose Purpose, subject string) error
+}
+
+type otpService struct {
+	otpRepo  redisdb.OTPRepository
+	provider sms.Provider
+	throttle throttle.Throttle
+}
+
+func NewOTPService(otpRepo redisdb.OTPRepository, provider sms.Provider, th throttle.Throttle) (OTPService, error) {
+	if otpRepo == nil {
+		utils.Log.Error("OTPRepository cannot be nil for OTPService.")
+		return nil, fmt.Errorf("OTPRepository cannot be nil for OTPService")
+	}
+	if provider == nil {
+		utils.Log.Error("SMS provider cannot be nil for OTPService.")
+		return nil, fmt.Errorf("SMS provider cannot be nil for OTPService")
+	}
+	if th == nil {
+		utils.Log.Error("Throttle cannot be nil for OTPService.")
+		return nil, fmt.Errorf("Throttle cannot be nil for OTPService")
+	}
+	return &otpService{otpRepo: otpRepo, provider: provider, throttle: th}, nil
+}
+
+func (s *otpService) Issue(req Request) error {
+	key := codeKey(req.Purpose, req.Subject)
+	subjects := []throttle.Subject{{Key: "otp_send:" + key, Limit: sendSubjectLimit}}
+	if req.Mobile != "" && req.Mobile != req.Subject {
+		subjects = append(subjects, throttle.Subject{Key: "otp_send:mobile:" + req.Mobile, Limit: sendMobileLimit})
+	}
+	subjects = throttle.WithIP(subjects, "otp_send", sendIPLimit, req.Client.IPAddress)
+	if err := s.throttle.Check(subjects...); err != nil {
+		utils.Log.Warn("SMS code refused while locked out", zap.String("key", key), zap.String("ip", req.Client.IPAddress), zap.Error(err))
+		return err
+	}
+
+	remaining, err := s.otpRepo.ClaimCooldown(key, resendCooldown)
+	if err != nil {
+		utils.Log.Error("Failed to check SMS code cooldown", zap.String("key", key), zap.Error(err))
+		return fmt.Errorf("%w: failed to check SMS code cooldown", service.ErrInternalService)
+	}
+	if remaining > 0 {
+		s.release(key, subjects)
+		return &service.LockoutError{RetryAfter: remaining}
+	}
+	if lockouts, err := s.throttle.Fail(subjects...); err != nil {
+		utils.Log.Error("Failed to count SMS code request", zap.String("key", key), zap.Error(err))
+	} else if len(lockouts) > 0 {
+		utils.Log.Warn("SMS code requests locked", zap.String("key", key), zap.Duration("lockout", lockouts[0].Duration))
+	}
+
+	code, err := newCode()
+	if err != nil {
+		utils.Log.Error("Failed to generate SMS code", zap.Error(err))
+		return fmt.Errorf("%w: failed to generate SMS code", service.ErrInternalService)
+	}
+	if err := s.otpRepo.SaveCode(key, hashCode(key, code), req.Payload, CodeTTL); err != nil {
+		return fmt.Errorf("%w: failed to store SMS code", service.ErrInternalService)
+	}
+	if req.Mobile == "" {
+		return nil
+	}
+
+	if req.Background {
+		go func() {
+			if err := s.send(req.Mobile, code); err != nil {
+				utils.Log.Error("Failed to deliver SMS code", zap.String("key", key), zap.Error(err))
+			}
+		}()
+		return nil
+	}
+	if err := s.send(req.Mobile, code); err != nil {
+		utils.Log.Error("Failed to deliver SMS code", zap.String("key", key), zap.Error(err))
+		s.dropCode(key)
+		if err := s.otpRepo.ReleaseCooldown(key); err != nil {
+			utils.Log.Error("Failed to release SMS code cooldown", zap.String("key", key), zap.Error(err))
+		}
+		return fmt.Errorf("%w: %v", service.ErrSMSDeliveryFailed, err)
+	}
+	return nil
+}
+
+func (s *otpService) send(mobile, code string) error {
+	ctx, cancel := context.WithTimeout(context.Background(), deliveryTimeout)
+	defer cancel()
+	return s.provider.SendOTP(ctx, mobile, code, CodeTTL)
+}
+
+func (s *otpService) Verify(purpose Purpose, subject, code string, client model.ClientInfo) (string, error) {
+	key := codeKey(purpose, subject)
+	subjects := throttle.WithIP([]throttle.Subject{{Key: verifyKey(purpose, subject), Limit: verifySubjectLimit}}, "otp_verify", verifyIPLimit, client.IPAddress)
+	if err := s.throttle.Check(subjects...); err != nil {
+		utils.Log.Warn("SMS code verification refused while locked out", zap.String("key", key), zap.Error(err))
+		return "", err
+	}
+
+	codeHash, _, err := s.otpRepo.GetCode(key)
+	if err != nil {
+		utils.Log.Error("Failed to look up SMS code", zap.String("key", key), zap.Error(err))
+		return "", fmt.Errorf("%w: failed to look up SMS code", service.ErrInternalService)
+	}
+	if codeHash == "" || subtle.ConstantTimeCompare([]byte(codeHash), []byte(hashCode(key, code))) != 1 {
+		return "", s.recordFailure(key, codeHash != "", subjects)
+	}
+	// A wrong guess leaves the code in place; the right one takes it out atomically, so it works only once
+	// even when two requests present it at the same time.
+	payload, ok, err := s.otpRepo.ConsumeCode(key, codeHash)
+	if err != nil {
+		utils.Log.Error("Failed to consume SMS code", zap.String("key", key), zap.Error(err))
+		return "", fmt.Errorf("%w: failed to consume SMS code", service.ErrInternalService)
+	}
+	if !ok {
+		s.release(key, subjects)
+		return "", service.ErrInvalidOTP
+	}
+
+	s.release(key, subjects)
+	if err := s.throttle.Clear(verifyKey(purpose, subject)); err != nil {
+		utils.Log.Error("Failed to clear SMS code failures", zap.String("key", key), zap.Error(err))
+	}
+	return payload, nil
+}
+
+// recordFailure counts a wrong code against the subject and IP counters and, if a code is pending, against the code.
+func (s *otpService) recordFailure(key string, pending bool, subjects []throttle.Subject) error {
+	lockouts, err := s.throttle.Fail(subjects...)
+	if err != nil {
+		utils.Log.Error("Failed to count SMS code failure", zap.String("key", key), zap.Error(err))
+	}
+	if len(lockouts) > 0 {
+		var longest time.Duration
+		for _, lockout := range lockouts {
+			utils.Log.Warn("Locked out after too many wrong SMS codes", zap.String("subject", lockout.Subject.Key), zap.Duration("lockout", lockout.Duration))
+			longest = max(longest, lockout.Duration)
+		}
+		s.dropCode(key)
+		return &service.LockoutError{RetryAfter: longest}
+	}
+	if !pending {
+		return service.ErrInvalidOTP
+	}
+
+	failures, err := s.otpRepo.IncrementCodeFailures(key)
+	if err != nil {
+		utils.Log.Error("Failed to count SMS code failure", zap.String("key", key), zap.Error(err))
+		return fmt.Errorf("%w: failed to count SMS code failure", service.ErrInternalService)
+	}
+	if failures >= maxCodeAttempts {
+		utils.Log.Warn("SMS code dropped after too many wrong attempts", zap.String("key", key))
+		s.dropCode(key)
+		return service.ErrTooManyAttempts
+	}
+	return service.ErrInvalidOTP
+}
+
+// release gives back the attempt slots taken by Check when the request was not counted.
+func (s *otpService) release(key string, subjects []throttle.Subject) {
+	if err := s.throttle.Release(subjects...); err != nil {
+		utils.Log.Error("Failed to release SMS code attempt slots", zap.String("key", key), zap.Error(err))
+	}
+}
+
+func (s *otpService) ClearLimits(purpose Purpose, subject string) error {
+	return s.throttle.Clear(verifyKey(purpose, subject), "otp_send:"+codeKey(purpose, subject))
+}
+
+func (s *otpService) dropCode(key string) {
+	if err := s.otpRepo.DeleteCode(key); err != nil {
+		utils.Log.Error("Failed to delete SMS code", zap.String("key", key), zap.Error(err))
+	}
+}
+
+func codeKey(purpose Purpose, subject string) string {
+	return string(purpose) + ":" + subject
+}
+
+func verifyKey(purpose Purpose, subject string) string {
+	return "otp_verify:" + codeKey(purpose, subject)
+}
+
+// hashCode binds the code to its key, so a stored hash says nothing about codes issued for other subjects.
+func hashCode(key, code string) string {
+	sum := sha256.Sum256([]byte(key + ":" + code))
+	return hex.EncodeToString(sum[:])
+}
+
+// newCode returns a uniformly random numeric code of codeDigits digits.
+func newCode() (string, error) {
+	n, err := rand.Int(rand.Reader, big.NewInt(1_000_000))
+	if err != nil {
+		return "", err
+	}
+	return fmt.Sprintf("%0*d", codeDigits, n.Int64()), nil
+}
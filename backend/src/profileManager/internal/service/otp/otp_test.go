@@ -0,0 +1,221 @@
+package otp
+
+import (
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"profile-gold/internal/model"
+	service "profile-gold/internal/service/common"
+	"profile-gold/internal/service/sms"
+	"profile-gold/internal/service/throttle"
+	"profile-gold/internal/utils"
+
+	"go.uber.org/zap"
+)
+
+// memoryOTPRepository keeps codes in memory with the same semantics as the Redis repository; expiry is not modelled.
+type memoryOTPRepository struct {
+	mu        sync.Mutex
+	cooldowns map[string]bool
+	codes     map[string][2]string
+	failures  map[string]int64
+}
+
+func newMemoryOTPRepository() *memoryOTPRepository {
+	return &memoryOTPRepository{cooldowns: map[string]bool{}, codes: map[string][2]string{}, failures: map[string]int64{}}
+}
+
+func (r *memoryOTPRepository) ClaimCooldown(key string, cooldown time.Duration) (time.Duration, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if r.cooldowns[key] {
+		return cooldown, nil
+	}
+	r.cooldowns[key] = true
+	return 0, nil
+}
+
+func (r *memoryOTPRepository) ReleaseCooldown(key string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	delete(r.cooldowns, key)
+	return nil
+}
+
+func (r *memoryOTPRepository) SaveCode(key, codeHash, payload string, _ time.Duration) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.codes[key] = [2]string{codeHash, payload}
+	delete(r.failures, key)
+	return nil
+}
+
+func (r *memoryOTPRepository) GetCode(key string) (string, string, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	code := r.codes[key]
+	return code[0], code[1], nil
+}
+
+func (r *memoryOTPRepository) ConsumeCode(key, codeHash string) (string, bool, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	code, found := r.codes[key]
+	delete(r.codes, key)
+	delete(r.failures, key)
+	if !found || code[0] != codeHash {
+		return "", false, nil
+	}
+	return code[1], true, nil
+}
+
+func (r *memoryOTPRepository) IncrementCodeFailures(key string) (int64, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.failures[key]++
+	return r.failures[key], nil
+}
+
+func (r *memoryOTPRepository) DeleteCode(key string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	delete(r.codes, key)
+	delete(r.failures, key)
+	return nil
+}
+
+// openThrottle never locks anyone out, so the tests exercise the per-code limits on their own.
+type openThrottle struct{}
+
+func (openThrottle) Check(...throttle.Subject) error                      { return nil }
+func (openThrottle) Fail(...throttle.Subject) ([]throttle.Lockout, error) { return nil, nil }
+func (openThrottle) Release(...throttle.Subject) error                    { return nil }
+func (openThrottle) Clear(...string) error                                { return nil }
+
+const testMobile = "09121234567"
+
+func newTestService(t *testing.T) (OTPService, *sms.FakeProvider) {
+	t.Helper()
+	utils.Log = zap.NewNop()
+	provider := sms.NewFakeProvider()
+	svc, err := NewOTPService(newMemoryOTPRepository(), provider, openThrottle{})
+	if err != nil {
+		t.Fatalf("NewOTPService: %v", err)
+	}
+	return svc, provider
+}
+
+func issueLogin(t *testing.T, svc OTPService) {
+	t.Helper()
+	err := svc.Issue(Request{Purpose: PurposeLogin, Subject: testMobile, Mobile: testMobile, Payload: "42", Client: model.ClientInfo{IPAddress: "10.0.0.1"}})
+	if err != nil {
+		t.Fatalf("Issue: %v", err)
+	}
+}
+
+// wrongCode returns a well-formed code that differs from code.
+func wrongCode(code string) string {
+	if code == "000000" {
+		return "111111"
+	}
+	return "000000"
+}
+
+func TestVerifyReturnsPayloadOnce(t *testing.T) {
+	svc, provider := newTestService(t)
+	issueLogin(t, svc)
+	code := provider.LastCode(testMobile)
+	if len(code) != codeDigits {
+		t.Fatalf("LastCode = %q, want %d digits", code, codeDigits)
+	}
+
+	payload, err := svc.Verify(PurposeLogin, testMobile, code, model.ClientInfo{})
+	if err != nil {
+		t.Fatalf("Verify: %v", err)
+	}
+	if payload != "42" {
+		t.Errorf("payload = %q, want %q", payload, "42")
+	}
+	if _, err := svc.Verify(PurposeLogin, testMobile, code, model.ClientInfo{}); !errors.Is(err, service.ErrInvalidOTP) {
+		t.Errorf("second Verify error = %v, want ErrInvalidOTP", err)
+	}
+}
+
+func TestVerifyConcurrentUseSucceedsOnce(t *testing.T) {
+	svc, provider := newTestService(t)
+	issueLogin(t, svc)
+	code := provider.LastCode(testMobile)
+
+	const attempts = 8
+	var wg sync.WaitGroup
+	results := make(chan error, attempts)
+	for range attempts {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			_, err := svc.Verify(PurposeLogin, testMobile, code, model.ClientInfo{})
+			results <- err
+		}()
+	}
+	wg.Wait()
+	close(results)
+
+	succeeded := 0
+	for err := range results {
+		if err == nil {
+			succeeded++
+		} else if !errors.Is(err, service.ErrInvalidOTP) {
+			t.Errorf("Verify error = %v, want nil or ErrInvalidOTP", err)
+		}
+	}
+	if succeeded != 1 {
+		t.Errorf("%d concurrent Verify calls succeeded, want 1", succeeded)
+	}
+}
+
+func TestVerifyWrongCodes(t *testing.T) {
+	svc, provider := newTestService(t)
+	issueLogin(t, svc)
+	code := provider.LastCode(testMobile)
+
+	for i := 1; i < maxCodeAttempts; i++ {
+		if _, err := svc.Verify(PurposeLogin, testMobile, wrongCode(code), model.ClientInfo{}); !errors.Is(err, service.ErrInvalidOTP) {
+			t.Fatalf("wrong code %d: error = %v, want ErrInvalidOTP", i, err)
+		}
+	}
+	if _, err := svc.Verify(PurposeLogin, testMobile, wrongCode(code), model.ClientInfo{}); !errors.Is(err, service.ErrTooManyAttempts) {
+		t.Fatalf("wrong code %d: error = %v, want ErrTooManyAttempts", maxCodeAttempts, err)
+	}
+	if _, err := svc.Verify(PurposeLogin, testMobile, code, model.ClientInfo{}); !errors.Is(err, service.ErrInvalidOTP) {
+		t.Errorf("right code after the code was dropped: error = %v, want ErrInvalidOTP", err)
+	}
+}
+
+func TestVerifyChecksPurpose(t *testing.T) {
+	svc, provider := newTestService(t)
+	issueLogin(t, svc)
+	code := provider.LastCode(testMobile)
+
+	if _, err := svc.Verify(PurposeVerifyMobile, testMobile, code, model.ClientInfo{}); !errors.Is(err, service.ErrInvalidOTP) {
+		t.Errorf("login code used for mobile verification: error = %v, want ErrInvalidOTP", err)
+	}
+	if _, err := svc.Verify(PurposeLogin, testMobile, code, model.ClientInfo{}); err != nil {
+		t.Errorf("login code after a mismatched purpose: %v", err)
+	}
+}
+
+func TestIssueResendCooldown(t *testing.T) {
+	svc, _ := newTestService(t)
+	issueLogin(t, svc)
+
+	err := svc.Issue(Request{Purpose: PurposeLogin, Subject: testMobile, Mobile: testMobile, Payload: "42"})
+	var lockout *service.LockoutError
+	if !errors.As(err, &lockout) {
+		t.Fatalf("second Issue error = %v, want *LockoutError", err)
+	}
+	if lockout.RetryAfter <= 0 {
+		t.Errorf("RetryAfter = %v, want > 0", lockout.RetryAfter)
+	}
+}
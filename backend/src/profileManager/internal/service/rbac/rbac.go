@@ -0,0 +1,217 @@
+package rbac
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"regexp"
+	"strings"
+
+	authzSvc "profile-gold/internal/api/authz"
+	"profile-gold/internal/model"
+	"profile-gold/internal/repository/db/postgresDb"
+	service "profile-gold/internal/service/common"
+	"profile-gold/internal/utils"
+
+	"common-gold/authz"
+
+	"go.uber.org/zap"
+)
+
+// Role names end up in JWT role claims, so they are kept to simple lowercase identifiers.
+var roleNamePattern = regexp.MustCompile(`^[a-z][a-z0-9_]{1,31}$`)
+
+// RBACService manages roles, their permissions and per-user overrides, and serves the versioned policy
+// that the API Gateway and CRM Manager cache.
+type RBACService interface {
+	ListRoles() ([]model.Role, error)
+	CreateRole(req model.RoleRequest) (*model.Role, error)
+	// UpdateRole changes the description and permissions; role names cannot change because users reference them.
+	UpdateRole(name string, req model.RoleRequest) (*model.Role, error)
+	DeleteRole(name string) error
+	ListPermissions() ([]model.Permission, error)
+	GetUserPermissions(userID string) (*model.UserPermissionsResponse, error)
+	SetUserPermissions(userID string, req model.UserPermissionsRequest) (*model.UserPermissionsResponse, error)
+	// Policy returns nil if knownVersion is already the current version.
+	Policy(knownVersion int64) (*authz.Policy, error)
+}
+
+type rbacService struct {
+	rbacRepo          postgresDb.RBACRepository
+	userRepo          postgresDb.UserRepository
+	permissionService authzSvc.PermissionService
+}
+
+func NewRBACService(rbacRepo postgresDb.RBACRepository, userRepo postgresDb.UserRepository, permService authzSvc.PermissionService) (RBACService, error) {
+	if rbacRepo == nil {
+		utils.Log.Error("RBACRepository cannot be nil for RBACService.")
+		return nil, fmt.Errorf("RBACRepository cannot be nil for RBACService")
+	}
+	if userRepo == nil {
+		utils.Log.Error("UserRepository cannot be nil for RBACService.")
+		return nil, fmt.Errorf("UserRepository cannot be nil for RBACService")
+	}
+	if permService == nil {
+		utils.Log.Error("PermissionService cannot be nil for RBACService.")
+		return nil, fmt.Errorf("PermissionService cannot be nil for RBACService")
+	}
+	utils.Log.Info("RBACService initialized successfully.")
+	return &rbacService{rbacRepo: rbacRepo, userRepo: userRepo, permissionService: permService}, nil
+}
+
+func (s *rbacService) ListRoles() ([]model.Role, error) {
+	roles, err := s.rbacRepo.ListRoles()
+	if err != nil {
+		utils.Log.Error("Failed to list roles", zap.Error(err))
+		return nil, fmt.Errorf("%w: failed to list roles", service.ErrInternalService)
+	}
+	return roles, nil
+}
+
+func (s *rbacService) CreateRole(req model.RoleRequest) (*model.Role, error) {
+	name := strings.TrimSpace(req.Name)
+	if !roleNamePattern.MatchString(name) {
+		return nil, fmt.Errorf("%w: use 2-32 lowercase letters, digits or underscores", service.ErrInvalidRoleName)
+	}
+	role := &model.Role{Name: name, Description: strings.TrimSpace(req.Description), Permissions: req.Permissions}
+	if err := s.rbacRepo.CreateRole(role); err != nil {
+		return nil, s.wrapRepoError("create role", err)
+	}
+	s.permissionService.Invalidate()
+	utils.Log.Info("Role created", zap.String("role", role.Name), zap.Int("permissions", len(role.Permissions)))
+	return role, nil
+}
+
+func (s *rbacService) UpdateRole(name string, req model.RoleRequest) (*model.Role, error) {
+	if req.Name != "" && req.Name != name {
+		return nil, fmt.Errorf("%w: roles cannot be renamed", service.ErrInvalidRoleName)
+	}
+	role, err := s.rbacRepo.UpdateRole(name, strings.TrimSpace(req.Description), req.Permissions)
+	if err != nil {
+		return nil, s.wrapRepoError("update role", err)
+	}
+	s.permissionService.Invalidate()
+	utils.Log.Info("Role updated", zap.String("role", name), zap.Int("permissions", len(role.Permissions)))
+	return role, nil
+}
+
+func (s *rbacService) DeleteRole(name string) error {
+	role, err := s.rbacRepo.GetRole(name)
+	if err != nil {
+		return s.wrapRepoError("get role", err)
+	}
+	if role.IsSystem {
+		return service.ErrSystemRole
+	}
+	if err := s.rbacRepo.DeleteRole(name); err != nil {
+		return s.wrapRepoError("delete role", err)
+	}
+	s.permissionService.Invalidate()
+	utils.Log.Info("Role deleted", zap.String("role", name))
+	return nil
+}
+
+func (s *rbacService) ListPermissions() ([]model.Permission, error) {
+	perms, err := s.rbacRepo.ListPermissions()
+	if err != nil {
+		utils.Log.Error("Failed to list permissions", zap.Error(err))
+		return nil, fmt.Errorf("%w: failed to list permissions", service.ErrInternalService)
+	}
+	return perms, nil
+}
+
+func (s *rbacService) GetUserPermissions(userID string) (*model.UserPermissionsResponse, error) {
+	roles, err := s.userRoles(userID)
+	if err != nil {
+		return nil, err
+	}
+	policy, err := s.rbacRepo.LoadPolicy()
+	if err != nil {
+		utils.Log.Error("Failed to load policy for user permissions", zap.String("user_id", userID), zap.Error(err))
+		return nil, fmt.Errorf("%w: failed to load permissions", service.ErrInternalService)
+	}
+	overrides := policy.UserOverrides[userID]
+	return &model.UserPermissionsResponse{
+		UserID:    userID,
+		Roles:     roles,
+		Grant:     nonNil(overrides.Grant),
+		Deny:      nonNil(overrides.Deny),
+		Effective: nonNil(policy.Effective(userID, roles)),
+	}, nil
+}
+
+func (s *rbacService) SetUserPermissions(userID string, req model.UserPermissionsRequest) (*model.UserPermissionsResponse, error) {
+	if _, err := s.userRoles(userID); err != nil {
+		return nil, err
+	}
+	denied := make(map[string]bool, len(req.Deny))
+	for _, perm := range req.Deny {
+		denied[perm] = true
+	}
+	for _, perm := range req.Grant {
+		if denied[perm] {
+			return nil, fmt.Errorf("%w: %s", service.ErrConflictingOverride, perm)
+		}
+	}
+	if err := s.rbacRepo.SetUserOverrides(userID, authz.Overrides{Grant: req.Grant, Deny: req.Deny}); err != nil {
+		return nil, s.wrapRepoError("set user permissions", err)
+	}
+	s.permissionService.Invalidate()
+	utils.Log.Info("User permission overrides updated", zap.String("user_id", userID),
+		zap.Int("granted", len(req.Grant)), zap.Int("denied", len(req.Deny)))
+	return s.GetUserPermissions(userID)
+}
+
+func (s *rbacService) Policy(knownVersion int64) (*authz.Policy, error) {
+	if knownVersion > 0 {
+		version, err := s.rbacRepo.PolicyVersion()
+		if err != nil {
+			utils.Log.Error("Failed to read policy version", zap.Error(err))
+			return nil, fmt.Errorf("%w: failed to read policy version", service.ErrInternalService)
+		}
+		if version == knownVersion {
+			return nil, nil
+		}
+	}
+	policy, err := s.rbacRepo.LoadPolicy()
+	if err != nil {
+		utils.Log.Error("Failed to load policy", zap.Error(err))
+		return nil, fmt.Errorf("%w: failed to load policy", service.ErrInternalService)
+	}
+	return policy, nil
+}
+
+func (s *rbacService) userRoles(userID string) ([]string, error) {
+	user, err := s.userRepo.GetUserByID(userID)
+	if err != nil {
+		if errors.Is(err, service.ErrUserNotFound) {
+			return nil, err
+		}
+		utils.Log.Error("Failed to get user for permissions", zap.String("user_id", userID), zap.Error(err))
+		return nil, fmt.Errorf("%w: failed to get user", service.ErrInternalService)
+	}
+	var roles []string
+	if err := json.Unmarshal(user.Roles, &roles); err != nil {
+		utils.Log.Error("Failed to unmarshal user roles", zap.String("user_id", userID), zap.Error(err))
+		return nil, fmt.Errorf("%w: failed to read user roles", service.ErrInternalService)
+	}
+	return roles, nil
+}
+
+// wrapRepoError passes RBAC validation errors through and hides everything else behind ErrInternalService.
+func (s *rbacService) wrapRepoError(op string, err error) error {
+	for _, known := range []error{service.ErrRoleNotFound, service.ErrRoleAlreadyExists, service.ErrRoleInUse, service.ErrUnknownPermission} {
+		if errors.Is(err, known) {
+			return err
+		}
+	}
+	utils.Log.Error("RBAC operation failed", zap.String("operation", op), zap.Error(err))
+	return fmt.Errorf("%w: failed to %s", service.ErrInternalService, op)
+}
+
+func nonNil(list []string) []string {
+	if list == nil {
+		return []string{}
+	}
+	return list
+}
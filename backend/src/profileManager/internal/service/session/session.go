@@ -0,0 +1,80 @@
+package session
+
+import (
+	"errors"
+	"fmt"
+	"profile-gold/internal/model"
+	"profile-gold/internal/repository/db/postgresDb"
+	redisdb "profile-gold/internal/repository/db/redisDb"
+	service "profile-gold/internal/service/common"
+	"profile-gold/internal/utils"
+	"time"
+
+	"go.uber.org/zap"
+)
+
+// SessionService lets a user see the devices they are signed in on and sign them out remotely.
+type SessionService interface {
+	// ListSessions returns the user's active sessions, flagging the one with currentSessionID.
+	ListSessions(userID, currentSessionID string) ([]model.Session, error)
+	RevokeSession(userID, sessionID string) error
+	// RevokeOtherSessions signs out every device except the current one and returns how many were revoked.
+	RevokeOtherSessions(userID, currentSessionID string) (int, error)
+}
+
+type sessionService struct {
+	sessionRepo postgresDb.SessionRepository
+	tokenRepo   redisdb.TokenRepository
+}
+
+func NewSessionService(sessionRepo postgresDb.SessionRepository, tokenRepo redisdb.TokenRepository) (SessionService, error) {
+	if sessionRepo == nil {
+		utils.Log.Error("SessionRepository cannot be nil for SessionService.")
+		return nil, fmt.Errorf("SessionRepository cannot be nil for SessionService")
+	}
+	if tokenRepo == nil {
+		utils.Log.Error("TokenRepository cannot be nil for SessionService.")
+		return nil, fmt.Errorf("TokenRepository cannot be nil for SessionService")
+	}
+	utils.Log.Info("SessionService initialized successfully.")
+	return &sessionService{sessionRepo: sessionRepo, tokenRepo: tokenRepo}, nil
+}
+
+func (s *sessionService) ListSessions(userID, currentSessionID string) ([]model.Session, error) {
+	sessions, err := s.sessionRepo.ListActiveSessions(userID, time.Now())
+	if err != nil {
+		utils.Log.Error("Failed to list sessions", zap.String("user_id", userID), zap.Error(err))
+		return nil, fmt.Errorf("%w: failed to list sessions", service.ErrInternalService)
+	}
+	for i := range sessions {
+		sessions[i].Current = sessions[i].ID == currentSessionID
+	}
+	return sessions, nil
+}
+
+func (s *sessionService) RevokeSession(userID, sessionID string) error {
+	if err := s.sessionRepo.RevokeSession(userID, sessionID, "user_revoked", time.Now()); err != nil {
+		if errors.Is(err, service.ErrSessionNotFound) {
+			return err
+		}
+		utils.Log.Error("Failed to revoke session", zap.String("user_id", userID), zap.String("session_id", sessionID), zap.Error(err))
+		return fmt.Errorf("%w: failed to revoke session", service.ErrInternalService)
+	}
+	if err := s.tokenRepo.RevokeSessions([]string{sessionID}, utils.TokenLifetime); err != nil {
+		return fmt.Errorf("%w: failed to revoke session tokens", service.ErrInternalService)
+	}
+	return nil
+}
+
+func (s *sessionService) RevokeOtherSessions(userID, currentSessionID string) (int, error) {
+	ids, err := s.sessionRepo.RevokeOtherSessions(userID, currentSessionID, "user_revoked", time.Now())
+	if err != nil {
+		utils.Log.Error("Failed to revoke other sessions", zap.String("user_id", userID), zap.Error(err))
+		return 0, fmt.Errorf("%w: failed to revoke sessions", service.ErrInternalService)
+	}
+	if err := s.tokenRepo.RevokeSessions(ids, utils.TokenLifetime); err != nil {
+		return 0, fmt.Errorf("%w: failed to revoke session tokens", service.ErrInternalService)
+	}
+	utils.Log.Info("Other sessions revoked", zap.String("user_id", userID), zap.Int("count", len(ids)))
+	return len(ids), nil
+}
@@ -0,0 +1,37 @@
+package sms
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"profile-gold/internal/utils"
+
+	"go.uber.org/zap"
+)
+
+// FakeProvider logs codes instead of sending them and remembers the last code per number, so local runs and
+// tests can complete an OTP flow without an SMS gateway. Only meant for development.
+type FakeProvider struct {
+	mu    sync.Mutex
+	codes map[string]string
+}
+
+func NewFakeProvider() *FakeProvider {
+	return &FakeProvider{codes: make(map[string]string)}
+}
+
+func (p *FakeProvider) SendOTP(_ context.Context, mobile, code string, ttl time.Duration) error {
+	p.mu.Lock()
+	p.codes[mobile] = code
+	p.mu.Unlock()
+	utils.Log.Warn("FakeProvider: SMS not sent, logging instead", zap.String("mobile", mobile), zap.String("code", code), zap.Duration("ttl", ttl))
+	return nil
+}
+
+// LastCode returns the most recent code sent to mobile, or "" if none was sent.
+func (p *FakeProvider) LastCode(mobile string) string {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	return p.codes[mobile]
+}
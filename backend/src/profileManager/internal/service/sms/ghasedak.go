@@ -0,0 +1,76 @@
+package sms
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+)
+
+const ghasedakBaseURL = "https://api.ghasedak.me/v2"
+
+// GhasedakProvider sends codes through Ghasedak: verification/send/simple with a template (the code is param1),
+// or sms/send/simple with a sender line.
+type GhasedakProvider struct {
+	cfg     Config
+	baseURL string
+	client  *http.Client
+}
+
+func NewGhasedakProvider(cfg Config) (*GhasedakProvider, error) {
+	if err := validateConfig("ghasedak", cfg); err != nil {
+		return nil, err
+	}
+	return &GhasedakProvider{cfg: cfg, baseURL: ghasedakBaseURL, client: newHTTPClient()}, nil
+}
+
+type ghasedakResponse struct {
+	Result struct {
+		Code    int    `json:"code"`
+		Message string `json:"message"`
+	} `json:"result"`
+}
+
+func (p *GhasedakProvider) SendOTP(ctx context.Context, mobile, code string, ttl time.Duration) error {
+	form := url.Values{"receptor": {mobile}}
+	path := "/sms/send/simple"
+	if p.cfg.Template != "" {
+		path = "/verification/send/simple"
+		form.Set("type", "1")
+		form.Set("template", p.cfg.Template)
+		form.Set("param1", code)
+	} else {
+		form.Set("linenumber", p.cfg.Sender)
+		form.Set("message", otpMessage(code, ttl))
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, p.baseURL+path, strings.NewReader(form.Encode()))
+	if err != nil {
+		return fmt.Errorf("ghasedak: failed to create request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	req.Header.Set("apikey", p.cfg.APIKey)
+
+	resp, err := p.client.Do(req)
+	if err != nil {
+		return fmt.Errorf("ghasedak: request failed: %w", err)
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return fmt.Errorf("ghasedak: failed to read response: %w", err)
+	}
+	var result ghasedakResponse
+	if err := json.Unmarshal(body, &result); err != nil {
+		return fmt.Errorf("ghasedak: unexpected response with HTTP status %d", resp.StatusCode)
+	}
+	if result.Result.Code != http.StatusOK {
+		return fmt.Errorf("ghasedak: code %d: %s", result.Result.Code, result.Result.Message)
+	}
+	return nil
+}
@@ -0,0 +1,76 @@
+package sms
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+)
+
+const kavenegarBaseURL = "https://api.kavenegar.com/v1"
+
+// KavenegarProvider sends codes through Kavenegar: verify/lookup.json with a template, or sms/send.json with a sender line.
+// The API key is part of the URL, so request URLs must never be logged.
+type KavenegarProvider struct {
+	cfg     Config
+	baseURL string
+	client  *http.Client
+}
+
+func NewKavenegarProvider(cfg Config) (*KavenegarProvider, error) {
+	if err := validateConfig("kavenegar", cfg); err != nil {
+		return nil, err
+	}
+	return &KavenegarProvider{cfg: cfg, baseURL: kavenegarBaseURL, client: newHTTPClient()}, nil
+}
+
+type kavenegarResponse struct {
+	Return struct {
+		Status  int    `json:"status"`
+		Message string `json:"message"`
+	} `json:"return"`
+}
+
+func (p *KavenegarProvider) SendOTP(ctx context.Context, mobile, code string, ttl time.Duration) error {
+	form := url.Values{"receptor": {mobile}}
+	method := "sms/send.json"
+	if p.cfg.Template != "" {
+		method = "verify/lookup.json"
+		form.Set("template", p.cfg.Template)
+		form.Set("token", code)
+	} else {
+		form.Set("sender", p.cfg.Sender)
+		form.Set("message", otpMessage(code, ttl))
+	}
+
+	endpoint := fmt.Sprintf("%s/%s/%s", p.baseURL, url.PathEscape(p.cfg.APIKey), method)
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, endpoint, strings.NewReader(form.Encode()))
+	if err != nil {
+		return fmt.Errorf("kavenegar: failed to create request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+
+	resp, err := p.client.Do(req)
+	if err != nil {
+		// The error text of a failed request contains the URL and with it the API key.
+		return fmt.Errorf("kavenegar: request to %s failed", method)
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return fmt.Errorf("kavenegar: failed to read response: %w", err)
+	}
+	var result kavenegarResponse
+	if err := json.Unmarshal(body, &result); err != nil {
+		return fmt.Errorf("kavenegar: unexpected response with HTTP status %d", resp.StatusCode)
+	}
+	if result.Return.Status != http.StatusOK {
+		return fmt.Errorf("kavenegar: status %d: %s", result.Return.Status, result.Return.Message)
+	}
+	return nil
+}
@@ -0,0 +1,74 @@
+package sms
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"os"
+	"strings"
+	"time"
+
+	"profile-gold/internal/utils"
+)
+
+// Provider delivers one-time codes by SMS. OTP login and mobile verification only depend on this interface,
+// so the Iranian SMS gateways below can be swapped for each other or for FakeProvider in development.
+type Provider interface {
+	SendOTP(ctx context.Context, mobile, code string, ttl time.Duration) error
+}
+
+// Config is shared by the gateway adapters. With Template set the code is sent through the gateway's
+// verification template (fast, allowed on numbers that block advertising); otherwise a plain message is sent from Sender.
+type Config struct {
+	APIKey   string
+	Sender   string
+	Template string
+}
+
+// otpMessage is the plain text used when no verification template is configured.
+func otpMessage(code string, ttl time.Duration) string {
+	return fmt.Sprintf("کد تأیید شما در سامانه طلا: %s\nاین کد تا %d دقیقه معتبر است. آن را در اختیار دیگران قرار ندهید.", code, int(ttl.Round(time.Minute)/time.Minute))
+}
+
+func newHTTPClient() *http.Client {
+	return &http.Client{Timeout: 10 * time.Second}
+}
+
+// NewProviderFromEnv picks the provider from SMS_DRIVER ("kavenegar", "ghasedak" or "fake") and configures it from
+// SMS_API_KEY, SMS_SENDER and SMS_OTP_TEMPLATE. There is no default: a missing driver fails startup instead of
+// silently logging codes, and "fake" is accepted only when SMS_ALLOW_FAKE=true is also set for local development.
+func NewProviderFromEnv() (Provider, error) {
+	driver := strings.ToLower(strings.TrimSpace(os.Getenv("SMS_DRIVER")))
+	if driver == "" {
+		return nil, fmt.Errorf("SMS_DRIVER is required")
+	}
+	cfg := Config{
+		APIKey:   os.Getenv("SMS_API_KEY"),
+		Sender:   os.Getenv("SMS_SENDER"),
+		Template: os.Getenv("SMS_OTP_TEMPLATE"),
+	}
+	switch driver {
+	case "kavenegar":
+		return NewKavenegarProvider(cfg)
+	case "ghasedak":
+		return NewGhasedakProvider(cfg)
+	case "fake":
+		if os.Getenv("SMS_ALLOW_FAKE") != "true" {
+			return nil, fmt.Errorf("SMS_DRIVER=fake only logs codes; set SMS_ALLOW_FAKE=true to use it in development")
+		}
+		utils.Log.Warn("Using FakeProvider: SMS codes will be logged, not delivered. NOT FOR PRODUCTION USE.")
+		return NewFakeProvider(), nil
+	default:
+		return nil, fmt.Errorf("unknown SMS_DRIVER %q", driver)
+	}
+}
+
+func validateConfig(name string, cfg Config) error {
+	if cfg.APIKey == "" {
+		return fmt.Errorf("%s: SMS_API_KEY is required", name)
+	}
+	if cfg.Template == "" && cfg.Sender == "" {
+		return fmt.Errorf("%s: either SMS_OTP_TEMPLATE or SMS_SENDER is required", name)
+	}
+	return nil
+}
@@ -0,0 +1,43 @@
+package sms
+
+import (
+	"testing"
+
+	"profile-gold/internal/utils"
+
+	"go.uber.org/zap"
+)
+
+func TestNewProviderFromEnv(t *testing.T) {
+	utils.Log = zap.NewNop()
+	tests := []struct {
+		name      string
+		driver    string
+		allowFake string
+		wantFake  bool
+		wantErr   bool
+	}{
+		{name: "missing driver", wantErr: true},
+		{name: "fake without opt-in", driver: "fake", wantErr: true},
+		{name: "fake with opt-in", driver: "fake", allowFake: "true", wantFake: true},
+		{name: "unknown driver", driver: "carrier-pigeon", wantErr: true},
+		{name: "real driver without key", driver: "kavenegar", wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Setenv("SMS_DRIVER", tt.driver)
+			t.Setenv("SMS_ALLOW_FAKE", tt.allowFake)
+			t.Setenv("SMS_API_KEY", "")
+			t.Setenv("SMS_SENDER", "")
+			t.Setenv("SMS_OTP_TEMPLATE", "")
+
+			provider, err := NewProviderFromEnv()
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if _, isFake := provider.(*FakeProvider); isFake != tt.wantFake {
+				t.Errorf("provider = %T, want fake %v", provider, tt.wantFake)
+			}
+		})
+	}
+}
@@ -0,0 +1,145 @@
+package throttle
+
+import (
+	"fmt"
+	"time"
+
+	redisdb "profile-gold/internal/repository/db/redisDb"
+	service "profile-gold/internal/service/common"
+	"profile-gold/internal/utils"
+
+	"go.uber.org/zap"
+)
+
+// Limit describes when a subject gets locked. After MaxFailures failures within Window the subject is locked for
+// BaseLockout; every further failure doubles the lockout, up to MaxLockout.
+type Limit struct {
+	MaxFailures int64
+	BaseLockout time.Duration
+	MaxLockout  time.Duration
+	Window      time.Duration
+}
+
+// Subject is one counter, e.g. a username or a client IP, with the limit that applies to it.
+type Subject struct {
+	Key   string
+	Limit Limit
+}
+
+// Lockout reports a lock started by a failure.
+type Lockout struct {
+	Subject  Subject
+	Duration time.Duration
+}
+
+// attemptHold bounds how long an attempt that is never failed or released keeps its slot, e.g. after an internal error.
+const attemptHold = 30 * time.Second
+
+// Throttle protects login, 2FA and password-reset requests against brute force.
+type Throttle interface {
+	// Check returns a *service.LockoutError with the longest remaining lock among the subjects. Otherwise it holds an
+	// attempt slot on every subject until Fail or Release, so concurrent guesses cannot overshoot the limit.
+	Check(subjects ...Subject) error
+	// Fail counts a failed attempt on every subject, gives back the slots taken by Check and returns the lockouts it started.
+	Fail(subjects ...Subject) ([]Lockout, error)
+	// Release gives back the slots taken by Check when the attempt did not fail.
+	Release(subjects ...Subject) error
+	// Clear forgets the failures and locks of the given subject keys.
+	Clear(keys ...string) error
+}
+
+type redisThrottle struct {
+	attemptRepo redisdb.AttemptRepository
+}
+
+func NewThrottle(attemptRepo redisdb.AttemptRepository) (Throttle, error) {
+	if attemptRepo == nil {
+		utils.Log.Error("AttemptRepository cannot be nil for Throttle.")
+		return nil, fmt.Errorf("AttemptRepository cannot be nil for Throttle")
+	}
+	return &redisThrottle{attemptRepo: attemptRepo}, nil
+}
+
+func (t *redisThrottle) Check(subjects ...Subject) error {
+	var longest time.Duration
+	reserved := make([]Subject, 0, len(subjects))
+	for _, subject := range subjects {
+		wait, err := t.attemptRepo.Reserve(subject.Key, subject.Limit.MaxFailures, attemptHold)
+		if err != nil {
+			t.release(reserved)
+			return fmt.Errorf("%w: %v", service.ErrInternalService, err)
+		}
+		if wait > 0 {
+			longest = max(longest, wait)
+			continue
+		}
+		reserved = append(reserved, subject)
+	}
+	if longest > 0 {
+		t.release(reserved)
+		return &service.LockoutError{RetryAfter: longest}
+	}
+	return nil
+}
+
+func (t *redisThrottle) Fail(subjects ...Subject) ([]Lockout, error) {
+	var lockouts []Lockout
+	for _, subject := range subjects {
+		failures, err := t.attemptRepo.RecordFailure(subject.Key, subject.Limit.Window)
+		if err != nil {
+			return lockouts, fmt.Errorf("%w: %v", service.ErrInternalService, err)
+		}
+		duration := lockoutFor(subject.Limit, failures)
+		if duration == 0 {
+			continue
+		}
+		if err := t.attemptRepo.Lock(subject.Key, duration); err != nil {
+			return lockouts, fmt.Errorf("%w: %v", service.ErrInternalService, err)
+		}
+		lockouts = append(lockouts, Lockout{Subject: subject, Duration: duration})
+	}
+	return lockouts, nil
+}
+
+func (t *redisThrottle) Release(subjects ...Subject) error {
+	for _, subject := range subjects {
+		if err := t.attemptRepo.Release(subject.Key); err != nil {
+			return fmt.Errorf("%w: %v", service.ErrInternalService, err)
+		}
+	}
+	return nil
+}
+
+// release is best effort; a slot that cannot be given back expires after attemptHold.
+func (t *redisThrottle) release(subjects []Subject) {
+	if err := t.Release(subjects...); err != nil {
+		utils.Log.Warn("Failed to release attempt slots", zap.Error(err))
+	}
+}
+
+func (t *redisThrottle) Clear(keys ...string) error {
+	if err := t.attemptRepo.Clear(keys...); err != nil {
+		return fmt.Errorf("%w: %v", service.ErrInternalService, err)
+	}
+	return nil
+}
+
+// WithIP adds the per-IP counter of the action when the client address is known.
+func WithIP(subjects []Subject, action string, limit Limit, ipAddress string) []Subject {
+	if ipAddress == "" {
+		return subjects
+	}
+	return append(subjects, Subject{Key: action + ":ip:" + ipAddress, Limit: limit})
+}
+
+// lockoutFor returns 0 below the limit, then BaseLockout doubled for each failure past MaxFailures.
+func lockoutFor(limit Limit, failures int64) time.Duration {
+	if failures < limit.MaxFailures {
+		return 0
+	}
+	duration := limit.BaseLockout
+	for i := limit.MaxFailures; i < failures && duration < limit.MaxLockout; i++ {
+		duration *= 2
+	}
+	return min(duration, limit.MaxLockout)
+}
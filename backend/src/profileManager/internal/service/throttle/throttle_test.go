@@ -0,0 +1,259 @@
+package throttle
+
+import (
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	service "profile-gold/internal/service/common"
+	"profile-gold/internal/utils"
+
+	"go.uber.org/zap"
+)
+
+// memoryAttemptRepository keeps counters in memory with the same semantics as the Redis scripts; expiry is not
+// modelled, so a lock lasts until Clear and a slot until Fail or Release.
+type memoryAttemptRepository struct {
+	mu       sync.Mutex
+	failures map[string]int64
+	pending  map[string]int64
+	locks    map[string]time.Duration
+}
+
+func newMemoryAttemptRepository() *memoryAttemptRepository {
+	return &memoryAttemptRepository{failures: map[string]int64{}, pending: map[string]int64{}, locks: map[string]time.Duration{}}
+}
+
+func (r *memoryAttemptRepository) Reserve(key string, maxFailures int64, hold time.Duration) (time.Duration, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if ttl := r.locks[key]; ttl > 0 {
+		return ttl, nil
+	}
+	if r.pending[key] > 0 && r.failures[key]+r.pending[key] >= maxFailures {
+		return hold, nil
+	}
+	r.pending[key]++
+	return 0, nil
+}
+
+func (r *memoryAttemptRepository) Release(key string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if r.pending[key] > 0 {
+		r.pending[key]--
+	}
+	return nil
+}
+
+func (r *memoryAttemptRepository) RecordFailure(key string, _ time.Duration) (int64, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if r.pending[key] > 0 {
+		r.pending[key]--
+	}
+	r.failures[key]++
+	return r.failures[key], nil
+}
+
+func (r *memoryAttemptRepository) Lock(key string, duration time.Duration) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.locks[key] = duration
+	return nil
+}
+
+func (r *memoryAttemptRepository) Clear(keys ...string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	for _, key := range keys {
+		delete(r.failures, key)
+		delete(r.pending, key)
+		delete(r.locks, key)
+	}
+	return nil
+}
+
+var testLimit = Limit{MaxFailures: 3, BaseLockout: time.Minute, MaxLockout: 10 * time.Minute, Window: time.Hour}
+
+func newTestThrottle(t *testing.T) (Throttle, *memoryAttemptRepository) {
+	t.Helper()
+	utils.Log = zap.NewNop()
+	repo := newMemoryAttemptRepository()
+	th, err := NewThrottle(repo)
+	if err != nil {
+		t.Fatalf("NewThrottle: %v", err)
+	}
+	return th, repo
+}
+
+// failAttempt runs one attempt that fails and returns the lockout it started, if any.
+func failAttempt(t *testing.T, th Throttle, subject Subject) time.Duration {
+	t.Helper()
+	if err := th.Check(subject); err != nil {
+		t.Fatalf("Check before failure: %v", err)
+	}
+	lockouts, err := th.Fail(subject)
+	if err != nil {
+		t.Fatalf("Fail: %v", err)
+	}
+	if len(lockouts) == 0 {
+		return 0
+	}
+	return lockouts[0].Duration
+}
+
+func retryAfter(err error) time.Duration {
+	var lockout *service.LockoutError
+	if errors.As(err, &lockout) {
+		return lockout.RetryAfter
+	}
+	return 0
+}
+
+func TestLockoutFor(t *testing.T) {
+	tests := []struct {
+		failures int64
+		want     time.Duration
+	}{
+		{failures: 0, want: 0},
+		{failures: 2, want: 0},
+		{failures: 3, want: time.Minute},
+		{failures: 4, want: 2 * time.Minute},
+		{failures: 5, want: 4 * time.Minute},
+		{failures: 6, want: 8 * time.Minute},
+		{failures: 7, want: 10 * time.Minute},
+		{failures: 50, want: 10 * time.Minute},
+	}
+	for _, tt := range tests {
+		if got := lockoutFor(testLimit, tt.failures); got != tt.want {
+			t.Errorf("lockoutFor(%d) = %v, want %v", tt.failures, got, tt.want)
+		}
+	}
+}
+
+func TestFailLocksAtLimit(t *testing.T) {
+	th, _ := newTestThrottle(t)
+	subject := Subject{Key: "login:user:alice", Limit: testLimit}
+
+	for i := 1; i < int(testLimit.MaxFailures); i++ {
+		if d := failAttempt(t, th, subject); d != 0 {
+			t.Fatalf("failure %d locked for %v", i, d)
+		}
+	}
+	if d := failAttempt(t, th, subject); d != time.Minute {
+		t.Fatalf("lockout at the limit = %v, want %v", d, time.Minute)
+	}
+	if got := retryAfter(th.Check(subject)); got != time.Minute {
+		t.Fatalf("Check while locked: RetryAfter = %v, want %v", got, time.Minute)
+	}
+}
+
+func TestCheckReportsLongestLock(t *testing.T) {
+	th, repo := newTestThrottle(t)
+	user := Subject{Key: "2fa:user:1", Limit: testLimit}
+	ip := Subject{Key: "2fa:ip:10.0.0.1", Limit: testLimit}
+	repo.locks[user.Key] = time.Minute
+	repo.locks[ip.Key] = 5 * time.Minute
+
+	if got := retryAfter(th.Check(user, ip)); got != 5*time.Minute {
+		t.Fatalf("RetryAfter = %v, want %v", got, 5*time.Minute)
+	}
+	if repo.pending[user.Key] != 0 || repo.pending[ip.Key] != 0 {
+		t.Fatalf("refused Check left slots behind: %v", repo.pending)
+	}
+}
+
+// Near the limit only one attempt may be in flight, so concurrent guesses cannot all pass Check before one is counted.
+func TestCheckHoldsSlotNearLimit(t *testing.T) {
+	th, repo := newTestThrottle(t)
+	user := Subject{Key: "2fa:user:1", Limit: testLimit}
+	ip := Subject{Key: "2fa:ip:10.0.0.1", Limit: testLimit}
+	failAttempt(t, th, user)
+	failAttempt(t, th, user)
+
+	if err := th.Check(user, ip); err != nil {
+		t.Fatalf("first Check: %v", err)
+	}
+	if got := retryAfter(th.Check(user, ip)); got != attemptHold {
+		t.Fatalf("second Check: RetryAfter = %v, want %v", got, attemptHold)
+	}
+	if repo.pending[ip.Key] != 1 {
+		t.Fatalf("refused Check kept its IP slot: pending = %d, want 1", repo.pending[ip.Key])
+	}
+
+	if err := th.Release(user, ip); err != nil {
+		t.Fatalf("Release: %v", err)
+	}
+	if err := th.Check(user, ip); err != nil {
+		t.Fatalf("Check after Release: %v", err)
+	}
+}
+
+func TestClearAfterSuccessResetsBackoff(t *testing.T) {
+	th, _ := newTestThrottle(t)
+	subject := Subject{Key: "login:user:alice", Limit: testLimit}
+	for i := 0; i < int(testLimit.MaxFailures)-1; i++ {
+		failAttempt(t, th, subject)
+	}
+
+	// A successful attempt gives its slot back and forgets the failures.
+	if err := th.Check(subject); err != nil {
+		t.Fatalf("Check: %v", err)
+	}
+	if err := th.Release(subject); err != nil {
+		t.Fatalf("Release: %v", err)
+	}
+	if err := th.Clear(subject.Key); err != nil {
+		t.Fatalf("Clear: %v", err)
+	}
+
+	for i := 1; i < int(testLimit.MaxFailures); i++ {
+		if d := failAttempt(t, th, subject); d != 0 {
+			t.Fatalf("failure %d after success locked for %v", i, d)
+		}
+	}
+}
+
+func TestClearUnlocks(t *testing.T) {
+	th, _ := newTestThrottle(t)
+	subject := Subject{Key: "login:user:alice", Limit: testLimit}
+	for i := 0; i < int(testLimit.MaxFailures)+2; i++ {
+		if err := th.Check(subject); err != nil {
+			break
+		}
+		if _, err := th.Fail(subject); err != nil {
+			t.Fatalf("Fail: %v", err)
+		}
+	}
+	if th.Check(subject) == nil {
+		t.Fatal("Check passed before unlock")
+	}
+
+	if err := th.Clear(subject.Key); err != nil {
+		t.Fatalf("Clear: %v", err)
+	}
+	if err := th.Check(subject); err != nil {
+		t.Fatalf("Check after unlock: %v", err)
+	}
+	if _, err := th.Fail(subject); err != nil {
+		t.Fatalf("Fail: %v", err)
+	}
+	// The backoff starts over: the next lockout is BaseLockout again, not the doubled one.
+	failAttempt(t, th, subject)
+	if d := failAttempt(t, th, subject); d != testLimit.BaseLockout {
+		t.Fatalf("lockout after unlock = %v, want %v", d, testLimit.BaseLockout)
+	}
+}
+
+func TestWithIP(t *testing.T) {
+	base := []Subject{{Key: "login:user:alice", Limit: testLimit}}
+	if got := WithIP(base, "login", testLimit, ""); len(got) != 1 {
+		t.Fatalf("WithIP without address added %d subjects", len(got)-1)
+	}
+	got := WithIP(base, "login", testLimit, "10.0.0.1")
+	if len(got) != 2 || got[1].Key != "login:ip:10.0.0.1" {
+		t.Fatalf("WithIP = %+v", got)
+	}
+}
@@ -0,0 +1,49 @@
+package twofa
+
+import (
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base32"
+	"encoding/hex"
+	"fmt"
+	"strings"
+)
+
+const (
+	// RecoveryCodeCount is how many recovery codes are issued at a time.
+	RecoveryCodeCount = 10
+	// recoveryCodeBytes gives 80 bits per code, shown as 16 base32 characters.
+	recoveryCodeBytes = 10
+)
+
+// NewRecoveryCodes returns codes formatted for the user (XXXX-XXXX-XXXX-XXXX) and their hashes for storage.
+func NewRecoveryCodes(n int) (codes []string, hashes []string, err error) {
+	codes = make([]string, n)
+	hashes = make([]string, n)
+	for i := 0; i < n; i++ {
+		b := make([]byte, recoveryCodeBytes)
+		if _, err := rand.Read(b); err != nil {
+			return nil, nil, fmt.Errorf("failed to generate recovery code: %w", err)
+		}
+		raw := base32.StdEncoding.WithPadding(base32.NoPadding).EncodeToString(b)
+		codes[i] = fmt.Sprintf("%s-%s-%s-%s", raw[0:4], raw[4:8], raw[8:12], raw[12:16])
+		hashes[i] = HashRecoveryCode(codes[i])
+	}
+	return codes, hashes, nil
+}
+
+// HashRecoveryCode ignores case, spaces and dashes so the code can be typed loosely.
+func HashRecoveryCode(code string) string {
+	sum := sha256.Sum256([]byte(normalizeRecoveryCode(code)))
+	return hex.EncodeToString(sum[:])
+}
+
+// IsRecoveryCodeFormat tells a recovery code apart from a 6-digit TOTP code.
+func IsRecoveryCodeFormat(code string) bool {
+	return len(normalizeRecoveryCode(code)) == 16
+}
+
+func normalizeRecoveryCode(code string) string {
+	code = strings.ToUpper(code)
+	return strings.NewReplacer("-", "", " ", "").Replace(code)
+}
@@ -0,0 +1,27 @@
+package twofa
+
+import (
+	"time"
+
+	"profile-gold/internal/service/throttle"
+)
+
+// UsedCodeTTL covers the validation window (previous, current and next 30s step), so a code cannot be replayed.
+const UsedCodeTTL = 90 * time.Second
+
+// Every check of a user's TOTP or recovery code counts against the same counters, whether it completes a login,
+// disables 2FA or regenerates recovery codes, so no endpoint gives an attacker more guesses than login does.
+var (
+	userLimit = throttle.Limit{MaxFailures: 10, BaseLockout: 5 * time.Minute, MaxLockout: 2 * time.Hour, Window: 24 * time.Hour}
+	ipLimit   = throttle.Limit{MaxFailures: 30, BaseLockout: time.Minute, MaxLockout: time.Hour, Window: time.Hour}
+)
+
+// UserThrottleKey is the per-user counter of wrong codes; clearing it unlocks the user's 2FA checks.
+func UserThrottleKey(userID string) string {
+	return "2fa:user:" + userID
+}
+
+// ThrottleSubjects returns the per-user and, when the address is known, per-IP counters of a code check.
+func ThrottleSubjects(userID, ipAddress string) []throttle.Subject {
+	return throttle.WithIP([]throttle.Subject{{Key: UserThrottleKey(userID), Limit: userLimit}}, "2fa", ipLimit, ipAddress)
+}
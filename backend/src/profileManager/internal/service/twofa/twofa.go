@@ -1,22 +1,31 @@
 package twofa
 
 import (
+	"bytes"
+	"encoding/base64"
 	"errors"
 	"fmt"
+	"image/png"
 	"time"
 
+	"profile-gold/internal/model"
 	"profile-gold/internal/repository/db/postgresDb"
 	"profile-gold/internal/utils"
 
 	"github.com/pquerna/otp/totp"
 )
 
+const (
+    totpIssuer = "Zarfolio"
+    qrCodeSize = 256
+)
+
 type TwoFAService interface {
-    GenerateSecret() (string, error)
+    // GenerateSetup creates a new secret for accountName with its otpauth URI and QR code.
+    GenerateSetup(accountName string) (*model.TwoFASetupResponse, error)
     GenerateTOTPCode(secret string) (string, error) 
     VerifyTOTPCode(secret, code string) bool        
     GetUser2FASecret(userID string) (string, error)
-    SetUser2FASecret(userID, secret string) error
     RemoveUser2FASecret(userID string) error
 }
 
@@ -34,15 +43,29 @@ func NewSimpleTwoFAService(userRepo postgresDb.UserRepository) (TwoFAService, er
     }, nil
 }
 
-func (s *simpleTwoFAService) GenerateSecret() (string, error) {
+func (s *simpleTwoFAService) GenerateSetup(accountName string) (*model.TwoFASetupResponse, error) {
     key, err := totp.Generate(totp.GenerateOpts{
-        Issuer:      "Zarfolio",      
-        AccountName: "user@example.com", 
+        Issuer:      totpIssuer,
+        AccountName: accountName,
     })
     if err != nil {
-        return "", fmt.Errorf("failed to generate 2FA secret: %w", err)
+        return nil, fmt.Errorf("failed to generate 2FA secret: %w", err)
+    }
+
+    img, err := key.Image(qrCodeSize, qrCodeSize)
+    if err != nil {
+        return nil, fmt.Errorf("failed to render 2FA QR code: %w", err)
     }
-    return key.Secret(), nil
+    var buf bytes.Buffer
+    if err := png.Encode(&buf, img); err != nil {
+        return nil, fmt.Errorf("failed to encode 2FA QR code: %w", err)
+    }
+
+    return &model.TwoFASetupResponse{
+        Secret:     key.Secret(),
+        OTPAuthURL: key.URL(),
+        QRCodePNG:  "data:image/png;base64," + base64.StdEncoding.EncodeToString(buf.Bytes()),
+    }, nil
 }
 
 func (s *simpleTwoFAService) GenerateTOTPCode(secret string) (string, error) {
@@ -69,23 +92,6 @@ func (s *simpleTwoFAService) GetUser2FASecret(userID string) (string, error) {
     return user.TwoFASecret, nil 
 }
 
-func (s *simpleTwoFAService) SetUser2FASecret(userID, secret string) error {
-    user, err := s.userRepo.GetUserByID(userID)
-    if err != nil {
-        return fmt.Errorf("failed to get user by ID %s: %w", userID, err)
-    }
-    if user == nil {
-        return errors.New("user not found")
-    }
-    user.TwoFASecret = secret
-    user.TwoFAEnabled = true 
-    err = s.userRepo.UpdateUser(user) 
-    if err != nil {
-        return fmt.Errorf("failed to update user with 2FA secret: %w", err)
-    }
-    return nil
-}
-
 func (s *simpleTwoFAService) RemoveUser2FASecret(userID string) error {
     user, err := s.userRepo.GetUserByID(userID)
     if err != nil {
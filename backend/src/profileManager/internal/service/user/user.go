@@ -157,7 +157,11 @@ func (s *userService) HandleUpdateUserRoles(userID string, newRoles []string) er
 	}
 
 	 for _, role := range newRoles {
-        if !s.permissionService.IsValidRole(role) { 
+        valid, err := s.permissionService.IsValidRole(role)
+        if err != nil {
+            return fmt.Errorf("failed to validate role %s: %w", role, err)
+        }
+        if !valid {
             return fmt.Errorf("invalid role provided: %s", role)
         }
     }
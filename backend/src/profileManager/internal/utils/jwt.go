@@ -11,6 +11,16 @@ import (
     "go.uber.org/zap"
 )
 
+// TokenLifetime is how long an access token stays valid; revocation markers must live at least this long.
+// Clients keep a session alive by exchanging their refresh token before it runs out.
+const TokenLifetime = 15 * time.Minute
+
+// Issued-at is kept to the millisecond, so user-wide revocation, which compares against it, does not catch
+// tokens issued later within the same second.
+func init() {
+    jwt.TimePrecision = time.Millisecond
+}
+
 type JWTValidator interface {
     ValidateToken(token string) (*model.CustomClaims, error)
 }
@@ -30,19 +40,20 @@ func NewJWTValidatorImpl() *JWTValidatorImpl {
     }
 }
 
-func GenerateJWTToken(user *model.User) (string, *model.CustomClaims, error) {
+func GenerateJWTToken(user *model.User, sessionID string) (string, *model.CustomClaims, error) {
    
     jwtSecret := os.Getenv("JWT_SECRET_KEY")
     if jwtSecret == "" {
         Log.Fatal("JWT_SECRET_KEY environment variable is not set. Cannot generate JWT.")
     }
 
-    expirationTime := time.Now().Add(24 * time.Hour)
+    expirationTime := time.Now().Add(TokenLifetime)
 
     claims := &model.CustomClaims{
-        UserID:   user.ID,
-        Username: user.Username,
-        Roles:    user.Roles, 
+        UserID:    user.ID,
+        Username:  user.Username,
+        Roles:     user.Roles, 
+        SessionID: sessionID,
         RegisteredClaims: jwt.RegisteredClaims{
             ExpiresAt: jwt.NewNumericDate(expirationTime),
             IssuedAt:  jwt.NewNumericDate(time.Now()),
@@ -65,6 +76,36 @@ func GenerateJWTToken(user *model.User) (string, *model.CustomClaims, error) {
     return tokenString, claims, nil
 }
 
+// GenerateAPIKeyToken issues an access token of the key's owner for the API Gateway to forward downstream.
+// It carries the key ID instead of a session ID, so revoking the key is what ends it, and the key's business,
+// so services behind the gateway keep the request on that business too.
+func GenerateAPIKeyToken(user *model.User, keyID string, businessID *uint) (string, *model.CustomClaims, error) {
+    jwtSecret := os.Getenv("JWT_SECRET_KEY")
+    if jwtSecret == "" {
+        Log.Fatal("JWT_SECRET_KEY environment variable is not set. Cannot generate JWT.")
+    }
+
+    now := time.Now()
+    claims := &model.CustomClaims{
+        UserID:     user.ID,
+        Username:   user.Username,
+        Roles:      user.Roles,
+        APIKeyID:   keyID,
+        BusinessID: businessID,
+        RegisteredClaims: jwt.RegisteredClaims{
+            ExpiresAt: jwt.NewNumericDate(now.Add(TokenLifetime)),
+            IssuedAt:  jwt.NewNumericDate(now),
+            NotBefore: jwt.NewNumericDate(now),
+        },
+    }
+
+    tokenString, err := jwt.NewWithClaims(jwt.SigningMethodHS256, claims).SignedString([]byte(jwtSecret))
+    if err != nil {
+        Log.Error("Failed to sign API key token", zap.Error(err), zap.String("api_key_id", keyID))
+        return "", nil, fmt.Errorf("failed to sign token: %w", err)
+    }
+    return tokenString, claims, nil
+}
 
 func (v *JWTValidatorImpl) ValidateToken(tokenString string) (*model.CustomClaims, error) {
     if v.jwtSecret == nil || len(v.jwtSecret) == 0 { 
@@ -0,0 +1,40 @@
+package utils
+
+import (
+	"errors"
+	"strings"
+	"unicode"
+)
+
+// NormalizeMobile turns an Iranian mobile number as people type it (+98 912 345 6789, 0098..., 98..., 912...,
+// 0912-345-6789, with Latin, Persian or Arabic digits) into the 09xxxxxxxxx form stored on users.
+func NormalizeMobile(mobile string) (string, error) {
+	var digits strings.Builder
+	for i, r := range strings.TrimSpace(mobile) {
+		switch {
+		case r >= '0' && r <= '9':
+			digits.WriteRune(r)
+		case r >= '۰' && r <= '۹':
+			digits.WriteRune('0' + r - '۰')
+		case r >= '٠' && r <= '٩':
+			digits.WriteRune('0' + r - '٠')
+		case r == '+' && i == 0, r == '-', r == '(', r == ')', unicode.IsSpace(r):
+		default:
+			return "", errors.New("mobile number may only contain digits")
+		}
+	}
+
+	number := digits.String()
+	switch {
+	case strings.HasPrefix(number, "0098"):
+		number = "0" + number[4:]
+	case strings.HasPrefix(number, "98") && len(number) == 12:
+		number = "0" + number[2:]
+	case strings.HasPrefix(number, "9") && len(number) == 10:
+		number = "0" + number
+	}
+	if len(number) != 11 || !strings.HasPrefix(number, "09") {
+		return "", errors.New("mobile number must look like 09123456789")
+	}
+	return number, nil
+}
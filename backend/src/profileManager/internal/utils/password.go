@@ -2,8 +2,12 @@ package utils
 
 import (
 	"crypto/rand"
+	"errors"
 	"fmt"
+	"strings"
 	"time"
+	"unicode"
+	"unicode/utf8"
 
 	"go.uber.org/zap"
 	"golang.org/x/crypto/bcrypt"
@@ -37,3 +41,40 @@ func CheckPasswordHash(password, hash string) error {
 	}
 	return err
 }
+
+const (
+	MinPasswordLength = 8
+	// bcrypt ignores everything after 72 bytes.
+	MaxPasswordBytes = 72
+)
+
+// ValidatePasswordPolicy requires MinPasswordLength characters with at least one letter and one digit,
+// and rejects passwords that contain the username or the local part of the email.
+func ValidatePasswordPolicy(password, username, email string) error {
+	if utf8.RuneCountInString(password) < MinPasswordLength {
+		return fmt.Errorf("password must be at least %d characters", MinPasswordLength)
+	}
+	if len(password) > MaxPasswordBytes {
+		return fmt.Errorf("password must be at most %d bytes", MaxPasswordBytes)
+	}
+	var hasLetter, hasDigit bool
+	for _, r := range password {
+		switch {
+		case unicode.IsLetter(r):
+			hasLetter = true
+		case unicode.IsDigit(r):
+			hasDigit = true
+		}
+	}
+	if !hasLetter || !hasDigit {
+		return errors.New("password must contain both letters and digits")
+	}
+	lower := strings.ToLower(password)
+	if username != "" && strings.Contains(lower, strings.ToLower(username)) {
+		return errors.New("password must not contain the username")
+	}
+	if local, _, _ := strings.Cut(email, "@"); len(local) >= 3 && strings.Contains(lower, strings.ToLower(local)) {
+		return errors.New("password must not contain the email address")
+	}
+	return nil
+}
@@ -54,6 +54,7 @@ const PrintSettings = lazy(() => import('./pages/settings/PrintSettings.jsx'));
 const TaxSettings = lazy(() => import('./pages/settings/TaxSettings.jsx'));
 const AvatarSettings = lazy(() => import('./pages/settings/AvatarSettings.jsx'));
 const LogsViewer = lazy(() => import('./pages/settings/LogsViewer.jsx'));
+const ApiKeys = lazy(() => import('./pages/settings/ApiKeys.jsx'));
 const ExtraCurrencies = lazy(() => import('./pages/settings/ExtraCurrencies.jsx'));
 const PriceBoardPage = lazy(() => import('./pages/settings/PriceBoardPage.jsx'));
 const PublicPriceBoard = lazy(() => import('./pages/public/PublicPriceBoard.jsx'));
@@ -170,11 +171,12 @@ function App() {
                       <Route path="settings/system" element={<SystemSettingsPage />} />
                       <Route path="settings/business" element={<BusinessSettings />} />
                       <Route path="settings/users" element={<UserRolls />} />
-                      <Route path="settings/users/permissions/:email" element={<UserPermissions />} />
+                      <Route path="settings/users/permissions/:userId" element={<UserPermissions />} />
                       <Route path="settings/print" element={<PrintSettings />} />
                       <Route path="settings/tax" element={<TaxSettings />} />
                       <Route path="settings/avatar" element={<AvatarSettings />} />
                       <Route path="settings/logs" element={<LogsViewer />} />
+                      <Route path="settings/api-keys" element={<ApiKeys />} />
                       <Route path="settings/currencies" element={<ExtraCurrencies />} />
                       <Route path="settings/price-board" element={<PriceBoardPage />} />
                       <Route path="settings/notifications" element={<AdminNotificationPage />} />
@@ -10,7 +10,7 @@ import {
   FaCube, FaUserCog, FaAngleLeft, FaAngleRight, FaUserCircle,
   FaStore, FaUsersCog, FaPrint, FaFileContract, FaIdBadge, FaHistory, FaMoneyBillWave,
   FaCogs, FaUniversity, FaCreditCard, FaMoneyCheckAlt, FaExchangeAlt, FaPiggyBank, FaClipboardList,
-  FaPlug,
+  FaPlug, FaKey,
   // +++ آیکون‌های جدید برای افزونه انبارداری +++
   FaWarehouse, FaDolly, FaTruckLoading, FaBell
 } from 'react-icons/fa';
@@ -135,6 +135,7 @@ function Sidebar({ isCollapsed, setIsCollapsed }) {
         { key: '/settings/tax', icon: <FaFileContract />, label: <Link to="/settings/tax">تنظیمات مالیاتی</Link> },
         { key: '/settings/avatar', icon: <FaIdBadge />, label: <Link to="/settings/avatar">نمایه و مهر</Link> },
         { key: '/settings/logs', icon: <FaHistory />, label: <Link to="/settings/logs">تاریخچه رویدادها</Link> },
+        { key: '/settings/api-keys', icon: <FaKey />, label: <Link to="/settings/api-keys">کلیدهای API</Link> },
         { key: '/settings/currencies', icon: <FaMoneyBillWave />, label: <Link to="/settings/currencies">مدیریت ارزها</Link> },
         { key: '/settings/system', icon: <FaCogs />, label: <Link to="/settings/system">تنظیمات سیستم</Link> },
         { key: '/settings/notifications', icon: <FaBell />, label: <Link to="/settings/notifications">اعلانات</Link> },
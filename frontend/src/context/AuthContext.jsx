@@ -1,4 +1,24 @@
-import React, { createContext, useContext, useState, useEffect } from 'react';
+import React, { createContext, useContext, useState, useEffect, useCallback } from 'react';
+
+const API_BASE_URL = import.meta.env.VITE_API_BASE_URL || 'http://localhost:8080/api/v1';
+// توکن دسترسی کوتاه‌مدت است؛ کمی قبل از انقضا با refresh token تمدید می‌شود
+const REFRESH_AHEAD_MS = 60 * 1000;
+
+// ذخیره پاسخ لاگین، تایید 2FA یا تمدید توکن
+export const storeSession = (data) => {
+    localStorage.setItem('authToken', data.token);
+    if (data.exp) localStorage.setItem('authTokenExp', String(data.exp));
+    if (data.refresh_token) localStorage.setItem('refreshToken', data.refresh_token);
+    if (data.user) localStorage.setItem('userData', JSON.stringify(data.user));
+};
+
+const clearSession = () => {
+    localStorage.removeItem('authToken');
+    localStorage.removeItem('authTokenExp');
+    localStorage.removeItem('refreshToken');
+    localStorage.removeItem('userData');
+    sessionStorage.removeItem('2fa_challenge_token'); // پاک کردن وضعیت 2FA در صورت وجود
+};
 
 // ۱. ایجاد کانتکست
 const AuthContext = createContext(null);
@@ -26,15 +46,55 @@ export const AuthProvider = ({ children }) => {
         setAuthToken(token);
     };
 
-    const logout = () => {
-        // حذف تمام اطلاعات مربوط به کاربر از localStorage
-        localStorage.removeItem('authToken');
-        localStorage.removeItem('userData');
-        localStorage.removeItem('2fa_user_id'); // پاک کردن وضعیت 2FA در صورت وجود
+    const logout = useCallback(() => {
+        const token = localStorage.getItem('authToken');
+        if (token) {
+            // نشست در سرور هم باطل می‌شود؛ خطای شبکه مانع خروج نمی‌شود
+            fetch(`${API_BASE_URL}/auth/logout`, {
+                method: 'POST',
+                headers: { Authorization: `Bearer ${token}` },
+            }).catch(() => {});
+        }
+        clearSession();
         setAuthToken(null);
         // برای اطمینان از خروج کامل، کاربر را به صفحه لاگین هدایت می‌کنیم
         window.location.href = '/login'; 
-    };
+    }, []);
+
+    // تمدید خودکار توکن دسترسی. refresh token یک‌بار مصرف است؛ اگر تب دیگری زودتر تمدید کرده باشد
+    // از توکن جدید آن استفاده می‌کنیم تا استفاده مجدد باعث ابطال نشست نشود.
+    useEffect(() => {
+        if (!authToken) return undefined;
+        const exp = Number(localStorage.getItem('authTokenExp'));
+        if (!exp || !localStorage.getItem('refreshToken')) return undefined;
+
+        const jitter = Math.random() * 15 * 1000;
+        const delay = Math.max(exp * 1000 - Date.now() - REFRESH_AHEAD_MS - jitter, 0);
+        const timer = setTimeout(async () => {
+            if (Number(localStorage.getItem('authTokenExp')) > exp) {
+                setAuthToken(localStorage.getItem('authToken'));
+                return;
+            }
+            try {
+                const response = await fetch(`${API_BASE_URL}/auth/refresh`, {
+                    method: 'POST',
+                    headers: { 'Content-Type': 'application/json' },
+                    body: JSON.stringify({ refresh_token: localStorage.getItem('refreshToken') }),
+                });
+                if (response.status === 401) {
+                    logout();
+                    return;
+                }
+                if (!response.ok) return;
+                const data = await response.json();
+                storeSession(data);
+                setAuthToken(data.token);
+            } catch (err) {
+                console.error('Token refresh failed:', err);
+            }
+        }, delay);
+        return () => clearTimeout(timer);
+    }, [authToken, logout]);
 
     // مقدار isAuthenticated به صورت بولین (true/false) از وجود توکن مشتق می‌شود
     const isAuthenticated = !!authToken;
@@ -48,6 +48,15 @@ function AccountManagementPage() {
   const [showRecoveryCodes, setShowRecoveryCodes] = useState(false); //
   const [twoFASetupStage, setTwoFASetupStage] = useState('initial'); //
 
+  // State for Mobile Verification
+  const [verifiedMobile, setVerifiedMobile] = useState(''); //
+  const [newMobile, setNewMobile] = useState(''); //
+  const [mobileCode, setMobileCode] = useState(''); //
+  const [mobileCodeSent, setMobileCodeSent] = useState(false); //
+  const [mobileMessage, setMobileMessage] = useState(''); //
+  const [mobileError, setMobileError] = useState(''); //
+  const [isMobileLoading, setIsMobileLoading] = useState(false); //
+
   const getAuthToken = () => localStorage.getItem('authToken'); //
 
   useEffect(() => {
@@ -63,6 +72,8 @@ function AccountManagementPage() {
                     setTwoFASetupStage('enabled'); //
                 }
             }
+            // شماره موبایل تأییدشده
+            setVerifiedMobile(userData?.mobile || ''); //
             // Initialize new profile fields
             if (userData?.fullName) { //
               const parts = userData.fullName.split(' '); //
@@ -244,7 +255,7 @@ function AccountManagementPage() {
         });
         const data = await response.json(); //
         if (response.ok) { //
-            setQrCodeUrl(data.qr_code_url); //
+            setQrCodeUrl(data.otpauth_url); //
             setTwoFASecret(data.secret); //
             setTwoFASetupStage('generated'); //
         } else {
@@ -300,6 +311,86 @@ function AccountManagementPage() {
     }
   };
 
+  const handleRequestMobileCode = async (e) => {
+    e.preventDefault(); //
+    setIsMobileLoading(true); //
+    setMobileError(''); //
+    setMobileMessage(''); //
+
+    try {
+        const response = await fetch(`${API_BASE_URL}/account/mobile`, { //
+            method: 'POST', //
+            headers: {
+                'Content-Type': 'application/json', //
+                'Authorization': `Bearer ${getAuthToken()}`, //
+            },
+            body: JSON.stringify({ mobile: newMobile }), //
+        });
+        const data = await response.json(); //
+        if (response.ok) { //
+            setMobileCodeSent(true); //
+            setMobileCode(''); //
+            setMobileMessage('کد تأیید به شماره موبایل وارد شده پیامک شد.'); //
+        } else if (response.status === 409) { //
+            setMobileError('این شماره موبایل برای حساب دیگری ثبت شده است.'); //
+        } else if (response.status === 429) { //
+            const retryAfter = parseInt(response.headers.get('Retry-After') || '0', 10); //
+            setMobileError(retryAfter > 0
+              ? `درخواست کد بیش از حد مجاز است. لطفاً ${retryAfter} ثانیه دیگر دوباره تلاش کنید.`
+              : 'درخواست کد بیش از حد مجاز است. لطفاً بعداً دوباره تلاش کنید.'); //
+        } else if (response.status === 502) { //
+            setMobileError('ارسال پیامک با خطا مواجه شد. لطفاً دوباره تلاش کنید.'); //
+        } else {
+            setMobileError(data.message || 'خطا در ارسال کد تأیید.'); //
+        }
+    } catch (err) {
+        setMobileError('خطا در ارتباط با سرور برای ارسال کد تأیید.'); //
+    } finally {
+        setIsMobileLoading(false); //
+    }
+  };
+
+  const handleVerifyMobile = async (e) => {
+    e.preventDefault(); //
+    setIsMobileLoading(true); //
+    setMobileError(''); //
+    setMobileMessage(''); //
+
+    try {
+        const response = await fetch(`${API_BASE_URL}/account/mobile/verify`, { //
+            method: 'POST', //
+            headers: {
+                'Content-Type': 'application/json', //
+                'Authorization': `Bearer ${getAuthToken()}`, //
+            },
+            body: JSON.stringify({ code: mobileCode }), //
+        });
+        const data = await response.json(); //
+        if (response.ok) { //
+            setVerifiedMobile(data.mobile || newMobile); //
+            setMobileCodeSent(false); //
+            setNewMobile(''); //
+            setMobileCode(''); //
+            setMobileMessage('شماره موبایل با موفقیت تأیید شد.'); //
+            const storedUserData = JSON.parse(localStorage.getItem('userData') || '{}'); //
+            storedUserData.mobile = data.mobile; //
+            storedUserData.mobile_verified_at = data.mobile_verified_at; //
+            localStorage.setItem('userData', JSON.stringify(storedUserData)); //
+        } else if (response.status === 409) { //
+            setMobileError('این شماره موبایل برای حساب دیگری ثبت شده است.'); //
+        } else if (response.status === 429) { //
+            setMobileCodeSent(false); //
+            setMobileError('تعداد تلاش‌های نادرست بیش از حد مجاز است. لطفاً کد جدید درخواست کنید.'); //
+        } else {
+            setMobileError(data.message || 'کد تأیید نادرست یا منقضی شده است.'); //
+        }
+    } catch (err) {
+        setMobileError('خطا در ارتباط با سرور برای تأیید شماره موبایل.'); //
+    } finally {
+        setIsMobileLoading(false); //
+    }
+  };
+
   const handleDisable2FA = async (e) => {
     e.preventDefault(); //
     setIsTwoFALoading(true); //
@@ -518,6 +609,62 @@ function AccountManagementPage() {
               </section>
             </div>
             
+            {/* بخش شماره موبایل */}
+            <section className="account-section">
+              <h2 className="section-title"><FaMobileAlt className="section-icon" /> شماره موبایل</h2>
+              {mobileMessage && <div className="message-banner success visible">{mobileMessage}</div>}
+              {mobileError && <div className="message-banner error visible">{mobileError}</div>}
+              <p>
+                {verifiedMobile
+                  ? <>شماره تأییدشده: <strong style={{ direction: 'ltr', display: 'inline-block' }}>{verifiedMobile}</strong></>
+                  : 'هنوز شماره موبایلی برای حساب شما تأیید نشده است. با تأیید شماره، امکان ورود با کد پیامکی فعال می‌شود.'}
+              </p>
+
+              {!mobileCodeSent ? (
+                <form onSubmit={handleRequestMobileCode} className="auth-form no-shadow">
+                  <div className="form-group">
+                    <label htmlFor="newMobile">{verifiedMobile ? 'شماره موبایل جدید' : 'شماره موبایل'}</label>
+                    <input
+                      type="tel"
+                      id="newMobile"
+                      className="form-control"
+                      value={newMobile}
+                      onChange={(e) => setNewMobile(e.target.value)}
+                      placeholder="09123456789"
+                      style={{ direction: 'ltr' }}
+                      required
+                    />
+                  </div>
+                  <button type="submit" className="auth-button" disabled={isMobileLoading}>
+                    {isMobileLoading ? <FaSpinner className="spinner-sm" /> : 'ارسال کد تأیید'}
+                  </button>
+                </form>
+              ) : (
+                <form onSubmit={handleVerifyMobile} className="auth-form no-shadow totp-form">
+                  <div className="form-group">
+                    <label htmlFor="mobileCode">کد ارسال‌شده به {newMobile}</label>
+                    <input
+                      type="text"
+                      id="mobileCode"
+                      className="form-control"
+                      value={mobileCode}
+                      onChange={(e) => setMobileCode(e.target.value)}
+                      placeholder="کد ۶ رقمی"
+                      maxLength={6}
+                      required
+                      style={{textAlign: 'center', letterSpacing: '0.2em'}}
+                    />
+                  </div>
+                  <button type="submit" className="auth-button" disabled={isMobileLoading}>
+                    {isMobileLoading ? <FaSpinner className="spinner-sm" /> : 'تأیید شماره موبایل'}
+                  </button>
+                  <button type="button" onClick={() => { setMobileCodeSent(false); setMobileCode(''); }} className="auth-button minimal" style={{marginTop:'10px', backgroundColor:'transparent', color:'var(--primary-color)'}}>
+                    تغییر شماره
+                  </button>
+                </form>
+              )}
+            </section>
+
             {/* 2FA Section remains below and at full width */}
             <section className="account-section last-section">
               <h2 className="section-title"><FaUserShield className="section-icon" /> مدیریت تایید دو مرحله‌ای (2FA)</h2>
@@ -6,10 +6,10 @@ import loginPageImage from '../assets/login.jpg';
 import {
   FaInstagram, FaTelegramPlane, FaWhatsapp, FaHeart,
   FaUserPlus, FaSignInAlt, FaUserCircle, FaEnvelope, FaLock, FaEye, FaEyeSlash,
-  FaKey, FaShieldAlt, FaTimes, FaCheckCircle
+  FaKey, FaShieldAlt, FaTimes, FaCheckCircle, FaMobileAlt, FaSms
 } from 'react-icons/fa';
 import Portal from '../components/Portal';
-import { useAuth } from '../context/AuthContext'; // ایمپورت کردن useAuth
+import { useAuth, storeSession } from '../context/AuthContext'; // ایمپورت کردن useAuth
 
 const API_BASE_URL = import.meta.env.VITE_API_BASE_URL || 'http://localhost:8080/api/v1';
 const APP_VERSION = "0.0.4 beta";
@@ -63,6 +63,12 @@ function LoginPage() {
   const [isLoading, setIsLoading] = useState(false);
   const [isPasswordModalOpen, setIsPasswordModalOpen] = useState(false);
   const [hoveredSocial, setHoveredSocial] = useState(null);
+  // ورود با کد پیامکی به جای رمز عبور؛ فقط برای کاربرانی که شماره موبایل خود را تأیید کرده‌اند
+  const [loginMethod, setLoginMethod] = useState('password');
+  const [mobile, setMobile] = useState('');
+  const [otpCode, setOtpCode] = useState('');
+  const [otpSent, setOtpSent] = useState(false);
+  const [resendIn, setResendIn] = useState(0);
 
   const navigate = useNavigate();
   const { login } = useAuth(); // استفاده از تابع login از AuthContext
@@ -74,6 +80,12 @@ function LoginPage() {
     }
   }, [navigate]);
 
+  useEffect(() => {
+    if (resendIn <= 0) return undefined;
+    const timer = setTimeout(() => setResendIn(resendIn - 1), 1000);
+    return () => clearTimeout(timer);
+  }, [resendIn]);
+
   const handleSocialMouseEnter = (platform) => setHoveredSocial(platform);
   const handleSocialMouseLeave = () => setHoveredSocial(null);
 
@@ -142,14 +154,13 @@ function LoginPage() {
         if (data.two_fa_required) {
           // سرور درخواست کد 2FA دارد
           setSuccessMessage('رمز عبور صحیح است. کد تایید دو مرحله‌ای خود را وارد کنید.');
-          // شناسه کاربر را برای استفاده در مرحله بعد ذخیره می‌کنیم
-          localStorage.setItem('2fa_user_id', data.user_id);
+          // توکن موقت چالش را برای مرحله بعد ذخیره می‌کنیم (تا چند دقیقه معتبر است)
+          sessionStorage.setItem('2fa_challenge_token', data.challenge_token);
           // کاربر را به صفحه ورود کد 2FA هدایت می‌کنیم
           navigate('/2fa-verify');
         } else if (data.token && data.user) {
           // لاگین موفق برای کاربری که 2FA ندارد یا آن را غیرفعال کرده
-          localStorage.setItem('authToken', data.token);
-          localStorage.setItem('userData', JSON.stringify(data.user));
+          storeSession(data);
           login(); // به‌روزرسانی AuthContext
           setSuccessMessage('ورود با موفقیت انجام شد. در حال انتقال به داشبورد...');
           setTimeout(() => navigate('/dashboard'), 1500);
@@ -167,8 +178,92 @@ function LoginPage() {
   };
   // ***** END: تابع اصلاح شده handleSubmit *****
 
+  // پاسخ درخواست کد برای شماره‌های ثبت‌نشده هم موفق است تا وجود حساب لو نرود
+  const handleRequestOtp = async () => {
+    clearMessages();
+    if (!mobile.trim()) {
+      setError('شماره موبایل را وارد کنید.');
+      return;
+    }
+    setIsLoading(true);
+    try {
+      const response = await fetch(`${API_BASE_URL}/auth/otp/request`, {
+        method: 'POST',
+        headers: { 'Content-Type': 'application/json' },
+        body: JSON.stringify({ mobile }),
+      });
+      const data = await response.json();
+      if (response.status === 429) {
+        const retryAfter = Number(response.headers.get('Retry-After')) || 60;
+        setResendIn(retryAfter);
+        throw new Error(`درخواست‌های زیادی ثبت شده است. ${retryAfter} ثانیه دیگر دوباره تلاش کنید.`);
+      }
+      if (!response.ok) {
+        throw new Error(response.status === 400 ? 'شماره موبایل معتبر نیست.' : (data.message || 'خطا در ارسال کد.'));
+      }
+      setOtpSent(true);
+      setResendIn(60);
+      setSuccessMessage(data.message || 'کد ورود پیامک شد.');
+    } catch (err) {
+      setError(err.message);
+    } finally {
+      setIsLoading(false);
+    }
+  };
+
+  const handleVerifyOtp = async (e) => {
+    e.preventDefault();
+    clearMessages();
+    if (!/^\d{6}$/.test(otpCode)) {
+      setError('لطفاً کد ۶ رقمی پیامک‌شده را وارد کنید.');
+      return;
+    }
+    setIsLoading(true);
+    try {
+      const response = await fetch(`${API_BASE_URL}/auth/otp/verify`, {
+        method: 'POST',
+        headers: { 'Content-Type': 'application/json' },
+        body: JSON.stringify({ mobile, code: otpCode }),
+      });
+      const data = await response.json();
+      if (!response.ok) {
+        if (response.status === 401) throw new Error('کد وارد شده نادرست یا منقضی است.');
+        if (response.status === 429) {
+          // کد باطل شده است و باید کد تازه‌ای درخواست شود
+          setOtpSent(false);
+          setOtpCode('');
+          throw new Error('تلاش‌های ناموفق زیاد بود. کمی بعد کد تازه‌ای درخواست کنید.');
+        }
+        throw new Error(data.message || 'ورود ناموفق بود.');
+      }
+      if (data.two_fa_required) {
+        sessionStorage.setItem('2fa_challenge_token', data.challenge_token);
+        navigate('/2fa-verify');
+      } else if (data.token && data.user) {
+        storeSession(data);
+        login();
+        setSuccessMessage('ورود با موفقیت انجام شد. در حال انتقال به داشبورد...');
+        setTimeout(() => navigate('/dashboard'), 1500);
+      } else {
+        throw new Error('Received an unexpected response from the server.');
+      }
+    } catch (err) {
+      setError(err.message);
+    } finally {
+      setIsLoading(false);
+    }
+  };
+
+  const toggleLoginMethod = () => {
+    setLoginMethod(loginMethod === 'password' ? 'sms' : 'password');
+    clearMessages();
+    setOtpSent(false);
+    setOtpCode('');
+  };
+
   const toggleForm = () => {
     setIsLogin(!isLogin);
+    setLoginMethod('password');
     clearMessages();
     setUsername('');
     setEmail('');
@@ -190,6 +285,69 @@ function LoginPage() {
 
   const handleClosePasswordModal = () => setIsPasswordModalOpen(false);
 
+  const renderOtpForm = () => (
+    <form onSubmit={handleVerifyOtp} className="auth-form">
+      <div className="form-group">
+        <label htmlFor="login-mobile"><FaMobileAlt /> شماره موبایل</label>
+        <input
+          type="tel"
+          id="login-mobile"
+          className="form-control"
+          placeholder="مثلاً ۰۹۱۲۳۴۵۶۷۸۹"
+          value={mobile}
+          onChange={(e) => setMobile(e.target.value)}
+          disabled={otpSent}
+          dir="ltr"
+          required
+        />
+      </div>
+
+      {otpSent && (
+        <div className="form-group">
+          <label htmlFor="login-otp"><FaSms /> کد پیامک‌شده</label>
+          <input
+            type="text"
+            id="login-otp"
+            className="form-control"
+            inputMode="numeric"
+            autoComplete="one-time-code"
+            maxLength={6}
+            placeholder="کد ۶ رقمی"
+            value={otpCode}
+            onChange={(e) => setOtpCode(e.target.value.replace(/\D/g, ''))}
+            dir="ltr"
+            required
+          />
+        </div>
+      )}
+
+      {error && <div className="message-banner error visible"><FaTimes style={{ marginLeft: '7px' }} />{error}</div>}
+      {successMessage && <div className="message-banner success visible"><FaCheckCircle style={{ marginLeft: '7px' }} />{successMessage}</div>}
+
+      {otpSent ? (
+        <>
+          <button type="submit" className="auth-button" disabled={isLoading}>
+            {isLoading ? <span className="spinner-sm"></span> : <FaSignInAlt />}
+            {isLoading ? 'در حال ورود...' : 'ورود به حساب کاربری'}
+          </button>
+          <div className="password-options">
+            <button type="button" className="toggle-auth-button" onClick={() => { setOtpSent(false); setOtpCode(''); clearMessages(); }}>
+              تغییر شماره
+            </button>
+            <button type="button" className="toggle-auth-button" onClick={handleRequestOtp} disabled={isLoading || resendIn > 0}>
+              {resendIn > 0 ? `ارسال دوباره تا ${resendIn} ثانیه دیگر` : 'ارسال دوباره کد'}
+            </button>
+          </div>
+        </>
+      ) : (
+        <button type="button" className="auth-button" onClick={handleRequestOtp} disabled={isLoading || resendIn > 0}>
+          {isLoading ? <span className="spinner-sm"></span> : <FaSms />}
+          {resendIn > 0 ? `ارسال کد تا ${resendIn} ثانیه دیگر` : 'دریافت کد ورود'}
+        </button>
+      )}
+    </form>
+  );
+
 
   const renderAuthForm = () => (
     <form onSubmit={handleSubmit} className="auth-form">
@@ -330,7 +488,14 @@ function LoginPage() {
                 : 'با ایجاد حساب کاربری جدید، به تمامی امکانات پیشرفته زرفولیو دسترسی خواهید داشت.'
               }
             </p>
-            {renderAuthForm()}
+            {isLogin && loginMethod === 'sms' ? renderOtpForm() : renderAuthForm()}
+            {isLogin && (
+              <div className="auth-toggle-section">
+                <button type="button" onClick={toggleLoginMethod} className="toggle-auth-button">
+                  {loginMethod === 'password' ? <><FaSms style={{ marginLeft: '5px' }} />ورود با کد پیامکی</> : <><FaLock style={{ marginLeft: '5px' }} />ورود با رمز عبور</>}
+                </button>
+              </div>
+            )}
             <div className="auth-toggle-section">
               {isLogin ? 'هنوز حساب کاربری ندارید؟' : 'قبلاً ثبت نام کرده‌اید؟'}
               <button type="button" onClick={toggleForm} className="toggle-auth-button">
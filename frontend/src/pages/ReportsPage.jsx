@@ -60,6 +60,9 @@ const reportsData = {
     className: 'google-green',
     items: [
       { key: 'event-history', label: 'تاریخچه رویدادها', link: '/settings/logs', icon: <HistoryOutlined /> },
+      { key: 'trial-balance', label: 'تراز آزمایشی', link: '/reports/trial-balance', icon: <FileTextOutlined /> },
+      { key: 'profit-loss', label: 'سود و زیان', link: '/reports/profit-loss', icon: <PieChartOutlined /> },
+      { key: 'balance-sheet', label: 'ترازنامه', link: '/reports/balance-sheet', icon: <BankOutlined /> },
     ],
   },
 };
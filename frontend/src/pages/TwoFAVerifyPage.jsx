@@ -1,6 +1,6 @@
 import React, { useState } from 'react';
 import { useNavigate } from 'react-router-dom';
-import { useAuth } from '../context/AuthContext';
+import { useAuth, storeSession } from '../context/AuthContext';
 import { Button } from 'antd'; // ایمپورت کامپوننت Button از Ant Design
 import { ArrowLeftOutlined } from '@ant-design/icons'; // ایمپورت آیکون از Ant Design
 import './TwoFAVerifyPage.css';
@@ -28,10 +28,10 @@ const TwoFAVerifyPage = () => {
     }
 
     setIsLoading(true);
-    const userId = localStorage.getItem('2fa_user_id');
+    const challengeToken = sessionStorage.getItem('2fa_challenge_token');
 
-    if (!userId) {
-      setError('خطایی رخ داد: شناسه کاربر یافت نشد. لطفاً دوباره وارد شوید.');
+    if (!challengeToken) {
+      setError('نشست تایید دو مرحله‌ای یافت نشد. لطفاً دوباره وارد شوید.');
       setIsLoading(false);
       setTimeout(() => navigate('/login'), 2000);
       return;
@@ -41,20 +41,24 @@ const TwoFAVerifyPage = () => {
       const response = await fetch(`${API_BASE_URL}/auth/2fa/verify`, {
         method: 'POST',
         headers: { 'Content-Type': 'application/json' },
-        body: JSON.stringify({ user_id: userId, totp_code: token }),
+        body: JSON.stringify({ challenge_token: challengeToken, code: token }),
       });
 
       const data = await response.json();
 
       if (!response.ok) {
+        // چالش منقضی یا به دلیل تلاش‌های ناموفق زیاد باطل شده؛ باید دوباره رمز عبور وارد شود
+        if (response.status === 429 || data.details === 'challenge_expired') {
+          sessionStorage.removeItem('2fa_challenge_token');
+          setTimeout(() => navigate('/login'), 2000);
+        }
         throw new Error(data.message || 'کد تایید نامعتبر است.');
       }
 
       if (data.token && data.user) {
         setSuccessMessage('تایید با موفقیت انجام شد. در حال انتقال به داشبورد...');
-        localStorage.setItem('authToken', data.token);
-        localStorage.setItem('userData', JSON.stringify(data.user));
-        localStorage.removeItem('2fa_user_id');
+        storeSession(data);
+        sessionStorage.removeItem('2fa_challenge_token');
         
         login();
 
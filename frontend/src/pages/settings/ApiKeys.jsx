@@ -0,0 +1,161 @@
+import React, { useState, useEffect } from 'react';
+import { Table, Button, Modal, Form, Input, Select, Checkbox, Tag, Typography, Alert, Popconfirm, notification } from 'antd';
+import axios from 'axios';
+
+const { Title, Text, Paragraph } = Typography;
+
+const API_BASE_URL = import.meta.env.VITE_API_BASE_URL || 'http://localhost:8080/api/v1';
+
+const authHeaders = () => ({ headers: { Authorization: `Bearer ${localStorage.getItem('authToken')}` } });
+
+const expiryOptions = [
+    { value: 0, label: 'بدون انقضا' },
+    { value: 30, label: '۳۰ روز' },
+    { value: 90, label: '۹۰ روز' },
+    { value: 365, label: 'یک سال' },
+];
+
+const formatDate = (value) => (value ? new Date(value).toLocaleString('fa-IR') : '-');
+
+const keyStatus = (key) => {
+    if (key.revoked_at) return <Tag color="red">باطل شده</Tag>;
+    if (key.expires_at && new Date(key.expires_at) <= new Date()) return <Tag color="orange">منقضی</Tag>;
+    return <Tag color="green">فعال</Tag>;
+};
+
+// کلیدهای API برای اتصال نرم‌افزارهای دیگر و دستگاه‌های کیوسک؛ کلید کامل فقط یک بار پس از ساخت نمایش داده می‌شود
+const ApiKeys = () => {
+    const [keys, setKeys] = useState([]);
+    const [permissions, setPermissions] = useState([]);
+    const [loading, setLoading] = useState(true);
+    const [modalOpen, setModalOpen] = useState(false);
+    const [saving, setSaving] = useState(false);
+    const [createdKey, setCreatedKey] = useState(null);
+    const [form] = Form.useForm();
+
+    const fetchKeys = async () => {
+        setLoading(true);
+        try {
+            const response = await axios.get(`${API_BASE_URL}/api-keys`, authHeaders());
+            setKeys(response.data.api_keys || []);
+        } catch (error) {
+            notification.error({ message: 'خطا', description: error.response?.data?.message || 'خطا در دریافت کلیدهای API' });
+        } finally {
+            setLoading(false);
+        }
+    };
+
+    useEffect(() => {
+        fetchKeys();
+        axios.get(`${API_BASE_URL}/rbac/permissions`, authHeaders())
+            .then(response => setPermissions(response.data.permissions || []))
+            .catch(() => setPermissions([]));
+    }, []);
+
+    const openCreate = () => {
+        form.resetFields();
+        setCreatedKey(null);
+        setModalOpen(true);
+    };
+
+    const handleCreate = async (values) => {
+        setSaving(true);
+        try {
+            const payload = { name: values.name.trim(), permissions: values.permissions };
+            if (values.businessOnly) {
+                payload.business_id = Number(localStorage.getItem('activeBid'));
+            }
+            if (values.expiryDays) {
+                payload.expires_at = new Date(Date.now() + values.expiryDays * 24 * 60 * 60 * 1000).toISOString();
+            }
+            const response = await axios.post(`${API_BASE_URL}/api-keys`, payload, authHeaders());
+            setCreatedKey(response.data.key);
+            fetchKeys();
+        } catch (error) {
+            const description = error.response?.status === 403
+                ? 'شما یکی از دسترسی‌های انتخاب‌شده را ندارید.'
+                : error.response?.data?.message || 'خطا در ساخت کلید API';
+            notification.error({ message: 'خطا', description });
+        } finally {
+            setSaving(false);
+        }
+    };
+
+    const handleRevoke = async (id) => {
+        try {
+            await axios.delete(`${API_BASE_URL}/api-keys/${id}`, authHeaders());
+            notification.success({ message: 'کلید API باطل شد' });
+            fetchKeys();
+        } catch (error) {
+            notification.error({ message: 'خطا', description: error.response?.data?.message || 'خطا در ابطال کلید API' });
+        }
+    };
+
+    const columns = [
+        { title: 'نام', dataIndex: 'name', key: 'name', align: 'center' },
+        { title: 'شناسه', dataIndex: 'prefix', key: 'prefix', align: 'center', render: v => <Text code dir="ltr">{v}…</Text> },
+        { title: 'دسترسی‌ها', dataIndex: 'permissions', key: 'permissions', align: 'center', render: perms => (perms || []).map(p => <Tag key={p}>{p}</Tag>) },
+        { title: 'کسب‌وکار', dataIndex: 'business_id', key: 'business_id', align: 'center', render: v => v || 'همه' },
+        { title: 'انقضا', dataIndex: 'expires_at', key: 'expires_at', align: 'center', render: formatDate },
+        { title: 'آخرین استفاده', dataIndex: 'last_used_at', key: 'last_used_at', align: 'center', render: (v, r) => (v ? `${formatDate(v)} (${r.last_used_ip})` : '-') },
+        { title: 'وضعیت', key: 'status', align: 'center', render: (_, r) => keyStatus(r) },
+        {
+            title: 'عملیات', key: 'actions', align: 'center', render: (_, r) => (r.revoked_at ? null : (
+                <Popconfirm title="این کلید بلافاصله از کار می‌افتد. ادامه می‌دهید؟" okText="ابطال" cancelText="انصراف" onConfirm={() => handleRevoke(r.id)}>
+                    <Button danger size="small">ابطال</Button>
+                </Popconfirm>
+            )),
+        },
+    ];
+
+    return (
+        <div>
+            <Title level={4}>کلیدهای API</Title>
+            <Paragraph type="secondary">
+                هر کلید به جای سازنده خود و فقط با دسترسی‌های انتخاب‌شده کار می‌کند. کلید را در سربرگ Authorization به صورت Bearer یا در سربرگ X-API-Key بفرستید.
+            </Paragraph>
+            <Button type="primary" onClick={openCreate} style={{ marginBottom: 16 }}>ساخت کلید جدید</Button>
+            <Table columns={columns} dataSource={keys} rowKey="id" loading={loading} bordered />
+
+            <Modal
+                title="ساخت کلید API"
+                open={modalOpen}
+                onCancel={() => setModalOpen(false)}
+                footer={createdKey ? <Button onClick={() => setModalOpen(false)}>بستن</Button> : undefined}
+                onOk={() => form.submit()}
+                okText="ساخت"
+                cancelText="انصراف"
+                confirmLoading={saving}
+                destroyOnClose
+            >
+                {createdKey ? (
+                    <>
+                        <Alert type="warning" showIcon style={{ marginBottom: 16 }} message="این کلید فقط همین یک بار نمایش داده می‌شود. آن را اکنون کپی و در جای امنی نگه دارید." />
+                        <Paragraph copyable code dir="ltr">{createdKey}</Paragraph>
+                    </>
+                ) : (
+                    <Form form={form} layout="vertical" onFinish={handleCreate} initialValues={{ expiryDays: 90, businessOnly: true }}>
+                        <Form.Item name="name" label="نام کلید" rules={[{ required: true, whitespace: true, message: 'نام کلید را وارد کنید' }, { max: 64, message: 'نام حداکثر ۶۴ نویسه است' }]}>
+                            <Input placeholder="مثلاً کیوسک ویترین" />
+                        </Form.Item>
+                        <Form.Item name="permissions" label="دسترسی‌ها" rules={[{ required: true, message: 'حداقل یک دسترسی انتخاب کنید' }]}>
+                            <Select
+                                mode="multiple"
+                                placeholder="دسترسی‌های کلید"
+                                options={permissions.map(p => ({ value: p.name, label: p.description ? `${p.name} - ${p.description}` : p.name }))}
+                            />
+                        </Form.Item>
+                        <Form.Item name="expiryDays" label="مدت اعتبار">
+                            <Select options={expiryOptions} />
+                        </Form.Item>
+                        <Form.Item name="businessOnly" valuePropName="checked">
+                            <Checkbox>فقط برای کسب‌وکار فعلی</Checkbox>
+                        </Form.Item>
+                    </Form>
+                )}
+            </Modal>
+        </div>
+    );
+};
+
+export default ApiKeys;
@@ -1,62 +1,138 @@
 import React, { useState, useEffect } from 'react';
-import { Table, Input, Spin, Typography } from 'antd';
+import { Table, Input, Select, Checkbox, Button, Space, Tag, Typography, Alert, notification } from 'antd';
 import axios from 'axios';
 
-const { Title } = Typography;
+const { Title, Text } = Typography;
 const { Search } = Input;
 
+const API_BASE_URL = import.meta.env.VITE_API_BASE_URL || 'http://localhost:8080/api/v1';
+
+const authHeaders = () => ({ headers: { Authorization: `Bearer ${localStorage.getItem('authToken')}` } });
+
+const categories = [
+    { value: 'auth', label: 'ورود و خروج' },
+    { value: 'rbac', label: 'نقش‌ها و دسترسی‌ها' },
+    { value: 'customer', label: 'اشخاص' },
+    { value: 'price', label: 'نرخ طلا' },
+    { value: 'invoice', label: 'فاکتورها' },
+];
+
+const categoryLabel = (value) => categories.find(c => c.value === value)?.label || value;
+
 const LogsViewer = () => {
     const [logs, setLogs] = useState([]);
+    const [total, setTotal] = useState(0);
+    const [page, setPage] = useState(1);
+    const [pageSize, setPageSize] = useState(20);
+    const [query, setQuery] = useState('');
+    const [category, setCategory] = useState();
+    // رویدادهای ورود و نقش‌ها به کسب‌وکار خاصی تعلق ندارند و فقط با برداشتن این گزینه دیده می‌شوند
+    const [businessOnly, setBusinessOnly] = useState(true);
     const [loading, setLoading] = useState(true);
-    const [searchText, setSearchText] = useState('');
+    const [chainReport, setChainReport] = useState(null);
+    const [verifying, setVerifying] = useState(false);
 
     useEffect(() => {
         const fetchLogs = async () => {
             setLoading(true);
             try {
-                const response = await axios.post('/api/business/logs/' + localStorage.getItem('activeBid'));
-                setLogs(response.data);
+                const params = { page, pageSize, q: query || undefined, category };
+                if (businessOnly) {
+                    params.bidId = localStorage.getItem('activeBid');
+                }
+                const response = await axios.get(`${API_BASE_URL}/audit/events`, { ...authHeaders(), params });
+                setLogs(response.data.items || []);
+                setTotal(response.data.total || 0);
             } catch (error) {
-                console.error('Error fetching logs:', error);
+                notification.error({ message: 'خطا', description: 'خطا در دریافت تاریخچه رویدادها' });
             } finally {
                 setLoading(false);
             }
         };
         fetchLogs();
-    }, []);
+    }, [page, pageSize, query, category, businessOnly]);
+
+    const verifyChain = async () => {
+        setVerifying(true);
+        try {
+            const response = await axios.get(`${API_BASE_URL}/audit/verify`, authHeaders());
+            setChainReport(response.data);
+        } catch (error) {
+            notification.error({ message: 'خطا', description: 'خطا در بررسی یکپارچگی تاریخچه' });
+        } finally {
+            setVerifying(false);
+        }
+    };
 
     const columns = [
-        { title: 'تاریخ', dataIndex: 'date', key: 'date', align: 'center' },
-        { title: 'کاربر', dataIndex: 'user', key: 'user', align: 'center' },
-        { title: 'توضیحات', dataIndex: 'des', key: 'des', align: 'center' },
-        { title: 'بخش', dataIndex: 'part', key: 'part', align: 'center' },
-        { title: 'آی پی آدرس', dataIndex: 'ipaddress', key: 'ipaddress', align: 'center' },
+        { title: 'تاریخ', dataIndex: 'occurredAt', key: 'occurredAt', align: 'center', render: v => new Date(v).toLocaleString('fa-IR') },
+        { title: 'کاربر', dataIndex: 'actorName', key: 'actorName', align: 'center', render: (v, r) => v || r.actorId || '-' },
+        { title: 'توضیحات', dataIndex: 'description', key: 'description', align: 'center' },
+        { title: 'بخش', dataIndex: 'category', key: 'category', align: 'center', render: v => <Tag>{categoryLabel(v)}</Tag> },
+        { title: 'آی پی آدرس', dataIndex: 'ipAddress', key: 'ipAddress', align: 'center' },
     ];
 
-    const filteredLogs = logs.filter(log =>
-        Object.values(log).some(val =>
-            String(val).toLowerCase().includes(searchText.toLowerCase())
-        )
+    const renderDetails = (record) => (
+        <Space direction="vertical" style={{ width: '100%' }}>
+            <Text type="secondary">شناسه درخواست: {record.correlationId}</Text>
+            <Text type="secondary">مرورگر: {record.userAgent}</Text>
+            {record.before && <pre dir="ltr">{'قبل:\n' + JSON.stringify(record.before, null, 2)}</pre>}
+            {record.after && <pre dir="ltr">{'بعد:\n' + JSON.stringify(record.after, null, 2)}</pre>}
+        </Space>
     );
 
     return (
         <div>
             <Title level={4}>تاریخچه رویدادها</Title>
-            <Search
-                placeholder="جستجو در رویدادها..."
-                onChange={e => setSearchText(e.target.value)}
-                style={{ marginBottom: 16 }}
-            />
-            {loading ? <Spin /> : (
-                <Table
-                    columns={columns}
-                    dataSource={filteredLogs}
-                    rowKey={(record, index) => index} // Assuming logs don't have a unique ID
-                    bordered
+            <Space wrap style={{ marginBottom: 16 }}>
+                <Search
+                    placeholder="جستجو در رویدادها..."
+                    allowClear
+                    onSearch={value => { setPage(1); setQuery(value.trim()); }}
+                    style={{ width: 280 }}
+                />
+                <Select
+                    placeholder="همه بخش‌ها"
+                    allowClear
+                    options={categories}
+                    value={category}
+                    onChange={value => { setPage(1); setCategory(value); }}
+                    style={{ width: 180 }}
+                />
+                <Checkbox checked={businessOnly} onChange={e => { setPage(1); setBusinessOnly(e.target.checked); }}>
+                    فقط رویدادهای این کسب‌وکار
+                </Checkbox>
+                <Button onClick={verifyChain} loading={verifying}>بررسی یکپارچگی</Button>
+            </Space>
+            {chainReport && (
+                <Alert
+                    style={{ marginBottom: 16 }}
+                    type={chainReport.valid ? 'success' : 'error'}
+                    showIcon
+                    closable
+                    onClose={() => setChainReport(null)}
+                    message={chainReport.valid
+                        ? `${chainReport.checked} رویداد بررسی شد و تاریخچه دست‌نخورده است.`
+                        : `تاریخچه از رویداد شماره ${chainReport.brokenAtSeq} دستکاری شده است.`}
                 />
             )}
+            <Table
+                columns={columns}
+                dataSource={logs}
+                rowKey="id"
+                loading={loading}
+                expandable={{ expandedRowRender: renderDetails }}
+                pagination={{
+                    current: page,
+                    pageSize,
+                    total,
+                    showSizeChanger: true,
+                    onChange: (p, size) => { setPage(size !== pageSize ? 1 : p); setPageSize(size); },
+                }}
+                bordered
+            />
         </div>
     );
 };
 
-export default LogsViewer;
\ No newline at end of file
+export default LogsViewer;
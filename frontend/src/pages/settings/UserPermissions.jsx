@@ -1,76 +1,132 @@
-import React, { useState, useEffect } from 'react';
-import { Switch, Row, Col, Card, Typography, notification, Spin } from 'antd';
+import React, { useState, useEffect, useMemo } from 'react';
+import { Radio, Row, Col, Card, Typography, Tag, Button, Space, notification, Spin } from 'antd';
 import axios from 'axios';
 import { useParams } from 'react-router-dom';
+import { groupPermissions } from './UserRolls.jsx';
 
 const { Title, Text } = Typography;
 
+const API_BASE_URL = import.meta.env.VITE_API_BASE_URL || 'http://localhost:8080/api/v1';
+
+const authHeaders = () => ({ headers: { Authorization: `Bearer ${localStorage.getItem('authToken')}` } });
+
+// هر مجوز یکی از سه حالت را دارد: از نقش‌ها (inherit)، اعطای مستقیم (grant) یا منع مستقیم (deny)
+const toOverrideMap = (data) => {
+    const overrides = {};
+    (data.grant || []).forEach(perm => { overrides[perm] = 'grant'; });
+    (data.deny || []).forEach(perm => { overrides[perm] = 'deny'; });
+    return overrides;
+};
+
 const UserPermissions = () => {
-    const [permissions, setPermissions] = useState({});
+    const { userId } = useParams();
+    const [userPermissions, setUserPermissions] = useState(null);
+    const [permissions, setPermissions] = useState([]);
+    const [overrides, setOverrides] = useState({});
     const [loading, setLoading] = useState(true);
-    const { email } = useParams(); // Get email from URL
+    const [saving, setSaving] = useState(false);
 
     useEffect(() => {
         const fetchPermissions = async () => {
             setLoading(true);
             try {
-                const response = await axios.post('/api/business/get/user/permissions', {
-                    'bid': localStorage.getItem('activeBid'),
-                    'email': email
-                });
-                setPermissions(response.data);
+                const [userRes, permsRes] = await Promise.all([
+                    axios.get(`${API_BASE_URL}/rbac/users/${encodeURIComponent(userId)}/permissions`, authHeaders()),
+                    axios.get(`${API_BASE_URL}/rbac/permissions`, authHeaders()),
+                ]);
+                setUserPermissions(userRes.data);
+                setOverrides(toOverrideMap(userRes.data));
+                setPermissions(permsRes.data.permissions || []);
             } catch (error) {
-                notification.error({ message: 'خطا', description: 'خطا در دریافت دسترسی‌ها' });
+                notification.error({ message: 'خطا', description: error.response?.data?.message || 'خطا در دریافت دسترسی‌ها' });
             } finally {
                 setLoading(false);
             }
         };
         fetchPermissions();
-    }, [email]);
+    }, [userId]);
+
+    const permissionGroups = useMemo(() => groupPermissions(permissions), [permissions]);
+    const effective = useMemo(() => new Set(userPermissions?.effective || []), [userPermissions]);
 
-    const handlePermissionChange = async (key, value) => {
-        const updatedPermissions = { ...permissions, [key]: value };
-        setPermissions(updatedPermissions);
-        
+    const handleChange = (perm, value) => {
+        setOverrides(prev => {
+            const next = { ...prev };
+            if (value === 'inherit') {
+                delete next[perm];
+            } else {
+                next[perm] = value;
+            }
+            return next;
+        });
+    };
+
+    const handleSave = async () => {
+        const entries = Object.entries(overrides);
+        setSaving(true);
         try {
-            await axios.post('/api/business/save/user/permissions', {
-                ...updatedPermissions,
-                email: email,
-                bid: localStorage.getItem('activeBid')
-            });
-            notification.success({ message: 'موفق', description: `دسترسی ${key} به‌روزرسانی شد.` });
+            const response = await axios.put(
+                `${API_BASE_URL}/rbac/users/${encodeURIComponent(userId)}/permissions`,
+                {
+                    grant: entries.filter(([, effect]) => effect === 'grant').map(([perm]) => perm),
+                    deny: entries.filter(([, effect]) => effect === 'deny').map(([perm]) => perm),
+                },
+                authHeaders(),
+            );
+            setUserPermissions(response.data);
+            setOverrides(toOverrideMap(response.data));
+            notification.success({ message: 'موفق', description: 'دسترسی‌های کاربر ذخیره شد.' });
         } catch (error) {
-            notification.error({ message: 'خطا', description: 'خطا در ذخیره دسترسی' });
-            // Revert change on error
-            setPermissions(prev => ({ ...prev, [key]: !value }));
+            notification.error({ message: 'خطا', description: error.response?.data?.message || 'خطا در ذخیره دسترسی' });
+        } finally {
+            setSaving(false);
         }
     };
 
     if (loading) {
         return <Spin size="large" />;
     }
+    if (!userPermissions) {
+        return null;
+    }
 
     return (
         <div>
-            <Title level={4}>تنظیم دسترسی‌های کاربر: {permissions.user}</Title>
-            <Text type="secondary">{permissions.email}</Text>
-            <Row gutter={[16, 16]} style={{ marginTop: 24 }}>
-                {Object.keys(permissions)
-                    .filter(key => typeof permissions[key] === 'boolean')
-                    .map(key => (
-                        <Col xs={24} sm={12} md={8} key={key}>
-                            <Card>
-                                <Switch
-                                    checked={permissions[key]}
-                                    onChange={(checked) => handlePermissionChange(key, checked)}
-                                />
-                                <span style={{ marginLeft: 8 }}>{key}</span>
-                            </Card>
-                        </Col>
-                ))}
-            </Row>
+            <Space style={{ width: '100%', justifyContent: 'space-between' }}>
+                <Title level={4} style={{ margin: 0 }}>تنظیم دسترسی‌های کاربر</Title>
+                <Button type="primary" loading={saving} onClick={handleSave}>ذخیره</Button>
+            </Space>
+            <Space style={{ marginTop: 8 }} wrap>
+                <Text type="secondary">نقش‌ها:</Text>
+                {userPermissions.roles.map(role => <Tag color="blue" key={role}>{role}</Tag>)}
+            </Space>
+            {Object.entries(permissionGroups).map(([resource, perms]) => (
+                <Card key={resource} title={resource} size="small" style={{ marginTop: 16 }}>
+                    <Row gutter={[16, 12]}>
+                        {perms.map(perm => (
+                            <Col xs={24} md={12} key={perm.name}>
+                                <Space direction="vertical" size={2}>
+                                    <Space>
+                                        <Text title={perm.description}>{perm.name}</Text>
+                                        {effective.has(perm.name) ? <Tag color="green">فعال</Tag> : <Tag>غیرفعال</Tag>}
+                                    </Space>
+                                    <Radio.Group
+                                        size="small"
+                                        value={overrides[perm.name] || 'inherit'}
+                                        onChange={(e) => handleChange(perm.name, e.target.value)}
+                                    >
+                                        <Radio.Button value="inherit">طبق نقش</Radio.Button>
+                                        <Radio.Button value="grant">اعطا</Radio.Button>
+                                        <Radio.Button value="deny">منع</Radio.Button>
+                                    </Radio.Group>
+                                </Space>
+                            </Col>
+                        ))}
+                    </Row>
+                </Card>
+            ))}
         </div>
     );
 };
 
-export default UserPermissions;
\ No newline at end of file
+export default UserPermissions;
@@ -1,154 +1,204 @@
-import React, { useState, useEffect } from 'react';
-import { Table, Button, Modal, Form, Input, Space, Typography, Tag, notification } from 'antd';
+import React, { useState, useEffect, useMemo } from 'react';
+import { Table, Button, Modal, Form, Input, Space, Typography, Tag, Checkbox, Row, Col, notification } from 'antd';
 import axios from 'axios';
+import { useNavigate } from 'react-router-dom';
 
-const { Title } = Typography;
+const { Title, Text } = Typography;
+
+const API_BASE_URL = import.meta.env.VITE_API_BASE_URL || 'http://localhost:8080/api/v1';
+
+const authHeaders = () => ({ headers: { Authorization: `Bearer ${localStorage.getItem('authToken')}` } });
+
+// groupPermissions مجوزها را بر اساس پیشوند منبع (inventory، crm، ...) دسته‌بندی می‌کند
+export const groupPermissions = (permissions) =>
+    permissions.reduce((groups, perm) => {
+        const resource = perm.name.split(':')[0];
+        (groups[resource] = groups[resource] || []).push(perm);
+        return groups;
+    }, {});
 
 const UserRolls = () => {
-    const [users, setUsers] = useState([]);
+    const [roles, setRoles] = useState([]);
+    const [permissions, setPermissions] = useState([]);
     const [loading, setLoading] = useState(true);
-    const [isModalVisible, setIsModalVisible] = useState(false);
-    const [userToDelete, setUserToDelete] = useState(null);
+    const [editingRole, setEditingRole] = useState(null);
+    const [isEditorVisible, setIsEditorVisible] = useState(false);
+    const [roleToDelete, setRoleToDelete] = useState(null);
     const [form] = Form.useForm();
+    const [userForm] = Form.useForm();
+    const navigate = useNavigate();
 
-    const fetchUsers = async () => {
+    const fetchData = async () => {
         setLoading(true);
         try {
-            const response = await axios.post('/api/user/get/users/of/business/' + localStorage.getItem('activeBid'));
-            setUsers(Array.isArray(response.data) ? response.data : []);
+            const [rolesRes, permsRes] = await Promise.all([
+                axios.get(`${API_BASE_URL}/rbac/roles`, authHeaders()),
+                axios.get(`${API_BASE_URL}/rbac/permissions`, authHeaders()),
+            ]);
+            setRoles(rolesRes.data.roles || []);
+            setPermissions(permsRes.data.permissions || []);
         } catch (error) {
-            notification.error({
-                message: 'خطا',
-                description: 'خطا در دریافت لیست کاربران',
-            });
+            notification.error({ message: 'خطا', description: 'خطا در دریافت نقش‌ها و دسترسی‌ها' });
         } finally {
             setLoading(false);
         }
     };
 
     useEffect(() => {
-        fetchUsers();
+        fetchData();
     }, []);
 
-    const handleAddUser = async (values) => {
+    const permissionGroups = useMemo(() => groupPermissions(permissions), [permissions]);
+
+    const openEditor = (role) => {
+        setEditingRole(role);
+        form.setFieldsValue({
+            name: role?.name || '',
+            description: role?.description || '',
+            permissions: role?.permissions || [],
+        });
+        setIsEditorVisible(true);
+    };
+
+    const handleSave = async (values) => {
         try {
-            const response = await axios.post("/api/business/add/user", {
-                'bid': localStorage.getItem('activeBid'),
-                'email': values.email
-            });
-            if (response.data.result === 0) {
-                notification.error({ message: 'خطا', description: 'کاربری با این پست الکترونیکی یافت نشد.' });
-            } else if (response.data.result === 1) {
-                notification.warning({ message: 'توجه', description: 'قبلا این کاربر به کسب و کار افزوده شده است.' });
+            if (editingRole) {
+                await axios.put(`${API_BASE_URL}/rbac/roles/${encodeURIComponent(editingRole.name)}`, values, authHeaders());
+                notification.success({ message: 'موفق', description: 'نقش به‌روزرسانی شد. تغییرات بدون نیاز به ورود دوباره اعمال می‌شوند.' });
             } else {
-                setUsers([...users, response.data.data]);
-                notification.success({ message: 'موفق', description: 'کاربر با موفقیت عضو کسب و کار شد.' });
-                form.resetFields();
+                await axios.post(`${API_BASE_URL}/rbac/roles`, values, authHeaders());
+                notification.success({ message: 'موفق', description: 'نقش جدید ایجاد شد.' });
             }
+            setIsEditorVisible(false);
+            fetchData();
         } catch (error) {
-            notification.error({ message: 'خطا', description: 'خطا در افزودن کاربر' });
+            notification.error({ message: 'خطا', description: error.response?.data?.message || 'خطا در ذخیره نقش' });
         }
     };
 
-    const showDeleteModal = (user) => {
-        setUserToDelete(user);
-        setIsModalVisible(true);
-    };
-
     const handleDelete = async () => {
         try {
-            await axios.post('/api/business/delete/user', {
-                'bid': localStorage.getItem('activeBid'),
-                'email': userToDelete.email
-            });
-            setUsers(users.filter(user => user.email !== userToDelete.email));
-            notification.success({ message: 'موفق', description: 'کاربر با موفقیت حذف شد.' });
+            await axios.delete(`${API_BASE_URL}/rbac/roles/${encodeURIComponent(roleToDelete.name)}`, authHeaders());
+            setRoles(roles.filter(role => role.name !== roleToDelete.name));
+            notification.success({ message: 'موفق', description: 'نقش حذف شد.' });
         } catch (error) {
-            notification.error({ message: 'خطا', description: 'خطا در حذف کاربر' });
+            notification.error({ message: 'خطا', description: error.response?.data?.message || 'خطا در حذف نقش' });
         } finally {
-            setIsModalVisible(false);
-            setUserToDelete(null);
+            setRoleToDelete(null);
         }
     };
 
     const columns = [
         {
-            title: '#',
-            key: 'index',
-            render: (text, record, index) => index + 1,
-        },
-        {
-            title: 'نام / نام خانوادگی',
+            title: 'نقش',
             dataIndex: 'name',
             key: 'name',
+            render: (name, record) => (
+                <Space>
+                    <Tag color="blue">{name}</Tag>
+                    {record.is_system && <Tag color="gold">پیش‌فرض</Tag>}
+                </Space>
+            ),
         },
         {
-            title: 'پست الکترونیکی',
-            dataIndex: 'email',
-            key: 'email',
-            render: email => <Tag color="blue">{email}</Tag>,
+            title: 'توضیحات',
+            dataIndex: 'description',
+            key: 'description',
         },
         {
-            title: 'شماره موبایل',
-            dataIndex: 'mobile',
-            key: 'mobile',
-            render: mobile => mobile || '-',
+            title: 'تعداد دسترسی‌ها',
+            key: 'count',
+            render: (text, record) => (record.permissions || []).length,
         },
         {
             title: 'عملیات',
             key: 'actions',
             render: (text, record) => (
-                record.owner !== 1 ? (
-                    <Space size="middle">
-                        <Button type="primary" ghost href={`/path/to/edit/user/${record.email}`}>ویرایش دسترسی</Button>
-                        <Button type="primary" danger ghost onClick={() => showDeleteModal(record)}>حذف</Button>
-                    </Space>
-                ) : (
-                    <Tag color="success">مدیر کل</Tag>
-                )
+                <Space size="middle">
+                    <Button type="primary" ghost onClick={() => openEditor(record)}>ویرایش دسترسی</Button>
+                    {!record.is_system && (
+                        <Button type="primary" danger ghost onClick={() => setRoleToDelete(record)}>حذف</Button>
+                    )}
+                </Space>
             ),
         },
     ];
 
     return (
         <div>
-            <Title level={4}>افزودن کاربر جدید</Title>
-            <Form form={form} layout="inline" onFinish={handleAddUser} style={{ marginBottom: 24 }}>
-                <Form.Item
-                    name="email"
-                    rules={[{ required: true, type: 'email', message: 'لطفا یک ایمیل معتبر وارد کنید' }]}
-                    style={{ flex: 1 }}
-                >
-                    <Input placeholder="پست الکترونیکی کاربر را وارد کنید" />
+            <Space style={{ width: '100%', justifyContent: 'space-between', marginBottom: 16 }}>
+                <Title level={4} style={{ margin: 0 }}>نقش‌ها و دسترسی‌ها</Title>
+                <Button type="primary" onClick={() => openEditor(null)}>نقش جدید</Button>
+            </Space>
+
+            <Table columns={columns} dataSource={roles} loading={loading} rowKey="name" bordered />
+
+            <Title level={5} style={{ marginTop: 24 }}>دسترسی‌های اختصاصی کاربر</Title>
+            <Form
+                form={userForm}
+                layout="inline"
+                onFinish={({ userId }) => navigate(`/settings/users/permissions/${encodeURIComponent(userId.trim())}`)}
+            >
+                <Form.Item name="userId" rules={[{ required: true, message: 'شناسه کاربر را وارد کنید' }]} style={{ flex: 1 }}>
+                    <Input placeholder="شناسه کاربر" />
                 </Form.Item>
                 <Form.Item>
-                    <Button type="primary" htmlType="submit">
-                        افزودن
-                    </Button>
+                    <Button htmlType="submit">مشاهده و ویرایش</Button>
                 </Form.Item>
             </Form>
 
-            <Table
-                columns={columns}
-                dataSource={users}
-                loading={loading}
-                rowKey="email"
-                bordered
-            />
+            <Modal
+                title={editingRole ? `ویرایش نقش ${editingRole.name}` : 'نقش جدید'}
+                open={isEditorVisible}
+                onOk={() => form.submit()}
+                onCancel={() => setIsEditorVisible(false)}
+                okText="ذخیره"
+                cancelText="انصراف"
+                width={800}
+            >
+                <Form form={form} layout="vertical" onFinish={handleSave}>
+                    <Form.Item
+                        name="name"
+                        label="نام نقش (حروف کوچک انگلیسی، عدد و _)"
+                        rules={[{ required: true, pattern: /^[a-z][a-z0-9_]{1,31}$/, message: 'نام نقش معتبر نیست' }]}
+                    >
+                        <Input disabled={!!editingRole} />
+                    </Form.Item>
+                    <Form.Item name="description" label="توضیحات">
+                        <Input />
+                    </Form.Item>
+                    <Form.Item name="permissions" label="دسترسی‌ها">
+                        <Checkbox.Group style={{ width: '100%' }}>
+                            {Object.entries(permissionGroups).map(([resource, perms]) => (
+                                <div key={resource} style={{ marginBottom: 12 }}>
+                                    <Text strong>{resource}</Text>
+                                    <Row>
+                                        {perms.map(perm => (
+                                            <Col xs={24} sm={12} key={perm.name}>
+                                                <Checkbox value={perm.name} title={perm.description}>{perm.name}</Checkbox>
+                                            </Col>
+                                        ))}
+                                    </Row>
+                                </div>
+                            ))}
+                        </Checkbox.Group>
+                    </Form.Item>
+                </Form>
+            </Modal>
 
             <Modal
-                title="تایید حذف کاربر"
-                visible={isModalVisible}
+                title="تایید حذف نقش"
+                open={!!roleToDelete}
                 onOk={handleDelete}
-                onCancel={() => setIsModalVisible(false)}
+                onCancel={() => setRoleToDelete(null)}
                 okText="حذف"
                 cancelText="انصراف"
                 okButtonProps={{ danger: true }}
             >
-                <p>آیا از حذف کاربر "{userToDelete?.name}" اطمینان دارید؟</p>
+                <p>آیا از حذف نقش "{roleToDelete?.name}" اطمینان دارید؟</p>
             </Modal>
         </div>
     );
 };
 
-export default UserRolls;
\ No newline at end of file
+export default UserRolls;